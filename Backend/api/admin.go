@@ -0,0 +1,306 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type loginAdminRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type adminResponse struct {
+	Username  string           `json:"username"`
+	FullName  string           `json:"full_name"`
+	Email     string           `json:"email"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type loginAdminResponse struct {
+	AccessToken          string        `json:"access_token"`
+	AccessTokenExpiresAt time.Time     `json:"access_token_expires_at"`
+	Admin                adminResponse `json:"admin"`
+}
+
+type ListUnverifiedSellersRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+type VerifySellerRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type CommissionRateURI struct {
+	SellerType string `uri:"seller_type" binding:"required"`
+}
+
+// UpdateCommissionRateRequest sets the percentage of each delivered
+// sub-order the platform keeps. Sub-orders already delivered keep the rate
+// they were charged.
+type UpdateCommissionRateRequest struct {
+	RatePercent string `json:"rate_percent" binding:"required"`
+}
+
+// ReportChargebackRequest records a chargeback the payment provider reported
+// on a delivered sub-order
+type ReportChargebackRequest struct {
+	Amount string `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (server *Server) LoginAdmin(c *gin.Context) {
+	var req loginAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	admin, err := server.store.GetAdmin(c, req.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("no admin found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := util.CheckPassword(req.Password, admin.Password); err != nil {
+		err := errors.New("invalid password field")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(admin.Username, util.Admin, server.config.TokenDuration)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, loginAdminResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		Admin: adminResponse{
+			Username:  admin.Username,
+			FullName:  admin.FullName,
+			Email:     admin.Email,
+			CreatedAt: admin.CreatedAt,
+		},
+	})
+}
+
+// authorizeAdmin checks that the logged-in user is a platform admin, writing
+// the error response itself when they aren't.
+func authorizeAdmin(c *gin.Context) (*token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Admin {
+		err := errors.New("only admins can do this")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, false
+	}
+	return authPayload, true
+}
+
+// ListUnverifiedSellers lists the NGO and hospital sellers waiting to be
+// verified, oldest first
+func (server *Server) ListUnverifiedSellers(c *gin.Context) {
+	if _, ok := authorizeAdmin(c); !ok {
+		return
+	}
+
+	var req ListUnverifiedSellersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	sellers, err := server.store.ListUnverifiedSellers(c, db.ListUnverifiedSellersParams{
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]sellerResponse, 0, len(sellers))
+	for _, seller := range sellers {
+		rsp = append(rsp, newSellerResponse(seller))
+	}
+	c.JSON(http.StatusOK, rsp)
+}
+
+// VerifySeller marks an NGO or hospital seller as verified, which lets them
+// claim donation offers
+func (server *Server) VerifySeller(c *gin.Context) {
+	authPayload, ok := authorizeAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req VerifySellerRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	seller, err := server.store.GetSellerByName(c, req.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !util.CanClaimDonations(seller.SellerType) {
+		err := fmt.Errorf("only NGO and hospital sellers are verified, %s is a %s seller", seller.Username, seller.SellerType)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	seller, err = server.store.VerifySeller(c, seller.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Seller %s verified by admin %s", seller.Username, authPayload.Username)
+	c.JSON(http.StatusOK, newSellerResponse(seller))
+}
+
+// ListCommissionRates lists the commission charged to each type of seller
+func (server *Server) ListCommissionRates(c *gin.Context) {
+	if _, ok := authorizeAdmin(c); !ok {
+		return
+	}
+
+	rates, err := server.store.ListCommissionRates(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// UpdateCommissionRate changes the commission charged to a type of seller
+// on sub-orders delivered from now on
+func (server *Server) UpdateCommissionRate(c *gin.Context) {
+	authPayload, ok := authorizeAdmin(c)
+	if !ok {
+		return
+	}
+
+	var uri CommissionRateURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !util.IsValidSellerType(uri.SellerType) {
+		err := fmt.Errorf("invalid seller type: %s", uri.SellerType)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req UpdateCommissionRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !util.IsValidCommissionRate(req.RatePercent) {
+		err := fmt.Errorf("invalid commission rate: %s, must be a percentage between 0 and 100", req.RatePercent)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var ratePercent pgtype.Numeric
+	if err := ratePercent.Scan(req.RatePercent); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rate, err := server.store.UpdateCommissionRate(c, db.UpdateCommissionRateParams{
+		SellerType:  uri.SellerType,
+		RatePercent: ratePercent,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := fmt.Errorf("no commission rate for %s sellers", uri.SellerType)
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Commission on %s sellers set to %s%% by admin %s", uri.SellerType, req.RatePercent, authPayload.Username)
+	c.JSON(http.StatusOK, rate)
+}
+
+// ReportChargeback records a chargeback on a delivered sub-order for the
+// next payout run to take back from the seller
+func (server *Server) ReportChargeback(c *gin.Context) {
+	authPayload, ok := authorizeAdmin(c)
+	if !ok {
+		return
+	}
+
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ReportChargebackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !util.IsValidMoneyAmount(req.Amount) {
+		err := fmt.Errorf("invalid chargeback amount: %s", req.Amount)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var amount pgtype.Numeric
+	if err := amount.Scan(req.Amount); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	chargeback, err := server.store.CreateChargebackTx(c, db.CreateChargebackTxParams{
+		SubOrderID: uri.ID,
+		Amount:     amount,
+		Reason:     req.Reason,
+		CreatedBy:  authPayload.Username,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			err := errors.New("order not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrNotRefundable), errors.Is(err, db.ErrRefundExceedsAmount):
+			c.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			util.LogError("Failed to report chargeback on sub-order %d: %v", uri.ID, err)
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	util.LogInfo("Chargeback %d of %s on sub-order %d reported by admin %s", chargeback.ID, req.Amount, uri.ID, authPayload.Username)
+	c.JSON(http.StatusOK, chargeback)
+}
@@ -0,0 +1,366 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+const (
+	analyticsDateLayout     = "2006-01-02"
+	defaultAnalyticsDays    = 30
+	defaultNearExpiryDays   = 90
+	maxAnalyticsRangeInDays = 366
+	analyticsFileDateLayout = "20060102"
+)
+
+// AnalyticsRangeRequest selects the days a report covers, both inclusive.
+// The range defaults to the last 30 days. Reports are returned as JSON
+// unless a spreadsheet format is asked for.
+type AnalyticsRangeRequest struct {
+	From   string `form:"from"`
+	To     string `form:"to"`
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+}
+
+type SalesAnalyticsRequest struct {
+	AnalyticsRangeRequest
+	Interval string `form:"interval,default=day"`
+}
+
+type TopMedicinesRequest struct {
+	AnalyticsRangeRequest
+	Limit int32 `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+type AnalyticsSummaryRequest struct {
+	AnalyticsRangeRequest
+	NearExpiryDays int32 `form:"near_expiry_days" binding:"min=0,max=365"`
+}
+
+type analyticsRange struct {
+	from time.Time
+	to   time.Time
+}
+
+func (r analyticsRange) fromDate() pgtype.Date {
+	return pgtype.Date{Time: r.from, Valid: true}
+}
+
+func (r analyticsRange) toDate() pgtype.Date {
+	return pgtype.Date{Time: r.to, Valid: true}
+}
+
+type analyticsSummaryResponse struct {
+	From              string         `json:"from"`
+	To                string         `json:"to"`
+	Orders            int32          `json:"orders"`
+	UnitsSold         int32          `json:"units_sold"`
+	Revenue           pgtype.Numeric `json:"revenue"`
+	AverageOrderValue pgtype.Numeric `json:"average_order_value"`
+	OpeningStock      int32          `json:"opening_stock"`
+	ClosingStock      int32          `json:"closing_stock"`
+	// StockTurnover is how many times the average stock on hand was sold
+	StockTurnover       float64        `json:"stock_turnover"`
+	ExpiryWriteOffUnits int32          `json:"expiry_write_off_units"`
+	ExpiryWriteOffValue pgtype.Numeric `json:"expiry_write_off_value"`
+	NearExpiryDays      int32          `json:"near_expiry_days"`
+	NearExpiryUnitsSold int32          `json:"near_expiry_units_sold"`
+	// NearExpirySellThrough is the share of near-expiry stock that was sold
+	// rather than written off
+	NearExpirySellThrough float64 `json:"near_expiry_sell_through"`
+}
+
+// bindAnalyticsRange checks that the logged-in user may see their store's
+// reports and works out the date range of the report, writing the error
+// response itself when either fails.
+func (server *Server) bindAnalyticsRange(c *gin.Context, req AnalyticsRangeRequest) (*token.Payload, analyticsRange, bool) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return nil, analyticsRange{}, false
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	r := analyticsRange{
+		from: today.AddDate(0, 0, -(defaultAnalyticsDays - 1)),
+		to:   today,
+	}
+
+	var err error
+	if req.To != "" {
+		r.to, err = time.Parse(analyticsDateLayout, req.To)
+		if err != nil {
+			err := fmt.Errorf("invalid to date, expected YYYY-MM-DD: %s", req.To)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return nil, r, false
+		}
+		if req.From == "" {
+			r.from = r.to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+		}
+	}
+	if req.From != "" {
+		r.from, err = time.Parse(analyticsDateLayout, req.From)
+		if err != nil {
+			err := fmt.Errorf("invalid from date, expected YYYY-MM-DD: %s", req.From)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return nil, r, false
+		}
+	}
+
+	if r.from.After(r.to) {
+		err := errors.New("from date must not be after to date")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return nil, r, false
+	}
+	if r.to.Sub(r.from) >= maxAnalyticsRangeInDays*24*time.Hour {
+		err := fmt.Errorf("date range can't be longer than %d days", maxAnalyticsRangeInDays)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return nil, r, false
+	}
+
+	return authPayload, r, true
+}
+
+func analyticsFileName(username, report string, r analyticsRange, format string) string {
+	return fmt.Sprintf("%s-%s-%s-%s.%s", username, report,
+		r.from.Format(analyticsFileDateLayout), r.to.Format(analyticsFileDateLayout), format)
+}
+
+func numericString(n pgtype.Numeric) string {
+	value, err := n.Value()
+	if err != nil || value == nil {
+		return "0"
+	}
+	return fmt.Sprint(value)
+}
+
+// GetSalesAnalytics returns the seller's orders, units, revenue and average
+// order value per day, week or month
+func (server *Server) GetSalesAnalytics(c *gin.Context) {
+	var req SalesAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, r, ok := server.bindAnalyticsRange(c, req.AnalyticsRangeRequest)
+	if !ok {
+		return
+	}
+
+	if !util.IsValidAnalyticsInterval(req.Interval) {
+		err := errors.New("interval must be day, week or month")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	periods, err := server.store.ListSellerSalesByPeriod(c, db.ListSellerSalesByPeriodParams{
+		Interval: req.Interval,
+		Seller:   authPayload.Store,
+		FromDate: r.fromDate(),
+		ToDate:   r.toDate(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.Format == "" {
+		c.JSON(http.StatusOK, periods)
+		return
+	}
+
+	rows := make([][]string, 0, len(periods)+1)
+	rows = append(rows, []string{"period", "orders", "units", "revenue", "average_order_value"})
+	for _, period := range periods {
+		rows = append(rows, []string{
+			period.Period.Time.Format(analyticsDateLayout),
+			strconv.Itoa(int(period.Orders)),
+			strconv.Itoa(int(period.Units)),
+			numericString(period.Revenue),
+			numericString(period.AverageOrderValue),
+		})
+	}
+
+	fileName := analyticsFileName(authPayload.Store, "sales-by-"+req.Interval, r, req.Format)
+	server.writeSpreadsheet(c, fileName, req.Format, rows)
+}
+
+// GetTopMedicines returns the seller's best selling medicines by revenue
+func (server *Server) GetTopMedicines(c *gin.Context) {
+	var req TopMedicinesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, r, ok := server.bindAnalyticsRange(c, req.AnalyticsRangeRequest)
+	if !ok {
+		return
+	}
+
+	medicines, err := server.store.ListTopSellerMedicines(c, db.ListTopSellerMedicinesParams{
+		Seller:   authPayload.Store,
+		FromDate: r.fromDate(),
+		ToDate:   r.toDate(),
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.Format == "" {
+		c.JSON(http.StatusOK, medicines)
+		return
+	}
+
+	rows := make([][]string, 0, len(medicines)+1)
+	rows = append(rows, []string{"medicine_id", "medicine_name", "units", "revenue"})
+	for _, medicine := range medicines {
+		rows = append(rows, []string{
+			strconv.Itoa(int(medicine.MedicineID)),
+			medicine.MedicineName,
+			strconv.Itoa(int(medicine.Units)),
+			numericString(medicine.Revenue),
+		})
+	}
+
+	fileName := analyticsFileName(authPayload.Store, "top-medicines", r, req.Format)
+	server.writeSpreadsheet(c, fileName, req.Format, rows)
+}
+
+// GetAnalyticsSummary returns the seller's sales and inventory figures over
+// a date range: revenue, average order value, stock turnover, expiry
+// write-offs and how much of the near-expiry stock was sold in time
+func (server *Server) GetAnalyticsSummary(c *gin.Context) {
+	var req AnalyticsSummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, r, ok := server.bindAnalyticsRange(c, req.AnalyticsRangeRequest)
+	if !ok {
+		return
+	}
+
+	if req.NearExpiryDays == 0 {
+		req.NearExpiryDays = defaultNearExpiryDays
+	}
+
+	sales, err := server.store.GetSellerSalesSummary(c, db.GetSellerSalesSummaryParams{
+		Seller:   authPayload.Store,
+		FromDate: r.fromDate(),
+		ToDate:   r.toDate(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	inventory, err := server.store.GetSellerInventorySummary(c, db.GetSellerInventorySummaryParams{
+		Seller:         authPayload.Store,
+		FromDate:       r.fromDate(),
+		ToDate:         r.toDate(),
+		NearExpiryDays: req.NearExpiryDays,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := analyticsSummaryResponse{
+		From:                r.from.Format(analyticsDateLayout),
+		To:                  r.to.Format(analyticsDateLayout),
+		Orders:              sales.Orders,
+		UnitsSold:           sales.Units,
+		Revenue:             sales.Revenue,
+		AverageOrderValue:   sales.AverageOrderValue,
+		OpeningStock:        inventory.OpeningStock,
+		ClosingStock:        inventory.ClosingStock,
+		ExpiryWriteOffUnits: inventory.ExpiryWriteOffUnits,
+		ExpiryWriteOffValue: inventory.ExpiryWriteOffValue,
+		NearExpiryDays:      req.NearExpiryDays,
+		NearExpiryUnitsSold: inventory.NearExpiryUnitsSold,
+		StockTurnover: roundRatio(util.StockTurnover(
+			int(inventory.UnitsSold),
+			int(inventory.OpeningStock),
+			int(inventory.ClosingStock),
+		)),
+		NearExpirySellThrough: roundRatio(util.SellThrough(
+			int(inventory.NearExpiryUnitsSold),
+			int(inventory.ExpiryWriteOffUnits),
+		)),
+	}
+
+	if req.Format == "" {
+		c.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	rows := [][]string{
+		{"metric", "value"},
+		{"from", rsp.From},
+		{"to", rsp.To},
+		{"orders", strconv.Itoa(int(rsp.Orders))},
+		{"units_sold", strconv.Itoa(int(rsp.UnitsSold))},
+		{"revenue", numericString(rsp.Revenue)},
+		{"average_order_value", numericString(rsp.AverageOrderValue)},
+		{"opening_stock", strconv.Itoa(int(rsp.OpeningStock))},
+		{"closing_stock", strconv.Itoa(int(rsp.ClosingStock))},
+		{"stock_turnover", strconv.FormatFloat(rsp.StockTurnover, 'f', -1, 64)},
+		{"expiry_write_off_units", strconv.Itoa(int(rsp.ExpiryWriteOffUnits))},
+		{"expiry_write_off_value", numericString(rsp.ExpiryWriteOffValue)},
+		{"near_expiry_days", strconv.Itoa(int(rsp.NearExpiryDays))},
+		{"near_expiry_units_sold", strconv.Itoa(int(rsp.NearExpiryUnitsSold))},
+		{"near_expiry_sell_through", strconv.FormatFloat(rsp.NearExpirySellThrough, 'f', -1, 64)},
+	}
+
+	fileName := analyticsFileName(authPayload.Store, "summary", r, req.Format)
+	server.writeSpreadsheet(c, fileName, req.Format, rows)
+}
+
+func roundRatio(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// startAnalyticsRefresher keeps the sales rollups behind the analytics
+// endpoints up to date
+func (server *Server) startAnalyticsRefresher(ctx context.Context) {
+	// Run immediately on startup
+	server.refreshAnalytics(ctx)
+
+	ticker := time.NewTicker(server.config.AnalyticsRefreshPeriod)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				server.refreshAnalytics(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (server *Server) refreshAnalytics(ctx context.Context) {
+	if err := server.store.RefreshSellerDailySales(ctx); err != nil {
+		log.Printf("Error refreshing seller daily sales: %v", err)
+	}
+	if err := server.store.RefreshSellerDailyMedicineSales(ctx); err != nil {
+		log.Printf("Error refreshing seller daily medicine sales: %v", err)
+	}
+}
@@ -0,0 +1,737 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/mail"
+	"github.com/pawaspy/MediBridge/payment"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+var (
+	errSlotUnavailable = errors.New("slot is not available")
+	errSlotTaken       = errors.New("slot has just been booked by someone else")
+	errPatientBusy     = errors.New("you already have an appointment at this time")
+)
+
+// BookAppointmentRequest books one of the slots listed for the doctor.
+// starts_at is the slot's start in the doctor's local time.
+type BookAppointmentRequest struct {
+	DoctorUsername string `json:"doctor_username" binding:"required"`
+	StartsAt       string `json:"starts_at" binding:"required"`
+	Reason         string `json:"reason"`
+	VisitType      string `json:"visit_type" binding:"omitempty,oneof=in_person video"`
+}
+
+type AppointmentIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type RescheduleAppointmentRequest struct {
+	StartsAt string `json:"starts_at" binding:"required"`
+}
+
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason"`
+}
+
+type UpdateCancellationWindowRequest struct {
+	Hours int32 `json:"hours" binding:"min=0,max=168"`
+}
+
+type ListPatientAppointmentsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=booked cancelled completed no_show"`
+	Upcoming bool   `form:"upcoming"`
+	Limit    int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset   int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+type DoctorDayRequest struct {
+	Date string `form:"date"`
+}
+
+type doctorDayResponse struct {
+	Date         string                            `json:"date"`
+	Appointments []db.ListDoctorDayAppointmentsRow `json:"appointments"`
+	// FreeSlots are the slots still open for booking
+	FreeSlots []appointmentSlotResponse `json:"free_slots"`
+}
+
+// findSlot looks up the free slot of the doctor that starts at startsAt,
+// writing the error response itself when there is none.
+func (server *Server) findSlot(c *gin.Context, doctorUsername, startsAt string, rescheduling int32) (util.TimeRange, bool) {
+	start, err := time.Parse(appointmentTimeLayout, startsAt)
+	if err != nil {
+		err := fmt.Errorf("invalid starts_at, expected YYYY-MM-DDTHH:MM: %s", startsAt)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return util.TimeRange{}, false
+	}
+
+	date := start.Truncate(24 * time.Hour)
+	if date.After(today().AddDate(0, 0, maxAppointmentDaysAhead)) {
+		err := fmt.Errorf("appointments can be booked at most %d days ahead", maxAppointmentDaysAhead)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return util.TimeRange{}, false
+	}
+
+	slots, err := server.getDoctorSlots(c, doctorUsername, date, date, rescheduling)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return util.TimeRange{}, false
+	}
+
+	for _, slot := range slots {
+		if slot.Start.Equal(start) {
+			return slot, true
+		}
+	}
+
+	c.JSON(http.StatusConflict, errorResponse(errSlotUnavailable))
+	return util.TimeRange{}, false
+}
+
+// appointmentConflict turns a violation of the database constraints that
+// keep appointments from overlapping into the error to show. Other errors
+// give nil.
+func appointmentConflict(err error) error {
+	if db.ErrorCode(err) != db.ExclusionViolation {
+		return nil
+	}
+	if db.ConstraintName(err) == "appointments_patient_no_overlap" {
+		return errPatientBusy
+	}
+	return errSlotTaken
+}
+
+// checkCancellationWindow stops a patient from cancelling or moving an
+// appointment inside the doctor's cancellation window, writing the error
+// response itself. Doctors aren't held to it.
+func (server *Server) checkCancellationWindow(c *gin.Context, authPayload *token.Payload, appointment db.Appointment) bool {
+	if authPayload.Role != util.Patient {
+		return true
+	}
+
+	doctor, err := server.store.GetDoctorByName(c, appointment.DoctorUsername)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if util.IsWithinCancellationWindow(appointment.StartsAt.Time, wallClockNow(), int(doctor.CancellationWindowHours)) {
+		err := fmt.Errorf("appointments with Dr. %s can't be changed within %d hours of the start", doctor.FullName, doctor.CancellationWindowHours)
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return false
+	}
+
+	return true
+}
+
+// getAppointmentParty loads an appointment the logged-in patient or doctor
+// is part of, writing the error response itself when they can't see it.
+func (server *Server) getAppointmentParty(c *gin.Context, id int32) (db.Appointment, *token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	appointment, err := server.store.GetAppointment(c, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("appointment not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return appointment, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return appointment, nil, false
+	}
+
+	switch {
+	case authPayload.Role == util.Patient && appointment.PatientUsername == authPayload.Username,
+		authPayload.Role == util.Doctor && appointment.DoctorUsername == authPayload.Username:
+		return appointment, authPayload, true
+	default:
+		err := errors.New("appointment doesn't belong to the authenticated user")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return appointment, nil, false
+	}
+}
+
+// sendAppointmentEmails tells the patient and the doctor what happened to
+// an appointment. Failures are logged; the change itself has been made.
+func (server *Server) sendAppointmentEmails(c *gin.Context, appointment db.Appointment, action string, previousStart pgtype.Timestamp) {
+	doctor, err := server.store.GetDoctorByName(c, appointment.DoctorUsername)
+	if err != nil {
+		util.LogError("Failed to get doctor %s for appointment %d: %v", appointment.DoctorUsername, appointment.ID, err)
+		return
+	}
+	patient, err := server.store.GetPatientByName(c, appointment.PatientUsername)
+	if err != nil {
+		util.LogError("Failed to get patient %s for appointment %d: %v", appointment.PatientUsername, appointment.ID, err)
+		return
+	}
+
+	data := mail.NewAppointmentData(appointment, doctor, patient, action)
+	if previousStart.Valid {
+		data.PreviousTime = previousStart.Time.Format(mail.AppointmentTimeLayout)
+	}
+
+	if _, err := server.mailer.SendAppointmentEmails(doctor, patient, data); err != nil {
+		util.LogError("Failed to send appointment %d emails: %v", appointment.ID, err)
+	}
+}
+
+// BookAppointment books a free slot with a doctor for the logged-in
+// patient. The database refuses a second booking of the same time, so two
+// patients racing for a slot can't both get it. Appointments with a fee
+// hold the slot until AppointmentPaymentHold has passed and are confirmed
+// once paid for, see CreateAppointmentPayment.
+func (server *Server) BookAppointment(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can book appointments")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req BookAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetDoctorByName(c, req.DoctorUsername); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("doctor not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slot, ok := server.findSlot(c, req.DoctorUsername, req.StartsAt, 0)
+	if !ok {
+		return
+	}
+
+	visitType := req.VisitType
+	if visitType == "" {
+		visitType = util.InPersonVisit
+	}
+
+	now := wallClockNow()
+	appointment, err := server.store.BookAppointmentTx(c, db.BookAppointmentTxParams{
+		DoctorUsername:  req.DoctorUsername,
+		PatientUsername: authPayload.Username,
+		StartsAt:        pgTimestamp(slot.Start),
+		EndsAt:          pgTimestamp(slot.End),
+		Reason:          req.Reason,
+		VisitType:       visitType,
+		Now:             pgTimestamp(now),
+		PaymentDueBy:    pgTimestamp(now.Add(server.config.AppointmentPaymentHold)),
+	})
+	if err != nil {
+		if conflict := appointmentConflict(err); conflict != nil {
+			c.JSON(http.StatusConflict, errorResponse(conflict))
+			return
+		}
+		util.LogError("Failed to book appointment with doctor %s for %s: %v", req.DoctorUsername, authPayload.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Appointment %d booked with doctor %s by %s", appointment.ID, appointment.DoctorUsername, authPayload.Username)
+	if appointment.PaymentStatus == util.PaymentNotRequired {
+		server.sendAppointmentEmails(c, appointment, "booked", pgtype.Timestamp{})
+	}
+	c.JSON(http.StatusOK, appointment)
+}
+
+// getPayableAppointment loads an appointment of the logged-in patient
+// that is waiting for payment, writing the error response itself when it
+// can't.
+func (server *Server) getPayableAppointment(c *gin.Context, id int32) (db.Appointment, *token.Payload, bool) {
+	appointment, authPayload, ok := server.getAppointmentParty(c, id)
+	if !ok {
+		return appointment, nil, false
+	}
+
+	if authPayload.Role != util.Patient {
+		err := errors.New("only the patient can pay for an appointment")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return appointment, nil, false
+	}
+
+	if appointment.PaymentStatus != util.PaymentPending {
+		err := fmt.Errorf("appointment payment is %s, not pending", appointment.PaymentStatus)
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return appointment, nil, false
+	}
+
+	return appointment, authPayload, true
+}
+
+// CreateAppointmentPayment starts the payment of an appointment's fee with
+// the provider. The payment is recorded against the appointment, not an
+// order, and the front end completes it with the client secret.
+func (server *Server) CreateAppointmentPayment(c *gin.Context) {
+	var uri AppointmentIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, authPayload, ok := server.getPayableAppointment(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	if appointment.PaymentDueBy.Valid && appointment.PaymentDueBy.Time.Before(wallClockNow()) {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrAppointmentNotPayable))
+		return
+	}
+
+	amount, err := minorUnits(appointment.Fee)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	intent, err := server.paymentProvider.CreateIntent(c, payment.CreateIntentParams{
+		Amount:   amount,
+		Currency: server.config.PaymentCurrency,
+		Metadata: map[string]string{"appointment_id": strconv.Itoa(int(appointment.ID))},
+	})
+	if err != nil {
+		util.LogError("Failed to create payment of appointment %d: %v", appointment.ID, err)
+		paymentProviderError(c, err)
+		return
+	}
+
+	paid, err := server.store.CreateAppointmentPayment(c, db.CreateAppointmentPaymentParams{
+		AppointmentID:   pgtype.Int4{Int32: appointment.ID, Valid: true},
+		UserID:          authPayload.Username,
+		Amount:          appointment.Fee,
+		Currency:        strings.ToUpper(server.config.PaymentCurrency),
+		PaymentIntentID: pgtype.Text{String: intent.ID, Valid: true},
+	})
+	if err != nil {
+		util.LogError("Failed to record payment %s of appointment %d: %v", intent.ID, appointment.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, paymentIntentResponse{Payment: paid, ClientSecret: intent.ClientSecret})
+}
+
+// ConfirmAppointmentPayment looks the patient's payment up with the
+// provider and confirms the appointment once the provider reports it
+// succeeded. The provider's webhook does the same, so this only saves
+// waiting for it.
+func (server *Server) ConfirmAppointmentPayment(c *gin.Context) {
+	var uri AppointmentIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ConfirmPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, _, ok := server.getAppointmentParty(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	intent, ok := server.intentFor(c, req.PaymentIntentID, "appointment_id", appointment.ID)
+	if !ok {
+		return
+	}
+
+	result, err := server.settleAppointmentPayment(c, intent)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			err := errors.New("payment not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, errPaymentMismatch):
+			c.JSON(http.StatusPaymentRequired, errorResponse(err))
+		case errors.Is(err, db.ErrAppointmentNotPayable):
+			err := fmt.Errorf("%w, the payment has been refunded", err)
+			c.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			util.LogError("Failed to confirm payment %s of appointment %d: %v", intent.ID, appointment.ID, err)
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// refundAppointmentPayment pays the fee of an appointment cancelled with a
+// refund back through the provider. Refunds that fail are retried by the
+// order expiry job.
+func (server *Server) refundAppointmentPayment(ctx context.Context, paid db.Payment, reason, createdBy string) (db.Payment, error) {
+	key := fmt.Sprintf("payment-%d-appointment-refund", paid.ID)
+	_, err := server.refundPayment(ctx, paid, 0, key, db.CreatePaymentRefundParams{
+		Reason:    reason,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return paid, err
+	}
+
+	return server.store.MarkPaymentRefunded(ctx, db.MarkPaymentRefundedParams{
+		ID:           paid.ID,
+		RefundReason: pgtype.Text{String: reason, Valid: true},
+	})
+}
+
+// ListAppointmentPayments lists the payments and refunds of an appointment
+// to its patient or doctor
+func (server *Server) ListAppointmentPayments(c *gin.Context) {
+	var req AppointmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, _, ok := server.getAppointmentParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	payments, err := server.store.ListAppointmentPayments(c, pgtype.Int4{Int32: appointment.ID, Valid: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// ListPatientAppointments lists the logged-in patient's appointments,
+// upcoming ones soonest first or the whole history newest first
+func (server *Server) ListPatientAppointments(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients have appointments to list")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req ListPatientAppointmentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointments, err := server.store.ListPatientAppointments(c, db.ListPatientAppointmentsParams{
+		PatientUsername: authPayload.Username,
+		Status:          pgtype.Text{String: req.Status, Valid: req.Status != ""},
+		Upcoming:        req.Upcoming,
+		Limit:           req.Limit,
+		Offset:          req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, appointments)
+}
+
+func (server *Server) GetAppointment(c *gin.Context) {
+	var req AppointmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, _, ok := server.getAppointmentParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, appointment)
+}
+
+// RescheduleAppointment moves the logged-in patient's upcoming appointment
+// to another free slot with the same doctor
+func (server *Server) RescheduleAppointment(c *gin.Context) {
+	var uri AppointmentIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req RescheduleAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, authPayload, ok := server.getAppointmentParty(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	if authPayload.Role != util.Patient {
+		err := errors.New("only the patient can reschedule an appointment")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if appointment.Status != util.BookedAppointment || !appointment.StartsAt.Time.After(wallClockNow()) {
+		err := errors.New("only upcoming booked appointments can be rescheduled")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	if !server.checkCancellationWindow(c, authPayload, appointment) {
+		return
+	}
+
+	slot, ok := server.findSlot(c, appointment.DoctorUsername, req.StartsAt, appointment.ID)
+	if !ok {
+		return
+	}
+
+	previousStart := appointment.StartsAt
+	appointment, err := server.store.RescheduleAppointment(c, db.RescheduleAppointmentParams{
+		ID:       appointment.ID,
+		StartsAt: pgTimestamp(slot.Start),
+		EndsAt:   pgTimestamp(slot.End),
+	})
+	if err != nil {
+		if conflict := appointmentConflict(err); conflict != nil {
+			c.JSON(http.StatusConflict, errorResponse(conflict))
+			return
+		}
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("appointment was cancelled")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to reschedule appointment %d: %v", uri.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Reminders already sent were for the old time
+	if err := server.store.DeleteAppointmentReminders(c, appointment.ID); err != nil {
+		util.LogError("Failed to reset reminders of appointment %d: %v", appointment.ID, err)
+	}
+
+	util.LogInfo("Appointment %d rescheduled by %s", appointment.ID, authPayload.Username)
+	server.sendAppointmentEmails(c, appointment, "rescheduled", previousStart)
+	c.JSON(http.StatusOK, appointment)
+}
+
+// CancelAppointment cancels an upcoming appointment on behalf of either the
+// patient or the doctor, freeing the slot. A paid appointment is refunded
+// in full when the doctor cancels, or when the patient does so at least
+// the doctor's refund window ahead of the start.
+func (server *Server) CancelAppointment(c *gin.Context) {
+	var uri AppointmentIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req CancelAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, authPayload, ok := server.getAppointmentParty(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	if appointment.Status != util.BookedAppointment || !appointment.StartsAt.Time.After(wallClockNow()) {
+		err := errors.New("only upcoming booked appointments can be cancelled")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	if !server.checkCancellationWindow(c, authPayload, appointment) {
+		return
+	}
+
+	doctor, err := server.store.GetDoctorByName(c, appointment.DoctorUsername)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	refund := util.IsRefundable(appointment.StartsAt.Time, wallClockNow(), int(doctor.RefundWindowHours), authPayload.Role == util.Doctor)
+	appointment, err = server.store.CancelAppointmentTx(c, db.CancelAppointmentTxParams{
+		Cancel: db.CancelAppointmentParams{
+			ID:                 appointment.ID,
+			CancelledBy:        pgtype.Text{String: authPayload.Username, Valid: true},
+			CancellationReason: pgtype.Text{String: req.Reason, Valid: req.Reason != ""},
+		},
+		Refund: refund,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("appointment is already cancelled")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to cancel appointment %d: %v", uri.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Appointment %d cancelled by %s, payment %s", appointment.ID, authPayload.Username, appointment.PaymentStatus)
+
+	if appointment.PaymentStatus == util.PaymentRefunded && appointment.PaidPaymentID.Valid {
+		paid, err := server.store.GetPayment(c, appointment.PaidPaymentID.Int32)
+		if err == nil {
+			_, err = server.refundAppointmentPayment(c, paid, fmt.Sprintf("appointment cancelled by %s", authPayload.Username), authPayload.Username)
+		}
+		if err != nil {
+			util.LogError("Failed to refund appointment %d, will retry: %v", appointment.ID, err)
+		}
+	}
+	server.sendAppointmentEmails(c, appointment, "cancelled", pgtype.Timestamp{})
+	c.JSON(http.StatusOK, appointment)
+}
+
+// CheckInAppointment records that the logged-in patient or doctor has
+// arrived. Check-in opens shortly before the appointment and closes when it
+// ends; appointments nobody checked in to are closed as no-shows.
+func (server *Server) CheckInAppointment(c *gin.Context) {
+	var req AppointmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, authPayload, ok := server.getAppointmentParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	if appointment.PaymentStatus == util.PaymentPending {
+		err := errors.New("appointment hasn't been paid for")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	if appointment.Status != util.BookedAppointment ||
+		!util.CanCheckIn(appointment.StartsAt.Time, appointment.EndsAt.Time, wallClockNow()) {
+		err := fmt.Errorf("check-in is open from %d minutes before the appointment until it ends",
+			int(util.CheckInOpensBefore.Minutes()))
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	appointment, err := server.store.CheckInAppointment(c, db.CheckInAppointmentParams{
+		ID:       appointment.ID,
+		ByDoctor: authPayload.Role == util.Doctor,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("appointment is no longer booked")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Appointment %d checked in by %s", appointment.ID, authPayload.Username)
+	c.JSON(http.StatusOK, appointment)
+}
+
+// UpdateCancellationWindow sets how many hours before an appointment the
+// logged-in doctor's patients can no longer cancel or reschedule it
+func (server *Server) UpdateCancellationWindow(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCancellationWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	doctor, err := server.store.SetDoctorCancellationWindow(c, db.SetDoctorCancellationWindowParams{
+		Username:                authPayload.Username,
+		CancellationWindowHours: req.Hours,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Cancellation window of doctor %s set to %d hours", doctor.Username, doctor.CancellationWindowHours)
+	c.JSON(http.StatusOK, newDoctorResponse(doctor))
+}
+
+// GetDoctorDay is the logged-in doctor's view of one day, today unless a
+// date is given: every appointment with its patient, and the slots that
+// are still free.
+func (server *Server) GetDoctorDay(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var req DoctorDayRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	date := today()
+	if req.Date != "" {
+		var err error
+		date, err = parseAppointmentDate("date", req.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	appointments, err := server.store.ListDoctorDayAppointments(c, db.ListDoctorDayAppointmentsParams{
+		DoctorUsername: authPayload.Username,
+		FromTime:       pgTimestamp(date),
+		ToTime:         pgTimestamp(date.AddDate(0, 0, 1)),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slots, err := server.getDoctorSlots(c, authPayload.Username, date, date, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := doctorDayResponse{
+		Date:         date.Format(appointmentDateLayout),
+		Appointments: appointments,
+		FreeSlots:    newSlotsResponse(slots),
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,463 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// newConsultationUpgrader accepts chat handshakes from the front end's
+// origins, and from clients such as mobile apps that send no origin. The
+// access_token subprotocol browsers authenticate with is echoed back, as
+// they drop connections that don't agree to a protocol they offered.
+func newConsultationUpgrader(origins []string) websocket.Upgrader {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[strings.TrimRight(strings.TrimSpace(origin), "/")] = true
+	}
+
+	return websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		Subprotocols:    []string{websocketTokenProtocol},
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || allowed[origin]
+		},
+	}
+}
+
+type ConsultationIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ConsultationAttachmentIDRequest struct {
+	ID           int32 `uri:"id" binding:"required,min=1"`
+	AttachmentID int32 `uri:"attachment_id" binding:"required,min=1"`
+}
+
+type ListConsultationMessagesRequest struct {
+	BeforeID int32 `form:"before_id" binding:"min=0"`
+	Limit    int32 `form:"limit,default=50" binding:"min=1,max=200"`
+}
+
+type SendConsultationAttachmentRequest struct {
+	File    *multipart.FileHeader `form:"file" binding:"required"`
+	Caption string                `form:"caption"`
+}
+
+// getConsultationParty loads a consultation the logged-in patient or doctor
+// is part of, writing the error response itself when they can't see it.
+func (server *Server) getConsultationParty(c *gin.Context, id int32) (db.Consultation, *token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	consultation, err := server.store.GetConsultation(c, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("consultation not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return consultation, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return consultation, nil, false
+	}
+
+	switch {
+	case authPayload.Role == util.Patient && consultation.PatientUsername == authPayload.Username,
+		authPayload.Role == util.Doctor && consultation.DoctorUsername == authPayload.Username:
+		return consultation, authPayload, true
+	default:
+		err := errors.New("consultation doesn't belong to the authenticated user")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return consultation, nil, false
+	}
+}
+
+// getOpenConsultationParty is getConsultationParty for actions that need the
+// consultation to still be open
+func (server *Server) getOpenConsultationParty(c *gin.Context, id int32) (db.Consultation, *token.Payload, bool) {
+	consultation, authPayload, ok := server.getConsultationParty(c, id)
+	if !ok {
+		return consultation, nil, false
+	}
+
+	if consultation.Status != util.OpenConsultation {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrConsultationClosed))
+		return consultation, nil, false
+	}
+
+	return consultation, authPayload, true
+}
+
+// StartConsultation opens the chat for an appointment, or returns it if it
+// is already open. Like check-in, it can be started from shortly before the
+// appointment until it ends; once started it stays open until closed.
+func (server *Server) StartConsultation(c *gin.Context) {
+	var req AppointmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, authPayload, ok := server.getAppointmentParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	if appointment.PaymentStatus == util.PaymentPending {
+		err := errors.New("appointment hasn't been paid for")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	if appointment.Status != util.BookedAppointment ||
+		!util.CanCheckIn(appointment.StartsAt.Time, appointment.EndsAt.Time, wallClockNow()) {
+		err := fmt.Errorf("a consultation can be started from %d minutes before the appointment until it ends",
+			int(util.CheckInOpensBefore.Minutes()))
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	consultation, err := server.store.CreateConsultation(c, db.CreateConsultationParams{
+		AppointmentID:   appointment.ID,
+		DoctorUsername:  appointment.DoctorUsername,
+		PatientUsername: appointment.PatientUsername,
+	})
+	if err != nil {
+		util.LogError("Failed to start consultation for appointment %d: %v", appointment.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if consultation.Status != util.OpenConsultation {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrConsultationClosed))
+		return
+	}
+
+	util.LogInfo("Consultation %d for appointment %d started by %s", consultation.ID, appointment.ID, authPayload.Username)
+	c.JSON(http.StatusOK, newConsultationResponse(consultation))
+}
+
+// GetAppointmentConsultation finds the consultation of an appointment, so
+// its transcript can be found once it is closed
+func (server *Server) GetAppointmentConsultation(c *gin.Context) {
+	var req AppointmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, _, ok := server.getAppointmentParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	consultation, err := server.store.GetConsultationByAppointment(c, appointment.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("no consultation was started for this appointment")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newConsultationResponse(consultation))
+}
+
+// GetConsultation returns a consultation, with its transcript once closed
+func (server *Server) GetConsultation(c *gin.Context) {
+	var req ConsultationIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	consultation, _, ok := server.getConsultationParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, newConsultationResponse(consultation))
+}
+
+// ListConsultationMessages pages back through the messages of an open
+// consultation, newest first. A closed consultation's messages are in its
+// transcript.
+func (server *Server) ListConsultationMessages(c *gin.Context) {
+	var uri ConsultationIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ListConsultationMessagesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	consultation, _, ok := server.getOpenConsultationParty(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.ListConsultationMessages(c, db.ListConsultationMessagesParams{
+		ConsultationID: consultation.ID,
+		BeforeID:       pgtype.Int4{Int32: req.BeforeID, Valid: req.BeforeID > 0},
+		LimitCount:     req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	messages := make([]consultationMessageResponse, 0, len(rows))
+	for _, row := range rows {
+		var attachment *consultationAttachmentResponse
+		if row.AttachmentID.Valid {
+			attachment = &consultationAttachmentResponse{
+				ID:          row.AttachmentID.Int32,
+				FileName:    row.AttachmentFileName.String,
+				ContentType: row.AttachmentContentType.String,
+				SizeBytes:   row.AttachmentSizeBytes.Int32,
+			}
+		}
+		messages = append(messages, consultationMessageResponse{
+			ID:             row.ID,
+			SenderUsername: row.SenderUsername,
+			Body:           row.Body,
+			Attachment:     attachment,
+			CreatedAt:      row.CreatedAt,
+			ReadAt:         row.ReadAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+// SendConsultationAttachment shares a file such as a lab report in an open
+// consultation. It is sent as a message, with the caption as its body, to
+// everyone connected.
+func (server *Server) SendConsultationAttachment(c *gin.Context) {
+	var uri ConsultationIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req SendConsultationAttachmentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	caption := strings.TrimSpace(req.Caption)
+	if utf8.RuneCountInString(caption) > util.MaxConsultationMessageLength {
+		err := errors.New("caption is too long")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.File.Size > util.MaxConsultationAttachmentSize {
+		err := fmt.Errorf("file is larger than %d MB", util.MaxConsultationAttachmentSize>>20)
+		c.JSON(http.StatusRequestEntityTooLarge, errorResponse(err))
+		return
+	}
+
+	consultation, authPayload, ok := server.getOpenConsultationParty(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(data) == 0 {
+		err := errors.New("file is empty")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	contentType, ok := util.ConsultationAttachmentType(data)
+	if !ok {
+		err := errors.New("file must be a PDF, JPEG or PNG")
+		c.JSON(http.StatusUnsupportedMediaType, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.SendConsultationAttachmentTx(c, db.SendConsultationAttachmentTxParams{
+		Attachment: db.CreateConsultationAttachmentParams{
+			ConsultationID: consultation.ID,
+			UploadedBy:     authPayload.Username,
+			FileName:       filepath.Base(req.File.Filename),
+			ContentType:    contentType,
+			SizeBytes:      int32(len(data)),
+			Data:           data,
+		},
+		Caption: caption,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrConsultationClosed) {
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to save attachment to consultation %d from %s: %v", consultation.ID, authPayload.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := newConsultationMessageResponse(result.Message, &consultationAttachmentResponse{
+		ID:          result.Attachment.ID,
+		FileName:    result.Attachment.FileName,
+		ContentType: result.Attachment.ContentType,
+		SizeBytes:   result.Attachment.SizeBytes,
+	})
+	server.consultationHub.broadcast(consultation.ID, consultationEvent{
+		Type:    consultationMessageEvent,
+		Message: &rsp,
+	}, nil)
+
+	util.LogInfo("Attachment %d shared in consultation %d by %s", result.Attachment.ID, consultation.ID, authPayload.Username)
+	c.JSON(http.StatusOK, rsp)
+}
+
+// DownloadConsultationAttachment downloads a file shared in a consultation.
+// Files stay available after the consultation is closed.
+func (server *Server) DownloadConsultationAttachment(c *gin.Context) {
+	var req ConsultationAttachmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	consultation, _, ok := server.getConsultationParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	attachment, err := server.store.GetConsultationAttachment(c, db.GetConsultationAttachmentParams{
+		ID:             req.AttachmentID,
+		ConsultationID: consultation.ID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("attachment not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, attachment.ContentType, attachment.Data)
+}
+
+// CloseConsultation ends a consultation on behalf of either side. Its
+// messages are archived into the transcript and everyone connected is told
+// and disconnected.
+func (server *Server) CloseConsultation(c *gin.Context) {
+	var req ConsultationIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	consultation, authPayload, ok := server.getOpenConsultationParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	consultation, err := server.store.CloseConsultationTx(c, db.CloseConsultationTxParams{
+		ID:       consultation.ID,
+		ClosedBy: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrConsultationClosed) {
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to close consultation %d: %v", req.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := newConsultationResponse(consultation)
+	server.consultationHub.closeRoom(consultation.ID, consultationEvent{
+		Type:         consultationClosedEvent,
+		Username:     authPayload.Username,
+		Consultation: &rsp,
+	})
+
+	util.LogInfo("Consultation %d closed by %s", consultation.ID, authPayload.Username)
+	c.JSON(http.StatusOK, rsp)
+}
+
+// ConsultationChat upgrades the request to a WebSocket carrying the chat of
+// an open consultation. Browsers pass the token as a subprotocol, see
+// websocketAuthMiddleware.
+func (server *Server) ConsultationChat(c *gin.Context) {
+	var req ConsultationIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	consultation, authPayload, ok := server.getOpenConsultationParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	conn, err := server.consultationUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written the error response
+		util.LogWarning("Failed to open consultation %d chat for %s: %v", consultation.ID, authPayload.Username, err)
+		return
+	}
+
+	client := &consultationClient{
+		consultationID: consultation.ID,
+		authPayload:    authPayload,
+		conn:           conn,
+		send:           make(chan consultationEvent, consultationSendBuffer),
+	}
+	if !server.consultationHub.join(client) {
+		// The consultation was closed after it was checked above
+		conn.SetWriteDeadline(time.Now().Add(consultationWriteWait))
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, db.ErrConsultationClosed.Error()))
+		conn.Close()
+		return
+	}
+	server.consultationHub.broadcast(consultation.ID, consultationEvent{
+		Type:     consultationJoinedEvent,
+		Username: authPayload.Username,
+	}, nil)
+
+	go writeConsultationEvents(client)
+	server.readConsultationEvents(c.Request.Context(), client)
+}
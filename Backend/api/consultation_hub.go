@@ -0,0 +1,394 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+const (
+	// Time allowed to write an event to the peer
+	consultationWriteWait = 10 * time.Second
+	// A connection that hasn't answered a ping for this long is dropped
+	consultationPongWait   = 60 * time.Second
+	consultationPingPeriod = consultationPongWait * 9 / 10
+	// Largest event a client can send; a message of
+	// MaxConsultationMessageLength characters fits comfortably
+	maxConsultationEventSize = 32 << 10
+	// Events queued for a client that reads too slowly; it is dropped when
+	// the queue is full
+	consultationSendBuffer = 64
+)
+
+// Events sent over a consultation's WebSocket. Clients send message, typing
+// and read; the server sends all of them plus joined, left, closed and
+// error.
+const (
+	consultationMessageEvent = "message"
+	consultationTypingEvent  = "typing"
+	consultationReadEvent    = "read"
+	consultationJoinedEvent  = "joined"
+	consultationLeftEvent    = "left"
+	consultationClosedEvent  = "closed"
+	consultationErrorEvent   = "error"
+)
+
+// consultationClientEvent is an event sent by a client: a message with a
+// body, typing started or stopped, or the id of the newest message read
+type consultationClientEvent struct {
+	Type      string `json:"type"`
+	Body      string `json:"body"`
+	Typing    bool   `json:"typing"`
+	MessageID int32  `json:"message_id"`
+}
+
+type consultationEvent struct {
+	Type         string                       `json:"type"`
+	Username     string                       `json:"username,omitempty"`
+	Message      *consultationMessageResponse `json:"message,omitempty"`
+	Typing       *bool                        `json:"typing,omitempty"`
+	MessageID    int32                        `json:"message_id,omitempty"`
+	Consultation *consultationResponse        `json:"consultation,omitempty"`
+	Error        string                       `json:"error,omitempty"`
+}
+
+// consultationClient is one WebSocket connection to a consultation
+type consultationClient struct {
+	consultationID int32
+	authPayload    *token.Payload
+	conn           *websocket.Conn
+	send           chan consultationEvent
+}
+
+// consultationHub relays events between the connections to each open
+// consultation. It only knows the connections to this server, so both sides
+// of a consultation have to reach the same instance.
+type consultationHub struct {
+	mu    sync.Mutex
+	rooms map[int32]map[*consultationClient]bool
+	// closed has the consultations closed through this server, so that a
+	// connection that was let in just before one closed can't join it after
+	closed map[int32]bool
+}
+
+func newConsultationHub() *consultationHub {
+	return &consultationHub{
+		rooms:  make(map[int32]map[*consultationClient]bool),
+		closed: make(map[int32]bool),
+	}
+}
+
+// join adds a client to its consultation's room. It returns false, leaving
+// the client out, when the consultation has been closed in the meantime.
+func (hub *consultationHub) join(client *consultationClient) bool {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if hub.closed[client.consultationID] {
+		return false
+	}
+
+	room := hub.rooms[client.consultationID]
+	if room == nil {
+		room = make(map[*consultationClient]bool)
+		hub.rooms[client.consultationID] = room
+	}
+	room[client] = true
+	return true
+}
+
+// leave removes a client, closing its send queue so its writer stops.
+// Leaving twice is harmless.
+func (hub *consultationHub) leave(client *consultationClient) bool {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	return hub.remove(client)
+}
+
+func (hub *consultationHub) remove(client *consultationClient) bool {
+	room := hub.rooms[client.consultationID]
+	if !room[client] {
+		return false
+	}
+
+	delete(room, client)
+	if len(room) == 0 {
+		delete(hub.rooms, client.consultationID)
+	}
+	close(client.send)
+	return true
+}
+
+// broadcast queues an event for every connection to a consultation except
+// skip, which may be nil. Clients too far behind are dropped rather than
+// holding up the others.
+func (hub *consultationHub) broadcast(consultationID int32, event consultationEvent, skip *consultationClient) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for client := range hub.rooms[consultationID] {
+		if client == skip {
+			continue
+		}
+		select {
+		case client.send <- event:
+		default:
+			util.LogWarning("Dropping slow consultation %d connection of %s", consultationID, client.authPayload.Username)
+			hub.remove(client)
+		}
+	}
+}
+
+// sendTo queues an event for one client, unless it has left or is too far
+// behind to take it
+func (hub *consultationHub) sendTo(client *consultationClient, event consultationEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if !hub.rooms[client.consultationID][client] {
+		return
+	}
+	select {
+	case client.send <- event:
+	default:
+	}
+}
+
+// closeRoom tells every connection to a consultation that it has been
+// closed and then disconnects them. Connections can't join it afterwards.
+func (hub *consultationHub) closeRoom(consultationID int32, event consultationEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.closed[consultationID] = true
+
+	for client := range hub.rooms[consultationID] {
+		select {
+		case client.send <- event:
+		default:
+		}
+		hub.remove(client)
+	}
+}
+
+// readConsultationEvents handles the events sent by the client until the connection
+// fails or the client disconnects
+func (server *Server) readConsultationEvents(ctx context.Context, client *consultationClient) {
+	defer func() {
+		if server.consultationHub.leave(client) {
+			server.consultationHub.broadcast(client.consultationID, consultationEvent{
+				Type:     consultationLeftEvent,
+				Username: client.authPayload.Username,
+			}, nil)
+		}
+		client.conn.Close()
+	}()
+
+	client.conn.SetReadLimit(maxConsultationEventSize)
+	client.conn.SetReadDeadline(time.Now().Add(consultationPongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(consultationPongWait))
+	})
+
+	for {
+		var event consultationClientEvent
+		if err := client.conn.ReadJSON(&event); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				server.sendConsultationError(client, errors.New("events must be JSON objects"))
+				continue
+			}
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				util.LogWarning("Consultation %d connection of %s failed: %v", client.consultationID, client.authPayload.Username, err)
+			}
+			return
+		}
+
+		switch event.Type {
+		case consultationMessageEvent:
+			server.handleConsultationMessage(ctx, client, event)
+		case consultationTypingEvent:
+			typing := event.Typing
+			server.consultationHub.broadcast(client.consultationID, consultationEvent{
+				Type:     consultationTypingEvent,
+				Username: client.authPayload.Username,
+				Typing:   &typing,
+			}, client)
+		case consultationReadEvent:
+			server.handleConsultationRead(ctx, client, event)
+		default:
+			server.sendConsultationError(client, errors.New("unknown event type: "+event.Type))
+		}
+	}
+}
+
+// handleConsultationMessage saves a message and passes it on to everyone
+// in the consultation, the sender included, which tells the sender it was
+// saved
+func (server *Server) handleConsultationMessage(ctx context.Context, client *consultationClient, event consultationClientEvent) {
+	body := strings.TrimSpace(event.Body)
+	if body == "" {
+		server.sendConsultationError(client, errors.New("message is empty"))
+		return
+	}
+	if utf8.RuneCountInString(body) > util.MaxConsultationMessageLength {
+		server.sendConsultationError(client, errors.New("message is too long"))
+		return
+	}
+
+	message, err := server.store.CreateConsultationMessage(ctx, db.CreateConsultationMessageParams{
+		ConsultationID: client.consultationID,
+		SenderUsername: client.authPayload.Username,
+		Body:           body,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			server.sendConsultationError(client, db.ErrConsultationClosed)
+			return
+		}
+		util.LogError("Failed to save consultation %d message from %s: %v", client.consultationID, client.authPayload.Username, err)
+		server.sendConsultationError(client, errors.New("message could not be sent"))
+		return
+	}
+
+	rsp := newConsultationMessageResponse(message, nil)
+	server.consultationHub.broadcast(client.consultationID, consultationEvent{
+		Type:    consultationMessageEvent,
+		Message: &rsp,
+	}, nil)
+}
+
+// handleConsultationRead marks the other side's messages as read up to the
+// given one and lets them know
+func (server *Server) handleConsultationRead(ctx context.Context, client *consultationClient, event consultationClientEvent) {
+	if event.MessageID <= 0 {
+		server.sendConsultationError(client, errors.New("message_id is required"))
+		return
+	}
+
+	marked, err := server.store.MarkConsultationMessagesRead(ctx, db.MarkConsultationMessagesReadParams{
+		ConsultationID: client.consultationID,
+		ReaderUsername: client.authPayload.Username,
+		UpToID:         event.MessageID,
+	})
+	if err != nil {
+		util.LogError("Failed to mark consultation %d messages read by %s: %v", client.consultationID, client.authPayload.Username, err)
+		server.sendConsultationError(client, errors.New("messages could not be marked as read"))
+		return
+	}
+	if marked == 0 {
+		return
+	}
+
+	server.consultationHub.broadcast(client.consultationID, consultationEvent{
+		Type:      consultationReadEvent,
+		Username:  client.authPayload.Username,
+		MessageID: event.MessageID,
+	}, client)
+}
+
+// sendConsultationError tells only the client that caused it about an error
+func (server *Server) sendConsultationError(client *consultationClient, err error) {
+	server.consultationHub.sendTo(client, consultationEvent{
+		Type:  consultationErrorEvent,
+		Error: err.Error(),
+	})
+}
+
+// writeConsultationEvents writes the events queued for the client and keeps
+// the connection alive with pings. The connection is closed when the queue
+// is closed or the client's token expires.
+func writeConsultationEvents(client *consultationClient) {
+	ticker := time.NewTicker(consultationPingPeriod)
+	expiry := time.NewTimer(time.Until(client.authPayload.ExpiredAt))
+	defer func() {
+		ticker.Stop()
+		expiry.Stop()
+		client.conn.Close()
+	}()
+
+	closeWith := func(code int, text string) {
+		client.conn.SetWriteDeadline(time.Now().Add(consultationWriteWait))
+		client.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, text))
+	}
+
+	for {
+		select {
+		case event, ok := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(consultationWriteWait))
+			if !ok {
+				closeWith(websocket.CloseNormalClosure, "")
+				return
+			}
+			if err := client.conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			client.conn.SetWriteDeadline(time.Now().Add(consultationWriteWait))
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-expiry.C:
+			closeWith(websocket.ClosePolicyViolation, token.ErrExpiredToken.Error())
+			return
+		}
+	}
+}
+
+type consultationAttachmentResponse struct {
+	ID          int32  `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int32  `json:"size_bytes"`
+}
+
+// consultationMessageResponse has the same shape as the messages archived
+// in a closed consultation's transcript
+type consultationMessageResponse struct {
+	ID             int32                           `json:"id"`
+	SenderUsername string                          `json:"sender_username"`
+	Body           string                          `json:"body"`
+	Attachment     *consultationAttachmentResponse `json:"attachment"`
+	CreatedAt      pgtype.Timestamp                `json:"created_at"`
+	ReadAt         pgtype.Timestamp                `json:"read_at"`
+}
+
+func newConsultationMessageResponse(message db.ConsultationMessage, attachment *consultationAttachmentResponse) consultationMessageResponse {
+	return consultationMessageResponse{
+		ID:             message.ID,
+		SenderUsername: message.SenderUsername,
+		Body:           message.Body,
+		Attachment:     attachment,
+		CreatedAt:      message.CreatedAt,
+		ReadAt:         message.ReadAt,
+	}
+}
+
+type consultationResponse struct {
+	db.Consultation
+	// Transcript is the archived messages of a closed consultation, null
+	// while it is open
+	Transcript json.RawMessage `json:"transcript"`
+}
+
+func newConsultationResponse(consultation db.Consultation) consultationResponse {
+	transcript := json.RawMessage(consultation.Transcript)
+	if len(transcript) == 0 {
+		transcript = json.RawMessage("null")
+	}
+	return consultationResponse{
+		Consultation: consultation,
+		Transcript:   transcript,
+	}
+}
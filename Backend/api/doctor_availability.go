@@ -0,0 +1,624 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// Appointment dates and times are exchanged in the doctor's local time
+const (
+	appointmentDateLayout = "2006-01-02"
+	appointmentTimeLayout = "2006-01-02T15:04"
+	// How far ahead patients can book and doctors plan exceptions and leave
+	maxAppointmentDaysAhead = 90
+	defaultSlotDays         = 7
+)
+
+type availabilitySlot struct {
+	DayOfWeek   int16  `json:"day_of_week" binding:"min=0,max=6"`
+	StartsAt    string `json:"starts_at" binding:"required"`
+	EndsAt      string `json:"ends_at" binding:"required"`
+	SlotMinutes int32  `json:"slot_minutes" binding:"required,min=5,max=240"`
+}
+
+type UpdateDoctorAvailabilityRequest struct {
+	Availability []availabilitySlot `json:"availability" binding:"dive"`
+}
+
+type DoctorUsernameRequest struct {
+	Username string `uri:"username" binding:"required"`
+}
+
+// CreateAvailabilityExceptionRequest changes the weekly hours on one date.
+// Unavailable blocks take a time range out; available ones add extra hours
+// cut into slots of slot_minutes.
+type CreateAvailabilityExceptionRequest struct {
+	Date        string `json:"date" binding:"required"`
+	StartsAt    string `json:"starts_at" binding:"required"`
+	EndsAt      string `json:"ends_at" binding:"required"`
+	Available   bool   `json:"available"`
+	SlotMinutes int32  `json:"slot_minutes" binding:"omitempty,min=5,max=240"`
+	Reason      string `json:"reason"`
+}
+
+type CreateDoctorLeaveRequest struct {
+	StartsOn string `json:"starts_on" binding:"required"`
+	EndsOn   string `json:"ends_on" binding:"required"`
+	Reason   string `json:"reason"`
+}
+
+type DoctorScheduleIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ListDoctorSlotsRequest struct {
+	From string `form:"from"`
+	Days int    `form:"days" binding:"omitempty,min=1,max=14"`
+}
+
+type appointmentSlotResponse struct {
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+}
+
+type doctorScheduleResponse struct {
+	Exceptions []db.DoctorAvailabilityException `json:"exceptions"`
+	Leaves     []db.DoctorLeave                 `json:"leaves"`
+}
+
+func newAvailabilityResponse(availability []db.DoctorAvailability) []availabilitySlot {
+	rsp := make([]availabilitySlot, 0, len(availability))
+	for _, a := range availability {
+		rsp = append(rsp, availabilitySlot{
+			DayOfWeek:   a.DayOfWeek,
+			StartsAt:    formatTimeOfDay(a.StartsAt),
+			EndsAt:      formatTimeOfDay(a.EndsAt),
+			SlotMinutes: a.SlotMinutes,
+		})
+	}
+	return rsp
+}
+
+func newSlotsResponse(slots []util.TimeRange) []appointmentSlotResponse {
+	rsp := make([]appointmentSlotResponse, 0, len(slots))
+	for _, slot := range slots {
+		rsp = append(rsp, appointmentSlotResponse{
+			StartsAt: slot.Start.Format(appointmentTimeLayout),
+			EndsAt:   slot.End.Format(appointmentTimeLayout),
+		})
+	}
+	return rsp
+}
+
+// wallClockNow is the current local time with its clock reading moved to
+// UTC, the way TIMESTAMP columns are read back
+func wallClockNow() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), time.UTC)
+}
+
+func today() time.Time {
+	return wallClockNow().Truncate(24 * time.Hour)
+}
+
+func parseAppointmentDate(field, value string) (time.Time, error) {
+	date, err := time.Parse(appointmentDateLayout, value)
+	if err != nil {
+		return date, fmt.Errorf("invalid %s, expected YYYY-MM-DD: %s", field, value)
+	}
+	return date, nil
+}
+
+// atTimeOfDay is the moment on date at a TIME column's clock reading
+func atTimeOfDay(date time.Time, t pgtype.Time) time.Time {
+	return date.Add(time.Duration(t.Microseconds) * time.Microsecond)
+}
+
+func pgTimestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t, Valid: true}
+}
+
+func pgDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: true}
+}
+
+// authorizeDoctor checks that the logged-in user is a doctor, writing the
+// error response itself when they aren't.
+func authorizeDoctor(c *gin.Context) (*token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Doctor {
+		err := errors.New("only doctors can manage their schedule")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, false
+	}
+	return authPayload, true
+}
+
+// getDoctorSlots works out the free slots of a doctor on the days from
+// from to to, both inclusive: the weekly availability plus extra hours on
+// each date, less leave, unavailable blocks and booked appointments. The
+// appointment being rescheduled, if any, doesn't count as booked.
+func (server *Server) getDoctorSlots(c *gin.Context, doctorUsername string, from, to time.Time, rescheduling int32) ([]util.TimeRange, error) {
+	availability, err := server.store.ListDoctorAvailability(c, doctorUsername)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := server.store.ListDoctorAvailabilityExceptions(c, db.ListDoctorAvailabilityExceptionsParams{
+		DoctorUsername: doctorUsername,
+		FromDate:       pgDate(from),
+		ToDate:         pgDate(to),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := server.store.ListDoctorLeaves(c, db.ListDoctorLeavesParams{
+		DoctorUsername: doctorUsername,
+		FromDate:       pgDate(from),
+		ToDate:         pgDate(to),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	booked, err := server.store.ListDoctorBookedAppointments(c, db.ListDoctorBookedAppointmentsParams{
+		DoctorUsername: doctorUsername,
+		FromTime:       pgTimestamp(from),
+		ToTime:         pgTimestamp(to.AddDate(0, 0, 1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var windows []util.AvailabilityWindow
+	var blocked []util.TimeRange
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		onLeave := false
+		for _, leave := range leaves {
+			if !date.Before(leave.StartsOn.Time) && !date.After(leave.EndsOn.Time) {
+				onLeave = true
+				break
+			}
+		}
+		if onLeave {
+			continue
+		}
+
+		for _, a := range availability {
+			if int(a.DayOfWeek) != int(date.Weekday()) {
+				continue
+			}
+			windows = append(windows, util.AvailabilityWindow{
+				TimeRange:   util.TimeRange{Start: atTimeOfDay(date, a.StartsAt), End: atTimeOfDay(date, a.EndsAt)},
+				SlotMinutes: int(a.SlotMinutes),
+			})
+		}
+
+		for _, e := range exceptions {
+			if !e.Date.Time.Equal(date) {
+				continue
+			}
+			r := util.TimeRange{Start: atTimeOfDay(date, e.StartsAt), End: atTimeOfDay(date, e.EndsAt)}
+			if e.Available {
+				windows = append(windows, util.AvailabilityWindow{TimeRange: r, SlotMinutes: int(e.SlotMinutes.Int32)})
+			} else {
+				blocked = append(blocked, r)
+			}
+		}
+	}
+
+	for _, appointment := range booked {
+		if appointment.ID == rescheduling {
+			continue
+		}
+		blocked = append(blocked, util.TimeRange{Start: appointment.StartsAt.Time, End: appointment.EndsAt.Time})
+	}
+
+	return util.AppointmentSlots(windows, blocked, wallClockNow()), nil
+}
+
+// hasBookedAppointments reports whether the doctor has booked appointments
+// overlapping from to to
+func (server *Server) hasBookedAppointments(c *gin.Context, doctorUsername string, from, to time.Time) (bool, error) {
+	booked, err := server.store.ListDoctorBookedAppointments(c, db.ListDoctorBookedAppointmentsParams{
+		DoctorUsername: doctorUsername,
+		FromTime:       pgTimestamp(from),
+		ToTime:         pgTimestamp(to),
+	})
+	return len(booked) > 0, err
+}
+
+// GetDoctorAvailability returns a doctor's weekly availability
+func (server *Server) GetDoctorAvailability(c *gin.Context) {
+	var req DoctorUsernameRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	availability, err := server.store.ListDoctorAvailability(c, req.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newAvailabilityResponse(availability))
+}
+
+// UpdateDoctorAvailability replaces the weekly availability of the
+// logged-in doctor. Appointments already booked are kept.
+func (server *Server) UpdateDoctorAvailability(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateDoctorAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ReplaceDoctorAvailabilityTxParams{
+		DoctorUsername: authPayload.Username,
+		Availability:   make([]db.CreateDoctorAvailabilityParams, 0, len(req.Availability)),
+	}
+	for _, slot := range req.Availability {
+		startsAt, err := parseTimeOfDay(slot.StartsAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		endsAt, err := parseTimeOfDay(slot.EndsAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if endsAt.Microseconds <= startsAt.Microseconds {
+			err := errors.New("ends_at must be after starts_at")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		arg.Availability = append(arg.Availability, db.CreateDoctorAvailabilityParams{
+			DayOfWeek:   slot.DayOfWeek,
+			StartsAt:    startsAt,
+			EndsAt:      endsAt,
+			SlotMinutes: slot.SlotMinutes,
+		})
+	}
+
+	availability, err := server.store.ReplaceDoctorAvailabilityTx(c, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			c.JSON(http.StatusConflict, errorResponse(errors.New("duplicate availability slot")))
+			return
+		}
+		util.LogError("Failed to update availability for doctor %s: %v", authPayload.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Availability updated for doctor %s", authPayload.Username)
+	c.JSON(http.StatusOK, newAvailabilityResponse(availability))
+}
+
+// ListDoctorSlots lists the free appointment slots of a doctor over up to
+// 14 days, starting today unless from is given.
+func (server *Server) ListDoctorSlots(c *gin.Context) {
+	var uri DoctorUsernameRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ListDoctorSlotsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetDoctorByName(c, uri.Username); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("doctor not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	from := today()
+	if req.From != "" {
+		date, err := parseAppointmentDate("from date", req.From)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if date.After(from) {
+			from = date
+		}
+	}
+
+	days := req.Days
+	if days == 0 {
+		days = defaultSlotDays
+	}
+	to := from.AddDate(0, 0, days-1)
+	if last := today().AddDate(0, 0, maxAppointmentDaysAhead); to.After(last) {
+		to = last
+	}
+
+	slots, err := server.getDoctorSlots(c, uri.Username, from, to, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newSlotsResponse(slots))
+}
+
+// CreateAvailabilityException adds extra hours on a date or blocks time
+// off. Time with booked appointments can't be blocked until they are
+// cancelled or moved.
+func (server *Server) CreateAvailabilityException(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var req CreateAvailabilityExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	date, err := parseAppointmentDate("date", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if date.Before(today()) || date.After(today().AddDate(0, 0, maxAppointmentDaysAhead)) {
+		err := fmt.Errorf("date must be within the next %d days", maxAppointmentDaysAhead)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	startsAt, err := parseTimeOfDay(req.StartsAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	endsAt, err := parseTimeOfDay(req.EndsAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if endsAt.Microseconds <= startsAt.Microseconds {
+		err := errors.New("ends_at must be after starts_at")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateDoctorAvailabilityExceptionParams{
+		DoctorUsername: authPayload.Username,
+		Date:           pgDate(date),
+		StartsAt:       startsAt,
+		EndsAt:         endsAt,
+		Available:      req.Available,
+		Reason:         req.Reason,
+	}
+
+	if req.Available {
+		if req.SlotMinutes == 0 {
+			err := errors.New("slot_minutes is required for extra hours")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.SlotMinutes = pgtype.Int4{Int32: req.SlotMinutes, Valid: true}
+	} else {
+		booked, err := server.hasBookedAppointments(c, authPayload.Username,
+			atTimeOfDay(date, startsAt), atTimeOfDay(date, endsAt))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if booked {
+			err := errors.New("appointments are booked in this time, cancel or move them first")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+	}
+
+	exception, err := server.store.CreateDoctorAvailabilityException(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Availability exception %d added for doctor %s on %s", exception.ID, authPayload.Username, req.Date)
+	c.JSON(http.StatusOK, exception)
+}
+
+// CreateDoctorLeave takes whole days off. Days with booked appointments
+// can't be taken off until they are cancelled or moved.
+func (server *Server) CreateDoctorLeave(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var req CreateDoctorLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	startsOn, err := parseAppointmentDate("starts_on", req.StartsOn)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	endsOn, err := parseAppointmentDate("ends_on", req.EndsOn)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if endsOn.Before(startsOn) {
+		err := errors.New("ends_on must not be before starts_on")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if startsOn.Before(today()) {
+		err := errors.New("leave can't start in the past")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	booked, err := server.hasBookedAppointments(c, authPayload.Username, startsOn, endsOn.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if booked {
+		err := errors.New("appointments are booked on these days, cancel or move them first")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	leave, err := server.store.CreateDoctorLeave(c, db.CreateDoctorLeaveParams{
+		DoctorUsername: authPayload.Username,
+		StartsOn:       pgDate(startsOn),
+		EndsOn:         pgDate(endsOn),
+		Reason:         req.Reason,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Leave %d added for doctor %s from %s to %s", leave.ID, authPayload.Username, req.StartsOn, req.EndsOn)
+	c.JSON(http.StatusOK, leave)
+}
+
+// ListDoctorSchedule lists the logged-in doctor's exceptions and leave from
+// today onwards
+func (server *Server) ListDoctorSchedule(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	from := today()
+	to := from.AddDate(1, 0, 0)
+
+	var rsp doctorScheduleResponse
+	var err error
+	rsp.Exceptions, err = server.store.ListDoctorAvailabilityExceptions(c, db.ListDoctorAvailabilityExceptionsParams{
+		DoctorUsername: authPayload.Username,
+		FromDate:       pgDate(from),
+		ToDate:         pgDate(to),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp.Leaves, err = server.store.ListDoctorLeaves(c, db.ListDoctorLeavesParams{
+		DoctorUsername: authPayload.Username,
+		FromDate:       pgDate(from),
+		ToDate:         pgDate(to),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+// DeleteAvailabilityException removes one of the logged-in doctor's
+// exceptions. Removing extra hours keeps the appointments booked in them.
+func (server *Server) DeleteAvailabilityException(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var req DoctorScheduleIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	exception, err := server.store.GetDoctorAvailabilityException(c, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("availability exception not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if exception.DoctorUsername != authPayload.Username {
+		err := errors.New("availability exception doesn't belong to the authenticated doctor")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteDoctorAvailabilityException(c, exception.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "availability exception deleted"})
+}
+
+// DeleteDoctorLeave cancels leave of the logged-in doctor
+func (server *Server) DeleteDoctorLeave(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var req DoctorScheduleIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	leave, err := server.store.GetDoctorLeave(c, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("leave not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if leave.DoctorUsername != authPayload.Username {
+		err := errors.New("leave doesn't belong to the authenticated doctor")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteDoctorLeave(c, leave.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "leave deleted"})
+}
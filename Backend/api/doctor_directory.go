@@ -0,0 +1,257 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+const (
+	// How far ahead the directory looks for each doctor's next free slot
+	directorySlotDays = 14
+	// How many candidates the directory fetches at a time when it has to
+	// work out free slots before it can filter and page, i.e. with
+	// available_within_days
+	directoryCandidateBatch = 50
+)
+
+// SearchDoctorsRequest holds the directory filters. All of them are
+// optional.
+type SearchDoctorsRequest struct {
+	// Specialization takes the same aliases as Aliza, e.g. "heart" for
+	// cardiology
+	Specialization string `form:"specialization"`
+	Hospital       string `form:"hospital"`
+	MinExperience  *int32 `form:"min_experience" binding:"omitempty,min=0"`
+	MaxExperience  *int32 `form:"max_experience" binding:"omitempty,min=0"`
+	Gender         string `form:"gender"`
+	Language       string `form:"language"`
+	MinRating      string `form:"min_rating"`
+	// MaxFee applies to the fee of VisitType, or to either fee without one
+	MaxFee    string `form:"max_fee"`
+	VisitType string `form:"visit_type" binding:"omitempty,oneof=in_person video"`
+	// AvailableWithinDays keeps only doctors with a free slot from today
+	// until that many days ahead
+	AvailableWithinDays int `form:"available_within_days" binding:"omitempty,min=1,max=14"`
+	// Sort is "experience" (most experienced first) or "rating" (best
+	// rated first)
+	Sort   string `form:"sort,default=experience" binding:"oneof=experience rating"`
+	Limit  int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+// doctorDirectoryEntry is the public view of a doctor. Contact details
+// stay private; patients reach doctors by booking an appointment.
+type doctorDirectoryEntry struct {
+	Username                string         `json:"username"`
+	FullName                string         `json:"full_name"`
+	Gender                  string         `json:"gender"`
+	Specialization          string         `json:"specialization"`
+	RegistrationNumber      string         `json:"registration_number"`
+	HospitalName            pgtype.Text    `json:"hospital_name"`
+	YearsExperience         int32          `json:"years_experience"`
+	Languages               []string       `json:"languages"`
+	CancellationWindowHours int32          `json:"cancellation_window_hours"`
+	InPersonFee             pgtype.Numeric `json:"in_person_fee"`
+	VideoFee                pgtype.Numeric `json:"video_fee"`
+	FollowUpWindowDays      int32          `json:"follow_up_window_days"`
+	FollowUpDiscount        int32          `json:"follow_up_discount"`
+	RefundWindowHours       int32          `json:"refund_window_hours"`
+	RatingAverage           pgtype.Numeric `json:"rating_average"`
+	ReviewCount             int32          `json:"review_count"`
+	// The first free slot in the next 14 days, null if there is none
+	NextAvailableSlot *appointmentSlotResponse `json:"next_available_slot"`
+}
+
+type doctorDirectoryResponse struct {
+	Total   int64                  `json:"total"`
+	Limit   int32                  `json:"limit"`
+	Offset  int32                  `json:"offset"`
+	Doctors []doctorDirectoryEntry `json:"doctors"`
+
+	// TotalIsEstimate is set when not every doctor was checked for a free
+	// slot. Total then counts the unchecked ones as available, so it is the
+	// most there can be.
+	TotalIsEstimate bool `json:"total_is_estimate"`
+}
+
+func newDoctorDirectoryEntry(doctor db.SearchDoctorsRow) doctorDirectoryEntry {
+	return doctorDirectoryEntry{
+		Username:                doctor.Username,
+		FullName:                doctor.FullName,
+		Gender:                  doctor.Gender,
+		Specialization:          doctor.Specialization,
+		RegistrationNumber:      doctor.RegistrationNumber,
+		HospitalName:            doctor.HospitalName,
+		YearsExperience:         doctor.YearsExperience,
+		Languages:               doctor.Languages,
+		CancellationWindowHours: doctor.CancellationWindowHours,
+		InPersonFee:             doctor.InPersonFee,
+		VideoFee:                doctor.VideoFee,
+		FollowUpWindowDays:      doctor.FollowUpWindowDays,
+		FollowUpDiscount:        doctor.FollowUpDiscount,
+		RefundWindowHours:       doctor.RefundWindowHours,
+		RatingAverage:           doctor.RatingAverage,
+		ReviewCount:             doctor.ReviewCount,
+	}
+}
+
+// SearchDoctors is the public doctor directory
+func (server *Server) SearchDoctors(c *gin.Context) {
+	var req SearchDoctorsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.SearchDoctorsParams{
+		SortBy:      req.Sort,
+		LimitCount:  req.Limit,
+		OffsetCount: req.Offset,
+	}
+
+	if specialization := util.NormalizeSpecialty(req.Specialization); specialization != "" {
+		arg.Specialization = pgtype.Text{String: specialization, Valid: true}
+	}
+
+	if hospital := strings.TrimSpace(req.Hospital); hospital != "" {
+		arg.Hospital = pgtype.Text{String: hospital, Valid: true}
+	}
+
+	if req.MinExperience != nil {
+		arg.MinExperience = pgtype.Int4{Int32: *req.MinExperience, Valid: true}
+	}
+	if req.MaxExperience != nil {
+		arg.MaxExperience = pgtype.Int4{Int32: *req.MaxExperience, Valid: true}
+	}
+	if req.MinExperience != nil && req.MaxExperience != nil && *req.MinExperience > *req.MaxExperience {
+		err := errors.New("min_experience can't be more than max_experience")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Gender != "" {
+		if !util.ValidateGender(req.Gender) {
+			err := errors.New("invalid gender provided")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.Gender = pgtype.Text{String: req.Gender, Valid: true}
+	}
+
+	if languages := util.NormalizeLanguages([]string{req.Language}); len(languages) > 0 {
+		arg.Language = pgtype.Text{String: languages[0], Valid: true}
+	}
+
+	if req.MinRating != "" {
+		rating, err := strconv.ParseFloat(req.MinRating, 64)
+		if err != nil || rating < 1 || rating > 5 {
+			err := errors.New("min_rating must be a number from 1 to 5")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if err := arg.MinRating.Scan(req.MinRating); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if req.MaxFee != "" {
+		maxFee, err := parseFee("max_fee", req.MaxFee)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.MaxFee = maxFee
+	}
+	if req.VisitType != "" {
+		arg.VisitType = pgtype.Text{String: req.VisitType, Valid: true}
+	}
+
+	days := directorySlotDays
+	if req.AvailableWithinDays > 0 {
+		days = req.AvailableWithinDays
+		arg.AvailableOnly = true
+	}
+	from := today()
+	to := from.AddDate(0, 0, days-1)
+
+	rsp := doctorDirectoryResponse{
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		Doctors: []doctorDirectoryEntry{},
+	}
+
+	// Free slots come from schedules, leave and bookings rather than a
+	// column, so with an availability filter the candidates are fetched a
+	// batch at a time and checked one by one until the page is full
+	if req.AvailableWithinDays > 0 {
+		arg.LimitCount = directoryCandidateBatch
+		arg.OffsetCount = 0
+	}
+
+	var matches int64
+	for {
+		doctors, err := server.store.SearchDoctors(c, arg)
+		if err != nil {
+			util.LogError("Failed to search doctors: %v", err)
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if len(doctors) > 0 {
+			rsp.Total = doctors[0].TotalCount
+		}
+
+		for i, doctor := range doctors {
+			entry := newDoctorDirectoryEntry(doctor)
+
+			slots, err := server.getDoctorSlots(c, doctor.Username, from, to, 0)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			if len(slots) > 0 {
+				entry.NextAvailableSlot = &newSlotsResponse(slots[:1])[0]
+			}
+
+			if req.AvailableWithinDays == 0 {
+				rsp.Doctors = append(rsp.Doctors, entry)
+				continue
+			}
+			if entry.NextAvailableSlot == nil {
+				continue
+			}
+
+			matches++
+			if matches <= int64(req.Offset) {
+				continue
+			}
+			rsp.Doctors = append(rsp.Doctors, entry)
+			if len(rsp.Doctors) == int(req.Limit) {
+				checked := int64(arg.OffsetCount) + int64(i) + 1
+				rsp.TotalIsEstimate = checked < rsp.Total
+				rsp.Total = matches + rsp.Total - checked
+				c.JSON(http.StatusOK, rsp)
+				return
+			}
+		}
+
+		if req.AvailableWithinDays == 0 {
+			break
+		}
+		if len(doctors) < int(arg.LimitCount) {
+			// Every candidate has been checked
+			rsp.Total = matches
+			break
+		}
+		arg.OffsetCount += arg.LimitCount
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// UpdateDoctorFeesRequest sets what the doctor charges. Fees are in rupees
+// and may be "0" for free visits.
+type UpdateDoctorFeesRequest struct {
+	InPersonFee        string `json:"in_person_fee" binding:"required"`
+	VideoFee           string `json:"video_fee" binding:"required"`
+	FollowUpWindowDays int32  `json:"follow_up_window_days" binding:"min=0,max=365"`
+	FollowUpDiscount   int32  `json:"follow_up_discount" binding:"min=0,max=100"`
+	RefundWindowHours  int32  `json:"refund_window_hours" binding:"min=0,max=720"`
+}
+
+// parseFee reads a fee of zero or more rupees with at most two decimal
+// places
+func parseFee(field, value string) (pgtype.Numeric, error) {
+	var fee pgtype.Numeric
+	if value != "0" && !util.IsValidMoneyAmount(value) {
+		return fee, fmt.Errorf("invalid %s: %s", field, value)
+	}
+	if err := fee.Scan(value); err != nil {
+		return fee, fmt.Errorf("invalid %s: %s", field, value)
+	}
+	return fee, nil
+}
+
+// UpdateDoctorFees sets the logged-in doctor's fees, follow-up discount
+// and refund window. Appointments already booked keep the fee they were
+// booked at.
+func (server *Server) UpdateDoctorFees(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateDoctorFeesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	inPersonFee, err := parseFee("in_person_fee", req.InPersonFee)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	videoFee, err := parseFee("video_fee", req.VideoFee)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.FollowUpDiscount > 0 && req.FollowUpWindowDays == 0 {
+		err := errors.New("a follow-up discount needs a follow-up window")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	doctor, err := server.store.SetDoctorFees(c, db.SetDoctorFeesParams{
+		Username:           authPayload.Username,
+		InPersonFee:        inPersonFee,
+		VideoFee:           videoFee,
+		FollowUpWindowDays: req.FollowUpWindowDays,
+		FollowUpDiscount:   req.FollowUpDiscount,
+		RefundWindowHours:  req.RefundWindowHours,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Fees of doctor %s updated", doctor.Username)
+	c.JSON(http.StatusOK, newDoctorResponse(doctor))
+}
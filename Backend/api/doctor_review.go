@@ -0,0 +1,323 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type CreateDoctorReviewRequest struct {
+	Rating  int16  `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment"`
+}
+
+type ListDoctorReviewsRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+type DoctorReviewIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ReportDoctorReviewRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ModerateReviewRequest publishes or rejects a held or reported review. The
+// note is kept with the review, such as why it was rejected.
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=published rejected"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+type doctorReviewsResponse struct {
+	RatingAverage pgtype.Numeric `json:"rating_average"`
+	ReviewCount   int32          `json:"review_count"`
+	// Distribution is the number of published reviews giving each rating,
+	// keyed "1" to "5"
+	Distribution map[string]int64          `json:"distribution"`
+	Reviews      []db.ListDoctorReviewsRow `json:"reviews"`
+}
+
+// CreateDoctorReview lets the logged-in patient rate and review the doctor
+// of a completed appointment, once per appointment. Reviews with contact
+// details or links are held for moderation; the rest are published at once.
+func (server *Server) CreateDoctorReview(c *gin.Context) {
+	var uri AppointmentIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req CreateDoctorReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, authPayload, ok := server.getAppointmentParty(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	if authPayload.Role != util.Patient {
+		err := errors.New("only the patient can review an appointment")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if appointment.Status != util.CompletedAppointment {
+		err := errors.New("only completed appointments can be reviewed")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	comment := strings.TrimSpace(req.Comment)
+	if utf8.RuneCountInString(comment) > util.MaxReviewCommentLength {
+		err := fmt.Errorf("comment is longer than %d characters", util.MaxReviewCommentLength)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	status := util.PublishedReview
+	moderationNote := util.ScreenReviewComment(comment)
+	if moderationNote != "" {
+		status = util.PendingReview
+	}
+
+	review, err := server.store.CreateDoctorReview(c, db.CreateDoctorReviewParams{
+		AppointmentID:   appointment.ID,
+		DoctorUsername:  appointment.DoctorUsername,
+		PatientUsername: appointment.PatientUsername,
+		Rating:          req.Rating,
+		Comment:         comment,
+		Status:          status,
+		ModerationNote:  moderationNote,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("this appointment has already been reviewed")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to save review of appointment %d: %v", appointment.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Review %d of doctor %s by %s saved as %s", review.ID, review.DoctorUsername, authPayload.Username, review.Status)
+	c.JSON(http.StatusOK, review)
+}
+
+// GetAppointmentReview returns the review of an appointment to its patient
+// or doctor, whatever its moderation status
+func (server *Server) GetAppointmentReview(c *gin.Context) {
+	var req AppointmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, _, ok := server.getAppointmentParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	review, err := server.store.GetDoctorReviewByAppointment(c, appointment.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("appointment hasn't been reviewed")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ListDoctorReviews is the public view of a doctor's rating: the average,
+// how the ratings are spread, and the published reviews newest first
+func (server *Server) ListDoctorReviews(c *gin.Context) {
+	var uri GetDoctorRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ListDoctorReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	doctor, err := server.store.GetDoctorByName(c, uri.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("doctor not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	distribution, err := server.store.GetDoctorRatingDistribution(c, doctor.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	reviews, err := server.store.ListDoctorReviews(c, db.ListDoctorReviewsParams{
+		DoctorUsername: doctor.Username,
+		LimitCount:     req.Limit,
+		OffsetCount:    req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := doctorReviewsResponse{
+		RatingAverage: doctor.RatingAverage,
+		ReviewCount:   doctor.ReviewCount,
+		Distribution:  make(map[string]int64, 5),
+		Reviews:       reviews,
+	}
+	for rating := 1; rating <= 5; rating++ {
+		rsp.Distribution[fmt.Sprint(rating)] = 0
+	}
+	for _, row := range distribution {
+		rsp.Distribution[fmt.Sprint(row.Rating)] = row.Reviews
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+// ReportDoctorReview lets the logged-in doctor flag a published review of
+// theirs for an operator to look at. The review stays up until then.
+func (server *Server) ReportDoctorReview(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var uri DoctorReviewIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ReportDoctorReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := server.store.GetDoctorReview(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("review not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if review.DoctorUsername != authPayload.Username {
+		err := errors.New("review isn't about the authenticated doctor")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	review, err = server.store.ReportDoctorReview(c, db.ReportDoctorReviewParams{
+		ID:           review.ID,
+		ReportReason: pgtype.Text{String: strings.TrimSpace(req.Reason), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("only published reviews can be reported")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Review %d reported by doctor %s", review.ID, authPayload.Username)
+	c.JSON(http.StatusOK, review)
+}
+
+// ListDoctorReviewModerationQueue lists the doctor reviews waiting for an
+// admin: held ones and those reported by their doctor
+func (server *Server) ListDoctorReviewModerationQueue(c *gin.Context) {
+	if _, ok := authorizeAdmin(c); !ok {
+		return
+	}
+
+	var req ListDoctorReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	reviews, err := server.store.ListDoctorReviewModerationQueue(c, db.ListDoctorReviewModerationQueueParams{
+		LimitCount:  req.Limit,
+		OffsetCount: req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ModerateDoctorReview lets an admin publish or reject a doctor review. The
+// doctor's rating is updated to match.
+func (server *Server) ModerateDoctorReview(c *gin.Context) {
+	authPayload, ok := authorizeAdmin(c)
+	if !ok {
+		return
+	}
+
+	var uri DoctorReviewIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := server.store.ModerateDoctorReview(c, db.ModerateDoctorReviewParams{
+		ID:             uri.ID,
+		Status:         req.Status,
+		ModerationNote: strings.TrimSpace(req.Note),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("review not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Review %d of doctor %s %s by admin %s", review.ID, review.DoctorUsername, review.Status, authPayload.Username)
+	c.JSON(http.StatusOK, review)
+}
@@ -0,0 +1,660 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/storage"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type UploadDocumentRequest struct {
+	File         *multipart.FileHeader `form:"file" binding:"required"`
+	DocumentType string                `form:"document_type" binding:"required"`
+	Title        string                `form:"title"`
+	DocumentDate string                `form:"document_date"`
+	Tags         []string              `form:"tags"`
+}
+
+// UpdateDocumentRequest changes the fields given. An empty document_date
+// clears it.
+type UpdateDocumentRequest struct {
+	DocumentType *string   `json:"document_type"`
+	Title        *string   `json:"title"`
+	DocumentDate *string   `json:"document_date"`
+	Tags         *[]string `json:"tags"`
+}
+
+type ListDocumentsRequest struct {
+	DocumentType string `form:"document_type"`
+	Tag          string `form:"tag"`
+	From         string `form:"from"`
+	To           string `form:"to"`
+	Limit        int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset       int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+type DocumentIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ShareDocumentsRequest struct {
+	DocumentIDs    []int32 `json:"document_ids" binding:"required,min=1,max=50,dive,min=1"`
+	DoctorUsername string  `json:"doctor_username" binding:"required,alphanum"`
+	ExpiresInHours int32   `json:"expires_in_hours" binding:"required,min=1"`
+}
+
+type ListSharedDocumentsRequest struct {
+	PatientUsername string `form:"patient" binding:"omitempty,alphanum"`
+	Limit           int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset          int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+// documentResponse is a document in the vault without the key its file is
+// stored under
+type documentResponse struct {
+	ID              int32            `json:"id"`
+	PatientUsername string           `json:"patient_username"`
+	DocumentType    string           `json:"document_type"`
+	Title           string           `json:"title"`
+	DocumentDate    pgtype.Date      `json:"document_date"`
+	Tags            []string         `json:"tags"`
+	FileName        string           `json:"file_name"`
+	ContentType     string           `json:"content_type"`
+	SizeBytes       int32            `json:"size_bytes"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+func newDocumentResponse(document db.PatientDocument) documentResponse {
+	return documentResponse{
+		ID:              document.ID,
+		PatientUsername: document.PatientUsername,
+		DocumentType:    document.DocumentType,
+		Title:           document.Title,
+		DocumentDate:    document.DocumentDate,
+		Tags:            document.Tags,
+		FileName:        document.FileName,
+		ContentType:     document.ContentType,
+		SizeBytes:       document.SizeBytes,
+		CreatedAt:       document.CreatedAt,
+		UpdatedAt:       document.UpdatedAt,
+	}
+}
+
+// UploadDocument adds a lab report, discharge summary, scan or other
+// document to the logged-in patient's medical record vault. Files are
+// checked by their contents and scanned for viruses before they are stored.
+func (server *Server) UploadDocument(c *gin.Context) {
+	authPayload, ok := authorizeDocumentOwner(c)
+	if !ok {
+		return
+	}
+
+	var req UploadDocumentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.File.Size > util.MaxDocumentSize {
+		err := fmt.Errorf("file is larger than %d MB", util.MaxDocumentSize>>20)
+		c.JSON(http.StatusRequestEntityTooLarge, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidDocumentType(req.DocumentType) {
+		err := fmt.Errorf("invalid document_type: %s", req.DocumentType)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	documentDate, err := parsePastDate("document_date", req.DocumentDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	tags, err := util.NormalizeDocumentTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fileName := filepath.Base(req.File.Filename)
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(data) == 0 {
+		err := errors.New("file is empty")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	contentType, ok := util.DocumentContentType(data)
+	if !ok {
+		err := errors.New("file must be a PDF, JPEG, PNG, WebP or DICOM file")
+		c.JSON(http.StatusUnsupportedMediaType, errorResponse(err))
+		return
+	}
+
+	// A file sent as one of the accepted types has to actually be of it
+	declaredType, _, _ := mime.ParseMediaType(req.File.Header.Get("Content-Type"))
+	if util.IsDocumentContentType(declaredType) && declaredType != contentType {
+		err := fmt.Errorf("file content doesn't match its type %s", declaredType)
+		c.JSON(http.StatusUnsupportedMediaType, errorResponse(err))
+		return
+	}
+
+	if err := server.virusScanner.Scan(c, fileName, data); err != nil {
+		if errors.Is(err, storage.ErrInfected) {
+			util.LogWarning("Upload of %s by %s failed the virus scan: %v", fileName, authPayload.Username, err)
+			c.JSON(http.StatusUnprocessableEntity, errorResponse(storage.ErrInfected))
+			return
+		}
+		util.LogError("Failed to scan upload of %s by %s: %v", fileName, authPayload.Username, err)
+		err := errors.New("the file couldn't be scanned, please try again later")
+		c.JSON(http.StatusServiceUnavailable, errorResponse(err))
+		return
+	}
+
+	storageKey := fmt.Sprintf("patients/%s/%s", authPayload.Username, uuid.NewString())
+	if err := server.documentStorage.Put(c, storageKey, data); err != nil {
+		util.LogError("Failed to store upload of %s by %s: %v", fileName, authPayload.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	document, err := server.store.CreatePatientDocument(c, db.CreatePatientDocumentParams{
+		PatientUsername: authPayload.Username,
+		DocumentType:    req.DocumentType,
+		Title:           title,
+		DocumentDate:    documentDate,
+		Tags:            tags,
+		FileName:        fileName,
+		ContentType:     contentType,
+		SizeBytes:       int32(len(data)),
+		StorageKey:      storageKey,
+	})
+	if err != nil {
+		util.LogError("Failed to save document %s of %s: %v", fileName, authPayload.Username, err)
+		server.deleteDocumentFile(c, storageKey)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Document %d (%s) uploaded by %s", document.ID, document.DocumentType, authPayload.Username)
+	c.JSON(http.StatusOK, newDocumentResponse(document))
+}
+
+// ListDocuments lists the logged-in patient's documents, newest first
+func (server *Server) ListDocuments(c *gin.Context) {
+	authPayload, ok := authorizeDocumentOwner(c)
+	if !ok {
+		return
+	}
+
+	var req ListDocumentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListPatientDocumentsParams{
+		PatientUsername: authPayload.Username,
+		LimitCount:      req.Limit,
+		OffsetCount:     req.Offset,
+	}
+
+	if req.DocumentType != "" {
+		if !util.IsValidDocumentType(req.DocumentType) {
+			err := fmt.Errorf("invalid document_type: %s", req.DocumentType)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.DocumentType = pgtype.Text{String: req.DocumentType, Valid: true}
+	}
+
+	if req.Tag != "" {
+		tags, err := util.NormalizeDocumentTags([]string{req.Tag})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if len(tags) > 0 {
+			arg.Tag = pgtype.Text{String: tags[0], Valid: true}
+		}
+	}
+
+	if req.From != "" {
+		from, err := parseAppointmentDate("from", req.From)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.FromDate = pgDate(from)
+	}
+
+	if req.To != "" {
+		to, err := parseAppointmentDate("to", req.To)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.ToDate = pgDate(to)
+	}
+
+	documents, err := server.store.ListPatientDocuments(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]documentResponse, len(documents))
+	for i, document := range documents {
+		rsp[i] = newDocumentResponse(document)
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+// GetDocument returns a document to its patient, or to a doctor it is
+// shared with
+func (server *Server) GetDocument(c *gin.Context) {
+	var req DocumentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	document, _, ok := server.getDocumentReader(c, req.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, newDocumentResponse(document))
+}
+
+// DownloadDocument downloads the file of a document, for its patient or a
+// doctor it is shared with
+func (server *Server) DownloadDocument(c *gin.Context) {
+	var req DocumentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	document, authPayload, ok := server.getDocumentReader(c, req.ID)
+	if !ok {
+		return
+	}
+
+	data, err := server.documentStorage.Get(c, document.StorageKey)
+	if err != nil {
+		util.LogError("Failed to read the file of document %d: %v", document.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if authPayload.Role == util.Doctor {
+		util.LogInfo("Document %d of %s downloaded by doctor %s", document.ID, document.PatientUsername, authPayload.Username)
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, document.ContentType, data)
+}
+
+// UpdateDocument changes the type, title, date or tags of one of the
+// logged-in patient's documents
+func (server *Server) UpdateDocument(c *gin.Context) {
+	authPayload, ok := authorizeDocumentOwner(c)
+	if !ok {
+		return
+	}
+
+	var uri DocumentIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req UpdateDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	document, ok := server.getOwnDocument(c, authPayload.Username, uri.ID)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdatePatientDocumentParams{
+		ID:              document.ID,
+		PatientUsername: authPayload.Username,
+		DocumentType:    document.DocumentType,
+		Title:           document.Title,
+		DocumentDate:    document.DocumentDate,
+		Tags:            document.Tags,
+	}
+
+	if req.DocumentType != nil {
+		if !util.IsValidDocumentType(*req.DocumentType) {
+			err := fmt.Errorf("invalid document_type: %s", *req.DocumentType)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.DocumentType = *req.DocumentType
+	}
+
+	if req.Title != nil {
+		arg.Title = strings.TrimSpace(*req.Title)
+		if arg.Title == "" {
+			err := errors.New("title can't be empty")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if req.DocumentDate != nil {
+		var err error
+		arg.DocumentDate, err = parsePastDate("document_date", *req.DocumentDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if req.Tags != nil {
+		var err error
+		arg.Tags, err = util.NormalizeDocumentTags(*req.Tags)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	document, err := server.store.UpdatePatientDocument(c, arg)
+	if err != nil {
+		util.LogError("Failed to update document %d of %s: %v", uri.ID, authPayload.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newDocumentResponse(document))
+}
+
+// DeleteDocument removes a document and its file from the logged-in
+// patient's vault, ending any shares of it
+func (server *Server) DeleteDocument(c *gin.Context) {
+	authPayload, ok := authorizeDocumentOwner(c)
+	if !ok {
+		return
+	}
+
+	var req DocumentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	document, err := server.store.DeletePatientDocument(c, db.DeletePatientDocumentParams{
+		ID:              req.ID,
+		PatientUsername: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("document not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.deleteDocumentFile(c, document.StorageKey)
+
+	util.LogInfo("Document %d deleted by %s", document.ID, authPayload.Username)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "document deleted successfully"})
+}
+
+// ShareDocuments shares some of the logged-in patient's documents with a
+// doctor for a limited time
+func (server *Server) ShareDocuments(c *gin.Context) {
+	authPayload, ok := authorizeDocumentOwner(c)
+	if !ok {
+		return
+	}
+
+	var req ShareDocumentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.ExpiresInHours > util.MaxDocumentShareHours {
+		err := fmt.Errorf("documents can be shared for at most %d hours", util.MaxDocumentShareHours)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	doctor, err := server.store.GetDoctorByName(c, req.DoctorUsername)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("doctor not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	documentIDs := make([]int32, 0, len(req.DocumentIDs))
+	for _, id := range req.DocumentIDs {
+		if _, ok := server.getOwnDocument(c, authPayload.Username, id); !ok {
+			return
+		}
+		documentIDs = append(documentIDs, id)
+	}
+
+	shares, err := server.store.ShareDocumentsTx(c, db.ShareDocumentsTxParams{
+		DocumentIDs:    documentIDs,
+		DoctorUsername: doctor.Username,
+		ExpiresAt:      pgTimestamp(time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)),
+	})
+	if err != nil {
+		util.LogError("Failed to share documents of %s with %s: %v", authPayload.Username, doctor.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Patient %s shared %d documents with doctor %s for %d hours", authPayload.Username, len(shares), doctor.Username, req.ExpiresInHours)
+	c.JSON(http.StatusOK, shares)
+}
+
+// ListDocumentShares lists the logged-in patient's shares that are still
+// in force
+func (server *Server) ListDocumentShares(c *gin.Context) {
+	authPayload, ok := authorizeDocumentOwner(c)
+	if !ok {
+		return
+	}
+
+	shares, err := server.store.ListDocumentShares(c, authPayload.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, shares)
+}
+
+// RevokeDocumentShare ends a share before it expires
+func (server *Server) RevokeDocumentShare(c *gin.Context) {
+	authPayload, ok := authorizeDocumentOwner(c)
+	if !ok {
+		return
+	}
+
+	var req DocumentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	share, err := server.store.RevokeDocumentShare(c, db.RevokeDocumentShareParams{
+		ID:              req.ID,
+		PatientUsername: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("share not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Share %d of document %d revoked by %s", share.ID, share.DocumentID, authPayload.Username)
+	c.JSON(http.StatusOK, share)
+}
+
+// ListSharedDocuments lists the documents patients currently share with
+// the logged-in doctor
+func (server *Server) ListSharedDocuments(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Doctor {
+		err := errors.New("only doctors have documents shared with them")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req ListSharedDocumentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListDocumentsSharedWithDoctorParams{
+		DoctorUsername: authPayload.Username,
+		LimitCount:     req.Limit,
+		OffsetCount:    req.Offset,
+	}
+	if req.PatientUsername != "" {
+		arg.PatientUsername = pgtype.Text{String: req.PatientUsername, Valid: true}
+	}
+
+	documents, err := server.store.ListDocumentsSharedWithDoctor(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// authorizeDocumentOwner checks that the logged-in user is a patient, the
+// only users with a medical record vault, writing the error response
+// itself when they aren't.
+func authorizeDocumentOwner(c *gin.Context) (*token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can manage their documents")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, false
+	}
+	return authPayload, true
+}
+
+// getOwnDocument loads one of the patient's documents, writing the error
+// response itself when there is no such document
+func (server *Server) getOwnDocument(c *gin.Context, patientUsername string, id int32) (db.PatientDocument, bool) {
+	document, err := server.store.GetPatientDocument(c, id)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return document, false
+	}
+	if err != nil || document.PatientUsername != patientUsername {
+		err := fmt.Errorf("document %d not found", id)
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return document, false
+	}
+	return document, true
+}
+
+// getDocumentReader loads a document the logged-in user can read: their
+// own, or, for doctors, one shared with them that hasn't expired. It
+// writes the error response itself when they can't.
+func (server *Server) getDocumentReader(c *gin.Context, id int32) (db.PatientDocument, *token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	document, err := server.store.GetPatientDocument(c, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("document not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return document, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return document, nil, false
+	}
+
+	switch authPayload.Role {
+	case util.Patient:
+		if document.PatientUsername == authPayload.Username {
+			return document, authPayload, true
+		}
+	case util.Doctor:
+		shared, err := server.store.IsDocumentSharedWithDoctor(c, db.IsDocumentSharedWithDoctorParams{
+			DocumentID:     document.ID,
+			DoctorUsername: authPayload.Username,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return document, nil, false
+		}
+		if shared {
+			return document, authPayload, true
+		}
+	}
+
+	err = errors.New("document isn't shared with the authenticated user")
+	c.JSON(http.StatusUnauthorized, errorResponse(err))
+	return document, nil, false
+}
+
+// deleteDocumentFile removes a document's file from storage. A file that
+// can't be removed is only logged: the document is already gone.
+func (server *Server) deleteDocumentFile(c *gin.Context, storageKey string) {
+	if err := server.documentStorage.Delete(c, storageKey); err != nil {
+		util.LogError("Failed to delete stored file %s: %v", storageKey, err)
+	}
+}
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type CreateDonationOfferRequest struct {
+	OfferType string `json:"offer_type" binding:"required"`
+	Quantity  int32  `json:"quantity" binding:"required,min=1"`
+	// UnitPrice is required for discounted offers and must be below the
+	// medicine's price; donations are free
+	UnitPrice string `json:"unit_price"`
+	Notes     string `json:"notes"`
+}
+
+type DonationOfferIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ListDonationOffersRequest struct {
+	Medicine  string `form:"medicine"`
+	OfferType string `form:"offer_type"`
+	Limit     int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset    int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+type ClaimDonationOfferRequest struct {
+	Quantity int32 `json:"quantity" binding:"required,min=1"`
+}
+
+type ListDonationHistoryRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+// CreateDonationOffer puts some of a near-expiry medicine's stock on offer
+// to NGO and hospital sellers, either free or at a deep discount. The stock
+// stays on sale until it is claimed.
+func (server *Server) CreateDonationOffer(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	if !util.CanOfferDonations(seller.SellerType) {
+		err := errors.New("only retail and wholesale sellers can offer stock")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	medicine, ok := server.getSellerMedicine(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var req CreateDonationOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidDonationOfferType(req.OfferType) {
+		err := errors.New("invalid offer type")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if medicine.Status != util.ActiveMedicine {
+		err := errors.New("medicine is not on sale")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	days := int(math.Ceil(time.Until(medicine.ExpiryDate.Time).Hours() / 24))
+	if !util.IsDonationCandidate(days, server.config.DonationWindowDays) {
+		err := fmt.Errorf("only stock expiring within %d days can be offered", server.config.DonationWindowDays)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Quantity > medicine.Quantity {
+		c.JSON(http.StatusBadRequest, errorResponse(db.ErrInsufficientStock))
+		return
+	}
+
+	unitPrice, err := newDonationUnitPrice(req.OfferType, req.UnitPrice, medicine.Price)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	offer, err := server.store.CreateDonationOffer(c, db.CreateDonationOfferParams{
+		MedicineID:      medicine.ID,
+		SellerUsername:  seller.Username,
+		OfferType:       req.OfferType,
+		UnitPrice:       unitPrice,
+		QuantityOffered: req.Quantity,
+		Notes:           strings.TrimSpace(req.Notes),
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("medicine is already on offer")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Donation offer %d created by seller %s for medicine %d", offer.ID, seller.Username, medicine.ID)
+	c.JSON(http.StatusOK, offer)
+}
+
+// newDonationUnitPrice checks the price of an offer against the medicine's
+// own price. Donations are always free.
+func newDonationUnitPrice(offerType, unitPrice string, medicinePrice pgtype.Numeric) (pgtype.Numeric, error) {
+	var price pgtype.Numeric
+	if offerType == util.DonationOffer {
+		err := price.Scan("0")
+		return price, err
+	}
+
+	if unitPrice == "" {
+		return price, errors.New("unit price is required for discounted offers")
+	}
+	if err := price.Scan(unitPrice); err != nil {
+		return price, errors.New("invalid unit price format")
+	}
+
+	offered, err := price.Float64Value()
+	if err != nil {
+		return price, errors.New("invalid unit price format")
+	}
+	listed, err := medicinePrice.Float64Value()
+	if err != nil {
+		return price, err
+	}
+	if offered.Float64 <= 0 || offered.Float64 >= listed.Float64 {
+		return price, errors.New("unit price must be above zero and below the medicine's price")
+	}
+
+	return price, nil
+}
+
+// WithdrawDonationOffer takes an open offer off the marketplace. Claims
+// already made are not affected.
+func (server *Server) WithdrawDonationOffer(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var uri DonationOfferIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	offer, err := server.store.GetDonationOffer(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if offer.SellerUsername != authPayload.Store {
+		err := errors.New("offer doesn't belong to the authenticated seller")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	offer, err = server.store.WithdrawDonationOffer(c, offer.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, errorResponse(db.ErrDonationOfferUnavailable))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// ListDonationOffers lets NGO and hospital sellers browse the open offers,
+// soonest expiry first.
+func (server *Server) ListDonationOffers(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, nil)
+	if !ok {
+		return
+	}
+
+	if !util.CanClaimDonations(seller.SellerType) {
+		err := errors.New("only NGO and hospital sellers can browse donation offers")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req ListDonationOffersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListOpenDonationOffersParams{
+		Medicine: strings.TrimSpace(req.Medicine),
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+	if req.OfferType != "" {
+		if !util.IsValidDonationOfferType(req.OfferType) {
+			err := errors.New("invalid offer type")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.OfferType = pgtype.Text{String: req.OfferType, Valid: true}
+	}
+
+	offers, err := server.store.ListOpenDonationOffers(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// ListSellerDonationOffers lists the offers the logged-in seller has made
+func (server *Server) ListSellerDonationOffers(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListDonationHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	offers, err := server.store.ListSellerDonationOffers(c, db.ListSellerDonationOffersParams{
+		SellerUsername: authPayload.Store,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// ClaimDonationOffer transfers part or all of an offer into the claiming
+// NGO or hospital seller's inventory.
+func (server *Server) ClaimDonationOffer(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	if !util.CanClaimDonations(seller.SellerType) {
+		err := errors.New("only NGO and hospital sellers can claim donation offers")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !seller.VerifiedAt.Valid {
+		err := errors.New("seller must be verified before claiming donation offers")
+		c.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	var uri DonationOfferIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ClaimDonationOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ClaimDonationTx(c, db.ClaimDonationTxParams{
+		OfferID:          uri.ID,
+		ClaimantUsername: seller.Username,
+		Quantity:         req.Quantity,
+		OutMovementType:  util.TransferOutMovement,
+		InMovementType:   util.TransferInMovement,
+		Reason:           "donation claim",
+		Reference:        fmt.Sprintf("donation-offer:%d", uri.ID),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrDonationOfferUnavailable),
+			errors.Is(err, db.ErrDonationQuantityExceeded),
+			errors.Is(err, db.ErrDonationMedicineExpired),
+			errors.Is(err, db.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			util.LogError("Failed to claim donation offer %d: %v", uri.ID, err)
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	util.LogInfo("Seller %s claimed %d units of donation offer %d", seller.Username, req.Quantity, uri.ID)
+	c.JSON(http.StatusOK, result)
+}
+
+// ListDonationClaims returns the transfer history of the logged-in seller:
+// what they received for NGO and hospital sellers, what they gave away for
+// everyone else.
+func (server *Server) ListDonationClaims(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListDonationHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if util.CanClaimDonations(seller.SellerType) {
+		claims, err := server.store.ListDonationClaimsByClaimant(c, db.ListDonationClaimsByClaimantParams{
+			ClaimantUsername: seller.Username,
+			Limit:            req.Limit,
+			Offset:           req.Offset,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusOK, claims)
+		return
+	}
+
+	claims, err := server.store.ListDonationClaimsByDonor(c, db.ListDonationClaimsByDonorParams{
+		DonorUsername: seller.Username,
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/mail"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// SetReorderLevelRequest sets the stock level at or below which the seller
+// is alerted. A null reorder level switches the alert off.
+type SetReorderLevelRequest struct {
+	ReorderLevel *int32 `json:"reorder_level" binding:"omitempty,min=0"`
+}
+
+type lowStockMedicineResponse struct {
+	db.ListLowStockMedicinesRow
+	SalesWindowDays   int `json:"sales_window_days"`
+	SuggestedQuantity int `json:"suggested_quantity"`
+}
+
+func (server *Server) SetReorderLevel(c *gin.Context) {
+	medicine, ok := server.getSellerMedicine(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	var req SetReorderLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.SetMedicineReorderLevelParams{ID: medicine.ID}
+	if req.ReorderLevel != nil {
+		arg.ReorderLevel = pgtype.Int4{Int32: *req.ReorderLevel, Valid: true}
+	}
+
+	medicine, err := server.store.SetMedicineReorderLevel(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, medicine)
+}
+
+// ListLowStockMedicines is the seller dashboard view of the medicines at or
+// below their reorder level, with a suggested order quantity based on recent
+// sales.
+func (server *Server) ListLowStockMedicines(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	medicines, err := server.store.ListLowStockMedicines(c, db.ListLowStockMedicinesParams{
+		SalesSince: mail.LowStockSalesSince(server.config),
+		Seller:     pgtype.Text{String: authPayload.Store, Valid: true},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]lowStockMedicineResponse, len(medicines))
+	for i, medicine := range medicines {
+		response[i] = lowStockMedicineResponse{
+			ListLowStockMedicinesRow: medicine,
+			SalesWindowDays:          server.config.LowStockSalesWindowDays,
+			SuggestedQuantity:        mail.SuggestReorderQuantity(server.config, medicine),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// CreateMarkdownRuleRequest describes a rule such as "40% off when under 30
+// days to expiry"
+type CreateMarkdownRuleRequest struct {
+	MaxDaysToExpiry int32 `json:"max_days_to_expiry" binding:"required,min=1"`
+	Discount        int32 `json:"discount" binding:"required,min=1,max=100"`
+}
+
+type UpdateMarkdownRuleRequest struct {
+	MaxDaysToExpiry *int32 `json:"max_days_to_expiry" binding:"omitempty,min=1"`
+	Discount        *int32 `json:"discount" binding:"omitempty,min=1,max=100"`
+	Active          *bool  `json:"active"`
+}
+
+type MarkdownRuleIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ListMarkdownEventsRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+func (server *Server) CreateMarkdownRule(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var req CreateMarkdownRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rule, err := server.store.CreateMarkdownRule(c, db.CreateMarkdownRuleParams{
+		SellerUsername:  authPayload.Store,
+		MaxDaysToExpiry: req.MaxDaysToExpiry,
+		Discount:        req.Discount,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("a rule for this number of days already exists")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Markdown rule %d created by %s for %s", rule.ID, authPayload.Username, authPayload.Store)
+	c.JSON(http.StatusOK, rule)
+}
+
+func (server *Server) ListMarkdownRules(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	rules, err := server.store.ListMarkdownRules(c, authPayload.Store)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// getSellerMarkdownRule loads the rule in the URI and checks that it belongs
+// to the logged-in user's store and that they may manage it, writing the
+// error response itself when it doesn't.
+func (server *Server) getSellerMarkdownRule(c *gin.Context) (db.MarkdownRule, bool) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return db.MarkdownRule{}, false
+	}
+
+	var uri MarkdownRuleIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.MarkdownRule{}, false
+	}
+
+	rule, err := server.store.GetMarkdownRule(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return rule, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return rule, false
+	}
+
+	if rule.SellerUsername != authPayload.Store {
+		err := errors.New("markdown rule doesn't belong to the authenticated seller")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return rule, false
+	}
+
+	return rule, true
+}
+
+// UpdateMarkdownRule changes a rule or switches it on or off. Medicines pick
+// up the change on the next markdown run.
+func (server *Server) UpdateMarkdownRule(c *gin.Context) {
+	rule, ok := server.getSellerMarkdownRule(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateMarkdownRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateMarkdownRuleParams{ID: rule.ID}
+	if req.MaxDaysToExpiry != nil {
+		arg.MaxDaysToExpiry = pgtype.Int4{Int32: *req.MaxDaysToExpiry, Valid: true}
+	}
+	if req.Discount != nil {
+		arg.Discount = pgtype.Int4{Int32: *req.Discount, Valid: true}
+	}
+	if req.Active != nil {
+		arg.Active = pgtype.Bool{Bool: *req.Active, Valid: true}
+	}
+
+	rule, err := server.store.UpdateMarkdownRule(c, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("a rule for this number of days already exists")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteMarkdownRule removes a rule. Discounts it applied are restored on
+// the next markdown run unless another rule covers the medicine.
+func (server *Server) DeleteMarkdownRule(c *gin.Context) {
+	rule, ok := server.getSellerMarkdownRule(c)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteMarkdownRule(c, rule.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "markdown rule deleted"})
+}
+
+// ListMarkdownEvents returns the log of discounts the markdown job applied
+// to and restored on the seller's medicines.
+func (server *Server) ListMarkdownEvents(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListMarkdownEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	events, err := server.store.ListMarkdownEvents(c, db.ListMarkdownEventsParams{
+		SellerUsername: authPayload.Store,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
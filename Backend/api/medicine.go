@@ -1,406 +1,496 @@
-package api
-
-import (
-	"errors"
-	"net/http"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgtype"
-	db "github.com/pawaspy/MediBridge/db/sqlc"
-	"github.com/pawaspy/MediBridge/token"
-	"github.com/pawaspy/MediBridge/util"
-)
-
-type CreateMedicineRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description" binding:"required"`
-	ExpiryDate  string `json:"expiry_date" binding:"required"`
-	Quantity    int32  `json:"quantity" binding:"required"`
-	Price       string `json:"price" binding:"required"`
-	Discount    int32  `json:"discount" binding:"required"`
-	Seller      string `json:"seller" binding:"required"`
-}
-
-type UpdateMedicineRequest struct {
-	Name        string `json:"name" binding:"omitempty"`
-	Description string `json:"description" binding:"omitempty"`
-	ExpiryDate  string `json:"expiry_date" binding:"omitempty"`
-	Quantity    int32  `json:"quantity" binding:"omitempty"`
-	Price       string `json:"price" binding:"omitempty"`
-	Discount    int32  `json:"discount" binding:"omitempty"`
-	Seller      string `json:"seller" binding:"omitempty"`
-}
-
-type MedicineResponse struct {
-	ID             int32  `json:"id"`
-	Name           string `json:"name"`
-	Description    string `json:"description"`
-	ExpiryDate     string `json:"expiry_date"`
-	Quantity       int32  `json:"quantity"`
-	Price          string `json:"price"`
-	Discount       int32  `json:"discount"`
-	SellerUsername string `json:"seller_username"`
-	CreatedAt      string `json:"created_at"`
-}
-
-type DeleteMedicineRequest struct {
-	ID int32 `uri:"id" binding:"required"`
-}
-
-type MedicineIDRequest struct {
-	ID int32 `uri:"id" binding:"required"`
-}
-
-type ListSellerMedicinesRequest struct {
-	SellerUsername string `uri:"username" binding:"required"`
-	Limit          int32  `form:"limit,default=10"`
-	Offset         int32  `form:"offset,default=0"`
-}
-
-type SearchMedicinesRequest struct {
-	Name   string `form:"name" binding:"required"`
-	Limit  int32  `form:"limit,default=10"`
-	Offset int32  `form:"offset,default=0"`
-}
-
-func (server *Server) CreateMedicine(c *gin.Context) {
-	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
-
-	var req CreateMedicineRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		util.LogError("Failed to bind JSON: %v", err)
-		err = errors.New("failed to bind JSON")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	// Log the request data for debugging
-	util.LogInfo("Creating medicine with data: %+v", req)
-	util.LogInfo("Auth payload: %+v", authPayload)
-
-	if req.Quantity < 0 {
-		err := errors.New("quantity cannot be negative")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	if req.Discount < 0 {
-		err := errors.New("discount cannot be negative")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	if req.Price == "" {
-		err := errors.New("price cannot be empty")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	if req.Seller == "" || req.Seller != authPayload.Username {
-		util.LogError("Invalid seller: req.Seller=%s, authPayload.Username=%s", req.Seller, authPayload.Username)
-		err := errors.New("invalid seller username")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	// Check if the seller exists
-	seller, err := server.store.GetSellerByName(c, req.Seller)
-	if err != nil {
-		util.LogError("Failed to get seller: %v", err)
-		if errors.Is(err, db.ErrRecordNotFound) {
-			err := errors.New("seller not found")
-			c.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	if seller.Username != req.Seller {
-		util.LogError("Seller username mismatch: seller.Username=%s, req.Seller=%s", seller.Username, req.Seller)
-		err := errors.New("invalid seller username")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	// Check if the medicine already exists
-	medicine, err := server.store.GetMedicineByName(c, req.Name)
-	if err != nil {
-		if err != db.ErrRecordNotFound {
-			util.LogError("Failed to check existing medicine: %v", err)
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-			return
-		}
-	}
-
-	if medicine.Name == req.Name && medicine.ExpiryDate.Time.Format("2006-01-02") == req.ExpiryDate {
-		arg := db.UpdateMedicineParams{
-			Quantity: pgtype.Int4{
-				Int32: req.Quantity + medicine.Quantity,
-				Valid: true,
-			},
-		}
-		medicine, err = server.store.UpdateMedicine(c, arg)
-		if err != nil {
-			util.LogError("Failed to update existing medicine: %v", err)
-			err := errors.New("failed to update medicine")
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-			return
-		}
-		c.JSON(http.StatusOK, medicine)
-		return
-	}
-
-	// Convert the expiry date to the correct format
-	expiryDate, err := time.Parse("2006-01-02", req.ExpiryDate)
-	if err != nil {
-		util.LogError("Invalid expiry date format: %s, error: %v", req.ExpiryDate, err)
-		err := errors.New("invalid expiry date format")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-	//Check if the expiry date is in the future
-	if expiryDate.Before(time.Now()) {
-		err := errors.New("expiry date must be in the future")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	// Create a numeric value from the price string
-	var priceNumeric pgtype.Numeric
-	util.LogInfo("Attempting to parse price: %s", req.Price)
-	err = priceNumeric.Scan(req.Price)
-	if err != nil {
-		util.LogError("Invalid price format: %s, error: %v", req.Price, err)
-		err := errors.New("invalid price format")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	arg := db.CreateMedicineParams{
-		Name:        req.Name,
-		Description: req.Description,
-		ExpiryDate: pgtype.Date{
-			Time:  expiryDate,
-			Valid: true,
-		},
-		Quantity:       req.Quantity,
-		Price:          priceNumeric,
-		Discount:       req.Discount,
-		SellerUsername: req.Seller,
-	}
-
-	util.LogInfo("Creating medicine with params: %+v", arg)
-	medicine, err = server.store.CreateMedicine(c, arg)
-	if err != nil {
-		util.LogError("Failed to create medicine in database: %v", err)
-		err := errors.New("failed to create medicine")
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	c.JSON(http.StatusOK, medicine)
-}
-
-func (server *Server) UpdateMedicine(c *gin.Context) {
-	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
-
-	var req UpdateMedicineRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		err = errors.New("failed to bind JSON")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	if req.Quantity < 0 {
-		err := errors.New("quantity cannot be negative")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	if req.Discount < 0 {
-		err := errors.New("discount cannot be negative")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	if req.Price == "" {
-		err := errors.New("price cannot be empty")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	if req.Seller == "" || req.Seller != authPayload.Username {
-		err := errors.New("invalid seller username")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	// Create a numeric value from the price string
-	var priceNumeric pgtype.Numeric
-	err := priceNumeric.Scan(req.Price)
-	if err != nil {
-		err := errors.New("invalid price format")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	arg := db.UpdateMedicineParams{
-		Name: pgtype.Text{
-			String: req.Name,
-			Valid:  true,
-		},
-		Description: pgtype.Text{
-			String: req.Description,
-			Valid:  true,
-		},
-		ExpiryDate: pgtype.Date{
-			Time:  time.Now(),
-			Valid: true,
-		},
-		Quantity: pgtype.Int4{
-			Int32: req.Quantity,
-			Valid: true,
-		},
-		Price: priceNumeric,
-		Discount: pgtype.Int4{
-			Int32: req.Discount,
-			Valid: true,
-		},
-	}
-
-	medicine, err := server.store.UpdateMedicine(c, arg)
-	if err != nil {
-		err := errors.New("failed to update medicine")
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-	c.JSON(http.StatusOK, medicine)
-}
-
-func (server *Server) DeleteMedicine(c *gin.Context) {
-	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
-
-	var req DeleteMedicineRequest
-	if err := c.ShouldBindUri(&req); err != nil {
-		err = errors.New("failed to bind JSON")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	medicine, err := server.store.GetMedicine(c, req.ID)
-	if err != nil {
-		err := errors.New("medicine not found")
-		c.JSON(http.StatusNotFound, errorResponse(err))
-		return
-	}
-
-	if medicine.SellerUsername != authPayload.Username || authPayload.Role != util.Seller {
-		err := errors.New("invalid seller username")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	_, err = server.store.DeleteMedicine(c, req.ID)
-	if err != nil {
-		if err == db.ErrRecordNotFound {
-			err := errors.New("medicine not found")
-			c.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		err := errors.New("failed to delete medicine")
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-	c.JSON(http.StatusOK, medicine)
-}
-
-func (server *Server) GetMedicine(c *gin.Context) {
-	var req MedicineIDRequest
-	if err := c.ShouldBindUri(&req); err != nil {
-		err = errors.New("failed to bind URI")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	medicine, err := server.store.GetMedicine(c, req.ID)
-	if err != nil {
-		if err == db.ErrRecordNotFound {
-			err := errors.New("medicine not found")
-			c.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	c.JSON(http.StatusOK, medicine)
-}
-
-func (server *Server) ListSellerMedicinesByExpiry(c *gin.Context) {
-	var req ListSellerMedicinesRequest
-	if err := c.ShouldBindUri(&req); err != nil {
-		err = errors.New("failed to bind URI")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	if err := c.ShouldBindQuery(&req); err != nil {
-		err = errors.New("failed to bind query parameters")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	// Validate the seller exists
-	_, err := server.store.GetSellerByName(c, req.SellerUsername)
-	if err != nil {
-		if err == db.ErrRecordNotFound {
-			err := errors.New("seller not found")
-			c.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	arg := db.ListSellerMedicinesByExpiryParams{
-		SellerUsername: req.SellerUsername,
-		Limit:          req.Limit,
-		Offset:         req.Offset,
-	}
-
-	medicines, err := server.store.ListSellerMedicinesByExpiry(c, arg)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	c.JSON(http.StatusOK, medicines)
-}
-
-func (server *Server) SearchMedicinesByNameSortedByPrice(c *gin.Context) {
-	var req SearchMedicinesRequest
-	if err := c.ShouldBindQuery(&req); err != nil {
-		err = errors.New("failed to bind query parameters")
-		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	arg := db.SearchMedicinesByNameSortedByPriceParams{
-		Name: pgtype.Text{
-			String: req.Name,
-			Valid:  true,
-		},
-		Limit:  req.Limit,
-		Offset: req.Offset,
-	}
-
-	medicines, err := server.store.SearchMedicinesByNameSortedByPrice(c, arg)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	c.JSON(http.StatusOK, medicines)
-}
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type CreateMedicineRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	ExpiryDate  string `json:"expiry_date" binding:"required"`
+	Quantity    int32  `json:"quantity" binding:"required"`
+	Price       string `json:"price" binding:"required"`
+	Discount    int32  `json:"discount" binding:"required"`
+	Seller      string `json:"seller" binding:"required"`
+	BatchNumber string `json:"batch_number"`
+	// Composition is optional but required for the medicine to show up as
+	// a generic substitute for other medicines.
+	ActiveIngredients    string `json:"active_ingredients"`
+	Strength             string `json:"strength"`
+	DosageForm           string `json:"dosage_form"`
+	PrescriptionRequired bool   `json:"prescription_required"`
+}
+
+type UpdateMedicineRequest struct {
+	Name        string `json:"name" binding:"omitempty"`
+	Description string `json:"description" binding:"omitempty"`
+	ExpiryDate  string `json:"expiry_date" binding:"omitempty"`
+	Price       string `json:"price" binding:"omitempty"`
+	Discount    int32  `json:"discount" binding:"omitempty"`
+	Seller      string `json:"seller" binding:"omitempty"`
+	BatchNumber string `json:"batch_number" binding:"omitempty"`
+
+	ActiveIngredients    string `json:"active_ingredients" binding:"omitempty"`
+	Strength             string `json:"strength" binding:"omitempty"`
+	DosageForm           string `json:"dosage_form" binding:"omitempty"`
+	PrescriptionRequired *bool  `json:"prescription_required" binding:"omitempty"`
+}
+
+type MedicineResponse struct {
+	ID             int32  `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	ExpiryDate     string `json:"expiry_date"`
+	Quantity       int32  `json:"quantity"`
+	Price          string `json:"price"`
+	Discount       int32  `json:"discount"`
+	SellerUsername string `json:"seller_username"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type DeleteMedicineRequest struct {
+	ID int32 `uri:"id" binding:"required"`
+}
+
+type MedicineIDRequest struct {
+	ID int32 `uri:"id" binding:"required"`
+}
+
+type ListSellerMedicinesRequest struct {
+	SellerUsername string `uri:"username" binding:"required"`
+	Limit          int32  `form:"limit,default=10"`
+	Offset         int32  `form:"offset,default=0"`
+}
+
+type ListMedicineSubstitutesRequest struct {
+	ID     int32 `uri:"id" binding:"required"`
+	Limit  int32 `form:"limit,default=10"`
+	Offset int32 `form:"offset,default=0"`
+}
+
+func (server *Server) CreateMedicine(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	var req CreateMedicineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.LogError("Failed to bind JSON: %v", err)
+		err = errors.New("failed to bind JSON")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Log the request data for debugging
+	util.LogInfo("Creating medicine with data: %+v", req)
+	util.LogInfo("Auth payload: %+v", authPayload)
+
+	arg, err := newCreateMedicineParams(req)
+	if err != nil {
+		util.LogError("Invalid medicine data: %v", err)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Seller == "" || req.Seller != authPayload.Store {
+		util.LogError("Invalid seller: req.Seller=%s, authPayload.Store=%s", req.Seller, authPayload.Store)
+		err := errors.New("invalid seller username")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Check if the seller exists
+	seller, err := server.store.GetSellerByName(c, req.Seller)
+	if err != nil {
+		util.LogError("Failed to get seller: %v", err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("seller not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if seller.Username != req.Seller {
+		util.LogError("Seller username mismatch: seller.Username=%s, req.Seller=%s", seller.Username, req.Seller)
+		err := errors.New("invalid seller username")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Check if the medicine already exists
+	medicine, err := server.store.GetMedicineByName(c, req.Name)
+	if err != nil {
+		if err != db.ErrRecordNotFound {
+			util.LogError("Failed to check existing medicine: %v", err)
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	if medicine.Name == req.Name && medicine.SellerUsername == req.Seller &&
+		medicine.Status == util.ActiveMedicine &&
+		medicine.ExpiryDate.Time.Format("2006-01-02") == req.ExpiryDate {
+		result, err := server.store.StockMovementTx(c, db.StockMovementTxParams{
+			MedicineID:     medicine.ID,
+			MovementType:   util.ReceiptMovement,
+			QuantityChange: req.Quantity,
+			CreatedBy:      authPayload.Username,
+		})
+		if err != nil {
+			util.LogError("Failed to update existing medicine: %v", err)
+			err := errors.New("failed to update medicine")
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusOK, result.Medicine)
+		return
+	}
+
+	util.LogInfo("Creating medicine with params: %+v", arg)
+	medicine, err = server.store.CreateMedicineTx(c, db.CreateMedicineTxParams{
+		CreateMedicineParams: arg,
+		MovementType:         util.ReceiptMovement,
+		CreatedBy:            authPayload.Username,
+	})
+	if err != nil {
+		util.LogError("Failed to create medicine in database: %v", err)
+		err := errors.New("failed to create medicine")
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, medicine)
+}
+
+// newCreateMedicineParams applies the validation rules for a new medicine
+// and builds the insert arguments. Bulk import runs every row through it so
+// that imported stock obeys the same rules as stock added one at a time.
+func newCreateMedicineParams(req CreateMedicineRequest) (db.CreateMedicineParams, error) {
+	var arg db.CreateMedicineParams
+
+	if req.Quantity < 0 {
+		return arg, errors.New("quantity cannot be negative")
+	}
+
+	if req.Discount < 0 || req.Discount > 100 {
+		return arg, errors.New("discount must be between 0 and 100")
+	}
+
+	if req.Price == "" {
+		return arg, errors.New("price cannot be empty")
+	}
+
+	if req.DosageForm != "" && !util.IsValidDosageForm(strings.ToLower(req.DosageForm)) {
+		return arg, errors.New("invalid dosage form")
+	}
+
+	// Convert the expiry date to the correct format
+	expiryDate, err := time.Parse("2006-01-02", req.ExpiryDate)
+	if err != nil {
+		return arg, errors.New("invalid expiry date format")
+	}
+	//Check if the expiry date is in the future
+	if expiryDate.Before(time.Now()) {
+		return arg, errors.New("expiry date must be in the future")
+	}
+
+	// Create a numeric value from the price string
+	var priceNumeric pgtype.Numeric
+	if err := priceNumeric.Scan(req.Price); err != nil {
+		return arg, errors.New("invalid price format")
+	}
+
+	arg = db.CreateMedicineParams{
+		Name:        req.Name,
+		Description: req.Description,
+		ExpiryDate: pgtype.Date{
+			Time:  expiryDate,
+			Valid: true,
+		},
+		Quantity:             req.Quantity,
+		Price:                priceNumeric,
+		Discount:             req.Discount,
+		SellerUsername:       req.Seller,
+		ActiveIngredients:    util.NormalizeIngredients(req.ActiveIngredients),
+		Strength:             util.NormalizeStrength(req.Strength),
+		DosageForm:           strings.ToLower(strings.TrimSpace(req.DosageForm)),
+		PrescriptionRequired: req.PrescriptionRequired,
+		BatchNumber:          strings.TrimSpace(req.BatchNumber),
+	}
+
+	return arg, nil
+}
+
+func (server *Server) UpdateMedicine(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	var req UpdateMedicineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = errors.New("failed to bind JSON")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Discount < 0 || req.Discount > 100 {
+		err := errors.New("discount must be between 0 and 100")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Price == "" {
+		err := errors.New("price cannot be empty")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Seller == "" || req.Seller != authPayload.Store {
+		err := errors.New("invalid seller username")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.DosageForm != "" && !util.IsValidDosageForm(strings.ToLower(req.DosageForm)) {
+		err := errors.New("invalid dosage form")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Create a numeric value from the price string
+	var priceNumeric pgtype.Numeric
+	err := priceNumeric.Scan(req.Price)
+	if err != nil {
+		err := errors.New("invalid price format")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateMedicineParams{
+		Name: pgtype.Text{
+			String: req.Name,
+			Valid:  true,
+		},
+		Description: pgtype.Text{
+			String: req.Description,
+			Valid:  true,
+		},
+		ExpiryDate: pgtype.Date{
+			Time:  time.Now(),
+			Valid: true,
+		},
+		Price: priceNumeric,
+		Discount: pgtype.Int4{
+			Int32: req.Discount,
+			Valid: true,
+		},
+		ActiveIngredients: pgtype.Text{
+			String: util.NormalizeIngredients(req.ActiveIngredients),
+			Valid:  req.ActiveIngredients != "",
+		},
+		Strength: pgtype.Text{
+			String: util.NormalizeStrength(req.Strength),
+			Valid:  req.Strength != "",
+		},
+		DosageForm: pgtype.Text{
+			String: strings.ToLower(strings.TrimSpace(req.DosageForm)),
+			Valid:  req.DosageForm != "",
+		},
+		BatchNumber: pgtype.Text{
+			String: strings.TrimSpace(req.BatchNumber),
+			Valid:  req.BatchNumber != "",
+		},
+	}
+
+	if req.PrescriptionRequired != nil {
+		arg.PrescriptionRequired = pgtype.Bool{
+			Bool:  *req.PrescriptionRequired,
+			Valid: true,
+		}
+	}
+
+	medicine, err := server.store.UpdateMedicine(c, arg)
+	if err != nil {
+		err := errors.New("failed to update medicine")
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, medicine)
+}
+
+func (server *Server) DeleteMedicine(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	var req DeleteMedicineRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		err = errors.New("failed to bind JSON")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	medicine, err := server.store.GetMedicine(c, req.ID)
+	if err != nil {
+		err := errors.New("medicine not found")
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	if medicine.SellerUsername != authPayload.Store {
+		err := errors.New("invalid seller username")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Medicines are withdrawn rather than deleted so their history is kept
+	medicine, err = server.store.WithdrawMedicineTx(c, db.WithdrawMedicineTxParams{
+		MedicineID: medicine.ID,
+		CreatedBy:  authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrMedicineNotActive) {
+			err := errors.New("only medicines on sale can be withdrawn")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("medicine not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		err := errors.New("failed to withdraw medicine")
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, medicine)
+}
+
+func (server *Server) GetMedicine(c *gin.Context) {
+	var req MedicineIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		err = errors.New("failed to bind URI")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	medicine, err := server.store.GetMedicine(c, req.ID)
+	if err != nil {
+		if err == db.ErrRecordNotFound {
+			err := errors.New("medicine not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, medicine)
+}
+
+func (server *Server) ListSellerMedicinesByExpiry(c *gin.Context) {
+	var req ListSellerMedicinesRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		err = errors.New("failed to bind URI")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		err = errors.New("failed to bind query parameters")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Validate the seller exists
+	_, err := server.store.GetSellerByName(c, req.SellerUsername)
+	if err != nil {
+		if err == db.ErrRecordNotFound {
+			err := errors.New("seller not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.ListSellerMedicinesByExpiryParams{
+		SellerUsername: req.SellerUsername,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	}
+
+	medicines, err := server.store.ListSellerMedicinesByExpiry(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, medicines)
+}
+
+// ListMedicineSubstitutes returns cheaper or equivalent medicines from any
+// seller that share the active ingredients, strength and dosage form of the
+// given medicine, sorted by the price after discount.
+func (server *Server) ListMedicineSubstitutes(c *gin.Context) {
+	var req ListMedicineSubstitutesRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		err = errors.New("failed to bind URI")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		err = errors.New("failed to bind query parameters")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	medicine, err := server.store.GetMedicine(c, req.ID)
+	if err != nil {
+		if err == db.ErrRecordNotFound {
+			err := errors.New("medicine not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if medicine.ActiveIngredients == "" {
+		err := errors.New("medicine has no active ingredients recorded")
+		c.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+
+	arg := db.ListMedicineSubstitutesParams{
+		ID:     req.ID,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+
+	substitutes, err := server.store.ListMedicineSubstitutes(c, arg)
+	if err != nil {
+		util.LogError("Failed to list substitutes for medicine %d: %v", req.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"medicine":    medicine,
+		"substitutes": substitutes,
+	})
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type DisposeMedicineRequest struct {
+	Method string `json:"method" binding:"required"`
+	// DisposedAt is when the stock was destroyed (RFC 3339); defaults to now
+	DisposedAt        string `json:"disposed_at"`
+	DisposedBy        string `json:"disposed_by"`
+	CertificateNumber string `json:"certificate_number"`
+	Notes             string `json:"notes"`
+}
+
+type ListSellerQuarantineRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+// ListQuarantinedMedicines lists the logged-in seller's quarantined stock
+// that is still waiting to be disposed of.
+func (server *Server) ListQuarantinedMedicines(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListSellerQuarantineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	medicines, err := server.store.ListQuarantinedMedicines(c, db.ListQuarantinedMedicinesParams{
+		SellerUsername: authPayload.Store,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, medicines)
+}
+
+// DisposeMedicine records how and when a quarantined medicine's stock was
+// destroyed, which closes the medicine off as disposed.
+func (server *Server) DisposeMedicine(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	medicine, ok := server.getSellerMedicine(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	var req DisposeMedicineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidDisposalMethod(req.Method) {
+		err := errors.New("invalid disposal method")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	disposedAt := time.Now()
+	if req.DisposedAt != "" {
+		var err error
+		disposedAt, err = time.Parse(time.RFC3339, req.DisposedAt)
+		if err != nil {
+			err := errors.New("invalid disposed_at format, expected RFC 3339")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if disposedAt.After(time.Now()) {
+			err := errors.New("disposed_at can't be in the future")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if req.Method == util.OtherDisposal && strings.TrimSpace(req.Notes) == "" {
+		err := errors.New("notes are required when the disposal method is other")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.DisposeMedicineTx(c, db.CreateMedicineDisposalParams{
+		MedicineID: medicine.ID,
+		Method:     req.Method,
+		DisposedAt: pgtype.Timestamp{
+			Time:  disposedAt,
+			Valid: true,
+		},
+		DisposedBy:        strings.TrimSpace(req.DisposedBy),
+		CertificateNumber: strings.TrimSpace(req.CertificateNumber),
+		Notes:             strings.TrimSpace(req.Notes),
+		RecordedBy:        authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrMedicineNotQuarantined) {
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to record disposal of medicine %d: %v", medicine.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Medicine %d disposed of by %s (%s)", medicine.ID, authPayload.Username, req.Method)
+	c.JSON(http.StatusOK, result)
+}
+
+func (server *Server) ListMedicineDisposals(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListSellerQuarantineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	disposals, err := server.store.ListMedicineDisposals(c, db.ListMedicineDisposalsParams{
+		SellerUsername: authPayload.Store,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, disposals)
+}
@@ -0,0 +1,554 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+const (
+	maxImportFileSize = 10 << 20
+	maxImportRows     = 5000
+
+	importStatusProcessing = "processing"
+	importStatusCompleted  = "completed"
+)
+
+// Column order used for exports; imports match columns by header name so
+// an exported file can be edited and imported again.
+var medicineSheetColumns = []string{
+	"name", "description", "batch_number", "expiry_date", "quantity", "price", "discount",
+	"active_ingredients", "strength", "dosage_form", "prescription_required",
+}
+
+var requiredMedicineSheetColumns = []string{"name", "description", "expiry_date", "quantity", "price"}
+
+var spreadsheetContentTypes = map[string]string{
+	util.CSVFormat:  "text/csv",
+	util.XLSXFormat: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type ImportMedicinesRequest struct {
+	File *multipart.FileHeader `form:"file" binding:"required"`
+	// Mode is "create" (every row is new stock) or "upsert" (rows matching
+	// an existing name and batch number update that medicine)
+	Mode string `form:"mode" binding:"omitempty,oneof=create upsert"`
+}
+
+type MedicineImportJobIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ListMedicineImportJobsRequest struct {
+	Limit  int32 `form:"limit,default=10" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+type SpreadsheetFormatRequest struct {
+	Format string `form:"format,default=csv" binding:"oneof=csv xlsx"`
+}
+
+type medicineImportRowError struct {
+	Row         int    `json:"row"`
+	Name        string `json:"name"`
+	BatchNumber string `json:"batch_number"`
+	Error       string `json:"error"`
+}
+
+type medicineImportJobResponse struct {
+	db.MedicineImportJob
+	RowErrors json.RawMessage `json:"row_errors"`
+}
+
+func newMedicineImportJobResponse(job db.MedicineImportJob) medicineImportJobResponse {
+	rowErrors := json.RawMessage(job.RowErrors)
+	if len(rowErrors) == 0 {
+		rowErrors = json.RawMessage("[]")
+	}
+	return medicineImportJobResponse{
+		MedicineImportJob: job,
+		RowErrors:         rowErrors,
+	}
+}
+
+// ImportMedicines accepts a CSV or XLSX file of medicines from the logged-in
+// seller. The header is checked straight away; the rows are imported in the
+// background and the job can be polled with GetMedicineImportJob.
+func (server *Server) ImportMedicines(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	var req ImportMedicinesRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	format := util.SpreadsheetFormatFromFileName(req.File.Filename)
+	if format == "" {
+		err := errors.New("file must be a .csv or .xlsx spreadsheet")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.File.Size > maxImportFileSize {
+		err := fmt.Errorf("file is larger than %d MB", maxImportFileSize>>20)
+		c.JSON(http.StatusRequestEntityTooLarge, errorResponse(err))
+		return
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	rows, err := util.ReadSpreadsheet(file, format)
+	if err != nil {
+		util.LogWarning("Failed to read medicine import file %s: %v", req.File.Filename, err)
+		err := errors.New("could not read spreadsheet")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if len(rows) < 2 {
+		err := errors.New("spreadsheet has no data rows")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if len(rows)-1 > maxImportRows {
+		err := fmt.Errorf("spreadsheet has more than %d rows", maxImportRows)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	columns, err := newMedicineSheetColumns(rows[0])
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	job, err := server.store.CreateMedicineImportJob(c, db.CreateMedicineImportJobParams{
+		SellerUsername: authPayload.Store,
+		FileName:       req.File.Filename,
+		FileFormat:     format,
+		Upsert:         req.Mode == "upsert",
+	})
+	if err != nil {
+		util.LogError("Failed to create medicine import job: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Medicine import job %d queued for seller %s with %d rows", job.ID, job.SellerUsername, len(rows)-1)
+	go server.runMedicineImport(job, columns, rows[1:])
+
+	c.JSON(http.StatusAccepted, newMedicineImportJobResponse(job))
+}
+
+// newMedicineSheetColumns maps lowercased header names to column indexes
+// and makes sure every required column is present.
+func newMedicineSheetColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var missing []string
+	for _, name := range requiredMedicineSheetColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	return columns, nil
+}
+
+// newMedicineRowRequest turns one spreadsheet row into the same request the
+// JSON create handler binds, so both go through newCreateMedicineParams.
+func newMedicineRowRequest(columns map[string]int, record []string, seller string) (CreateMedicineRequest, error) {
+	cell := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	req := CreateMedicineRequest{
+		Name:              cell("name"),
+		Description:       cell("description"),
+		ExpiryDate:        cell("expiry_date"),
+		Price:             cell("price"),
+		Seller:            seller,
+		BatchNumber:       cell("batch_number"),
+		ActiveIngredients: cell("active_ingredients"),
+		Strength:          cell("strength"),
+		DosageForm:        cell("dosage_form"),
+	}
+
+	for _, name := range requiredMedicineSheetColumns {
+		if cell(name) == "" {
+			return req, fmt.Errorf("%s is required", name)
+		}
+	}
+
+	quantity, err := strconv.ParseInt(cell("quantity"), 10, 32)
+	if err != nil {
+		return req, errors.New("invalid quantity")
+	}
+	req.Quantity = int32(quantity)
+
+	if value := cell("discount"); value != "" {
+		discount, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return req, errors.New("invalid discount")
+		}
+		req.Discount = int32(discount)
+	}
+
+	if value := cell("prescription_required"); value != "" {
+		required, err := strconv.ParseBool(strings.ToLower(value))
+		if err != nil {
+			return req, errors.New("invalid prescription_required, expected true or false")
+		}
+		req.PrescriptionRequired = required
+	}
+
+	return req, nil
+}
+
+// runMedicineImport imports the rows of a job one at a time. A bad row is
+// recorded against the job and does not stop the rest of the file.
+func (server *Server) runMedicineImport(job db.MedicineImportJob, columns map[string]int, records [][]string) {
+	ctx := context.Background()
+
+	if err := server.store.UpdateMedicineImportJobStatus(ctx, db.UpdateMedicineImportJobStatusParams{
+		ID:     job.ID,
+		Status: importStatusProcessing,
+	}); err != nil {
+		util.LogError("Failed to start medicine import job %d: %v", job.ID, err)
+	}
+
+	result := db.CompleteMedicineImportJobParams{
+		ID:     job.ID,
+		Status: importStatusCompleted,
+	}
+	rowErrors := []medicineImportRowError{}
+
+	for i, record := range records {
+		// Row numbers match the spreadsheet, where row 1 is the header
+		rowNumber := i + 2
+
+		if isBlankRecord(record) {
+			continue
+		}
+		result.TotalRows++
+
+		created, err := server.importMedicineRow(ctx, job, columns, record)
+		if err != nil {
+			result.FailedRows++
+			req, _ := newMedicineRowRequest(columns, record, job.SellerUsername)
+			rowErrors = append(rowErrors, medicineImportRowError{
+				Row:         rowNumber,
+				Name:        req.Name,
+				BatchNumber: req.BatchNumber,
+				Error:       err.Error(),
+			})
+			continue
+		}
+
+		if created {
+			result.CreatedRows++
+		} else {
+			result.UpdatedRows++
+		}
+	}
+
+	result.RowErrors, _ = json.Marshal(rowErrors)
+
+	if _, err := server.store.CompleteMedicineImportJob(ctx, result); err != nil {
+		util.LogError("Failed to complete medicine import job %d: %v", job.ID, err)
+		return
+	}
+
+	util.LogInfo("Medicine import job %d finished: %d created, %d updated, %d failed",
+		job.ID, result.CreatedRows, result.UpdatedRows, result.FailedRows)
+}
+
+// importMedicineRow validates and stores a single row, reporting whether a
+// new medicine was created (as opposed to an existing batch being updated).
+func (server *Server) importMedicineRow(ctx context.Context, job db.MedicineImportJob, columns map[string]int, record []string) (bool, error) {
+	req, err := newMedicineRowRequest(columns, record, job.SellerUsername)
+	if err != nil {
+		return false, err
+	}
+
+	arg, err := newCreateMedicineParams(req)
+	if err != nil {
+		return false, err
+	}
+
+	if job.Upsert {
+		existing, err := server.store.GetSellerMedicineByNameAndBatch(ctx, db.GetSellerMedicineByNameAndBatchParams{
+			SellerUsername: arg.SellerUsername,
+			Name:           arg.Name,
+			BatchNumber:    arg.BatchNumber,
+		})
+		if err == nil {
+			_, err = server.store.UpdateMedicine(ctx, newUpsertMedicineParams(existing.ID, arg))
+			if err != nil {
+				util.LogError("Failed to update medicine %d during import: %v", existing.ID, err)
+				return false, errors.New("failed to update medicine")
+			}
+
+			_, err = server.store.StockMovementTx(ctx, db.StockMovementTxParams{
+				MedicineID:     existing.ID,
+				MovementType:   util.AdjustmentMovement,
+				TargetQuantity: pgtype.Int4{Int32: arg.Quantity, Valid: true},
+				Reason:         "bulk import",
+				Reference:      importReference(job),
+				CreatedBy:      job.SellerUsername,
+			})
+			if err != nil {
+				util.LogError("Failed to update stock of medicine %d during import: %v", existing.ID, err)
+				return false, errors.New("failed to update stock")
+			}
+			return false, nil
+		}
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			return false, errors.New("failed to look up existing medicine")
+		}
+	}
+
+	_, err = server.store.CreateMedicineTx(ctx, db.CreateMedicineTxParams{
+		CreateMedicineParams: arg,
+		MovementType:         util.ReceiptMovement,
+		Reference:            importReference(job),
+		CreatedBy:            job.SellerUsername,
+	})
+	if err != nil {
+		util.LogError("Failed to create medicine during import: %v", err)
+		return false, errors.New("failed to create medicine")
+	}
+	return true, nil
+}
+
+// importReference ties ledger entries back to the import job that made them
+func importReference(job db.MedicineImportJob) string {
+	return fmt.Sprintf("import:%d", job.ID)
+}
+
+// newUpsertMedicineParams overwrites the details of an existing medicine
+// with the values from an import row; stock is set through the ledger.
+func newUpsertMedicineParams(id int32, arg db.CreateMedicineParams) db.UpdateMedicineParams {
+	return db.UpdateMedicineParams{
+		ID:                   id,
+		Name:                 pgtype.Text{String: arg.Name, Valid: true},
+		Description:          pgtype.Text{String: arg.Description, Valid: true},
+		ExpiryDate:           arg.ExpiryDate,
+		Price:                arg.Price,
+		Discount:             pgtype.Int4{Int32: arg.Discount, Valid: true},
+		ActiveIngredients:    pgtype.Text{String: arg.ActiveIngredients, Valid: true},
+		Strength:             pgtype.Text{String: arg.Strength, Valid: true},
+		DosageForm:           pgtype.Text{String: arg.DosageForm, Valid: true},
+		PrescriptionRequired: pgtype.Bool{Bool: arg.PrescriptionRequired, Valid: true},
+		BatchNumber:          pgtype.Text{String: arg.BatchNumber, Valid: true},
+	}
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// getSellerImportJob loads an import job and checks that it belongs to the
+// logged-in user's store, writing the error response itself when it doesn't.
+func (server *Server) getSellerImportJob(c *gin.Context) (db.MedicineImportJob, bool) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return db.MedicineImportJob{}, false
+	}
+
+	var req MedicineImportJobIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.MedicineImportJob{}, false
+	}
+
+	job, err := server.store.GetMedicineImportJob(c, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return job, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return job, false
+	}
+
+	if job.SellerUsername != authPayload.Store {
+		err := errors.New("import job doesn't belong to the authenticated seller")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return job, false
+	}
+
+	return job, true
+}
+
+func (server *Server) GetMedicineImportJob(c *gin.Context) {
+	job, ok := server.getSellerImportJob(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, newMedicineImportJobResponse(job))
+}
+
+func (server *Server) ListMedicineImportJobs(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListMedicineImportJobsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	jobs, err := server.store.ListMedicineImportJobs(c, db.ListMedicineImportJobsParams{
+		SellerUsername: authPayload.Store,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]medicineImportJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		rsp = append(rsp, newMedicineImportJobResponse(job))
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+// DownloadMedicineImportErrors returns the failed rows of an import job as a
+// spreadsheet the seller can fix and upload again.
+func (server *Server) DownloadMedicineImportErrors(c *gin.Context) {
+	var req SpreadsheetFormatRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	job, ok := server.getSellerImportJob(c)
+	if !ok {
+		return
+	}
+
+	if job.Status != importStatusCompleted {
+		err := errors.New("import job has not finished yet")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	var rowErrors []medicineImportRowError
+	if err := json.Unmarshal(job.RowErrors, &rowErrors); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows := [][]string{{"row", "name", "batch_number", "error"}}
+	for _, rowError := range rowErrors {
+		rows = append(rows, []string{
+			strconv.Itoa(rowError.Row),
+			rowError.Name,
+			rowError.BatchNumber,
+			rowError.Error,
+		})
+	}
+
+	fileName := fmt.Sprintf("medicine-import-%d-errors.%s", job.ID, req.Format)
+	server.writeSpreadsheet(c, fileName, req.Format, rows)
+}
+
+// ExportMedicines downloads the logged-in seller's full inventory in the
+// same layout that ImportMedicines accepts.
+func (server *Server) ExportMedicines(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req SpreadsheetFormatRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	medicines, err := server.store.ListAllSellerMedicines(c, authPayload.Store)
+	if err != nil {
+		util.LogError("Failed to list medicines for export: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows := make([][]string, 0, len(medicines)+1)
+	rows = append(rows, medicineSheetColumns)
+	for _, medicine := range medicines {
+		price, _ := medicine.Price.Value()
+		rows = append(rows, []string{
+			medicine.Name,
+			medicine.Description,
+			medicine.BatchNumber,
+			medicine.ExpiryDate.Time.Format("2006-01-02"),
+			strconv.Itoa(int(medicine.Quantity)),
+			fmt.Sprint(price),
+			strconv.Itoa(int(medicine.Discount)),
+			medicine.ActiveIngredients,
+			medicine.Strength,
+			medicine.DosageForm,
+			strconv.FormatBool(medicine.PrescriptionRequired),
+		})
+	}
+
+	fileName := fmt.Sprintf("%s-medicines-%s.%s", authPayload.Store, time.Now().Format("20060102"), req.Format)
+	server.writeSpreadsheet(c, fileName, req.Format, rows)
+}
+
+func (server *Server) writeSpreadsheet(c *gin.Context, fileName, format string, rows [][]string) {
+	var buf bytes.Buffer
+	if err := util.WriteSpreadsheet(&buf, format, rows); err != nil {
+		util.LogError("Failed to write %s spreadsheet: %v", format, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, spreadsheetContentTypes[format], buf.Bytes())
+}
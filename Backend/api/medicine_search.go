@@ -0,0 +1,177 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// Cheapest first was the only ordering before sort options existed
+const defaultMedicineSort = "price_asc"
+
+// SearchMedicinesRequest holds the search text and filters for the medicine
+// search. Name is the original search parameter and is kept as an alias of
+// Q so existing clients keep working.
+type SearchMedicinesRequest struct {
+	Name                 string `form:"name"`
+	Q                    string `form:"q"`
+	MinPrice             string `form:"min_price"`
+	MaxPrice             string `form:"max_price"`
+	SellerType           string `form:"seller_type"`
+	InStock              bool   `form:"in_stock"`
+	MinDaysToExpiry      *int32 `form:"min_days_to_expiry" binding:"omitempty,min=0"`
+	PrescriptionRequired *bool  `form:"prescription_required"`
+	Sort                 string `form:"sort" binding:"omitempty,oneof=relevance price_asc price_desc expiry newest"`
+	Limit                int32  `form:"limit,default=10" binding:"min=1,max=100"`
+	Offset               int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+type medicineFacetsResponse struct {
+	Facets map[string]map[string]int64 `json:"facets"`
+	Total  int64                       `json:"total"`
+}
+
+// SearchMedicines runs a full-text and fuzzy search over medicine names,
+// descriptions and active ingredients. The response is a JSON array of
+// medicines, as it has always been; facet counts are served separately by
+// SearchMedicineFacets.
+func (server *Server) SearchMedicines(c *gin.Context) {
+	var req SearchMedicinesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		err = errors.New("failed to bind query parameters")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	filters, err := newMedicineSearchFilters(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	sortBy := req.Sort
+	if sortBy == "" {
+		sortBy = defaultMedicineSort
+	}
+
+	arg := db.SearchMedicinesParams{
+		Query:                filters.Query,
+		MinPrice:             filters.MinPrice,
+		MaxPrice:             filters.MaxPrice,
+		SellerType:           filters.SellerType,
+		InStockOnly:          filters.InStockOnly,
+		MinDaysToExpiry:      filters.MinDaysToExpiry,
+		PrescriptionRequired: filters.PrescriptionRequired,
+		SortBy:               sortBy,
+		Limit:                req.Limit,
+		Offset:               req.Offset,
+	}
+
+	medicines, err := server.store.SearchMedicines(c, arg)
+	if err != nil {
+		util.LogError("Failed to search medicines: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, medicines)
+}
+
+// SearchMedicineFacets returns facet counts (seller type, prescription,
+// availability, price range and days to expiry) for the same search text
+// and filters accepted by SearchMedicines.
+func (server *Server) SearchMedicineFacets(c *gin.Context) {
+	var req SearchMedicinesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		err = errors.New("failed to bind query parameters")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	filters, err := newMedicineSearchFilters(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.SearchMedicineFacets(c, filters)
+	if err != nil {
+		util.LogError("Failed to compute medicine search facets: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := medicineFacetsResponse{
+		Facets: make(map[string]map[string]int64),
+	}
+	for _, row := range rows {
+		if rsp.Facets[row.Facet] == nil {
+			rsp.Facets[row.Facet] = make(map[string]int64)
+		}
+		rsp.Facets[row.Facet][row.Value] = row.Count
+
+		// Every match falls in exactly one availability bucket
+		if row.Facet == "availability" {
+			rsp.Total += row.Count
+		}
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+// newMedicineSearchFilters validates the request and converts it into the
+// filter arguments shared by the search and facet queries.
+func newMedicineSearchFilters(req SearchMedicinesRequest) (db.SearchMedicineFacetsParams, error) {
+	query := strings.TrimSpace(req.Q)
+	if query == "" {
+		query = strings.TrimSpace(req.Name)
+	}
+
+	arg := db.SearchMedicineFacetsParams{
+		Query:       query,
+		InStockOnly: req.InStock,
+	}
+
+	if req.MinPrice != "" {
+		if err := arg.MinPrice.Scan(req.MinPrice); err != nil {
+			return arg, errors.New("invalid min_price format")
+		}
+	}
+
+	if req.MaxPrice != "" {
+		if err := arg.MaxPrice.Scan(req.MaxPrice); err != nil {
+			return arg, errors.New("invalid max_price format")
+		}
+	}
+
+	if req.SellerType != "" {
+		if !util.IsValidSellerType(req.SellerType) {
+			return arg, errors.New("invalid seller type provided")
+		}
+		arg.SellerType = pgtype.Text{
+			String: req.SellerType,
+			Valid:  true,
+		}
+	}
+
+	if req.MinDaysToExpiry != nil {
+		arg.MinDaysToExpiry = pgtype.Int4{
+			Int32: *req.MinDaysToExpiry,
+			Valid: true,
+		}
+	}
+
+	if req.PrescriptionRequired != nil {
+		arg.PrescriptionRequired = pgtype.Bool{
+			Bool:  *req.PrescriptionRequired,
+			Valid: true,
+		}
+	}
+
+	return arg, nil
+}
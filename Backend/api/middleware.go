@@ -1,52 +1,95 @@
-package api
-
-import (
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
-
-	"github.com/gin-gonic/gin"
-	"github.com/pawaspy/MediBridge/token"
-)
-
-const (
-	authorizatonHeaderKey   = "authorization"
-	authorizationTypeBearer = "bearer"
-	authorizationPayloadKey = "authorization_key"
-)
-
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		authorizatonHeader := ctx.GetHeader(authorizatonHeaderKey)
-		if len(authorizatonHeader) == 0 {
-			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
-			return
-		}
-		
-		fields := strings.Fields(authorizatonHeader)
-		if len(fields) < 2 {
-			err := errors.New("invalid authorization header is provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
-			return
-		}
-
-		authorizationType := strings.ToLower(fields[0])
-		if authorizationType != authorizationTypeBearer {
-			err := fmt.Errorf("unsupported authorization type: %s", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
-			return
-		}
-
-		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
-			return
-		}
-
-		ctx.Set(authorizationPayloadKey, payload)
-		ctx.Next()
-	}
-}
\ No newline at end of file
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/pawaspy/MediBridge/ai_agent"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+const (
+	authorizatonHeaderKey   = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authorization_key"
+	// Browsers offer this WebSocket subprotocol followed by the access
+	// token, since they can't set headers on the handshake
+	websocketTokenProtocol = "access_token"
+)
+
+func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizatonHeader := ctx.GetHeader(authorizatonHeaderKey)
+		if len(authorizatonHeader) == 0 {
+			err := errors.New("authorization header is not provided")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		
+		fields := strings.Fields(authorizatonHeader)
+		if len(fields) < 2 {
+			err := errors.New("invalid authorization header is provided")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		authorizationType := strings.ToLower(fields[0])
+		if authorizationType != authorizationTypeBearer {
+			err := fmt.Errorf("unsupported authorization type: %s", authorizationType)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		accessToken := fields[1]
+		payload, err := tokenMaker.VerifyToken(accessToken)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}
+}
+
+// websocketAuthMiddleware authenticates like authMiddleware, but also takes
+// the token from the Sec-WebSocket-Protocol header, where browsers send it as
+// the subprotocols ["access_token", token]. Unlike a query parameter, it
+// doesn't end up in the access log.
+func websocketAuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	auth := authMiddleware(tokenMaker)
+	return func(ctx *gin.Context) {
+		protocols := websocket.Subprotocols(ctx.Request)
+		if len(protocols) == 2 && protocols[0] == websocketTokenProtocol && ctx.GetHeader(authorizatonHeaderKey) == "" {
+			ctx.Request.Header.Set(authorizatonHeaderKey, authorizationTypeBearer+" "+protocols[1])
+		}
+		auth(ctx)
+	}
+}
+
+// optionalAuthMiddleware authenticates like authMiddleware when the request
+// carries an authorization header, and lets anonymous requests through
+func optionalAuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	auth := authMiddleware(tokenMaker)
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader(authorizatonHeaderKey) == "" {
+			ctx.Next()
+			return
+		}
+		auth(ctx)
+	}
+}
+
+// alizaPatientMiddleware tells Aliza which patient is asking, so that it
+// can leave out medicines that conflict with their allergies and conditions
+func alizaPatientMiddleware(ctx *gin.Context) {
+	if payload, ok := ctx.Value(authorizationPayloadKey).(*token.Payload); ok && payload.Role == util.Patient {
+		ctx.Set(ai_agent.PatientUsernameKey, payload.Username)
+	}
+	ctx.Next()
+}
@@ -0,0 +1,675 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/payment"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// CheckoutRequest places an order for everything in the cart. The delivery
+// address defaults to the patient's own address.
+type CheckoutRequest struct {
+	DeliveryAddress string `json:"delivery_address"`
+}
+
+type OrderIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ListOrdersRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+type ListSellerOrdersRequest struct {
+	Status string `form:"status"`
+	Limit  int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+type UpdateSubOrderStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type subOrderResponse struct {
+	db.SubOrder
+	Items []db.OrderItem `json:"items"`
+}
+
+type orderResponse struct {
+	db.Order
+	SubOrders []subOrderResponse `json:"sub_orders"`
+}
+
+func newOrderResponse(order db.Order, subOrders []db.SubOrder, items []db.OrderItem) orderResponse {
+	itemsBySubOrder := make(map[int32][]db.OrderItem)
+	for _, item := range items {
+		itemsBySubOrder[item.SubOrderID] = append(itemsBySubOrder[item.SubOrderID], item)
+	}
+
+	rsp := orderResponse{
+		Order:     order,
+		SubOrders: make([]subOrderResponse, len(subOrders)),
+	}
+	for i, subOrder := range subOrders {
+		rsp.SubOrders[i] = subOrderResponse{
+			SubOrder: subOrder,
+			Items:    itemsBySubOrder[subOrder.ID],
+		}
+		if rsp.SubOrders[i].Items == nil {
+			rsp.SubOrders[i].Items = []db.OrderItem{}
+		}
+	}
+	return rsp
+}
+
+// Orders released per run of the order expiry job
+const orderExpiryBatchSize = 100
+
+// CheckoutCart turns the patient's cart into an order, split into one
+// sub-order per seller. Stock is taken out of each seller's inventory when
+// the order is placed and held for OrderPaymentHold; orders that aren't
+// paid for by then give it back.
+func (server *Server) CheckoutCart(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can place orders")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// The body is optional
+	var req CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	deliveryAddress := strings.TrimSpace(req.DeliveryAddress)
+	if deliveryAddress == "" {
+		patient, err := server.store.GetPatientByName(c, authPayload.Username)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		deliveryAddress = patient.Address
+	}
+
+	result, err := server.store.CheckoutTx(c, db.CheckoutTxParams{
+		PatientUsername: authPayload.Username,
+		DeliveryAddress: deliveryAddress,
+		PaymentDueBy:    pgtype.Timestamp{Time: time.Now().Add(server.config.OrderPaymentHold), Valid: true},
+		MovementType:    util.SaleMovement,
+		Reason:          "order placed",
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrCartEmpty):
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, db.ErrMedicineUnavailable),
+			errors.Is(err, db.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, errorResponse(err))
+		case errors.Is(err, db.ErrRecordNotFound):
+			err := errors.New("a medicine in the cart no longer exists")
+			c.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			util.LogError("Failed to check out cart of %s: %v", authPayload.Username, err)
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	util.LogInfo("Order %d placed by %s with %d sellers", result.Order.ID, authPayload.Username, len(result.SubOrders))
+	c.JSON(http.StatusOK, newOrderResponse(result.Order, result.SubOrders, result.Items))
+}
+
+// getPatientOrder loads an order of the logged-in patient, writing the
+// error response itself when it can't.
+func (server *Server) getPatientOrder(c *gin.Context, id int32) (db.Order, *token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can pay for orders")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return db.Order{}, nil, false
+	}
+
+	order, err := server.store.GetOrder(c, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("order not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return db.Order{}, nil, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Order{}, nil, false
+	}
+
+	if order.PatientUsername != authPayload.Username {
+		err := errors.New("order doesn't belong to the authenticated patient")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return db.Order{}, nil, false
+	}
+
+	return order, authPayload, true
+}
+
+// CreateOrderPayment starts the payment of an order with the provider, for
+// the order's total. The front end completes it with the client secret.
+func (server *Server) CreateOrderPayment(c *gin.Context) {
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	order, authPayload, ok := server.getPatientOrder(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	if order.PaymentStatus != util.PaymentPending ||
+		(order.PaymentDueBy.Valid && !time.Now().Before(order.PaymentDueBy.Time)) {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrOrderNotPayable))
+		return
+	}
+
+	amount, err := minorUnits(order.TotalAmount)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	intent, err := server.paymentProvider.CreateIntent(c, payment.CreateIntentParams{
+		Amount:   amount,
+		Currency: server.config.PaymentCurrency,
+		Metadata: map[string]string{"order_id": strconv.Itoa(int(order.ID))},
+	})
+	if err != nil {
+		util.LogError("Failed to create payment of order %d: %v", order.ID, err)
+		paymentProviderError(c, err)
+		return
+	}
+
+	paid, err := server.store.CreateOrderPayment(c, db.CreateOrderPaymentParams{
+		OrderID:         pgtype.Int4{Int32: order.ID, Valid: true},
+		UserID:          authPayload.Username,
+		Amount:          order.TotalAmount,
+		Currency:        strings.ToUpper(server.config.PaymentCurrency),
+		PaymentIntentID: pgtype.Text{String: intent.ID, Valid: true},
+	})
+	if err != nil {
+		util.LogError("Failed to record payment %s of order %d: %v", intent.ID, order.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, paymentIntentResponse{Payment: paid, ClientSecret: intent.ClientSecret})
+}
+
+// ConfirmOrderPayment looks the patient's payment up with the provider and
+// marks the order paid once the provider reports it succeeded. The
+// provider's webhook does the same, so this only saves waiting for it.
+func (server *Server) ConfirmOrderPayment(c *gin.Context) {
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ConfirmPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	order, _, ok := server.getPatientOrder(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	intent, ok := server.intentFor(c, req.PaymentIntentID, "order_id", order.ID)
+	if !ok {
+		return
+	}
+
+	result, err := server.settleOrderPayment(c, intent)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			err := errors.New("payment not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, errPaymentMismatch):
+			c.JSON(http.StatusPaymentRequired, errorResponse(err))
+		case errors.Is(err, db.ErrOrderNotPayable):
+			err := fmt.Errorf("%w, the payment has been refunded", err)
+			c.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			util.LogError("Failed to confirm payment %s of order %d: %v", intent.ID, order.ID, err)
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// startOrderExpiry periodically gives the stock of orders that weren't paid
+// for in time back, and retries refunds through the provider that didn't go
+// through: of cancelled sub-orders of paid orders, of delivered ones and of
+// cancelled appointments
+func (server *Server) startOrderExpiry(ctx context.Context) {
+	// Run immediately on startup
+	server.expireOrders(ctx)
+
+	ticker := time.NewTicker(server.config.OrderExpiryPeriod)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				server.expireOrders(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (server *Server) expireOrders(ctx context.Context) {
+	now := pgtype.Timestamp{Time: time.Now(), Valid: true}
+	ids, err := server.store.ListOverdueOrders(ctx, db.ListOverdueOrdersParams{
+		Now:   now,
+		Limit: orderExpiryBatchSize,
+	})
+	if err != nil {
+		log.Printf("Error listing unpaid orders: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		_, err := server.store.ExpireOrderTx(ctx, db.ExpireOrderTxParams{
+			OrderID:      id,
+			Now:          now,
+			MovementType: util.ReturnMovement,
+			Reason:       "order not paid for",
+			CreatedBy:    util.SystemActor,
+		})
+		if err != nil && !errors.Is(err, db.ErrOrderNotPayable) {
+			log.Printf("Error expiring unpaid order %d: %v", id, err)
+			continue
+		}
+		if err == nil {
+			log.Printf("Order %d wasn't paid for in time, its stock was put back", id)
+		}
+	}
+
+	subOrders, err := server.store.ListUnrefundedCancelledSubOrders(ctx, orderExpiryBatchSize)
+	if err != nil {
+		log.Printf("Error listing cancelled sub-orders to refund: %v", err)
+		return
+	}
+	for _, subOrder := range subOrders {
+		if err := server.refundCancelledSubOrder(ctx, subOrder.ID, subOrder.PaymentID, subOrder.Subtotal, util.SystemActor); err != nil {
+			log.Printf("Error refunding cancelled sub-order %d: %v", subOrder.ID, err)
+		}
+	}
+
+	refunds, err := server.store.ListUnpaidLedgerRefunds(ctx, orderExpiryBatchSize)
+	if err != nil {
+		log.Printf("Error listing refunds to pay back: %v", err)
+		return
+	}
+	for _, refund := range refunds {
+		err := server.refundLedgerRefund(ctx, refund.ID, refund.SubOrderID, refund.PaymentID, refund.Amount, refund.Description, refund.CreatedBy)
+		if err != nil {
+			log.Printf("Error paying back refund %d: %v", refund.ID, err)
+		}
+	}
+
+	payments, err := server.store.ListUnrefundedAppointmentPayments(ctx, orderExpiryBatchSize)
+	if err != nil {
+		log.Printf("Error listing appointment payments to refund: %v", err)
+		return
+	}
+	for _, paid := range payments {
+		reason := fmt.Sprintf("appointment %d cancelled", paid.AppointmentID.Int32)
+		if _, err := server.refundAppointmentPayment(ctx, paid, reason, util.SystemActor); err != nil {
+			log.Printf("Error refunding payment %d of appointment %d: %v", paid.ID, paid.AppointmentID.Int32, err)
+		}
+	}
+}
+
+// refundCancelledSubOrder pays a sub-order of a paid order back to the
+// patient through the provider once it has been cancelled
+func (server *Server) refundCancelledSubOrder(ctx context.Context, subOrderID, paymentID int32, subtotal pgtype.Numeric, createdBy string) error {
+	amount, err := minorUnits(subtotal)
+	if err != nil {
+		return err
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	paid, err := server.store.GetPayment(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("payment-%d-sub-order-%d", paid.ID, subOrderID)
+	_, err = server.refundPayment(ctx, paid, amount, key, db.CreatePaymentRefundParams{
+		Reason:     fmt.Sprintf("sub-order %d cancelled", subOrderID),
+		SubOrderID: pgtype.Int4{Int32: subOrderID, Valid: true},
+		CreatedBy:  createdBy,
+	})
+	return err
+}
+
+// ListPatientOrders lists the logged-in patient's orders, newest first
+func (server *Server) ListPatientOrders(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients have orders")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req ListOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	orders, err := server.store.ListPatientOrders(c, db.ListPatientOrdersParams{
+		PatientUsername: authPayload.Username,
+		Limit:           req.Limit,
+		Offset:          req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetOrder returns an order with its sub-orders and items. Patients see
+// their own orders in full; sellers only see their own sub-order of it.
+func (server *Server) GetOrder(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	order, err := server.store.GetOrder(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("order not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	subOrders, err := server.store.ListOrderSubOrders(c, order.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	switch authPayload.Role {
+	case util.Patient:
+		if order.PatientUsername != authPayload.Username {
+			err := errors.New("order doesn't belong to the authenticated patient")
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+	case util.Seller:
+		storePayload, ok := server.authorizeStore(c, nil)
+		if !ok {
+			return
+		}
+		var sellerSubOrders []db.SubOrder
+		for _, subOrder := range subOrders {
+			if subOrder.SellerUsername == storePayload.Store {
+				sellerSubOrders = append(sellerSubOrders, subOrder)
+			}
+		}
+		if len(sellerSubOrders) == 0 {
+			err := errors.New("order doesn't include the authenticated seller")
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		subOrders = sellerSubOrders
+	default:
+		err := errors.New("only patients and sellers can view orders")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	items, err := server.store.ListOrderItems(c, order.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrderResponse(order, subOrders, items))
+}
+
+// ListSellerSubOrders lists the sub-orders the logged-in seller has to
+// fulfil, optionally filtered by status
+func (server *Server) ListSellerSubOrders(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListSellerOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListSellerSubOrdersParams{
+		Seller: authPayload.Store,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+	if req.Status != "" {
+		if !util.IsValidOrderStatus(req.Status) {
+			err := errors.New("invalid order status")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.Status = pgtype.Text{String: req.Status, Valid: true}
+	}
+
+	subOrders, err := server.store.ListSellerSubOrders(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, subOrders)
+}
+
+// UpdateSubOrderStatus moves a sub-order along its fulfilment. The seller
+// confirms, ships and delivers it; either side can cancel it before it
+// ships, but a patient only until the seller has confirmed it. Cancelling
+// puts the stock back and delivering it credits the seller's ledger with
+// the subtotal less commission. Sub-orders with prescription medicines can
+// only be confirmed by the store owner or a pharmacist.
+func (server *Server) UpdateSubOrderStatus(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req UpdateSubOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidOrderStatus(req.Status) {
+		err := errors.New("invalid order status")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	subOrder, err := server.store.GetSubOrder(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("order not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	switch authPayload.Role {
+	case util.Seller:
+		storePayload, ok := server.authorizeStore(c, nil)
+		if !ok {
+			return
+		}
+		if subOrder.SellerUsername != storePayload.Store {
+			err := errors.New("order doesn't belong to the authenticated seller")
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		if req.Status != util.CancelledOrder {
+			order, err := server.store.GetOrder(c, subOrder.OrderID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			if order.PaymentStatus != util.PaymentPaid {
+				err := errors.New("order hasn't been paid for yet")
+				c.JSON(http.StatusConflict, errorResponse(err))
+				return
+			}
+		}
+		if req.Status == util.ConfirmedOrder && !util.CanApprovePrescriptions(storePayload.StaffRole) {
+			items, err := server.store.ListSubOrderItems(c, subOrder.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			for _, item := range items {
+				if item.PrescriptionRequired {
+					err := errors.New("orders for prescription medicines must be confirmed by a pharmacist")
+					c.JSON(http.StatusUnauthorized, errorResponse(err))
+					return
+				}
+			}
+		}
+	case util.Patient:
+		order, err := server.store.GetOrder(c, subOrder.OrderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if order.PatientUsername != authPayload.Username {
+			err := errors.New("order doesn't belong to the authenticated patient")
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		if req.Status != util.CancelledOrder || subOrder.Status != util.PlacedOrder {
+			err := errors.New("patients can only cancel orders the seller hasn't confirmed yet")
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+	default:
+		err := errors.New("only patients and sellers can change orders")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !util.CanTransitionSubOrder(subOrder.Status, req.Status) {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrSubOrderStatus))
+		return
+	}
+
+	if req.Status == util.CancelledOrder {
+		subOrder, err = server.store.CancelSubOrderTx(c, db.CancelSubOrderTxParams{
+			SubOrderID:   subOrder.ID,
+			MovementType: util.ReturnMovement,
+			Reason:       "order cancelled",
+			CreatedBy:    authPayload.Username,
+		})
+	} else if req.Status == util.DeliveredOrder {
+		subOrder, err = server.store.DeliverSubOrderTx(c, db.DeliverSubOrderTxParams{
+			SubOrderID: subOrder.ID,
+			CreatedBy:  authPayload.Username,
+		})
+	} else {
+		subOrder, err = server.store.UpdateSubOrderStatus(c, db.UpdateSubOrderStatusParams{
+			ID:         subOrder.ID,
+			Status:     req.Status,
+			FromStatus: subOrder.Status,
+		})
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrSubOrderStatus) || errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, errorResponse(db.ErrSubOrderStatus))
+			return
+		}
+		util.LogError("Failed to update sub-order %d: %v", uri.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Sub-order %d of order %d %s by %s", subOrder.ID, subOrder.OrderID, subOrder.Status, authPayload.Username)
+
+	if subOrder.Status == util.CancelledOrder {
+		server.refundCancelledPaidSubOrder(c, subOrder, authPayload.Username)
+	}
+
+	c.JSON(http.StatusOK, subOrder)
+}
+
+// refundCancelledPaidSubOrder refunds a sub-order that was cancelled after
+// its order was paid for. A refund that fails is retried by the order
+// expiry job.
+func (server *Server) refundCancelledPaidSubOrder(c *gin.Context, subOrder db.SubOrder, createdBy string) {
+	order, err := server.store.GetOrder(c, subOrder.OrderID)
+	if err != nil {
+		util.LogError("Failed to get order %d to refund sub-order %d: %v", subOrder.OrderID, subOrder.ID, err)
+		return
+	}
+	if order.PaymentStatus != util.PaymentPaid || !order.PaidPaymentID.Valid {
+		return
+	}
+
+	err = server.refundCancelledSubOrder(c, subOrder.ID, order.PaidPaymentID.Int32, subOrder.Subtotal, createdBy)
+	if err != nil {
+		util.LogError("Failed to refund cancelled sub-order %d, will retry: %v", subOrder.ID, err)
+		return
+	}
+	util.LogInfo("Refunded cancelled sub-order %d of order %d", subOrder.ID, order.ID)
+}
@@ -0,0 +1,513 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// CreateBranchRequest opens a new branch with a seller account of its own.
+// The seller type is always the head office's, and so is the GST number
+// unless another one is given.
+type CreateBranchRequest struct {
+	Username          string   `json:"username" binding:"required,alphanum"`
+	FullName          string   `json:"full_name" binding:"required"`
+	Email             string   `json:"email" binding:"required,email"`
+	Password          string   `json:"password" binding:"required,min=6"`
+	MobileNumber      string   `json:"mobile_number" binding:"required"`
+	StoreName         string   `json:"store_name" binding:"required"`
+	GstNumber         string   `json:"gst_number"`
+	DrugLicenseNumber string   `json:"drug_license_number" binding:"required"`
+	StoreAddress      string   `json:"store_address" binding:"required"`
+	Pincode           string   `json:"pincode"`
+	Latitude          *float64 `json:"latitude"`
+	Longitude         *float64 `json:"longitude"`
+	DeliveryRadiusKm  *float64 `json:"delivery_radius_km" binding:"omitempty,min=0"`
+}
+
+type BranchUsernameRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type organizationResponse struct {
+	ID            int32            `json:"id"`
+	Name          string           `json:"name"`
+	OwnerUsername string           `json:"owner_username"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	Branches      []sellerResponse `json:"branches"`
+}
+
+func newOrganizationResponse(organization db.Organization, branches []db.Seller) organizationResponse {
+	rsp := organizationResponse{
+		ID:            organization.ID,
+		Name:          organization.Name,
+		OwnerUsername: organization.OwnerUsername,
+		CreatedAt:     organization.CreatedAt,
+		Branches:      make([]sellerResponse, 0, len(branches)),
+	}
+	for _, branch := range branches {
+		rsp.Branches = append(rsp.Branches, newSellerResponse(branch))
+	}
+	return rsp
+}
+
+type branchReportResponse struct {
+	From     string                               `json:"from"`
+	To       string                               `json:"to"`
+	Branches []db.ListOrganizationBranchReportRow `json:"branches"`
+	Total    branchReportTotal                    `json:"total"`
+}
+
+type branchReportTotal struct {
+	Orders            int32   `json:"orders"`
+	UnitsSold         int32   `json:"units_sold"`
+	Revenue           float64 `json:"revenue"`
+	StockUnits        int32   `json:"stock_units"`
+	StockValue        float64 `json:"stock_value"`
+	LowStockMedicines int32   `json:"low_stock_medicines"`
+}
+
+// getStoreOrganization loads the organisation the logged-in user's store
+// belongs to, writing the error response itself when there is none.
+func (server *Server) getStoreOrganization(c *gin.Context, authPayload *token.Payload) (db.Organization, bool) {
+	seller, err := server.store.GetSellerByName(c, authPayload.Store)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return db.Organization{}, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Organization{}, false
+	}
+
+	if !seller.OrganizationID.Valid {
+		err := errors.New("store doesn't belong to an organization")
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return db.Organization{}, false
+	}
+
+	organization, err := server.store.GetOrganization(c, seller.OrganizationID.Int32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return organization, false
+	}
+
+	return organization, true
+}
+
+// getOwnedOrganization loads the organisation whose head office is the
+// logged-in user's store. Only the head office's owners manage the chain.
+func (server *Server) getOwnedOrganization(c *gin.Context) (*token.Payload, db.Organization, bool) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return nil, db.Organization{}, false
+	}
+
+	organization, ok := server.getStoreOrganization(c, authPayload)
+	if !ok {
+		return nil, organization, false
+	}
+
+	if organization.OwnerUsername != authPayload.Store {
+		err := errors.New("only the head office can manage the organization")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, organization, false
+	}
+
+	return authPayload, organization, true
+}
+
+// CreateOrganization turns the logged-in seller's store into the head office
+// of a new organisation.
+func (server *Server) CreateOrganization(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if seller.OrganizationID.Valid {
+		err := errors.New("store already belongs to an organization")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	organization, err := server.store.CreateOrganizationTx(c, db.CreateOrganizationTxParams{
+		Name:          strings.TrimSpace(req.Name),
+		OwnerUsername: seller.Username,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("store already owns an organization")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to create organization for seller %s: %v", seller.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	seller.OrganizationID = pgtype.Int4{Int32: organization.ID, Valid: true}
+	util.LogInfo("Organization %d created by seller %s", organization.ID, seller.Username)
+	c.JSON(http.StatusOK, newOrganizationResponse(organization, []db.Seller{seller}))
+}
+
+// GetStoreOrganization returns the organisation of the logged-in user's
+// store with all of its branches.
+func (server *Server) GetStoreOrganization(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	organization, ok := server.getStoreOrganization(c, authPayload)
+	if !ok {
+		return
+	}
+
+	branches, err := server.store.ListOrganizationBranches(c, pgtype.Int4{Int32: organization.ID, Valid: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrganizationResponse(organization, branches))
+}
+
+func (server *Server) UpdateOrganization(c *gin.Context) {
+	_, organization, ok := server.getOwnedOrganization(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	organization, err := server.store.UpdateOrganizationName(c, db.UpdateOrganizationNameParams{
+		ID:   organization.ID,
+		Name: strings.TrimSpace(req.Name),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+// CreateBranch opens a new branch of the organisation. The branch gets its
+// own seller login, inventory, opening hours and location.
+func (server *Server) CreateBranch(c *gin.Context) {
+	authPayload, organization, ok := server.getOwnedOrganization(c)
+	if !ok {
+		return
+	}
+
+	var req CreateBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidPhoneNumber(req.MobileNumber) {
+		err := errors.New("phone number must be exactly 10 digits")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	headOffice, err := server.store.GetSellerByName(c, organization.OwnerUsername)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Branches share the seller login namespace with sellers and staff
+	if _, err := server.store.GetSellerByName(c, req.Username); err == nil {
+		err := fmt.Errorf("seller with username %s already exists", req.Username)
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	if _, err := server.store.GetStoreStaff(c, req.Username); err == nil {
+		err := fmt.Errorf("seller with username %s already exists", req.Username)
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	location, err := server.resolveSellerLocation(c, req.Pincode, req.StoreAddress, req.Latitude, req.Longitude)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	deliveryRadiusKm := util.DefaultDeliveryRadiusKm
+	if req.DeliveryRadiusKm != nil {
+		deliveryRadiusKm = *req.DeliveryRadiusKm
+	}
+
+	gstNumber := strings.TrimSpace(req.GstNumber)
+	if gstNumber == "" {
+		gstNumber = headOffice.GstNumber
+	}
+
+	hashPass, err := util.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(errors.New("failed to hash password")))
+		return
+	}
+
+	branch, err := server.store.CreateBranchTx(c, db.CreateBranchTxParams{
+		OrganizationID: organization.ID,
+		Seller: db.CreateSellerParams{
+			Username:          req.Username,
+			FullName:          req.FullName,
+			Email:             req.Email,
+			Password:          hashPass,
+			MobileNumber:      req.MobileNumber,
+			StoreName:         req.StoreName,
+			GstNumber:         gstNumber,
+			DrugLicenseNumber: req.DrugLicenseNumber,
+			SellerType:        headOffice.SellerType,
+			StoreAddress:      req.StoreAddress,
+			Pincode:           location.Pincode,
+			Latitude:          location.Latitude,
+			Longitude:         location.Longitude,
+			DeliveryRadiusKm:  deliveryRadiusKm,
+		},
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("username or email is already registered")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to create branch %s for organization %d: %v", req.Username, organization.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Branch %s added to organization %d by %s", branch.Username, organization.ID, authPayload.Username)
+	c.JSON(http.StatusOK, newSellerResponse(branch))
+}
+
+// RemoveBranch takes a branch out of the organisation. The branch keeps its
+// account and stock and carries on as an independent store.
+func (server *Server) RemoveBranch(c *gin.Context) {
+	authPayload, organization, ok := server.getOwnedOrganization(c)
+	if !ok {
+		return
+	}
+
+	var req BranchUsernameRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Username == organization.OwnerUsername {
+		err := errors.New("the head office can't be removed from its organization")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	branch, err := server.store.GetSellerByName(c, req.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("branch not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !branch.OrganizationID.Valid || branch.OrganizationID.Int32 != organization.ID {
+		err := errors.New("branch not found")
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	branch, err = server.store.SetSellerOrganization(c, db.SetSellerOrganizationParams{
+		Username: branch.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Branch %s removed from organization %d by %s", branch.Username, organization.ID, authPayload.Username)
+	c.JSON(http.StatusOK, newSellerResponse(branch))
+}
+
+// GetOrganizationBranchReport compares the branches of the organisation:
+// their sales over a date range and the stock they hold now, with totals
+// for the whole organisation.
+func (server *Server) GetOrganizationBranchReport(c *gin.Context) {
+	var req AnalyticsRangeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, r, ok := server.bindAnalyticsRange(c, req)
+	if !ok {
+		return
+	}
+
+	_, organization, ok := server.getOwnedOrganization(c)
+	if !ok {
+		return
+	}
+
+	branches, err := server.store.ListOrganizationBranchReport(c, db.ListOrganizationBranchReportParams{
+		OrganizationID: pgtype.Int4{Int32: organization.ID, Valid: true},
+		FromDate:       r.fromDate(),
+		ToDate:         r.toDate(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := branchReportResponse{
+		From:     r.from.Format(analyticsDateLayout),
+		To:       r.to.Format(analyticsDateLayout),
+		Branches: branches,
+	}
+	for _, branch := range branches {
+		rsp.Total.Orders += branch.Orders
+		rsp.Total.UnitsSold += branch.UnitsSold
+		rsp.Total.Revenue += numericFloat(branch.Revenue)
+		rsp.Total.StockUnits += branch.StockUnits
+		rsp.Total.StockValue += numericFloat(branch.StockValue)
+		rsp.Total.LowStockMedicines += branch.LowStockMedicines
+	}
+	rsp.Total.Revenue = roundRatio(rsp.Total.Revenue)
+	rsp.Total.StockValue = roundRatio(rsp.Total.StockValue)
+
+	if req.Format == "" {
+		c.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	rows := make([][]string, 0, len(branches)+2)
+	rows = append(rows, []string{
+		"branch", "store_name", "pincode", "orders", "units_sold", "revenue",
+		"medicines", "stock_units", "stock_value", "low_stock_medicines",
+	})
+	for _, branch := range branches {
+		rows = append(rows, []string{
+			branch.Username,
+			branch.StoreName,
+			branch.Pincode.String,
+			strconv.Itoa(int(branch.Orders)),
+			strconv.Itoa(int(branch.UnitsSold)),
+			numericString(branch.Revenue),
+			strconv.Itoa(int(branch.Medicines)),
+			strconv.Itoa(int(branch.StockUnits)),
+			numericString(branch.StockValue),
+			strconv.Itoa(int(branch.LowStockMedicines)),
+		})
+	}
+	rows = append(rows, []string{
+		"total", organization.Name, "",
+		strconv.Itoa(int(rsp.Total.Orders)),
+		strconv.Itoa(int(rsp.Total.UnitsSold)),
+		strconv.FormatFloat(rsp.Total.Revenue, 'f', 2, 64),
+		"",
+		strconv.Itoa(int(rsp.Total.StockUnits)),
+		strconv.FormatFloat(rsp.Total.StockValue, 'f', 2, 64),
+		strconv.Itoa(int(rsp.Total.LowStockMedicines)),
+	})
+
+	fileName := analyticsFileName(organization.OwnerUsername, "branches", r, req.Format)
+	server.writeSpreadsheet(c, fileName, req.Format, rows)
+}
+
+// GetOrganizationSales returns the orders, units, revenue and average order
+// value of all branches together per day, week or month
+func (server *Server) GetOrganizationSales(c *gin.Context) {
+	var req SalesAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, r, ok := server.bindAnalyticsRange(c, req.AnalyticsRangeRequest)
+	if !ok {
+		return
+	}
+
+	_, organization, ok := server.getOwnedOrganization(c)
+	if !ok {
+		return
+	}
+
+	if !util.IsValidAnalyticsInterval(req.Interval) {
+		err := errors.New("interval must be day, week or month")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	periods, err := server.store.ListOrganizationSalesByPeriod(c, db.ListOrganizationSalesByPeriodParams{
+		Interval:       req.Interval,
+		OrganizationID: pgtype.Int4{Int32: organization.ID, Valid: true},
+		FromDate:       r.fromDate(),
+		ToDate:         r.toDate(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.Format == "" {
+		c.JSON(http.StatusOK, periods)
+		return
+	}
+
+	rows := make([][]string, 0, len(periods)+1)
+	rows = append(rows, []string{"period", "orders", "units", "revenue", "average_order_value"})
+	for _, period := range periods {
+		rows = append(rows, []string{
+			period.Period.Time.Format(analyticsDateLayout),
+			strconv.Itoa(int(period.Orders)),
+			strconv.Itoa(int(period.Units)),
+			numericString(period.Revenue),
+			numericString(period.AverageOrderValue),
+		})
+	}
+
+	fileName := analyticsFileName(organization.OwnerUsername, "organization-sales-by-"+req.Interval, r, req.Format)
+	server.writeSpreadsheet(c, fileName, req.Format, rows)
+}
+
+func numericFloat(n pgtype.Numeric) float64 {
+	value, err := n.Float64Value()
+	if err != nil || !value.Valid {
+		return 0
+	}
+	return value.Float64
+}
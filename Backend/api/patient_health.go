@@ -0,0 +1,397 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type SearchClinicalTermsRequest struct {
+	Kind  string `form:"kind" binding:"required,oneof=allergy condition"`
+	Query string `form:"q"`
+	Limit int32  `form:"limit,default=50" binding:"min=1,max=100"`
+}
+
+type CreateHealthEntryRequest struct {
+	TermCode string `json:"term_code" binding:"required"`
+	// Description says what the allergy or condition is for the "other"
+	// terms, and adds detail for the rest
+	Description string `json:"description"`
+	Reaction    string `json:"reaction"`
+	Severity    string `json:"severity"`
+	OnsetDate   string `json:"onset_date"`
+	Status      string `json:"status"`
+}
+
+// UpdateHealthEntryRequest changes the fields given; the term an entry is
+// coded with can't be changed. An empty onset_date clears it.
+type UpdateHealthEntryRequest struct {
+	Description *string `json:"description"`
+	Reaction    *string `json:"reaction"`
+	Severity    *string `json:"severity"`
+	OnsetDate   *string `json:"onset_date"`
+	Status      *string `json:"status"`
+}
+
+type HealthEntryIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type healthEntryResponse struct {
+	db.PatientHealthEntry
+	TermName string `json:"term_name"`
+}
+
+// healthWarning is an active allergy or condition of the patient that
+// flags one or more of a medicine's active ingredients
+type healthWarning struct {
+	Kind        string   `json:"kind"`
+	TermCode    string   `json:"term_code"`
+	TermName    string   `json:"term_name"`
+	Severity    string   `json:"severity"`
+	Ingredients []string `json:"ingredients"`
+}
+
+// SearchClinicalTerms looks up the allergy or condition terms patients code
+// their health lists with
+func (server *Server) SearchClinicalTerms(c *gin.Context) {
+	var req SearchClinicalTermsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	terms, err := server.store.SearchClinicalTerms(c, db.SearchClinicalTermsParams{
+		Kind:       req.Kind,
+		Query:      strings.TrimSpace(req.Query),
+		LimitCount: req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// ListPatientAllergies lists the logged-in patient's allergies
+func (server *Server) ListPatientAllergies(c *gin.Context) {
+	server.listHealthEntries(c, util.AllergyEntry)
+}
+
+// CreatePatientAllergy adds an allergy to the logged-in patient's list
+func (server *Server) CreatePatientAllergy(c *gin.Context) {
+	server.createHealthEntry(c, util.AllergyEntry)
+}
+
+// UpdatePatientAllergy changes an allergy on the logged-in patient's list
+func (server *Server) UpdatePatientAllergy(c *gin.Context) {
+	server.updateHealthEntry(c, util.AllergyEntry)
+}
+
+// DeletePatientAllergy removes an allergy recorded by mistake. Allergies
+// that have gone away should be marked resolved instead.
+func (server *Server) DeletePatientAllergy(c *gin.Context) {
+	server.deleteHealthEntry(c, util.AllergyEntry)
+}
+
+// ListPatientConditions lists the logged-in patient's chronic conditions
+func (server *Server) ListPatientConditions(c *gin.Context) {
+	server.listHealthEntries(c, util.ConditionEntry)
+}
+
+// CreatePatientCondition adds a chronic condition to the logged-in
+// patient's list
+func (server *Server) CreatePatientCondition(c *gin.Context) {
+	server.createHealthEntry(c, util.ConditionEntry)
+}
+
+// UpdatePatientCondition changes a condition on the logged-in patient's list
+func (server *Server) UpdatePatientCondition(c *gin.Context) {
+	server.updateHealthEntry(c, util.ConditionEntry)
+}
+
+// DeletePatientCondition removes a condition recorded by mistake
+func (server *Server) DeletePatientCondition(c *gin.Context) {
+	server.deleteHealthEntry(c, util.ConditionEntry)
+}
+
+// authorizePatient checks that the logged-in user is a patient, writing the
+// error response itself when they aren't.
+func authorizePatient(c *gin.Context) (*token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients have allergies and conditions")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, false
+	}
+	return authPayload, true
+}
+
+func (server *Server) listHealthEntries(c *gin.Context, kind string) {
+	authPayload, ok := authorizePatient(c)
+	if !ok {
+		return
+	}
+
+	entries, err := server.store.ListPatientHealthEntries(c, db.ListPatientHealthEntriesParams{
+		PatientUsername: authPayload.Username,
+		Kind:            kind,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (server *Server) createHealthEntry(c *gin.Context, kind string) {
+	authPayload, ok := authorizePatient(c)
+	if !ok {
+		return
+	}
+
+	var req CreateHealthEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	term, err := server.store.GetClinicalTerm(c, strings.ToUpper(strings.TrimSpace(req.TermCode)))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := fmt.Errorf("unknown %s code: %s", kind, req.TermCode)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if term.Kind != kind {
+		err := fmt.Errorf("%s is not one of the %s terms", term.Code, kind)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreatePatientHealthEntryParams{
+		PatientUsername: authPayload.Username,
+		Kind:            kind,
+		TermCode:        term.Code,
+		Description:     strings.TrimSpace(req.Description),
+		Reaction:        strings.TrimSpace(req.Reaction),
+		Severity:        req.Severity,
+		Status:          req.Status,
+	}
+	if arg.Severity == "" {
+		arg.Severity = util.UnknownSeverity
+	}
+	if arg.Status == "" {
+		arg.Status = util.ActiveHealthEntry
+	}
+
+	arg.OnsetDate, err = parsePastDate("onset_date", req.OnsetDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := validateHealthEntry(term, arg.Description, arg.Severity, arg.Status); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	entry, err := server.store.CreatePatientHealthEntry(c, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := fmt.Errorf("%s is already on your list", term.Name)
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to save %s %s of %s: %v", kind, term.Code, authPayload.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Patient %s added %s %d (%s)", authPayload.Username, kind, entry.ID, term.Code)
+	c.JSON(http.StatusOK, healthEntryResponse{PatientHealthEntry: entry, TermName: term.Name})
+}
+
+func (server *Server) updateHealthEntry(c *gin.Context, kind string) {
+	authPayload, ok := authorizePatient(c)
+	if !ok {
+		return
+	}
+
+	var uri HealthEntryIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req UpdateHealthEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	entry, ok := server.getHealthEntry(c, authPayload.Username, uri.ID, kind)
+	if !ok {
+		return
+	}
+
+	term, err := server.store.GetClinicalTerm(c, entry.TermCode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdatePatientHealthEntryParams{
+		ID:              entry.ID,
+		PatientUsername: authPayload.Username,
+		Description:     entry.Description,
+		Reaction:        entry.Reaction,
+		Severity:        entry.Severity,
+		OnsetDate:       entry.OnsetDate,
+		Status:          entry.Status,
+	}
+	if req.Description != nil {
+		arg.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Reaction != nil {
+		arg.Reaction = strings.TrimSpace(*req.Reaction)
+	}
+	if req.Severity != nil {
+		arg.Severity = *req.Severity
+	}
+	if req.Status != nil {
+		arg.Status = *req.Status
+	}
+	if req.OnsetDate != nil {
+		arg.OnsetDate, err = parsePastDate("onset_date", *req.OnsetDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if err := validateHealthEntry(term, arg.Description, arg.Severity, arg.Status); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	entry, err = server.store.UpdatePatientHealthEntry(c, arg)
+	if err != nil {
+		util.LogError("Failed to update %s %d of %s: %v", kind, uri.ID, authPayload.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Patient %s updated %s %d", authPayload.Username, kind, entry.ID)
+	c.JSON(http.StatusOK, healthEntryResponse{PatientHealthEntry: entry, TermName: term.Name})
+}
+
+func (server *Server) deleteHealthEntry(c *gin.Context, kind string) {
+	authPayload, ok := authorizePatient(c)
+	if !ok {
+		return
+	}
+
+	var uri HealthEntryIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.getHealthEntry(c, authPayload.Username, uri.ID, kind); !ok {
+		return
+	}
+
+	_, err := server.store.DeletePatientHealthEntry(c, db.DeletePatientHealthEntryParams{
+		ID:              uri.ID,
+		PatientUsername: authPayload.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Patient %s deleted %s %d", authPayload.Username, kind, uri.ID)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": kind + " deleted successfully"})
+}
+
+// getHealthEntry loads an entry of the given kind from the patient's lists,
+// writing the error response itself when there is no such entry
+func (server *Server) getHealthEntry(c *gin.Context, patientUsername string, id int32, kind string) (db.PatientHealthEntry, bool) {
+	entry, err := server.store.GetPatientHealthEntry(c, db.GetPatientHealthEntryParams{
+		ID:              id,
+		PatientUsername: patientUsername,
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return entry, false
+	}
+	if err != nil || entry.Kind != kind {
+		err := fmt.Errorf("%s not found", kind)
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return entry, false
+	}
+	return entry, true
+}
+
+func validateHealthEntry(term db.ClinicalTerm, description, severity, status string) error {
+	if util.IsOtherClinicalTerm(term.Code) && description == "" {
+		return fmt.Errorf("a description is required with %s", term.Code)
+	}
+	if !util.IsValidSeverity(severity) {
+		return fmt.Errorf("invalid severity: %s", severity)
+	}
+	if !util.IsValidHealthEntryStatus(status) {
+		return fmt.Errorf("invalid status: %s", status)
+	}
+	return nil
+}
+
+// parsePastDate reads an optional date, such as when a condition started,
+// which can't be in the future
+func parsePastDate(field, value string) (pgtype.Date, error) {
+	if value == "" {
+		return pgtype.Date{}, nil
+	}
+	date, err := parseAppointmentDate(field, value)
+	if err != nil {
+		return pgtype.Date{}, err
+	}
+	if date.After(today()) {
+		return pgtype.Date{}, fmt.Errorf("%s can't be in the future", field)
+	}
+	return pgDate(date), nil
+}
+
+// medicineHealthWarnings checks a medicine's active ingredients against the
+// patient's active allergies and conditions, as listed by
+// ListPatientHealthFlags
+func medicineHealthWarnings(activeIngredients string, flags []db.ListPatientHealthFlagsRow) []healthWarning {
+	var warnings []healthWarning
+	for _, flag := range flags {
+		ingredients := util.FlaggedIngredients(activeIngredients, flag.Ingredients)
+		if len(ingredients) == 0 {
+			continue
+		}
+		warnings = append(warnings, healthWarning{
+			Kind:        flag.Kind,
+			TermCode:    flag.TermCode,
+			TermName:    flag.TermName,
+			Severity:    flag.Severity,
+			Ingredients: ingredients,
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,291 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/payment"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// errPaymentMismatch is returned for an intent that doesn't match the
+// payment recorded for it
+var errPaymentMismatch = errors.New("payment doesn't match")
+
+const (
+	paymentSignatureHeader = "Stripe-Signature"
+	maxWebhookBodySize     = 1 << 20
+)
+
+// paymentIntentResponse is what the front end needs to collect a payment
+// with the provider's widget
+type paymentIntentResponse struct {
+	Payment      db.Payment `json:"payment"`
+	ClientSecret string     `json:"client_secret"`
+}
+
+// ConfirmPaymentRequest asks the server to look up a payment the patient
+// made with the provider's widget
+type ConfirmPaymentRequest struct {
+	PaymentIntentID string `json:"payment_intent_id" binding:"required"`
+}
+
+// minorUnits converts an amount like 249.50 to the 24950 paise the provider
+// works in
+func minorUnits(amount pgtype.Numeric) (int64, error) {
+	if !amount.Valid || amount.NaN || amount.InfinityModifier != pgtype.Finite {
+		return 0, errors.New("invalid amount")
+	}
+
+	value := new(big.Int).Set(amount.Int)
+	exp := amount.Exp + 2
+	ten := big.NewInt(10)
+	for ; exp > 0; exp-- {
+		value.Mul(value, ten)
+	}
+	for ; exp < 0; exp++ {
+		var remainder big.Int
+		value.QuoRem(value, ten, &remainder)
+		if remainder.Sign() != 0 {
+			return 0, fmt.Errorf("amount has more than two decimal places")
+		}
+	}
+
+	if !value.IsInt64() {
+		return 0, errors.New("amount is too large")
+	}
+	return value.Int64(), nil
+}
+
+func fromMinorUnits(amount int64) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(amount), Exp: -2, Valid: true}
+}
+
+// paymentProviderError writes the response for a failed call to the
+// payment provider
+func paymentProviderError(c *gin.Context, err error) {
+	if errors.Is(err, payment.ErrNotConfigured) {
+		c.JSON(http.StatusServiceUnavailable, errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusBadGateway, errorResponse(err))
+}
+
+// checkIntent makes sure an intent the provider reports as succeeded is for
+// the amount and currency the payment was created with
+func (server *Server) checkIntent(intent payment.Intent, expected db.Payment) error {
+	if intent.Status != payment.IntentSucceeded {
+		return fmt.Errorf("%w: payment is %s, not succeeded", errPaymentMismatch, intent.Status)
+	}
+
+	amount, err := minorUnits(expected.Amount)
+	if err != nil {
+		return err
+	}
+	if intent.Amount != amount || !strings.EqualFold(intent.Currency, expected.Currency) {
+		return fmt.Errorf("%w: got %d %s, expected %d %s", errPaymentMismatch,
+			intent.Amount, intent.Currency, amount, expected.Currency)
+	}
+	return nil
+}
+
+// refundPayment pays money back through the provider and records the
+// refund under the provider's ID, with what it was for taken from record.
+// An amount of 0 refunds whatever is left of the payment. The key makes
+// retries of the same refund pay out once.
+func (server *Server) refundPayment(ctx context.Context, paid db.Payment, amount int64, key string, record db.CreatePaymentRefundParams) (db.PaymentRefund, error) {
+	refund, err := server.paymentProvider.Refund(ctx, payment.RefundParams{
+		IntentID:       paid.PaymentIntentID.String,
+		Amount:         amount,
+		Reason:         record.Reason,
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		return db.PaymentRefund{}, err
+	}
+
+	record.PaymentID = paid.ID
+	record.ProviderRefundID = refund.ID
+	record.Amount = fromMinorUnits(refund.Amount)
+	result, err := server.store.CreatePaymentRefund(ctx, record)
+	if db.ErrorCode(err) == db.UniqueViolation {
+		// A retry the provider answered with the refund it already made
+		return result, nil
+	}
+	return result, err
+}
+
+// refundLedgerRefund pays a refund posted to the ledger on a delivered
+// sub-order back to the patient
+func (server *Server) refundLedgerRefund(ctx context.Context, transactionID int64, subOrderID pgtype.Int4, paymentID int32, refunded pgtype.Numeric, reason, createdBy string) error {
+	amount, err := minorUnits(refunded)
+	if err != nil {
+		return err
+	}
+
+	paid, err := server.store.GetPayment(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("payment-%d-ledger-%d", paid.ID, transactionID)
+	_, err = server.refundPayment(ctx, paid, amount, key, db.CreatePaymentRefundParams{
+		Reason:              reason,
+		SubOrderID:          subOrderID,
+		LedgerTransactionID: pgtype.Int8{Int64: transactionID, Valid: true},
+		CreatedBy:           createdBy,
+	})
+	return err
+}
+
+// settleOrderPayment marks an order paid once the provider has confirmed
+// its payment. A payment that came in after the order expired, or on top of
+// another one, is refunded in full.
+func (server *Server) settleOrderPayment(ctx context.Context, intent payment.Intent) (db.ConfirmOrderPaymentTxResult, error) {
+	paid, err := server.store.GetPaymentByIntent(ctx, pgtype.Text{String: intent.ID, Valid: true})
+	if err != nil {
+		return db.ConfirmOrderPaymentTxResult{}, err
+	}
+	if err := server.checkIntent(intent, paid); err != nil {
+		return db.ConfirmOrderPaymentTxResult{}, err
+	}
+
+	result, err := server.store.ConfirmOrderPaymentTx(ctx, db.ConfirmOrderPaymentTxParams{
+		PaymentIntentID: intent.ID,
+		ChargeID:        pgtype.Text{String: intent.ChargeID, Valid: intent.ChargeID != ""},
+		PaymentMethod:   intent.PaymentMethod,
+	})
+	if !errors.Is(err, db.ErrOrderNotPayable) {
+		if err == nil {
+			util.LogInfo("Order %d paid with payment %d", result.Order.ID, result.Payment.ID)
+		}
+		return result, err
+	}
+
+	refunds, listErr := server.store.ListPaymentRefunds(ctx, result.Payment.ID)
+	if listErr != nil {
+		return result, listErr
+	}
+	if len(refunds) == 0 {
+		key := fmt.Sprintf("payment-%d-unused", result.Payment.ID)
+		_, refundErr := server.refundPayment(ctx, result.Payment, 0, key, db.CreatePaymentRefundParams{
+			Reason:    fmt.Sprintf("order %d was no longer waiting for this payment", result.Order.ID),
+			CreatedBy: util.SystemActor,
+		})
+		if refundErr != nil {
+			util.LogError("Failed to refund payment %d of order %d: %v", result.Payment.ID, result.Order.ID, refundErr)
+			return result, refundErr
+		}
+		util.LogInfo("Refunded payment %d that came in for order %d after it stopped waiting for payment", result.Payment.ID, result.Order.ID)
+	}
+	return result, err
+}
+
+// settleAppointmentPayment confirms an appointment once the provider has
+// confirmed its payment and lets the patient and doctor know. A payment
+// that came in after the appointment's hold ran out, or on top of another
+// one, is refunded in full.
+func (server *Server) settleAppointmentPayment(c *gin.Context, intent payment.Intent) (db.ConfirmAppointmentPaymentTxResult, error) {
+	paid, err := server.store.GetPaymentByIntent(c, pgtype.Text{String: intent.ID, Valid: true})
+	if err != nil {
+		return db.ConfirmAppointmentPaymentTxResult{}, err
+	}
+	if err := server.checkIntent(intent, paid); err != nil {
+		return db.ConfirmAppointmentPaymentTxResult{}, err
+	}
+
+	result, err := server.store.ConfirmAppointmentPaymentTx(c, db.ConfirmAppointmentPaymentTxParams{
+		PaymentIntentID: intent.ID,
+		ChargeID:        pgtype.Text{String: intent.ChargeID, Valid: intent.ChargeID != ""},
+		PaymentMethod:   intent.PaymentMethod,
+		Now:             pgTimestamp(wallClockNow()),
+	})
+	if !errors.Is(err, db.ErrAppointmentNotPayable) {
+		if err == nil && result.Confirmed {
+			util.LogInfo("Appointment %d paid with payment %d", result.Appointment.ID, result.Payment.ID)
+			server.sendAppointmentEmails(c, result.Appointment, "booked", pgtype.Timestamp{})
+		}
+		return result, err
+	}
+
+	if result.Payment.Status == util.PaymentSucceeded {
+		reason := fmt.Sprintf("appointment %d was no longer waiting for this payment", result.Appointment.ID)
+		if _, refundErr := server.refundAppointmentPayment(c, result.Payment, reason, util.SystemActor); refundErr != nil {
+			util.LogError("Failed to refund payment %d of appointment %d: %v", result.Payment.ID, result.Appointment.ID, refundErr)
+			return result, refundErr
+		}
+		util.LogInfo("Refunded payment %d that came in for appointment %d after it stopped waiting for payment", result.Payment.ID, result.Appointment.ID)
+	}
+	return result, err
+}
+
+// PaymentWebhook receives the provider's notifications. Only requests with
+// a valid signature are acted on, so the client can't mark anything paid.
+func (server *Server) PaymentWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	event, err := server.paymentProvider.ParseWebhook(body, c.GetHeader(paymentSignatureHeader))
+	if err != nil {
+		if errors.Is(err, payment.ErrNotConfigured) {
+			c.JSON(http.StatusServiceUnavailable, errorResponse(err))
+			return
+		}
+		util.LogWarning("Refused payment webhook: %v", err)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if event.Type != payment.EventIntentSucceeded {
+		c.JSON(http.StatusOK, gin.H{"received": true})
+		return
+	}
+
+	if _, ok := event.Intent.Metadata["order_id"]; ok {
+		_, err = server.settleOrderPayment(c, event.Intent)
+	}
+	if _, ok := event.Intent.Metadata["appointment_id"]; ok {
+		_, err = server.settleAppointmentPayment(c, event.Intent)
+	}
+	switch {
+	case err == nil, errors.Is(err, db.ErrOrderNotPayable), errors.Is(err, db.ErrAppointmentNotPayable):
+	case errors.Is(err, db.ErrRecordNotFound), errors.Is(err, errPaymentMismatch):
+		// Retrying won't help with these
+		util.LogError("Ignored payment %s from webhook %s: %v", event.Intent.ID, event.ID, err)
+	default:
+		// The provider retries webhooks that fail
+		util.LogError("Failed to settle payment %s from webhook %s: %v", event.Intent.ID, event.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// intentFor looks up an intent with the provider and checks that it was
+// created for the given kind of purchase, such as order_id 42
+func (server *Server) intentFor(c *gin.Context, intentID, key string, id int32) (payment.Intent, bool) {
+	intent, err := server.paymentProvider.GetIntent(c, intentID)
+	if err != nil {
+		paymentProviderError(c, err)
+		return payment.Intent{}, false
+	}
+	if intent.Metadata[key] != strconv.Itoa(int(id)) {
+		err := errors.New("payment doesn't belong to this purchase")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return payment.Intent{}, false
+	}
+	return intent, true
+}
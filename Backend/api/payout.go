@@ -0,0 +1,306 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// RefundSubOrderRequest pays a patient back on a delivered sub-order. A
+// missing amount refunds everything not refunded yet.
+type RefundSubOrderRequest struct {
+	Amount string `json:"amount"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+type PayoutIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ListPayoutsRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+// ListLedgerRequest filters the seller's ledger by day, both inclusive, or
+// to the period a payout settled.
+type ListLedgerRequest struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	PayoutID int32  `form:"payout_id" binding:"min=0"`
+	Limit    int32  `form:"limit,default=50" binding:"min=1,max=100"`
+	Offset   int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+type sellerBalanceResponse struct {
+	// Balance is what the seller would be paid if a payout ran now
+	Balance pgtype.Numeric `json:"balance"`
+	// Pending is the ledger activity since the last payout
+	Pending    db.GetSellerSettlementRow `json:"pending"`
+	LastPayout *db.Payout                `json:"last_payout"`
+}
+
+// RefundSubOrder refunds part or all of a delivered sub-order. The seller's
+// payable and the platform's commission are reduced in proportion.
+func (server *Server) RefundSubOrder(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req RefundSubOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var amount pgtype.Numeric
+	if req.Amount != "" {
+		if !util.IsValidMoneyAmount(req.Amount) {
+			err := fmt.Errorf("invalid refund amount: %s", req.Amount)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if err := amount.Scan(req.Amount); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	subOrder, err := server.store.GetSubOrder(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("order not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if subOrder.SellerUsername != authPayload.Store {
+		err := errors.New("order doesn't belong to the authenticated seller")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	transaction, err := server.store.RefundSubOrderTx(c, db.RefundSubOrderTxParams{
+		SubOrderID:  subOrder.ID,
+		Kind:        util.RefundLedgerKind,
+		Amount:      amount,
+		Description: req.Reason,
+		CreatedBy:   authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrNotRefundable) || errors.Is(err, db.ErrRefundExceedsAmount) {
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to refund sub-order %d: %v", subOrder.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Sub-order %d of order %d refunded by %s", subOrder.ID, subOrder.OrderID, authPayload.Username)
+
+	refund, err := server.store.GetUnpaidLedgerRefund(c, transaction.ID)
+	if err == nil {
+		err = server.refundLedgerRefund(c, refund.ID, refund.SubOrderID, refund.PaymentID, refund.Amount, refund.Description, refund.CreatedBy)
+	}
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		util.LogError("Failed to pay back refund %d of sub-order %d, will retry: %v", transaction.ID, subOrder.ID, err)
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// GetSellerBalance returns what the store is owed right now, the activity
+// behind it since the last payout and that payout.
+func (server *Server) GetSellerBalance(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	now := pgtype.Timestamp{Time: time.Now(), Valid: true}
+	var rsp sellerBalanceResponse
+
+	var periodStart pgtype.Timestamp
+	last, err := server.store.GetLastSellerPayout(c, authPayload.Store)
+	if err == nil {
+		rsp.LastPayout = &last
+		periodStart = last.PeriodEnd
+	} else if !errors.Is(err, db.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp.Balance, err = server.store.GetSellerLedgerBalance(c, db.GetSellerLedgerBalanceParams{
+		Seller: authPayload.Store,
+		AsOf:   now,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp.Pending, err = server.store.GetSellerSettlement(c, db.GetSellerSettlementParams{
+		Seller:      authPayload.Store,
+		PeriodStart: periodStart,
+		PeriodEnd:   now,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+// ListSellerPayouts lists the store's settlement statements, newest first
+func (server *Server) ListSellerPayouts(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var req ListPayoutsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payouts, err := server.store.ListSellerPayouts(c, db.ListSellerPayoutsParams{
+		Seller: authPayload.Store,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, payouts)
+}
+
+// GetSellerPayout returns one settlement statement of the store
+func (server *Server) GetSellerPayout(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var uri PayoutIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payout, ok := server.getStorePayout(c, authPayload, uri.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, payout)
+}
+
+// getStorePayout loads a payout of the logged-in store, writing the error
+// response itself when it can't.
+func (server *Server) getStorePayout(c *gin.Context, authPayload *token.Payload, id int32) (db.Payout, bool) {
+	payout, err := server.store.GetPayout(c, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("payout not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return payout, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return payout, false
+	}
+
+	if payout.SellerUsername != authPayload.Store {
+		err := errors.New("payout doesn't belong to the authenticated seller")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return payout, false
+	}
+
+	return payout, true
+}
+
+// ListSellerLedger lists the store's side of each ledger transaction,
+// newest first: earnings on delivered orders, refunds, chargebacks and
+// payouts, with the commission on each.
+func (server *Server) ListSellerLedger(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var req ListLedgerRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListSellerLedgerTransactionsParams{
+		Seller: authPayload.Store,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+
+	if req.PayoutID != 0 {
+		if req.From != "" || req.To != "" {
+			err := errors.New("payout_id can't be combined with from or to")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		payout, ok := server.getStorePayout(c, authPayload, req.PayoutID)
+		if !ok {
+			return
+		}
+		arg.FromTime = payout.PeriodStart
+		arg.ToTime = payout.PeriodEnd
+	}
+
+	// Entries are timestamped to the microsecond, so these bounds make both
+	// days inclusive
+	if req.From != "" {
+		from, err := time.Parse(analyticsDateLayout, req.From)
+		if err != nil {
+			err := fmt.Errorf("invalid from date, expected YYYY-MM-DD: %s", req.From)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.FromTime = pgtype.Timestamp{Time: from.Add(-time.Microsecond), Valid: true}
+	}
+	if req.To != "" {
+		to, err := time.Parse(analyticsDateLayout, req.To)
+		if err != nil {
+			err := fmt.Errorf("invalid to date, expected YYYY-MM-DD: %s", req.To)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.ToTime = pgtype.Timestamp{Time: to.AddDate(0, 0, 1).Add(-time.Microsecond), Valid: true}
+	}
+
+	transactions, err := server.store.ListSellerLedgerTransactions(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
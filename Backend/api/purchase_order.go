@@ -0,0 +1,519 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type TradePriceTierRequest struct {
+	MinQuantity int32  `json:"min_quantity" binding:"required,min=2"`
+	UnitPrice   string `json:"unit_price" binding:"required"`
+}
+
+// SetTradePricingRequest replaces the trade pricing of a medicine. An empty
+// trade price takes the medicine out of the wholesale catalogue.
+type SetTradePricingRequest struct {
+	TradePrice       string                  `json:"trade_price"`
+	MinOrderQuantity int32                   `json:"min_order_quantity" binding:"omitempty,min=1"`
+	Tiers            []TradePriceTierRequest `json:"tiers" binding:"omitempty,dive"`
+}
+
+type ListWholesaleCatalogueRequest struct {
+	Supplier string `form:"supplier"`
+	Medicine string `form:"medicine"`
+	Limit    int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset   int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+type wholesaleCatalogueItem struct {
+	db.ListWholesaleCatalogueRow
+	Tiers []db.TradePriceTier `json:"tiers"`
+}
+
+type PurchaseOrderItemRequest struct {
+	MedicineID int32 `json:"medicine_id" binding:"required,min=1"`
+	Quantity   int32 `json:"quantity" binding:"required,min=1"`
+}
+
+type CreatePurchaseOrderRequest struct {
+	SupplierUsername string                     `json:"supplier_username" binding:"required"`
+	Notes            string                     `json:"notes"`
+	Items            []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type PurchaseOrderIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ListPurchaseOrdersRequest struct {
+	Status string `form:"status"`
+	Limit  int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+// SetTradePricing sets the trade price, minimum order quantity and price
+// tiers at which a wholesale seller offers a medicine to retail sellers.
+func (server *Server) SetTradePricing(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	if seller.SellerType != util.WholesaleSeller {
+		err := errors.New("only wholesale sellers can set trade pricing")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	medicine, ok := server.getSellerMedicine(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var req SetTradePricingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.SetTradePricingTxParams{
+		MedicineID:       medicine.ID,
+		MinOrderQuantity: req.MinOrderQuantity,
+	}
+	if arg.MinOrderQuantity == 0 {
+		arg.MinOrderQuantity = 1
+	}
+
+	if req.TradePrice != "" {
+		var err error
+		arg.TradePrice, err = parseTradePrice(req.TradePrice)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	} else if len(req.Tiers) > 0 {
+		err := errors.New("price tiers need a trade price")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	seen := make(map[int32]bool, len(req.Tiers))
+	for _, tier := range req.Tiers {
+		if seen[tier.MinQuantity] {
+			err := fmt.Errorf("duplicate price tier for %d units", tier.MinQuantity)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		seen[tier.MinQuantity] = true
+
+		unitPrice, err := parseTradePrice(tier.UnitPrice)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.Tiers = append(arg.Tiers, db.TradePriceTierParams{
+			MinQuantity: tier.MinQuantity,
+			UnitPrice:   unitPrice,
+		})
+	}
+
+	result, err := server.store.SetTradePricingTx(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func parseTradePrice(value string) (pgtype.Numeric, error) {
+	var price pgtype.Numeric
+	if err := price.Scan(value); err != nil {
+		return price, fmt.Errorf("invalid price format: %s", value)
+	}
+
+	number, err := price.Float64Value()
+	if err != nil || number.Float64 < 0 {
+		return price, fmt.Errorf("invalid price: %s", value)
+	}
+
+	return price, nil
+}
+
+// ListWholesaleCatalogue lets retail sellers browse wholesale stock at trade
+// prices, with the price tiers of each medicine.
+func (server *Server) ListWholesaleCatalogue(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, nil)
+	if !ok {
+		return
+	}
+
+	if seller.SellerType != util.RetailSeller {
+		err := errors.New("only retail sellers can browse the wholesale catalogue")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req ListWholesaleCatalogueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListWholesaleCatalogueParams{
+		Medicine: strings.TrimSpace(req.Medicine),
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+	if req.Supplier != "" {
+		arg.Supplier = pgtype.Text{String: req.Supplier, Valid: true}
+	}
+
+	rows, err := server.store.ListWholesaleCatalogue(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ids := make([]int32, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	tiers, err := server.store.ListTradePriceTiersForMedicines(c, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	tiersByMedicine := make(map[int32][]db.TradePriceTier)
+	for _, tier := range tiers {
+		tiersByMedicine[tier.MedicineID] = append(tiersByMedicine[tier.MedicineID], tier)
+	}
+
+	items := make([]wholesaleCatalogueItem, len(rows))
+	for i, row := range rows {
+		items[i] = wholesaleCatalogueItem{
+			ListWholesaleCatalogueRow: row,
+			Tiers:                     tiersByMedicine[row.ID],
+		}
+		if items[i].Tiers == nil {
+			items[i].Tiers = []db.TradePriceTier{}
+		}
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// CreatePurchaseOrder places an order from a retail seller with a wholesale
+// supplier. Prices are fixed at the time the order is placed.
+func (server *Server) CreatePurchaseOrder(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	if seller.SellerType != util.RetailSeller {
+		err := errors.New("only retail sellers can place purchase orders")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req CreatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	supplier, err := server.store.GetSellerByName(c, req.SupplierUsername)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("supplier not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if supplier.SellerType != util.WholesaleSeller {
+		err := errors.New("purchase orders can only be placed with wholesale sellers")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreatePurchaseOrderTxParams{
+		BuyerUsername:    seller.Username,
+		SupplierUsername: supplier.Username,
+		Notes:            strings.TrimSpace(req.Notes),
+	}
+
+	// Merge repeated medicines into one line
+	quantities := make(map[int32]int32, len(req.Items))
+	for _, item := range req.Items {
+		quantities[item.MedicineID] += item.Quantity
+	}
+	for medicineID, quantity := range quantities {
+		arg.Items = append(arg.Items, db.PurchaseOrderItemParams{
+			MedicineID: medicineID,
+			Quantity:   quantity,
+		})
+	}
+	sort.Slice(arg.Items, func(i, j int) bool {
+		return arg.Items[i].MedicineID < arg.Items[j].MedicineID
+	})
+
+	result, err := server.store.CreatePurchaseOrderTx(c, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			err := errors.New("medicine not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrNotInCatalogue),
+			errors.Is(err, db.ErrBelowMinimumOrder),
+			errors.Is(err, db.ErrInsufficientStock):
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+		default:
+			util.LogError("Failed to create purchase order for %s: %v", seller.Username, err)
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	util.LogInfo("Purchase order %d placed by %s with %s", result.Order.ID, seller.Username, supplier.Username)
+	c.JSON(http.StatusOK, result)
+}
+
+// ListPurchaseOrders lists the orders the logged-in seller placed or
+// received.
+func (server *Server) ListPurchaseOrders(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListPurchaseOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListSellerPurchaseOrdersParams{
+		Seller: authPayload.Store,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+	if req.Status != "" {
+		if !util.IsValidPurchaseOrderStatus(req.Status) {
+			err := errors.New("invalid purchase order status")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.Status = pgtype.Text{String: req.Status, Valid: true}
+	}
+
+	orders, err := server.store.ListSellerPurchaseOrders(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// getPurchaseOrder loads the order in the URI and checks that the logged-in
+// user's store is its buyer or supplier and that allowed accepts their staff
+// role, writing the error response itself when it doesn't.
+func (server *Server) getPurchaseOrder(c *gin.Context, allowed func(staffRole string) bool) (*token.Payload, db.PurchaseOrder, bool) {
+	authPayload, ok := server.authorizeStore(c, allowed)
+	if !ok {
+		return nil, db.PurchaseOrder{}, false
+	}
+
+	var uri PurchaseOrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return nil, db.PurchaseOrder{}, false
+	}
+
+	order, err := server.store.GetPurchaseOrder(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("purchase order not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return nil, order, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, order, false
+	}
+
+	if order.BuyerUsername != authPayload.Store && order.SupplierUsername != authPayload.Store {
+		err := errors.New("purchase order doesn't belong to the authenticated seller")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, order, false
+	}
+
+	return authPayload, order, true
+}
+
+func (server *Server) GetPurchaseOrder(c *gin.Context) {
+	_, order, ok := server.getPurchaseOrder(c, nil)
+	if !ok {
+		return
+	}
+
+	items, err := server.store.ListPurchaseOrderItems(c, order.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, db.PurchaseOrderTxResult{
+		Order: order,
+		Items: items,
+	})
+}
+
+// AcceptPurchaseOrder lets the supplier confirm a placed order
+func (server *Server) AcceptPurchaseOrder(c *gin.Context) {
+	server.changePurchaseOrderStatus(c, util.AcceptedPurchaseOrder, false)
+}
+
+// RejectPurchaseOrder lets the supplier turn down a placed order
+func (server *Server) RejectPurchaseOrder(c *gin.Context) {
+	server.changePurchaseOrderStatus(c, util.RejectedPurchaseOrder, false)
+}
+
+// CancelPurchaseOrder lets the buyer withdraw an order that hasn't shipped
+func (server *Server) CancelPurchaseOrder(c *gin.Context) {
+	server.changePurchaseOrderStatus(c, util.CancelledPurchaseOrder, true)
+}
+
+// changePurchaseOrderStatus moves an order on to a status that doesn't touch
+// stock. byBuyer says whether the buyer or the supplier makes the change.
+func (server *Server) changePurchaseOrderStatus(c *gin.Context, status string, byBuyer bool) {
+	authPayload, order, ok := server.getPurchaseOrder(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	if byBuyer && order.BuyerUsername != authPayload.Store {
+		err := errors.New("only the buyer can make this change")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if !byBuyer && order.SupplierUsername != authPayload.Store {
+		err := errors.New("only the supplier can make this change")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !util.CanTransitionPurchaseOrder(order.Status, status) {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrPurchaseOrderStatus))
+		return
+	}
+
+	order, err := server.store.UpdatePurchaseOrderStatus(c, db.UpdatePurchaseOrderStatusParams{
+		ID:         order.ID,
+		Status:     status,
+		FromStatus: order.Status,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, errorResponse(db.ErrPurchaseOrderStatus))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Purchase order %d %s by %s", order.ID, status, authPayload.Username)
+	c.JSON(http.StatusOK, order)
+}
+
+// ShipPurchaseOrder takes the ordered stock out of the supplier's inventory
+func (server *Server) ShipPurchaseOrder(c *gin.Context) {
+	authPayload, order, ok := server.getPurchaseOrder(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	if order.SupplierUsername != authPayload.Store {
+		err := errors.New("only the supplier can ship an order")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ShipPurchaseOrderTx(c, db.PurchaseOrderStockTxParams{
+		PurchaseOrderID: order.ID,
+		MovementType:    util.SaleMovement,
+		Reason:          "wholesale order shipped",
+		Reference:       purchaseOrderReference(order),
+		CreatedBy:       authPayload.Username,
+	})
+	if err != nil {
+		server.handlePurchaseOrderTxError(c, order, err)
+		return
+	}
+
+	util.LogInfo("Purchase order %d shipped by %s", order.ID, authPayload.Username)
+	c.JSON(http.StatusOK, result)
+}
+
+// ReceivePurchaseOrder adds the delivered goods to the buyer's inventory as
+// new batches
+func (server *Server) ReceivePurchaseOrder(c *gin.Context) {
+	authPayload, order, ok := server.getPurchaseOrder(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	if order.BuyerUsername != authPayload.Store {
+		err := errors.New("only the buyer can receive an order")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReceivePurchaseOrderTx(c, db.PurchaseOrderStockTxParams{
+		PurchaseOrderID: order.ID,
+		MovementType:    util.ReceiptMovement,
+		Reason:          "wholesale order received",
+		Reference:       purchaseOrderReference(order),
+		CreatedBy:       authPayload.Username,
+	})
+	if err != nil {
+		server.handlePurchaseOrderTxError(c, order, err)
+		return
+	}
+
+	util.LogInfo("Purchase order %d received by %s", order.ID, authPayload.Username)
+	c.JSON(http.StatusOK, result)
+}
+
+func (server *Server) handlePurchaseOrderTxError(c *gin.Context, order db.PurchaseOrder, err error) {
+	switch {
+	case errors.Is(err, db.ErrPurchaseOrderStatus),
+		errors.Is(err, db.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrRecordNotFound):
+		err := fmt.Errorf("an ordered medicine no longer exists: %w", err)
+		c.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		util.LogError("Failed to update stock for purchase order %d: %v", order.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// purchaseOrderReference ties the stock movements of an order back to it
+func purchaseOrderReference(order db.PurchaseOrder) string {
+	return fmt.Sprintf("purchase-order:%d", order.ID)
+}
@@ -0,0 +1,331 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// Opening hours are exchanged as "HH:MM" in the store's local time
+const openingHoursLayout = "15:04"
+
+type sellerLocation struct {
+	Pincode   pgtype.Text
+	Latitude  pgtype.Float8
+	Longitude pgtype.Float8
+}
+
+// resolveSellerLocation works out where a store is. Explicit coordinates win;
+// otherwise the pincode (given directly or found in the address) is looked up
+// in the pincode gazetteer. An unknown pincode is stored without coordinates.
+func (server *Server) resolveSellerLocation(c *gin.Context, pincode, address string, latitude, longitude *float64) (sellerLocation, error) {
+	var location sellerLocation
+
+	pincode = strings.TrimSpace(pincode)
+	if pincode == "" {
+		pincode = util.ExtractPincode(address)
+	}
+	if pincode != "" {
+		if !util.IsValidPincode(pincode) {
+			return location, errors.New("invalid pincode provided")
+		}
+		location.Pincode = pgtype.Text{String: pincode, Valid: true}
+	}
+
+	if (latitude == nil) != (longitude == nil) {
+		return location, errors.New("latitude and longitude must be provided together")
+	}
+
+	if latitude != nil {
+		if !util.IsValidCoordinates(*latitude, *longitude) {
+			return location, errors.New("invalid coordinates provided")
+		}
+		location.Latitude = pgtype.Float8{Float64: *latitude, Valid: true}
+		location.Longitude = pgtype.Float8{Float64: *longitude, Valid: true}
+		return location, nil
+	}
+
+	if !location.Pincode.Valid {
+		return location, nil
+	}
+
+	place, err := server.store.GetPincode(c, pincode)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			util.LogWarning("Pincode %s not found in gazetteer, storing without coordinates", pincode)
+			return location, nil
+		}
+		return location, err
+	}
+
+	location.Latitude = pgtype.Float8{Float64: place.Latitude, Valid: true}
+	location.Longitude = pgtype.Float8{Float64: place.Longitude, Valid: true}
+	return location, nil
+}
+
+type GetPincodeRequest struct {
+	Pincode string `uri:"pincode" binding:"required"`
+}
+
+func (server *Server) GetPincode(c *gin.Context) {
+	var req GetPincodeRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidPincode(req.Pincode) {
+		err := errors.New("invalid pincode provided")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	place, err := server.store.GetPincode(c, req.Pincode)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, place)
+}
+
+type openingHoursSlot struct {
+	DayOfWeek int16  `json:"day_of_week" binding:"min=0,max=6"`
+	OpensAt   string `json:"opens_at" binding:"required"`
+	ClosesAt  string `json:"closes_at" binding:"required"`
+}
+
+type UpdateSellerOpeningHoursRequest struct {
+	Hours []openingHoursSlot `json:"hours" binding:"dive"`
+}
+
+type ListSellerOpeningHoursRequest struct {
+	Username string `uri:"username" binding:"required"`
+}
+
+func newOpeningHoursResponse(hours []db.SellerOpeningHour) []openingHoursSlot {
+	rsp := make([]openingHoursSlot, 0, len(hours))
+	for _, h := range hours {
+		rsp = append(rsp, openingHoursSlot{
+			DayOfWeek: h.DayOfWeek,
+			OpensAt:   formatTimeOfDay(h.OpensAt),
+			ClosesAt:  formatTimeOfDay(h.ClosesAt),
+		})
+	}
+	return rsp
+}
+
+func parseTimeOfDay(value string) (pgtype.Time, error) {
+	t, err := time.Parse(openingHoursLayout, value)
+	if err != nil {
+		return pgtype.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	micros := int64(t.Hour())*int64(time.Hour/time.Microsecond) +
+		int64(t.Minute())*int64(time.Minute/time.Microsecond)
+	return pgtype.Time{Microseconds: micros, Valid: true}, nil
+}
+
+func formatTimeOfDay(t pgtype.Time) string {
+	return time.Time{}.Add(time.Duration(t.Microseconds) * time.Microsecond).Format(openingHoursLayout)
+}
+
+func (server *Server) ListSellerOpeningHours(c *gin.Context) {
+	var req ListSellerOpeningHoursRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hours, err := server.store.ListSellerOpeningHours(c, req.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newOpeningHoursResponse(hours))
+}
+
+// UpdateSellerOpeningHours replaces the weekly opening hours of the
+// logged-in seller.
+func (server *Server) UpdateSellerOpeningHours(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var req UpdateSellerOpeningHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ReplaceSellerOpeningHoursTxParams{
+		SellerUsername: authPayload.Store,
+		Hours:          make([]db.CreateSellerOpeningHoursParams, 0, len(req.Hours)),
+	}
+	for _, slot := range req.Hours {
+		opensAt, err := parseTimeOfDay(slot.OpensAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		closesAt, err := parseTimeOfDay(slot.ClosesAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if closesAt.Microseconds <= opensAt.Microseconds {
+			err := errors.New("closes_at must be after opens_at")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		arg.Hours = append(arg.Hours, db.CreateSellerOpeningHoursParams{
+			DayOfWeek: slot.DayOfWeek,
+			OpensAt:   opensAt,
+			ClosesAt:  closesAt,
+		})
+	}
+
+	hours, err := server.store.ReplaceSellerOpeningHoursTx(c, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			c.JSON(http.StatusConflict, errorResponse(errors.New("duplicate opening hours slot")))
+			return
+		}
+		util.LogError("Failed to update opening hours for seller %s: %v", authPayload.Store, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Opening hours updated for seller %s by %s", authPayload.Store, authPayload.Username)
+	c.JSON(http.StatusOK, newOpeningHoursResponse(hours))
+}
+
+type SearchNearbyPharmaciesRequest struct {
+	Medicine  string   `form:"medicine" binding:"required"`
+	Latitude  *float64 `form:"lat"`
+	Longitude *float64 `form:"lng"`
+	Pincode   string   `form:"pincode"`
+	RadiusKm  float64  `form:"radius_km" binding:"omitempty,min=0,max=100"`
+	Limit     int32    `form:"limit,default=10" binding:"min=1,max=100"`
+	Offset    int32    `form:"offset,default=0" binding:"min=0"`
+}
+
+type nearbyPharmacyResponse struct {
+	db.ListNearbySellersWithMedicineRow
+	// Delivers is true when the patient is inside the store's delivery radius
+	Delivers bool `json:"delivers"`
+}
+
+// SearchNearbyPharmacies lists stores near a point (coordinates or pincode)
+// that have the requested medicine in stock, nearest first.
+func (server *Server) SearchNearbyPharmacies(c *gin.Context) {
+	var req SearchNearbyPharmaciesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	location, err := server.resolveSellerLocation(c, req.Pincode, "", req.Latitude, req.Longitude)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !location.Latitude.Valid {
+		err := errors.New("a known pincode or lat and lng are required")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.listNearbyPharmacies(c, req, location)
+}
+
+// SearchNearbyPharmaciesForPatient runs the nearby search from the pincode
+// in the logged-in patient's address, unless a location is given explicitly.
+func (server *Server) SearchNearbyPharmaciesForPatient(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can search pharmacies near their address")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req SearchNearbyPharmaciesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	address := ""
+	if req.Pincode == "" && req.Latitude == nil {
+		patient, err := server.store.GetPatientByName(c, authPayload.Username)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		address = patient.Address
+	}
+
+	location, err := server.resolveSellerLocation(c, req.Pincode, address, req.Latitude, req.Longitude)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !location.Latitude.Valid {
+		err := errors.New("could not determine a location from your address, pass pincode or lat and lng")
+		c.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+
+	server.listNearbyPharmacies(c, req, location)
+}
+
+func (server *Server) listNearbyPharmacies(c *gin.Context, req SearchNearbyPharmaciesRequest, location sellerLocation) {
+	radiusKm := req.RadiusKm
+	if radiusKm == 0 {
+		radiusKm = util.DefaultSearchRadiusKm
+	}
+
+	arg := db.ListNearbySellersWithMedicineParams{
+		Latitude:      location.Latitude.Float64,
+		Longitude:     location.Longitude.Float64,
+		MaxDistanceKm: radiusKm,
+		Medicine:      strings.TrimSpace(req.Medicine),
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	}
+
+	rows, err := server.store.ListNearbySellersWithMedicine(c, arg)
+	if err != nil {
+		util.LogError("Failed to search nearby pharmacies: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]nearbyPharmacyResponse, 0, len(rows))
+	for _, row := range rows {
+		rsp = append(rsp, nearbyPharmacyResponse{
+			ListNearbySellersWithMedicineRow: row,
+			Delivers:                         row.DistanceKm <= row.DeliveryRadiusKm,
+		})
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
@@ -1,192 +1,455 @@
-package api
-
-import (
-	"context"
-	"fmt"
-	"log"
-	"net/http"
-	"time"
-
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
-	"github.com/pawaspy/MediBridge/ai_agent"
-	db "github.com/pawaspy/MediBridge/db/sqlc"
-	"github.com/pawaspy/MediBridge/mail"
-	"github.com/pawaspy/MediBridge/token"
-	"github.com/pawaspy/MediBridge/util"
-)
-
-type Server struct {
-	config        util.Config
-	store         db.Store
-	router        *gin.Engine
-	tokenMaker    token.Maker
-	mailer        *mail.Mailer
-	expiryChecker *mail.ExpiryChecker
-	alizaHandler  *ai_agent.Handler
-}
-
-func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
-	if err != nil {
-		return nil, fmt.Errorf("cannot create token: %w", err)
-	}
-
-	// Initialize the mailer
-	mailer, err := mail.NewMailer(config)
-	if err != nil {
-		return nil, fmt.Errorf("cannot create mailer: %w", err)
-	}
-
-	// Initialize the expiry checker
-	expiryChecker := mail.NewExpiryChecker(store, mailer, config)
-
-	// Initialize Aliza AI agent handler
-	alizaHandler := ai_agent.NewHandler(ai_agent.NewAliza(store))
-
-	server := &Server{
-		config:        config,
-		store:         store,
-		tokenMaker:    tokenMaker,
-		mailer:        mailer,
-		expiryChecker: expiryChecker,
-		alizaHandler:  alizaHandler,
-	}
-
-	server.setupRouter()
-
-	return server, nil
-}
-
-func (server *Server) setupRouter() {
-	router := gin.Default()
-
-	// Add CORS middleware
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
-
-	// Add a debug endpoint for testing seller creation
-	router.GET("/api/test/seller", func(c *gin.Context) {
-		util.LogInfo("Test seller creation endpoint called")
-
-		// Create a test seller with hardcoded values
-		hashedPassword, err := util.HashPassword("testpassword")
-		if err != nil {
-			util.LogError("Failed to hash password: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("Failed to hash password: %v", err),
-			})
-			return
-		}
-
-		testSeller := db.CreateSellerParams{
-			Username:          "test_seller_" + fmt.Sprint(time.Now().Unix()),
-			FullName:          "Test Seller",
-			Email:             "test" + fmt.Sprint(time.Now().Unix()) + "@example.com",
-			Password:          hashedPassword,
-			MobileNumber:      "1234567890",
-			StoreName:         "Test Store",
-			GstNumber:         "TEST123456",
-			DrugLicenseNumber: "DL123456",
-			SellerType:        "retail",
-			StoreAddress:      "Test Address",
-		}
-
-		util.LogInfo("Creating test seller: %+v", testSeller)
-
-		seller, err := server.store.CreateSeller(c, testSeller)
-		if err != nil {
-			util.LogError("Failed to create test seller: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("Failed to create test seller: %v", err),
-			})
-			return
-		}
-
-		util.LogInfo("Successfully created test seller")
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Test seller created successfully",
-			"seller":  seller,
-		})
-	})
-
-	// Setup routes
-	publicRoutes := router.Group("/api")
-	authRoutes := router.Group("/api").Use(authMiddleware(server.tokenMaker))
-
-	// Auth routes
-	publicRoutes.POST("/patients", server.CreatePatient)
-	publicRoutes.POST("/loginpatient", server.LoginPatient)
-	publicRoutes.POST("/doctors", server.CreateDoctor)
-	publicRoutes.POST("/logindoctor", server.LoginDoctor)
-	publicRoutes.POST("/sellers", server.CreateSeller)
-	publicRoutes.POST("/loginseller", server.LoginSeller)
-	publicRoutes.POST("/reset-patient-password", server.ResetPatientPassword)
-
-	// Patient routes
-	publicRoutes.GET("/patients/:username", server.GetPatient)
-	authRoutes.PUT("/patients", server.UpdatePatient)
-	authRoutes.DELETE("/patients/:username", server.DeletePatient)
-
-	// Patient Profile routes
-	publicRoutes.GET("/patient-profiles/:username", server.GetPatientProfile)
-	authRoutes.POST("/patient-profiles", server.CreatePatientProfile)
-	authRoutes.PUT("/patient-profiles", server.UpdatePatientProfile)
-	authRoutes.DELETE("/patient-profiles/:username", server.DeletePatientProfile)
-	authRoutes.GET("/patient-profiles", server.ListPatientProfiles)
-
-	// Doctor routes
-	publicRoutes.GET("/doctors/:username", server.GetDoctor)
-	authRoutes.PUT("/doctors", server.UpdateDoctor)
-	authRoutes.DELETE("/doctors/:username", server.DeleteDoctor)
-
-	// Seller routes
-	publicRoutes.GET("/sellers/:username", server.GetSeller)
-	authRoutes.PUT("/sellers", server.UpdateSeller)
-	authRoutes.DELETE("/sellers/:username", server.DeleteSeller)
-
-	// Medicine routes
-	publicRoutes.GET("/medicines/:id", server.GetMedicine)
-	publicRoutes.GET("/medicines/search", server.SearchMedicinesByNameSortedByPrice)
-	publicRoutes.GET("/sellers/:username/medicines", server.ListSellerMedicinesByExpiry)
-	authRoutes.POST("/medicines", server.CreateMedicine)
-	authRoutes.PUT("/medicines", server.UpdateMedicine)
-	authRoutes.DELETE("/medicines/:id", server.DeleteMedicine)
-
-	// Cart routes
-	authRoutes.POST("/cart", server.AddToCart)
-	authRoutes.GET("/cart", server.GetCartItems)
-	authRoutes.PUT("/cart/:id", server.UpdateCartItem)
-	authRoutes.DELETE("/cart/:id", server.DeleteCartItem)
-	authRoutes.DELETE("/cart", server.ClearCart)
-	authRoutes.GET("/cart/count", server.GetCartCount)
-
-	// Aliza AI agent routes
-	alizaRoutes := publicRoutes.Group("/aliza")
-	server.alizaHandler.RegisterRoutes(alizaRoutes)
-
-	server.router = router
-}
-
-func (server *Server) Start(address string) error {
-	// Start the medicine expiry checker in a background goroutine
-	ctx := context.Background()
-	server.expiryChecker.StartExpiryCheckScheduler(ctx)
-	log.Printf("Medicine expiry checker scheduled to run")
-
-	return server.router.Run(address)
-}
-
-func errorResponse(err error) gin.H {
-	return gin.H{
-		"error": gin.H{
-			"message": err.Error(),
-		},
-	}
-}
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/pawaspy/MediBridge/ai_agent"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/mail"
+	"github.com/pawaspy/MediBridge/payment"
+	"github.com/pawaspy/MediBridge/storage"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type Server struct {
+	config          util.Config
+	store           db.Store
+	router          *gin.Engine
+	tokenMaker      token.Maker
+	mailer          *mail.Mailer
+	expiryChecker   *mail.ExpiryChecker
+	markdownApplier *mail.MarkdownApplier
+	lowStockChecker *mail.LowStockChecker
+	payoutRunner    *mail.PayoutRunner
+	reminderJob     *mail.AppointmentReminderJob
+	consultationHub *consultationHub
+	alizaHandler    *ai_agent.Handler
+	documentStorage storage.Storage
+	virusScanner    storage.Scanner
+	paymentProvider payment.Provider
+
+	// Checks the origin of consultation chat handshakes
+	consultationUpgrader websocket.Upgrader
+}
+
+func NewServer(config util.Config, store db.Store) (*Server, error) {
+	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token: %w", err)
+	}
+
+	// Initialize the mailer
+	mailer, err := mail.NewMailer(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mailer: %w", err)
+	}
+
+	// Initialize the expiry checker
+	expiryChecker := mail.NewExpiryChecker(store, mailer, config)
+
+	// Initialize the near-expiry markdown job
+	markdownApplier := mail.NewMarkdownApplier(store, mailer, config)
+
+	// Initialize the low-stock alert job
+	lowStockChecker := mail.NewLowStockChecker(store, mailer, config)
+
+	// Initialize the seller payout job
+	payoutRunner := mail.NewPayoutRunner(store, mailer, config)
+
+	// Initialize the appointment reminder job
+	reminderJob := mail.NewAppointmentReminderJob(store, mailer, config)
+
+	// Initialize the storage of patients' medical record vaults
+	documentStorage, err := storage.NewLocalStorage(config.DocumentStorageDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create document storage: %w", err)
+	}
+
+	// Initialize the payment provider; payments are refused until it is
+	// configured
+	var paymentProvider payment.Provider = payment.Unconfigured{}
+	if config.PaymentSecretKey != "" {
+		paymentProvider = payment.NewStripeProvider(config.PaymentSecretKey, config.PaymentWebhookSecret)
+	}
+
+	// Initialize Aliza AI agent handler
+	alizaHandler := ai_agent.NewHandler(ai_agent.NewAliza(store))
+
+	server := &Server{
+		config:          config,
+		store:           store,
+		tokenMaker:      tokenMaker,
+		mailer:          mailer,
+		expiryChecker:   expiryChecker,
+		markdownApplier: markdownApplier,
+		lowStockChecker: lowStockChecker,
+		payoutRunner:    payoutRunner,
+		reminderJob:     reminderJob,
+		consultationHub: newConsultationHub(),
+		documentStorage: documentStorage,
+		virusScanner:    storage.NoopScanner{},
+		paymentProvider: paymentProvider,
+		alizaHandler:    alizaHandler,
+
+		consultationUpgrader: newConsultationUpgrader(config.FrontendOrigins),
+	}
+
+	server.setupRouter()
+
+	return server, nil
+}
+
+func (server *Server) setupRouter() {
+	router := gin.Default()
+
+	// Add CORS middleware
+	router.Use(cors.New(cors.Config{
+		AllowOrigins:     []string{"*"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		ExposeHeaders:    []string{"Content-Length"},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+	}))
+
+	// Add a debug endpoint for testing seller creation
+	router.GET("/api/test/seller", func(c *gin.Context) {
+		util.LogInfo("Test seller creation endpoint called")
+
+		// Create a test seller with hardcoded values
+		hashedPassword, err := util.HashPassword("testpassword")
+		if err != nil {
+			util.LogError("Failed to hash password: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to hash password: %v", err),
+			})
+			return
+		}
+
+		testSeller := db.CreateSellerParams{
+			Username:          "test_seller_" + fmt.Sprint(time.Now().Unix()),
+			FullName:          "Test Seller",
+			Email:             "test" + fmt.Sprint(time.Now().Unix()) + "@example.com",
+			Password:          hashedPassword,
+			MobileNumber:      "1234567890",
+			StoreName:         "Test Store",
+			GstNumber:         "TEST123456",
+			DrugLicenseNumber: "DL123456",
+			SellerType:        "retail",
+			StoreAddress:      "Test Address",
+			DeliveryRadiusKm:  util.DefaultDeliveryRadiusKm,
+		}
+
+		util.LogInfo("Creating test seller: %+v", testSeller)
+
+		seller, err := server.store.CreateSeller(c, testSeller)
+		if err != nil {
+			util.LogError("Failed to create test seller: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to create test seller: %v", err),
+			})
+			return
+		}
+
+		util.LogInfo("Successfully created test seller")
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Test seller created successfully",
+			"seller":  seller,
+		})
+	})
+
+	// Setup routes
+	publicRoutes := router.Group("/api")
+	authRoutes := router.Group("/api").Use(authMiddleware(server.tokenMaker))
+	websocketRoutes := router.Group("/api").Use(websocketAuthMiddleware(server.tokenMaker))
+
+	// Auth routes
+	publicRoutes.POST("/patients", server.CreatePatient)
+	publicRoutes.POST("/loginpatient", server.LoginPatient)
+	publicRoutes.POST("/doctors", server.CreateDoctor)
+	publicRoutes.POST("/logindoctor", server.LoginDoctor)
+	publicRoutes.POST("/sellers", server.CreateSeller)
+	publicRoutes.POST("/loginseller", server.LoginSeller)
+	publicRoutes.POST("/reset-patient-password", server.ResetPatientPassword)
+
+	// Patient routes
+	publicRoutes.GET("/patients/:username", server.GetPatient)
+	authRoutes.PUT("/patients", server.UpdatePatient)
+	authRoutes.DELETE("/patients/:username", server.DeletePatient)
+
+	// Patient Profile routes
+	publicRoutes.GET("/patient-profiles/:username", server.GetPatientProfile)
+	authRoutes.POST("/patient-profiles", server.CreatePatientProfile)
+	authRoutes.PUT("/patient-profiles", server.UpdatePatientProfile)
+	authRoutes.DELETE("/patient-profiles/:username", server.DeletePatientProfile)
+	authRoutes.GET("/patient-profiles", server.ListPatientProfiles)
+
+	// Allergy and chronic condition routes
+	publicRoutes.GET("/clinical-terms", server.SearchClinicalTerms)
+	authRoutes.GET("/patients/allergies", server.ListPatientAllergies)
+	authRoutes.POST("/patients/allergies", server.CreatePatientAllergy)
+	authRoutes.PUT("/patients/allergies/:id", server.UpdatePatientAllergy)
+	authRoutes.DELETE("/patients/allergies/:id", server.DeletePatientAllergy)
+	authRoutes.GET("/patients/conditions", server.ListPatientConditions)
+	authRoutes.POST("/patients/conditions", server.CreatePatientCondition)
+	authRoutes.PUT("/patients/conditions/:id", server.UpdatePatientCondition)
+	authRoutes.DELETE("/patients/conditions/:id", server.DeletePatientCondition)
+
+	// Medical record vault routes
+	authRoutes.POST("/documents", server.UploadDocument)
+	authRoutes.GET("/documents", server.ListDocuments)
+	authRoutes.GET("/documents/:id", server.GetDocument)
+	authRoutes.PUT("/documents/:id", server.UpdateDocument)
+	authRoutes.DELETE("/documents/:id", server.DeleteDocument)
+	authRoutes.GET("/documents/:id/file", server.DownloadDocument)
+	authRoutes.POST("/documents/shares", server.ShareDocuments)
+	authRoutes.GET("/documents/shares", server.ListDocumentShares)
+	authRoutes.DELETE("/documents/shares/:id", server.RevokeDocumentShare)
+	authRoutes.GET("/doctors/shared-documents", server.ListSharedDocuments)
+
+	// Doctor routes
+	publicRoutes.GET("/doctors", server.SearchDoctors)
+	publicRoutes.GET("/doctors/:username", server.GetDoctor)
+	authRoutes.PUT("/doctors", server.UpdateDoctor)
+	authRoutes.DELETE("/doctors/:username", server.DeleteDoctor)
+
+	// Doctor schedule routes
+	publicRoutes.GET("/doctors/:username/availability", server.GetDoctorAvailability)
+	publicRoutes.GET("/doctors/:username/slots", server.ListDoctorSlots)
+	authRoutes.PUT("/doctors/availability", server.UpdateDoctorAvailability)
+	authRoutes.GET("/doctors/schedule", server.ListDoctorSchedule)
+	authRoutes.POST("/doctors/availability/exceptions", server.CreateAvailabilityException)
+	authRoutes.DELETE("/doctors/availability/exceptions/:id", server.DeleteAvailabilityException)
+	authRoutes.POST("/doctors/leaves", server.CreateDoctorLeave)
+	authRoutes.DELETE("/doctors/leaves/:id", server.DeleteDoctorLeave)
+	authRoutes.GET("/doctors/appointments", server.GetDoctorDay)
+	authRoutes.PUT("/doctors/cancellation-window", server.UpdateCancellationWindow)
+	authRoutes.PUT("/doctors/fees", server.UpdateDoctorFees)
+
+	// Appointment routes
+	authRoutes.POST("/appointments", server.BookAppointment)
+	authRoutes.GET("/appointments", server.ListPatientAppointments)
+	authRoutes.GET("/appointments/:id", server.GetAppointment)
+	authRoutes.PUT("/appointments/:id/reschedule", server.RescheduleAppointment)
+	authRoutes.POST("/appointments/:id/cancel", server.CancelAppointment)
+	authRoutes.POST("/appointments/:id/payment", server.CreateAppointmentPayment)
+	authRoutes.POST("/appointments/:id/payment/confirm", server.ConfirmAppointmentPayment)
+	authRoutes.GET("/appointments/:id/payments", server.ListAppointmentPayments)
+	authRoutes.POST("/appointments/:id/check-in", server.CheckInAppointment)
+
+	// Teleconsultation routes
+	authRoutes.POST("/appointments/:id/consultation", server.StartConsultation)
+	authRoutes.GET("/appointments/:id/consultation", server.GetAppointmentConsultation)
+	authRoutes.GET("/consultations/:id", server.GetConsultation)
+	authRoutes.GET("/consultations/:id/messages", server.ListConsultationMessages)
+	authRoutes.POST("/consultations/:id/attachments", server.SendConsultationAttachment)
+	authRoutes.GET("/consultations/:id/attachments/:attachment_id", server.DownloadConsultationAttachment)
+	authRoutes.POST("/consultations/:id/close", server.CloseConsultation)
+	websocketRoutes.GET("/consultations/:id/ws", server.ConsultationChat)
+
+	// Visit note routes
+	publicRoutes.GET("/icd10-codes", server.SearchICD10Codes)
+	authRoutes.PUT("/appointments/:id/visit-note", server.SaveVisitNote)
+	authRoutes.GET("/appointments/:id/visit-note", server.GetVisitNote)
+	authRoutes.POST("/appointments/:id/visit-note/finalize", server.FinalizeVisitNote)
+	authRoutes.GET("/patients/visits", server.ListVisitSummaries)
+	authRoutes.GET("/doctors/patients/:username/history", server.GetPatientHistory)
+
+	// Doctor review routes
+	publicRoutes.GET("/doctors/:username/reviews", server.ListDoctorReviews)
+	authRoutes.POST("/appointments/:id/review", server.CreateDoctorReview)
+	authRoutes.GET("/appointments/:id/review", server.GetAppointmentReview)
+	authRoutes.POST("/doctors/reviews/:id/report", server.ReportDoctorReview)
+
+	// Seller and medicine review routes
+	publicRoutes.GET("/sellers/:username/reviews", server.ListSellerReviews)
+	publicRoutes.GET("/medicines/:id/reviews", server.ListMedicineReviews)
+	authRoutes.POST("/sub-orders/:id/review", server.CreateSellerReview)
+	authRoutes.GET("/sub-orders/:id/review", server.GetSubOrderReview)
+	authRoutes.POST("/order-items/:id/review", server.CreateMedicineReview)
+	authRoutes.GET("/order-items/:id/review", server.GetOrderItemReview)
+	authRoutes.PUT("/sellers/reviews/:id/reply", server.ReplyToSellerReview)
+	authRoutes.PUT("/sellers/medicine-reviews/:id/reply", server.ReplyToMedicineReview)
+
+	// Seller routes
+	publicRoutes.GET("/sellers/:username", server.GetSeller)
+	authRoutes.PUT("/sellers", server.UpdateSeller)
+	authRoutes.DELETE("/sellers/:username", server.DeleteSeller)
+	publicRoutes.GET("/sellers/:username/opening-hours", server.ListSellerOpeningHours)
+	authRoutes.PUT("/sellers/opening-hours", server.UpdateSellerOpeningHours)
+
+	// Location routes
+	publicRoutes.GET("/pincodes/:pincode", server.GetPincode)
+	publicRoutes.GET("/pharmacies/nearby", server.SearchNearbyPharmacies)
+	authRoutes.GET("/patients/nearby-pharmacies", server.SearchNearbyPharmaciesForPatient)
+
+	// Medicine routes
+	publicRoutes.GET("/medicines/:id", server.GetMedicine)
+	publicRoutes.GET("/medicines/search", server.SearchMedicines)
+	publicRoutes.GET("/medicines/search/facets", server.SearchMedicineFacets)
+	publicRoutes.GET("/medicines/:id/substitutes", server.ListMedicineSubstitutes)
+	publicRoutes.GET("/sellers/:username/medicines", server.ListSellerMedicinesByExpiry)
+	authRoutes.POST("/medicines", server.CreateMedicine)
+	authRoutes.PUT("/medicines", server.UpdateMedicine)
+	authRoutes.DELETE("/medicines/:id", server.DeleteMedicine)
+	authRoutes.POST("/medicines/import", server.ImportMedicines)
+	authRoutes.GET("/medicines/imports", server.ListMedicineImportJobs)
+	authRoutes.GET("/medicines/imports/:id", server.GetMedicineImportJob)
+	authRoutes.GET("/medicines/imports/:id/errors", server.DownloadMedicineImportErrors)
+	authRoutes.GET("/medicines/export", server.ExportMedicines)
+	authRoutes.POST("/medicines/:id/stock", server.RecordStockMovement)
+	authRoutes.GET("/medicines/:id/stock", server.ListStockMovements)
+	authRoutes.GET("/sellers/stock/reconciliation", server.GetStockReconciliation)
+	authRoutes.GET("/sellers/quarantine", server.ListQuarantinedMedicines)
+	authRoutes.GET("/sellers/disposals", server.ListMedicineDisposals)
+	authRoutes.POST("/medicines/:id/disposal", server.DisposeMedicine)
+
+	// Markdown rule routes
+	authRoutes.POST("/sellers/markdown-rules", server.CreateMarkdownRule)
+	authRoutes.GET("/sellers/markdown-rules", server.ListMarkdownRules)
+	authRoutes.PUT("/sellers/markdown-rules/:id", server.UpdateMarkdownRule)
+	authRoutes.DELETE("/sellers/markdown-rules/:id", server.DeleteMarkdownRule)
+	authRoutes.GET("/sellers/markdown-events", server.ListMarkdownEvents)
+
+	// Donation marketplace routes
+	authRoutes.POST("/medicines/:id/donation-offer", server.CreateDonationOffer)
+	authRoutes.GET("/donation-offers", server.ListDonationOffers)
+	authRoutes.DELETE("/donation-offers/:id", server.WithdrawDonationOffer)
+	authRoutes.POST("/donation-offers/:id/claims", server.ClaimDonationOffer)
+	authRoutes.GET("/sellers/donation-offers", server.ListSellerDonationOffers)
+	authRoutes.GET("/sellers/donation-claims", server.ListDonationClaims)
+
+	// Wholesale ordering routes
+	authRoutes.PUT("/medicines/:id/trade-pricing", server.SetTradePricing)
+	authRoutes.GET("/wholesale/catalogue", server.ListWholesaleCatalogue)
+	authRoutes.POST("/purchase-orders", server.CreatePurchaseOrder)
+	authRoutes.GET("/purchase-orders", server.ListPurchaseOrders)
+	authRoutes.GET("/purchase-orders/:id", server.GetPurchaseOrder)
+	authRoutes.POST("/purchase-orders/:id/accept", server.AcceptPurchaseOrder)
+	authRoutes.POST("/purchase-orders/:id/reject", server.RejectPurchaseOrder)
+	authRoutes.POST("/purchase-orders/:id/cancel", server.CancelPurchaseOrder)
+	authRoutes.POST("/purchase-orders/:id/ship", server.ShipPurchaseOrder)
+	authRoutes.POST("/purchase-orders/:id/receive", server.ReceivePurchaseOrder)
+
+	// Low stock routes
+	authRoutes.PUT("/medicines/:id/reorder-level", server.SetReorderLevel)
+	authRoutes.GET("/sellers/low-stock", server.ListLowStockMedicines)
+
+	// Cart routes
+	authRoutes.POST("/cart", server.AddToCart)
+	authRoutes.GET("/cart", server.GetCartItems)
+	authRoutes.PUT("/cart/:id", server.UpdateCartItem)
+	authRoutes.DELETE("/cart/:id", server.DeleteCartItem)
+	authRoutes.DELETE("/cart", server.ClearCart)
+	authRoutes.GET("/cart/count", server.GetCartCount)
+	authRoutes.POST("/cart/checkout", server.CheckoutCart)
+
+	// Payment provider notifications, authenticated by their signature
+	publicRoutes.POST("/payments/webhook", server.PaymentWebhook)
+
+	// Order routes
+	authRoutes.GET("/orders", server.ListPatientOrders)
+	authRoutes.GET("/orders/:id", server.GetOrder)
+	authRoutes.POST("/orders/:id/payment", server.CreateOrderPayment)
+	authRoutes.POST("/orders/:id/payment/confirm", server.ConfirmOrderPayment)
+	authRoutes.GET("/sellers/orders", server.ListSellerSubOrders)
+	authRoutes.PUT("/sub-orders/:id/status", server.UpdateSubOrderStatus)
+	authRoutes.POST("/sub-orders/:id/refund", server.RefundSubOrder)
+
+	// Seller settlement routes
+	authRoutes.GET("/sellers/balance", server.GetSellerBalance)
+	authRoutes.GET("/sellers/payouts", server.ListSellerPayouts)
+	authRoutes.GET("/sellers/payouts/:id", server.GetSellerPayout)
+	authRoutes.GET("/sellers/ledger", server.ListSellerLedger)
+
+	// Seller analytics routes
+	authRoutes.GET("/sellers/analytics/sales", server.GetSalesAnalytics)
+	authRoutes.GET("/sellers/analytics/top-medicines", server.GetTopMedicines)
+	authRoutes.GET("/sellers/analytics/summary", server.GetAnalyticsSummary)
+
+	// Store staff routes
+	publicRoutes.POST("/loginstaff", server.LoginStaff)
+	publicRoutes.POST("/staff/accept-invite", server.AcceptStaffInvite)
+	authRoutes.POST("/sellers/staff/invites", server.InviteStaff)
+	authRoutes.GET("/sellers/staff/invites", server.ListStaffInvites)
+	authRoutes.DELETE("/sellers/staff/invites/:id", server.RevokeStaffInvite)
+	authRoutes.GET("/sellers/staff", server.ListStoreStaff)
+	authRoutes.PUT("/sellers/staff/:username", server.UpdateStaffRole)
+	authRoutes.DELETE("/sellers/staff/:username", server.RemoveStoreStaff)
+
+	// Organization (multi-branch chain) routes
+	authRoutes.POST("/sellers/organization", server.CreateOrganization)
+	authRoutes.GET("/sellers/organization", server.GetStoreOrganization)
+	authRoutes.PUT("/sellers/organization", server.UpdateOrganization)
+	authRoutes.POST("/sellers/organization/branches", server.CreateBranch)
+	authRoutes.DELETE("/sellers/organization/branches/:username", server.RemoveBranch)
+	authRoutes.GET("/sellers/organization/reports/branches", server.GetOrganizationBranchReport)
+	authRoutes.GET("/sellers/organization/reports/sales", server.GetOrganizationSales)
+	authRoutes.GET("/sellers/organization/stock-transfers", server.ListOrganizationStockTransfers)
+
+	// Inter-branch stock transfer routes
+	authRoutes.POST("/stock-transfers", server.CreateStockTransfer)
+	authRoutes.GET("/stock-transfers", server.ListStockTransfers)
+	authRoutes.GET("/stock-transfers/:id", server.GetStockTransfer)
+	authRoutes.POST("/stock-transfers/:id/dispatch", server.DispatchStockTransfer)
+	authRoutes.POST("/stock-transfers/:id/reject", server.RejectStockTransfer)
+	authRoutes.POST("/stock-transfers/:id/cancel", server.CancelStockTransfer)
+	authRoutes.POST("/stock-transfers/:id/receive", server.ReceiveStockTransfer)
+
+	// Admin routes
+	publicRoutes.POST("/loginadmin", server.LoginAdmin)
+	authRoutes.GET("/admin/sellers/unverified", server.ListUnverifiedSellers)
+	authRoutes.PUT("/admin/sellers/:username/verify", server.VerifySeller)
+	authRoutes.GET("/admin/commission-rates", server.ListCommissionRates)
+	authRoutes.PUT("/admin/commission-rates/:seller_type", server.UpdateCommissionRate)
+	authRoutes.POST("/admin/sub-orders/:id/chargebacks", server.ReportChargeback)
+	authRoutes.GET("/admin/doctor-reviews", server.ListDoctorReviewModerationQueue)
+	authRoutes.PUT("/admin/doctor-reviews/:id/moderation", server.ModerateDoctorReview)
+	authRoutes.GET("/admin/seller-reviews", server.ListPendingSellerReviews)
+	authRoutes.PUT("/admin/seller-reviews/:id/moderation", server.ModerateSellerReview)
+	authRoutes.GET("/admin/medicine-reviews", server.ListPendingMedicineReviews)
+	authRoutes.PUT("/admin/medicine-reviews/:id/moderation", server.ModerateMedicineReview)
+
+	// Aliza AI agent routes
+	alizaRoutes := publicRoutes.Group("/aliza", optionalAuthMiddleware(server.tokenMaker), alizaPatientMiddleware)
+	server.alizaHandler.RegisterRoutes(alizaRoutes)
+
+	server.router = router
+}
+
+func (server *Server) Start(address string) error {
+	// Start the medicine expiry checker in a background goroutine
+	ctx := context.Background()
+	server.expiryChecker.StartExpiryCheckScheduler(ctx)
+	log.Printf("Medicine expiry checker scheduled to run")
+	server.markdownApplier.StartMarkdownScheduler(ctx)
+	log.Printf("Markdown pricing job scheduled to run")
+	server.lowStockChecker.StartLowStockScheduler(ctx)
+	log.Printf("Low stock check scheduled to run")
+	server.payoutRunner.StartPayoutScheduler(ctx)
+	log.Printf("Seller payouts scheduled to run")
+	server.reminderJob.StartAppointmentReminderScheduler(ctx)
+	log.Printf("Appointment reminders scheduled to run")
+	server.startAnalyticsRefresher(ctx)
+	log.Printf("Analytics refresh scheduled to run")
+	server.startOrderExpiry(ctx)
+	log.Printf("Unpaid order expiry scheduled to run")
+
+	return server.router.Run(address)
+}
+
+func errorResponse(err error) gin.H {
+	return gin.H{
+		"error": gin.H{
+			"message": err.Error(),
+		},
+	}
+}
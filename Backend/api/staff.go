@@ -0,0 +1,467 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/mail"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type InviteStaffRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	StaffRole string `json:"staff_role" binding:"required"`
+}
+
+type StaffInviteIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type AcceptStaffInviteRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Username string `json:"username" binding:"required,alphanum"`
+	FullName string `json:"full_name" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type StaffUsernameRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type UpdateStaffRoleRequest struct {
+	StaffRole string `json:"staff_role" binding:"required"`
+}
+
+type loginStaffRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type staffResponse struct {
+	Username       string           `json:"username"`
+	SellerUsername string           `json:"seller_username"`
+	FullName       string           `json:"full_name"`
+	Email          string           `json:"email"`
+	StaffRole      string           `json:"staff_role"`
+	InvitedBy      string           `json:"invited_by"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
+func newStaffResponse(staff db.StoreStaff) staffResponse {
+	return staffResponse{
+		Username:       staff.Username,
+		SellerUsername: staff.SellerUsername,
+		FullName:       staff.FullName,
+		Email:          staff.Email,
+		StaffRole:      staff.StaffRole,
+		InvitedBy:      staff.InvitedBy,
+		CreatedAt:      staff.CreatedAt,
+	}
+}
+
+type staffInviteResponse struct {
+	ID             int32            `json:"id"`
+	SellerUsername string           `json:"seller_username"`
+	Email          string           `json:"email"`
+	StaffRole      string           `json:"staff_role"`
+	InvitedBy      string           `json:"invited_by"`
+	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
+	AcceptedAt     pgtype.Timestamp `json:"accepted_at"`
+	RevokedAt      pgtype.Timestamp `json:"revoked_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
+func newStaffInviteResponse(invite db.StaffInvite) staffInviteResponse {
+	return staffInviteResponse{
+		ID:             invite.ID,
+		SellerUsername: invite.SellerUsername,
+		Email:          invite.Email,
+		StaffRole:      invite.StaffRole,
+		InvitedBy:      invite.InvitedBy,
+		ExpiresAt:      invite.ExpiresAt,
+		AcceptedAt:     invite.AcceptedAt,
+		RevokedAt:      invite.RevokedAt,
+		CreatedAt:      invite.CreatedAt,
+	}
+}
+
+type loginStaffResponse struct {
+	AccessToken          string        `json:"access_token"`
+	AccessTokenExpiresAt time.Time     `json:"access_token_expires_at"`
+	Staff                staffResponse `json:"staff"`
+}
+
+var staffRoleNames = map[string]string{
+	util.OwnerStaff:          "an owner",
+	util.PharmacistStaff:     "a pharmacist",
+	util.InventoryClerkStaff: "an inventory clerk",
+}
+
+// authorizeStore checks that the logged-in user works for a store and that
+// allowed accepts their staff role (any role when allowed is nil), writing
+// the error response itself when they don't. Staff accounts are looked up
+// on every request so that removing someone or changing their role applies
+// at once. The returned payload carries the current staff role.
+func (server *Server) authorizeStore(c *gin.Context, allowed func(staffRole string) bool) (*token.Payload, bool) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Seller || authPayload.Store == "" {
+		err := errors.New("only store staff can access this resource")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, false
+	}
+
+	payload := *authPayload
+	if payload.Username != payload.Store {
+		staff, err := server.store.GetStoreStaff(c, payload.Username)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				err := errors.New("staff account no longer exists")
+				c.JSON(http.StatusUnauthorized, errorResponse(err))
+				return nil, false
+			}
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return nil, false
+		}
+		if staff.SellerUsername != payload.Store {
+			err := errors.New("staff account doesn't belong to this store")
+			c.JSON(http.StatusUnauthorized, errorResponse(err))
+			return nil, false
+		}
+		payload.StaffRole = staff.StaffRole
+	}
+
+	if allowed != nil && !allowed(payload.StaffRole) {
+		err := fmt.Errorf("the %s role can't access this resource", payload.StaffRole)
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, false
+	}
+
+	return &payload, true
+}
+
+// InviteStaff emails an invite to join the store with the given role
+func (server *Server) InviteStaff(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var req InviteStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidStaffRole(req.StaffRole) {
+		err := errors.New("staff role must be owner, pharmacist or inventory_clerk")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	inviteToken, err := util.NewInviteToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+	err = server.store.RevokeExpiredStaffInvites(c, db.RevokeExpiredStaffInvitesParams{
+		SellerUsername: seller.Username,
+		Email:          email,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	invite, err := server.store.CreateStaffInvite(c, db.CreateStaffInviteParams{
+		SellerUsername: seller.Username,
+		Email:          email,
+		StaffRole:      req.StaffRole,
+		TokenHash:      util.HashInviteToken(inviteToken),
+		InvitedBy:      authPayload.Username,
+		ExpiresAt: pgtype.Timestamp{
+			Time:  time.Now().Add(server.config.StaffInviteDuration),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("this email already has a pending invite")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.mailer.SendStaffInviteEmail(invite.Email, mail.StaffInviteData{
+		StoreName: seller.StoreName,
+		InvitedBy: authPayload.Username,
+		RoleName:  staffRoleNames[invite.StaffRole],
+		Token:     inviteToken,
+		ExpiresAt: invite.ExpiresAt.Time.Format("2006-01-02 15:04"),
+	})
+	if err != nil {
+		util.LogError("Failed to send staff invite %d to %s: %v", invite.ID, invite.Email, err)
+		// Don't leave an invite behind that nobody received
+		if _, revokeErr := server.store.RevokeStaffInvite(c, db.RevokeStaffInviteParams{
+			ID:             invite.ID,
+			SellerUsername: seller.Username,
+		}); revokeErr != nil {
+			util.LogError("Failed to revoke unsent staff invite %d: %v", invite.ID, revokeErr)
+		}
+		err := errors.New("failed to send invite email")
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Staff invite %d sent to %s for %s as %s", invite.ID, invite.Email, seller.Username, invite.StaffRole)
+	c.JSON(http.StatusOK, newStaffInviteResponse(invite))
+}
+
+// ListStaffInvites lists the store's invites that are still pending
+func (server *Server) ListStaffInvites(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	invites, err := server.store.ListPendingStaffInvites(c, authPayload.Store)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]staffInviteResponse, len(invites))
+	for i, invite := range invites {
+		rsp[i] = newStaffInviteResponse(invite)
+	}
+	c.JSON(http.StatusOK, rsp)
+}
+
+// RevokeStaffInvite cancels an invite that hasn't been accepted yet
+func (server *Server) RevokeStaffInvite(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var uri StaffInviteIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	invite, err := server.store.RevokeStaffInvite(c, db.RevokeStaffInviteParams{
+		ID:             uri.ID,
+		SellerUsername: authPayload.Store,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("pending invite not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newStaffInviteResponse(invite))
+}
+
+// AcceptStaffInvite creates a staff account from an emailed invite. The
+// account gets the invited email and role.
+func (server *Server) AcceptStaffInvite(c *gin.Context) {
+	var req AcceptStaffInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Staff and store logins share the seller role, so their usernames
+	// must not clash
+	_, err := server.store.GetSellerByName(c, req.Username)
+	if err == nil {
+		err := errors.New("username is already taken")
+		c.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.AcceptStaffInviteTx(c, db.AcceptStaffInviteTxParams{
+		TokenHash: util.HashInviteToken(strings.TrimSpace(req.Token)),
+		Username:  req.Username,
+		FullName:  req.FullName,
+		Password:  hashedPassword,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrStaffInviteInvalid):
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+		case db.ErrorCode(err) == db.UniqueViolation:
+			err := errors.New("username or email is already taken")
+			c.JSON(http.StatusForbidden, errorResponse(err))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	util.LogInfo("Staff account %s created for %s as %s", result.Staff.Username, result.Staff.SellerUsername, result.Staff.StaffRole)
+	c.JSON(http.StatusOK, newStaffResponse(result.Staff))
+}
+
+// LoginStaff logs a staff member in on behalf of their store
+func (server *Server) LoginStaff(c *gin.Context) {
+	var req loginStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	staff, err := server.store.GetStoreStaff(c, req.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("no staff account found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := util.CheckPassword(req.Password, staff.Password); err != nil {
+		err := errors.New("invalid password field")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateStoreToken(
+		staff.Username, util.Seller, staff.SellerUsername, staff.StaffRole, server.config.TokenDuration)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, loginStaffResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		Staff:                newStaffResponse(staff),
+	})
+}
+
+// ListStoreStaff lists the staff accounts of the logged-in user's store
+func (server *Server) ListStoreStaff(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	staff, err := server.store.ListStoreStaff(c, authPayload.Store)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]staffResponse, len(staff))
+	for i, member := range staff {
+		rsp[i] = newStaffResponse(member)
+	}
+	c.JSON(http.StatusOK, rsp)
+}
+
+// UpdateStaffRole changes the role of one of the store's staff members
+func (server *Server) UpdateStaffRole(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var uri StaffUsernameRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req UpdateStaffRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidStaffRole(req.StaffRole) {
+		err := errors.New("staff role must be owner, pharmacist or inventory_clerk")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	staff, err := server.store.UpdateStoreStaffRole(c, db.UpdateStoreStaffRoleParams{
+		StaffRole:      req.StaffRole,
+		Username:       uri.Username,
+		SellerUsername: authPayload.Store,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("staff member not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Staff member %s of %s is now %s (changed by %s)", staff.Username, staff.SellerUsername, staff.StaffRole, authPayload.Username)
+	c.JSON(http.StatusOK, newStaffResponse(staff))
+}
+
+// RemoveStoreStaff deletes a staff account. Its tokens stop working at once.
+func (server *Server) RemoveStoreStaff(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var uri StaffUsernameRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	username, err := server.store.DeleteStoreStaff(c, db.DeleteStoreStaffParams{
+		Username:       uri.Username,
+		SellerUsername: authPayload.Store,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("staff member not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Staff member %s removed from %s by %s", username, authPayload.Store, authPayload.Username)
+	c.JSON(http.StatusOK, gin.H{
+		"username": username,
+		"message":  "staff member removed successfully",
+	})
+}
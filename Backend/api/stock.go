@@ -0,0 +1,178 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type RecordStockMovementRequest struct {
+	MovementType string `json:"movement_type" binding:"required"`
+	// Quantity is always positive except for adjustments, where the sign
+	// says whether stock is added or removed
+	Quantity  int32  `json:"quantity" binding:"required"`
+	Reason    string `json:"reason"`
+	Reference string `json:"reference"`
+}
+
+type ListStockMovementsRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+type stockReconciliationResponse struct {
+	Seller     string                         `json:"seller"`
+	Medicines  []db.GetStockReconciliationRow `json:"medicines"`
+	Mismatched int                            `json:"mismatched"`
+}
+
+// getSellerMedicine loads the medicine in the URI and checks that it belongs
+// to the logged-in user's store and that allowed accepts their staff role,
+// writing the error response itself when it doesn't.
+func (server *Server) getSellerMedicine(c *gin.Context, allowed func(staffRole string) bool) (db.Medicine, bool) {
+	authPayload, ok := server.authorizeStore(c, allowed)
+	if !ok {
+		return db.Medicine{}, false
+	}
+
+	var uri MedicineIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Medicine{}, false
+	}
+
+	medicine, err := server.store.GetMedicine(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("medicine not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return medicine, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return medicine, false
+	}
+
+	if medicine.SellerUsername != authPayload.Store {
+		err := errors.New("medicine doesn't belong to the authenticated seller")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return medicine, false
+	}
+
+	return medicine, true
+}
+
+// RecordStockMovement changes the stock of one of the seller's medicines
+// and appends the change to the stock ledger.
+func (server *Server) RecordStockMovement(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	medicine, ok := server.getSellerMedicine(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	var req RecordStockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !util.IsValidStockMovementType(req.MovementType) {
+		err := errors.New("invalid movement type")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if req.MovementType == util.AdjustmentMovement && reason == "" {
+		err := errors.New("a reason is required for manual adjustments")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	change, err := util.SignedStockChange(req.MovementType, req.Quantity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.StockMovementTx(c, db.StockMovementTxParams{
+		MedicineID:     medicine.ID,
+		MovementType:   req.MovementType,
+		QuantityChange: change,
+		Reason:         reason,
+		Reference:      strings.TrimSpace(req.Reference),
+		CreatedBy:      authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientStock) {
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to record stock movement for medicine %d: %v", medicine.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Stock of medicine %d changed by %d (%s) by %s", medicine.ID, change, req.MovementType, authPayload.Username)
+	c.JSON(http.StatusOK, result)
+}
+
+func (server *Server) ListStockMovements(c *gin.Context) {
+	medicine, ok := server.getSellerMedicine(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListStockMovementsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	movements, err := server.store.ListStockMovements(c, db.ListStockMovementsParams{
+		MedicineID: medicine.ID,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+// GetStockReconciliation compares the ledger total of each of the seller's
+// medicines with the quantity on hand. Any difference means stock was
+// changed without going through the ledger.
+func (server *Server) GetStockReconciliation(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.GetStockReconciliation(c, authPayload.Store)
+	if err != nil {
+		util.LogError("Failed to reconcile stock for seller %s: %v", authPayload.Store, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := stockReconciliationResponse{
+		Seller:    authPayload.Store,
+		Medicines: rows,
+	}
+	for _, row := range rows {
+		if row.Difference != 0 {
+			rsp.Mismatched++
+		}
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,377 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// CreateStockTransferRequest asks another branch of the organisation for
+// some of its stock of a medicine.
+type CreateStockTransferRequest struct {
+	MedicineID int32  `json:"medicine_id" binding:"required,min=1"`
+	Quantity   int32  `json:"quantity" binding:"required,min=1"`
+	Notes      string `json:"notes"`
+}
+
+type StockTransferIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ListStockTransfersRequest struct {
+	Status string `form:"status"`
+	Limit  int32  `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32  `form:"offset,default=0" binding:"min=0"`
+}
+
+// CreateStockTransfer requests stock of a medicine held by another branch
+// of the same organisation. Nothing moves until that branch dispatches it.
+func (server *Server) CreateStockTransfer(c *gin.Context) {
+	seller, ok := server.getAuthSeller(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var req CreateStockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !seller.OrganizationID.Valid {
+		err := errors.New("only branches of an organization can transfer stock")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	medicine, err := server.store.GetMedicine(c, req.MedicineID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("medicine not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if medicine.SellerUsername == seller.Username {
+		err := errors.New("medicine already belongs to this branch")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	source, err := server.store.GetSellerByName(c, medicine.SellerUsername)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if source.OrganizationID != seller.OrganizationID {
+		err := errors.New("stock can only be transferred between branches of the same organization")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if medicine.Status != util.ActiveMedicine || !medicine.ExpiryDate.Time.After(time.Now()) {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrTransferMedicineOffSale))
+		return
+	}
+
+	if req.Quantity > medicine.Quantity {
+		c.JSON(http.StatusBadRequest, errorResponse(db.ErrInsufficientStock))
+		return
+	}
+
+	transfer, err := server.store.CreateStockTransfer(c, db.CreateStockTransferParams{
+		OrganizationID:       seller.OrganizationID.Int32,
+		FromSeller:           source.Username,
+		ToSeller:             seller.Username,
+		MedicineID:           medicine.ID,
+		Name:                 medicine.Name,
+		Description:          medicine.Description,
+		ActiveIngredients:    medicine.ActiveIngredients,
+		Strength:             medicine.Strength,
+		DosageForm:           medicine.DosageForm,
+		PrescriptionRequired: medicine.PrescriptionRequired,
+		BatchNumber:          medicine.BatchNumber,
+		ExpiryDate:           medicine.ExpiryDate,
+		Price:                medicine.Price,
+		Quantity:             req.Quantity,
+		Notes:                strings.TrimSpace(req.Notes),
+		RequestedBy:          authPayload.Username,
+	})
+	if err != nil {
+		util.LogError("Failed to create stock transfer for %s: %v", seller.Username, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Stock transfer %d requested by %s from %s", transfer.ID, seller.Username, source.Username)
+	c.JSON(http.StatusOK, transfer)
+}
+
+// ListStockTransfers lists the transfers the logged-in user's branch sends
+// or receives.
+func (server *Server) ListStockTransfers(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, nil)
+	if !ok {
+		return
+	}
+
+	var req ListStockTransfersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	status, ok := bindStockTransferStatus(c, req.Status)
+	if !ok {
+		return
+	}
+
+	transfers, err := server.store.ListSellerStockTransfers(c, db.ListSellerStockTransfersParams{
+		Seller: authPayload.Store,
+		Status: status,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+// ListOrganizationStockTransfers lists the transfers between all branches
+// of the organisation for the head office.
+func (server *Server) ListOrganizationStockTransfers(c *gin.Context) {
+	_, organization, ok := server.getOwnedOrganization(c)
+	if !ok {
+		return
+	}
+
+	var req ListStockTransfersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	status, ok := bindStockTransferStatus(c, req.Status)
+	if !ok {
+		return
+	}
+
+	transfers, err := server.store.ListOrganizationStockTransfers(c, db.ListOrganizationStockTransfersParams{
+		OrganizationID: organization.ID,
+		Status:         status,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+func bindStockTransferStatus(c *gin.Context, status string) (pgtype.Text, bool) {
+	if status == "" {
+		return pgtype.Text{}, true
+	}
+	if !util.IsValidStockTransferStatus(status) {
+		err := errors.New("invalid stock transfer status")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return pgtype.Text{}, false
+	}
+	return pgtype.Text{String: status, Valid: true}, true
+}
+
+// getStockTransfer loads the transfer in the URI and checks that the
+// logged-in user's branch sends or receives it and that allowed accepts
+// their staff role, writing the error response itself when it doesn't.
+func (server *Server) getStockTransfer(c *gin.Context, allowed func(staffRole string) bool) (*token.Payload, db.StockTransfer, bool) {
+	authPayload, ok := server.authorizeStore(c, allowed)
+	if !ok {
+		return nil, db.StockTransfer{}, false
+	}
+
+	var uri StockTransferIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return nil, db.StockTransfer{}, false
+	}
+
+	transfer, err := server.store.GetStockTransfer(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("stock transfer not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return nil, transfer, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, transfer, false
+	}
+
+	if transfer.FromSeller != authPayload.Store && transfer.ToSeller != authPayload.Store {
+		err := errors.New("stock transfer doesn't belong to the authenticated seller")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return nil, transfer, false
+	}
+
+	return authPayload, transfer, true
+}
+
+func (server *Server) GetStockTransfer(c *gin.Context) {
+	_, transfer, ok := server.getStockTransfer(c, nil)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// RejectStockTransfer lets the sending branch turn down a request
+func (server *Server) RejectStockTransfer(c *gin.Context) {
+	server.changeStockTransferStatus(c, util.RejectedStockTransfer, false)
+}
+
+// CancelStockTransfer lets the requesting branch withdraw a request that
+// hasn't been dispatched
+func (server *Server) CancelStockTransfer(c *gin.Context) {
+	server.changeStockTransferStatus(c, util.CancelledStockTransfer, true)
+}
+
+// changeStockTransferStatus moves a transfer on to a status that doesn't
+// touch stock. byReceiver says whether the receiving or the sending branch
+// makes the change.
+func (server *Server) changeStockTransferStatus(c *gin.Context, status string, byReceiver bool) {
+	authPayload, transfer, ok := server.getStockTransfer(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	if byReceiver && transfer.ToSeller != authPayload.Store {
+		err := errors.New("only the requesting branch can make this change")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if !byReceiver && transfer.FromSeller != authPayload.Store {
+		err := errors.New("only the sending branch can make this change")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !util.CanTransitionStockTransfer(transfer.Status, status) {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrStockTransferStatus))
+		return
+	}
+
+	transfer, err := server.store.UpdateStockTransferStatus(c, db.UpdateStockTransferStatusParams{
+		ID:         transfer.ID,
+		Status:     status,
+		FromStatus: transfer.Status,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, errorResponse(db.ErrStockTransferStatus))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Stock transfer %d %s by %s", transfer.ID, status, authPayload.Username)
+	c.JSON(http.StatusOK, transfer)
+}
+
+// DispatchStockTransfer takes the requested stock out of the sending
+// branch's inventory
+func (server *Server) DispatchStockTransfer(c *gin.Context) {
+	authPayload, transfer, ok := server.getStockTransfer(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	if transfer.FromSeller != authPayload.Store {
+		err := errors.New("only the sending branch can dispatch a transfer")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.DispatchStockTransferTx(c, db.StockTransferTxParams{
+		TransferID:   transfer.ID,
+		MovementType: util.TransferOutMovement,
+		Reason:       "branch transfer dispatched",
+		Reference:    stockTransferReference(transfer),
+		CreatedBy:    authPayload.Username,
+	})
+	if err != nil {
+		server.handleStockTransferTxError(c, transfer, err)
+		return
+	}
+
+	util.LogInfo("Stock transfer %d dispatched by %s", transfer.ID, authPayload.Username)
+	c.JSON(http.StatusOK, result)
+}
+
+// ReceiveStockTransfer adds dispatched stock to the receiving branch's
+// inventory
+func (server *Server) ReceiveStockTransfer(c *gin.Context) {
+	authPayload, transfer, ok := server.getStockTransfer(c, util.CanEditStock)
+	if !ok {
+		return
+	}
+
+	if transfer.ToSeller != authPayload.Store {
+		err := errors.New("only the requesting branch can receive a transfer")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReceiveStockTransferTx(c, db.StockTransferTxParams{
+		TransferID:   transfer.ID,
+		MovementType: util.TransferInMovement,
+		Reason:       "branch transfer received",
+		Reference:    stockTransferReference(transfer),
+		CreatedBy:    authPayload.Username,
+	})
+	if err != nil {
+		server.handleStockTransferTxError(c, transfer, err)
+		return
+	}
+
+	util.LogInfo("Stock transfer %d received by %s", transfer.ID, authPayload.Username)
+	c.JSON(http.StatusOK, result)
+}
+
+func (server *Server) handleStockTransferTxError(c *gin.Context, transfer db.StockTransfer, err error) {
+	switch {
+	case errors.Is(err, db.ErrStockTransferStatus),
+		errors.Is(err, db.ErrTransferMedicineOffSale),
+		errors.Is(err, db.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrRecordNotFound):
+		err := fmt.Errorf("the transferred medicine no longer exists: %w", err)
+		c.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		util.LogError("Failed to update stock for transfer %d: %v", transfer.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// stockTransferReference ties the stock movements of a transfer back to it
+func stockTransferReference(transfer db.StockTransfer) string {
+	return fmt.Sprintf("stock-transfer:%d", transfer.ID)
+}
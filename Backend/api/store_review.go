@@ -0,0 +1,615 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+type CreateSellerReviewRequest struct {
+	DeliveryRating     int16  `json:"delivery_rating" binding:"required,min=1,max=5"`
+	PackagingRating    int16  `json:"packaging_rating" binding:"required,min=1,max=5"`
+	AuthenticityRating int16  `json:"authenticity_rating" binding:"required,min=1,max=5"`
+	Comment            string `json:"comment"`
+}
+
+type CreateMedicineReviewRequest struct {
+	Rating  int16  `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment"`
+}
+
+type ListStoreReviewsRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+type StoreReviewIDRequest struct {
+	ID int32 `uri:"id" binding:"required,min=1"`
+}
+
+type ReplyToReviewRequest struct {
+	Reply string `json:"reply" binding:"required"`
+}
+
+type sellerReviewsResponse struct {
+	RatingAverage             pgtype.Numeric            `json:"rating_average"`
+	DeliveryRatingAverage     pgtype.Numeric            `json:"delivery_rating_average"`
+	PackagingRatingAverage    pgtype.Numeric            `json:"packaging_rating_average"`
+	AuthenticityRatingAverage pgtype.Numeric            `json:"authenticity_rating_average"`
+	ReviewCount               int32                     `json:"review_count"`
+	Reviews                   []db.ListSellerReviewsRow `json:"reviews"`
+}
+
+type medicineReviewsResponse struct {
+	RatingAverage pgtype.Numeric              `json:"rating_average"`
+	ReviewCount   int32                       `json:"review_count"`
+	Reviews       []db.ListMedicineReviewsRow `json:"reviews"`
+}
+
+// screenStoreReviewComment trims and length-checks a review comment and
+// decides whether it is published at once or held for moderation
+func screenStoreReviewComment(c *gin.Context, comment string) (string, string, string, bool) {
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > util.MaxReviewCommentLength {
+		err := fmt.Errorf("comment is longer than %d characters", util.MaxReviewCommentLength)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return "", "", "", false
+	}
+
+	status := util.PublishedReview
+	moderationNote := util.ScreenReviewComment(comment)
+	if moderationNote != "" {
+		status = util.PendingReview
+	}
+	return comment, status, moderationNote, true
+}
+
+// CreateSellerReview lets the patient who placed an order rate the seller
+// of one of its delivered sub-orders on delivery, packaging and
+// authenticity, once per sub-order
+func (server *Server) CreateSellerReview(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can review sellers")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req CreateSellerReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	subOrder, err := server.store.GetSubOrderForReview(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("order not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if subOrder.PatientUsername != authPayload.Username {
+		err := errors.New("order doesn't belong to the authenticated patient")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if subOrder.Status != util.DeliveredOrder {
+		err := errors.New("only delivered orders can be reviewed")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	comment, status, moderationNote, ok := screenStoreReviewComment(c, req.Comment)
+	if !ok {
+		return
+	}
+
+	review, err := server.store.CreateSellerReview(c, db.CreateSellerReviewParams{
+		SubOrderID:         subOrder.ID,
+		SellerUsername:     subOrder.SellerUsername,
+		PatientUsername:    subOrder.PatientUsername,
+		DeliveryRating:     req.DeliveryRating,
+		PackagingRating:    req.PackagingRating,
+		AuthenticityRating: req.AuthenticityRating,
+		Comment:            comment,
+		Status:             status,
+		ModerationNote:     moderationNote,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("this order has already been reviewed")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to save review of sub-order %d: %v", subOrder.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Review %d of seller %s by %s saved as %s", review.ID, review.SellerUsername, authPayload.Username, review.Status)
+	c.JSON(http.StatusOK, review)
+}
+
+// GetSubOrderReview returns the patient's review of a sub-order, whatever
+// its moderation status
+func (server *Server) GetSubOrderReview(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can view their reviews")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := server.store.GetSellerReviewBySubOrder(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("order hasn't been reviewed")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if review.PatientUsername != authPayload.Username {
+		err := errors.New("review doesn't belong to the authenticated patient")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// CreateMedicineReview lets the patient who placed an order rate a
+// medicine they received, once per order line, after its sub-order has
+// been delivered
+func (server *Server) CreateMedicineReview(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can review medicines")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req CreateMedicineReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetOrderItemForReview(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("order item not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if item.PatientUsername != authPayload.Username {
+		err := errors.New("order doesn't belong to the authenticated patient")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if item.SubOrderStatus != util.DeliveredOrder {
+		err := errors.New("only delivered orders can be reviewed")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	comment, status, moderationNote, ok := screenStoreReviewComment(c, req.Comment)
+	if !ok {
+		return
+	}
+
+	review, err := server.store.CreateMedicineReview(c, db.CreateMedicineReviewParams{
+		OrderItemID:     item.ID,
+		MedicineID:      item.MedicineID,
+		MedicineName:    item.MedicineName,
+		SellerUsername:  item.SellerUsername,
+		PatientUsername: item.PatientUsername,
+		Rating:          req.Rating,
+		Comment:         comment,
+		Status:          status,
+		ModerationNote:  moderationNote,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			err := errors.New("this order item has already been reviewed")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to save review of order item %d: %v", item.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Review %d of medicine %d by %s saved as %s", review.ID, review.MedicineID, authPayload.Username, review.Status)
+	c.JSON(http.StatusOK, review)
+}
+
+// GetOrderItemReview returns the patient's review of an order line,
+// whatever its moderation status
+func (server *Server) GetOrderItemReview(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients can view their reviews")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var uri OrderIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := server.store.GetMedicineReviewByOrderItem(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("order item hasn't been reviewed")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if review.PatientUsername != authPayload.Username {
+		err := errors.New("review doesn't belong to the authenticated patient")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ListSellerReviews is the public view of a seller's ratings: the overall
+// and per-aspect averages and the published reviews newest first
+func (server *Server) ListSellerReviews(c *gin.Context) {
+	var uri GetSellerRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ListStoreReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	seller, err := server.store.GetSellerByName(c, uri.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("seller not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	reviews, err := server.store.ListSellerReviews(c, db.ListSellerReviewsParams{
+		SellerUsername: seller.Username,
+		LimitCount:     req.Limit,
+		OffsetCount:    req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, sellerReviewsResponse{
+		RatingAverage:             seller.RatingAverage,
+		DeliveryRatingAverage:     seller.DeliveryRatingAverage,
+		PackagingRatingAverage:    seller.PackagingRatingAverage,
+		AuthenticityRatingAverage: seller.AuthenticityRatingAverage,
+		ReviewCount:               seller.ReviewCount,
+		Reviews:                   reviews,
+	})
+}
+
+// ListMedicineReviews is the public view of a medicine's rating and its
+// published reviews, newest first. They cover every batch the seller has
+// listed under the medicine's name.
+func (server *Server) ListMedicineReviews(c *gin.Context) {
+	var uri MedicineIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ListStoreReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	medicine, err := server.store.GetMedicine(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("medicine not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	reviews, err := server.store.ListMedicineReviews(c, db.ListMedicineReviewsParams{
+		SellerUsername: medicine.SellerUsername,
+		MedicineName:   medicine.Name,
+		LimitCount:     req.Limit,
+		OffsetCount:    req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, medicineReviewsResponse{
+		RatingAverage: medicine.RatingAverage,
+		ReviewCount:   medicine.ReviewCount,
+		Reviews:       reviews,
+	})
+}
+
+// newReviewReply validates a seller's public reply to a review
+func newReviewReply(c *gin.Context) (pgtype.Text, bool) {
+	var req ReplyToReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return pgtype.Text{}, false
+	}
+
+	reply := strings.TrimSpace(req.Reply)
+	if reply == "" {
+		err := errors.New("reply can't be empty")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return pgtype.Text{}, false
+	}
+	if utf8.RuneCountInString(reply) > util.MaxReviewCommentLength {
+		err := fmt.Errorf("reply is longer than %d characters", util.MaxReviewCommentLength)
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return pgtype.Text{}, false
+	}
+
+	return pgtype.Text{String: reply, Valid: true}, true
+}
+
+// ReplyToSellerReview posts or replaces the store's public reply to a
+// published review of the store
+func (server *Server) ReplyToSellerReview(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var uri StoreReviewIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	reply, ok := newReviewReply(c)
+	if !ok {
+		return
+	}
+
+	review, err := server.store.ReplyToSellerReview(c, db.ReplyToSellerReviewParams{
+		SellerReply:    reply,
+		RepliedBy:      pgtype.Text{String: authPayload.Username, Valid: true},
+		ID:             uri.ID,
+		SellerUsername: authPayload.Store,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("no published review of this store with that id")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Seller %s replied to review %d", authPayload.Username, review.ID)
+	c.JSON(http.StatusOK, review)
+}
+
+// ReplyToMedicineReview posts or replaces the store's public reply to a
+// published review of a medicine it sold
+func (server *Server) ReplyToMedicineReview(c *gin.Context) {
+	authPayload, ok := server.authorizeStore(c, util.CanManageStore)
+	if !ok {
+		return
+	}
+
+	var uri StoreReviewIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	reply, ok := newReviewReply(c)
+	if !ok {
+		return
+	}
+
+	review, err := server.store.ReplyToMedicineReview(c, db.ReplyToMedicineReviewParams{
+		SellerReply:    reply,
+		RepliedBy:      pgtype.Text{String: authPayload.Username, Valid: true},
+		ID:             uri.ID,
+		SellerUsername: authPayload.Store,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("no published review of this store's medicines with that id")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Seller %s replied to medicine review %d", authPayload.Username, review.ID)
+	c.JSON(http.StatusOK, review)
+}
+
+// ListPendingSellerReviews lists the held seller reviews waiting for an admin
+func (server *Server) ListPendingSellerReviews(c *gin.Context) {
+	if _, ok := authorizeAdmin(c); !ok {
+		return
+	}
+
+	var req ListStoreReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	reviews, err := server.store.ListPendingSellerReviews(c, db.ListPendingSellerReviewsParams{
+		LimitCount:  req.Limit,
+		OffsetCount: req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ModerateSellerReview lets an admin publish or reject a seller review. The seller's rating averages are updated to match.
+func (server *Server) ModerateSellerReview(c *gin.Context) {
+	authPayload, ok := authorizeAdmin(c)
+	if !ok {
+		return
+	}
+
+	var uri StoreReviewIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := server.store.ModerateSellerReview(c, db.ModerateSellerReviewParams{
+		ID:             uri.ID,
+		Status:         req.Status,
+		ModerationNote: strings.TrimSpace(req.Note),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("review not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Seller review %d of %s %s by admin %s", review.ID, review.SellerUsername, review.Status, authPayload.Username)
+	c.JSON(http.StatusOK, review)
+}
+
+// ListPendingMedicineReviews lists the held medicine reviews waiting for an admin
+func (server *Server) ListPendingMedicineReviews(c *gin.Context) {
+	if _, ok := authorizeAdmin(c); !ok {
+		return
+	}
+
+	var req ListStoreReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	reviews, err := server.store.ListPendingMedicineReviews(c, db.ListPendingMedicineReviewsParams{
+		LimitCount:  req.Limit,
+		OffsetCount: req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ModerateMedicineReview lets an admin publish or reject a medicine review. The medicine's rating average is updated to match.
+func (server *Server) ModerateMedicineReview(c *gin.Context) {
+	authPayload, ok := authorizeAdmin(c)
+	if !ok {
+		return
+	}
+
+	var uri StoreReviewIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := server.store.ModerateMedicineReview(c, db.ModerateMedicineReviewParams{
+		ID:             uri.ID,
+		Status:         req.Status,
+		ModerationNote: strings.TrimSpace(req.Note),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("review not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Medicine review %d of %s %s by admin %s", review.ID, review.MedicineName, review.Status, authPayload.Username)
+	c.JSON(http.StatusOK, review)
+}
@@ -0,0 +1,511 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/token"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// VitalsRequest holds the vitals taken at a visit. Any of them can be left
+// out.
+type VitalsRequest struct {
+	BpSystolic      *int32   `json:"bp_systolic" binding:"omitempty,min=40,max=300"`
+	BpDiastolic     *int32   `json:"bp_diastolic" binding:"omitempty,min=20,max=200"`
+	PulseBpm        *int32   `json:"pulse_bpm" binding:"omitempty,min=20,max=250"`
+	TemperatureC    *float64 `json:"temperature_c" binding:"omitempty,min=30,max=45"`
+	RespiratoryRate *int32   `json:"respiratory_rate" binding:"omitempty,min=4,max=80"`
+	Spo2Percent     *int32   `json:"spo2_percent" binding:"omitempty,min=50,max=100"`
+	WeightKg        *float64 `json:"weight_kg" binding:"omitempty,gt=0,max=400"`
+	HeightCm        *float64 `json:"height_cm" binding:"omitempty,gt=0,max=260"`
+}
+
+type PrescriptionItemRequest struct {
+	MedicineName      string `json:"medicine_name" binding:"required"`
+	ActiveIngredients string `json:"active_ingredients"`
+	Strength          string `json:"strength"`
+	DosageForm        string `json:"dosage_form"`
+	// Dose and Frequency are as the patient should read them, e.g.
+	// "1 tablet" and "twice a day after meals"
+	Dose         string `json:"dose" binding:"required"`
+	Frequency    string `json:"frequency" binding:"required"`
+	DurationDays int32  `json:"duration_days" binding:"required,min=1,max=365"`
+	Instructions string `json:"instructions"`
+}
+
+// SaveVisitNoteRequest is the whole draft note; saving it again replaces
+// the previous draft, prescription included
+type SaveVisitNoteRequest struct {
+	ChiefComplaint string                    `json:"chief_complaint"`
+	Vitals         VitalsRequest             `json:"vitals"`
+	Diagnosis      string                    `json:"diagnosis"`
+	ICD10Code      string                    `json:"icd10_code"`
+	Advice         string                    `json:"advice"`
+	Prescription   []PrescriptionItemRequest `json:"prescription" binding:"max=30,dive"`
+}
+
+type ListVisitSummariesRequest struct {
+	Limit  int32 `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int32 `form:"offset,default=0" binding:"min=0"`
+}
+
+type PatientHistoryRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type SearchICD10CodesRequest struct {
+	Query string `form:"q" binding:"required,min=2"`
+	Limit int32  `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+type visitNoteResponse struct {
+	db.VisitNote
+	Prescription []db.PrescriptionItem `json:"prescription"`
+}
+
+// visitSummaryResponse is a finalized visit note as it appears in the
+// patient's history
+type visitSummaryResponse struct {
+	db.ListPatientVisitSummariesRow
+	Prescription []db.PrescriptionItem `json:"prescription"`
+}
+
+type patientHistoryResponse struct {
+	Patient userResponse           `json:"patient"`
+	Profile *db.PatientProfile     `json:"profile"`
+	Visits  []visitSummaryResponse `json:"visits"`
+
+	// All the patient's allergies and conditions, including the resolved
+	// ones, active ones first
+	Allergies  []db.ListPatientHealthEntriesRow `json:"allergies"`
+	Conditions []db.ListPatientHealthEntriesRow `json:"conditions"`
+}
+
+func pgInt4(value *int32) pgtype.Int4 {
+	if value == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *value, Valid: true}
+}
+
+func pgFloat8(value *float64) pgtype.Float8 {
+	if value == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *value, Valid: true}
+}
+
+// listPrescriptions loads the prescriptions of several visit notes at once,
+// keyed by note
+func (server *Server) listPrescriptions(c *gin.Context, visitNoteIDs []int32) (map[int32][]db.PrescriptionItem, error) {
+	items, err := server.store.ListPrescriptionItems(c, visitNoteIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	prescriptions := make(map[int32][]db.PrescriptionItem, len(visitNoteIDs))
+	for _, id := range visitNoteIDs {
+		prescriptions[id] = []db.PrescriptionItem{}
+	}
+	for _, item := range items {
+		prescriptions[item.VisitNoteID] = append(prescriptions[item.VisitNoteID], item)
+	}
+	return prescriptions, nil
+}
+
+func (server *Server) newVisitNoteResponse(c *gin.Context, note db.VisitNote) (visitNoteResponse, error) {
+	prescriptions, err := server.listPrescriptions(c, []int32{note.ID})
+	if err != nil {
+		return visitNoteResponse{}, err
+	}
+	return visitNoteResponse{
+		VisitNote:    note,
+		Prescription: prescriptions[note.ID],
+	}, nil
+}
+
+// listVisitSummaries is a page of the patient's finalized visit notes with
+// their prescriptions
+func (server *Server) listVisitSummaries(c *gin.Context, patientUsername string, limit, offset int32) ([]visitSummaryResponse, error) {
+	rows, err := server.store.ListPatientVisitSummaries(c, db.ListPatientVisitSummariesParams{
+		PatientUsername: patientUsername,
+		LimitCount:      limit,
+		OffsetCount:     offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int32, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	prescriptions, err := server.listPrescriptions(c, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	visits := make([]visitSummaryResponse, len(rows))
+	for i, row := range rows {
+		visits[i] = visitSummaryResponse{
+			ListPatientVisitSummariesRow: row,
+			Prescription:                 prescriptions[row.ID],
+		}
+	}
+	return visits, nil
+}
+
+// getDoctorAppointment loads an appointment of the logged-in doctor,
+// writing the error response itself when it isn't theirs
+func (server *Server) getDoctorAppointment(c *gin.Context, id int32) (db.Appointment, bool) {
+	appointment, authPayload, ok := server.getAppointmentParty(c, id)
+	if !ok {
+		return appointment, false
+	}
+
+	if authPayload.Role != util.Doctor {
+		err := errors.New("only the doctor can write the visit note")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return appointment, false
+	}
+
+	return appointment, true
+}
+
+// SaveVisitNote records the logged-in doctor's draft note of an
+// appointment: complaint, vitals, diagnosis, advice and prescription. The
+// draft can be saved any number of times until it is finalized.
+func (server *Server) SaveVisitNote(c *gin.Context) {
+	var uri AppointmentIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req SaveVisitNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, ok := server.getDoctorAppointment(c, uri.ID)
+	if !ok {
+		return
+	}
+
+	switch {
+	case appointment.Status != util.BookedAppointment && appointment.Status != util.CompletedAppointment:
+		err := fmt.Errorf("visit notes can't be written for %s appointments", strings.ReplaceAll(appointment.Status, "_", "-"))
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	case wallClockNow().Before(appointment.StartsAt.Time.Add(-util.CheckInOpensBefore)):
+		err := errors.New("the appointment hasn't started yet")
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	icd10Code := pgtype.Text{}
+	if req.ICD10Code != "" {
+		code := util.NormalizeICD10Code(req.ICD10Code)
+		if !util.IsValidICD10Code(code) {
+			err := fmt.Errorf("invalid ICD-10 code: %s", req.ICD10Code)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if _, err := server.store.GetICD10Code(c, code); err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				err := fmt.Errorf("unknown ICD-10 code: %s", code)
+				c.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		icd10Code = pgtype.Text{String: code, Valid: true}
+	}
+
+	prescription := make([]db.CreatePrescriptionItemParams, len(req.Prescription))
+	for i, item := range req.Prescription {
+		dosageForm := strings.ToLower(strings.TrimSpace(item.DosageForm))
+		if dosageForm != "" && !util.IsValidDosageForm(dosageForm) {
+			err := fmt.Errorf("prescription item %d: invalid dosage form: %s", i+1, item.DosageForm)
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		prescription[i] = db.CreatePrescriptionItemParams{
+			MedicineName:      strings.TrimSpace(item.MedicineName),
+			ActiveIngredients: util.NormalizeIngredients(item.ActiveIngredients),
+			Strength:          strings.TrimSpace(item.Strength),
+			DosageForm:        dosageForm,
+			Dose:              strings.TrimSpace(item.Dose),
+			Frequency:         strings.TrimSpace(item.Frequency),
+			DurationDays:      item.DurationDays,
+			Instructions:      strings.TrimSpace(item.Instructions),
+		}
+	}
+
+	result, err := server.store.SaveVisitNoteTx(c, db.SaveVisitNoteTxParams{
+		Note: db.SaveVisitNoteParams{
+			AppointmentID:   appointment.ID,
+			DoctorUsername:  appointment.DoctorUsername,
+			PatientUsername: appointment.PatientUsername,
+			ChiefComplaint:  strings.TrimSpace(req.ChiefComplaint),
+			BpSystolic:      pgInt4(req.Vitals.BpSystolic),
+			BpDiastolic:     pgInt4(req.Vitals.BpDiastolic),
+			PulseBpm:        pgInt4(req.Vitals.PulseBpm),
+			TemperatureC:    pgFloat8(req.Vitals.TemperatureC),
+			RespiratoryRate: pgInt4(req.Vitals.RespiratoryRate),
+			Spo2Percent:     pgInt4(req.Vitals.Spo2Percent),
+			WeightKg:        pgFloat8(req.Vitals.WeightKg),
+			HeightCm:        pgFloat8(req.Vitals.HeightCm),
+			Diagnosis:       strings.TrimSpace(req.Diagnosis),
+			Icd10Code:       icd10Code,
+			Advice:          strings.TrimSpace(req.Advice),
+		},
+		Prescription: prescription,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrVisitNoteFinalized) {
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		util.LogError("Failed to save visit note of appointment %d: %v", appointment.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Visit note %d of appointment %d saved by doctor %s", result.Note.ID, appointment.ID, appointment.DoctorUsername)
+	c.JSON(http.StatusOK, visitNoteResponse{
+		VisitNote:    result.Note,
+		Prescription: result.Prescription,
+	})
+}
+
+// FinalizeVisitNote signs off the logged-in doctor's note of an
+// appointment. It can't be changed afterwards and becomes part of the
+// patient's history.
+func (server *Server) FinalizeVisitNote(c *gin.Context) {
+	var req AppointmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, ok := server.getDoctorAppointment(c, req.ID)
+	if !ok {
+		return
+	}
+
+	note, err := server.store.GetVisitNoteByAppointment(c, appointment.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("no visit note has been written for this appointment")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if note.Status != util.DraftVisitNote {
+		c.JSON(http.StatusConflict, errorResponse(db.ErrVisitNoteFinalized))
+		return
+	}
+	if note.ChiefComplaint == "" || note.Diagnosis == "" {
+		err := errors.New("a visit note needs a chief complaint and a diagnosis to be finalized")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	note, err = server.store.FinalizeVisitNote(c, note.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, errorResponse(db.ErrVisitNoteFinalized))
+			return
+		}
+		util.LogError("Failed to finalize visit note %d: %v", note.ID, err)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.newVisitNoteResponse(c, note)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	util.LogInfo("Visit note %d of appointment %d finalized by doctor %s", note.ID, appointment.ID, appointment.DoctorUsername)
+	c.JSON(http.StatusOK, rsp)
+}
+
+// GetVisitNote returns the note of an appointment. The doctor sees their
+// draft; the patient only sees the note once it is finalized.
+func (server *Server) GetVisitNote(c *gin.Context) {
+	var req AppointmentIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	appointment, authPayload, ok := server.getAppointmentParty(c, req.ID)
+	if !ok {
+		return
+	}
+
+	note, err := server.store.GetVisitNoteByAppointment(c, appointment.ID)
+	if err == nil && authPayload.Role == util.Patient && note.Status != util.FinalizedVisitNote {
+		err = db.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("visit summary not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.newVisitNoteResponse(c, note)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+// ListVisitSummaries is the logged-in patient's visit history, newest first
+func (server *Server) ListVisitSummaries(c *gin.Context) {
+	authPayload := c.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.Patient {
+		err := errors.New("only patients have a visit history")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req ListVisitSummariesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	visits, err := server.listVisitSummaries(c, authPayload.Username, req.Limit, req.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, visits)
+}
+
+// GetPatientHistory gives the logged-in doctor a read-only view of a
+// patient's profile, allergies, conditions and visit history, including
+// visits to other doctors.
+// Only doctors the patient has an appointment with can see it.
+func (server *Server) GetPatientHistory(c *gin.Context) {
+	authPayload, ok := authorizeDoctor(c)
+	if !ok {
+		return
+	}
+
+	var uri PatientHistoryRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req ListVisitSummariesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	isDoctor, err := server.store.IsDoctorOfPatient(c, db.IsDoctorOfPatientParams{
+		DoctorUsername:  authPayload.Username,
+		PatientUsername: uri.Username,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !isDoctor {
+		err := errors.New("only doctors the patient has an appointment with can see their history")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	patient, err := server.store.GetPatientByName(c, uri.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("patient not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := patientHistoryResponse{Patient: newUserResponse(patient)}
+
+	profile, err := server.store.GetPatientProfile(c, patient.Username)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err == nil {
+		rsp.Profile = &profile
+	}
+
+	rsp.Allergies, err = server.store.ListPatientHealthEntries(c, db.ListPatientHealthEntriesParams{
+		PatientUsername: patient.Username,
+		Kind:            util.AllergyEntry,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp.Conditions, err = server.store.ListPatientHealthEntries(c, db.ListPatientHealthEntriesParams{
+		PatientUsername: patient.Username,
+		Kind:            util.ConditionEntry,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp.Visits, err = server.listVisitSummaries(c, patient.Username, req.Limit, req.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+// SearchICD10Codes looks up diagnosis codes by code or description
+func (server *Server) SearchICD10Codes(c *gin.Context) {
+	var req SearchICD10CodesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	codes, err := server.store.SearchICD10Codes(c, db.SearchICD10CodesParams{
+		Query:      strings.TrimSpace(req.Query),
+		LimitCount: req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
@@ -0,0 +1,72 @@
+// Command createadmin adds a platform admin account. The password is read
+// from standard input so it doesn't end up in the shell history.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+func main() {
+	username := flag.String("username", "", "admin username")
+	fullName := flag.String("name", "", "admin full name")
+	email := flag.String("email", "", "admin email")
+	flag.Parse()
+
+	if !util.IsValidUsername(*username) {
+		log.Fatalf("invalid username: %q", *username)
+	}
+	if !util.IsValidEmail(*email) {
+		log.Fatalf("invalid email: %q", *email)
+	}
+	if strings.TrimSpace(*fullName) == "" {
+		*fullName = *username
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatalf("cannot read password: %v", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if !util.IsValidPassword(password) {
+		log.Fatal("password must have at least 8 characters, with an uppercase letter, a lowercase letter and a digit")
+	}
+
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		log.Fatalf("cannot hash password: %v", err)
+	}
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("cannot load config: %v", err)
+	}
+
+	connPool, err := pgxpool.New(context.Background(), config.DBSource)
+	if err != nil {
+		log.Fatalf("cannot connect to database: %v", err)
+	}
+	defer connPool.Close()
+
+	admin, err := db.NewStore(connPool).CreateAdmin(context.Background(), db.CreateAdminParams{
+		Username: *username,
+		FullName: strings.TrimSpace(*fullName),
+		Email:    *email,
+		Password: hashedPassword,
+	})
+	if err != nil {
+		log.Fatalf("cannot create admin: %v", err)
+	}
+
+	fmt.Printf("Created admin %s\n", admin.Username)
+}
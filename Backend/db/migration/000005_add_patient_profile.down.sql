@@ -1 +1 @@
-DROP TABLE IF EXISTS patient_profiles;
+DROP TABLE IF EXISTS patient_profiles;
@@ -1,2 +1,2 @@
-DROP TABLE IF EXISTS payment_methods;
-DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS payment_methods;
+DROP TABLE IF EXISTS payments;
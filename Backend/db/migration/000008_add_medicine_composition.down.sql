@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_medicines_composition;
+
+ALTER TABLE medicines
+    DROP COLUMN IF EXISTS dosage_form,
+    DROP COLUMN IF EXISTS strength,
+    DROP COLUMN IF EXISTS active_ingredients;
@@ -0,0 +1,6 @@
+ALTER TABLE medicines
+    ADD COLUMN active_ingredients VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN strength VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN dosage_form VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX idx_medicines_composition ON medicines(active_ingredients, strength, dosage_form);
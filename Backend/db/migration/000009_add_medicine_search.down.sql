@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_medicines_ingredients_trgm;
+DROP INDEX IF EXISTS idx_medicines_name_trgm;
+DROP INDEX IF EXISTS idx_medicines_search_document;
+
+DROP FUNCTION IF EXISTS medicine_search_document(VARCHAR, TEXT, VARCHAR);
+
+ALTER TABLE medicines DROP COLUMN IF EXISTS prescription_required;
@@ -0,0 +1,18 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE medicines
+    ADD COLUMN prescription_required BOOLEAN NOT NULL DEFAULT false;
+
+-- The search document is built by an immutable function so that the same
+-- expression can back both the GIN index and the search queries.
+CREATE FUNCTION medicine_search_document(name VARCHAR, description TEXT, active_ingredients VARCHAR)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+           setweight(to_tsvector('english', coalesce(active_ingredients, '')), 'A') ||
+           setweight(to_tsvector('english', coalesce(description, '')), 'B')
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE INDEX idx_medicines_search_document ON medicines
+    USING GIN (medicine_search_document(name, description, active_ingredients));
+CREATE INDEX idx_medicines_name_trgm ON medicines USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_medicines_ingredients_trgm ON medicines USING GIN (active_ingredients gin_trgm_ops);
@@ -0,0 +1,11 @@
+DROP FUNCTION IF EXISTS haversine_km(DOUBLE PRECISION, DOUBLE PRECISION, DOUBLE PRECISION, DOUBLE PRECISION);
+DROP TABLE IF EXISTS seller_opening_hours;
+DROP INDEX IF EXISTS idx_sellers_location;
+
+ALTER TABLE sellers
+    DROP COLUMN IF EXISTS delivery_radius_km,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS pincode;
+
+DROP TABLE IF EXISTS pincodes;
@@ -0,0 +1,35 @@
+CREATE TABLE pincodes (
+    pincode VARCHAR(6) PRIMARY KEY,
+    locality VARCHAR NOT NULL,
+    district VARCHAR NOT NULL,
+    state VARCHAR NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION NOT NULL CHECK (longitude BETWEEN -180 AND 180)
+);
+
+ALTER TABLE sellers
+    ADD COLUMN pincode VARCHAR(6),
+    ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    ADD COLUMN delivery_radius_km DOUBLE PRECISION NOT NULL DEFAULT 5 CHECK (delivery_radius_km >= 0);
+
+CREATE INDEX idx_sellers_location ON sellers(latitude, longitude);
+
+CREATE TABLE seller_opening_hours (
+    id SERIAL PRIMARY KEY,
+    seller_username VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    CHECK (closes_at > opens_at),
+    UNIQUE(seller_username, day_of_week, opens_at)
+);
+
+-- Great-circle distance between two points in kilometres
+CREATE FUNCTION haversine_km(lat1 DOUBLE PRECISION, lng1 DOUBLE PRECISION, lat2 DOUBLE PRECISION, lng2 DOUBLE PRECISION)
+RETURNS DOUBLE PRECISION AS $$
+    SELECT 6371 * 2 * asin(sqrt(
+        power(sin(radians(lat2 - lat1) / 2), 2) +
+        cos(radians(lat1)) * cos(radians(lat2)) * power(sin(radians(lng2 - lng1) / 2), 2)
+    ))
+$$ LANGUAGE SQL IMMUTABLE;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS medicine_import_jobs;
+
+DROP INDEX IF EXISTS idx_medicines_seller_name_batch;
+
+ALTER TABLE medicines DROP COLUMN IF EXISTS batch_number;
+
+ALTER TABLE medicines ADD CONSTRAINT medicines_expiry_date_key UNIQUE (expiry_date);
@@ -0,0 +1,25 @@
+-- Several batches of the same medicine (or different medicines) can share
+-- an expiry date, which the original UNIQUE constraint did not allow.
+ALTER TABLE medicines DROP CONSTRAINT IF EXISTS medicines_expiry_date_key;
+
+ALTER TABLE medicines ADD COLUMN batch_number VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX idx_medicines_seller_name_batch ON medicines (seller_username, name, batch_number);
+
+CREATE TABLE medicine_import_jobs (
+    "id" SERIAL PRIMARY KEY,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "file_name" VARCHAR NOT NULL,
+    "file_format" VARCHAR NOT NULL,
+    "upsert" BOOLEAN NOT NULL DEFAULT false,
+    "status" VARCHAR NOT NULL DEFAULT 'pending',
+    "total_rows" INTEGER NOT NULL DEFAULT 0,
+    "created_rows" INTEGER NOT NULL DEFAULT 0,
+    "updated_rows" INTEGER NOT NULL DEFAULT 0,
+    "failed_rows" INTEGER NOT NULL DEFAULT 0,
+    "row_errors" JSONB NOT NULL DEFAULT '[]',
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "completed_at" TIMESTAMP
+);
+
+CREATE INDEX idx_medicine_import_jobs_seller ON medicine_import_jobs (seller_username, created_at DESC);
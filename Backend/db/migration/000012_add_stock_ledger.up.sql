@@ -0,0 +1,38 @@
+-- Append-only history of every change to medicines.quantity. There are no
+-- foreign keys so that entries outlive the medicine or seller they describe.
+CREATE TABLE stock_movements (
+    "id" BIGSERIAL PRIMARY KEY,
+    "medicine_id" INTEGER NOT NULL,
+    "seller_username" VARCHAR NOT NULL,
+    "movement_type" VARCHAR NOT NULL CHECK (movement_type IN (
+        'receipt', 'sale', 'return', 'damage', 'expiry_write_off', 'adjustment'
+    )),
+    "quantity_change" INTEGER NOT NULL CHECK (quantity_change <> 0),
+    "quantity_after" INTEGER NOT NULL CHECK (quantity_after >= 0),
+    "reason" TEXT NOT NULL DEFAULT '',
+    "reference" VARCHAR NOT NULL DEFAULT '',
+    "created_by" VARCHAR NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    CHECK (movement_type <> 'adjustment' OR reason <> '')
+);
+
+CREATE INDEX idx_stock_movements_medicine ON stock_movements (medicine_id, created_at);
+CREATE INDEX idx_stock_movements_seller ON stock_movements (seller_username, created_at);
+
+CREATE FUNCTION prevent_stock_movement_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_append_only
+BEFORE UPDATE OR DELETE ON stock_movements
+FOR EACH ROW EXECUTE FUNCTION prevent_stock_movement_changes();
+
+-- Open the ledger with the stock already on hand
+INSERT INTO stock_movements (
+    medicine_id, seller_username, movement_type, quantity_change, quantity_after, reason, created_by
+)
+SELECT id, seller_username, 'adjustment', quantity, quantity, 'opening balance', 'system'
+FROM medicines
+WHERE quantity > 0;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS medicine_disposals;
+
+DROP INDEX IF EXISTS idx_medicines_status;
+
+ALTER TABLE medicines DROP COLUMN IF EXISTS quarantined_at;
+ALTER TABLE medicines DROP COLUMN IF EXISTS quarantined_quantity;
+ALTER TABLE medicines DROP COLUMN IF EXISTS status;
@@ -0,0 +1,25 @@
+-- Expired stock is quarantined instead of deleted so that carts, sales and
+-- the stock ledger keep pointing at a real medicine.
+ALTER TABLE medicines ADD COLUMN status VARCHAR NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'quarantined', 'disposed'));
+ALTER TABLE medicines ADD COLUMN quarantined_quantity INTEGER NOT NULL DEFAULT 0
+    CHECK (quarantined_quantity >= 0);
+ALTER TABLE medicines ADD COLUMN quarantined_at TIMESTAMP;
+
+CREATE INDEX idx_medicines_status ON medicines (seller_username, status);
+
+CREATE TABLE medicine_disposals (
+    "id" SERIAL PRIMARY KEY,
+    "medicine_id" INTEGER NOT NULL UNIQUE REFERENCES medicines(id),
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "quantity" INTEGER NOT NULL CHECK (quantity >= 0),
+    "method" VARCHAR NOT NULL,
+    "disposed_at" TIMESTAMP NOT NULL,
+    "disposed_by" VARCHAR NOT NULL DEFAULT '',
+    "certificate_number" VARCHAR NOT NULL DEFAULT '',
+    "notes" TEXT NOT NULL DEFAULT '',
+    "recorded_by" VARCHAR NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_medicine_disposals_seller ON medicine_disposals (seller_username, disposed_at);
@@ -0,0 +1,13 @@
+-- One row per medicine and threshold the seller has been told about, so a
+-- digest never repeats an item for the same threshold. A threshold of 0
+-- means the medicine had already expired.
+CREATE TABLE expiry_notifications (
+    "id" SERIAL PRIMARY KEY,
+    "medicine_id" INTEGER NOT NULL REFERENCES medicines(id) ON DELETE CASCADE,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "threshold_days" INTEGER NOT NULL CHECK (threshold_days >= 0),
+    "sent_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    UNIQUE (medicine_id, threshold_days)
+);
+
+CREATE INDEX idx_expiry_notifications_seller ON expiry_notifications (seller_username, sent_at);
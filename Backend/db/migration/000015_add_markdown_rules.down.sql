@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS markdown_events;
+
+ALTER TABLE medicines DROP COLUMN IF EXISTS markdown_rule_id;
+ALTER TABLE medicines DROP COLUMN IF EXISTS original_discount;
+
+DROP TABLE IF EXISTS markdown_rules;
@@ -0,0 +1,27 @@
+CREATE TABLE markdown_rules (
+    "id" SERIAL PRIMARY KEY,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "max_days_to_expiry" INTEGER NOT NULL CHECK (max_days_to_expiry > 0),
+    "discount" INTEGER NOT NULL CHECK (discount > 0 AND discount <= 100),
+    "active" BOOLEAN NOT NULL DEFAULT true,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    UNIQUE (seller_username, max_days_to_expiry)
+);
+
+-- original_discount holds the seller's own discount while a markdown rule
+-- has replaced it, so it can be restored; NULL means no markdown applies.
+ALTER TABLE medicines ADD COLUMN original_discount INTEGER;
+ALTER TABLE medicines ADD COLUMN markdown_rule_id INTEGER REFERENCES markdown_rules(id) ON DELETE SET NULL;
+
+CREATE TABLE markdown_events (
+    "id" BIGSERIAL PRIMARY KEY,
+    "medicine_id" INTEGER NOT NULL REFERENCES medicines(id) ON DELETE CASCADE,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "rule_id" INTEGER REFERENCES markdown_rules(id) ON DELETE SET NULL,
+    "action" VARCHAR NOT NULL CHECK (action IN ('applied', 'restored')),
+    "old_discount" INTEGER NOT NULL,
+    "new_discount" INTEGER NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_markdown_events_seller ON markdown_events (seller_username, created_at);
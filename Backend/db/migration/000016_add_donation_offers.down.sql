@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS donation_claims;
+DROP TABLE IF EXISTS donation_offers;
+
+-- Transfers already in the ledger can't be removed, so the old check is
+-- only enforced for new rows
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_movement_type_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_movement_type_check
+    CHECK (movement_type IN (
+        'receipt', 'sale', 'return', 'damage', 'expiry_write_off', 'adjustment'
+    )) NOT VALID;
+
+ALTER TABLE sellers DROP COLUMN IF EXISTS verified_at;
@@ -0,0 +1,53 @@
+-- NGO and hospital sellers must be verified before they can claim donated
+-- stock. Verification is done by the operators (see
+-- `PUT /api/admin/sellers/:username/verify`).
+ALTER TABLE sellers ADD COLUMN verified_at TIMESTAMP;
+
+-- Stock moved between sellers by a donation claim
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_movement_type_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_movement_type_check
+    CHECK (movement_type IN (
+        'receipt', 'sale', 'return', 'damage', 'expiry_write_off', 'adjustment',
+        'transfer_out', 'transfer_in'
+    ));
+
+-- Offers and claims keep no foreign key to medicines so that the record of
+-- a transfer outlives the medicines on either side of it.
+CREATE TABLE donation_offers (
+    "id" SERIAL PRIMARY KEY,
+    "medicine_id" INTEGER NOT NULL,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "offer_type" VARCHAR NOT NULL CHECK (offer_type IN ('donation', 'discounted')),
+    "unit_price" NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (unit_price >= 0),
+    "quantity_offered" INTEGER NOT NULL CHECK (quantity_offered > 0),
+    "quantity_remaining" INTEGER NOT NULL CHECK (quantity_remaining >= 0),
+    "status" VARCHAR NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed', 'withdrawn')),
+    "notes" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "closed_at" TIMESTAMP,
+    CHECK (offer_type <> 'donation' OR unit_price = 0),
+    CHECK (quantity_remaining <= quantity_offered)
+);
+
+-- A medicine can only be on offer once at a time
+CREATE UNIQUE INDEX idx_donation_offers_open_medicine ON donation_offers (medicine_id)
+    WHERE status = 'open';
+CREATE INDEX idx_donation_offers_seller ON donation_offers (seller_username, created_at);
+
+-- Every claim is kept as the record of a transfer. The medicines on both
+-- sides are kept so the matching stock movements can be traced.
+CREATE TABLE donation_claims (
+    "id" SERIAL PRIMARY KEY,
+    "offer_id" INTEGER NOT NULL REFERENCES donation_offers(id),
+    "donor_username" VARCHAR NOT NULL,
+    "claimant_username" VARCHAR NOT NULL,
+    "source_medicine_id" INTEGER NOT NULL,
+    "received_medicine_id" INTEGER NOT NULL,
+    "quantity" INTEGER NOT NULL CHECK (quantity > 0),
+    "unit_price" NUMERIC(10, 2) NOT NULL CHECK (unit_price >= 0),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_donation_claims_offer ON donation_claims (offer_id);
+CREATE INDEX idx_donation_claims_donor ON donation_claims (donor_username, created_at);
+CREATE INDEX idx_donation_claims_claimant ON donation_claims (claimant_username, created_at);
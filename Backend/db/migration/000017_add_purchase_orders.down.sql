@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS purchase_order_items;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS trade_price_tiers;
+
+ALTER TABLE medicines DROP COLUMN IF EXISTS min_order_quantity;
+ALTER TABLE medicines DROP COLUMN IF EXISTS trade_price;
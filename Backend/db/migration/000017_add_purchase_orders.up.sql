@@ -0,0 +1,53 @@
+-- Trade pricing of wholesale stock. Medicines without a trade price are not
+-- listed in the wholesale catalogue.
+ALTER TABLE medicines ADD COLUMN trade_price NUMERIC(10, 2) CHECK (trade_price >= 0);
+ALTER TABLE medicines ADD COLUMN min_order_quantity INTEGER NOT NULL DEFAULT 1
+    CHECK (min_order_quantity > 0);
+
+-- Lower unit prices for larger orders; the tier with the highest
+-- min_quantity not above the ordered quantity applies
+CREATE TABLE trade_price_tiers (
+    "id" SERIAL PRIMARY KEY,
+    "medicine_id" INTEGER NOT NULL REFERENCES medicines(id) ON DELETE CASCADE,
+    "min_quantity" INTEGER NOT NULL CHECK (min_quantity > 0),
+    "unit_price" NUMERIC(10, 2) NOT NULL CHECK (unit_price >= 0),
+    UNIQUE (medicine_id, min_quantity)
+);
+
+CREATE TABLE purchase_orders (
+    "id" SERIAL PRIMARY KEY,
+    "buyer_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "supplier_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "status" VARCHAR NOT NULL DEFAULT 'placed' CHECK (status IN (
+        'placed', 'accepted', 'rejected', 'cancelled', 'shipped', 'received'
+    )),
+    "total_amount" NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    "notes" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    CHECK (buyer_username <> supplier_username)
+);
+
+CREATE INDEX idx_purchase_orders_buyer ON purchase_orders (buyer_username, created_at);
+CREATE INDEX idx_purchase_orders_supplier ON purchase_orders (supplier_username, created_at);
+
+-- Items keep a copy of the supplier's medicine as ordered, which is what
+-- the buyer's new batch is created from on receipt
+CREATE TABLE purchase_order_items (
+    "id" SERIAL PRIMARY KEY,
+    "purchase_order_id" INTEGER NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    "medicine_id" INTEGER NOT NULL,
+    "name" VARCHAR NOT NULL,
+    "description" TEXT NOT NULL,
+    "active_ingredients" VARCHAR NOT NULL DEFAULT '',
+    "strength" VARCHAR NOT NULL DEFAULT '',
+    "dosage_form" VARCHAR NOT NULL DEFAULT '',
+    "prescription_required" BOOLEAN NOT NULL DEFAULT false,
+    "batch_number" VARCHAR NOT NULL DEFAULT '',
+    "expiry_date" DATE NOT NULL,
+    "mrp" NUMERIC(10, 2) NOT NULL,
+    "quantity" INTEGER NOT NULL CHECK (quantity > 0),
+    "unit_price" NUMERIC(10, 2) NOT NULL CHECK (unit_price >= 0),
+    "received_medicine_id" INTEGER,
+    UNIQUE (purchase_order_id, medicine_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS low_stock_alerts;
+
+ALTER TABLE medicines DROP COLUMN IF EXISTS reorder_level;
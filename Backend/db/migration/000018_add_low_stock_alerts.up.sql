@@ -0,0 +1,13 @@
+-- Stock at or below the reorder level triggers a low-stock alert. Medicines
+-- without a reorder level are never alerted on.
+ALTER TABLE medicines ADD COLUMN reorder_level INTEGER CHECK (reorder_level >= 0);
+
+-- One alert per medicine until it is restocked above its reorder level
+CREATE TABLE low_stock_alerts (
+    "medicine_id" INTEGER PRIMARY KEY REFERENCES medicines(id) ON DELETE CASCADE,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "quantity" INTEGER NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_low_stock_alerts_seller ON low_stock_alerts (seller_username);
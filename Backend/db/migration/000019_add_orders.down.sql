@@ -0,0 +1,6 @@
+DROP MATERIALIZED VIEW IF EXISTS seller_daily_medicine_sales;
+DROP MATERIALIZED VIEW IF EXISTS seller_daily_sales;
+
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS sub_orders;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,79 @@
+-- A patient's checkout. The cart is split into one sub-order per seller,
+-- which each seller fulfils on their own. Orders are sales history, so
+-- nothing deletes them with the patient or seller they belong to: deleting
+-- an account with orders is refused instead.
+CREATE TABLE orders (
+    "id" SERIAL PRIMARY KEY,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE RESTRICT,
+    "delivery_address" VARCHAR NOT NULL,
+    "total_amount" NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    "payment_status" VARCHAR NOT NULL DEFAULT 'pending'
+        CHECK (payment_status IN ('pending', 'paid', 'refunded')),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_orders_patient ON orders (patient_username, created_at);
+
+CREATE TABLE sub_orders (
+    "id" SERIAL PRIMARY KEY,
+    "order_id" INTEGER NOT NULL REFERENCES orders(id) ON DELETE RESTRICT,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE RESTRICT,
+    "status" VARCHAR NOT NULL DEFAULT 'placed' CHECK (status IN (
+        'placed', 'confirmed', 'shipped', 'delivered', 'cancelled'
+    )),
+    "subtotal" NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "delivered_at" TIMESTAMP,
+    UNIQUE (order_id, seller_username)
+);
+
+CREATE INDEX idx_sub_orders_seller ON sub_orders (seller_username, created_at);
+
+-- Order lines keep the medicine's name and price as sold; medicine_id has
+-- no foreign key so the sale outlives the listing
+CREATE TABLE order_items (
+    "id" SERIAL PRIMARY KEY,
+    "sub_order_id" INTEGER NOT NULL REFERENCES sub_orders(id) ON DELETE RESTRICT,
+    "medicine_id" INTEGER NOT NULL,
+    "medicine_name" VARCHAR NOT NULL,
+    "batch_number" VARCHAR NOT NULL DEFAULT '',
+    "quantity" INTEGER NOT NULL CHECK (quantity > 0),
+    "unit_price" NUMERIC(10, 2) NOT NULL CHECK (unit_price >= 0),
+    "discount" INTEGER NOT NULL DEFAULT 0 CHECK (discount >= 0 AND discount <= 100),
+    "line_total" NUMERIC(12, 2) NOT NULL CHECK (line_total >= 0)
+);
+
+CREATE INDEX idx_order_items_sub_order ON order_items (sub_order_id);
+CREATE INDEX idx_order_items_medicine ON order_items (medicine_id);
+
+-- Daily sales rollups for the seller analytics. Cancelled sub-orders are
+-- left out. They are refreshed periodically by the server, so the current
+-- day may lag behind by up to ANALYTICS_REFRESH_PERIOD.
+CREATE MATERIALIZED VIEW seller_daily_sales AS
+SELECT so.seller_username,
+    so.created_at::DATE AS day,
+    COUNT(DISTINCT so.id)::INT AS orders,
+    SUM(oi.quantity)::INT AS units,
+    SUM(oi.line_total)::NUMERIC(14, 2) AS revenue
+FROM sub_orders so
+JOIN order_items oi ON oi.sub_order_id = so.id
+WHERE so.status <> 'cancelled'
+GROUP BY so.seller_username, so.created_at::DATE;
+
+CREATE UNIQUE INDEX idx_seller_daily_sales ON seller_daily_sales (seller_username, day);
+
+CREATE MATERIALIZED VIEW seller_daily_medicine_sales AS
+SELECT so.seller_username,
+    so.created_at::DATE AS day,
+    oi.medicine_id,
+    MAX(oi.medicine_name)::VARCHAR AS medicine_name,
+    SUM(oi.quantity)::INT AS units,
+    SUM(oi.line_total)::NUMERIC(14, 2) AS revenue
+FROM sub_orders so
+JOIN order_items oi ON oi.sub_order_id = so.id
+WHERE so.status <> 'cancelled'
+GROUP BY so.seller_username, so.created_at::DATE, oi.medicine_id;
+
+CREATE UNIQUE INDEX idx_seller_daily_medicine_sales
+    ON seller_daily_medicine_sales (seller_username, day, medicine_id);
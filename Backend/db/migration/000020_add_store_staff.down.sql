@@ -0,0 +1,3 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS prescription_required;
+DROP TABLE IF EXISTS staff_invites;
+DROP TABLE IF EXISTS store_staff;
@@ -0,0 +1,39 @@
+-- Staff accounts that log in on behalf of a store. The store itself is the
+-- seller account, whose login acts as the store's owner.
+CREATE TABLE store_staff (
+    "username" VARCHAR PRIMARY KEY,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "full_name" VARCHAR NOT NULL,
+    "email" VARCHAR UNIQUE NOT NULL,
+    "password" VARCHAR NOT NULL,
+    "staff_role" VARCHAR NOT NULL
+        CHECK (staff_role IN ('owner', 'pharmacist', 'inventory_clerk')),
+    "invited_by" VARCHAR NOT NULL,
+    "password_changed_at" TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00',
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_store_staff_seller ON store_staff (seller_username);
+
+-- Only a SHA-256 hash of the invite token is kept
+CREATE TABLE staff_invites (
+    "id" SERIAL PRIMARY KEY,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "email" VARCHAR NOT NULL,
+    "staff_role" VARCHAR NOT NULL
+        CHECK (staff_role IN ('owner', 'pharmacist', 'inventory_clerk')),
+    "token_hash" VARCHAR UNIQUE NOT NULL,
+    "invited_by" VARCHAR NOT NULL,
+    "expires_at" TIMESTAMP NOT NULL,
+    "accepted_at" TIMESTAMP,
+    "revoked_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+-- At most one pending invite per store and email
+CREATE UNIQUE INDEX idx_staff_invites_pending
+    ON staff_invites (seller_username, lower(email))
+    WHERE accepted_at IS NULL AND revoked_at IS NULL;
+
+-- Orders for prescription medicines have to be confirmed by a pharmacist
+ALTER TABLE order_items ADD COLUMN "prescription_required" BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_transfers;
+ALTER TABLE sellers DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,49 @@
+-- A pharmacy chain. Each branch is a seller account of its own, with its own
+-- inventory, opening hours and location; the owner is the head office
+-- branch, which manages the others.
+CREATE TABLE organizations (
+    "id" SERIAL PRIMARY KEY,
+    "name" VARCHAR NOT NULL,
+    "owner_username" VARCHAR NOT NULL UNIQUE REFERENCES sellers(username) ON DELETE CASCADE,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE sellers ADD COLUMN organization_id INTEGER REFERENCES organizations(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_sellers_organization ON sellers (organization_id);
+
+-- Stock moved between two branches of an organisation. Like purchase order
+-- items, a transfer keeps a copy of the source medicine as requested, which
+-- is what the receiving branch's stock is created from.
+CREATE TABLE stock_transfers (
+    "id" SERIAL PRIMARY KEY,
+    "organization_id" INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    "from_seller" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "to_seller" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "status" VARCHAR NOT NULL DEFAULT 'requested' CHECK (status IN (
+        'requested', 'rejected', 'cancelled', 'dispatched', 'received'
+    )),
+    "medicine_id" INTEGER NOT NULL,
+    "name" VARCHAR NOT NULL,
+    "description" TEXT NOT NULL,
+    "active_ingredients" VARCHAR NOT NULL DEFAULT '',
+    "strength" VARCHAR NOT NULL DEFAULT '',
+    "dosage_form" VARCHAR NOT NULL DEFAULT '',
+    "prescription_required" BOOLEAN NOT NULL DEFAULT false,
+    "batch_number" VARCHAR NOT NULL DEFAULT '',
+    "expiry_date" DATE NOT NULL,
+    "price" NUMERIC(10, 2) NOT NULL,
+    "quantity" INTEGER NOT NULL CHECK (quantity > 0),
+    "notes" TEXT NOT NULL DEFAULT '',
+    "requested_by" VARCHAR NOT NULL,
+    "dispatched_by" VARCHAR NOT NULL DEFAULT '',
+    "received_by" VARCHAR NOT NULL DEFAULT '',
+    "received_medicine_id" INTEGER,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    CHECK (from_seller <> to_seller)
+);
+
+CREATE INDEX idx_stock_transfers_from ON stock_transfers (from_seller, created_at);
+CREATE INDEX idx_stock_transfers_to ON stock_transfers (to_seller, created_at);
+CREATE INDEX idx_stock_transfers_organization ON stock_transfers (organization_id, created_at);
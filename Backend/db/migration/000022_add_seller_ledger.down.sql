@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS chargebacks;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP FUNCTION IF EXISTS check_ledger_transaction_balanced;
+DROP FUNCTION IF EXISTS prevent_ledger_changes;
+DROP TABLE IF EXISTS payouts;
+DROP TABLE IF EXISTS payout_batches;
+DROP TABLE IF EXISTS commission_rates;
@@ -0,0 +1,127 @@
+-- Commission the platform keeps on each delivered sub-order, by seller type.
+-- Rates are changed by the operators (see
+-- `PUT /api/admin/commission-rates/:seller_type`).
+CREATE TABLE commission_rates (
+    "seller_type" VARCHAR PRIMARY KEY,
+    "rate_percent" NUMERIC(5, 2) NOT NULL CHECK (rate_percent >= 0 AND rate_percent <= 100),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+INSERT INTO commission_rates (seller_type, rate_percent) VALUES
+    ('retail', 10),
+    ('wholesale', 5),
+    ('hospital', 0),
+    ('ngo', 0);
+
+-- Payout batches settle every seller with money owed to them up to the
+-- batch's cutoff. Each payout carries the seller's settlement statement for
+-- the period since their previous payout.
+CREATE TABLE payout_batches (
+    "id" SERIAL PRIMARY KEY,
+    "cutoff" TIMESTAMP NOT NULL,
+    "payouts" INTEGER NOT NULL DEFAULT 0,
+    "total_amount" NUMERIC(14, 2) NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE payouts (
+    "id" SERIAL PRIMARY KEY,
+    "batch_id" INTEGER NOT NULL REFERENCES payout_batches(id) ON DELETE CASCADE,
+    "seller_username" VARCHAR NOT NULL,
+    "period_start" TIMESTAMP,
+    "period_end" TIMESTAMP NOT NULL,
+    "opening_balance" NUMERIC(14, 2) NOT NULL,
+    "gross_sales" NUMERIC(14, 2) NOT NULL,
+    "commission" NUMERIC(14, 2) NOT NULL,
+    "refunds" NUMERIC(14, 2) NOT NULL,
+    "chargebacks" NUMERIC(14, 2) NOT NULL,
+    "amount" NUMERIC(14, 2) NOT NULL CHECK (amount > 0),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    UNIQUE (batch_id, seller_username)
+);
+
+CREATE INDEX idx_payouts_seller ON payouts (seller_username, period_end);
+
+-- Double-entry ledger of the money the platform holds for sellers. Every
+-- transaction concerns one seller and its entries sum to zero; amounts are
+-- debits when positive and credits when negative. Accounts:
+--   customer_payments    money taken from patients
+--   seller_payable       what is owed to the seller (a credit balance)
+--   platform_commission  the platform's revenue
+--   payouts              money paid out to sellers
+-- Like the stock ledger there are no foreign keys, so entries outlive the
+-- orders and sellers they describe, and nothing is ever updated or deleted.
+CREATE TABLE ledger_transactions (
+    "id" BIGSERIAL PRIMARY KEY,
+    "kind" VARCHAR NOT NULL CHECK (kind IN ('earning', 'refund', 'chargeback', 'payout')),
+    "seller_username" VARCHAR NOT NULL,
+    "sub_order_id" INTEGER,
+    "payout_id" INTEGER,
+    -- The commission rate applied to the sub-order; refunds and chargebacks
+    -- give back commission at the same rate
+    "commission_rate" NUMERIC(5, 2),
+    "description" TEXT NOT NULL DEFAULT '',
+    "created_by" VARCHAR NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    CHECK (kind = 'payout' OR sub_order_id IS NOT NULL),
+    CHECK (kind <> 'payout' OR payout_id IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX idx_ledger_transactions_earning
+    ON ledger_transactions (sub_order_id) WHERE kind = 'earning';
+CREATE INDEX idx_ledger_transactions_seller ON ledger_transactions (seller_username, created_at);
+
+CREATE TABLE ledger_entries (
+    "id" BIGSERIAL PRIMARY KEY,
+    "transaction_id" BIGINT NOT NULL REFERENCES ledger_transactions(id),
+    "account" VARCHAR NOT NULL CHECK (account IN (
+        'customer_payments', 'seller_payable', 'platform_commission', 'payouts'
+    )),
+    "amount" NUMERIC(14, 2) NOT NULL CHECK (amount <> 0)
+);
+
+CREATE INDEX idx_ledger_entries_transaction ON ledger_entries (transaction_id);
+
+CREATE FUNCTION prevent_ledger_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_transactions_append_only
+BEFORE UPDATE OR DELETE ON ledger_transactions
+FOR EACH ROW EXECUTE FUNCTION prevent_ledger_changes();
+
+CREATE TRIGGER ledger_entries_append_only
+BEFORE UPDATE OR DELETE ON ledger_entries
+FOR EACH ROW EXECUTE FUNCTION prevent_ledger_changes();
+
+-- Checked at commit so that all entries of a transaction can be inserted
+-- first
+CREATE FUNCTION check_ledger_transaction_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % does not balance', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+AFTER INSERT ON ledger_entries
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION check_ledger_transaction_balanced();
+
+-- Chargebacks reported by the payment provider, recorded by the operators
+-- (see `POST /api/admin/sub-orders/:id/chargebacks`). The payout job posts
+-- them to the ledger before settling.
+CREATE TABLE chargebacks (
+    "id" SERIAL PRIMARY KEY,
+    "sub_order_id" INTEGER NOT NULL REFERENCES sub_orders(id) ON DELETE CASCADE,
+    "amount" NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    "reason" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "posted_at" TIMESTAMP
+);
+
+CREATE INDEX idx_chargebacks_pending ON chargebacks (created_at) WHERE posted_at IS NULL;
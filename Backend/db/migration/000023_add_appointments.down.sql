@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS appointments;
+DROP TABLE IF EXISTS doctor_leaves;
+DROP TABLE IF EXISTS doctor_availability_exceptions;
+DROP TABLE IF EXISTS doctor_availability;
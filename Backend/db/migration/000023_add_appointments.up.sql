@@ -0,0 +1,73 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Weekly hours a doctor takes appointments in, cut into slots of
+-- slot_minutes. Times are the doctor's local time.
+CREATE TABLE doctor_availability (
+    "id" SERIAL PRIMARY KEY,
+    "doctor_username" VARCHAR NOT NULL REFERENCES doctors(username) ON DELETE CASCADE,
+    "day_of_week" SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    "starts_at" TIME NOT NULL,
+    "ends_at" TIME NOT NULL,
+    "slot_minutes" INTEGER NOT NULL CHECK (slot_minutes BETWEEN 5 AND 240),
+    CHECK (ends_at > starts_at),
+    UNIQUE(doctor_username, day_of_week, starts_at)
+);
+
+-- Changes to the weekly hours on one date: either a block of time the
+-- doctor is unavailable, or extra hours they take appointments in
+CREATE TABLE doctor_availability_exceptions (
+    "id" SERIAL PRIMARY KEY,
+    "doctor_username" VARCHAR NOT NULL REFERENCES doctors(username) ON DELETE CASCADE,
+    "date" DATE NOT NULL,
+    "starts_at" TIME NOT NULL,
+    "ends_at" TIME NOT NULL,
+    "available" BOOLEAN NOT NULL,
+    "slot_minutes" INTEGER CHECK (slot_minutes BETWEEN 5 AND 240),
+    "reason" VARCHAR NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    CHECK (ends_at > starts_at),
+    CHECK (available = (slot_minutes IS NOT NULL))
+);
+
+CREATE INDEX idx_doctor_availability_exceptions_date ON doctor_availability_exceptions (doctor_username, date);
+
+-- Whole days off, both dates inclusive
+CREATE TABLE doctor_leaves (
+    "id" SERIAL PRIMARY KEY,
+    "doctor_username" VARCHAR NOT NULL REFERENCES doctors(username) ON DELETE CASCADE,
+    "starts_on" DATE NOT NULL,
+    "ends_on" DATE NOT NULL,
+    "reason" VARCHAR NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    CHECK (ends_on >= starts_on)
+);
+
+CREATE INDEX idx_doctor_leaves_dates ON doctor_leaves (doctor_username, starts_on, ends_on);
+
+CREATE TABLE appointments (
+    "id" SERIAL PRIMARY KEY,
+    "doctor_username" VARCHAR NOT NULL REFERENCES doctors(username) ON DELETE CASCADE,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE CASCADE,
+    "starts_at" TIMESTAMP NOT NULL,
+    "ends_at" TIMESTAMP NOT NULL,
+    "status" VARCHAR NOT NULL DEFAULT 'booked'
+        CHECK (status IN ('booked', 'cancelled')),
+    "reason" VARCHAR NOT NULL DEFAULT '',
+    "cancelled_by" VARCHAR,
+    "cancellation_reason" VARCHAR,
+    "rescheduled_count" INTEGER NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    CHECK (ends_at > starts_at),
+    -- A doctor's booked appointments never overlap, and neither do a
+    -- patient's
+    CONSTRAINT appointments_doctor_no_overlap
+        EXCLUDE USING gist (doctor_username WITH =, tsrange(starts_at, ends_at) WITH &&)
+        WHERE (status = 'booked'),
+    CONSTRAINT appointments_patient_no_overlap
+        EXCLUDE USING gist (patient_username WITH =, tsrange(starts_at, ends_at) WITH &&)
+        WHERE (status = 'booked')
+);
+
+CREATE INDEX idx_appointments_doctor ON appointments (doctor_username, starts_at);
+CREATE INDEX idx_appointments_patient ON appointments (patient_username, starts_at);
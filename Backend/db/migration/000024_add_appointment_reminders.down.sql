@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS appointment_reminders;
+
+UPDATE appointments SET status = 'cancelled' WHERE status IN ('completed', 'no_show');
+ALTER TABLE appointments DROP CONSTRAINT appointments_status_check;
+ALTER TABLE appointments ADD CONSTRAINT appointments_status_check
+    CHECK (status IN ('booked', 'cancelled'));
+
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS doctor_checked_in_at,
+    DROP COLUMN IF EXISTS patient_checked_in_at;
+
+ALTER TABLE doctors DROP COLUMN IF EXISTS cancellation_window_hours;
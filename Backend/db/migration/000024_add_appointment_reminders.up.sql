@@ -0,0 +1,22 @@
+-- Patients can't cancel or reschedule within this many hours of the start
+ALTER TABLE doctors ADD COLUMN cancellation_window_hours INTEGER NOT NULL DEFAULT 0
+    CHECK (cancellation_window_hours BETWEEN 0 AND 168);
+
+-- Appointments are closed after they end: completed when either side
+-- checked in, no_show when neither did
+ALTER TABLE appointments
+    ADD COLUMN patient_checked_in_at TIMESTAMP,
+    ADD COLUMN doctor_checked_in_at TIMESTAMP;
+
+ALTER TABLE appointments DROP CONSTRAINT appointments_status_check;
+ALTER TABLE appointments ADD CONSTRAINT appointments_status_check
+    CHECK (status IN ('booked', 'cancelled', 'completed', 'no_show'));
+
+-- One row per reminder sent, claimed before the email goes out so that a
+-- restart never sends the same reminder twice
+CREATE TABLE appointment_reminders (
+    "appointment_id" INTEGER NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+    "kind" VARCHAR NOT NULL CHECK (kind IN ('24h', '1h')),
+    "sent_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    PRIMARY KEY (appointment_id, kind)
+);
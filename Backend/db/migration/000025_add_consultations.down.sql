@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS consultation_messages;
+DROP TABLE IF EXISTS consultation_attachments;
+DROP TABLE IF EXISTS consultations;
@@ -0,0 +1,47 @@
+-- A consultation is the chat between the patient and the doctor of one
+-- appointment. While it is open its messages live in consultation_messages;
+-- closing it archives them into transcript and clears the live rows.
+CREATE TABLE consultations (
+    "id" SERIAL PRIMARY KEY,
+    "appointment_id" INTEGER NOT NULL UNIQUE REFERENCES appointments(id) ON DELETE CASCADE,
+    "doctor_username" VARCHAR NOT NULL REFERENCES doctors(username) ON DELETE CASCADE,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE CASCADE,
+    "status" VARCHAR NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    "transcript" JSONB,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "closed_at" TIMESTAMP,
+    "closed_by" VARCHAR,
+    CHECK ((status = 'closed') = (transcript IS NOT NULL))
+);
+
+CREATE INDEX idx_consultations_doctor ON consultations (doctor_username, created_at);
+CREATE INDEX idx_consultations_patient ON consultations (patient_username, created_at);
+
+-- Files shared in a consultation, such as lab reports. They are kept after
+-- the consultation is closed so the transcript can still link to them.
+CREATE TABLE consultation_attachments (
+    "id" SERIAL PRIMARY KEY,
+    "consultation_id" INTEGER NOT NULL REFERENCES consultations(id) ON DELETE CASCADE,
+    "uploaded_by" VARCHAR NOT NULL,
+    "file_name" VARCHAR NOT NULL,
+    "content_type" VARCHAR NOT NULL,
+    "size_bytes" INTEGER NOT NULL CHECK (size_bytes > 0),
+    "data" BYTEA NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_consultation_attachments_consultation ON consultation_attachments (consultation_id);
+
+CREATE TABLE consultation_messages (
+    "id" SERIAL PRIMARY KEY,
+    "consultation_id" INTEGER NOT NULL REFERENCES consultations(id) ON DELETE CASCADE,
+    "sender_username" VARCHAR NOT NULL,
+    "body" TEXT NOT NULL DEFAULT '',
+    "attachment_id" INTEGER REFERENCES consultation_attachments(id),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    -- Set when the other side has read the message
+    "read_at" TIMESTAMP,
+    CHECK (body <> '' OR attachment_id IS NOT NULL)
+);
+
+CREATE INDEX idx_consultation_messages_consultation ON consultation_messages (consultation_id, id);
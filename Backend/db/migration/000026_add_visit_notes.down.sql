@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS prescription_items;
+DROP TABLE IF EXISTS visit_notes;
+DROP TABLE IF EXISTS icd10_codes;
@@ -0,0 +1,56 @@
+-- ICD-10 code table diagnoses are coded against. It is loaded by the
+-- operators (see `make loadicd10`).
+CREATE TABLE icd10_codes (
+    "code" VARCHAR(8) PRIMARY KEY,
+    "description" VARCHAR NOT NULL
+);
+
+CREATE INDEX idx_icd10_codes_description ON icd10_codes USING gin (description gin_trgm_ops);
+
+-- The doctor's record of an appointment. Drafts are only seen by the
+-- doctor writing them; once finalized the note can't be changed and becomes
+-- part of the patient's history.
+CREATE TABLE visit_notes (
+    "id" SERIAL PRIMARY KEY,
+    "appointment_id" INTEGER NOT NULL UNIQUE REFERENCES appointments(id) ON DELETE CASCADE,
+    "doctor_username" VARCHAR NOT NULL REFERENCES doctors(username) ON DELETE CASCADE,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE CASCADE,
+    "chief_complaint" TEXT NOT NULL DEFAULT '',
+    -- Vitals, each left empty when not taken
+    "bp_systolic" INTEGER CHECK (bp_systolic BETWEEN 40 AND 300),
+    "bp_diastolic" INTEGER CHECK (bp_diastolic BETWEEN 20 AND 200),
+    "pulse_bpm" INTEGER CHECK (pulse_bpm BETWEEN 20 AND 250),
+    "temperature_c" DOUBLE PRECISION CHECK (temperature_c BETWEEN 30 AND 45),
+    "respiratory_rate" INTEGER CHECK (respiratory_rate BETWEEN 4 AND 80),
+    "spo2_percent" INTEGER CHECK (spo2_percent BETWEEN 50 AND 100),
+    "weight_kg" DOUBLE PRECISION CHECK (weight_kg > 0 AND weight_kg <= 400),
+    "height_cm" DOUBLE PRECISION CHECK (height_cm > 0 AND height_cm <= 260),
+    "diagnosis" TEXT NOT NULL DEFAULT '',
+    "icd10_code" VARCHAR(8) REFERENCES icd10_codes(code),
+    "advice" TEXT NOT NULL DEFAULT '',
+    "status" VARCHAR NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'finalized')),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "finalized_at" TIMESTAMP,
+    CHECK (status = 'draft' OR (chief_complaint <> '' AND diagnosis <> '')),
+    CHECK ((status = 'finalized') = (finalized_at IS NOT NULL))
+);
+
+CREATE INDEX idx_visit_notes_patient ON visit_notes (patient_username, created_at);
+CREATE INDEX idx_visit_notes_doctor ON visit_notes (doctor_username, created_at);
+
+-- The prescription written with a visit note
+CREATE TABLE prescription_items (
+    "id" SERIAL PRIMARY KEY,
+    "visit_note_id" INTEGER NOT NULL REFERENCES visit_notes(id) ON DELETE CASCADE,
+    "medicine_name" VARCHAR NOT NULL,
+    "active_ingredients" VARCHAR NOT NULL DEFAULT '',
+    "strength" VARCHAR NOT NULL DEFAULT '',
+    "dosage_form" VARCHAR NOT NULL DEFAULT '',
+    "dose" VARCHAR NOT NULL,
+    "frequency" VARCHAR NOT NULL,
+    "duration_days" INTEGER NOT NULL CHECK (duration_days BETWEEN 1 AND 365),
+    "instructions" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_prescription_items_visit_note ON prescription_items (visit_note_id);
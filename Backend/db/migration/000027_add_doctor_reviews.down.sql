@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS doctor_reviews;
+DROP FUNCTION IF EXISTS refresh_doctor_rating();
+DROP FUNCTION IF EXISTS refresh_doctor_rating_of(VARCHAR);
+
+ALTER TABLE doctors
+    DROP COLUMN IF EXISTS review_count,
+    DROP COLUMN IF EXISTS rating_average;
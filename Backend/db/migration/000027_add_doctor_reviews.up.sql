@@ -0,0 +1,64 @@
+-- Published reviews summarised on the doctor, kept up to date by a trigger.
+-- rating_average is NULL until the first review is published.
+ALTER TABLE doctors
+    ADD COLUMN rating_average NUMERIC(3, 2),
+    ADD COLUMN review_count INTEGER NOT NULL DEFAULT 0;
+
+-- One review per completed appointment. Reviews that look like they carry
+-- contact details or links are held as pending until an operator publishes
+-- or rejects them (see `PUT /api/admin/doctor-reviews/:id/moderation`).
+CREATE TABLE doctor_reviews (
+    "id" SERIAL PRIMARY KEY,
+    "appointment_id" INTEGER NOT NULL UNIQUE REFERENCES appointments(id) ON DELETE CASCADE,
+    "doctor_username" VARCHAR NOT NULL REFERENCES doctors(username) ON DELETE CASCADE,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE CASCADE,
+    "rating" SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    "comment" TEXT NOT NULL DEFAULT '',
+    "status" VARCHAR NOT NULL DEFAULT 'published'
+        CHECK (status IN ('pending', 'published', 'rejected')),
+    -- Why the review was held or rejected
+    "moderation_note" VARCHAR NOT NULL DEFAULT '',
+    -- Doctors can report a published review for an operator to look at
+    "reported_at" TIMESTAMP,
+    "report_reason" VARCHAR,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "moderated_at" TIMESTAMP
+);
+
+CREATE INDEX idx_doctor_reviews_doctor ON doctor_reviews (doctor_username, status, created_at);
+CREATE INDEX idx_doctor_reviews_moderation ON doctor_reviews (created_at)
+    WHERE status = 'pending' OR reported_at IS NOT NULL;
+
+-- The doctor is locked before the reviews are summed, so that a review
+-- committed at the same time is counted by whichever refresh runs second.
+-- Each statement sees the reviews committed before it started.
+CREATE FUNCTION refresh_doctor_rating_of(doctor VARCHAR) RETURNS void AS $$
+    SELECT 1 FROM doctors WHERE username = doctor FOR UPDATE;
+
+    UPDATE doctors SET
+        rating_average = summary.average,
+        review_count = summary.reviews
+    FROM (
+        SELECT ROUND(AVG(rating), 2) AS average, COUNT(*) AS reviews
+        FROM doctor_reviews
+        WHERE doctor_username = doctor AND status = 'published'
+    ) summary
+    WHERE username = doctor;
+$$ LANGUAGE sql;
+
+CREATE FUNCTION refresh_doctor_rating() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_doctor_rating_of(OLD.doctor_username);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND
+        (TG_OP = 'INSERT' OR NEW.doctor_username <> OLD.doctor_username) THEN
+        PERFORM refresh_doctor_rating_of(NEW.doctor_username);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER doctor_reviews_refresh_rating
+AFTER INSERT OR UPDATE OR DELETE ON doctor_reviews
+FOR EACH ROW EXECUTE FUNCTION refresh_doctor_rating();
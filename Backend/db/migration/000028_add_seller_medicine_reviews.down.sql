@@ -0,0 +1,19 @@
+DROP TRIGGER IF EXISTS medicines_inherit_rating ON medicines;
+DROP FUNCTION IF EXISTS inherit_medicine_rating();
+DROP TABLE IF EXISTS medicine_reviews;
+DROP TABLE IF EXISTS seller_reviews;
+DROP FUNCTION IF EXISTS refresh_medicine_rating();
+DROP FUNCTION IF EXISTS refresh_medicine_rating_of(VARCHAR, VARCHAR);
+DROP FUNCTION IF EXISTS refresh_seller_rating();
+DROP FUNCTION IF EXISTS refresh_seller_rating_of(VARCHAR);
+
+ALTER TABLE medicines
+    DROP COLUMN IF EXISTS review_count,
+    DROP COLUMN IF EXISTS rating_average;
+
+ALTER TABLE sellers
+    DROP COLUMN IF EXISTS review_count,
+    DROP COLUMN IF EXISTS authenticity_rating_average,
+    DROP COLUMN IF EXISTS packaging_rating_average,
+    DROP COLUMN IF EXISTS delivery_rating_average,
+    DROP COLUMN IF EXISTS rating_average;
@@ -0,0 +1,155 @@
+-- Published reviews summarised on the seller and the medicine, kept up to
+-- date by triggers like the doctors' ratings. A seller's rating_average is
+-- the average of the three aspects over all its reviews. A medicine's rating
+-- covers every batch the seller has listed under its name, so restocking
+-- keeps the reviews.
+ALTER TABLE sellers
+    ADD COLUMN rating_average NUMERIC(3, 2),
+    ADD COLUMN delivery_rating_average NUMERIC(3, 2),
+    ADD COLUMN packaging_rating_average NUMERIC(3, 2),
+    ADD COLUMN authenticity_rating_average NUMERIC(3, 2),
+    ADD COLUMN review_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE medicines
+    ADD COLUMN rating_average NUMERIC(3, 2),
+    ADD COLUMN review_count INTEGER NOT NULL DEFAULT 0;
+
+-- Verified-purchase reviews of a seller, one per delivered sub-order
+CREATE TABLE seller_reviews (
+    "id" SERIAL PRIMARY KEY,
+    "sub_order_id" INTEGER NOT NULL UNIQUE REFERENCES sub_orders(id) ON DELETE CASCADE,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE CASCADE,
+    "delivery_rating" SMALLINT NOT NULL CHECK (delivery_rating BETWEEN 1 AND 5),
+    "packaging_rating" SMALLINT NOT NULL CHECK (packaging_rating BETWEEN 1 AND 5),
+    "authenticity_rating" SMALLINT NOT NULL CHECK (authenticity_rating BETWEEN 1 AND 5),
+    "comment" TEXT NOT NULL DEFAULT '',
+    "status" VARCHAR NOT NULL DEFAULT 'published'
+        CHECK (status IN ('pending', 'published', 'rejected')),
+    "moderation_note" VARCHAR NOT NULL DEFAULT '',
+    -- The store's public reply and the staff member who wrote it
+    "seller_reply" TEXT,
+    "replied_by" VARCHAR,
+    "replied_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "moderated_at" TIMESTAMP
+);
+
+CREATE INDEX idx_seller_reviews_seller ON seller_reviews (seller_username, status, created_at);
+
+-- Verified-purchase reviews of a medicine, one per delivered order line.
+-- Like the order line, medicine_id has no foreign key so the review
+-- outlives the listing.
+CREATE TABLE medicine_reviews (
+    "id" SERIAL PRIMARY KEY,
+    "order_item_id" INTEGER NOT NULL UNIQUE REFERENCES order_items(id) ON DELETE CASCADE,
+    "medicine_id" INTEGER NOT NULL,
+    "medicine_name" VARCHAR NOT NULL,
+    "seller_username" VARCHAR NOT NULL REFERENCES sellers(username) ON DELETE CASCADE,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE CASCADE,
+    "rating" SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    "comment" TEXT NOT NULL DEFAULT '',
+    "status" VARCHAR NOT NULL DEFAULT 'published'
+        CHECK (status IN ('pending', 'published', 'rejected')),
+    "moderation_note" VARCHAR NOT NULL DEFAULT '',
+    "seller_reply" TEXT,
+    "replied_by" VARCHAR,
+    "replied_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "moderated_at" TIMESTAMP
+);
+
+CREATE INDEX idx_medicine_reviews_product
+    ON medicine_reviews (seller_username, medicine_name, status, created_at);
+CREATE INDEX idx_medicine_reviews_seller ON medicine_reviews (seller_username, created_at);
+
+-- As with doctors, the seller or medicine is locked before its reviews are
+-- summed so that reviews committed at the same time are all counted.
+CREATE FUNCTION refresh_seller_rating_of(seller VARCHAR) RETURNS void AS $$
+    SELECT 1 FROM sellers WHERE username = seller FOR UPDATE;
+
+    UPDATE sellers SET
+        rating_average = summary.average,
+        delivery_rating_average = summary.delivery,
+        packaging_rating_average = summary.packaging,
+        authenticity_rating_average = summary.authenticity,
+        review_count = summary.reviews
+    FROM (
+        SELECT ROUND(AVG((delivery_rating + packaging_rating + authenticity_rating) / 3.0), 2) AS average,
+            ROUND(AVG(delivery_rating), 2) AS delivery,
+            ROUND(AVG(packaging_rating), 2) AS packaging,
+            ROUND(AVG(authenticity_rating), 2) AS authenticity,
+            COUNT(*) AS reviews
+        FROM seller_reviews
+        WHERE seller_username = seller AND status = 'published'
+    ) summary
+    WHERE username = seller;
+$$ LANGUAGE sql;
+
+CREATE FUNCTION refresh_seller_rating() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_seller_rating_of(OLD.seller_username);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND
+        (TG_OP = 'INSERT' OR NEW.seller_username <> OLD.seller_username) THEN
+        PERFORM refresh_seller_rating_of(NEW.seller_username);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER seller_reviews_refresh_rating
+AFTER INSERT OR UPDATE OR DELETE ON seller_reviews
+FOR EACH ROW EXECUTE FUNCTION refresh_seller_rating();
+
+CREATE FUNCTION refresh_medicine_rating_of(seller VARCHAR, product VARCHAR) RETURNS void AS $$
+    SELECT 1 FROM medicines
+    WHERE seller_username = seller AND name = product
+    ORDER BY id
+    FOR UPDATE;
+
+    UPDATE medicines SET
+        rating_average = summary.average,
+        review_count = summary.reviews
+    FROM (
+        SELECT ROUND(AVG(rating), 2) AS average, COUNT(*) AS reviews
+        FROM medicine_reviews
+        WHERE seller_username = seller AND medicine_name = product AND status = 'published'
+    ) summary
+    WHERE seller_username = seller AND name = product;
+$$ LANGUAGE sql;
+
+CREATE FUNCTION refresh_medicine_rating() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_medicine_rating_of(OLD.seller_username, OLD.medicine_name);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND (TG_OP = 'INSERT' OR
+        (NEW.seller_username, NEW.medicine_name) IS DISTINCT FROM (OLD.seller_username, OLD.medicine_name)) THEN
+        PERFORM refresh_medicine_rating_of(NEW.seller_username, NEW.medicine_name);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER medicine_reviews_refresh_rating
+AFTER INSERT OR UPDATE OR DELETE ON medicine_reviews
+FOR EACH ROW EXECUTE FUNCTION refresh_medicine_rating();
+
+-- A new batch, or a medicine renamed to one the seller already sells, takes
+-- on the rating of the reviews under its name
+CREATE FUNCTION inherit_medicine_rating() RETURNS trigger AS $$
+BEGIN
+    SELECT ROUND(AVG(rating), 2), COUNT(*)
+    INTO NEW.rating_average, NEW.review_count
+    FROM medicine_reviews
+    WHERE seller_username = NEW.seller_username AND medicine_name = NEW.name
+      AND status = 'published';
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER medicines_inherit_rating
+BEFORE INSERT OR UPDATE OF name, seller_username ON medicines
+FOR EACH ROW EXECUTE FUNCTION inherit_medicine_rating();
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_doctors_languages;
+DROP INDEX IF EXISTS idx_doctors_hospital_name_trgm;
+DROP INDEX IF EXISTS idx_doctors_specialization_trgm;
+
+ALTER TABLE doctors
+    DROP COLUMN IF EXISTS languages;
@@ -0,0 +1,9 @@
+-- Languages a doctor consults in, lower-cased (see util.NormalizeLanguages)
+ALTER TABLE doctors
+    ADD COLUMN languages VARCHAR[] NOT NULL DEFAULT '{}';
+
+-- The directory matches specializations and hospitals by substring and
+-- similarity
+CREATE INDEX idx_doctors_specialization_trgm ON doctors USING GIN (lower(specialization) gin_trgm_ops);
+CREATE INDEX idx_doctors_hospital_name_trgm ON doctors USING GIN (hospital_name gin_trgm_ops);
+CREATE INDEX idx_doctors_languages ON doctors USING GIN (languages);
@@ -0,0 +1,23 @@
+DROP INDEX IF EXISTS idx_payments_appointment_id;
+
+ALTER TABLE payments
+    DROP CONSTRAINT IF EXISTS payments_single_purpose,
+    DROP COLUMN IF EXISTS refund_reason,
+    DROP COLUMN IF EXISTS refunded_at,
+    DROP COLUMN IF EXISTS appointment_id;
+
+DROP INDEX IF EXISTS idx_appointments_payment_due;
+
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS payment_due_by,
+    DROP COLUMN IF EXISTS payment_status,
+    DROP COLUMN IF EXISTS follow_up_of,
+    DROP COLUMN IF EXISTS fee,
+    DROP COLUMN IF EXISTS visit_type;
+
+ALTER TABLE doctors
+    DROP COLUMN IF EXISTS refund_window_hours,
+    DROP COLUMN IF EXISTS follow_up_discount,
+    DROP COLUMN IF EXISTS follow_up_window_days,
+    DROP COLUMN IF EXISTS video_fee,
+    DROP COLUMN IF EXISTS in_person_fee;
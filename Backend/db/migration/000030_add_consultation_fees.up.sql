@@ -0,0 +1,37 @@
+-- What a doctor charges per appointment. A follow-up booked within
+-- follow_up_window_days of a completed appointment that wasn't itself a
+-- follow-up gets follow_up_discount percent off. Patients who cancel at
+-- least refund_window_hours before the start are refunded in full.
+ALTER TABLE doctors
+    ADD COLUMN in_person_fee NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (in_person_fee >= 0),
+    ADD COLUMN video_fee NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (video_fee >= 0),
+    ADD COLUMN follow_up_window_days INTEGER NOT NULL DEFAULT 0
+        CHECK (follow_up_window_days BETWEEN 0 AND 365),
+    ADD COLUMN follow_up_discount INTEGER NOT NULL DEFAULT 0
+        CHECK (follow_up_discount BETWEEN 0 AND 100),
+    ADD COLUMN refund_window_hours INTEGER NOT NULL DEFAULT 24
+        CHECK (refund_window_hours BETWEEN 0 AND 720);
+
+-- The fee is fixed when the appointment is booked. Paid appointments hold
+-- their slot as booked with payment_status pending until payment_due_by;
+-- unpaid ones are then cancelled and their payment_status set to unpaid.
+ALTER TABLE appointments
+    ADD COLUMN visit_type VARCHAR NOT NULL DEFAULT 'in_person'
+        CHECK (visit_type IN ('in_person', 'video')),
+    ADD COLUMN fee NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN follow_up_of INTEGER REFERENCES appointments(id) ON DELETE SET NULL,
+    ADD COLUMN payment_status VARCHAR NOT NULL DEFAULT 'not_required'
+        CHECK (payment_status IN ('not_required', 'pending', 'paid', 'refunded', 'unpaid')),
+    ADD COLUMN payment_due_by TIMESTAMP;
+
+CREATE INDEX idx_appointments_payment_due ON appointments (payment_due_by)
+    WHERE payment_status = 'pending';
+
+-- A payment is for either an order or an appointment
+ALTER TABLE payments
+    ADD COLUMN appointment_id INTEGER REFERENCES appointments(id) ON DELETE SET NULL,
+    ADD COLUMN refunded_at TIMESTAMPTZ,
+    ADD COLUMN refund_reason TEXT,
+    ADD CONSTRAINT payments_single_purpose CHECK (order_id IS NULL OR appointment_id IS NULL);
+
+CREATE INDEX idx_payments_appointment_id ON payments(appointment_id);
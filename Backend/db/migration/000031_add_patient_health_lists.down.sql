@@ -0,0 +1,15 @@
+ALTER TABLE patient_profiles
+    DROP COLUMN IF EXISTS updated_at,
+    DROP CONSTRAINT IF EXISTS patient_profiles_username_key,
+    ADD COLUMN disease_allergies TEXT;
+
+-- Bring the lists back as free text
+UPDATE patient_profiles p SET disease_allergies = (
+    SELECT string_agg(CASE WHEN e.description <> '' THEN e.description ELSE t.name END, ', ' ORDER BY e.id)
+    FROM patient_health_entries e
+    JOIN clinical_terms t ON t.code = e.term_code
+    WHERE e.patient_username = p.username AND e.status = 'active'
+);
+
+DROP TABLE IF EXISTS patient_health_entries;
+DROP TABLE IF EXISTS clinical_terms;
@@ -0,0 +1,115 @@
+-- Local vocabulary allergies and chronic conditions are coded against.
+-- ingredients are the active ingredients, written as NormalizeIngredients
+-- does, a medicine is flagged for: the drugs an allergen cross-reacts with,
+-- or the ones to avoid with a condition. More terms can be loaded by the
+-- operators (see `make loadclinicalterms`).
+CREATE TABLE clinical_terms (
+    "code" VARCHAR(32) PRIMARY KEY,
+    "kind" VARCHAR NOT NULL CHECK (kind IN ('allergy', 'condition')),
+    "name" VARCHAR NOT NULL,
+    "ingredients" VARCHAR[] NOT NULL DEFAULT '{}',
+    -- Lets entries check that their kind matches their term's
+    UNIQUE (code, kind)
+);
+
+CREATE INDEX idx_clinical_terms_name ON clinical_terms USING gin (name gin_trgm_ops);
+
+INSERT INTO clinical_terms (code, kind, name, ingredients) VALUES
+    ('ALG-PENICILLIN', 'allergy', 'Penicillin', '{penicillin,amoxicillin,ampicillin,cloxacillin,piperacillin,benzathine penicillin}'),
+    ('ALG-CEPHALOSPORIN', 'allergy', 'Cephalosporin', '{cefalexin,cephalexin,cefadroxil,cefuroxime,cefixime,cefpodoxime,ceftriaxone,cefotaxime}'),
+    ('ALG-SULFONAMIDE', 'allergy', 'Sulfonamide', '{sulfamethoxazole,sulfadiazine,sulfasalazine}'),
+    ('ALG-MACROLIDE', 'allergy', 'Macrolide', '{azithromycin,clarithromycin,erythromycin}'),
+    ('ALG-FLUOROQUINOLONE', 'allergy', 'Fluoroquinolone', '{ciprofloxacin,levofloxacin,ofloxacin,norfloxacin,moxifloxacin}'),
+    ('ALG-TETRACYCLINE', 'allergy', 'Tetracycline', '{tetracycline,doxycycline,minocycline}'),
+    ('ALG-ASPIRIN', 'allergy', 'Aspirin', '{aspirin,acetylsalicylic acid}'),
+    ('ALG-NSAID', 'allergy', 'NSAID', '{aspirin,acetylsalicylic acid,ibuprofen,diclofenac,naproxen,ketorolac,mefenamic acid,aceclofenac,piroxicam,etoricoxib}'),
+    ('ALG-PARACETAMOL', 'allergy', 'Paracetamol', '{paracetamol,acetaminophen}'),
+    ('ALG-OPIOID', 'allergy', 'Opioid', '{codeine,tramadol,morphine,tapentadol,oxycodone}'),
+    ('ALG-SULFONYLUREA', 'allergy', 'Sulfonylurea', '{glimepiride,gliclazide,glibenclamide,glipizide}'),
+    ('ALG-LATEX', 'allergy', 'Latex', '{}'),
+    ('ALG-PEANUT', 'allergy', 'Peanut', '{}'),
+    ('ALG-EGG', 'allergy', 'Egg', '{}'),
+    ('ALG-SHELLFISH', 'allergy', 'Shellfish', '{}'),
+    ('ALG-LACTOSE', 'allergy', 'Lactose', '{}'),
+    ('ALG-DUST', 'allergy', 'Dust mite', '{}'),
+    ('ALG-POLLEN', 'allergy', 'Pollen', '{}'),
+    ('ALG-OTHER', 'allergy', 'Other allergy', '{}'),
+    ('CND-HYPERTENSION', 'condition', 'Hypertension', '{pseudoephedrine,phenylephrine}'),
+    ('CND-DIABETES-1', 'condition', 'Type 1 diabetes', '{}'),
+    ('CND-DIABETES-2', 'condition', 'Type 2 diabetes', '{}'),
+    ('CND-ASTHMA', 'condition', 'Asthma', '{propranolol,aspirin}'),
+    ('CND-COPD', 'condition', 'Chronic obstructive pulmonary disease', '{propranolol}'),
+    ('CND-CKD', 'condition', 'Chronic kidney disease', '{ibuprofen,diclofenac,naproxen,ketorolac,aceclofenac,etoricoxib}'),
+    ('CND-PEPTIC-ULCER', 'condition', 'Peptic ulcer disease', '{aspirin,ibuprofen,diclofenac,naproxen,ketorolac,aceclofenac,piroxicam}'),
+    ('CND-HEART-FAILURE', 'condition', 'Heart failure', '{ibuprofen,diclofenac,naproxen,pioglitazone}'),
+    ('CND-CORONARY', 'condition', 'Coronary artery disease', '{}'),
+    ('CND-EPILEPSY', 'condition', 'Epilepsy', '{tramadol,bupropion}'),
+    ('CND-HYPOTHYROIDISM', 'condition', 'Hypothyroidism', '{}'),
+    ('CND-HYPERTHYROIDISM', 'condition', 'Hyperthyroidism', '{}'),
+    ('CND-LIVER', 'condition', 'Chronic liver disease', '{}'),
+    ('CND-G6PD', 'condition', 'G6PD deficiency', '{primaquine,dapsone,nitrofurantoin}'),
+    ('CND-GLAUCOMA', 'condition', 'Glaucoma', '{}'),
+    ('CND-OTHER', 'condition', 'Other condition', '{}');
+
+-- A patient's allergies and chronic conditions. The "other" terms need a
+-- description and can be recorded more than once; every other term only
+-- once per patient.
+CREATE TABLE patient_health_entries (
+    "id" SERIAL PRIMARY KEY,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE CASCADE,
+    "kind" VARCHAR NOT NULL CHECK (kind IN ('allergy', 'condition')),
+    "term_code" VARCHAR(32) NOT NULL,
+    "description" VARCHAR NOT NULL DEFAULT '',
+    -- What happens on exposure to an allergen
+    "reaction" VARCHAR NOT NULL DEFAULT '',
+    "severity" VARCHAR NOT NULL DEFAULT 'unknown' CHECK (severity IN ('mild', 'moderate', 'severe', 'unknown')),
+    "onset_date" DATE,
+    "status" VARCHAR NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'inactive', 'resolved')),
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    CHECK (term_code NOT IN ('ALG-OTHER', 'CND-OTHER') OR description <> ''),
+    FOREIGN KEY (term_code, kind) REFERENCES clinical_terms (code, kind)
+);
+
+CREATE UNIQUE INDEX idx_patient_health_entries_term ON patient_health_entries (patient_username, term_code)
+    WHERE term_code NOT IN ('ALG-OTHER', 'CND-OTHER');
+CREATE INDEX idx_patient_health_entries_patient ON patient_health_entries (patient_username, kind);
+
+-- Move the free-text allergies over. Only entries that are exactly a term's
+-- name are coded with it; free text such as "no allergy to penicillin"
+-- can't be coded safely, so everything else is kept word for word as an
+-- "other" allergy or condition for the patient to review. Entries that
+-- mention an allergy, or an allergen such as "dust" for dust mite, are taken
+-- to be allergies.
+INSERT INTO patient_health_entries (patient_username, kind, term_code, description)
+SELECT DISTINCT ON (i.username, lower(i.item))
+    i.username,
+    COALESCE(t.kind, CASE WHEN i.allergy THEN 'allergy' ELSE 'condition' END),
+    COALESCE(t.code, CASE WHEN i.allergy THEN 'ALG-OTHER' ELSE 'CND-OTHER' END),
+    CASE WHEN t.code IS NULL THEN i.item ELSE '' END
+FROM (
+    SELECT p.username, btrim(item) AS item,
+        btrim(item) ILIKE '%allerg%' OR EXISTS (
+            SELECT 1 FROM clinical_terms a
+            WHERE a.kind = 'allergy' AND a.code <> 'ALG-OTHER'
+              AND (lower(btrim(item)) LIKE '%' || lower(a.name) || '%'
+                OR (length(btrim(item)) >= 3 AND lower(a.name) LIKE '%' || lower(btrim(item)) || '%'))
+        ) AS allergy
+    FROM patient_profiles p, regexp_split_to_table(p.disease_allergies, '[,;\n]') AS item
+    WHERE btrim(item) <> ''
+) i
+LEFT JOIN clinical_terms t ON t.code NOT IN ('ALG-OTHER', 'CND-OTHER')
+    AND lower(i.item) = lower(t.name)
+ORDER BY i.username, lower(i.item), t.code
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE patient_profiles DROP COLUMN disease_allergies;
+
+-- A patient has one profile; keep the newest where there are more
+DELETE FROM patient_profiles p
+USING patient_profiles newer
+WHERE newer.username = p.username AND newer.id > p.id;
+
+ALTER TABLE patient_profiles
+    ADD CONSTRAINT patient_profiles_username_key UNIQUE (username),
+    ADD COLUMN "updated_at" TIMESTAMP NOT NULL DEFAULT (now());
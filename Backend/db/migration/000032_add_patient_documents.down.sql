@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS document_shares;
+DROP TABLE IF EXISTS patient_documents;
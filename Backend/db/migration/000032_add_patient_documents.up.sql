@@ -0,0 +1,36 @@
+-- The documents in a patient's medical record vault. The files themselves
+-- are kept by the document storage under storage_key.
+CREATE TABLE patient_documents (
+    "id" SERIAL PRIMARY KEY,
+    "patient_username" VARCHAR NOT NULL REFERENCES patients(username) ON DELETE CASCADE,
+    "document_type" VARCHAR NOT NULL CHECK (document_type IN (
+        'lab_report', 'discharge_summary', 'scan', 'prescription', 'vaccination', 'insurance', 'other'
+    )),
+    "title" VARCHAR NOT NULL,
+    -- When the test was done, the patient discharged and so on
+    "document_date" DATE,
+    "tags" VARCHAR[] NOT NULL DEFAULT '{}',
+    "file_name" VARCHAR NOT NULL,
+    "content_type" VARCHAR NOT NULL,
+    "size_bytes" INTEGER NOT NULL CHECK (size_bytes > 0),
+    "storage_key" VARCHAR NOT NULL UNIQUE,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now()),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_patient_documents_patient ON patient_documents (patient_username, document_date);
+CREATE INDEX idx_patient_documents_tags ON patient_documents USING gin (tags);
+
+-- A document shared with a doctor until expires_at, or until the patient
+-- revokes it
+CREATE TABLE document_shares (
+    "id" SERIAL PRIMARY KEY,
+    "document_id" INTEGER NOT NULL REFERENCES patient_documents(id) ON DELETE CASCADE,
+    "doctor_username" VARCHAR NOT NULL REFERENCES doctors(username) ON DELETE CASCADE,
+    "expires_at" TIMESTAMP NOT NULL,
+    "revoked_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_document_shares_document ON document_shares (document_id, doctor_username);
+CREATE INDEX idx_document_shares_doctor ON document_shares (doctor_username, expires_at);
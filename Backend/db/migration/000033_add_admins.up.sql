@@ -0,0 +1,10 @@
+-- Platform operators. They verify NGO and hospital sellers and run the other
+-- back-office tasks through the admin API; accounts are created with
+-- make createadmin.
+CREATE TABLE admins (
+    "username" VARCHAR PRIMARY KEY,
+    "full_name" VARCHAR NOT NULL,
+    "email" VARCHAR UNIQUE NOT NULL,
+    "password" VARCHAR NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
@@ -0,0 +1,40 @@
+DROP MATERIALIZED VIEW IF EXISTS seller_daily_medicine_sales;
+DROP MATERIALIZED VIEW IF EXISTS seller_daily_sales;
+
+CREATE MATERIALIZED VIEW seller_daily_sales AS
+SELECT so.seller_username,
+    so.created_at::DATE AS day,
+    COUNT(DISTINCT so.id)::INT AS orders,
+    SUM(oi.quantity)::INT AS units,
+    SUM(oi.line_total)::NUMERIC(14, 2) AS revenue
+FROM sub_orders so
+JOIN order_items oi ON oi.sub_order_id = so.id
+WHERE so.status <> 'cancelled'
+GROUP BY so.seller_username, so.created_at::DATE;
+
+CREATE UNIQUE INDEX idx_seller_daily_sales ON seller_daily_sales (seller_username, day);
+
+CREATE MATERIALIZED VIEW seller_daily_medicine_sales AS
+SELECT so.seller_username,
+    so.created_at::DATE AS day,
+    oi.medicine_id,
+    MAX(oi.medicine_name)::VARCHAR AS medicine_name,
+    SUM(oi.quantity)::INT AS units,
+    SUM(oi.line_total)::NUMERIC(14, 2) AS revenue
+FROM sub_orders so
+JOIN order_items oi ON oi.sub_order_id = so.id
+WHERE so.status <> 'cancelled'
+GROUP BY so.seller_username, so.created_at::DATE, oi.medicine_id;
+
+CREATE UNIQUE INDEX idx_seller_daily_medicine_sales
+    ON seller_daily_medicine_sales (seller_username, day, medicine_id);
+
+DROP TABLE IF EXISTS payment_refunds;
+ALTER TABLE orders DROP COLUMN IF EXISTS paid_payment_id;
+
+DROP INDEX IF EXISTS idx_orders_payment_due;
+UPDATE orders SET payment_status = 'pending' WHERE payment_status = 'expired';
+ALTER TABLE orders DROP CONSTRAINT orders_payment_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_payment_status_check
+    CHECK (payment_status IN ('pending', 'paid', 'refunded'));
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_due_by;
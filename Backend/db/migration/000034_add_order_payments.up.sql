@@ -0,0 +1,63 @@
+-- Orders are paid for through the payment provider. An order holds its
+-- stock as pending until payment_due_by; the server then releases the stock
+-- of unpaid orders and marks them expired. Orders placed before this have
+-- no payment_due_by and are left alone. paid_payment_id is the payment the
+-- order was paid with; any other payment that goes through for it is
+-- refunded.
+ALTER TABLE orders ADD COLUMN payment_due_by TIMESTAMP;
+ALTER TABLE orders ADD COLUMN paid_payment_id INTEGER REFERENCES payments(id);
+ALTER TABLE orders DROP CONSTRAINT orders_payment_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_payment_status_check
+    CHECK (payment_status IN ('pending', 'paid', 'refunded', 'expired'));
+
+CREATE INDEX idx_orders_payment_due ON orders (payment_due_by)
+    WHERE payment_status = 'pending';
+
+-- Money paid back through the provider, partly or in full, on a payment.
+-- provider_refund_id is the provider's own ID for the refund.
+CREATE TABLE payment_refunds (
+    "id" SERIAL PRIMARY KEY,
+    "payment_id" INTEGER NOT NULL REFERENCES payments(id),
+    "provider_refund_id" VARCHAR UNIQUE NOT NULL,
+    "amount" NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    "reason" TEXT NOT NULL DEFAULT '',
+    "sub_order_id" INTEGER REFERENCES sub_orders(id) ON DELETE SET NULL,
+    "created_by" VARCHAR NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_payment_refunds_payment ON payment_refunds (payment_id);
+
+-- Only paid orders count as sales
+DROP MATERIALIZED VIEW seller_daily_medicine_sales;
+DROP MATERIALIZED VIEW seller_daily_sales;
+
+CREATE MATERIALIZED VIEW seller_daily_sales AS
+SELECT so.seller_username,
+    so.created_at::DATE AS day,
+    COUNT(DISTINCT so.id)::INT AS orders,
+    SUM(oi.quantity)::INT AS units,
+    SUM(oi.line_total)::NUMERIC(14, 2) AS revenue
+FROM sub_orders so
+JOIN orders o ON o.id = so.order_id
+JOIN order_items oi ON oi.sub_order_id = so.id
+WHERE so.status <> 'cancelled' AND o.payment_status = 'paid'
+GROUP BY so.seller_username, so.created_at::DATE;
+
+CREATE UNIQUE INDEX idx_seller_daily_sales ON seller_daily_sales (seller_username, day);
+
+CREATE MATERIALIZED VIEW seller_daily_medicine_sales AS
+SELECT so.seller_username,
+    so.created_at::DATE AS day,
+    oi.medicine_id,
+    MAX(oi.medicine_name)::VARCHAR AS medicine_name,
+    SUM(oi.quantity)::INT AS units,
+    SUM(oi.line_total)::NUMERIC(14, 2) AS revenue
+FROM sub_orders so
+JOIN orders o ON o.id = so.order_id
+JOIN order_items oi ON oi.sub_order_id = so.id
+WHERE so.status <> 'cancelled' AND o.payment_status = 'paid'
+GROUP BY so.seller_username, so.created_at::DATE, oi.medicine_id;
+
+CREATE UNIQUE INDEX idx_seller_daily_medicine_sales
+    ON seller_daily_medicine_sales (seller_username, day, medicine_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_chargebacks_sub_order;
+
+ALTER TABLE chargebacks DROP COLUMN IF EXISTS "created_by";
+
+ALTER TABLE payment_refunds DROP COLUMN IF EXISTS "ledger_transaction_id";
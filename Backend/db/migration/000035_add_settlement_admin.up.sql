@@ -0,0 +1,11 @@
+-- Refunds of delivered sub-orders are posted to the ledger first and then
+-- paid back through the provider; the link shows which ones still have to be
+-- paid
+ALTER TABLE payment_refunds
+    ADD COLUMN "ledger_transaction_id" BIGINT UNIQUE REFERENCES ledger_transactions(id);
+
+-- Chargebacks are now reported by admins through the API
+ALTER TABLE chargebacks
+    ADD COLUMN "created_by" VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX idx_chargebacks_sub_order ON chargebacks (sub_order_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_appointments_refund_owed;
+
+ALTER TABLE appointments DROP COLUMN IF EXISTS "paid_payment_id";
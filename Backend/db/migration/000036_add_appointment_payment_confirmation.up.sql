@@ -0,0 +1,14 @@
+-- Appointment payments are confirmed with the payment provider. The payment
+-- that confirmed an appointment is kept so that any other payment made for
+-- it can be refunded, and so that cancellations refund the right one.
+ALTER TABLE appointments
+    ADD COLUMN "paid_payment_id" INTEGER REFERENCES payments(id);
+
+UPDATE appointments a SET paid_payment_id = (
+    SELECT MIN(p.id) FROM payments p
+    WHERE p.appointment_id = a.id AND p.status IN ('succeeded', 'refunded')
+)
+WHERE a.payment_status IN ('paid', 'refunded');
+
+CREATE INDEX idx_appointments_refund_owed ON appointments (id)
+    WHERE payment_status = 'refunded';
@@ -0,0 +1,7 @@
+ALTER TABLE medicines DROP COLUMN IF EXISTS withdrawn_at;
+
+-- Withdrawn medicines have no stock left, like disposed ones
+UPDATE medicines SET status = 'disposed' WHERE status = 'withdrawn';
+ALTER TABLE medicines DROP CONSTRAINT medicines_status_check;
+ALTER TABLE medicines ADD CONSTRAINT medicines_status_check
+    CHECK (status IN ('active', 'quarantined', 'disposed'));
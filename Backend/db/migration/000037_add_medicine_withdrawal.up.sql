@@ -0,0 +1,6 @@
+-- Sellers withdraw medicines instead of deleting them, so that carts, sales
+-- and the stock ledger keep pointing at a real medicine.
+ALTER TABLE medicines DROP CONSTRAINT medicines_status_check;
+ALTER TABLE medicines ADD CONSTRAINT medicines_status_check
+    CHECK (status IN ('active', 'quarantined', 'disposed', 'withdrawn'));
+ALTER TABLE medicines ADD COLUMN withdrawn_at TIMESTAMP;
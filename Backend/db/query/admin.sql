@@ -0,0 +1,9 @@
+-- name: CreateAdmin :one
+INSERT INTO admins (
+  username, full_name, email, password
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetAdmin :one
+SELECT * FROM admins WHERE username = $1;
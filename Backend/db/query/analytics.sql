@@ -0,0 +1,67 @@
+-- name: RefreshSellerDailySales :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY seller_daily_sales;
+
+-- name: RefreshSellerDailyMedicineSales :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY seller_daily_medicine_sales;
+
+-- name: ListSellerSalesByPeriod :many
+-- Revenue, units and orders per day, week or month between two dates
+-- (inclusive)
+SELECT date_trunc(sqlc.arg(interval)::VARCHAR, day)::DATE AS period,
+    SUM(orders)::INT AS orders,
+    SUM(units)::INT AS units,
+    SUM(revenue)::NUMERIC(14, 2) AS revenue,
+    (SUM(revenue) / NULLIF(SUM(orders), 0))::NUMERIC(12, 2) AS average_order_value
+FROM seller_daily_sales
+WHERE seller_username = sqlc.arg(seller)
+  AND day BETWEEN sqlc.arg(from_date)::DATE AND sqlc.arg(to_date)::DATE
+GROUP BY 1
+ORDER BY 1 ASC;
+
+-- name: GetSellerSalesSummary :one
+SELECT COALESCE(SUM(orders), 0)::INT AS orders,
+    COALESCE(SUM(units), 0)::INT AS units,
+    COALESCE(SUM(revenue), 0)::NUMERIC(14, 2) AS revenue,
+    COALESCE(SUM(revenue) / NULLIF(SUM(orders), 0), 0)::NUMERIC(12, 2) AS average_order_value
+FROM seller_daily_sales
+WHERE seller_username = sqlc.arg(seller)
+  AND day BETWEEN sqlc.arg(from_date)::DATE AND sqlc.arg(to_date)::DATE;
+
+-- name: ListTopSellerMedicines :many
+SELECT medicine_id,
+    MAX(medicine_name)::VARCHAR AS medicine_name,
+    SUM(units)::INT AS units,
+    SUM(revenue)::NUMERIC(14, 2) AS revenue
+FROM seller_daily_medicine_sales
+WHERE seller_username = sqlc.arg(seller)
+  AND day BETWEEN sqlc.arg(from_date)::DATE AND sqlc.arg(to_date)::DATE
+GROUP BY medicine_id
+ORDER BY revenue DESC, units DESC, medicine_id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: GetSellerInventorySummary :one
+-- Inventory figures between two dates (inclusive) taken from the stock
+-- ledger. Write-offs are valued at the medicine's current price, and a sale
+-- counts as near-expiry when it was made within near_expiry_days of expiry.
+SELECT
+    COALESCE(SUM(sm.quantity_change) FILTER (
+        WHERE sm.created_at < sqlc.arg(from_date)::DATE
+    ), 0)::INT AS opening_stock,
+    COALESCE(SUM(sm.quantity_change), 0)::INT AS closing_stock,
+    COALESCE(-SUM(sm.quantity_change) FILTER (
+        WHERE sm.movement_type = 'sale' AND sm.created_at >= sqlc.arg(from_date)::DATE
+    ), 0)::INT AS units_sold,
+    COALESCE(-SUM(sm.quantity_change) FILTER (
+        WHERE sm.movement_type = 'sale' AND sm.created_at >= sqlc.arg(from_date)::DATE
+          AND m.expiry_date - sm.created_at::DATE <= sqlc.arg(near_expiry_days)::INT
+    ), 0)::INT AS near_expiry_units_sold,
+    COALESCE(-SUM(sm.quantity_change) FILTER (
+        WHERE sm.movement_type = 'expiry_write_off' AND sm.created_at >= sqlc.arg(from_date)::DATE
+    ), 0)::INT AS expiry_write_off_units,
+    COALESCE(-SUM(sm.quantity_change * COALESCE(m.price, 0)) FILTER (
+        WHERE sm.movement_type = 'expiry_write_off' AND sm.created_at >= sqlc.arg(from_date)::DATE
+    ), 0)::NUMERIC(14, 2) AS expiry_write_off_value
+FROM stock_movements sm
+LEFT JOIN medicines m ON m.id = sm.medicine_id
+WHERE sm.seller_username = sqlc.arg(seller)
+  AND sm.created_at < sqlc.arg(to_date)::DATE + 1;
@@ -0,0 +1,184 @@
+-- name: CreateAppointment :one
+INSERT INTO appointments (
+    doctor_username, patient_username, starts_at, ends_at, reason,
+    visit_type, fee, follow_up_of, payment_status, payment_due_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetAppointmentFee :one
+-- What the patient pays for a visit of visit_type with the doctor starting
+-- at starts_at. It is a follow-up, at the doctor's follow-up discount, when
+-- it falls within the doctor's follow-up window of the patient's last
+-- completed appointment with them that wasn't itself a follow-up;
+-- follow_up_of is that appointment, or 0 when it isn't a follow-up.
+SELECT
+    CASE WHEN sqlc.arg(visit_type)::VARCHAR = 'video' THEN d.video_fee ELSE d.in_person_fee END::NUMERIC AS base_fee,
+    COALESCE(f.id, 0)::INTEGER AS follow_up_of,
+    CASE WHEN f.id IS NULL
+        THEN CASE WHEN sqlc.arg(visit_type)::VARCHAR = 'video' THEN d.video_fee ELSE d.in_person_fee END
+        ELSE round(CASE WHEN sqlc.arg(visit_type)::VARCHAR = 'video' THEN d.video_fee ELSE d.in_person_fee END
+            * (100 - d.follow_up_discount) / 100, 2)
+    END::NUMERIC AS fee
+FROM doctors d
+LEFT JOIN LATERAL (
+    SELECT a.id FROM appointments a
+    WHERE a.doctor_username = d.username
+      AND a.patient_username = sqlc.arg(patient_username)
+      AND a.status = 'completed'
+      AND a.follow_up_of IS NULL
+      AND a.starts_at < sqlc.arg(starts_at)::TIMESTAMP
+      AND a.starts_at >= sqlc.arg(starts_at)::TIMESTAMP - make_interval(days => d.follow_up_window_days)
+    ORDER BY a.starts_at DESC
+    LIMIT 1
+) f ON true
+WHERE d.username = sqlc.arg(doctor_username);
+
+-- name: GetAppointment :one
+SELECT * FROM appointments WHERE id = $1;
+
+-- name: RescheduleAppointment :one
+UPDATE appointments SET
+    starts_at = sqlc.arg(starts_at),
+    ends_at = sqlc.arg(ends_at),
+    rescheduled_count = rescheduled_count + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'booked'
+RETURNING *;
+
+-- name: CancelAppointment :one
+-- An appointment cancelled before it was paid for is left unpaid
+UPDATE appointments SET
+    status = 'cancelled',
+    payment_status = CASE WHEN payment_status = 'pending' THEN 'unpaid' ELSE payment_status END,
+    cancelled_by = sqlc.arg(cancelled_by),
+    cancellation_reason = sqlc.arg(cancellation_reason),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'booked'
+RETURNING *;
+
+-- name: ListDoctorBookedAppointments :many
+-- The doctor's booked appointments overlapping from_time to to_time
+SELECT * FROM appointments
+WHERE doctor_username = sqlc.arg(doctor_username)
+  AND status = 'booked'
+  AND starts_at < sqlc.arg(to_time)
+  AND ends_at > sqlc.arg(from_time)
+ORDER BY starts_at;
+
+-- name: ListDoctorDayAppointments :many
+-- Every appointment of the doctor starting from from_time and before
+-- to_time, with the patient's details
+SELECT a.*, p.full_name AS patient_name, p.mobile_number AS patient_mobile_number,
+    p.gender AS patient_gender, p.age AS patient_age
+FROM appointments a
+JOIN patients p ON p.username = a.patient_username
+WHERE a.doctor_username = sqlc.arg(doctor_username)
+  AND a.starts_at >= sqlc.arg(from_time)
+  AND a.starts_at < sqlc.arg(to_time)
+ORDER BY a.starts_at, a.id;
+
+-- name: ListPatientAppointments :many
+-- Upcoming appointments come soonest first, the full history newest first
+SELECT a.*, d.full_name AS doctor_name, d.specialization, d.hospital_name
+FROM appointments a
+JOIN doctors d ON d.username = a.doctor_username
+WHERE a.patient_username = sqlc.arg(patient_username)
+  AND (sqlc.narg(status)::VARCHAR IS NULL OR a.status = sqlc.narg(status))
+  AND (NOT sqlc.arg(upcoming)::BOOLEAN OR a.starts_at >= now())
+ORDER BY CASE WHEN sqlc.arg(upcoming)::BOOLEAN THEN a.starts_at END ASC,
+    a.starts_at DESC, a.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CheckInAppointment :one
+-- Records when the patient or the doctor arrived; checking in twice keeps
+-- the first time
+UPDATE appointments SET
+    patient_checked_in_at = CASE WHEN sqlc.arg(by_doctor)::BOOLEAN
+        THEN patient_checked_in_at ELSE COALESCE(patient_checked_in_at, now()) END,
+    doctor_checked_in_at = CASE WHEN sqlc.arg(by_doctor)::BOOLEAN
+        THEN COALESCE(doctor_checked_in_at, now()) ELSE doctor_checked_in_at END
+WHERE id = sqlc.arg(id) AND status = 'booked'
+RETURNING *;
+
+-- name: CloseFinishedAppointments :many
+-- Closes booked appointments that ended before ended_before: completed when
+-- either side checked in, no_show when neither did
+UPDATE appointments SET
+    status = CASE
+        WHEN patient_checked_in_at IS NULL AND doctor_checked_in_at IS NULL THEN 'no_show'
+        ELSE 'completed'
+    END,
+    updated_at = now()
+WHERE status = 'booked' AND payment_status <> 'pending'
+  AND ends_at < sqlc.arg(ended_before)
+RETURNING *;
+
+-- name: ListDueAppointmentReminders :many
+-- Booked appointments starting within lead_minutes that haven't had this
+-- reminder yet. Appointments booked, paid for or moved inside the lead
+-- time are skipped, the confirmation having just gone out, and so are
+-- those still waiting for payment.
+SELECT a.* FROM appointments a
+WHERE a.status = 'booked' AND a.payment_status <> 'pending'
+  AND a.starts_at > sqlc.arg(now)::TIMESTAMP
+  AND a.starts_at <= sqlc.arg(now)::TIMESTAMP + make_interval(mins => sqlc.arg(lead_minutes)::INT)
+  AND a.updated_at < a.starts_at - make_interval(mins => sqlc.arg(lead_minutes)::INT)
+  AND NOT EXISTS (
+      SELECT 1 FROM appointment_reminders r
+      WHERE r.appointment_id = a.id AND r.kind = sqlc.arg(kind)
+  )
+ORDER BY a.starts_at;
+
+-- name: ClaimAppointmentReminder :execrows
+INSERT INTO appointment_reminders (
+    appointment_id, kind
+) VALUES (
+    $1, $2
+)
+ON CONFLICT DO NOTHING;
+
+-- name: ReleaseAppointmentReminder :exec
+-- Gives back a claimed reminder that couldn't be sent, so it is retried
+DELETE FROM appointment_reminders
+WHERE appointment_id = $1 AND kind = $2;
+
+-- name: DeleteAppointmentReminders :exec
+-- A rescheduled appointment is reminded again for its new time
+DELETE FROM appointment_reminders
+WHERE appointment_id = $1;
+
+-- name: MarkAppointmentPaid :one
+-- Confirms an appointment waiting for payment, as long as its hold on the
+-- slot hasn't run out at now
+UPDATE appointments SET
+    payment_status = 'paid',
+    paid_payment_id = sqlc.arg(payment_id),
+    payment_due_by = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND status = 'booked'
+  AND payment_status = 'pending'
+  AND payment_due_by >= sqlc.arg(now)::TIMESTAMP
+RETURNING *;
+
+-- name: MarkAppointmentRefunded :one
+UPDATE appointments SET
+    payment_status = 'refunded',
+    updated_at = now()
+WHERE id = $1 AND payment_status = 'paid'
+RETURNING *;
+
+-- name: ReleaseUnpaidAppointments :many
+-- Cancels appointments whose payment didn't arrive by payment_due_by,
+-- freeing their slots
+UPDATE appointments SET
+    status = 'cancelled',
+    payment_status = 'unpaid',
+    cancellation_reason = 'payment not received in time',
+    updated_at = now()
+WHERE status = 'booked'
+  AND payment_status = 'pending'
+  AND payment_due_by < sqlc.arg(now)::TIMESTAMP
+RETURNING *;
@@ -0,0 +1,89 @@
+-- name: CreateConsultation :one
+-- Starting a consultation that already exists returns the existing one
+INSERT INTO consultations (
+    appointment_id, doctor_username, patient_username
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (appointment_id) DO UPDATE SET appointment_id = EXCLUDED.appointment_id
+RETURNING *;
+
+-- name: GetConsultation :one
+SELECT * FROM consultations WHERE id = $1;
+
+-- name: GetConsultationByAppointment :one
+SELECT * FROM consultations WHERE appointment_id = $1;
+
+-- name: CreateConsultationMessage :one
+-- Nothing is inserted once the consultation is closed. The row lock makes
+-- a message sent while the consultation is being closed wait for the close
+-- and then be refused, so it can't slip past the archived transcript.
+INSERT INTO consultation_messages (
+    consultation_id, sender_username, body, attachment_id
+)
+SELECT c.id, sqlc.arg(sender_username), sqlc.arg(body), sqlc.narg(attachment_id)
+FROM consultations c
+WHERE c.id = sqlc.arg(consultation_id) AND c.status = 'open'
+FOR SHARE
+RETURNING *;
+
+-- name: ListConsultationMessages :many
+-- Newest first, paging back with before_id
+SELECT m.*, a.file_name AS attachment_file_name, a.content_type AS attachment_content_type,
+    a.size_bytes AS attachment_size_bytes
+FROM consultation_messages m
+LEFT JOIN consultation_attachments a ON a.id = m.attachment_id
+WHERE m.consultation_id = sqlc.arg(consultation_id)
+  AND (sqlc.narg(before_id)::INTEGER IS NULL OR m.id < sqlc.narg(before_id))
+ORDER BY m.id DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: MarkConsultationMessagesRead :execrows
+-- Marks the other side's messages up to and including up_to_id as read
+UPDATE consultation_messages SET read_at = now()
+WHERE consultation_id = sqlc.arg(consultation_id)
+  AND sender_username <> sqlc.arg(reader_username)
+  AND id <= sqlc.arg(up_to_id)
+  AND read_at IS NULL;
+
+-- name: CreateConsultationAttachment :one
+INSERT INTO consultation_attachments (
+    consultation_id, uploaded_by, file_name, content_type, size_bytes, data
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetConsultationAttachment :one
+SELECT * FROM consultation_attachments
+WHERE id = sqlc.arg(id) AND consultation_id = sqlc.arg(consultation_id);
+
+-- name: CloseConsultation :one
+-- Archives the messages into the transcript, oldest first
+UPDATE consultations SET
+    status = 'closed',
+    closed_at = now(),
+    closed_by = sqlc.arg(closed_by),
+    transcript = (
+        SELECT COALESCE(jsonb_agg(jsonb_build_object(
+            'id', m.id,
+            'sender_username', m.sender_username,
+            'body', m.body,
+            'attachment', CASE WHEN a.id IS NULL THEN NULL ELSE jsonb_build_object(
+                'id', a.id,
+                'file_name', a.file_name,
+                'content_type', a.content_type,
+                'size_bytes', a.size_bytes
+            ) END,
+            'created_at', m.created_at,
+            'read_at', m.read_at
+        ) ORDER BY m.id), '[]'::JSONB)
+        FROM consultation_messages m
+        LEFT JOIN consultation_attachments a ON a.id = m.attachment_id
+        WHERE m.consultation_id = consultations.id
+    )
+WHERE consultations.id = sqlc.arg(id) AND consultations.status = 'open'
+RETURNING *;
+
+-- name: DeleteConsultationMessages :exec
+DELETE FROM consultation_messages WHERE consultation_id = $1;
@@ -1,39 +1,85 @@
--- name: CreateDoctor :one
-INSERT INTO doctors (
-  username, full_name, mobile_number, gender, age,
-  specialization, email, password,
-  registration_number, hospital_name, years_experience
-) VALUES (
-  $1, $2, $3, $4, $5,
-  $6, $7, $8,
-  $9, $10, $11
-) RETURNING *;
-
--- name: GetDoctorByName :one
-SELECT * FROM doctors WHERE username = $1;
-
--- name: ListDoctorsBySpecialization :many
-SELECT * FROM doctors
-WHERE specialization = $1
-ORDER BY years_experience DESC
-LIMIT $2 OFFSET $3;
-
--- name: UpdateDoctor :one
-UPDATE doctors SET
-  full_name = COALESCE(sqlc.narg(full_name), full_name),
-  mobile_number = COALESCE(sqlc.narg(mobile_number), mobile_number),
-  gender = COALESCE(sqlc.narg(gender), gender),
-  age = COALESCE(sqlc.narg(age), age),
-  specialization = COALESCE(sqlc.narg(specialization), specialization),
-  email = COALESCE(sqlc.narg(email), email),
-  password = COALESCE(sqlc.narg(password), password),
-  registration_number = COALESCE(sqlc.narg(registration_number), registration_number),
-  hospital_name = COALESCE(sqlc.narg(hospital_name), hospital_name),
-  years_experience = COALESCE(sqlc.narg(years_experience), years_experience),
-  password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at)
-WHERE username = sqlc.arg(username)
-RETURNING *;
-
--- name: DeleteDoctor :one
-DELETE FROM doctors WHERE username = $1
-RETURNING username;
\ No newline at end of file
+-- name: CreateDoctor :one
+INSERT INTO doctors (
+  username, full_name, mobile_number, gender, age,
+  specialization, email, password,
+  registration_number, hospital_name, years_experience, languages
+) VALUES (
+  $1, $2, $3, $4, $5,
+  $6, $7, $8,
+  $9, $10, $11, $12
+) RETURNING *;
+
+-- name: GetDoctorByName :one
+SELECT * FROM doctors WHERE username = $1;
+
+-- name: SearchDoctors :many
+-- The public doctor directory. Every filter is optional. specialization is
+-- a normalized name such as "cardiology" and also matches close spellings
+-- like "Cardiologist"; hospital matches part of the hospital name. max_fee
+-- applies to the fee of visit_type, or to either fee without one. With
+-- available_only, only doctors with weekly hours or extra hours ahead are
+-- returned. Sorted by years of experience, or by rating when sort_by is
+-- 'rating', with doctors without reviews last. total_count is the number
+-- of doctors matching before the limit and offset.
+SELECT *, COUNT(*) OVER () AS total_count FROM doctors
+WHERE (sqlc.narg(specialization)::VARCHAR IS NULL
+       OR lower(specialization) LIKE '%' || sqlc.narg(specialization) || '%'
+       OR similarity(lower(specialization), sqlc.narg(specialization)) >= 0.5)
+  AND (sqlc.narg(hospital)::VARCHAR IS NULL OR hospital_name ILIKE '%' || sqlc.narg(hospital) || '%')
+  AND (sqlc.narg(min_experience)::INTEGER IS NULL OR years_experience >= sqlc.narg(min_experience))
+  AND (sqlc.narg(max_experience)::INTEGER IS NULL OR years_experience <= sqlc.narg(max_experience))
+  AND (sqlc.narg(gender)::VARCHAR IS NULL OR gender = sqlc.narg(gender))
+  AND (sqlc.narg(language)::VARCHAR IS NULL OR sqlc.narg(language) = ANY(languages))
+  AND (sqlc.narg(min_rating)::NUMERIC IS NULL OR rating_average >= sqlc.narg(min_rating))
+  AND (sqlc.narg(max_fee)::NUMERIC IS NULL
+       OR (sqlc.narg(visit_type)::VARCHAR IS DISTINCT FROM 'video' AND in_person_fee <= sqlc.narg(max_fee))
+       OR (sqlc.narg(visit_type)::VARCHAR IS DISTINCT FROM 'in_person' AND video_fee <= sqlc.narg(max_fee)))
+  AND (NOT sqlc.arg(available_only)::BOOLEAN
+       OR EXISTS (SELECT 1 FROM doctor_availability a WHERE a.doctor_username = doctors.username)
+       OR EXISTS (
+           SELECT 1 FROM doctor_availability_exceptions e
+           WHERE e.doctor_username = doctors.username AND e.available AND e.date >= CURRENT_DATE
+       ))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::VARCHAR = 'rating' THEN rating_average END DESC NULLS LAST,
+  CASE WHEN sqlc.arg(sort_by)::VARCHAR = 'rating' THEN review_count END DESC,
+  years_experience DESC,
+  username
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: UpdateDoctor :one
+UPDATE doctors SET
+  full_name = COALESCE(sqlc.narg(full_name), full_name),
+  mobile_number = COALESCE(sqlc.narg(mobile_number), mobile_number),
+  gender = COALESCE(sqlc.narg(gender), gender),
+  age = COALESCE(sqlc.narg(age), age),
+  specialization = COALESCE(sqlc.narg(specialization), specialization),
+  email = COALESCE(sqlc.narg(email), email),
+  password = COALESCE(sqlc.narg(password), password),
+  registration_number = COALESCE(sqlc.narg(registration_number), registration_number),
+  hospital_name = COALESCE(sqlc.narg(hospital_name), hospital_name),
+  years_experience = COALESCE(sqlc.narg(years_experience), years_experience),
+  languages = COALESCE(sqlc.narg(languages), languages),
+  password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: DeleteDoctor :one
+DELETE FROM doctors WHERE username = $1
+RETURNING username;
+
+-- name: SetDoctorCancellationWindow :one
+UPDATE doctors SET
+  cancellation_window_hours = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: SetDoctorFees :one
+UPDATE doctors SET
+  in_person_fee = sqlc.arg(in_person_fee),
+  video_fee = sqlc.arg(video_fee),
+  follow_up_window_days = sqlc.arg(follow_up_window_days),
+  follow_up_discount = sqlc.arg(follow_up_discount),
+  refund_window_hours = sqlc.arg(refund_window_hours)
+WHERE username = sqlc.arg(username)
+RETURNING *;
@@ -0,0 +1,59 @@
+-- name: CreateDoctorAvailability :one
+INSERT INTO doctor_availability (
+    doctor_username, day_of_week, starts_at, ends_at, slot_minutes
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListDoctorAvailability :many
+SELECT * FROM doctor_availability
+WHERE doctor_username = $1
+ORDER BY day_of_week, starts_at;
+
+-- name: DeleteDoctorAvailability :exec
+DELETE FROM doctor_availability
+WHERE doctor_username = $1;
+
+-- name: CreateDoctorAvailabilityException :one
+INSERT INTO doctor_availability_exceptions (
+    doctor_username, date, starts_at, ends_at, available, slot_minutes, reason
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetDoctorAvailabilityException :one
+SELECT * FROM doctor_availability_exceptions WHERE id = $1;
+
+-- name: ListDoctorAvailabilityExceptions :many
+-- The doctor's exceptions on dates from from_date to to_date, both inclusive
+SELECT * FROM doctor_availability_exceptions
+WHERE doctor_username = sqlc.arg(doctor_username)
+  AND date BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+ORDER BY date, starts_at;
+
+-- name: DeleteDoctorAvailabilityException :exec
+DELETE FROM doctor_availability_exceptions WHERE id = $1;
+
+-- name: CreateDoctorLeave :one
+INSERT INTO doctor_leaves (
+    doctor_username, starts_on, ends_on, reason
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetDoctorLeave :one
+SELECT * FROM doctor_leaves WHERE id = $1;
+
+-- name: ListDoctorLeaves :many
+-- The doctor's leave overlapping from_date to to_date, both inclusive
+SELECT * FROM doctor_leaves
+WHERE doctor_username = sqlc.arg(doctor_username)
+  AND starts_on <= sqlc.arg(to_date)
+  AND ends_on >= sqlc.arg(from_date)
+ORDER BY starts_on;
+
+-- name: DeleteDoctorLeave :exec
+DELETE FROM doctor_leaves WHERE id = $1;
@@ -0,0 +1,56 @@
+-- name: CreateDoctorReview :one
+INSERT INTO doctor_reviews (
+    appointment_id, doctor_username, patient_username, rating, comment,
+    status, moderation_note
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetDoctorReview :one
+SELECT * FROM doctor_reviews WHERE id = $1;
+
+-- name: GetDoctorReviewByAppointment :one
+SELECT * FROM doctor_reviews WHERE appointment_id = $1;
+
+-- name: ListDoctorReviews :many
+-- A doctor's published reviews, newest first
+SELECT r.id, r.appointment_id, r.rating, r.comment, r.created_at, p.full_name AS patient_name
+FROM doctor_reviews r
+JOIN patients p ON p.username = r.patient_username
+WHERE r.doctor_username = sqlc.arg(doctor_username)
+  AND r.status = 'published'
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: GetDoctorRatingDistribution :many
+-- How many published reviews gave each star rating
+SELECT rating, COUNT(*) AS reviews
+FROM doctor_reviews
+WHERE doctor_username = $1 AND status = 'published'
+GROUP BY rating
+ORDER BY rating DESC;
+
+-- name: ReportDoctorReview :one
+UPDATE doctor_reviews SET
+    reported_at = now(),
+    report_reason = sqlc.arg(report_reason)
+WHERE id = sqlc.arg(id) AND status = 'published'
+RETURNING *;
+
+-- name: ListDoctorReviewModerationQueue :many
+-- Held reviews and published ones reported by their doctor, oldest first
+SELECT * FROM doctor_reviews
+WHERE status = 'pending' OR (status = 'published' AND reported_at IS NOT NULL)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: ModerateDoctorReview :one
+-- Publishes or rejects a review, which also settles any report on it
+UPDATE doctor_reviews SET
+    status = sqlc.arg(status),
+    moderation_note = sqlc.arg(moderation_note),
+    reported_at = NULL,
+    moderated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,82 @@
+-- name: CreateDonationOffer :one
+INSERT INTO donation_offers (
+    medicine_id, seller_username, offer_type, unit_price,
+    quantity_offered, quantity_remaining, notes
+) VALUES (
+    $1, $2, $3, $4, $5, $5, $6
+)
+RETURNING *;
+
+-- name: GetDonationOffer :one
+SELECT * FROM donation_offers WHERE id = $1;
+
+-- name: GetDonationOfferForUpdate :one
+SELECT * FROM donation_offers WHERE id = $1
+FOR UPDATE;
+
+-- name: ListOpenDonationOffers :many
+-- Offers NGO and hospital sellers can still claim, soonest expiry first
+SELECT o.*, m.name AS medicine_name, m.batch_number, m.expiry_date,
+    m.active_ingredients, m.strength, m.dosage_form,
+    s.store_name, s.store_address, s.pincode
+FROM donation_offers o
+JOIN medicines m ON m.id = o.medicine_id
+JOIN sellers s ON s.username = o.seller_username
+WHERE o.status = 'open'
+  AND m.status = 'active'
+  AND m.expiry_date > CURRENT_DATE
+  AND (sqlc.arg(medicine)::VARCHAR = '' OR m.name ILIKE '%' || sqlc.arg(medicine) || '%')
+  AND (sqlc.narg(offer_type)::VARCHAR IS NULL OR o.offer_type = sqlc.narg(offer_type))
+ORDER BY m.expiry_date ASC, o.id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListSellerDonationOffers :many
+SELECT * FROM donation_offers
+WHERE seller_username = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListOfferedMedicineIDs :many
+-- Medicines of a seller that are currently on offer
+SELECT medicine_id FROM donation_offers
+WHERE seller_username = $1 AND status = 'open';
+
+-- name: UpdateDonationOfferRemaining :one
+UPDATE donation_offers SET
+    quantity_remaining = sqlc.arg(quantity_remaining),
+    status = CASE WHEN sqlc.arg(quantity_remaining)::INT = 0 THEN 'closed' ELSE status END,
+    closed_at = CASE WHEN sqlc.arg(quantity_remaining)::INT = 0 THEN now() ELSE closed_at END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: WithdrawDonationOffer :one
+UPDATE donation_offers SET
+    status = 'withdrawn',
+    closed_at = now()
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: CreateDonationClaim :one
+INSERT INTO donation_claims (
+    offer_id, donor_username, claimant_username, source_medicine_id,
+    received_medicine_id, quantity, unit_price
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListDonationClaimsByClaimant :many
+SELECT c.*, m.name AS medicine_name, m.batch_number, m.expiry_date
+FROM donation_claims c
+LEFT JOIN medicines m ON m.id = c.received_medicine_id
+WHERE c.claimant_username = $1
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListDonationClaimsByDonor :many
+SELECT c.*, m.name AS medicine_name, m.batch_number, m.expiry_date
+FROM donation_claims c
+LEFT JOIN medicines m ON m.id = c.source_medicine_id
+WHERE c.donor_username = $1
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $2 OFFSET $3;
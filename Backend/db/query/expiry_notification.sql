@@ -0,0 +1,23 @@
+-- name: CreateExpiryNotification :exec
+INSERT INTO expiry_notifications (
+    medicine_id, seller_username, threshold_days
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (medicine_id, threshold_days) DO NOTHING;
+
+-- name: ListSentExpiryNotifications :many
+SELECT medicine_id, threshold_days FROM expiry_notifications
+WHERE seller_username = $1;
+
+-- name: ListUnnotifiedExpiredMedicines :many
+-- Medicines quarantined for expiring whose seller hasn't been told yet,
+-- including those from runs where the digest couldn't be sent
+SELECT m.* FROM medicines m
+WHERE m.status = 'quarantined'
+  AND m.expiry_date <= m.quarantined_at::date
+  AND NOT EXISTS (
+    SELECT 1 FROM expiry_notifications n
+    WHERE n.medicine_id = m.id AND n.threshold_days = 0
+  )
+ORDER BY m.id ASC;
@@ -0,0 +1,152 @@
+-- name: CreateLedgerTransaction :one
+INSERT INTO ledger_transactions (
+    kind, seller_username, sub_order_id, payout_id, commission_rate, description, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: CreateLedgerEntry :one
+INSERT INTO ledger_entries (
+    transaction_id, account, amount
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetSubOrderEarning :one
+-- What a sub-order earns its seller once delivered, after the commission
+-- for the seller's type
+SELECT so.id, so.seller_username, so.subtotal,
+    COALESCE(cr.rate_percent, 0)::NUMERIC(5, 2) AS commission_rate,
+    ROUND(so.subtotal * COALESCE(cr.rate_percent, 0) / 100, 2)::NUMERIC(14, 2) AS commission,
+    (so.subtotal - ROUND(so.subtotal * COALESCE(cr.rate_percent, 0) / 100, 2))::NUMERIC(14, 2) AS seller_amount
+FROM sub_orders so
+JOIN sellers s ON s.username = so.seller_username
+LEFT JOIN commission_rates cr ON cr.seller_type = s.seller_type
+WHERE so.id = $1;
+
+-- name: GetSubOrderRefundSplit :one
+-- Splits money paid back on a delivered sub-order between the seller and
+-- the platform at the commission rate of the original earning. A null
+-- amount pays back everything not yet refunded or charged back.
+WITH earning AS (
+    SELECT t.seller_username, t.commission_rate,
+        so.subtotal - COALESCE((
+            SELECT -SUM(e.amount)
+            FROM ledger_entries e
+            JOIN ledger_transactions r ON r.id = e.transaction_id
+            WHERE r.sub_order_id = t.sub_order_id
+              AND r.kind IN ('refund', 'chargeback')
+              AND e.account = 'customer_payments'
+        ), 0) AS refundable
+    FROM ledger_transactions t
+    JOIN sub_orders so ON so.id = t.sub_order_id
+    WHERE t.sub_order_id = sqlc.arg(sub_order_id) AND t.kind = 'earning'
+), refund AS (
+    SELECT earning.*, COALESCE(sqlc.narg(amount)::NUMERIC, earning.refundable) AS amount
+    FROM earning
+)
+SELECT seller_username,
+    commission_rate::NUMERIC(5, 2) AS commission_rate,
+    refundable::NUMERIC(14, 2) AS refundable,
+    amount::NUMERIC(14, 2) AS amount,
+    ROUND(amount * commission_rate / 100, 2)::NUMERIC(14, 2) AS commission,
+    (amount - ROUND(amount * commission_rate / 100, 2))::NUMERIC(14, 2) AS seller_amount,
+    (amount > 0 AND amount <= refundable) AS allowed
+FROM refund;
+
+-- name: GetSellerLedgerBalance :one
+-- What the platform owes the seller as of a point in time. It is negative
+-- when refunds and chargebacks exceeded earnings since the last payout.
+-- Payouts are made after their cutoff, so they count from their period_end.
+SELECT COALESCE(-SUM(e.amount), 0)::NUMERIC(14, 2) AS balance
+FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+LEFT JOIN payouts p ON p.id = t.payout_id
+WHERE t.seller_username = sqlc.arg(seller)
+  AND e.account = 'seller_payable'
+  AND COALESCE(p.period_end, t.created_at) <= sqlc.arg(as_of)::TIMESTAMP;
+
+-- name: ListSellersWithPayableBalance :many
+SELECT t.seller_username, (-SUM(e.amount))::NUMERIC(14, 2) AS balance
+FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+LEFT JOIN payouts p ON p.id = t.payout_id
+WHERE e.account = 'seller_payable'
+  AND COALESCE(p.period_end, t.created_at) <= sqlc.arg(as_of)::TIMESTAMP
+GROUP BY t.seller_username
+HAVING -SUM(e.amount) > 0
+ORDER BY t.seller_username ASC;
+
+-- name: GetSellerSettlement :one
+-- The seller's ledger activity after period_start (from the beginning when
+-- null) up to and including period_end
+SELECT
+    COALESCE(SUM(e.amount) FILTER (
+        WHERE t.kind = 'earning' AND e.account = 'customer_payments'
+    ), 0)::NUMERIC(14, 2) AS gross_sales,
+    COALESCE(-SUM(e.amount) FILTER (
+        WHERE e.account = 'platform_commission'
+    ), 0)::NUMERIC(14, 2) AS commission,
+    COALESCE(-SUM(e.amount) FILTER (
+        WHERE t.kind = 'refund' AND e.account = 'customer_payments'
+    ), 0)::NUMERIC(14, 2) AS refunds,
+    COALESCE(-SUM(e.amount) FILTER (
+        WHERE t.kind = 'chargeback' AND e.account = 'customer_payments'
+    ), 0)::NUMERIC(14, 2) AS chargebacks
+FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE t.seller_username = sqlc.arg(seller)
+  AND t.kind <> 'payout'
+  AND (sqlc.narg(period_start)::TIMESTAMP IS NULL OR t.created_at > sqlc.narg(period_start))
+  AND t.created_at <= sqlc.arg(period_end)::TIMESTAMP;
+
+-- name: ListSellerLedgerTransactions :many
+-- The seller's side of each ledger transaction after from (when set) up to
+-- and including to (when set), newest first
+SELECT t.id, t.kind, t.sub_order_id, t.payout_id, t.commission_rate, t.description, t.created_at,
+    COALESCE(-SUM(e.amount) FILTER (WHERE e.account = 'seller_payable'), 0)::NUMERIC(14, 2) AS seller_amount,
+    COALESCE(-SUM(e.amount) FILTER (WHERE e.account = 'platform_commission'), 0)::NUMERIC(14, 2) AS commission
+FROM ledger_transactions t
+JOIN ledger_entries e ON e.transaction_id = t.id
+WHERE t.seller_username = sqlc.arg(seller)
+  AND (sqlc.narg(from_time)::TIMESTAMP IS NULL OR t.created_at > sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::TIMESTAMP IS NULL OR t.created_at <= sqlc.narg(to_time))
+GROUP BY t.id
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListPendingChargebacks :many
+SELECT * FROM chargebacks
+WHERE posted_at IS NULL
+ORDER BY created_at ASC, id ASC;
+
+-- name: MarkChargebackPosted :exec
+UPDATE chargebacks SET posted_at = now()
+WHERE id = $1;
+
+-- name: ListCommissionRates :many
+SELECT * FROM commission_rates
+ORDER BY seller_type ASC;
+
+-- name: UpdateCommissionRate :one
+UPDATE commission_rates SET
+    rate_percent = sqlc.arg(rate_percent),
+    updated_at = now()
+WHERE seller_type = sqlc.arg(seller_type)
+RETURNING *;
+
+-- name: CreateChargeback :one
+INSERT INTO chargebacks (
+    sub_order_id, amount, reason, created_by
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetPendingChargebackTotal :one
+-- Chargebacks reported on a sub-order that the payout job hasn't posted yet
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(14, 2) AS total
+FROM chargebacks
+WHERE sub_order_id = $1 AND posted_at IS NULL;
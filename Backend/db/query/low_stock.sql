@@ -0,0 +1,42 @@
+-- name: SetMedicineReorderLevel :one
+UPDATE medicines SET reorder_level = sqlc.narg(reorder_level)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListLowStockMedicines :many
+-- Medicines on sale at or below their reorder level, with the units sold
+-- since sales_since. A null seller lists every seller's medicines.
+SELECT m.id, m.name, m.batch_number, m.seller_username, m.quantity,
+    m.reorder_level::INT AS reorder_level, m.expiry_date,
+    COALESCE((
+        SELECT -SUM(sm.quantity_change) FROM stock_movements sm
+        WHERE sm.medicine_id = m.id
+          AND sm.movement_type = 'sale'
+          AND sm.created_at >= sqlc.arg(sales_since)
+    ), 0)::INT AS units_sold,
+    EXISTS (
+        SELECT 1 FROM low_stock_alerts a WHERE a.medicine_id = m.id
+    ) AS alerted
+FROM medicines m
+WHERE m.status = 'active'
+  AND m.reorder_level IS NOT NULL
+  AND m.quantity <= m.reorder_level
+  AND m.expiry_date > CURRENT_DATE
+  AND (sqlc.narg(seller)::VARCHAR IS NULL OR m.seller_username = sqlc.narg(seller))
+ORDER BY m.seller_username ASC, m.quantity ASC, m.name ASC;
+
+-- name: CreateLowStockAlert :exec
+INSERT INTO low_stock_alerts (
+    medicine_id, seller_username, quantity
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (medicine_id) DO NOTHING;
+
+-- name: DeleteResolvedLowStockAlerts :execrows
+-- Clears alerts of medicines that were restocked, taken off sale or no
+-- longer have a reorder level, so they are alerted on again next time
+DELETE FROM low_stock_alerts a
+USING medicines m
+WHERE m.id = a.medicine_id
+  AND (m.status <> 'active' OR m.reorder_level IS NULL OR m.quantity > m.reorder_level);
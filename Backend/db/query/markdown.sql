@@ -0,0 +1,79 @@
+-- name: CreateMarkdownRule :one
+INSERT INTO markdown_rules (
+    seller_username, max_days_to_expiry, discount
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetMarkdownRule :one
+SELECT * FROM markdown_rules WHERE id = $1;
+
+-- name: ListMarkdownRules :many
+SELECT * FROM markdown_rules
+WHERE seller_username = $1
+ORDER BY max_days_to_expiry ASC;
+
+-- name: UpdateMarkdownRule :one
+UPDATE markdown_rules SET
+    max_days_to_expiry = COALESCE(sqlc.narg(max_days_to_expiry), max_days_to_expiry),
+    discount = COALESCE(sqlc.narg(discount), discount),
+    active = COALESCE(sqlc.narg(active), active)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteMarkdownRule :exec
+DELETE FROM markdown_rules WHERE id = $1;
+
+-- name: ListMarkdownCandidates :many
+-- Medicines on sale that either fall under one of their seller's active
+-- rules (the rule with the fewest days wins) or carry a markdown that may
+-- need restoring. rule_id is 0 when no rule applies.
+SELECT m.id, m.name, m.batch_number, m.seller_username, m.price, m.discount,
+    m.original_discount, m.markdown_rule_id, m.expiry_date,
+    COALESCE(r.id, 0)::INT AS rule_id,
+    COALESCE(r.discount, 0)::INT AS rule_discount
+FROM medicines m
+LEFT JOIN LATERAL (
+    SELECT mr.id, mr.discount
+    FROM markdown_rules mr
+    WHERE mr.seller_username = m.seller_username
+      AND mr.active
+      AND m.expiry_date - CURRENT_DATE <= mr.max_days_to_expiry
+    ORDER BY mr.max_days_to_expiry ASC
+    LIMIT 1
+) r ON true
+WHERE m.status = 'active'
+  AND m.expiry_date > CURRENT_DATE
+  AND (r.id IS NOT NULL OR m.original_discount IS NOT NULL)
+ORDER BY m.seller_username, m.expiry_date;
+
+-- name: ApplyMarkdown :one
+UPDATE medicines SET
+    original_discount = COALESCE(original_discount, discount),
+    discount = sqlc.arg(discount),
+    markdown_rule_id = sqlc.arg(rule_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: RestoreMarkdown :one
+UPDATE medicines SET
+    discount = original_discount,
+    original_discount = NULL,
+    markdown_rule_id = NULL
+WHERE id = $1 AND original_discount IS NOT NULL
+RETURNING *;
+
+-- name: CreateMarkdownEvent :one
+INSERT INTO markdown_events (
+    medicine_id, seller_username, rule_id, action, old_discount, new_discount
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListMarkdownEvents :many
+SELECT * FROM markdown_events
+WHERE seller_username = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
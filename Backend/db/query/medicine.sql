@@ -1,44 +1,197 @@
--- name: CreateMedicine :one
-INSERT INTO medicines (
-    name, description, expiry_date, quantity, price, discount, seller_username
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-)
-RETURNING *;
-
--- name: ListSellerMedicinesByExpiry :many
-SELECT * FROM medicines
-WHERE seller_username = $1
-ORDER BY expiry_date ASC
-LIMIT $2 OFFSET $3;
-
--- name: SearchMedicinesByNameSortedByPrice :many
-SELECT * FROM medicines
-WHERE name ILIKE '%' || sqlc.arg('name') || '%'
-ORDER BY price ASC
-LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
-
--- name: UpdateMedicine :one
-UPDATE medicines SET
-    name = COALESCE(sqlc.narg(name), name),
-    description = COALESCE(sqlc.narg(description), description),
-    expiry_date = COALESCE(sqlc.narg(expiry_date), expiry_date),
-    quantity = COALESCE(sqlc.narg(quantity), quantity),
-    price = COALESCE(sqlc.narg(price), price),
-    discount = COALESCE(sqlc.narg(discount), discount)
-WHERE id = sqlc.arg(id)
-RETURNING *;
-
--- name: DeleteMedicine :one
-DELETE FROM medicines WHERE id = $1
-RETURNING id;
-
--- name: GetMedicineByName :one
-SELECT * FROM medicines WHERE name = $1;
-
--- name: GetMedicine :one
-SELECT * FROM medicines WHERE id = $1;
-
--- name: ListAllMedicines :many
-SELECT * FROM medicines
-ORDER BY id ASC;
+-- name: CreateMedicine :one
+INSERT INTO medicines (
+    name, description, expiry_date, quantity, price, discount, seller_username,
+    active_ingredients, strength, dosage_form, prescription_required, batch_number
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7,
+    $8, $9, $10, $11, $12
+)
+RETURNING *;
+
+-- name: ListSellerMedicinesByExpiry :many
+SELECT * FROM medicines
+WHERE seller_username = $1 AND status = 'active'
+ORDER BY expiry_date ASC
+LIMIT $2 OFFSET $3;
+
+-- name: SearchMedicines :many
+SELECT * FROM (
+    SELECT m.*, s.seller_type, s.store_name,
+        s.rating_average AS seller_rating_average, s.review_count AS seller_review_count,
+        (m.price * (100 - m.discount) / 100)::NUMERIC(10, 2) AS effective_price,
+        (CASE WHEN sqlc.arg(query)::VARCHAR = '' THEN 0
+              ELSE ts_rank(medicine_search_document(m.name, m.description, m.active_ingredients),
+                           websearch_to_tsquery('english', sqlc.arg(query)))
+                   + similarity(m.name, sqlc.arg(query))
+         END)::FLOAT8 AS relevance
+    FROM medicines m
+    JOIN sellers s ON s.username = m.seller_username
+    WHERE (sqlc.arg(query) = ''
+           OR medicine_search_document(m.name, m.description, m.active_ingredients)
+              @@ websearch_to_tsquery('english', sqlc.arg(query))
+           OR m.name % sqlc.arg(query)
+           OR m.active_ingredients % sqlc.arg(query)
+           OR m.name ILIKE '%' || sqlc.arg(query) || '%')
+      AND m.expiry_date > CURRENT_DATE
+      AND m.status = 'active'
+      AND (sqlc.narg(min_price)::NUMERIC IS NULL OR m.price * (100 - m.discount) / 100 >= sqlc.narg(min_price))
+      AND (sqlc.narg(max_price)::NUMERIC IS NULL OR m.price * (100 - m.discount) / 100 <= sqlc.narg(max_price))
+      AND (sqlc.narg(seller_type)::VARCHAR IS NULL OR s.seller_type = sqlc.narg(seller_type))
+      AND (NOT sqlc.arg(in_stock_only)::BOOLEAN OR m.quantity > 0)
+      AND (sqlc.narg(min_days_to_expiry)::INT IS NULL OR m.expiry_date >= CURRENT_DATE + sqlc.narg(min_days_to_expiry)::INT)
+      AND (sqlc.narg(prescription_required)::BOOLEAN IS NULL OR m.prescription_required = sqlc.narg(prescription_required))
+) r
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::VARCHAR = 'relevance' THEN r.relevance END DESC,
+    CASE WHEN sqlc.arg(sort_by) = 'price_desc' THEN r.effective_price END DESC,
+    CASE WHEN sqlc.arg(sort_by) = 'expiry' THEN r.expiry_date END DESC,
+    CASE WHEN sqlc.arg(sort_by) = 'newest' THEN r.created_at END DESC,
+    r.effective_price ASC,
+    r.id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: SearchMedicineFacets :many
+WITH matches AS (
+    SELECT m.quantity, m.expiry_date, m.prescription_required, s.seller_type,
+        (m.price * (100 - m.discount) / 100)::NUMERIC(10, 2) AS effective_price
+    FROM medicines m
+    JOIN sellers s ON s.username = m.seller_username
+    WHERE (sqlc.arg(query)::VARCHAR = ''
+           OR medicine_search_document(m.name, m.description, m.active_ingredients)
+              @@ websearch_to_tsquery('english', sqlc.arg(query))
+           OR m.name % sqlc.arg(query)
+           OR m.active_ingredients % sqlc.arg(query)
+           OR m.name ILIKE '%' || sqlc.arg(query) || '%')
+      AND m.expiry_date > CURRENT_DATE
+      AND m.status = 'active'
+      AND (sqlc.narg(min_price)::NUMERIC IS NULL OR m.price * (100 - m.discount) / 100 >= sqlc.narg(min_price))
+      AND (sqlc.narg(max_price)::NUMERIC IS NULL OR m.price * (100 - m.discount) / 100 <= sqlc.narg(max_price))
+      AND (sqlc.narg(seller_type)::VARCHAR IS NULL OR s.seller_type = sqlc.narg(seller_type))
+      AND (NOT sqlc.arg(in_stock_only)::BOOLEAN OR m.quantity > 0)
+      AND (sqlc.narg(min_days_to_expiry)::INT IS NULL OR m.expiry_date >= CURRENT_DATE + sqlc.narg(min_days_to_expiry)::INT)
+      AND (sqlc.narg(prescription_required)::BOOLEAN IS NULL OR m.prescription_required = sqlc.narg(prescription_required))
+)
+SELECT 'seller_type'::VARCHAR AS facet, seller_type::VARCHAR AS value, COUNT(*) AS count
+FROM matches GROUP BY seller_type
+UNION ALL
+SELECT 'prescription_required', prescription_required::VARCHAR, COUNT(*)
+FROM matches GROUP BY prescription_required
+UNION ALL
+SELECT 'availability', CASE WHEN quantity > 0 THEN 'in_stock' ELSE 'out_of_stock' END, COUNT(*)
+FROM matches GROUP BY 2
+UNION ALL
+SELECT 'price_range',
+    CASE WHEN effective_price < 100 THEN '0-100'
+         WHEN effective_price < 500 THEN '100-500'
+         WHEN effective_price < 1000 THEN '500-1000'
+         ELSE '1000+' END,
+    COUNT(*)
+FROM matches GROUP BY 2
+UNION ALL
+SELECT 'days_to_expiry',
+    CASE WHEN expiry_date < CURRENT_DATE + 30 THEN 'under_30'
+         WHEN expiry_date < CURRENT_DATE + 90 THEN '30-90'
+         WHEN expiry_date < CURRENT_DATE + 180 THEN '90-180'
+         ELSE '180+' END,
+    COUNT(*)
+FROM matches GROUP BY 2;
+
+-- name: UpdateMedicine :one
+UPDATE medicines SET
+    name = COALESCE(sqlc.narg(name), name),
+    description = COALESCE(sqlc.narg(description), description),
+    expiry_date = COALESCE(sqlc.narg(expiry_date), expiry_date),
+    price = COALESCE(sqlc.narg(price), price),
+    discount = COALESCE(sqlc.narg(discount), discount),
+    -- a new discount set by the seller replaces any markdown; resending the
+    -- current one leaves the markdown in place
+    original_discount = CASE WHEN sqlc.narg(discount)::INT IS NULL OR sqlc.narg(discount)::INT = discount
+                             THEN original_discount END,
+    markdown_rule_id = CASE WHEN sqlc.narg(discount)::INT IS NULL OR sqlc.narg(discount)::INT = discount
+                            THEN markdown_rule_id END,
+    active_ingredients = COALESCE(sqlc.narg(active_ingredients), active_ingredients),
+    strength = COALESCE(sqlc.narg(strength), strength),
+    dosage_form = COALESCE(sqlc.narg(dosage_form), dosage_form),
+    prescription_required = COALESCE(sqlc.narg(prescription_required), prescription_required),
+    batch_number = COALESCE(sqlc.narg(batch_number), batch_number)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: WithdrawMedicine :one
+UPDATE medicines SET
+    status = 'withdrawn',
+    withdrawn_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: GetMedicineByName :one
+SELECT * FROM medicines WHERE name = $1;
+
+-- name: GetSellerMedicineByNameAndBatch :one
+SELECT * FROM medicines
+WHERE seller_username = $1 AND name = $2 AND batch_number = $3
+  AND status = 'active'
+ORDER BY id ASC
+LIMIT 1;
+
+-- name: ListAllSellerMedicines :many
+SELECT * FROM medicines
+WHERE seller_username = $1 AND status = 'active'
+ORDER BY name ASC, expiry_date ASC, id ASC;
+
+-- name: GetMedicineForUpdate :one
+SELECT * FROM medicines WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateMedicineQuantity :one
+UPDATE medicines SET quantity = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetMedicine :one
+SELECT * FROM medicines WHERE id = $1;
+
+-- name: ListAllMedicines :many
+SELECT * FROM medicines
+ORDER BY id ASC;
+
+-- name: ListActiveMedicines :many
+SELECT * FROM medicines
+WHERE status = 'active'
+ORDER BY id ASC;
+
+-- name: QuarantineMedicine :one
+UPDATE medicines SET
+    status = 'quarantined',
+    quarantined_quantity = $2,
+    quarantined_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: MarkMedicineDisposed :one
+UPDATE medicines SET
+    status = 'disposed',
+    quarantined_quantity = 0
+WHERE id = $1 AND status = 'quarantined'
+RETURNING *;
+
+-- name: ListQuarantinedMedicines :many
+SELECT * FROM medicines
+WHERE seller_username = $1 AND status = 'quarantined'
+ORDER BY quarantined_at ASC
+LIMIT $2 OFFSET $3;
+
+-- name: ListMedicineSubstitutes :many
+SELECT m.*, (m.price * (100 - m.discount) / 100)::NUMERIC(10, 2) AS effective_price
+FROM medicines m
+JOIN medicines src ON src.id = sqlc.arg(id)
+WHERE m.id <> src.id
+  AND src.active_ingredients <> ''
+  AND m.active_ingredients = src.active_ingredients
+  AND m.strength = src.strength
+  AND m.dosage_form = src.dosage_form
+  AND m.quantity > 0
+  AND m.expiry_date > CURRENT_DATE
+  AND m.status = 'active'
+ORDER BY effective_price ASC, m.expiry_date DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -0,0 +1,17 @@
+-- name: CreateMedicineDisposal :one
+INSERT INTO medicine_disposals (
+    medicine_id, seller_username, quantity, method, disposed_at,
+    disposed_by, certificate_number, notes, recorded_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetMedicineDisposal :one
+SELECT * FROM medicine_disposals WHERE medicine_id = $1;
+
+-- name: ListMedicineDisposals :many
+SELECT * FROM medicine_disposals
+WHERE seller_username = $1
+ORDER BY disposed_at DESC
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,32 @@
+-- name: CreateMedicineImportJob :one
+INSERT INTO medicine_import_jobs (
+    seller_username, file_name, file_format, upsert
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetMedicineImportJob :one
+SELECT * FROM medicine_import_jobs WHERE id = $1;
+
+-- name: ListMedicineImportJobs :many
+SELECT * FROM medicine_import_jobs
+WHERE seller_username = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: UpdateMedicineImportJobStatus :exec
+UPDATE medicine_import_jobs SET status = $2
+WHERE id = $1;
+
+-- name: CompleteMedicineImportJob :one
+UPDATE medicine_import_jobs SET
+    status = sqlc.arg(status),
+    total_rows = sqlc.arg(total_rows),
+    created_rows = sqlc.arg(created_rows),
+    updated_rows = sqlc.arg(updated_rows),
+    failed_rows = sqlc.arg(failed_rows),
+    row_errors = sqlc.arg(row_errors),
+    completed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
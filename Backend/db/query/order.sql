@@ -0,0 +1,127 @@
+-- name: ListCartItemsForCheckout :many
+-- Ordered by medicine so concurrent checkouts lock medicines in the same
+-- order
+SELECT c.id, c.medicine_id, c.quantity, m.seller_username
+FROM carts c
+JOIN medicines m ON m.id = c.medicine_id
+WHERE c.patient_username = $1
+ORDER BY c.medicine_id ASC;
+
+-- name: CreateOrder :one
+INSERT INTO orders (
+    patient_username, delivery_address, payment_due_by
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: CreateSubOrder :one
+INSERT INTO sub_orders (
+    order_id, seller_username
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: CreateOrderItem :one
+INSERT INTO order_items (
+    sub_order_id, medicine_id, medicine_name, batch_number, quantity,
+    unit_price, discount, line_total, prescription_required
+) VALUES (
+    sqlc.arg(sub_order_id), sqlc.arg(medicine_id), sqlc.arg(medicine_name),
+    sqlc.arg(batch_number), sqlc.arg(quantity), sqlc.arg(unit_price), sqlc.arg(discount),
+    ROUND(sqlc.arg(unit_price)::NUMERIC * sqlc.arg(quantity)::INT * (100 - sqlc.arg(discount)::INT) / 100, 2),
+    sqlc.arg(prescription_required)
+)
+RETURNING *;
+
+-- name: UpdateSubOrderSubtotal :one
+UPDATE sub_orders SET
+    subtotal = (
+        SELECT COALESCE(SUM(oi.line_total), 0) FROM order_items oi
+        WHERE oi.sub_order_id = sqlc.arg(id)
+    )
+WHERE sub_orders.id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateOrderTotal :one
+UPDATE orders SET
+    total_amount = (
+        SELECT COALESCE(SUM(so.subtotal), 0) FROM sub_orders so
+        WHERE so.order_id = sqlc.arg(id)
+    )
+WHERE orders.id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetOrder :one
+SELECT * FROM orders WHERE id = $1;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders WHERE id = $1
+FOR UPDATE;
+
+-- name: MarkOrderPaid :one
+UPDATE orders SET
+    payment_status = 'paid',
+    paid_payment_id = sqlc.arg(payment_id)
+WHERE id = sqlc.arg(id) AND payment_status = 'pending'
+RETURNING *;
+
+-- name: ListOverdueOrders :many
+-- Unpaid orders whose hold on their stock has run out
+SELECT id FROM orders
+WHERE payment_status = 'pending' AND payment_due_by < sqlc.arg(now)
+ORDER BY id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: ExpireOrder :one
+UPDATE orders SET payment_status = 'expired'
+WHERE id = $1 AND payment_status = 'pending'
+RETURNING *;
+
+-- name: ListPatientOrders :many
+SELECT * FROM orders
+WHERE patient_username = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListOrderSubOrders :many
+SELECT * FROM sub_orders
+WHERE order_id = $1
+ORDER BY id ASC;
+
+-- name: ListOrderItems :many
+SELECT oi.* FROM order_items oi
+JOIN sub_orders so ON so.id = oi.sub_order_id
+WHERE so.order_id = $1
+ORDER BY oi.id ASC;
+
+-- name: GetSubOrder :one
+SELECT * FROM sub_orders WHERE id = $1;
+
+-- name: GetSubOrderForUpdate :one
+SELECT * FROM sub_orders WHERE id = $1
+FOR UPDATE;
+
+-- name: ListSubOrderItems :many
+SELECT * FROM order_items
+WHERE sub_order_id = $1
+ORDER BY id ASC;
+
+-- name: ListSellerSubOrders :many
+SELECT so.*, o.patient_username, o.delivery_address
+FROM sub_orders so
+JOIN orders o ON o.id = so.order_id
+WHERE so.seller_username = sqlc.arg(seller)
+  AND (sqlc.narg(status)::VARCHAR IS NULL OR so.status = sqlc.narg(status))
+ORDER BY so.created_at DESC, so.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateSubOrderStatus :one
+-- Moves a sub-order on only if it is still in from_status
+UPDATE sub_orders SET
+    status = sqlc.arg(status),
+    updated_at = now(),
+    delivered_at = CASE WHEN sqlc.arg(status) = 'delivered' THEN now() ELSE delivered_at END
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
@@ -0,0 +1,71 @@
+-- name: CreateOrganization :one
+INSERT INTO organizations (
+    name, owner_username
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: GetOrganization :one
+SELECT * FROM organizations WHERE id = $1;
+
+-- name: UpdateOrganizationName :one
+UPDATE organizations SET name = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: SetSellerOrganization :one
+UPDATE sellers SET organization_id = sqlc.narg(organization_id)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: ListOrganizationBranches :many
+SELECT * FROM sellers
+WHERE organization_id = $1
+ORDER BY store_name ASC, username ASC;
+
+-- name: ListOrganizationBranchReport :many
+-- Sales between two dates (inclusive) and the stock on hand now for every
+-- branch of an organisation
+SELECT s.username, s.store_name, s.pincode,
+    COALESCE(sales.orders, 0)::INT AS orders,
+    COALESCE(sales.units, 0)::INT AS units_sold,
+    COALESCE(sales.revenue, 0)::NUMERIC(14, 2) AS revenue,
+    COALESCE(stock.medicines, 0)::INT AS medicines,
+    COALESCE(stock.units, 0)::INT AS stock_units,
+    COALESCE(stock.value, 0)::NUMERIC(14, 2) AS stock_value,
+    COALESCE(stock.low_stock, 0)::INT AS low_stock_medicines
+FROM sellers s
+LEFT JOIN (
+    SELECT seller_username, SUM(orders) AS orders, SUM(units) AS units, SUM(revenue) AS revenue
+    FROM seller_daily_sales
+    WHERE day BETWEEN sqlc.arg(from_date)::DATE AND sqlc.arg(to_date)::DATE
+    GROUP BY seller_username
+) sales ON sales.seller_username = s.username
+LEFT JOIN (
+    SELECT seller_username,
+        COUNT(*) AS medicines,
+        SUM(quantity) AS units,
+        SUM(quantity * price) AS value,
+        COUNT(*) FILTER (WHERE reorder_level IS NOT NULL AND quantity <= reorder_level) AS low_stock
+    FROM medicines
+    WHERE status = 'active'
+    GROUP BY seller_username
+) stock ON stock.seller_username = s.username
+WHERE s.organization_id = sqlc.arg(organization_id)
+ORDER BY revenue DESC, s.username ASC;
+
+-- name: ListOrganizationSalesByPeriod :many
+-- Revenue, units and orders of all branches together per day, week or month
+-- between two dates (inclusive)
+SELECT date_trunc(sqlc.arg(interval)::VARCHAR, d.day)::DATE AS period,
+    SUM(d.orders)::INT AS orders,
+    SUM(d.units)::INT AS units,
+    SUM(d.revenue)::NUMERIC(14, 2) AS revenue,
+    (SUM(d.revenue) / NULLIF(SUM(d.orders), 0))::NUMERIC(12, 2) AS average_order_value
+FROM seller_daily_sales d
+JOIN sellers s ON s.username = d.seller_username
+WHERE s.organization_id = sqlc.arg(organization_id)
+  AND d.day BETWEEN sqlc.arg(from_date)::DATE AND sqlc.arg(to_date)::DATE
+GROUP BY 1
+ORDER BY 1 ASC;
@@ -0,0 +1,94 @@
+-- name: CreatePatientDocument :one
+INSERT INTO patient_documents (
+    patient_username, document_type, title, document_date, tags, file_name,
+    content_type, size_bytes, storage_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetPatientDocument :one
+SELECT * FROM patient_documents WHERE id = $1;
+
+-- name: ListPatientDocuments :many
+-- The patient's documents, newest first, optionally of one type, with a
+-- tag or dated within a range
+SELECT * FROM patient_documents
+WHERE patient_username = sqlc.arg(patient_username)
+  AND (sqlc.narg(document_type)::VARCHAR IS NULL OR document_type = sqlc.narg(document_type))
+  AND (sqlc.narg(tag)::VARCHAR IS NULL OR sqlc.narg(tag)::VARCHAR = ANY(tags))
+  AND (sqlc.narg(from_date)::DATE IS NULL OR document_date >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::DATE IS NULL OR document_date <= sqlc.narg(to_date))
+ORDER BY COALESCE(document_date, created_at::DATE) DESC, id DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: UpdatePatientDocument :one
+UPDATE patient_documents SET
+    document_type = sqlc.arg(document_type),
+    title = sqlc.arg(title),
+    document_date = sqlc.narg(document_date),
+    tags = sqlc.arg(tags),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND patient_username = sqlc.arg(patient_username)
+RETURNING *;
+
+-- name: DeletePatientDocument :one
+DELETE FROM patient_documents
+WHERE id = sqlc.arg(id) AND patient_username = sqlc.arg(patient_username)
+RETURNING *;
+
+-- name: CreateDocumentShare :one
+INSERT INTO document_shares (
+    document_id, doctor_username, expires_at
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListDocumentShares :many
+-- Shares of the patient's documents that haven't expired or been revoked,
+-- the soonest to expire first
+SELECT s.*, d.title AS document_title, doc.full_name AS doctor_name
+FROM document_shares s
+JOIN patient_documents d ON d.id = s.document_id
+JOIN doctors doc ON doc.username = s.doctor_username
+WHERE d.patient_username = sqlc.arg(patient_username)
+  AND s.revoked_at IS NULL
+  AND s.expires_at > now()
+ORDER BY s.expires_at, s.id;
+
+-- name: RevokeDocumentShare :one
+UPDATE document_shares s SET revoked_at = now()
+FROM patient_documents d
+WHERE s.id = sqlc.arg(id)
+  AND d.id = s.document_id
+  AND d.patient_username = sqlc.arg(patient_username)
+  AND s.revoked_at IS NULL
+RETURNING s.*;
+
+-- name: IsDocumentSharedWithDoctor :one
+SELECT EXISTS (
+    SELECT 1 FROM document_shares
+    WHERE document_id = sqlc.arg(document_id)
+      AND doctor_username = sqlc.arg(doctor_username)
+      AND revoked_at IS NULL
+      AND expires_at > now()
+);
+
+-- name: ListDocumentsSharedWithDoctor :many
+-- The documents currently shared with the doctor, optionally by one
+-- patient, newest first. A document shared more than once comes with the
+-- share that lasts longest.
+SELECT DISTINCT ON (COALESCE(d.document_date, d.created_at::DATE), d.id)
+    d.id, d.patient_username, d.document_type, d.title, d.document_date, d.tags,
+    d.file_name, d.content_type, d.size_bytes, d.created_at,
+    p.full_name AS patient_name, s.expires_at AS shared_until
+FROM document_shares s
+JOIN patient_documents d ON d.id = s.document_id
+JOIN patients p ON p.username = d.patient_username
+WHERE s.doctor_username = sqlc.arg(doctor_username)
+  AND (sqlc.narg(patient_username)::VARCHAR IS NULL OR d.patient_username = sqlc.narg(patient_username))
+  AND s.revoked_at IS NULL
+  AND s.expires_at > now()
+ORDER BY COALESCE(d.document_date, d.created_at::DATE) DESC, d.id DESC, s.expires_at DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
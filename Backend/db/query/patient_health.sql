@@ -0,0 +1,64 @@
+-- name: SearchClinicalTerms :many
+-- Terms of one kind whose code or name matches the query, or all of them
+-- when there is no query
+SELECT * FROM clinical_terms
+WHERE kind = sqlc.arg(kind)
+  AND (sqlc.arg(query)::VARCHAR = ''
+    OR code ILIKE sqlc.arg(query)::VARCHAR || '%'
+    OR name ILIKE '%' || sqlc.arg(query)::VARCHAR || '%')
+ORDER BY name
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetClinicalTerm :one
+SELECT * FROM clinical_terms WHERE code = $1;
+
+-- name: CreatePatientHealthEntry :one
+INSERT INTO patient_health_entries (
+    patient_username, kind, term_code, description, reaction, severity,
+    onset_date, status
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: GetPatientHealthEntry :one
+SELECT * FROM patient_health_entries
+WHERE id = sqlc.arg(id) AND patient_username = sqlc.arg(patient_username);
+
+-- name: UpdatePatientHealthEntry :one
+UPDATE patient_health_entries SET
+    description = sqlc.arg(description),
+    reaction = sqlc.arg(reaction),
+    severity = sqlc.arg(severity),
+    onset_date = sqlc.narg(onset_date),
+    status = sqlc.arg(status),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND patient_username = sqlc.arg(patient_username)
+RETURNING *;
+
+-- name: DeletePatientHealthEntry :execrows
+DELETE FROM patient_health_entries
+WHERE id = sqlc.arg(id) AND patient_username = sqlc.arg(patient_username);
+
+-- name: ListPatientHealthEntries :many
+-- The patient's allergies or conditions with their terms, active ones
+-- first and the most severe first within them
+SELECT e.*, t.name AS term_name
+FROM patient_health_entries e
+JOIN clinical_terms t ON t.code = e.term_code
+WHERE e.patient_username = sqlc.arg(patient_username)
+  AND e.kind = sqlc.arg(kind)
+ORDER BY e.status = 'active' DESC,
+    array_position(ARRAY['severe', 'moderate', 'mild', 'unknown']::VARCHAR[], e.severity),
+    t.name, e.id;
+
+-- name: ListPatientHealthFlags :many
+-- The patient's active allergies and conditions that flag medicines, with
+-- the ingredients they flag
+SELECT e.id, e.kind, e.term_code, t.name AS term_name, e.severity, t.ingredients
+FROM patient_health_entries e
+JOIN clinical_terms t ON t.code = e.term_code
+WHERE e.patient_username = $1
+  AND e.status = 'active'
+  AND cardinality(t.ingredients) > 0
+ORDER BY e.kind, e.id;
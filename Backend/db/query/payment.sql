@@ -0,0 +1,125 @@
+-- name: CreateAppointmentPayment :one
+-- Records an intent created for an appointment's fee; it stays pending
+-- until the provider confirms it
+INSERT INTO payments (
+    appointment_id, user_id, amount, currency, status,
+    payment_method, payment_intent_id
+) VALUES (
+    $1, $2, $3, $4, 'pending', '', $5
+)
+RETURNING *;
+
+-- name: ListAppointmentPayments :many
+SELECT * FROM payments
+WHERE appointment_id = $1
+ORDER BY created_at, id;
+
+-- name: MarkPaymentRefunded :one
+-- Records that a payment was refunded in full through the provider
+UPDATE payments SET
+    status = 'refunded',
+    refunded_at = now(),
+    refund_reason = sqlc.arg(refund_reason),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'succeeded'
+RETURNING *;
+
+-- name: ListUnrefundedAppointmentPayments :many
+-- Payments of appointments cancelled with a refund that haven't been paid
+-- back through the provider yet
+SELECT p.* FROM payments p
+JOIN appointments a ON a.paid_payment_id = p.id
+WHERE a.payment_status = 'refunded' AND p.status = 'succeeded'
+ORDER BY p.id ASC
+LIMIT $1;
+
+-- name: CreateOrderPayment :one
+-- Records an intent created for an order; it stays pending until the
+-- provider confirms it
+INSERT INTO payments (
+    order_id, user_id, amount, currency, status,
+    payment_method, payment_intent_id
+) VALUES (
+    $1, $2, $3, $4, 'pending', '', $5
+)
+RETURNING *;
+
+-- name: GetPayment :one
+SELECT * FROM payments WHERE id = $1;
+
+-- name: GetPaymentByIntent :one
+SELECT * FROM payments WHERE payment_intent_id = $1;
+
+-- name: GetPaymentByIntentForUpdate :one
+SELECT * FROM payments WHERE payment_intent_id = $1
+FOR UPDATE;
+
+-- name: MarkPaymentSucceeded :one
+UPDATE payments SET
+    status = 'succeeded',
+    charge_id = sqlc.narg(charge_id),
+    payment_method = COALESCE(NULLIF(sqlc.arg(payment_method)::VARCHAR, ''), payment_method),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreatePaymentRefund :one
+INSERT INTO payment_refunds (
+    payment_id, provider_refund_id, amount, reason, sub_order_id,
+    ledger_transaction_id, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListPaymentRefunds :many
+SELECT * FROM payment_refunds
+WHERE payment_id = $1
+ORDER BY created_at, id;
+
+-- name: ListUnrefundedCancelledSubOrders :many
+-- Sub-orders cancelled after their order was paid for whose share of the
+-- payment hasn't been refunded yet
+SELECT so.id, so.order_id, so.subtotal, p.id AS payment_id
+FROM sub_orders so
+JOIN orders o ON o.id = so.order_id
+JOIN payments p ON p.id = o.paid_payment_id
+WHERE so.status = 'cancelled' AND o.payment_status = 'paid'
+  AND so.subtotal > 0
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_refunds r WHERE r.sub_order_id = so.id
+  )
+ORDER BY so.id ASC
+LIMIT $1;
+
+-- name: ListUnpaidLedgerRefunds :many
+-- Refunds posted to the ledger on delivered sub-orders that haven't been
+-- paid back through the provider yet
+SELECT t.id, t.sub_order_id, t.description, t.created_by,
+    (-SUM(e.amount))::NUMERIC(14, 2) AS amount,
+    o.paid_payment_id::INT AS payment_id
+FROM ledger_transactions t
+JOIN ledger_entries e ON e.transaction_id = t.id AND e.account = 'customer_payments'
+JOIN sub_orders so ON so.id = t.sub_order_id
+JOIN orders o ON o.id = so.order_id
+WHERE t.kind = 'refund' AND o.paid_payment_id IS NOT NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_refunds r WHERE r.ledger_transaction_id = t.id
+  )
+GROUP BY t.id, o.paid_payment_id
+ORDER BY t.id ASC
+LIMIT $1;
+
+-- name: GetUnpaidLedgerRefund :one
+SELECT t.id, t.sub_order_id, t.description, t.created_by,
+    (-SUM(e.amount))::NUMERIC(14, 2) AS amount,
+    o.paid_payment_id::INT AS payment_id
+FROM ledger_transactions t
+JOIN ledger_entries e ON e.transaction_id = t.id AND e.account = 'customer_payments'
+JOIN sub_orders so ON so.id = t.sub_order_id
+JOIN orders o ON o.id = so.order_id
+WHERE t.id = $1 AND t.kind = 'refund' AND o.paid_payment_id IS NOT NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_refunds r WHERE r.ledger_transaction_id = t.id
+  )
+GROUP BY t.id, o.paid_payment_id;
@@ -0,0 +1,43 @@
+-- name: CreatePayoutBatch :one
+INSERT INTO payout_batches (
+    cutoff
+) VALUES (
+    $1
+)
+RETURNING *;
+
+-- name: UpdatePayoutBatchTotals :one
+UPDATE payout_batches SET
+    payouts = (SELECT COUNT(*) FROM payouts p WHERE p.batch_id = sqlc.arg(id)),
+    total_amount = (SELECT COALESCE(SUM(p.amount), 0) FROM payouts p WHERE p.batch_id = sqlc.arg(id))
+WHERE payout_batches.id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreatePayout :one
+INSERT INTO payouts (
+    batch_id, seller_username, period_start, period_end, opening_balance,
+    gross_sales, commission, refunds, chargebacks, amount
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetPayout :one
+SELECT * FROM payouts WHERE id = $1;
+
+-- name: GetLastSellerPayout :one
+SELECT * FROM payouts
+WHERE seller_username = $1
+ORDER BY period_end DESC, id DESC
+LIMIT 1;
+
+-- name: ListSellerPayouts :many
+SELECT * FROM payouts
+WHERE seller_username = sqlc.arg(seller)
+ORDER BY period_end DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetLastPayoutBatch :one
+SELECT * FROM payout_batches
+ORDER BY cutoff DESC, id DESC
+LIMIT 1;
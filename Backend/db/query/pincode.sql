@@ -0,0 +1,17 @@
+-- name: GetPincode :one
+SELECT * FROM pincodes WHERE pincode = $1;
+
+-- name: UpsertPincode :one
+INSERT INTO pincodes (
+    pincode, locality, district, state, latitude, longitude
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (pincode) DO UPDATE
+SET
+    locality = EXCLUDED.locality,
+    district = EXCLUDED.district,
+    state = EXCLUDED.state,
+    latitude = EXCLUDED.latitude,
+    longitude = EXCLUDED.longitude
+RETURNING *;
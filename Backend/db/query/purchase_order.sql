@@ -0,0 +1,60 @@
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (
+    buyer_username, supplier_username, notes
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: CreatePurchaseOrderItem :one
+INSERT INTO purchase_order_items (
+    purchase_order_id, medicine_id, name, description, active_ingredients,
+    strength, dosage_form, prescription_required, batch_number, expiry_date,
+    mrp, quantity, unit_price
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+)
+RETURNING *;
+
+-- name: UpdatePurchaseOrderTotal :one
+UPDATE purchase_orders SET
+    total_amount = (
+        SELECT COALESCE(SUM(i.quantity * i.unit_price), 0)
+        FROM purchase_order_items i
+        WHERE i.purchase_order_id = sqlc.arg(id)
+    )
+WHERE purchase_orders.id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetPurchaseOrder :one
+SELECT * FROM purchase_orders WHERE id = $1;
+
+-- name: GetPurchaseOrderForUpdate :one
+SELECT * FROM purchase_orders WHERE id = $1
+FOR UPDATE;
+
+-- name: ListPurchaseOrderItems :many
+SELECT * FROM purchase_order_items
+WHERE purchase_order_id = $1
+ORDER BY id ASC;
+
+-- name: ListSellerPurchaseOrders :many
+-- Orders the seller placed as a buyer or received as a supplier
+SELECT * FROM purchase_orders
+WHERE (buyer_username = sqlc.arg(seller) OR supplier_username = sqlc.arg(seller))
+  AND (sqlc.narg(status)::VARCHAR IS NULL OR status = sqlc.narg(status))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdatePurchaseOrderStatus :one
+-- Moves an order on only if it is still in from_status, so concurrent
+-- changes can't both succeed
+UPDATE purchase_orders SET
+    status = sqlc.arg(status),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: SetPurchaseOrderItemReceived :exec
+UPDATE purchase_order_items SET received_medicine_id = $2
+WHERE id = $1;
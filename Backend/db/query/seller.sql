@@ -1,38 +1,105 @@
--- name: CreateSeller :one
-INSERT INTO sellers (
-  username, full_name, email, password, mobile_number,
-  store_name, gst_number, drug_license_number,
-  seller_type, store_address
-) VALUES (
-  $1, $2, $3, $4, $5,
-  $6, $7, $8,
-  $9, $10
-) RETURNING *;
-
--- name: GetSellerByName :one
-SELECT * FROM sellers WHERE username = $1;
-
--- name: ListSellersByStoreName :many
-SELECT * FROM sellers
-WHERE store_name ILIKE '%' || sqlc.arg('store_name') || '%'
-ORDER BY store_name
-LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
-
--- name: UpdateSeller :one
-UPDATE sellers SET
-  full_name = COALESCE(sqlc.narg(full_name), full_name),
-  email = COALESCE(sqlc.narg(email), email),
-  password = COALESCE(sqlc.narg(password), password),
-  mobile_number = COALESCE(sqlc.narg(mobile_number), mobile_number),
-  store_name = COALESCE(sqlc.narg(store_name), store_name),
-  gst_number = COALESCE(sqlc.narg(gst_number), gst_number),
-  drug_license_number = COALESCE(sqlc.narg(drug_license_number), drug_license_number),
-  seller_type = COALESCE(sqlc.narg(seller_type), seller_type),
-  store_address = COALESCE(sqlc.narg(store_address), store_address),
-  password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at)
-WHERE username = sqlc.arg(username)
-RETURNING *;
-
--- name: DeleteSeller :one
-DELETE FROM sellers WHERE username = $1
-RETURNING username;
\ No newline at end of file
+-- name: CreateSeller :one
+INSERT INTO sellers (
+  username, full_name, email, password, mobile_number,
+  store_name, gst_number, drug_license_number,
+  seller_type, store_address,
+  pincode, latitude, longitude, delivery_radius_km
+) VALUES (
+  $1, $2, $3, $4, $5,
+  $6, $7, $8,
+  $9, $10,
+  $11, $12, $13, $14
+) RETURNING *;
+
+-- name: GetSellerByName :one
+SELECT * FROM sellers WHERE username = $1;
+
+-- name: ListSellersByStoreName :many
+SELECT * FROM sellers
+WHERE store_name ILIKE '%' || sqlc.arg('store_name') || '%'
+ORDER BY store_name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateSeller :one
+UPDATE sellers SET
+  full_name = COALESCE(sqlc.narg(full_name), full_name),
+  email = COALESCE(sqlc.narg(email), email),
+  password = COALESCE(sqlc.narg(password), password),
+  mobile_number = COALESCE(sqlc.narg(mobile_number), mobile_number),
+  store_name = COALESCE(sqlc.narg(store_name), store_name),
+  gst_number = COALESCE(sqlc.narg(gst_number), gst_number),
+  drug_license_number = COALESCE(sqlc.narg(drug_license_number), drug_license_number),
+  seller_type = COALESCE(sqlc.narg(seller_type), seller_type),
+  store_address = COALESCE(sqlc.narg(store_address), store_address),
+  pincode = COALESCE(sqlc.narg(pincode), pincode),
+  latitude = COALESCE(sqlc.narg(latitude), latitude),
+  longitude = COALESCE(sqlc.narg(longitude), longitude),
+  delivery_radius_km = COALESCE(sqlc.narg(delivery_radius_km), delivery_radius_km),
+  password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at),
+  -- a seller changing type has to be verified again
+  verified_at = CASE WHEN sqlc.narg(seller_type) IS NOT NULL AND sqlc.narg(seller_type) <> seller_type
+                     THEN NULL ELSE verified_at END
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: DeleteSeller :one
+DELETE FROM sellers WHERE username = $1
+RETURNING username;
+
+-- name: ListNearbySellersWithMedicine :many
+-- Branches of a chain are collapsed to the one nearest the patient for each
+-- medicine, so that a chain doesn't fill the results with its own stores
+SELECT username, store_name, store_address, seller_type, mobile_number,
+    pincode, latitude, longitude, delivery_radius_km,
+    medicine_id, medicine_name, quantity, price, discount, expiry_date,
+    effective_price, distance_km, open_now, organization_id, organization_name
+FROM (
+    SELECT nearby.*,
+        ROW_NUMBER() OVER (
+            PARTITION BY CASE WHEN nearby.organization_id IS NULL
+                              THEN 'medicine:' || nearby.medicine_id
+                              ELSE 'organization:' || nearby.organization_id || ':' || lower(nearby.medicine_name) END
+            ORDER BY nearby.distance_km ASC, nearby.effective_price ASC
+        ) AS branch_rank
+    FROM (
+        SELECT s.username, s.store_name, s.store_address, s.seller_type, s.mobile_number,
+            s.pincode, s.latitude, s.longitude, s.delivery_radius_km,
+            m.id AS medicine_id, m.name AS medicine_name, m.quantity, m.price, m.discount, m.expiry_date,
+            (m.price * (100 - m.discount) / 100)::NUMERIC(10, 2) AS effective_price,
+            haversine_km(sqlc.arg(latitude)::FLOAT8, sqlc.arg(longitude)::FLOAT8, s.latitude, s.longitude)::FLOAT8 AS distance_km,
+            EXISTS (
+                SELECT 1 FROM seller_opening_hours h
+                WHERE h.seller_username = s.username
+                  AND h.day_of_week = EXTRACT(DOW FROM LOCALTIMESTAMP)
+                  AND LOCALTIME BETWEEN h.opens_at AND h.closes_at
+            ) AS open_now,
+            s.organization_id, o.name AS organization_name
+        FROM sellers s
+        JOIN medicines m ON m.seller_username = s.username
+        LEFT JOIN organizations o ON o.id = s.organization_id
+        WHERE s.latitude IS NOT NULL
+          AND s.longitude IS NOT NULL
+          -- cheap bounding box on latitude (1 degree is roughly 111 km) before the exact distance
+          AND s.latitude BETWEEN sqlc.arg(latitude) - sqlc.arg(max_distance_km)::FLOAT8 / 111
+                             AND sqlc.arg(latitude) + sqlc.arg(max_distance_km) / 111
+          AND m.quantity > 0
+          AND m.expiry_date > CURRENT_DATE
+          AND m.status = 'active'
+          AND (m.name ILIKE '%' || sqlc.arg(medicine)::VARCHAR || '%' OR m.name % sqlc.arg(medicine))
+    ) nearby
+    WHERE nearby.distance_km <= sqlc.arg(max_distance_km)
+) ranked
+WHERE branch_rank = 1
+ORDER BY distance_km ASC, effective_price ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: VerifySeller :one
+UPDATE sellers SET verified_at = COALESCE(verified_at, now())
+WHERE username = $1
+RETURNING *;
+
+-- name: ListUnverifiedSellers :many
+SELECT * FROM sellers
+WHERE seller_type IN ('ngo', 'hospital') AND verified_at IS NULL
+ORDER BY created_at ASC
+LIMIT $1 OFFSET $2;
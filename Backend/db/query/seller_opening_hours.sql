@@ -0,0 +1,16 @@
+-- name: CreateSellerOpeningHours :one
+INSERT INTO seller_opening_hours (
+    seller_username, day_of_week, opens_at, closes_at
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListSellerOpeningHours :many
+SELECT * FROM seller_opening_hours
+WHERE seller_username = $1
+ORDER BY day_of_week, opens_at;
+
+-- name: DeleteSellerOpeningHours :exec
+DELETE FROM seller_opening_hours
+WHERE seller_username = $1;
@@ -0,0 +1,60 @@
+-- name: CreateStoreStaff :one
+INSERT INTO store_staff (
+    username, seller_username, full_name, email, password, staff_role, invited_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetStoreStaff :one
+SELECT * FROM store_staff WHERE username = $1;
+
+-- name: ListStoreStaff :many
+SELECT * FROM store_staff
+WHERE seller_username = $1
+ORDER BY created_at ASC, username ASC;
+
+-- name: UpdateStoreStaffRole :one
+UPDATE store_staff SET staff_role = sqlc.arg(staff_role)
+WHERE username = sqlc.arg(username) AND seller_username = sqlc.arg(seller_username)
+RETURNING *;
+
+-- name: DeleteStoreStaff :one
+DELETE FROM store_staff
+WHERE username = sqlc.arg(username) AND seller_username = sqlc.arg(seller_username)
+RETURNING username;
+
+-- name: CreateStaffInvite :one
+INSERT INTO staff_invites (
+    seller_username, email, staff_role, token_hash, invited_by, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetStaffInviteByTokenForUpdate :one
+SELECT * FROM staff_invites
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: ListPendingStaffInvites :many
+SELECT * FROM staff_invites
+WHERE seller_username = $1
+  AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > now()
+ORDER BY created_at DESC;
+
+-- name: RevokeStaffInvite :one
+UPDATE staff_invites SET revoked_at = now()
+WHERE id = sqlc.arg(id) AND seller_username = sqlc.arg(seller_username)
+  AND accepted_at IS NULL AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeExpiredStaffInvites :exec
+-- Frees the pending slot of an email whose invite expired, so it can be
+-- invited again
+UPDATE staff_invites SET revoked_at = now()
+WHERE seller_username = sqlc.arg(seller_username) AND lower(email) = lower(sqlc.arg(email))
+  AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= now();
+
+-- name: AcceptStaffInvite :one
+UPDATE staff_invites SET accepted_at = now()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,24 @@
+-- name: CreateStockMovement :one
+INSERT INTO stock_movements (
+    medicine_id, seller_username, movement_type, quantity_change, quantity_after,
+    reason, reference, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: ListStockMovements :many
+SELECT * FROM stock_movements
+WHERE medicine_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetStockReconciliation :many
+SELECT m.id AS medicine_id, m.name, m.batch_number, m.quantity,
+    COALESCE(SUM(sm.quantity_change), 0)::INT AS ledger_quantity,
+    (m.quantity - COALESCE(SUM(sm.quantity_change), 0))::INT AS difference
+FROM medicines m
+LEFT JOIN stock_movements sm ON sm.medicine_id = m.id
+WHERE m.seller_username = $1
+GROUP BY m.id
+ORDER BY m.name ASC, m.id ASC;
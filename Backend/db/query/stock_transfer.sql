@@ -0,0 +1,57 @@
+-- name: CreateStockTransfer :one
+INSERT INTO stock_transfers (
+    organization_id, from_seller, to_seller, medicine_id, name, description,
+    active_ingredients, strength, dosage_form, prescription_required,
+    batch_number, expiry_date, price, quantity, notes, requested_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+)
+RETURNING *;
+
+-- name: GetStockTransfer :one
+SELECT * FROM stock_transfers WHERE id = $1;
+
+-- name: GetStockTransferForUpdate :one
+SELECT * FROM stock_transfers WHERE id = $1
+FOR UPDATE;
+
+-- name: ListSellerStockTransfers :many
+-- Transfers the branch sends or receives
+SELECT * FROM stock_transfers
+WHERE (from_seller = sqlc.arg(seller) OR to_seller = sqlc.arg(seller))
+  AND (sqlc.narg(status)::VARCHAR IS NULL OR status = sqlc.narg(status))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListOrganizationStockTransfers :many
+SELECT * FROM stock_transfers
+WHERE organization_id = sqlc.arg(organization_id)
+  AND (sqlc.narg(status)::VARCHAR IS NULL OR status = sqlc.narg(status))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateStockTransferStatus :one
+-- Moves a transfer on only if it is still in from_status, so concurrent
+-- changes can't both succeed
+UPDATE stock_transfers SET
+    status = sqlc.arg(status),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: MarkStockTransferDispatched :one
+UPDATE stock_transfers SET
+    status = 'dispatched',
+    dispatched_by = $2,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkStockTransferReceived :one
+UPDATE stock_transfers SET
+    status = 'received',
+    received_by = $2,
+    received_medicine_id = $3,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,113 @@
+-- name: GetSubOrderForReview :one
+-- A sub-order with the patient who placed it
+SELECT so.*, o.patient_username
+FROM sub_orders so
+JOIN orders o ON o.id = so.order_id
+WHERE so.id = $1;
+
+-- name: GetOrderItemForReview :one
+-- An order line with its sub-order's seller and status and the patient
+-- who placed the order
+SELECT oi.*, so.seller_username, so.status AS sub_order_status, o.patient_username
+FROM order_items oi
+JOIN sub_orders so ON so.id = oi.sub_order_id
+JOIN orders o ON o.id = so.order_id
+WHERE oi.id = $1;
+
+-- name: CreateSellerReview :one
+INSERT INTO seller_reviews (
+    sub_order_id, seller_username, patient_username, delivery_rating,
+    packaging_rating, authenticity_rating, comment, status, moderation_note
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetSellerReviewBySubOrder :one
+SELECT * FROM seller_reviews WHERE sub_order_id = $1;
+
+-- name: ListSellerReviews :many
+-- A seller's published reviews, newest first
+SELECT r.id, r.sub_order_id, r.delivery_rating, r.packaging_rating, r.authenticity_rating,
+    r.comment, r.seller_reply, r.replied_at, r.created_at, p.full_name AS patient_name
+FROM seller_reviews r
+JOIN patients p ON p.username = r.patient_username
+WHERE r.seller_username = sqlc.arg(seller_username)
+  AND r.status = 'published'
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: ReplyToSellerReview :one
+UPDATE seller_reviews SET
+    seller_reply = sqlc.arg(seller_reply),
+    replied_by = sqlc.arg(replied_by),
+    replied_at = now()
+WHERE id = sqlc.arg(id)
+  AND seller_username = sqlc.arg(seller_username)
+  AND status = 'published'
+RETURNING *;
+
+-- name: CreateMedicineReview :one
+INSERT INTO medicine_reviews (
+    order_item_id, medicine_id, medicine_name, seller_username,
+    patient_username, rating, comment, status, moderation_note
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetMedicineReviewByOrderItem :one
+SELECT * FROM medicine_reviews WHERE order_item_id = $1;
+
+-- name: ListMedicineReviews :many
+-- The published reviews of every batch the seller has listed under the
+-- medicine's name, newest first
+SELECT r.id, r.order_item_id, r.medicine_id, r.rating, r.comment, r.seller_reply, r.replied_at,
+    r.created_at, p.full_name AS patient_name
+FROM medicine_reviews r
+JOIN patients p ON p.username = r.patient_username
+WHERE r.seller_username = sqlc.arg(seller_username)
+  AND r.medicine_name = sqlc.arg(medicine_name)
+  AND r.status = 'published'
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: ReplyToMedicineReview :one
+UPDATE medicine_reviews SET
+    seller_reply = sqlc.arg(seller_reply),
+    replied_by = sqlc.arg(replied_by),
+    replied_at = now()
+WHERE id = sqlc.arg(id)
+  AND seller_username = sqlc.arg(seller_username)
+  AND status = 'published'
+RETURNING *;
+
+-- name: ListPendingSellerReviews :many
+-- Held seller reviews, oldest first
+SELECT * FROM seller_reviews
+WHERE status = 'pending'
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: ModerateSellerReview :one
+UPDATE seller_reviews SET
+    status = sqlc.arg(status),
+    moderation_note = sqlc.arg(moderation_note),
+    moderated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListPendingMedicineReviews :many
+-- Held medicine reviews, oldest first
+SELECT * FROM medicine_reviews
+WHERE status = 'pending'
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: ModerateMedicineReview :one
+UPDATE medicine_reviews SET
+    status = sqlc.arg(status),
+    moderation_note = sqlc.arg(moderation_note),
+    moderated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
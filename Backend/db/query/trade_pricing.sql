@@ -0,0 +1,56 @@
+-- name: UpdateMedicineTradePricing :one
+UPDATE medicines SET
+    trade_price = sqlc.narg(trade_price),
+    min_order_quantity = sqlc.arg(min_order_quantity)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteTradePriceTiers :exec
+DELETE FROM trade_price_tiers WHERE medicine_id = $1;
+
+-- name: CreateTradePriceTier :one
+INSERT INTO trade_price_tiers (
+    medicine_id, min_quantity, unit_price
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListTradePriceTiers :many
+SELECT * FROM trade_price_tiers
+WHERE medicine_id = $1
+ORDER BY min_quantity ASC;
+
+-- name: ListTradePriceTiersForMedicines :many
+SELECT * FROM trade_price_tiers
+WHERE medicine_id = ANY(sqlc.arg(medicine_ids)::INT[])
+ORDER BY medicine_id ASC, min_quantity ASC;
+
+-- name: GetTradeUnitPrice :one
+-- Unit price of an order of the given quantity: the largest tier the
+-- quantity reaches, or the base trade price below every tier
+SELECT COALESCE((
+    SELECT t.unit_price FROM trade_price_tiers t
+    WHERE t.medicine_id = m.id AND t.min_quantity <= sqlc.arg(quantity)::INT
+    ORDER BY t.min_quantity DESC
+    LIMIT 1
+), m.trade_price)::NUMERIC(10, 2) AS unit_price
+FROM medicines m
+WHERE m.id = sqlc.arg(medicine_id);
+
+-- name: ListWholesaleCatalogue :many
+SELECT m.id, m.name, m.description, m.active_ingredients, m.strength, m.dosage_form,
+    m.prescription_required, m.batch_number, m.expiry_date, m.quantity,
+    m.price AS mrp, m.trade_price, m.min_order_quantity,
+    s.username AS supplier_username, s.store_name, s.pincode
+FROM medicines m
+JOIN sellers s ON s.username = m.seller_username
+WHERE s.seller_type = 'wholesale'
+  AND m.trade_price IS NOT NULL
+  AND m.status = 'active'
+  AND m.quantity > 0
+  AND m.expiry_date > CURRENT_DATE
+  AND (sqlc.narg(supplier)::VARCHAR IS NULL OR s.username = sqlc.narg(supplier))
+  AND (sqlc.arg(medicine)::VARCHAR = '' OR m.name ILIKE '%' || sqlc.arg(medicine) || '%')
+ORDER BY m.name ASC, m.expiry_date DESC, m.id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
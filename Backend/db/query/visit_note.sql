@@ -0,0 +1,89 @@
+-- name: SearchICD10Codes :many
+-- Codes starting with the query come first, then descriptions matching it
+SELECT * FROM icd10_codes
+WHERE code ILIKE sqlc.arg(query)::VARCHAR || '%'
+   OR description ILIKE '%' || sqlc.arg(query)::VARCHAR || '%'
+ORDER BY code ILIKE sqlc.arg(query)::VARCHAR || '%' DESC, code
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetICD10Code :one
+SELECT * FROM icd10_codes WHERE code = $1;
+
+-- name: SaveVisitNote :one
+-- Creates the note of an appointment or replaces its draft. A finalized
+-- note is left alone and no row is returned.
+INSERT INTO visit_notes (
+    appointment_id, doctor_username, patient_username, chief_complaint,
+    bp_systolic, bp_diastolic, pulse_bpm, temperature_c, respiratory_rate,
+    spo2_percent, weight_kg, height_cm, diagnosis, icd10_code, advice
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+ON CONFLICT (appointment_id) DO UPDATE SET
+    chief_complaint = EXCLUDED.chief_complaint,
+    bp_systolic = EXCLUDED.bp_systolic,
+    bp_diastolic = EXCLUDED.bp_diastolic,
+    pulse_bpm = EXCLUDED.pulse_bpm,
+    temperature_c = EXCLUDED.temperature_c,
+    respiratory_rate = EXCLUDED.respiratory_rate,
+    spo2_percent = EXCLUDED.spo2_percent,
+    weight_kg = EXCLUDED.weight_kg,
+    height_cm = EXCLUDED.height_cm,
+    diagnosis = EXCLUDED.diagnosis,
+    icd10_code = EXCLUDED.icd10_code,
+    advice = EXCLUDED.advice,
+    updated_at = now()
+WHERE visit_notes.status = 'draft'
+RETURNING *;
+
+-- name: GetVisitNoteByAppointment :one
+SELECT * FROM visit_notes WHERE appointment_id = $1;
+
+-- name: FinalizeVisitNote :one
+UPDATE visit_notes SET
+    status = 'finalized',
+    finalized_at = now(),
+    updated_at = now()
+WHERE id = $1 AND status = 'draft'
+RETURNING *;
+
+-- name: CreatePrescriptionItem :one
+INSERT INTO prescription_items (
+    visit_note_id, medicine_name, active_ingredients, strength, dosage_form,
+    dose, frequency, duration_days, instructions
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: DeletePrescriptionItems :exec
+DELETE FROM prescription_items WHERE visit_note_id = $1;
+
+-- name: ListPrescriptionItems :many
+SELECT * FROM prescription_items
+WHERE visit_note_id = ANY(sqlc.arg(visit_note_ids)::INTEGER[])
+ORDER BY visit_note_id, id;
+
+-- name: ListPatientVisitSummaries :many
+-- The patient's finalized visit notes, newest first, with the doctor who
+-- wrote them
+SELECT v.*, d.full_name AS doctor_name, d.specialization, d.hospital_name,
+    a.starts_at AS appointment_starts_at, i.description AS icd10_description
+FROM visit_notes v
+JOIN doctors d ON d.username = v.doctor_username
+JOIN appointments a ON a.id = v.appointment_id
+LEFT JOIN icd10_codes i ON i.code = v.icd10_code
+WHERE v.patient_username = sqlc.arg(patient_username)
+  AND v.status = 'finalized'
+ORDER BY a.starts_at DESC, v.id DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: IsDoctorOfPatient :one
+-- Whether the doctor has an appointment with the patient that wasn't
+-- cancelled, which lets them read the patient's history
+SELECT EXISTS (
+    SELECT 1 FROM appointments
+    WHERE doctor_username = sqlc.arg(doctor_username)
+      AND patient_username = sqlc.arg(patient_username)
+      AND status <> 'cancelled'
+)::BOOLEAN;
@@ -0,0 +1,60 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: admin.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createAdmin = `-- name: CreateAdmin :one
+INSERT INTO admins (
+  username, full_name, email, password
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING username, full_name, email, password, created_at
+`
+
+type CreateAdminParams struct {
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (q *Queries) CreateAdmin(ctx context.Context, arg CreateAdminParams) (Admin, error) {
+	row := q.db.QueryRow(ctx, createAdmin,
+		arg.Username,
+		arg.FullName,
+		arg.Email,
+		arg.Password,
+	)
+	var i Admin
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAdmin = `-- name: GetAdmin :one
+SELECT username, full_name, email, password, created_at FROM admins WHERE username = $1
+`
+
+func (q *Queries) GetAdmin(ctx context.Context, username string) (Admin, error) {
+	row := q.db.QueryRow(ctx, getAdmin, username)
+	var i Admin
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+	)
+	return i, err
+}
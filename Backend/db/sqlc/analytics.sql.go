@@ -0,0 +1,246 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: analytics.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getSellerInventorySummary = `-- name: GetSellerInventorySummary :one
+SELECT
+    COALESCE(SUM(sm.quantity_change) FILTER (
+        WHERE sm.created_at < $1::DATE
+    ), 0)::INT AS opening_stock,
+    COALESCE(SUM(sm.quantity_change), 0)::INT AS closing_stock,
+    COALESCE(-SUM(sm.quantity_change) FILTER (
+        WHERE sm.movement_type = 'sale' AND sm.created_at >= $1::DATE
+    ), 0)::INT AS units_sold,
+    COALESCE(-SUM(sm.quantity_change) FILTER (
+        WHERE sm.movement_type = 'sale' AND sm.created_at >= $1::DATE
+          AND m.expiry_date - sm.created_at::DATE <= $2::INT
+    ), 0)::INT AS near_expiry_units_sold,
+    COALESCE(-SUM(sm.quantity_change) FILTER (
+        WHERE sm.movement_type = 'expiry_write_off' AND sm.created_at >= $1::DATE
+    ), 0)::INT AS expiry_write_off_units,
+    COALESCE(-SUM(sm.quantity_change * COALESCE(m.price, 0)) FILTER (
+        WHERE sm.movement_type = 'expiry_write_off' AND sm.created_at >= $1::DATE
+    ), 0)::NUMERIC(14, 2) AS expiry_write_off_value
+FROM stock_movements sm
+LEFT JOIN medicines m ON m.id = sm.medicine_id
+WHERE sm.seller_username = $3
+  AND sm.created_at < $4::DATE + 1
+`
+
+type GetSellerInventorySummaryParams struct {
+	FromDate       pgtype.Date `json:"from_date"`
+	NearExpiryDays int32       `json:"near_expiry_days"`
+	Seller         string      `json:"seller"`
+	ToDate         pgtype.Date `json:"to_date"`
+}
+
+type GetSellerInventorySummaryRow struct {
+	OpeningStock        int32          `json:"opening_stock"`
+	ClosingStock        int32          `json:"closing_stock"`
+	UnitsSold           int32          `json:"units_sold"`
+	NearExpiryUnitsSold int32          `json:"near_expiry_units_sold"`
+	ExpiryWriteOffUnits int32          `json:"expiry_write_off_units"`
+	ExpiryWriteOffValue pgtype.Numeric `json:"expiry_write_off_value"`
+}
+
+// Inventory figures between two dates (inclusive) taken from the stock
+// ledger. Write-offs are valued at the medicine's current price, and a sale
+// counts as near-expiry when it was made within near_expiry_days of expiry.
+func (q *Queries) GetSellerInventorySummary(ctx context.Context, arg GetSellerInventorySummaryParams) (GetSellerInventorySummaryRow, error) {
+	row := q.db.QueryRow(ctx, getSellerInventorySummary,
+		arg.FromDate,
+		arg.NearExpiryDays,
+		arg.Seller,
+		arg.ToDate,
+	)
+	var i GetSellerInventorySummaryRow
+	err := row.Scan(
+		&i.OpeningStock,
+		&i.ClosingStock,
+		&i.UnitsSold,
+		&i.NearExpiryUnitsSold,
+		&i.ExpiryWriteOffUnits,
+		&i.ExpiryWriteOffValue,
+	)
+	return i, err
+}
+
+const getSellerSalesSummary = `-- name: GetSellerSalesSummary :one
+SELECT COALESCE(SUM(orders), 0)::INT AS orders,
+    COALESCE(SUM(units), 0)::INT AS units,
+    COALESCE(SUM(revenue), 0)::NUMERIC(14, 2) AS revenue,
+    COALESCE(SUM(revenue) / NULLIF(SUM(orders), 0), 0)::NUMERIC(12, 2) AS average_order_value
+FROM seller_daily_sales
+WHERE seller_username = $1
+  AND day BETWEEN $2::DATE AND $3::DATE
+`
+
+type GetSellerSalesSummaryParams struct {
+	Seller   string      `json:"seller"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+type GetSellerSalesSummaryRow struct {
+	Orders            int32          `json:"orders"`
+	Units             int32          `json:"units"`
+	Revenue           pgtype.Numeric `json:"revenue"`
+	AverageOrderValue pgtype.Numeric `json:"average_order_value"`
+}
+
+func (q *Queries) GetSellerSalesSummary(ctx context.Context, arg GetSellerSalesSummaryParams) (GetSellerSalesSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getSellerSalesSummary, arg.Seller, arg.FromDate, arg.ToDate)
+	var i GetSellerSalesSummaryRow
+	err := row.Scan(
+		&i.Orders,
+		&i.Units,
+		&i.Revenue,
+		&i.AverageOrderValue,
+	)
+	return i, err
+}
+
+const listSellerSalesByPeriod = `-- name: ListSellerSalesByPeriod :many
+SELECT date_trunc($1::VARCHAR, day)::DATE AS period,
+    SUM(orders)::INT AS orders,
+    SUM(units)::INT AS units,
+    SUM(revenue)::NUMERIC(14, 2) AS revenue,
+    (SUM(revenue) / NULLIF(SUM(orders), 0))::NUMERIC(12, 2) AS average_order_value
+FROM seller_daily_sales
+WHERE seller_username = $2
+  AND day BETWEEN $3::DATE AND $4::DATE
+GROUP BY 1
+ORDER BY 1 ASC
+`
+
+type ListSellerSalesByPeriodParams struct {
+	Interval string      `json:"interval"`
+	Seller   string      `json:"seller"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+type ListSellerSalesByPeriodRow struct {
+	Period            pgtype.Date    `json:"period"`
+	Orders            int32          `json:"orders"`
+	Units             int32          `json:"units"`
+	Revenue           pgtype.Numeric `json:"revenue"`
+	AverageOrderValue pgtype.Numeric `json:"average_order_value"`
+}
+
+// Revenue, units and orders per day, week or month between two dates
+// (inclusive)
+func (q *Queries) ListSellerSalesByPeriod(ctx context.Context, arg ListSellerSalesByPeriodParams) ([]ListSellerSalesByPeriodRow, error) {
+	rows, err := q.db.Query(ctx, listSellerSalesByPeriod,
+		arg.Interval,
+		arg.Seller,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSellerSalesByPeriodRow{}
+	for rows.Next() {
+		var i ListSellerSalesByPeriodRow
+		if err := rows.Scan(
+			&i.Period,
+			&i.Orders,
+			&i.Units,
+			&i.Revenue,
+			&i.AverageOrderValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopSellerMedicines = `-- name: ListTopSellerMedicines :many
+SELECT medicine_id,
+    MAX(medicine_name)::VARCHAR AS medicine_name,
+    SUM(units)::INT AS units,
+    SUM(revenue)::NUMERIC(14, 2) AS revenue
+FROM seller_daily_medicine_sales
+WHERE seller_username = $1
+  AND day BETWEEN $2::DATE AND $3::DATE
+GROUP BY medicine_id
+ORDER BY revenue DESC, units DESC, medicine_id ASC
+LIMIT $4
+`
+
+type ListTopSellerMedicinesParams struct {
+	Seller   string      `json:"seller"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+	Limit    int32       `json:"limit"`
+}
+
+type ListTopSellerMedicinesRow struct {
+	MedicineID   int32          `json:"medicine_id"`
+	MedicineName string         `json:"medicine_name"`
+	Units        int32          `json:"units"`
+	Revenue      pgtype.Numeric `json:"revenue"`
+}
+
+func (q *Queries) ListTopSellerMedicines(ctx context.Context, arg ListTopSellerMedicinesParams) ([]ListTopSellerMedicinesRow, error) {
+	rows, err := q.db.Query(ctx, listTopSellerMedicines,
+		arg.Seller,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTopSellerMedicinesRow{}
+	for rows.Next() {
+		var i ListTopSellerMedicinesRow
+		if err := rows.Scan(
+			&i.MedicineID,
+			&i.MedicineName,
+			&i.Units,
+			&i.Revenue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const refreshSellerDailyMedicineSales = `-- name: RefreshSellerDailyMedicineSales :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY seller_daily_medicine_sales
+`
+
+func (q *Queries) RefreshSellerDailyMedicineSales(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, refreshSellerDailyMedicineSales)
+	return err
+}
+
+const refreshSellerDailySales = `-- name: RefreshSellerDailySales :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY seller_daily_sales
+`
+
+func (q *Queries) RefreshSellerDailySales(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, refreshSellerDailySales)
+	return err
+}
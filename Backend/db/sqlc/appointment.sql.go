@@ -0,0 +1,848 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: appointment.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelAppointment = `-- name: CancelAppointment :one
+UPDATE appointments SET
+    status = 'cancelled',
+    payment_status = CASE WHEN payment_status = 'pending' THEN 'unpaid' ELSE payment_status END,
+    cancelled_by = $1,
+    cancellation_reason = $2,
+    updated_at = now()
+WHERE id = $3 AND status = 'booked'
+RETURNING id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id
+`
+
+type CancelAppointmentParams struct {
+	CancelledBy        pgtype.Text `json:"cancelled_by"`
+	CancellationReason pgtype.Text `json:"cancellation_reason"`
+	ID                 int32       `json:"id"`
+}
+
+// An appointment cancelled before it was paid for is left unpaid
+func (q *Queries) CancelAppointment(ctx context.Context, arg CancelAppointmentParams) (Appointment, error) {
+	row := q.db.QueryRow(ctx, cancelAppointment, arg.CancelledBy, arg.CancellationReason, arg.ID)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Status,
+		&i.Reason,
+		&i.CancelledBy,
+		&i.CancellationReason,
+		&i.RescheduledCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PatientCheckedInAt,
+		&i.DoctorCheckedInAt,
+		&i.VisitType,
+		&i.Fee,
+		&i.FollowUpOf,
+		&i.PaymentStatus,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const checkInAppointment = `-- name: CheckInAppointment :one
+UPDATE appointments SET
+    patient_checked_in_at = CASE WHEN $1::BOOLEAN
+        THEN patient_checked_in_at ELSE COALESCE(patient_checked_in_at, now()) END,
+    doctor_checked_in_at = CASE WHEN $1::BOOLEAN
+        THEN COALESCE(doctor_checked_in_at, now()) ELSE doctor_checked_in_at END
+WHERE id = $2 AND status = 'booked'
+RETURNING id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id
+`
+
+type CheckInAppointmentParams struct {
+	ByDoctor bool  `json:"by_doctor"`
+	ID       int32 `json:"id"`
+}
+
+// Records when the patient or the doctor arrived; checking in twice keeps
+// the first time
+func (q *Queries) CheckInAppointment(ctx context.Context, arg CheckInAppointmentParams) (Appointment, error) {
+	row := q.db.QueryRow(ctx, checkInAppointment, arg.ByDoctor, arg.ID)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Status,
+		&i.Reason,
+		&i.CancelledBy,
+		&i.CancellationReason,
+		&i.RescheduledCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PatientCheckedInAt,
+		&i.DoctorCheckedInAt,
+		&i.VisitType,
+		&i.Fee,
+		&i.FollowUpOf,
+		&i.PaymentStatus,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const claimAppointmentReminder = `-- name: ClaimAppointmentReminder :execrows
+INSERT INTO appointment_reminders (
+    appointment_id, kind
+) VALUES (
+    $1, $2
+)
+ON CONFLICT DO NOTHING
+`
+
+type ClaimAppointmentReminderParams struct {
+	AppointmentID int32  `json:"appointment_id"`
+	Kind          string `json:"kind"`
+}
+
+func (q *Queries) ClaimAppointmentReminder(ctx context.Context, arg ClaimAppointmentReminderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, claimAppointmentReminder, arg.AppointmentID, arg.Kind)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const closeFinishedAppointments = `-- name: CloseFinishedAppointments :many
+UPDATE appointments SET
+    status = CASE
+        WHEN patient_checked_in_at IS NULL AND doctor_checked_in_at IS NULL THEN 'no_show'
+        ELSE 'completed'
+    END,
+    updated_at = now()
+WHERE status = 'booked' AND payment_status <> 'pending'
+  AND ends_at < $1
+RETURNING id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id
+`
+
+// Closes booked appointments that ended before ended_before: completed when
+// either side checked in, no_show when neither did
+func (q *Queries) CloseFinishedAppointments(ctx context.Context, endedBefore pgtype.Timestamp) ([]Appointment, error) {
+	rows, err := q.db.Query(ctx, closeFinishedAppointments, endedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Appointment{}
+	for rows.Next() {
+		var i Appointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.PatientUsername,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Status,
+			&i.Reason,
+			&i.CancelledBy,
+			&i.CancellationReason,
+			&i.RescheduledCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PatientCheckedInAt,
+			&i.DoctorCheckedInAt,
+			&i.VisitType,
+			&i.Fee,
+			&i.FollowUpOf,
+			&i.PaymentStatus,
+			&i.PaymentDueBy,
+			&i.PaidPaymentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createAppointment = `-- name: CreateAppointment :one
+INSERT INTO appointments (
+    doctor_username, patient_username, starts_at, ends_at, reason,
+    visit_type, fee, follow_up_of, payment_status, payment_due_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id
+`
+
+type CreateAppointmentParams struct {
+	DoctorUsername  string           `json:"doctor_username"`
+	PatientUsername string           `json:"patient_username"`
+	StartsAt        pgtype.Timestamp `json:"starts_at"`
+	EndsAt          pgtype.Timestamp `json:"ends_at"`
+	Reason          string           `json:"reason"`
+	VisitType       string           `json:"visit_type"`
+	Fee             pgtype.Numeric   `json:"fee"`
+	FollowUpOf      pgtype.Int4      `json:"follow_up_of"`
+	PaymentStatus   string           `json:"payment_status"`
+	PaymentDueBy    pgtype.Timestamp `json:"payment_due_by"`
+}
+
+func (q *Queries) CreateAppointment(ctx context.Context, arg CreateAppointmentParams) (Appointment, error) {
+	row := q.db.QueryRow(ctx, createAppointment,
+		arg.DoctorUsername,
+		arg.PatientUsername,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Reason,
+		arg.VisitType,
+		arg.Fee,
+		arg.FollowUpOf,
+		arg.PaymentStatus,
+		arg.PaymentDueBy,
+	)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Status,
+		&i.Reason,
+		&i.CancelledBy,
+		&i.CancellationReason,
+		&i.RescheduledCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PatientCheckedInAt,
+		&i.DoctorCheckedInAt,
+		&i.VisitType,
+		&i.Fee,
+		&i.FollowUpOf,
+		&i.PaymentStatus,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const deleteAppointmentReminders = `-- name: DeleteAppointmentReminders :exec
+DELETE FROM appointment_reminders
+WHERE appointment_id = $1
+`
+
+// A rescheduled appointment is reminded again for its new time
+func (q *Queries) DeleteAppointmentReminders(ctx context.Context, appointmentID int32) error {
+	_, err := q.db.Exec(ctx, deleteAppointmentReminders, appointmentID)
+	return err
+}
+
+const getAppointment = `-- name: GetAppointment :one
+SELECT id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id FROM appointments WHERE id = $1
+`
+
+func (q *Queries) GetAppointment(ctx context.Context, id int32) (Appointment, error) {
+	row := q.db.QueryRow(ctx, getAppointment, id)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Status,
+		&i.Reason,
+		&i.CancelledBy,
+		&i.CancellationReason,
+		&i.RescheduledCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PatientCheckedInAt,
+		&i.DoctorCheckedInAt,
+		&i.VisitType,
+		&i.Fee,
+		&i.FollowUpOf,
+		&i.PaymentStatus,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const getAppointmentFee = `-- name: GetAppointmentFee :one
+SELECT
+    CASE WHEN $1::VARCHAR = 'video' THEN d.video_fee ELSE d.in_person_fee END::NUMERIC AS base_fee,
+    COALESCE(f.id, 0)::INTEGER AS follow_up_of,
+    CASE WHEN f.id IS NULL
+        THEN CASE WHEN $1::VARCHAR = 'video' THEN d.video_fee ELSE d.in_person_fee END
+        ELSE round(CASE WHEN $1::VARCHAR = 'video' THEN d.video_fee ELSE d.in_person_fee END
+            * (100 - d.follow_up_discount) / 100, 2)
+    END::NUMERIC AS fee
+FROM doctors d
+LEFT JOIN LATERAL (
+    SELECT a.id FROM appointments a
+    WHERE a.doctor_username = d.username
+      AND a.patient_username = $2
+      AND a.status = 'completed'
+      AND a.follow_up_of IS NULL
+      AND a.starts_at < $3::TIMESTAMP
+      AND a.starts_at >= $3::TIMESTAMP - make_interval(days => d.follow_up_window_days)
+    ORDER BY a.starts_at DESC
+    LIMIT 1
+) f ON true
+WHERE d.username = $4
+`
+
+type GetAppointmentFeeParams struct {
+	VisitType       string           `json:"visit_type"`
+	PatientUsername string           `json:"patient_username"`
+	StartsAt        pgtype.Timestamp `json:"starts_at"`
+	DoctorUsername  string           `json:"doctor_username"`
+}
+
+type GetAppointmentFeeRow struct {
+	BaseFee    pgtype.Numeric `json:"base_fee"`
+	FollowUpOf int32          `json:"follow_up_of"`
+	Fee        pgtype.Numeric `json:"fee"`
+}
+
+// What the patient pays for a visit of visit_type with the doctor starting
+// at starts_at. It is a follow-up, at the doctor's follow-up discount, when
+// it falls within the doctor's follow-up window of the patient's last
+// completed appointment with them that wasn't itself a follow-up;
+// follow_up_of is that appointment, or 0 when it isn't a follow-up.
+func (q *Queries) GetAppointmentFee(ctx context.Context, arg GetAppointmentFeeParams) (GetAppointmentFeeRow, error) {
+	row := q.db.QueryRow(ctx, getAppointmentFee,
+		arg.VisitType,
+		arg.PatientUsername,
+		arg.StartsAt,
+		arg.DoctorUsername,
+	)
+	var i GetAppointmentFeeRow
+	err := row.Scan(&i.BaseFee, &i.FollowUpOf, &i.Fee)
+	return i, err
+}
+
+const listDoctorBookedAppointments = `-- name: ListDoctorBookedAppointments :many
+SELECT id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id FROM appointments
+WHERE doctor_username = $1
+  AND status = 'booked'
+  AND starts_at < $2
+  AND ends_at > $3
+ORDER BY starts_at
+`
+
+type ListDoctorBookedAppointmentsParams struct {
+	DoctorUsername string           `json:"doctor_username"`
+	ToTime         pgtype.Timestamp `json:"to_time"`
+	FromTime       pgtype.Timestamp `json:"from_time"`
+}
+
+// The doctor's booked appointments overlapping from_time to to_time
+func (q *Queries) ListDoctorBookedAppointments(ctx context.Context, arg ListDoctorBookedAppointmentsParams) ([]Appointment, error) {
+	rows, err := q.db.Query(ctx, listDoctorBookedAppointments, arg.DoctorUsername, arg.ToTime, arg.FromTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Appointment{}
+	for rows.Next() {
+		var i Appointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.PatientUsername,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Status,
+			&i.Reason,
+			&i.CancelledBy,
+			&i.CancellationReason,
+			&i.RescheduledCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PatientCheckedInAt,
+			&i.DoctorCheckedInAt,
+			&i.VisitType,
+			&i.Fee,
+			&i.FollowUpOf,
+			&i.PaymentStatus,
+			&i.PaymentDueBy,
+			&i.PaidPaymentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDoctorDayAppointments = `-- name: ListDoctorDayAppointments :many
+SELECT a.id, a.doctor_username, a.patient_username, a.starts_at, a.ends_at, a.status, a.reason, a.cancelled_by, a.cancellation_reason, a.rescheduled_count, a.created_at, a.updated_at, a.patient_checked_in_at, a.doctor_checked_in_at, a.visit_type, a.fee, a.follow_up_of, a.payment_status, a.payment_due_by, a.paid_payment_id, p.full_name AS patient_name, p.mobile_number AS patient_mobile_number,
+    p.gender AS patient_gender, p.age AS patient_age
+FROM appointments a
+JOIN patients p ON p.username = a.patient_username
+WHERE a.doctor_username = $1
+  AND a.starts_at >= $2
+  AND a.starts_at < $3
+ORDER BY a.starts_at, a.id
+`
+
+type ListDoctorDayAppointmentsParams struct {
+	DoctorUsername string           `json:"doctor_username"`
+	FromTime       pgtype.Timestamp `json:"from_time"`
+	ToTime         pgtype.Timestamp `json:"to_time"`
+}
+
+type ListDoctorDayAppointmentsRow struct {
+	ID                  int32            `json:"id"`
+	DoctorUsername      string           `json:"doctor_username"`
+	PatientUsername     string           `json:"patient_username"`
+	StartsAt            pgtype.Timestamp `json:"starts_at"`
+	EndsAt              pgtype.Timestamp `json:"ends_at"`
+	Status              string           `json:"status"`
+	Reason              string           `json:"reason"`
+	CancelledBy         pgtype.Text      `json:"cancelled_by"`
+	CancellationReason  pgtype.Text      `json:"cancellation_reason"`
+	RescheduledCount    int32            `json:"rescheduled_count"`
+	CreatedAt           pgtype.Timestamp `json:"created_at"`
+	UpdatedAt           pgtype.Timestamp `json:"updated_at"`
+	PatientCheckedInAt  pgtype.Timestamp `json:"patient_checked_in_at"`
+	DoctorCheckedInAt   pgtype.Timestamp `json:"doctor_checked_in_at"`
+	VisitType           string           `json:"visit_type"`
+	Fee                 pgtype.Numeric   `json:"fee"`
+	FollowUpOf          pgtype.Int4      `json:"follow_up_of"`
+	PaymentStatus       string           `json:"payment_status"`
+	PaymentDueBy        pgtype.Timestamp `json:"payment_due_by"`
+	PaidPaymentID       pgtype.Int4      `json:"paid_payment_id"`
+	PatientName         string           `json:"patient_name"`
+	PatientMobileNumber string           `json:"patient_mobile_number"`
+	PatientGender       string           `json:"patient_gender"`
+	PatientAge          int32            `json:"patient_age"`
+}
+
+// Every appointment of the doctor starting from from_time and before
+// to_time, with the patient's details
+func (q *Queries) ListDoctorDayAppointments(ctx context.Context, arg ListDoctorDayAppointmentsParams) ([]ListDoctorDayAppointmentsRow, error) {
+	rows, err := q.db.Query(ctx, listDoctorDayAppointments, arg.DoctorUsername, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDoctorDayAppointmentsRow{}
+	for rows.Next() {
+		var i ListDoctorDayAppointmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.PatientUsername,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Status,
+			&i.Reason,
+			&i.CancelledBy,
+			&i.CancellationReason,
+			&i.RescheduledCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PatientCheckedInAt,
+			&i.DoctorCheckedInAt,
+			&i.VisitType,
+			&i.Fee,
+			&i.FollowUpOf,
+			&i.PaymentStatus,
+			&i.PaymentDueBy,
+			&i.PaidPaymentID,
+			&i.PatientName,
+			&i.PatientMobileNumber,
+			&i.PatientGender,
+			&i.PatientAge,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueAppointmentReminders = `-- name: ListDueAppointmentReminders :many
+SELECT a.id, a.doctor_username, a.patient_username, a.starts_at, a.ends_at, a.status, a.reason, a.cancelled_by, a.cancellation_reason, a.rescheduled_count, a.created_at, a.updated_at, a.patient_checked_in_at, a.doctor_checked_in_at, a.visit_type, a.fee, a.follow_up_of, a.payment_status, a.payment_due_by, a.paid_payment_id FROM appointments a
+WHERE a.status = 'booked' AND a.payment_status <> 'pending'
+  AND a.starts_at > $1::TIMESTAMP
+  AND a.starts_at <= $1::TIMESTAMP + make_interval(mins => $2::INT)
+  AND a.updated_at < a.starts_at - make_interval(mins => $2::INT)
+  AND NOT EXISTS (
+      SELECT 1 FROM appointment_reminders r
+      WHERE r.appointment_id = a.id AND r.kind = $3
+  )
+ORDER BY a.starts_at
+`
+
+type ListDueAppointmentRemindersParams struct {
+	Now         pgtype.Timestamp `json:"now"`
+	LeadMinutes int32            `json:"lead_minutes"`
+	Kind        string           `json:"kind"`
+}
+
+// Booked appointments starting within lead_minutes that haven't had this
+// reminder yet. Appointments booked, paid for or moved inside the lead
+// time are skipped, the confirmation having just gone out, and so are
+// those still waiting for payment.
+func (q *Queries) ListDueAppointmentReminders(ctx context.Context, arg ListDueAppointmentRemindersParams) ([]Appointment, error) {
+	rows, err := q.db.Query(ctx, listDueAppointmentReminders, arg.Now, arg.LeadMinutes, arg.Kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Appointment{}
+	for rows.Next() {
+		var i Appointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.PatientUsername,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Status,
+			&i.Reason,
+			&i.CancelledBy,
+			&i.CancellationReason,
+			&i.RescheduledCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PatientCheckedInAt,
+			&i.DoctorCheckedInAt,
+			&i.VisitType,
+			&i.Fee,
+			&i.FollowUpOf,
+			&i.PaymentStatus,
+			&i.PaymentDueBy,
+			&i.PaidPaymentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPatientAppointments = `-- name: ListPatientAppointments :many
+SELECT a.id, a.doctor_username, a.patient_username, a.starts_at, a.ends_at, a.status, a.reason, a.cancelled_by, a.cancellation_reason, a.rescheduled_count, a.created_at, a.updated_at, a.patient_checked_in_at, a.doctor_checked_in_at, a.visit_type, a.fee, a.follow_up_of, a.payment_status, a.payment_due_by, a.paid_payment_id, d.full_name AS doctor_name, d.specialization, d.hospital_name
+FROM appointments a
+JOIN doctors d ON d.username = a.doctor_username
+WHERE a.patient_username = $1
+  AND ($2::VARCHAR IS NULL OR a.status = $2)
+  AND (NOT $3::BOOLEAN OR a.starts_at >= now())
+ORDER BY CASE WHEN $3::BOOLEAN THEN a.starts_at END ASC,
+    a.starts_at DESC, a.id DESC
+LIMIT $5 OFFSET $4
+`
+
+type ListPatientAppointmentsParams struct {
+	PatientUsername string      `json:"patient_username"`
+	Status          pgtype.Text `json:"status"`
+	Upcoming        bool        `json:"upcoming"`
+	Offset          int32       `json:"offset"`
+	Limit           int32       `json:"limit"`
+}
+
+type ListPatientAppointmentsRow struct {
+	ID                 int32            `json:"id"`
+	DoctorUsername     string           `json:"doctor_username"`
+	PatientUsername    string           `json:"patient_username"`
+	StartsAt           pgtype.Timestamp `json:"starts_at"`
+	EndsAt             pgtype.Timestamp `json:"ends_at"`
+	Status             string           `json:"status"`
+	Reason             string           `json:"reason"`
+	CancelledBy        pgtype.Text      `json:"cancelled_by"`
+	CancellationReason pgtype.Text      `json:"cancellation_reason"`
+	RescheduledCount   int32            `json:"rescheduled_count"`
+	CreatedAt          pgtype.Timestamp `json:"created_at"`
+	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
+	PatientCheckedInAt pgtype.Timestamp `json:"patient_checked_in_at"`
+	DoctorCheckedInAt  pgtype.Timestamp `json:"doctor_checked_in_at"`
+	VisitType          string           `json:"visit_type"`
+	Fee                pgtype.Numeric   `json:"fee"`
+	FollowUpOf         pgtype.Int4      `json:"follow_up_of"`
+	PaymentStatus      string           `json:"payment_status"`
+	PaymentDueBy       pgtype.Timestamp `json:"payment_due_by"`
+	PaidPaymentID      pgtype.Int4      `json:"paid_payment_id"`
+	DoctorName         string           `json:"doctor_name"`
+	Specialization     string           `json:"specialization"`
+	HospitalName       pgtype.Text      `json:"hospital_name"`
+}
+
+// Upcoming appointments come soonest first, the full history newest first
+func (q *Queries) ListPatientAppointments(ctx context.Context, arg ListPatientAppointmentsParams) ([]ListPatientAppointmentsRow, error) {
+	rows, err := q.db.Query(ctx, listPatientAppointments,
+		arg.PatientUsername,
+		arg.Status,
+		arg.Upcoming,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPatientAppointmentsRow{}
+	for rows.Next() {
+		var i ListPatientAppointmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.PatientUsername,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Status,
+			&i.Reason,
+			&i.CancelledBy,
+			&i.CancellationReason,
+			&i.RescheduledCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PatientCheckedInAt,
+			&i.DoctorCheckedInAt,
+			&i.VisitType,
+			&i.Fee,
+			&i.FollowUpOf,
+			&i.PaymentStatus,
+			&i.PaymentDueBy,
+			&i.PaidPaymentID,
+			&i.DoctorName,
+			&i.Specialization,
+			&i.HospitalName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAppointmentPaid = `-- name: MarkAppointmentPaid :one
+UPDATE appointments SET
+    payment_status = 'paid',
+    paid_payment_id = $1,
+    payment_due_by = NULL,
+    updated_at = now()
+WHERE id = $2
+  AND status = 'booked'
+  AND payment_status = 'pending'
+  AND payment_due_by >= $3::TIMESTAMP
+RETURNING id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id
+`
+
+type MarkAppointmentPaidParams struct {
+	PaymentID pgtype.Int4      `json:"payment_id"`
+	ID        int32            `json:"id"`
+	Now       pgtype.Timestamp `json:"now"`
+}
+
+// Confirms an appointment waiting for payment, as long as its hold on the
+// slot hasn't run out at now
+func (q *Queries) MarkAppointmentPaid(ctx context.Context, arg MarkAppointmentPaidParams) (Appointment, error) {
+	row := q.db.QueryRow(ctx, markAppointmentPaid, arg.PaymentID, arg.ID, arg.Now)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Status,
+		&i.Reason,
+		&i.CancelledBy,
+		&i.CancellationReason,
+		&i.RescheduledCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PatientCheckedInAt,
+		&i.DoctorCheckedInAt,
+		&i.VisitType,
+		&i.Fee,
+		&i.FollowUpOf,
+		&i.PaymentStatus,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const markAppointmentRefunded = `-- name: MarkAppointmentRefunded :one
+UPDATE appointments SET
+    payment_status = 'refunded',
+    updated_at = now()
+WHERE id = $1 AND payment_status = 'paid'
+RETURNING id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id
+`
+
+func (q *Queries) MarkAppointmentRefunded(ctx context.Context, id int32) (Appointment, error) {
+	row := q.db.QueryRow(ctx, markAppointmentRefunded, id)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Status,
+		&i.Reason,
+		&i.CancelledBy,
+		&i.CancellationReason,
+		&i.RescheduledCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PatientCheckedInAt,
+		&i.DoctorCheckedInAt,
+		&i.VisitType,
+		&i.Fee,
+		&i.FollowUpOf,
+		&i.PaymentStatus,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const releaseAppointmentReminder = `-- name: ReleaseAppointmentReminder :exec
+DELETE FROM appointment_reminders
+WHERE appointment_id = $1 AND kind = $2
+`
+
+type ReleaseAppointmentReminderParams struct {
+	AppointmentID int32  `json:"appointment_id"`
+	Kind          string `json:"kind"`
+}
+
+// Gives back a claimed reminder that couldn't be sent, so it is retried
+func (q *Queries) ReleaseAppointmentReminder(ctx context.Context, arg ReleaseAppointmentReminderParams) error {
+	_, err := q.db.Exec(ctx, releaseAppointmentReminder, arg.AppointmentID, arg.Kind)
+	return err
+}
+
+const releaseUnpaidAppointments = `-- name: ReleaseUnpaidAppointments :many
+UPDATE appointments SET
+    status = 'cancelled',
+    payment_status = 'unpaid',
+    cancellation_reason = 'payment not received in time',
+    updated_at = now()
+WHERE status = 'booked'
+  AND payment_status = 'pending'
+  AND payment_due_by < $1::TIMESTAMP
+RETURNING id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id
+`
+
+// Cancels appointments whose payment didn't arrive by payment_due_by,
+// freeing their slots
+func (q *Queries) ReleaseUnpaidAppointments(ctx context.Context, now pgtype.Timestamp) ([]Appointment, error) {
+	rows, err := q.db.Query(ctx, releaseUnpaidAppointments, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Appointment{}
+	for rows.Next() {
+		var i Appointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.PatientUsername,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Status,
+			&i.Reason,
+			&i.CancelledBy,
+			&i.CancellationReason,
+			&i.RescheduledCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PatientCheckedInAt,
+			&i.DoctorCheckedInAt,
+			&i.VisitType,
+			&i.Fee,
+			&i.FollowUpOf,
+			&i.PaymentStatus,
+			&i.PaymentDueBy,
+			&i.PaidPaymentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rescheduleAppointment = `-- name: RescheduleAppointment :one
+UPDATE appointments SET
+    starts_at = $1,
+    ends_at = $2,
+    rescheduled_count = rescheduled_count + 1,
+    updated_at = now()
+WHERE id = $3 AND status = 'booked'
+RETURNING id, doctor_username, patient_username, starts_at, ends_at, status, reason, cancelled_by, cancellation_reason, rescheduled_count, created_at, updated_at, patient_checked_in_at, doctor_checked_in_at, visit_type, fee, follow_up_of, payment_status, payment_due_by, paid_payment_id
+`
+
+type RescheduleAppointmentParams struct {
+	StartsAt pgtype.Timestamp `json:"starts_at"`
+	EndsAt   pgtype.Timestamp `json:"ends_at"`
+	ID       int32            `json:"id"`
+}
+
+func (q *Queries) RescheduleAppointment(ctx context.Context, arg RescheduleAppointmentParams) (Appointment, error) {
+	row := q.db.QueryRow(ctx, rescheduleAppointment, arg.StartsAt, arg.EndsAt, arg.ID)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Status,
+		&i.Reason,
+		&i.CancelledBy,
+		&i.CancellationReason,
+		&i.RescheduledCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PatientCheckedInAt,
+		&i.DoctorCheckedInAt,
+		&i.VisitType,
+		&i.Fee,
+		&i.FollowUpOf,
+		&i.PaymentStatus,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
@@ -0,0 +1,340 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: consultation.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const closeConsultation = `-- name: CloseConsultation :one
+UPDATE consultations SET
+    status = 'closed',
+    closed_at = now(),
+    closed_by = $1,
+    transcript = (
+        SELECT COALESCE(jsonb_agg(jsonb_build_object(
+            'id', m.id,
+            'sender_username', m.sender_username,
+            'body', m.body,
+            'attachment', CASE WHEN a.id IS NULL THEN NULL ELSE jsonb_build_object(
+                'id', a.id,
+                'file_name', a.file_name,
+                'content_type', a.content_type,
+                'size_bytes', a.size_bytes
+            ) END,
+            'created_at', m.created_at,
+            'read_at', m.read_at
+        ) ORDER BY m.id), '[]'::JSONB)
+        FROM consultation_messages m
+        LEFT JOIN consultation_attachments a ON a.id = m.attachment_id
+        WHERE m.consultation_id = consultations.id
+    )
+WHERE consultations.id = $2 AND consultations.status = 'open'
+RETURNING id, appointment_id, doctor_username, patient_username, status, transcript, created_at, closed_at, closed_by
+`
+
+type CloseConsultationParams struct {
+	ClosedBy pgtype.Text `json:"closed_by"`
+	ID       int32       `json:"id"`
+}
+
+// Archives the messages into the transcript, oldest first
+func (q *Queries) CloseConsultation(ctx context.Context, arg CloseConsultationParams) (Consultation, error) {
+	row := q.db.QueryRow(ctx, closeConsultation, arg.ClosedBy, arg.ID)
+	var i Consultation
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Status,
+		&i.Transcript,
+		&i.CreatedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+	)
+	return i, err
+}
+
+const createConsultation = `-- name: CreateConsultation :one
+INSERT INTO consultations (
+    appointment_id, doctor_username, patient_username
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (appointment_id) DO UPDATE SET appointment_id = EXCLUDED.appointment_id
+RETURNING id, appointment_id, doctor_username, patient_username, status, transcript, created_at, closed_at, closed_by
+`
+
+type CreateConsultationParams struct {
+	AppointmentID   int32  `json:"appointment_id"`
+	DoctorUsername  string `json:"doctor_username"`
+	PatientUsername string `json:"patient_username"`
+}
+
+// Starting a consultation that already exists returns the existing one
+func (q *Queries) CreateConsultation(ctx context.Context, arg CreateConsultationParams) (Consultation, error) {
+	row := q.db.QueryRow(ctx, createConsultation, arg.AppointmentID, arg.DoctorUsername, arg.PatientUsername)
+	var i Consultation
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Status,
+		&i.Transcript,
+		&i.CreatedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+	)
+	return i, err
+}
+
+const createConsultationAttachment = `-- name: CreateConsultationAttachment :one
+INSERT INTO consultation_attachments (
+    consultation_id, uploaded_by, file_name, content_type, size_bytes, data
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, consultation_id, uploaded_by, file_name, content_type, size_bytes, data, created_at
+`
+
+type CreateConsultationAttachmentParams struct {
+	ConsultationID int32  `json:"consultation_id"`
+	UploadedBy     string `json:"uploaded_by"`
+	FileName       string `json:"file_name"`
+	ContentType    string `json:"content_type"`
+	SizeBytes      int32  `json:"size_bytes"`
+	Data           []byte `json:"data"`
+}
+
+func (q *Queries) CreateConsultationAttachment(ctx context.Context, arg CreateConsultationAttachmentParams) (ConsultationAttachment, error) {
+	row := q.db.QueryRow(ctx, createConsultationAttachment,
+		arg.ConsultationID,
+		arg.UploadedBy,
+		arg.FileName,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.Data,
+	)
+	var i ConsultationAttachment
+	err := row.Scan(
+		&i.ID,
+		&i.ConsultationID,
+		&i.UploadedBy,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Data,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createConsultationMessage = `-- name: CreateConsultationMessage :one
+INSERT INTO consultation_messages (
+    consultation_id, sender_username, body, attachment_id
+)
+SELECT c.id, $1, $2, $3
+FROM consultations c
+WHERE c.id = $4 AND c.status = 'open'
+FOR SHARE
+RETURNING id, consultation_id, sender_username, body, attachment_id, created_at, read_at
+`
+
+type CreateConsultationMessageParams struct {
+	SenderUsername string      `json:"sender_username"`
+	Body           string      `json:"body"`
+	AttachmentID   pgtype.Int4 `json:"attachment_id"`
+	ConsultationID int32       `json:"consultation_id"`
+}
+
+// Nothing is inserted once the consultation is closed. The row lock makes
+// a message sent while the consultation is being closed wait for the close
+// and then be refused, so it can't slip past the archived transcript.
+func (q *Queries) CreateConsultationMessage(ctx context.Context, arg CreateConsultationMessageParams) (ConsultationMessage, error) {
+	row := q.db.QueryRow(ctx, createConsultationMessage,
+		arg.SenderUsername,
+		arg.Body,
+		arg.AttachmentID,
+		arg.ConsultationID,
+	)
+	var i ConsultationMessage
+	err := row.Scan(
+		&i.ID,
+		&i.ConsultationID,
+		&i.SenderUsername,
+		&i.Body,
+		&i.AttachmentID,
+		&i.CreatedAt,
+		&i.ReadAt,
+	)
+	return i, err
+}
+
+const deleteConsultationMessages = `-- name: DeleteConsultationMessages :exec
+DELETE FROM consultation_messages WHERE consultation_id = $1
+`
+
+func (q *Queries) DeleteConsultationMessages(ctx context.Context, consultationID int32) error {
+	_, err := q.db.Exec(ctx, deleteConsultationMessages, consultationID)
+	return err
+}
+
+const getConsultation = `-- name: GetConsultation :one
+SELECT id, appointment_id, doctor_username, patient_username, status, transcript, created_at, closed_at, closed_by FROM consultations WHERE id = $1
+`
+
+func (q *Queries) GetConsultation(ctx context.Context, id int32) (Consultation, error) {
+	row := q.db.QueryRow(ctx, getConsultation, id)
+	var i Consultation
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Status,
+		&i.Transcript,
+		&i.CreatedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+	)
+	return i, err
+}
+
+const getConsultationAttachment = `-- name: GetConsultationAttachment :one
+SELECT id, consultation_id, uploaded_by, file_name, content_type, size_bytes, data, created_at FROM consultation_attachments
+WHERE id = $1 AND consultation_id = $2
+`
+
+type GetConsultationAttachmentParams struct {
+	ID             int32 `json:"id"`
+	ConsultationID int32 `json:"consultation_id"`
+}
+
+func (q *Queries) GetConsultationAttachment(ctx context.Context, arg GetConsultationAttachmentParams) (ConsultationAttachment, error) {
+	row := q.db.QueryRow(ctx, getConsultationAttachment, arg.ID, arg.ConsultationID)
+	var i ConsultationAttachment
+	err := row.Scan(
+		&i.ID,
+		&i.ConsultationID,
+		&i.UploadedBy,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Data,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getConsultationByAppointment = `-- name: GetConsultationByAppointment :one
+SELECT id, appointment_id, doctor_username, patient_username, status, transcript, created_at, closed_at, closed_by FROM consultations WHERE appointment_id = $1
+`
+
+func (q *Queries) GetConsultationByAppointment(ctx context.Context, appointmentID int32) (Consultation, error) {
+	row := q.db.QueryRow(ctx, getConsultationByAppointment, appointmentID)
+	var i Consultation
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Status,
+		&i.Transcript,
+		&i.CreatedAt,
+		&i.ClosedAt,
+		&i.ClosedBy,
+	)
+	return i, err
+}
+
+const listConsultationMessages = `-- name: ListConsultationMessages :many
+SELECT m.id, m.consultation_id, m.sender_username, m.body, m.attachment_id, m.created_at, m.read_at, a.file_name AS attachment_file_name, a.content_type AS attachment_content_type,
+    a.size_bytes AS attachment_size_bytes
+FROM consultation_messages m
+LEFT JOIN consultation_attachments a ON a.id = m.attachment_id
+WHERE m.consultation_id = $1
+  AND ($2::INTEGER IS NULL OR m.id < $2)
+ORDER BY m.id DESC
+LIMIT $3
+`
+
+type ListConsultationMessagesParams struct {
+	ConsultationID int32       `json:"consultation_id"`
+	BeforeID       pgtype.Int4 `json:"before_id"`
+	LimitCount     int32       `json:"limit_count"`
+}
+
+type ListConsultationMessagesRow struct {
+	ID                    int32            `json:"id"`
+	ConsultationID        int32            `json:"consultation_id"`
+	SenderUsername        string           `json:"sender_username"`
+	Body                  string           `json:"body"`
+	AttachmentID          pgtype.Int4      `json:"attachment_id"`
+	CreatedAt             pgtype.Timestamp `json:"created_at"`
+	ReadAt                pgtype.Timestamp `json:"read_at"`
+	AttachmentFileName    pgtype.Text      `json:"attachment_file_name"`
+	AttachmentContentType pgtype.Text      `json:"attachment_content_type"`
+	AttachmentSizeBytes   pgtype.Int4      `json:"attachment_size_bytes"`
+}
+
+// Newest first, paging back with before_id
+func (q *Queries) ListConsultationMessages(ctx context.Context, arg ListConsultationMessagesParams) ([]ListConsultationMessagesRow, error) {
+	rows, err := q.db.Query(ctx, listConsultationMessages, arg.ConsultationID, arg.BeforeID, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListConsultationMessagesRow{}
+	for rows.Next() {
+		var i ListConsultationMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ConsultationID,
+			&i.SenderUsername,
+			&i.Body,
+			&i.AttachmentID,
+			&i.CreatedAt,
+			&i.ReadAt,
+			&i.AttachmentFileName,
+			&i.AttachmentContentType,
+			&i.AttachmentSizeBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markConsultationMessagesRead = `-- name: MarkConsultationMessagesRead :execrows
+UPDATE consultation_messages SET read_at = now()
+WHERE consultation_id = $1
+  AND sender_username <> $2
+  AND id <= $3
+  AND read_at IS NULL
+`
+
+type MarkConsultationMessagesReadParams struct {
+	ConsultationID int32  `json:"consultation_id"`
+	ReaderUsername string `json:"reader_username"`
+	UpToID         int32  `json:"up_to_id"`
+}
+
+// Marks the other side's messages up to and including up_to_id as read
+func (q *Queries) MarkConsultationMessagesRead(ctx context.Context, arg MarkConsultationMessagesReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markConsultationMessagesRead, arg.ConsultationID, arg.ReaderUsername, arg.UpToID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -1,220 +1,440 @@
-// Code generated by sqlc. DO NOT EDIT.
-// versions:
-//   sqlc v1.29.0
-// source: doctor.sql
-
-package db
-
-import (
-	"context"
-
-	"github.com/jackc/pgx/v5/pgtype"
-)
-
-const createDoctor = `-- name: CreateDoctor :one
-INSERT INTO doctors (
-  username, full_name, mobile_number, gender, age,
-  specialization, email, password,
-  registration_number, hospital_name, years_experience
-) VALUES (
-  $1, $2, $3, $4, $5,
-  $6, $7, $8,
-  $9, $10, $11
-) RETURNING username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at
-`
-
-type CreateDoctorParams struct {
-	Username           string      `json:"username"`
-	FullName           string      `json:"full_name"`
-	MobileNumber       string      `json:"mobile_number"`
-	Gender             string      `json:"gender"`
-	Age                int32       `json:"age"`
-	Specialization     string      `json:"specialization"`
-	Email              string      `json:"email"`
-	Password           string      `json:"password"`
-	RegistrationNumber string      `json:"registration_number"`
-	HospitalName       pgtype.Text `json:"hospital_name"`
-	YearsExperience    int32       `json:"years_experience"`
-}
-
-func (q *Queries) CreateDoctor(ctx context.Context, arg CreateDoctorParams) (Doctor, error) {
-	row := q.db.QueryRow(ctx, createDoctor,
-		arg.Username,
-		arg.FullName,
-		arg.MobileNumber,
-		arg.Gender,
-		arg.Age,
-		arg.Specialization,
-		arg.Email,
-		arg.Password,
-		arg.RegistrationNumber,
-		arg.HospitalName,
-		arg.YearsExperience,
-	)
-	var i Doctor
-	err := row.Scan(
-		&i.Username,
-		&i.FullName,
-		&i.MobileNumber,
-		&i.Gender,
-		&i.Age,
-		&i.Specialization,
-		&i.Email,
-		&i.Password,
-		&i.RegistrationNumber,
-		&i.HospitalName,
-		&i.YearsExperience,
-		&i.PasswordChangedAt,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const deleteDoctor = `-- name: DeleteDoctor :one
-DELETE FROM doctors WHERE username = $1
-RETURNING username
-`
-
-func (q *Queries) DeleteDoctor(ctx context.Context, username string) (string, error) {
-	row := q.db.QueryRow(ctx, deleteDoctor, username)
-	err := row.Scan(&username)
-	return username, err
-}
-
-const getDoctorByName = `-- name: GetDoctorByName :one
-SELECT username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at FROM doctors WHERE username = $1
-`
-
-func (q *Queries) GetDoctorByName(ctx context.Context, username string) (Doctor, error) {
-	row := q.db.QueryRow(ctx, getDoctorByName, username)
-	var i Doctor
-	err := row.Scan(
-		&i.Username,
-		&i.FullName,
-		&i.MobileNumber,
-		&i.Gender,
-		&i.Age,
-		&i.Specialization,
-		&i.Email,
-		&i.Password,
-		&i.RegistrationNumber,
-		&i.HospitalName,
-		&i.YearsExperience,
-		&i.PasswordChangedAt,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const listDoctorsBySpecialization = `-- name: ListDoctorsBySpecialization :many
-SELECT username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at FROM doctors
-WHERE specialization = $1
-ORDER BY years_experience DESC
-LIMIT $2 OFFSET $3
-`
-
-type ListDoctorsBySpecializationParams struct {
-	Specialization string `json:"specialization"`
-	Limit          int32  `json:"limit"`
-	Offset         int32  `json:"offset"`
-}
-
-func (q *Queries) ListDoctorsBySpecialization(ctx context.Context, arg ListDoctorsBySpecializationParams) ([]Doctor, error) {
-	rows, err := q.db.Query(ctx, listDoctorsBySpecialization, arg.Specialization, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Doctor{}
-	for rows.Next() {
-		var i Doctor
-		if err := rows.Scan(
-			&i.Username,
-			&i.FullName,
-			&i.MobileNumber,
-			&i.Gender,
-			&i.Age,
-			&i.Specialization,
-			&i.Email,
-			&i.Password,
-			&i.RegistrationNumber,
-			&i.HospitalName,
-			&i.YearsExperience,
-			&i.PasswordChangedAt,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const updateDoctor = `-- name: UpdateDoctor :one
-UPDATE doctors SET
-  full_name = COALESCE($1, full_name),
-  mobile_number = COALESCE($2, mobile_number),
-  gender = COALESCE($3, gender),
-  age = COALESCE($4, age),
-  specialization = COALESCE($5, specialization),
-  email = COALESCE($6, email),
-  password = COALESCE($7, password),
-  registration_number = COALESCE($8, registration_number),
-  hospital_name = COALESCE($9, hospital_name),
-  years_experience = COALESCE($10, years_experience),
-  password_changed_at = COALESCE($11, password_changed_at)
-WHERE username = $12
-RETURNING username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at
-`
-
-type UpdateDoctorParams struct {
-	FullName           pgtype.Text      `json:"full_name"`
-	MobileNumber       pgtype.Text      `json:"mobile_number"`
-	Gender             pgtype.Text      `json:"gender"`
-	Age                pgtype.Int4      `json:"age"`
-	Specialization     pgtype.Text      `json:"specialization"`
-	Email              pgtype.Text      `json:"email"`
-	Password           pgtype.Text      `json:"password"`
-	RegistrationNumber pgtype.Text      `json:"registration_number"`
-	HospitalName       pgtype.Text      `json:"hospital_name"`
-	YearsExperience    pgtype.Int4      `json:"years_experience"`
-	PasswordChangedAt  pgtype.Timestamp `json:"password_changed_at"`
-	Username           string           `json:"username"`
-}
-
-func (q *Queries) UpdateDoctor(ctx context.Context, arg UpdateDoctorParams) (Doctor, error) {
-	row := q.db.QueryRow(ctx, updateDoctor,
-		arg.FullName,
-		arg.MobileNumber,
-		arg.Gender,
-		arg.Age,
-		arg.Specialization,
-		arg.Email,
-		arg.Password,
-		arg.RegistrationNumber,
-		arg.HospitalName,
-		arg.YearsExperience,
-		arg.PasswordChangedAt,
-		arg.Username,
-	)
-	var i Doctor
-	err := row.Scan(
-		&i.Username,
-		&i.FullName,
-		&i.MobileNumber,
-		&i.Gender,
-		&i.Age,
-		&i.Specialization,
-		&i.Email,
-		&i.Password,
-		&i.RegistrationNumber,
-		&i.HospitalName,
-		&i.YearsExperience,
-		&i.PasswordChangedAt,
-		&i.CreatedAt,
-	)
-	return i, err
-}
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: doctor.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createDoctor = `-- name: CreateDoctor :one
+INSERT INTO doctors (
+  username, full_name, mobile_number, gender, age,
+  specialization, email, password,
+  registration_number, hospital_name, years_experience, languages
+) VALUES (
+  $1, $2, $3, $4, $5,
+  $6, $7, $8,
+  $9, $10, $11, $12
+) RETURNING username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at, cancellation_window_hours, rating_average, review_count, languages, in_person_fee, video_fee, follow_up_window_days, follow_up_discount, refund_window_hours
+`
+
+type CreateDoctorParams struct {
+	Username           string      `json:"username"`
+	FullName           string      `json:"full_name"`
+	MobileNumber       string      `json:"mobile_number"`
+	Gender             string      `json:"gender"`
+	Age                int32       `json:"age"`
+	Specialization     string      `json:"specialization"`
+	Email              string      `json:"email"`
+	Password           string      `json:"password"`
+	RegistrationNumber string      `json:"registration_number"`
+	HospitalName       pgtype.Text `json:"hospital_name"`
+	YearsExperience    int32       `json:"years_experience"`
+	Languages          []string    `json:"languages"`
+}
+
+func (q *Queries) CreateDoctor(ctx context.Context, arg CreateDoctorParams) (Doctor, error) {
+	row := q.db.QueryRow(ctx, createDoctor,
+		arg.Username,
+		arg.FullName,
+		arg.MobileNumber,
+		arg.Gender,
+		arg.Age,
+		arg.Specialization,
+		arg.Email,
+		arg.Password,
+		arg.RegistrationNumber,
+		arg.HospitalName,
+		arg.YearsExperience,
+		arg.Languages,
+	)
+	var i Doctor
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.MobileNumber,
+		&i.Gender,
+		&i.Age,
+		&i.Specialization,
+		&i.Email,
+		&i.Password,
+		&i.RegistrationNumber,
+		&i.HospitalName,
+		&i.YearsExperience,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.CancellationWindowHours,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.Languages,
+		&i.InPersonFee,
+		&i.VideoFee,
+		&i.FollowUpWindowDays,
+		&i.FollowUpDiscount,
+		&i.RefundWindowHours,
+	)
+	return i, err
+}
+
+const deleteDoctor = `-- name: DeleteDoctor :one
+DELETE FROM doctors WHERE username = $1
+RETURNING username
+`
+
+func (q *Queries) DeleteDoctor(ctx context.Context, username string) (string, error) {
+	row := q.db.QueryRow(ctx, deleteDoctor, username)
+	err := row.Scan(&username)
+	return username, err
+}
+
+const getDoctorByName = `-- name: GetDoctorByName :one
+SELECT username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at, cancellation_window_hours, rating_average, review_count, languages, in_person_fee, video_fee, follow_up_window_days, follow_up_discount, refund_window_hours FROM doctors WHERE username = $1
+`
+
+func (q *Queries) GetDoctorByName(ctx context.Context, username string) (Doctor, error) {
+	row := q.db.QueryRow(ctx, getDoctorByName, username)
+	var i Doctor
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.MobileNumber,
+		&i.Gender,
+		&i.Age,
+		&i.Specialization,
+		&i.Email,
+		&i.Password,
+		&i.RegistrationNumber,
+		&i.HospitalName,
+		&i.YearsExperience,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.CancellationWindowHours,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.Languages,
+		&i.InPersonFee,
+		&i.VideoFee,
+		&i.FollowUpWindowDays,
+		&i.FollowUpDiscount,
+		&i.RefundWindowHours,
+	)
+	return i, err
+}
+
+const searchDoctors = `-- name: SearchDoctors :many
+SELECT username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at, cancellation_window_hours, rating_average, review_count, languages, in_person_fee, video_fee, follow_up_window_days, follow_up_discount, refund_window_hours, COUNT(*) OVER () AS total_count FROM doctors
+WHERE ($1::VARCHAR IS NULL
+       OR lower(specialization) LIKE '%' || $1 || '%'
+       OR similarity(lower(specialization), $1) >= 0.5)
+  AND ($2::VARCHAR IS NULL OR hospital_name ILIKE '%' || $2 || '%')
+  AND ($3::INTEGER IS NULL OR years_experience >= $3)
+  AND ($4::INTEGER IS NULL OR years_experience <= $4)
+  AND ($5::VARCHAR IS NULL OR gender = $5)
+  AND ($6::VARCHAR IS NULL OR $6 = ANY(languages))
+  AND ($7::NUMERIC IS NULL OR rating_average >= $7)
+  AND ($8::NUMERIC IS NULL
+       OR ($9::VARCHAR IS DISTINCT FROM 'video' AND in_person_fee <= $8)
+       OR ($9::VARCHAR IS DISTINCT FROM 'in_person' AND video_fee <= $8))
+  AND (NOT $10::BOOLEAN
+       OR EXISTS (SELECT 1 FROM doctor_availability a WHERE a.doctor_username = doctors.username)
+       OR EXISTS (
+           SELECT 1 FROM doctor_availability_exceptions e
+           WHERE e.doctor_username = doctors.username AND e.available AND e.date >= CURRENT_DATE
+       ))
+ORDER BY
+  CASE WHEN $11::VARCHAR = 'rating' THEN rating_average END DESC NULLS LAST,
+  CASE WHEN $11::VARCHAR = 'rating' THEN review_count END DESC,
+  years_experience DESC,
+  username
+LIMIT $13 OFFSET $12
+`
+
+type SearchDoctorsParams struct {
+	Specialization pgtype.Text    `json:"specialization"`
+	Hospital       pgtype.Text    `json:"hospital"`
+	MinExperience  pgtype.Int4    `json:"min_experience"`
+	MaxExperience  pgtype.Int4    `json:"max_experience"`
+	Gender         pgtype.Text    `json:"gender"`
+	Language       pgtype.Text    `json:"language"`
+	MinRating      pgtype.Numeric `json:"min_rating"`
+	MaxFee         pgtype.Numeric `json:"max_fee"`
+	VisitType      pgtype.Text    `json:"visit_type"`
+	AvailableOnly  bool           `json:"available_only"`
+	SortBy         string         `json:"sort_by"`
+	OffsetCount    int32          `json:"offset_count"`
+	LimitCount     int32          `json:"limit_count"`
+}
+
+type SearchDoctorsRow struct {
+	Username                string           `json:"username"`
+	FullName                string           `json:"full_name"`
+	MobileNumber            string           `json:"mobile_number"`
+	Gender                  string           `json:"gender"`
+	Age                     int32            `json:"age"`
+	Specialization          string           `json:"specialization"`
+	Email                   string           `json:"email"`
+	Password                string           `json:"password"`
+	RegistrationNumber      string           `json:"registration_number"`
+	HospitalName            pgtype.Text      `json:"hospital_name"`
+	YearsExperience         int32            `json:"years_experience"`
+	PasswordChangedAt       pgtype.Timestamp `json:"password_changed_at"`
+	CreatedAt               pgtype.Timestamp `json:"created_at"`
+	CancellationWindowHours int32            `json:"cancellation_window_hours"`
+	RatingAverage           pgtype.Numeric   `json:"rating_average"`
+	ReviewCount             int32            `json:"review_count"`
+	Languages               []string         `json:"languages"`
+	InPersonFee             pgtype.Numeric   `json:"in_person_fee"`
+	VideoFee                pgtype.Numeric   `json:"video_fee"`
+	FollowUpWindowDays      int32            `json:"follow_up_window_days"`
+	FollowUpDiscount        int32            `json:"follow_up_discount"`
+	RefundWindowHours       int32            `json:"refund_window_hours"`
+	TotalCount              int64            `json:"total_count"`
+}
+
+// The public doctor directory. Every filter is optional. specialization is
+// a normalized name such as "cardiology" and also matches close spellings
+// like "Cardiologist"; hospital matches part of the hospital name. max_fee
+// applies to the fee of visit_type, or to either fee without one. With
+// available_only, only doctors with weekly hours or extra hours ahead are
+// returned. Sorted by years of experience, or by rating when sort_by is
+// 'rating', with doctors without reviews last. total_count is the number
+// of doctors matching before the limit and offset.
+func (q *Queries) SearchDoctors(ctx context.Context, arg SearchDoctorsParams) ([]SearchDoctorsRow, error) {
+	rows, err := q.db.Query(ctx, searchDoctors,
+		arg.Specialization,
+		arg.Hospital,
+		arg.MinExperience,
+		arg.MaxExperience,
+		arg.Gender,
+		arg.Language,
+		arg.MinRating,
+		arg.MaxFee,
+		arg.VisitType,
+		arg.AvailableOnly,
+		arg.SortBy,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchDoctorsRow{}
+	for rows.Next() {
+		var i SearchDoctorsRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.FullName,
+			&i.MobileNumber,
+			&i.Gender,
+			&i.Age,
+			&i.Specialization,
+			&i.Email,
+			&i.Password,
+			&i.RegistrationNumber,
+			&i.HospitalName,
+			&i.YearsExperience,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.CancellationWindowHours,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.Languages,
+			&i.InPersonFee,
+			&i.VideoFee,
+			&i.FollowUpWindowDays,
+			&i.FollowUpDiscount,
+			&i.RefundWindowHours,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setDoctorCancellationWindow = `-- name: SetDoctorCancellationWindow :one
+UPDATE doctors SET
+  cancellation_window_hours = $2
+WHERE username = $1
+RETURNING username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at, cancellation_window_hours, rating_average, review_count, languages, in_person_fee, video_fee, follow_up_window_days, follow_up_discount, refund_window_hours
+`
+
+type SetDoctorCancellationWindowParams struct {
+	Username                string `json:"username"`
+	CancellationWindowHours int32  `json:"cancellation_window_hours"`
+}
+
+func (q *Queries) SetDoctorCancellationWindow(ctx context.Context, arg SetDoctorCancellationWindowParams) (Doctor, error) {
+	row := q.db.QueryRow(ctx, setDoctorCancellationWindow, arg.Username, arg.CancellationWindowHours)
+	var i Doctor
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.MobileNumber,
+		&i.Gender,
+		&i.Age,
+		&i.Specialization,
+		&i.Email,
+		&i.Password,
+		&i.RegistrationNumber,
+		&i.HospitalName,
+		&i.YearsExperience,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.CancellationWindowHours,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.Languages,
+		&i.InPersonFee,
+		&i.VideoFee,
+		&i.FollowUpWindowDays,
+		&i.FollowUpDiscount,
+		&i.RefundWindowHours,
+	)
+	return i, err
+}
+
+const setDoctorFees = `-- name: SetDoctorFees :one
+UPDATE doctors SET
+  in_person_fee = $1,
+  video_fee = $2,
+  follow_up_window_days = $3,
+  follow_up_discount = $4,
+  refund_window_hours = $5
+WHERE username = $6
+RETURNING username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at, cancellation_window_hours, rating_average, review_count, languages, in_person_fee, video_fee, follow_up_window_days, follow_up_discount, refund_window_hours
+`
+
+type SetDoctorFeesParams struct {
+	InPersonFee        pgtype.Numeric `json:"in_person_fee"`
+	VideoFee           pgtype.Numeric `json:"video_fee"`
+	FollowUpWindowDays int32          `json:"follow_up_window_days"`
+	FollowUpDiscount   int32          `json:"follow_up_discount"`
+	RefundWindowHours  int32          `json:"refund_window_hours"`
+	Username           string         `json:"username"`
+}
+
+func (q *Queries) SetDoctorFees(ctx context.Context, arg SetDoctorFeesParams) (Doctor, error) {
+	row := q.db.QueryRow(ctx, setDoctorFees,
+		arg.InPersonFee,
+		arg.VideoFee,
+		arg.FollowUpWindowDays,
+		arg.FollowUpDiscount,
+		arg.RefundWindowHours,
+		arg.Username,
+	)
+	var i Doctor
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.MobileNumber,
+		&i.Gender,
+		&i.Age,
+		&i.Specialization,
+		&i.Email,
+		&i.Password,
+		&i.RegistrationNumber,
+		&i.HospitalName,
+		&i.YearsExperience,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.CancellationWindowHours,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.Languages,
+		&i.InPersonFee,
+		&i.VideoFee,
+		&i.FollowUpWindowDays,
+		&i.FollowUpDiscount,
+		&i.RefundWindowHours,
+	)
+	return i, err
+}
+
+const updateDoctor = `-- name: UpdateDoctor :one
+UPDATE doctors SET
+  full_name = COALESCE($1, full_name),
+  mobile_number = COALESCE($2, mobile_number),
+  gender = COALESCE($3, gender),
+  age = COALESCE($4, age),
+  specialization = COALESCE($5, specialization),
+  email = COALESCE($6, email),
+  password = COALESCE($7, password),
+  registration_number = COALESCE($8, registration_number),
+  hospital_name = COALESCE($9, hospital_name),
+  years_experience = COALESCE($10, years_experience),
+  languages = COALESCE($11, languages),
+  password_changed_at = COALESCE($12, password_changed_at)
+WHERE username = $13
+RETURNING username, full_name, mobile_number, gender, age, specialization, email, password, registration_number, hospital_name, years_experience, password_changed_at, created_at, cancellation_window_hours, rating_average, review_count, languages, in_person_fee, video_fee, follow_up_window_days, follow_up_discount, refund_window_hours
+`
+
+type UpdateDoctorParams struct {
+	FullName           pgtype.Text      `json:"full_name"`
+	MobileNumber       pgtype.Text      `json:"mobile_number"`
+	Gender             pgtype.Text      `json:"gender"`
+	Age                pgtype.Int4      `json:"age"`
+	Specialization     pgtype.Text      `json:"specialization"`
+	Email              pgtype.Text      `json:"email"`
+	Password           pgtype.Text      `json:"password"`
+	RegistrationNumber pgtype.Text      `json:"registration_number"`
+	HospitalName       pgtype.Text      `json:"hospital_name"`
+	YearsExperience    pgtype.Int4      `json:"years_experience"`
+	Languages          []string         `json:"languages"`
+	PasswordChangedAt  pgtype.Timestamp `json:"password_changed_at"`
+	Username           string           `json:"username"`
+}
+
+func (q *Queries) UpdateDoctor(ctx context.Context, arg UpdateDoctorParams) (Doctor, error) {
+	row := q.db.QueryRow(ctx, updateDoctor,
+		arg.FullName,
+		arg.MobileNumber,
+		arg.Gender,
+		arg.Age,
+		arg.Specialization,
+		arg.Email,
+		arg.Password,
+		arg.RegistrationNumber,
+		arg.HospitalName,
+		arg.YearsExperience,
+		arg.Languages,
+		arg.PasswordChangedAt,
+		arg.Username,
+	)
+	var i Doctor
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.MobileNumber,
+		&i.Gender,
+		&i.Age,
+		&i.Specialization,
+		&i.Email,
+		&i.Password,
+		&i.RegistrationNumber,
+		&i.HospitalName,
+		&i.YearsExperience,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.CancellationWindowHours,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.Languages,
+		&i.InPersonFee,
+		&i.VideoFee,
+		&i.FollowUpWindowDays,
+		&i.FollowUpDiscount,
+		&i.RefundWindowHours,
+	)
+	return i, err
+}
@@ -0,0 +1,314 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: doctor_availability.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createDoctorAvailability = `-- name: CreateDoctorAvailability :one
+INSERT INTO doctor_availability (
+    doctor_username, day_of_week, starts_at, ends_at, slot_minutes
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, doctor_username, day_of_week, starts_at, ends_at, slot_minutes
+`
+
+type CreateDoctorAvailabilityParams struct {
+	DoctorUsername string      `json:"doctor_username"`
+	DayOfWeek      int16       `json:"day_of_week"`
+	StartsAt       pgtype.Time `json:"starts_at"`
+	EndsAt         pgtype.Time `json:"ends_at"`
+	SlotMinutes    int32       `json:"slot_minutes"`
+}
+
+func (q *Queries) CreateDoctorAvailability(ctx context.Context, arg CreateDoctorAvailabilityParams) (DoctorAvailability, error) {
+	row := q.db.QueryRow(ctx, createDoctorAvailability,
+		arg.DoctorUsername,
+		arg.DayOfWeek,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.SlotMinutes,
+	)
+	var i DoctorAvailability
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.DayOfWeek,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.SlotMinutes,
+	)
+	return i, err
+}
+
+const createDoctorAvailabilityException = `-- name: CreateDoctorAvailabilityException :one
+INSERT INTO doctor_availability_exceptions (
+    doctor_username, date, starts_at, ends_at, available, slot_minutes, reason
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id, doctor_username, date, starts_at, ends_at, available, slot_minutes, reason, created_at
+`
+
+type CreateDoctorAvailabilityExceptionParams struct {
+	DoctorUsername string      `json:"doctor_username"`
+	Date           pgtype.Date `json:"date"`
+	StartsAt       pgtype.Time `json:"starts_at"`
+	EndsAt         pgtype.Time `json:"ends_at"`
+	Available      bool        `json:"available"`
+	SlotMinutes    pgtype.Int4 `json:"slot_minutes"`
+	Reason         string      `json:"reason"`
+}
+
+func (q *Queries) CreateDoctorAvailabilityException(ctx context.Context, arg CreateDoctorAvailabilityExceptionParams) (DoctorAvailabilityException, error) {
+	row := q.db.QueryRow(ctx, createDoctorAvailabilityException,
+		arg.DoctorUsername,
+		arg.Date,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Available,
+		arg.SlotMinutes,
+		arg.Reason,
+	)
+	var i DoctorAvailabilityException
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.Date,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Available,
+		&i.SlotMinutes,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createDoctorLeave = `-- name: CreateDoctorLeave :one
+INSERT INTO doctor_leaves (
+    doctor_username, starts_on, ends_on, reason
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, doctor_username, starts_on, ends_on, reason, created_at
+`
+
+type CreateDoctorLeaveParams struct {
+	DoctorUsername string      `json:"doctor_username"`
+	StartsOn       pgtype.Date `json:"starts_on"`
+	EndsOn         pgtype.Date `json:"ends_on"`
+	Reason         string      `json:"reason"`
+}
+
+func (q *Queries) CreateDoctorLeave(ctx context.Context, arg CreateDoctorLeaveParams) (DoctorLeave, error) {
+	row := q.db.QueryRow(ctx, createDoctorLeave,
+		arg.DoctorUsername,
+		arg.StartsOn,
+		arg.EndsOn,
+		arg.Reason,
+	)
+	var i DoctorLeave
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteDoctorAvailability = `-- name: DeleteDoctorAvailability :exec
+DELETE FROM doctor_availability
+WHERE doctor_username = $1
+`
+
+func (q *Queries) DeleteDoctorAvailability(ctx context.Context, doctorUsername string) error {
+	_, err := q.db.Exec(ctx, deleteDoctorAvailability, doctorUsername)
+	return err
+}
+
+const deleteDoctorAvailabilityException = `-- name: DeleteDoctorAvailabilityException :exec
+DELETE FROM doctor_availability_exceptions WHERE id = $1
+`
+
+func (q *Queries) DeleteDoctorAvailabilityException(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteDoctorAvailabilityException, id)
+	return err
+}
+
+const deleteDoctorLeave = `-- name: DeleteDoctorLeave :exec
+DELETE FROM doctor_leaves WHERE id = $1
+`
+
+func (q *Queries) DeleteDoctorLeave(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteDoctorLeave, id)
+	return err
+}
+
+const getDoctorAvailabilityException = `-- name: GetDoctorAvailabilityException :one
+SELECT id, doctor_username, date, starts_at, ends_at, available, slot_minutes, reason, created_at FROM doctor_availability_exceptions WHERE id = $1
+`
+
+func (q *Queries) GetDoctorAvailabilityException(ctx context.Context, id int32) (DoctorAvailabilityException, error) {
+	row := q.db.QueryRow(ctx, getDoctorAvailabilityException, id)
+	var i DoctorAvailabilityException
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.Date,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Available,
+		&i.SlotMinutes,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDoctorLeave = `-- name: GetDoctorLeave :one
+SELECT id, doctor_username, starts_on, ends_on, reason, created_at FROM doctor_leaves WHERE id = $1
+`
+
+func (q *Queries) GetDoctorLeave(ctx context.Context, id int32) (DoctorLeave, error) {
+	row := q.db.QueryRow(ctx, getDoctorLeave, id)
+	var i DoctorLeave
+	err := row.Scan(
+		&i.ID,
+		&i.DoctorUsername,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listDoctorAvailability = `-- name: ListDoctorAvailability :many
+SELECT id, doctor_username, day_of_week, starts_at, ends_at, slot_minutes FROM doctor_availability
+WHERE doctor_username = $1
+ORDER BY day_of_week, starts_at
+`
+
+func (q *Queries) ListDoctorAvailability(ctx context.Context, doctorUsername string) ([]DoctorAvailability, error) {
+	rows, err := q.db.Query(ctx, listDoctorAvailability, doctorUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DoctorAvailability{}
+	for rows.Next() {
+		var i DoctorAvailability
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.DayOfWeek,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.SlotMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDoctorAvailabilityExceptions = `-- name: ListDoctorAvailabilityExceptions :many
+SELECT id, doctor_username, date, starts_at, ends_at, available, slot_minutes, reason, created_at FROM doctor_availability_exceptions
+WHERE doctor_username = $1
+  AND date BETWEEN $2 AND $3
+ORDER BY date, starts_at
+`
+
+type ListDoctorAvailabilityExceptionsParams struct {
+	DoctorUsername string      `json:"doctor_username"`
+	FromDate       pgtype.Date `json:"from_date"`
+	ToDate         pgtype.Date `json:"to_date"`
+}
+
+// The doctor's exceptions on dates from from_date to to_date, both inclusive
+func (q *Queries) ListDoctorAvailabilityExceptions(ctx context.Context, arg ListDoctorAvailabilityExceptionsParams) ([]DoctorAvailabilityException, error) {
+	rows, err := q.db.Query(ctx, listDoctorAvailabilityExceptions, arg.DoctorUsername, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DoctorAvailabilityException{}
+	for rows.Next() {
+		var i DoctorAvailabilityException
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.Date,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Available,
+			&i.SlotMinutes,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDoctorLeaves = `-- name: ListDoctorLeaves :many
+SELECT id, doctor_username, starts_on, ends_on, reason, created_at FROM doctor_leaves
+WHERE doctor_username = $1
+  AND starts_on <= $2
+  AND ends_on >= $3
+ORDER BY starts_on
+`
+
+type ListDoctorLeavesParams struct {
+	DoctorUsername string      `json:"doctor_username"`
+	ToDate         pgtype.Date `json:"to_date"`
+	FromDate       pgtype.Date `json:"from_date"`
+}
+
+// The doctor's leave overlapping from_date to to_date, both inclusive
+func (q *Queries) ListDoctorLeaves(ctx context.Context, arg ListDoctorLeavesParams) ([]DoctorLeave, error) {
+	rows, err := q.db.Query(ctx, listDoctorLeaves, arg.DoctorUsername, arg.ToDate, arg.FromDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DoctorLeave{}
+	for rows.Next() {
+		var i DoctorLeave
+		if err := rows.Scan(
+			&i.ID,
+			&i.DoctorUsername,
+			&i.StartsOn,
+			&i.EndsOn,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
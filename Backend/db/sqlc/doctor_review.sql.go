@@ -0,0 +1,311 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: doctor_review.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createDoctorReview = `-- name: CreateDoctorReview :one
+INSERT INTO doctor_reviews (
+    appointment_id, doctor_username, patient_username, rating, comment,
+    status, moderation_note
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id, appointment_id, doctor_username, patient_username, rating, comment, status, moderation_note, reported_at, report_reason, created_at, moderated_at
+`
+
+type CreateDoctorReviewParams struct {
+	AppointmentID   int32  `json:"appointment_id"`
+	DoctorUsername  string `json:"doctor_username"`
+	PatientUsername string `json:"patient_username"`
+	Rating          int16  `json:"rating"`
+	Comment         string `json:"comment"`
+	Status          string `json:"status"`
+	ModerationNote  string `json:"moderation_note"`
+}
+
+func (q *Queries) CreateDoctorReview(ctx context.Context, arg CreateDoctorReviewParams) (DoctorReview, error) {
+	row := q.db.QueryRow(ctx, createDoctorReview,
+		arg.AppointmentID,
+		arg.DoctorUsername,
+		arg.PatientUsername,
+		arg.Rating,
+		arg.Comment,
+		arg.Status,
+		arg.ModerationNote,
+	)
+	var i DoctorReview
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.ReportedAt,
+		&i.ReportReason,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const getDoctorRatingDistribution = `-- name: GetDoctorRatingDistribution :many
+SELECT rating, COUNT(*) AS reviews
+FROM doctor_reviews
+WHERE doctor_username = $1 AND status = 'published'
+GROUP BY rating
+ORDER BY rating DESC
+`
+
+type GetDoctorRatingDistributionRow struct {
+	Rating  int16 `json:"rating"`
+	Reviews int64 `json:"reviews"`
+}
+
+// How many published reviews gave each star rating
+func (q *Queries) GetDoctorRatingDistribution(ctx context.Context, doctorUsername string) ([]GetDoctorRatingDistributionRow, error) {
+	rows, err := q.db.Query(ctx, getDoctorRatingDistribution, doctorUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetDoctorRatingDistributionRow{}
+	for rows.Next() {
+		var i GetDoctorRatingDistributionRow
+		if err := rows.Scan(&i.Rating, &i.Reviews); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDoctorReview = `-- name: GetDoctorReview :one
+SELECT id, appointment_id, doctor_username, patient_username, rating, comment, status, moderation_note, reported_at, report_reason, created_at, moderated_at FROM doctor_reviews WHERE id = $1
+`
+
+func (q *Queries) GetDoctorReview(ctx context.Context, id int32) (DoctorReview, error) {
+	row := q.db.QueryRow(ctx, getDoctorReview, id)
+	var i DoctorReview
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.ReportedAt,
+		&i.ReportReason,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const getDoctorReviewByAppointment = `-- name: GetDoctorReviewByAppointment :one
+SELECT id, appointment_id, doctor_username, patient_username, rating, comment, status, moderation_note, reported_at, report_reason, created_at, moderated_at FROM doctor_reviews WHERE appointment_id = $1
+`
+
+func (q *Queries) GetDoctorReviewByAppointment(ctx context.Context, appointmentID int32) (DoctorReview, error) {
+	row := q.db.QueryRow(ctx, getDoctorReviewByAppointment, appointmentID)
+	var i DoctorReview
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.ReportedAt,
+		&i.ReportReason,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const listDoctorReviewModerationQueue = `-- name: ListDoctorReviewModerationQueue :many
+SELECT id, appointment_id, doctor_username, patient_username, rating, comment, status, moderation_note, reported_at, report_reason, created_at, moderated_at FROM doctor_reviews
+WHERE status = 'pending' OR (status = 'published' AND reported_at IS NOT NULL)
+ORDER BY created_at ASC, id ASC
+LIMIT $2 OFFSET $1
+`
+
+type ListDoctorReviewModerationQueueParams struct {
+	OffsetCount int32 `json:"offset_count"`
+	LimitCount  int32 `json:"limit_count"`
+}
+
+// Held reviews and published ones reported by their doctor, oldest first
+func (q *Queries) ListDoctorReviewModerationQueue(ctx context.Context, arg ListDoctorReviewModerationQueueParams) ([]DoctorReview, error) {
+	rows, err := q.db.Query(ctx, listDoctorReviewModerationQueue, arg.OffsetCount, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DoctorReview{}
+	for rows.Next() {
+		var i DoctorReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.DoctorUsername,
+			&i.PatientUsername,
+			&i.Rating,
+			&i.Comment,
+			&i.Status,
+			&i.ModerationNote,
+			&i.ReportedAt,
+			&i.ReportReason,
+			&i.CreatedAt,
+			&i.ModeratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDoctorReviews = `-- name: ListDoctorReviews :many
+SELECT r.id, r.appointment_id, r.rating, r.comment, r.created_at, p.full_name AS patient_name
+FROM doctor_reviews r
+JOIN patients p ON p.username = r.patient_username
+WHERE r.doctor_username = $1
+  AND r.status = 'published'
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListDoctorReviewsParams struct {
+	DoctorUsername string `json:"doctor_username"`
+	OffsetCount    int32  `json:"offset_count"`
+	LimitCount     int32  `json:"limit_count"`
+}
+
+type ListDoctorReviewsRow struct {
+	ID            int32            `json:"id"`
+	AppointmentID int32            `json:"appointment_id"`
+	Rating        int16            `json:"rating"`
+	Comment       string           `json:"comment"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	PatientName   string           `json:"patient_name"`
+}
+
+// A doctor's published reviews, newest first
+func (q *Queries) ListDoctorReviews(ctx context.Context, arg ListDoctorReviewsParams) ([]ListDoctorReviewsRow, error) {
+	rows, err := q.db.Query(ctx, listDoctorReviews, arg.DoctorUsername, arg.OffsetCount, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDoctorReviewsRow{}
+	for rows.Next() {
+		var i ListDoctorReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.Rating,
+			&i.Comment,
+			&i.CreatedAt,
+			&i.PatientName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moderateDoctorReview = `-- name: ModerateDoctorReview :one
+UPDATE doctor_reviews SET
+    status = $1,
+    moderation_note = $2,
+    reported_at = NULL,
+    moderated_at = now()
+WHERE id = $3
+RETURNING id, appointment_id, doctor_username, patient_username, rating, comment, status, moderation_note, reported_at, report_reason, created_at, moderated_at
+`
+
+type ModerateDoctorReviewParams struct {
+	Status         string `json:"status"`
+	ModerationNote string `json:"moderation_note"`
+	ID             int32  `json:"id"`
+}
+
+// Publishes or rejects a review, which also settles any report on it
+func (q *Queries) ModerateDoctorReview(ctx context.Context, arg ModerateDoctorReviewParams) (DoctorReview, error) {
+	row := q.db.QueryRow(ctx, moderateDoctorReview, arg.Status, arg.ModerationNote, arg.ID)
+	var i DoctorReview
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.ReportedAt,
+		&i.ReportReason,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const reportDoctorReview = `-- name: ReportDoctorReview :one
+UPDATE doctor_reviews SET
+    reported_at = now(),
+    report_reason = $1
+WHERE id = $2 AND status = 'published'
+RETURNING id, appointment_id, doctor_username, patient_username, rating, comment, status, moderation_note, reported_at, report_reason, created_at, moderated_at
+`
+
+type ReportDoctorReviewParams struct {
+	ReportReason pgtype.Text `json:"report_reason"`
+	ID           int32       `json:"id"`
+}
+
+func (q *Queries) ReportDoctorReview(ctx context.Context, arg ReportDoctorReviewParams) (DoctorReview, error) {
+	row := q.db.QueryRow(ctx, reportDoctorReview, arg.ReportReason, arg.ID)
+	var i DoctorReview
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.ReportedAt,
+		&i.ReportReason,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
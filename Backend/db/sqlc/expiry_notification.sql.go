@@ -0,0 +1,119 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: expiry_notification.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createExpiryNotification = `-- name: CreateExpiryNotification :exec
+INSERT INTO expiry_notifications (
+    medicine_id, seller_username, threshold_days
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (medicine_id, threshold_days) DO NOTHING
+`
+
+type CreateExpiryNotificationParams struct {
+	MedicineID     int32  `json:"medicine_id"`
+	SellerUsername string `json:"seller_username"`
+	ThresholdDays  int32  `json:"threshold_days"`
+}
+
+func (q *Queries) CreateExpiryNotification(ctx context.Context, arg CreateExpiryNotificationParams) error {
+	_, err := q.db.Exec(ctx, createExpiryNotification, arg.MedicineID, arg.SellerUsername, arg.ThresholdDays)
+	return err
+}
+
+const listSentExpiryNotifications = `-- name: ListSentExpiryNotifications :many
+SELECT medicine_id, threshold_days FROM expiry_notifications
+WHERE seller_username = $1
+`
+
+type ListSentExpiryNotificationsRow struct {
+	MedicineID    int32 `json:"medicine_id"`
+	ThresholdDays int32 `json:"threshold_days"`
+}
+
+func (q *Queries) ListSentExpiryNotifications(ctx context.Context, sellerUsername string) ([]ListSentExpiryNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, listSentExpiryNotifications, sellerUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSentExpiryNotificationsRow{}
+	for rows.Next() {
+		var i ListSentExpiryNotificationsRow
+		if err := rows.Scan(&i.MedicineID, &i.ThresholdDays); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnnotifiedExpiredMedicines = `-- name: ListUnnotifiedExpiredMedicines :many
+SELECT m.id, m.name, m.description, m.expiry_date, m.quantity, m.price, m.discount, m.seller_username, m.created_at, m.active_ingredients, m.strength, m.dosage_form, m.prescription_required, m.batch_number, m.status, m.quarantined_quantity, m.quarantined_at, m.original_discount, m.markdown_rule_id, m.trade_price, m.min_order_quantity, m.reorder_level, m.rating_average, m.review_count, m.withdrawn_at FROM medicines m
+WHERE m.status = 'quarantined'
+  AND m.expiry_date <= m.quarantined_at::date
+  AND NOT EXISTS (
+    SELECT 1 FROM expiry_notifications n
+    WHERE n.medicine_id = m.id AND n.threshold_days = 0
+  )
+ORDER BY m.id ASC
+`
+
+// Medicines quarantined for expiring whose seller hasn't been told yet,
+// including those from runs where the digest couldn't be sent
+func (q *Queries) ListUnnotifiedExpiredMedicines(ctx context.Context) ([]Medicine, error) {
+	rows, err := q.db.Query(ctx, listUnnotifiedExpiredMedicines)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Medicine{}
+	for rows.Next() {
+		var i Medicine
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.SellerUsername,
+			&i.CreatedAt,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.Status,
+			&i.QuarantinedQuantity,
+			&i.QuarantinedAt,
+			&i.OriginalDiscount,
+			&i.MarkdownRuleID,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.ReorderLevel,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.WithdrawnAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
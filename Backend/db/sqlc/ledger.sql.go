@@ -0,0 +1,502 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: ledger.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createChargeback = `-- name: CreateChargeback :one
+INSERT INTO chargebacks (
+    sub_order_id, amount, reason, created_by
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, sub_order_id, amount, reason, created_at, posted_at, created_by
+`
+
+type CreateChargebackParams struct {
+	SubOrderID int32          `json:"sub_order_id"`
+	Amount     pgtype.Numeric `json:"amount"`
+	Reason     string         `json:"reason"`
+	CreatedBy  string         `json:"created_by"`
+}
+
+func (q *Queries) CreateChargeback(ctx context.Context, arg CreateChargebackParams) (Chargeback, error) {
+	row := q.db.QueryRow(ctx, createChargeback,
+		arg.SubOrderID,
+		arg.Amount,
+		arg.Reason,
+		arg.CreatedBy,
+	)
+	var i Chargeback
+	err := row.Scan(
+		&i.ID,
+		&i.SubOrderID,
+		&i.Amount,
+		&i.Reason,
+		&i.CreatedAt,
+		&i.PostedAt,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
+const createLedgerEntry = `-- name: CreateLedgerEntry :one
+INSERT INTO ledger_entries (
+    transaction_id, account, amount
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, transaction_id, account, amount
+`
+
+type CreateLedgerEntryParams struct {
+	TransactionID int64          `json:"transaction_id"`
+	Account       string         `json:"account"`
+	Amount        pgtype.Numeric `json:"amount"`
+}
+
+func (q *Queries) CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) (LedgerEntry, error) {
+	row := q.db.QueryRow(ctx, createLedgerEntry, arg.TransactionID, arg.Account, arg.Amount)
+	var i LedgerEntry
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.Account,
+		&i.Amount,
+	)
+	return i, err
+}
+
+const createLedgerTransaction = `-- name: CreateLedgerTransaction :one
+INSERT INTO ledger_transactions (
+    kind, seller_username, sub_order_id, payout_id, commission_rate, description, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id, kind, seller_username, sub_order_id, payout_id, commission_rate, description, created_by, created_at
+`
+
+type CreateLedgerTransactionParams struct {
+	Kind           string         `json:"kind"`
+	SellerUsername string         `json:"seller_username"`
+	SubOrderID     pgtype.Int4    `json:"sub_order_id"`
+	PayoutID       pgtype.Int4    `json:"payout_id"`
+	CommissionRate pgtype.Numeric `json:"commission_rate"`
+	Description    string         `json:"description"`
+	CreatedBy      string         `json:"created_by"`
+}
+
+func (q *Queries) CreateLedgerTransaction(ctx context.Context, arg CreateLedgerTransactionParams) (LedgerTransaction, error) {
+	row := q.db.QueryRow(ctx, createLedgerTransaction,
+		arg.Kind,
+		arg.SellerUsername,
+		arg.SubOrderID,
+		arg.PayoutID,
+		arg.CommissionRate,
+		arg.Description,
+		arg.CreatedBy,
+	)
+	var i LedgerTransaction
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.SellerUsername,
+		&i.SubOrderID,
+		&i.PayoutID,
+		&i.CommissionRate,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPendingChargebackTotal = `-- name: GetPendingChargebackTotal :one
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(14, 2) AS total
+FROM chargebacks
+WHERE sub_order_id = $1 AND posted_at IS NULL
+`
+
+// Chargebacks reported on a sub-order that the payout job hasn't posted yet
+func (q *Queries) GetPendingChargebackTotal(ctx context.Context, subOrderID int32) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, getPendingChargebackTotal, subOrderID)
+	var total pgtype.Numeric
+	err := row.Scan(&total)
+	return total, err
+}
+
+const getSellerLedgerBalance = `-- name: GetSellerLedgerBalance :one
+SELECT COALESCE(-SUM(e.amount), 0)::NUMERIC(14, 2) AS balance
+FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+LEFT JOIN payouts p ON p.id = t.payout_id
+WHERE t.seller_username = $1
+  AND e.account = 'seller_payable'
+  AND COALESCE(p.period_end, t.created_at) <= $2::TIMESTAMP
+`
+
+type GetSellerLedgerBalanceParams struct {
+	Seller string           `json:"seller"`
+	AsOf   pgtype.Timestamp `json:"as_of"`
+}
+
+// What the platform owes the seller as of a point in time. It is negative
+// when refunds and chargebacks exceeded earnings since the last payout.
+// Payouts are made after their cutoff, so they count from their period_end.
+func (q *Queries) GetSellerLedgerBalance(ctx context.Context, arg GetSellerLedgerBalanceParams) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, getSellerLedgerBalance, arg.Seller, arg.AsOf)
+	var balance pgtype.Numeric
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const getSellerSettlement = `-- name: GetSellerSettlement :one
+SELECT
+    COALESCE(SUM(e.amount) FILTER (
+        WHERE t.kind = 'earning' AND e.account = 'customer_payments'
+    ), 0)::NUMERIC(14, 2) AS gross_sales,
+    COALESCE(-SUM(e.amount) FILTER (
+        WHERE e.account = 'platform_commission'
+    ), 0)::NUMERIC(14, 2) AS commission,
+    COALESCE(-SUM(e.amount) FILTER (
+        WHERE t.kind = 'refund' AND e.account = 'customer_payments'
+    ), 0)::NUMERIC(14, 2) AS refunds,
+    COALESCE(-SUM(e.amount) FILTER (
+        WHERE t.kind = 'chargeback' AND e.account = 'customer_payments'
+    ), 0)::NUMERIC(14, 2) AS chargebacks
+FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE t.seller_username = $1
+  AND t.kind <> 'payout'
+  AND ($2::TIMESTAMP IS NULL OR t.created_at > $2)
+  AND t.created_at <= $3::TIMESTAMP
+`
+
+type GetSellerSettlementParams struct {
+	Seller      string           `json:"seller"`
+	PeriodStart pgtype.Timestamp `json:"period_start"`
+	PeriodEnd   pgtype.Timestamp `json:"period_end"`
+}
+
+type GetSellerSettlementRow struct {
+	GrossSales  pgtype.Numeric `json:"gross_sales"`
+	Commission  pgtype.Numeric `json:"commission"`
+	Refunds     pgtype.Numeric `json:"refunds"`
+	Chargebacks pgtype.Numeric `json:"chargebacks"`
+}
+
+// The seller's ledger activity after period_start (from the beginning when
+// null) up to and including period_end
+func (q *Queries) GetSellerSettlement(ctx context.Context, arg GetSellerSettlementParams) (GetSellerSettlementRow, error) {
+	row := q.db.QueryRow(ctx, getSellerSettlement, arg.Seller, arg.PeriodStart, arg.PeriodEnd)
+	var i GetSellerSettlementRow
+	err := row.Scan(
+		&i.GrossSales,
+		&i.Commission,
+		&i.Refunds,
+		&i.Chargebacks,
+	)
+	return i, err
+}
+
+const getSubOrderEarning = `-- name: GetSubOrderEarning :one
+SELECT so.id, so.seller_username, so.subtotal,
+    COALESCE(cr.rate_percent, 0)::NUMERIC(5, 2) AS commission_rate,
+    ROUND(so.subtotal * COALESCE(cr.rate_percent, 0) / 100, 2)::NUMERIC(14, 2) AS commission,
+    (so.subtotal - ROUND(so.subtotal * COALESCE(cr.rate_percent, 0) / 100, 2))::NUMERIC(14, 2) AS seller_amount
+FROM sub_orders so
+JOIN sellers s ON s.username = so.seller_username
+LEFT JOIN commission_rates cr ON cr.seller_type = s.seller_type
+WHERE so.id = $1
+`
+
+type GetSubOrderEarningRow struct {
+	ID             int32          `json:"id"`
+	SellerUsername string         `json:"seller_username"`
+	Subtotal       pgtype.Numeric `json:"subtotal"`
+	CommissionRate pgtype.Numeric `json:"commission_rate"`
+	Commission     pgtype.Numeric `json:"commission"`
+	SellerAmount   pgtype.Numeric `json:"seller_amount"`
+}
+
+// What a sub-order earns its seller once delivered, after the commission
+// for the seller's type
+func (q *Queries) GetSubOrderEarning(ctx context.Context, id int32) (GetSubOrderEarningRow, error) {
+	row := q.db.QueryRow(ctx, getSubOrderEarning, id)
+	var i GetSubOrderEarningRow
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.Subtotal,
+		&i.CommissionRate,
+		&i.Commission,
+		&i.SellerAmount,
+	)
+	return i, err
+}
+
+const getSubOrderRefundSplit = `-- name: GetSubOrderRefundSplit :one
+WITH earning AS (
+    SELECT t.seller_username, t.commission_rate,
+        so.subtotal - COALESCE((
+            SELECT -SUM(e.amount)
+            FROM ledger_entries e
+            JOIN ledger_transactions r ON r.id = e.transaction_id
+            WHERE r.sub_order_id = t.sub_order_id
+              AND r.kind IN ('refund', 'chargeback')
+              AND e.account = 'customer_payments'
+        ), 0) AS refundable
+    FROM ledger_transactions t
+    JOIN sub_orders so ON so.id = t.sub_order_id
+    WHERE t.sub_order_id = $1 AND t.kind = 'earning'
+), refund AS (
+    SELECT earning.seller_username, earning.commission_rate, earning.refundable, COALESCE($2::NUMERIC, earning.refundable) AS amount
+    FROM earning
+)
+SELECT seller_username,
+    commission_rate::NUMERIC(5, 2) AS commission_rate,
+    refundable::NUMERIC(14, 2) AS refundable,
+    amount::NUMERIC(14, 2) AS amount,
+    ROUND(amount * commission_rate / 100, 2)::NUMERIC(14, 2) AS commission,
+    (amount - ROUND(amount * commission_rate / 100, 2))::NUMERIC(14, 2) AS seller_amount,
+    (amount > 0 AND amount <= refundable) AS allowed
+FROM refund
+`
+
+type GetSubOrderRefundSplitParams struct {
+	SubOrderID pgtype.Int4    `json:"sub_order_id"`
+	Amount     pgtype.Numeric `json:"amount"`
+}
+
+type GetSubOrderRefundSplitRow struct {
+	SellerUsername string         `json:"seller_username"`
+	CommissionRate pgtype.Numeric `json:"commission_rate"`
+	Refundable     pgtype.Numeric `json:"refundable"`
+	Amount         pgtype.Numeric `json:"amount"`
+	Commission     pgtype.Numeric `json:"commission"`
+	SellerAmount   pgtype.Numeric `json:"seller_amount"`
+	Allowed        pgtype.Bool    `json:"allowed"`
+}
+
+// Splits money paid back on a delivered sub-order between the seller and
+// the platform at the commission rate of the original earning. A null
+// amount pays back everything not yet refunded or charged back.
+func (q *Queries) GetSubOrderRefundSplit(ctx context.Context, arg GetSubOrderRefundSplitParams) (GetSubOrderRefundSplitRow, error) {
+	row := q.db.QueryRow(ctx, getSubOrderRefundSplit, arg.SubOrderID, arg.Amount)
+	var i GetSubOrderRefundSplitRow
+	err := row.Scan(
+		&i.SellerUsername,
+		&i.CommissionRate,
+		&i.Refundable,
+		&i.Amount,
+		&i.Commission,
+		&i.SellerAmount,
+		&i.Allowed,
+	)
+	return i, err
+}
+
+const listCommissionRates = `-- name: ListCommissionRates :many
+SELECT seller_type, rate_percent, updated_at FROM commission_rates
+ORDER BY seller_type ASC
+`
+
+func (q *Queries) ListCommissionRates(ctx context.Context) ([]CommissionRate, error) {
+	rows, err := q.db.Query(ctx, listCommissionRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CommissionRate{}
+	for rows.Next() {
+		var i CommissionRate
+		if err := rows.Scan(&i.SellerType, &i.RatePercent, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingChargebacks = `-- name: ListPendingChargebacks :many
+SELECT id, sub_order_id, amount, reason, created_at, posted_at, created_by FROM chargebacks
+WHERE posted_at IS NULL
+ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListPendingChargebacks(ctx context.Context) ([]Chargeback, error) {
+	rows, err := q.db.Query(ctx, listPendingChargebacks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Chargeback{}
+	for rows.Next() {
+		var i Chargeback
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubOrderID,
+			&i.Amount,
+			&i.Reason,
+			&i.CreatedAt,
+			&i.PostedAt,
+			&i.CreatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSellerLedgerTransactions = `-- name: ListSellerLedgerTransactions :many
+SELECT t.id, t.kind, t.sub_order_id, t.payout_id, t.commission_rate, t.description, t.created_at,
+    COALESCE(-SUM(e.amount) FILTER (WHERE e.account = 'seller_payable'), 0)::NUMERIC(14, 2) AS seller_amount,
+    COALESCE(-SUM(e.amount) FILTER (WHERE e.account = 'platform_commission'), 0)::NUMERIC(14, 2) AS commission
+FROM ledger_transactions t
+JOIN ledger_entries e ON e.transaction_id = t.id
+WHERE t.seller_username = $1
+  AND ($2::TIMESTAMP IS NULL OR t.created_at > $2)
+  AND ($3::TIMESTAMP IS NULL OR t.created_at <= $3)
+GROUP BY t.id
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT $5 OFFSET $4
+`
+
+type ListSellerLedgerTransactionsParams struct {
+	Seller   string           `json:"seller"`
+	FromTime pgtype.Timestamp `json:"from_time"`
+	ToTime   pgtype.Timestamp `json:"to_time"`
+	Offset   int32            `json:"offset"`
+	Limit    int32            `json:"limit"`
+}
+
+type ListSellerLedgerTransactionsRow struct {
+	ID             int64            `json:"id"`
+	Kind           string           `json:"kind"`
+	SubOrderID     pgtype.Int4      `json:"sub_order_id"`
+	PayoutID       pgtype.Int4      `json:"payout_id"`
+	CommissionRate pgtype.Numeric   `json:"commission_rate"`
+	Description    string           `json:"description"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	SellerAmount   pgtype.Numeric   `json:"seller_amount"`
+	Commission     pgtype.Numeric   `json:"commission"`
+}
+
+// The seller's side of each ledger transaction after from (when set) up to
+// and including to (when set), newest first
+func (q *Queries) ListSellerLedgerTransactions(ctx context.Context, arg ListSellerLedgerTransactionsParams) ([]ListSellerLedgerTransactionsRow, error) {
+	rows, err := q.db.Query(ctx, listSellerLedgerTransactions,
+		arg.Seller,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSellerLedgerTransactionsRow{}
+	for rows.Next() {
+		var i ListSellerLedgerTransactionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.SubOrderID,
+			&i.PayoutID,
+			&i.CommissionRate,
+			&i.Description,
+			&i.CreatedAt,
+			&i.SellerAmount,
+			&i.Commission,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSellersWithPayableBalance = `-- name: ListSellersWithPayableBalance :many
+SELECT t.seller_username, (-SUM(e.amount))::NUMERIC(14, 2) AS balance
+FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+LEFT JOIN payouts p ON p.id = t.payout_id
+WHERE e.account = 'seller_payable'
+  AND COALESCE(p.period_end, t.created_at) <= $1::TIMESTAMP
+GROUP BY t.seller_username
+HAVING -SUM(e.amount) > 0
+ORDER BY t.seller_username ASC
+`
+
+type ListSellersWithPayableBalanceRow struct {
+	SellerUsername string         `json:"seller_username"`
+	Balance        pgtype.Numeric `json:"balance"`
+}
+
+func (q *Queries) ListSellersWithPayableBalance(ctx context.Context, asOf pgtype.Timestamp) ([]ListSellersWithPayableBalanceRow, error) {
+	rows, err := q.db.Query(ctx, listSellersWithPayableBalance, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSellersWithPayableBalanceRow{}
+	for rows.Next() {
+		var i ListSellersWithPayableBalanceRow
+		if err := rows.Scan(&i.SellerUsername, &i.Balance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markChargebackPosted = `-- name: MarkChargebackPosted :exec
+UPDATE chargebacks SET posted_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkChargebackPosted(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, markChargebackPosted, id)
+	return err
+}
+
+const updateCommissionRate = `-- name: UpdateCommissionRate :one
+UPDATE commission_rates SET
+    rate_percent = $1,
+    updated_at = now()
+WHERE seller_type = $2
+RETURNING seller_type, rate_percent, updated_at
+`
+
+type UpdateCommissionRateParams struct {
+	RatePercent pgtype.Numeric `json:"rate_percent"`
+	SellerType  string         `json:"seller_type"`
+}
+
+func (q *Queries) UpdateCommissionRate(ctx context.Context, arg UpdateCommissionRateParams) (CommissionRate, error) {
+	row := q.db.QueryRow(ctx, updateCommissionRate, arg.RatePercent, arg.SellerType)
+	var i CommissionRate
+	err := row.Scan(&i.SellerType, &i.RatePercent, &i.UpdatedAt)
+	return i, err
+}
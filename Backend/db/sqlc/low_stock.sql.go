@@ -0,0 +1,163 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: low_stock.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createLowStockAlert = `-- name: CreateLowStockAlert :exec
+INSERT INTO low_stock_alerts (
+    medicine_id, seller_username, quantity
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (medicine_id) DO NOTHING
+`
+
+type CreateLowStockAlertParams struct {
+	MedicineID     int32  `json:"medicine_id"`
+	SellerUsername string `json:"seller_username"`
+	Quantity       int32  `json:"quantity"`
+}
+
+func (q *Queries) CreateLowStockAlert(ctx context.Context, arg CreateLowStockAlertParams) error {
+	_, err := q.db.Exec(ctx, createLowStockAlert, arg.MedicineID, arg.SellerUsername, arg.Quantity)
+	return err
+}
+
+const deleteResolvedLowStockAlerts = `-- name: DeleteResolvedLowStockAlerts :execrows
+DELETE FROM low_stock_alerts a
+USING medicines m
+WHERE m.id = a.medicine_id
+  AND (m.status <> 'active' OR m.reorder_level IS NULL OR m.quantity > m.reorder_level)
+`
+
+// Clears alerts of medicines that were restocked, taken off sale or no
+// longer have a reorder level, so they are alerted on again next time
+func (q *Queries) DeleteResolvedLowStockAlerts(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteResolvedLowStockAlerts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listLowStockMedicines = `-- name: ListLowStockMedicines :many
+SELECT m.id, m.name, m.batch_number, m.seller_username, m.quantity,
+    m.reorder_level::INT AS reorder_level, m.expiry_date,
+    COALESCE((
+        SELECT -SUM(sm.quantity_change) FROM stock_movements sm
+        WHERE sm.medicine_id = m.id
+          AND sm.movement_type = 'sale'
+          AND sm.created_at >= $1
+    ), 0)::INT AS units_sold,
+    EXISTS (
+        SELECT 1 FROM low_stock_alerts a WHERE a.medicine_id = m.id
+    ) AS alerted
+FROM medicines m
+WHERE m.status = 'active'
+  AND m.reorder_level IS NOT NULL
+  AND m.quantity <= m.reorder_level
+  AND m.expiry_date > CURRENT_DATE
+  AND ($2::VARCHAR IS NULL OR m.seller_username = $2)
+ORDER BY m.seller_username ASC, m.quantity ASC, m.name ASC
+`
+
+type ListLowStockMedicinesParams struct {
+	SalesSince pgtype.Timestamp `json:"sales_since"`
+	Seller     pgtype.Text      `json:"seller"`
+}
+
+type ListLowStockMedicinesRow struct {
+	ID             int32       `json:"id"`
+	Name           string      `json:"name"`
+	BatchNumber    string      `json:"batch_number"`
+	SellerUsername string      `json:"seller_username"`
+	Quantity       int32       `json:"quantity"`
+	ReorderLevel   int32       `json:"reorder_level"`
+	ExpiryDate     pgtype.Date `json:"expiry_date"`
+	UnitsSold      int32       `json:"units_sold"`
+	Alerted        bool        `json:"alerted"`
+}
+
+// Medicines on sale at or below their reorder level, with the units sold
+// since sales_since. A null seller lists every seller's medicines.
+func (q *Queries) ListLowStockMedicines(ctx context.Context, arg ListLowStockMedicinesParams) ([]ListLowStockMedicinesRow, error) {
+	rows, err := q.db.Query(ctx, listLowStockMedicines, arg.SalesSince, arg.Seller)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLowStockMedicinesRow{}
+	for rows.Next() {
+		var i ListLowStockMedicinesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.BatchNumber,
+			&i.SellerUsername,
+			&i.Quantity,
+			&i.ReorderLevel,
+			&i.ExpiryDate,
+			&i.UnitsSold,
+			&i.Alerted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setMedicineReorderLevel = `-- name: SetMedicineReorderLevel :one
+UPDATE medicines SET reorder_level = $1
+WHERE id = $2
+RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at
+`
+
+type SetMedicineReorderLevelParams struct {
+	ReorderLevel pgtype.Int4 `json:"reorder_level"`
+	ID           int32       `json:"id"`
+}
+
+func (q *Queries) SetMedicineReorderLevel(ctx context.Context, arg SetMedicineReorderLevelParams) (Medicine, error) {
+	row := q.db.QueryRow(ctx, setMedicineReorderLevel, arg.ReorderLevel, arg.ID)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
@@ -1,277 +1,1106 @@
-// Code generated by sqlc. DO NOT EDIT.
-// versions:
-//   sqlc v1.29.0
-// source: medicine.sql
-
-package db
-
-import (
-	"context"
-
-	"github.com/jackc/pgx/v5/pgtype"
-)
-
-const createMedicine = `-- name: CreateMedicine :one
-INSERT INTO medicines (
-    name, description, expiry_date, quantity, price, discount, seller_username
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-)
-RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at
-`
-
-type CreateMedicineParams struct {
-	Name           string         `json:"name"`
-	Description    string         `json:"description"`
-	ExpiryDate     pgtype.Date    `json:"expiry_date"`
-	Quantity       int32          `json:"quantity"`
-	Price          pgtype.Numeric `json:"price"`
-	Discount       int32          `json:"discount"`
-	SellerUsername string         `json:"seller_username"`
-}
-
-func (q *Queries) CreateMedicine(ctx context.Context, arg CreateMedicineParams) (Medicine, error) {
-	row := q.db.QueryRow(ctx, createMedicine,
-		arg.Name,
-		arg.Description,
-		arg.ExpiryDate,
-		arg.Quantity,
-		arg.Price,
-		arg.Discount,
-		arg.SellerUsername,
-	)
-	var i Medicine
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.ExpiryDate,
-		&i.Quantity,
-		&i.Price,
-		&i.Discount,
-		&i.SellerUsername,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const deleteMedicine = `-- name: DeleteMedicine :one
-DELETE FROM medicines WHERE id = $1
-RETURNING id
-`
-
-func (q *Queries) DeleteMedicine(ctx context.Context, id int32) (int32, error) {
-	row := q.db.QueryRow(ctx, deleteMedicine, id)
-	err := row.Scan(&id)
-	return id, err
-}
-
-const getMedicine = `-- name: GetMedicine :one
-SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at FROM medicines WHERE id = $1
-`
-
-func (q *Queries) GetMedicine(ctx context.Context, id int32) (Medicine, error) {
-	row := q.db.QueryRow(ctx, getMedicine, id)
-	var i Medicine
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.ExpiryDate,
-		&i.Quantity,
-		&i.Price,
-		&i.Discount,
-		&i.SellerUsername,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const getMedicineByName = `-- name: GetMedicineByName :one
-SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at FROM medicines WHERE name = $1
-`
-
-func (q *Queries) GetMedicineByName(ctx context.Context, name string) (Medicine, error) {
-	row := q.db.QueryRow(ctx, getMedicineByName, name)
-	var i Medicine
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.ExpiryDate,
-		&i.Quantity,
-		&i.Price,
-		&i.Discount,
-		&i.SellerUsername,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const listAllMedicines = `-- name: ListAllMedicines :many
-SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at FROM medicines
-ORDER BY id ASC
-`
-
-func (q *Queries) ListAllMedicines(ctx context.Context) ([]Medicine, error) {
-	rows, err := q.db.Query(ctx, listAllMedicines)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Medicine{}
-	for rows.Next() {
-		var i Medicine
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.ExpiryDate,
-			&i.Quantity,
-			&i.Price,
-			&i.Discount,
-			&i.SellerUsername,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const listSellerMedicinesByExpiry = `-- name: ListSellerMedicinesByExpiry :many
-SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at FROM medicines
-WHERE seller_username = $1
-ORDER BY expiry_date ASC
-LIMIT $2 OFFSET $3
-`
-
-type ListSellerMedicinesByExpiryParams struct {
-	SellerUsername string `json:"seller_username"`
-	Limit          int32  `json:"limit"`
-	Offset         int32  `json:"offset"`
-}
-
-func (q *Queries) ListSellerMedicinesByExpiry(ctx context.Context, arg ListSellerMedicinesByExpiryParams) ([]Medicine, error) {
-	rows, err := q.db.Query(ctx, listSellerMedicinesByExpiry, arg.SellerUsername, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Medicine{}
-	for rows.Next() {
-		var i Medicine
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.ExpiryDate,
-			&i.Quantity,
-			&i.Price,
-			&i.Discount,
-			&i.SellerUsername,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const searchMedicinesByNameSortedByPrice = `-- name: SearchMedicinesByNameSortedByPrice :many
-SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at FROM medicines
-WHERE name ILIKE '%' || $1 || '%'
-ORDER BY price ASC
-LIMIT $3 OFFSET $2
-`
-
-type SearchMedicinesByNameSortedByPriceParams struct {
-	Name   pgtype.Text `json:"name"`
-	Offset int32       `json:"offset"`
-	Limit  int32       `json:"limit"`
-}
-
-func (q *Queries) SearchMedicinesByNameSortedByPrice(ctx context.Context, arg SearchMedicinesByNameSortedByPriceParams) ([]Medicine, error) {
-	rows, err := q.db.Query(ctx, searchMedicinesByNameSortedByPrice, arg.Name, arg.Offset, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Medicine{}
-	for rows.Next() {
-		var i Medicine
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.ExpiryDate,
-			&i.Quantity,
-			&i.Price,
-			&i.Discount,
-			&i.SellerUsername,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const updateMedicine = `-- name: UpdateMedicine :one
-UPDATE medicines SET
-    name = COALESCE($1, name),
-    description = COALESCE($2, description),
-    expiry_date = COALESCE($3, expiry_date),
-    quantity = COALESCE($4, quantity),
-    price = COALESCE($5, price),
-    discount = COALESCE($6, discount)
-WHERE id = $7
-RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at
-`
-
-type UpdateMedicineParams struct {
-	Name        pgtype.Text    `json:"name"`
-	Description pgtype.Text    `json:"description"`
-	ExpiryDate  pgtype.Date    `json:"expiry_date"`
-	Quantity    pgtype.Int4    `json:"quantity"`
-	Price       pgtype.Numeric `json:"price"`
-	Discount    pgtype.Int4    `json:"discount"`
-	ID          int32          `json:"id"`
-}
-
-func (q *Queries) UpdateMedicine(ctx context.Context, arg UpdateMedicineParams) (Medicine, error) {
-	row := q.db.QueryRow(ctx, updateMedicine,
-		arg.Name,
-		arg.Description,
-		arg.ExpiryDate,
-		arg.Quantity,
-		arg.Price,
-		arg.Discount,
-		arg.ID,
-	)
-	var i Medicine
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.ExpiryDate,
-		&i.Quantity,
-		&i.Price,
-		&i.Discount,
-		&i.SellerUsername,
-		&i.CreatedAt,
-	)
-	return i, err
-}
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: medicine.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createMedicine = `-- name: CreateMedicine :one
+INSERT INTO medicines (
+    name, description, expiry_date, quantity, price, discount, seller_username,
+    active_ingredients, strength, dosage_form, prescription_required, batch_number
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7,
+    $8, $9, $10, $11, $12
+)
+RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at
+`
+
+type CreateMedicineParams struct {
+	Name                 string         `json:"name"`
+	Description          string         `json:"description"`
+	ExpiryDate           pgtype.Date    `json:"expiry_date"`
+	Quantity             int32          `json:"quantity"`
+	Price                pgtype.Numeric `json:"price"`
+	Discount             int32          `json:"discount"`
+	SellerUsername       string         `json:"seller_username"`
+	ActiveIngredients    string         `json:"active_ingredients"`
+	Strength             string         `json:"strength"`
+	DosageForm           string         `json:"dosage_form"`
+	PrescriptionRequired bool           `json:"prescription_required"`
+	BatchNumber          string         `json:"batch_number"`
+}
+
+func (q *Queries) CreateMedicine(ctx context.Context, arg CreateMedicineParams) (Medicine, error) {
+	row := q.db.QueryRow(ctx, createMedicine,
+		arg.Name,
+		arg.Description,
+		arg.ExpiryDate,
+		arg.Quantity,
+		arg.Price,
+		arg.Discount,
+		arg.SellerUsername,
+		arg.ActiveIngredients,
+		arg.Strength,
+		arg.DosageForm,
+		arg.PrescriptionRequired,
+		arg.BatchNumber,
+	)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const getMedicine = `-- name: GetMedicine :one
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines WHERE id = $1
+`
+
+func (q *Queries) GetMedicine(ctx context.Context, id int32) (Medicine, error) {
+	row := q.db.QueryRow(ctx, getMedicine, id)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const getMedicineByName = `-- name: GetMedicineByName :one
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines WHERE name = $1
+`
+
+func (q *Queries) GetMedicineByName(ctx context.Context, name string) (Medicine, error) {
+	row := q.db.QueryRow(ctx, getMedicineByName, name)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const getMedicineForUpdate = `-- name: GetMedicineForUpdate :one
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetMedicineForUpdate(ctx context.Context, id int32) (Medicine, error) {
+	row := q.db.QueryRow(ctx, getMedicineForUpdate, id)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const getSellerMedicineByNameAndBatch = `-- name: GetSellerMedicineByNameAndBatch :one
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines
+WHERE seller_username = $1 AND name = $2 AND batch_number = $3
+  AND status = 'active'
+ORDER BY id ASC
+LIMIT 1
+`
+
+type GetSellerMedicineByNameAndBatchParams struct {
+	SellerUsername string `json:"seller_username"`
+	Name           string `json:"name"`
+	BatchNumber    string `json:"batch_number"`
+}
+
+func (q *Queries) GetSellerMedicineByNameAndBatch(ctx context.Context, arg GetSellerMedicineByNameAndBatchParams) (Medicine, error) {
+	row := q.db.QueryRow(ctx, getSellerMedicineByNameAndBatch, arg.SellerUsername, arg.Name, arg.BatchNumber)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const listActiveMedicines = `-- name: ListActiveMedicines :many
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines
+WHERE status = 'active'
+ORDER BY id ASC
+`
+
+func (q *Queries) ListActiveMedicines(ctx context.Context) ([]Medicine, error) {
+	rows, err := q.db.Query(ctx, listActiveMedicines)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Medicine{}
+	for rows.Next() {
+		var i Medicine
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.SellerUsername,
+			&i.CreatedAt,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.Status,
+			&i.QuarantinedQuantity,
+			&i.QuarantinedAt,
+			&i.OriginalDiscount,
+			&i.MarkdownRuleID,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.ReorderLevel,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.WithdrawnAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllMedicines = `-- name: ListAllMedicines :many
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines
+ORDER BY id ASC
+`
+
+func (q *Queries) ListAllMedicines(ctx context.Context) ([]Medicine, error) {
+	rows, err := q.db.Query(ctx, listAllMedicines)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Medicine{}
+	for rows.Next() {
+		var i Medicine
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.SellerUsername,
+			&i.CreatedAt,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.Status,
+			&i.QuarantinedQuantity,
+			&i.QuarantinedAt,
+			&i.OriginalDiscount,
+			&i.MarkdownRuleID,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.ReorderLevel,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.WithdrawnAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllSellerMedicines = `-- name: ListAllSellerMedicines :many
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines
+WHERE seller_username = $1 AND status = 'active'
+ORDER BY name ASC, expiry_date ASC, id ASC
+`
+
+func (q *Queries) ListAllSellerMedicines(ctx context.Context, sellerUsername string) ([]Medicine, error) {
+	rows, err := q.db.Query(ctx, listAllSellerMedicines, sellerUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Medicine{}
+	for rows.Next() {
+		var i Medicine
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.SellerUsername,
+			&i.CreatedAt,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.Status,
+			&i.QuarantinedQuantity,
+			&i.QuarantinedAt,
+			&i.OriginalDiscount,
+			&i.MarkdownRuleID,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.ReorderLevel,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.WithdrawnAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMedicineSubstitutes = `-- name: ListMedicineSubstitutes :many
+SELECT m.id, m.name, m.description, m.expiry_date, m.quantity, m.price, m.discount, m.seller_username, m.created_at, m.active_ingredients, m.strength, m.dosage_form, m.prescription_required, m.batch_number, m.status, m.quarantined_quantity, m.quarantined_at, m.original_discount, m.markdown_rule_id, m.trade_price, m.min_order_quantity, m.reorder_level, m.rating_average, m.review_count, m.withdrawn_at, (m.price * (100 - m.discount) / 100)::NUMERIC(10, 2) AS effective_price
+FROM medicines m
+JOIN medicines src ON src.id = $1
+WHERE m.id <> src.id
+  AND src.active_ingredients <> ''
+  AND m.active_ingredients = src.active_ingredients
+  AND m.strength = src.strength
+  AND m.dosage_form = src.dosage_form
+  AND m.quantity > 0
+  AND m.expiry_date > CURRENT_DATE
+  AND m.status = 'active'
+ORDER BY effective_price ASC, m.expiry_date DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListMedicineSubstitutesParams struct {
+	ID     int32 `json:"id"`
+	Offset int32 `json:"offset"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListMedicineSubstitutesRow struct {
+	ID                   int32            `json:"id"`
+	Name                 string           `json:"name"`
+	Description          string           `json:"description"`
+	ExpiryDate           pgtype.Date      `json:"expiry_date"`
+	Quantity             int32            `json:"quantity"`
+	Price                pgtype.Numeric   `json:"price"`
+	Discount             int32            `json:"discount"`
+	SellerUsername       string           `json:"seller_username"`
+	CreatedAt            pgtype.Timestamp `json:"created_at"`
+	ActiveIngredients    string           `json:"active_ingredients"`
+	Strength             string           `json:"strength"`
+	DosageForm           string           `json:"dosage_form"`
+	PrescriptionRequired bool             `json:"prescription_required"`
+	BatchNumber          string           `json:"batch_number"`
+	Status               string           `json:"status"`
+	QuarantinedQuantity  int32            `json:"quarantined_quantity"`
+	QuarantinedAt        pgtype.Timestamp `json:"quarantined_at"`
+	OriginalDiscount     pgtype.Int4      `json:"original_discount"`
+	MarkdownRuleID       pgtype.Int4      `json:"markdown_rule_id"`
+	TradePrice           pgtype.Numeric   `json:"trade_price"`
+	MinOrderQuantity     int32            `json:"min_order_quantity"`
+	ReorderLevel         pgtype.Int4      `json:"reorder_level"`
+	RatingAverage        pgtype.Numeric   `json:"rating_average"`
+	ReviewCount          int32            `json:"review_count"`
+	WithdrawnAt          pgtype.Timestamp `json:"withdrawn_at"`
+	EffectivePrice       pgtype.Numeric   `json:"effective_price"`
+}
+
+func (q *Queries) ListMedicineSubstitutes(ctx context.Context, arg ListMedicineSubstitutesParams) ([]ListMedicineSubstitutesRow, error) {
+	rows, err := q.db.Query(ctx, listMedicineSubstitutes, arg.ID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMedicineSubstitutesRow{}
+	for rows.Next() {
+		var i ListMedicineSubstitutesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.SellerUsername,
+			&i.CreatedAt,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.Status,
+			&i.QuarantinedQuantity,
+			&i.QuarantinedAt,
+			&i.OriginalDiscount,
+			&i.MarkdownRuleID,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.ReorderLevel,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.WithdrawnAt,
+			&i.EffectivePrice,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuarantinedMedicines = `-- name: ListQuarantinedMedicines :many
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines
+WHERE seller_username = $1 AND status = 'quarantined'
+ORDER BY quarantined_at ASC
+LIMIT $2 OFFSET $3
+`
+
+type ListQuarantinedMedicinesParams struct {
+	SellerUsername string `json:"seller_username"`
+	Limit          int32  `json:"limit"`
+	Offset         int32  `json:"offset"`
+}
+
+func (q *Queries) ListQuarantinedMedicines(ctx context.Context, arg ListQuarantinedMedicinesParams) ([]Medicine, error) {
+	rows, err := q.db.Query(ctx, listQuarantinedMedicines, arg.SellerUsername, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Medicine{}
+	for rows.Next() {
+		var i Medicine
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.SellerUsername,
+			&i.CreatedAt,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.Status,
+			&i.QuarantinedQuantity,
+			&i.QuarantinedAt,
+			&i.OriginalDiscount,
+			&i.MarkdownRuleID,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.ReorderLevel,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.WithdrawnAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSellerMedicinesByExpiry = `-- name: ListSellerMedicinesByExpiry :many
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at FROM medicines
+WHERE seller_username = $1 AND status = 'active'
+ORDER BY expiry_date ASC
+LIMIT $2 OFFSET $3
+`
+
+type ListSellerMedicinesByExpiryParams struct {
+	SellerUsername string `json:"seller_username"`
+	Limit          int32  `json:"limit"`
+	Offset         int32  `json:"offset"`
+}
+
+func (q *Queries) ListSellerMedicinesByExpiry(ctx context.Context, arg ListSellerMedicinesByExpiryParams) ([]Medicine, error) {
+	rows, err := q.db.Query(ctx, listSellerMedicinesByExpiry, arg.SellerUsername, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Medicine{}
+	for rows.Next() {
+		var i Medicine
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.SellerUsername,
+			&i.CreatedAt,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.Status,
+			&i.QuarantinedQuantity,
+			&i.QuarantinedAt,
+			&i.OriginalDiscount,
+			&i.MarkdownRuleID,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.ReorderLevel,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.WithdrawnAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markMedicineDisposed = `-- name: MarkMedicineDisposed :one
+UPDATE medicines SET
+    status = 'disposed',
+    quarantined_quantity = 0
+WHERE id = $1 AND status = 'quarantined'
+RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at
+`
+
+func (q *Queries) MarkMedicineDisposed(ctx context.Context, id int32) (Medicine, error) {
+	row := q.db.QueryRow(ctx, markMedicineDisposed, id)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const quarantineMedicine = `-- name: QuarantineMedicine :one
+UPDATE medicines SET
+    status = 'quarantined',
+    quarantined_quantity = $2,
+    quarantined_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at
+`
+
+type QuarantineMedicineParams struct {
+	ID                  int32 `json:"id"`
+	QuarantinedQuantity int32 `json:"quarantined_quantity"`
+}
+
+func (q *Queries) QuarantineMedicine(ctx context.Context, arg QuarantineMedicineParams) (Medicine, error) {
+	row := q.db.QueryRow(ctx, quarantineMedicine, arg.ID, arg.QuarantinedQuantity)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const searchMedicineFacets = `-- name: SearchMedicineFacets :many
+WITH matches AS (
+    SELECT m.quantity, m.expiry_date, m.prescription_required, s.seller_type,
+        (m.price * (100 - m.discount) / 100)::NUMERIC(10, 2) AS effective_price
+    FROM medicines m
+    JOIN sellers s ON s.username = m.seller_username
+    WHERE ($1::VARCHAR = ''
+           OR medicine_search_document(m.name, m.description, m.active_ingredients)
+              @@ websearch_to_tsquery('english', $1)
+           OR m.name % $1
+           OR m.active_ingredients % $1
+           OR m.name ILIKE '%' || $1 || '%')
+      AND m.expiry_date > CURRENT_DATE
+      AND m.status = 'active'
+      AND ($2::NUMERIC IS NULL OR m.price * (100 - m.discount) / 100 >= $2)
+      AND ($3::NUMERIC IS NULL OR m.price * (100 - m.discount) / 100 <= $3)
+      AND ($4::VARCHAR IS NULL OR s.seller_type = $4)
+      AND (NOT $5::BOOLEAN OR m.quantity > 0)
+      AND ($6::INT IS NULL OR m.expiry_date >= CURRENT_DATE + $6::INT)
+      AND ($7::BOOLEAN IS NULL OR m.prescription_required = $7)
+)
+SELECT 'seller_type'::VARCHAR AS facet, seller_type::VARCHAR AS value, COUNT(*) AS count
+FROM matches GROUP BY seller_type
+UNION ALL
+SELECT 'prescription_required', prescription_required::VARCHAR, COUNT(*)
+FROM matches GROUP BY prescription_required
+UNION ALL
+SELECT 'availability', CASE WHEN quantity > 0 THEN 'in_stock' ELSE 'out_of_stock' END, COUNT(*)
+FROM matches GROUP BY 2
+UNION ALL
+SELECT 'price_range',
+    CASE WHEN effective_price < 100 THEN '0-100'
+         WHEN effective_price < 500 THEN '100-500'
+         WHEN effective_price < 1000 THEN '500-1000'
+         ELSE '1000+' END,
+    COUNT(*)
+FROM matches GROUP BY 2
+UNION ALL
+SELECT 'days_to_expiry',
+    CASE WHEN expiry_date < CURRENT_DATE + 30 THEN 'under_30'
+         WHEN expiry_date < CURRENT_DATE + 90 THEN '30-90'
+         WHEN expiry_date < CURRENT_DATE + 180 THEN '90-180'
+         ELSE '180+' END,
+    COUNT(*)
+FROM matches GROUP BY 2
+`
+
+type SearchMedicineFacetsParams struct {
+	Query                string         `json:"query"`
+	MinPrice             pgtype.Numeric `json:"min_price"`
+	MaxPrice             pgtype.Numeric `json:"max_price"`
+	SellerType           pgtype.Text    `json:"seller_type"`
+	InStockOnly          bool           `json:"in_stock_only"`
+	MinDaysToExpiry      pgtype.Int4    `json:"min_days_to_expiry"`
+	PrescriptionRequired pgtype.Bool    `json:"prescription_required"`
+}
+
+type SearchMedicineFacetsRow struct {
+	Facet string `json:"facet"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+func (q *Queries) SearchMedicineFacets(ctx context.Context, arg SearchMedicineFacetsParams) ([]SearchMedicineFacetsRow, error) {
+	rows, err := q.db.Query(ctx, searchMedicineFacets,
+		arg.Query,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.SellerType,
+		arg.InStockOnly,
+		arg.MinDaysToExpiry,
+		arg.PrescriptionRequired,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchMedicineFacetsRow{}
+	for rows.Next() {
+		var i SearchMedicineFacetsRow
+		if err := rows.Scan(&i.Facet, &i.Value, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchMedicines = `-- name: SearchMedicines :many
+SELECT id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at, seller_type, store_name, seller_rating_average, seller_review_count, effective_price, relevance FROM (
+    SELECT m.id, m.name, m.description, m.expiry_date, m.quantity, m.price, m.discount, m.seller_username, m.created_at, m.active_ingredients, m.strength, m.dosage_form, m.prescription_required, m.batch_number, m.status, m.quarantined_quantity, m.quarantined_at, m.original_discount, m.markdown_rule_id, m.trade_price, m.min_order_quantity, m.reorder_level, m.rating_average, m.review_count, m.withdrawn_at, s.seller_type, s.store_name,
+        s.rating_average AS seller_rating_average, s.review_count AS seller_review_count,
+        (m.price * (100 - m.discount) / 100)::NUMERIC(10, 2) AS effective_price,
+        (CASE WHEN $1::VARCHAR = '' THEN 0
+              ELSE ts_rank(medicine_search_document(m.name, m.description, m.active_ingredients),
+                           websearch_to_tsquery('english', $1))
+                   + similarity(m.name, $1)
+         END)::FLOAT8 AS relevance
+    FROM medicines m
+    JOIN sellers s ON s.username = m.seller_username
+    WHERE ($1 = ''
+           OR medicine_search_document(m.name, m.description, m.active_ingredients)
+              @@ websearch_to_tsquery('english', $1)
+           OR m.name % $1
+           OR m.active_ingredients % $1
+           OR m.name ILIKE '%' || $1 || '%')
+      AND m.expiry_date > CURRENT_DATE
+      AND m.status = 'active'
+      AND ($2::NUMERIC IS NULL OR m.price * (100 - m.discount) / 100 >= $2)
+      AND ($3::NUMERIC IS NULL OR m.price * (100 - m.discount) / 100 <= $3)
+      AND ($4::VARCHAR IS NULL OR s.seller_type = $4)
+      AND (NOT $5::BOOLEAN OR m.quantity > 0)
+      AND ($6::INT IS NULL OR m.expiry_date >= CURRENT_DATE + $6::INT)
+      AND ($7::BOOLEAN IS NULL OR m.prescription_required = $7)
+) r
+ORDER BY
+    CASE WHEN $8::VARCHAR = 'relevance' THEN r.relevance END DESC,
+    CASE WHEN $8 = 'price_desc' THEN r.effective_price END DESC,
+    CASE WHEN $8 = 'expiry' THEN r.expiry_date END DESC,
+    CASE WHEN $8 = 'newest' THEN r.created_at END DESC,
+    r.effective_price ASC,
+    r.id ASC
+LIMIT $10 OFFSET $9
+`
+
+type SearchMedicinesParams struct {
+	Query                string         `json:"query"`
+	MinPrice             pgtype.Numeric `json:"min_price"`
+	MaxPrice             pgtype.Numeric `json:"max_price"`
+	SellerType           pgtype.Text    `json:"seller_type"`
+	InStockOnly          bool           `json:"in_stock_only"`
+	MinDaysToExpiry      pgtype.Int4    `json:"min_days_to_expiry"`
+	PrescriptionRequired pgtype.Bool    `json:"prescription_required"`
+	SortBy               string         `json:"sort_by"`
+	Offset               int32          `json:"offset"`
+	Limit                int32          `json:"limit"`
+}
+
+type SearchMedicinesRow struct {
+	ID                   int32            `json:"id"`
+	Name                 string           `json:"name"`
+	Description          string           `json:"description"`
+	ExpiryDate           pgtype.Date      `json:"expiry_date"`
+	Quantity             int32            `json:"quantity"`
+	Price                pgtype.Numeric   `json:"price"`
+	Discount             int32            `json:"discount"`
+	SellerUsername       string           `json:"seller_username"`
+	CreatedAt            pgtype.Timestamp `json:"created_at"`
+	ActiveIngredients    string           `json:"active_ingredients"`
+	Strength             string           `json:"strength"`
+	DosageForm           string           `json:"dosage_form"`
+	PrescriptionRequired bool             `json:"prescription_required"`
+	BatchNumber          string           `json:"batch_number"`
+	Status               string           `json:"status"`
+	QuarantinedQuantity  int32            `json:"quarantined_quantity"`
+	QuarantinedAt        pgtype.Timestamp `json:"quarantined_at"`
+	OriginalDiscount     pgtype.Int4      `json:"original_discount"`
+	MarkdownRuleID       pgtype.Int4      `json:"markdown_rule_id"`
+	TradePrice           pgtype.Numeric   `json:"trade_price"`
+	MinOrderQuantity     int32            `json:"min_order_quantity"`
+	ReorderLevel         pgtype.Int4      `json:"reorder_level"`
+	RatingAverage        pgtype.Numeric   `json:"rating_average"`
+	ReviewCount          int32            `json:"review_count"`
+	WithdrawnAt          pgtype.Timestamp `json:"withdrawn_at"`
+	SellerType           string           `json:"seller_type"`
+	StoreName            string           `json:"store_name"`
+	SellerRatingAverage  pgtype.Numeric   `json:"seller_rating_average"`
+	SellerReviewCount    int32            `json:"seller_review_count"`
+	EffectivePrice       pgtype.Numeric   `json:"effective_price"`
+	Relevance            float64          `json:"relevance"`
+}
+
+func (q *Queries) SearchMedicines(ctx context.Context, arg SearchMedicinesParams) ([]SearchMedicinesRow, error) {
+	rows, err := q.db.Query(ctx, searchMedicines,
+		arg.Query,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.SellerType,
+		arg.InStockOnly,
+		arg.MinDaysToExpiry,
+		arg.PrescriptionRequired,
+		arg.SortBy,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchMedicinesRow{}
+	for rows.Next() {
+		var i SearchMedicinesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.SellerUsername,
+			&i.CreatedAt,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.Status,
+			&i.QuarantinedQuantity,
+			&i.QuarantinedAt,
+			&i.OriginalDiscount,
+			&i.MarkdownRuleID,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.ReorderLevel,
+			&i.RatingAverage,
+			&i.ReviewCount,
+			&i.WithdrawnAt,
+			&i.SellerType,
+			&i.StoreName,
+			&i.SellerRatingAverage,
+			&i.SellerReviewCount,
+			&i.EffectivePrice,
+			&i.Relevance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateMedicine = `-- name: UpdateMedicine :one
+UPDATE medicines SET
+    name = COALESCE($1, name),
+    description = COALESCE($2, description),
+    expiry_date = COALESCE($3, expiry_date),
+    price = COALESCE($4, price),
+    discount = COALESCE($5, discount),
+    -- a new discount set by the seller replaces any markdown; resending the
+    -- current one leaves the markdown in place
+    original_discount = CASE WHEN $5::INT IS NULL OR $5::INT = discount
+                             THEN original_discount END,
+    markdown_rule_id = CASE WHEN $5::INT IS NULL OR $5::INT = discount
+                            THEN markdown_rule_id END,
+    active_ingredients = COALESCE($6, active_ingredients),
+    strength = COALESCE($7, strength),
+    dosage_form = COALESCE($8, dosage_form),
+    prescription_required = COALESCE($9, prescription_required),
+    batch_number = COALESCE($10, batch_number)
+WHERE id = $11
+RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at
+`
+
+type UpdateMedicineParams struct {
+	Name                 pgtype.Text    `json:"name"`
+	Description          pgtype.Text    `json:"description"`
+	ExpiryDate           pgtype.Date    `json:"expiry_date"`
+	Price                pgtype.Numeric `json:"price"`
+	Discount             pgtype.Int4    `json:"discount"`
+	ActiveIngredients    pgtype.Text    `json:"active_ingredients"`
+	Strength             pgtype.Text    `json:"strength"`
+	DosageForm           pgtype.Text    `json:"dosage_form"`
+	PrescriptionRequired pgtype.Bool    `json:"prescription_required"`
+	BatchNumber          pgtype.Text    `json:"batch_number"`
+	ID                   int32          `json:"id"`
+}
+
+func (q *Queries) UpdateMedicine(ctx context.Context, arg UpdateMedicineParams) (Medicine, error) {
+	row := q.db.QueryRow(ctx, updateMedicine,
+		arg.Name,
+		arg.Description,
+		arg.ExpiryDate,
+		arg.Price,
+		arg.Discount,
+		arg.ActiveIngredients,
+		arg.Strength,
+		arg.DosageForm,
+		arg.PrescriptionRequired,
+		arg.BatchNumber,
+		arg.ID,
+	)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const updateMedicineQuantity = `-- name: UpdateMedicineQuantity :one
+UPDATE medicines SET quantity = $2
+WHERE id = $1
+RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at
+`
+
+type UpdateMedicineQuantityParams struct {
+	ID       int32 `json:"id"`
+	Quantity int32 `json:"quantity"`
+}
+
+func (q *Queries) UpdateMedicineQuantity(ctx context.Context, arg UpdateMedicineQuantityParams) (Medicine, error) {
+	row := q.db.QueryRow(ctx, updateMedicineQuantity, arg.ID, arg.Quantity)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
+
+const withdrawMedicine = `-- name: WithdrawMedicine :one
+UPDATE medicines SET
+    status = 'withdrawn',
+    withdrawn_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at
+`
+
+func (q *Queries) WithdrawMedicine(ctx context.Context, id int32) (Medicine, error) {
+	row := q.db.QueryRow(ctx, withdrawMedicine, id)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
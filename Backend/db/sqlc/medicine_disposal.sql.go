@@ -0,0 +1,131 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: medicine_disposal.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createMedicineDisposal = `-- name: CreateMedicineDisposal :one
+INSERT INTO medicine_disposals (
+    medicine_id, seller_username, quantity, method, disposed_at,
+    disposed_by, certificate_number, notes, recorded_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, medicine_id, seller_username, quantity, method, disposed_at, disposed_by, certificate_number, notes, recorded_by, created_at
+`
+
+type CreateMedicineDisposalParams struct {
+	MedicineID        int32            `json:"medicine_id"`
+	SellerUsername    string           `json:"seller_username"`
+	Quantity          int32            `json:"quantity"`
+	Method            string           `json:"method"`
+	DisposedAt        pgtype.Timestamp `json:"disposed_at"`
+	DisposedBy        string           `json:"disposed_by"`
+	CertificateNumber string           `json:"certificate_number"`
+	Notes             string           `json:"notes"`
+	RecordedBy        string           `json:"recorded_by"`
+}
+
+func (q *Queries) CreateMedicineDisposal(ctx context.Context, arg CreateMedicineDisposalParams) (MedicineDisposal, error) {
+	row := q.db.QueryRow(ctx, createMedicineDisposal,
+		arg.MedicineID,
+		arg.SellerUsername,
+		arg.Quantity,
+		arg.Method,
+		arg.DisposedAt,
+		arg.DisposedBy,
+		arg.CertificateNumber,
+		arg.Notes,
+		arg.RecordedBy,
+	)
+	var i MedicineDisposal
+	err := row.Scan(
+		&i.ID,
+		&i.MedicineID,
+		&i.SellerUsername,
+		&i.Quantity,
+		&i.Method,
+		&i.DisposedAt,
+		&i.DisposedBy,
+		&i.CertificateNumber,
+		&i.Notes,
+		&i.RecordedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMedicineDisposal = `-- name: GetMedicineDisposal :one
+SELECT id, medicine_id, seller_username, quantity, method, disposed_at, disposed_by, certificate_number, notes, recorded_by, created_at FROM medicine_disposals WHERE medicine_id = $1
+`
+
+func (q *Queries) GetMedicineDisposal(ctx context.Context, medicineID int32) (MedicineDisposal, error) {
+	row := q.db.QueryRow(ctx, getMedicineDisposal, medicineID)
+	var i MedicineDisposal
+	err := row.Scan(
+		&i.ID,
+		&i.MedicineID,
+		&i.SellerUsername,
+		&i.Quantity,
+		&i.Method,
+		&i.DisposedAt,
+		&i.DisposedBy,
+		&i.CertificateNumber,
+		&i.Notes,
+		&i.RecordedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listMedicineDisposals = `-- name: ListMedicineDisposals :many
+SELECT id, medicine_id, seller_username, quantity, method, disposed_at, disposed_by, certificate_number, notes, recorded_by, created_at FROM medicine_disposals
+WHERE seller_username = $1
+ORDER BY disposed_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListMedicineDisposalsParams struct {
+	SellerUsername string `json:"seller_username"`
+	Limit          int32  `json:"limit"`
+	Offset         int32  `json:"offset"`
+}
+
+func (q *Queries) ListMedicineDisposals(ctx context.Context, arg ListMedicineDisposalsParams) ([]MedicineDisposal, error) {
+	rows, err := q.db.Query(ctx, listMedicineDisposals, arg.SellerUsername, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MedicineDisposal{}
+	for rows.Next() {
+		var i MedicineDisposal
+		if err := rows.Scan(
+			&i.ID,
+			&i.MedicineID,
+			&i.SellerUsername,
+			&i.Quantity,
+			&i.Method,
+			&i.DisposedAt,
+			&i.DisposedBy,
+			&i.CertificateNumber,
+			&i.Notes,
+			&i.RecordedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,191 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: medicine_import.sql
+
+package db
+
+import (
+	"context"
+)
+
+const completeMedicineImportJob = `-- name: CompleteMedicineImportJob :one
+UPDATE medicine_import_jobs SET
+    status = $1,
+    total_rows = $2,
+    created_rows = $3,
+    updated_rows = $4,
+    failed_rows = $5,
+    row_errors = $6,
+    completed_at = now()
+WHERE id = $7
+RETURNING id, seller_username, file_name, file_format, upsert, status, total_rows, created_rows, updated_rows, failed_rows, row_errors, created_at, completed_at
+`
+
+type CompleteMedicineImportJobParams struct {
+	Status      string `json:"status"`
+	TotalRows   int32  `json:"total_rows"`
+	CreatedRows int32  `json:"created_rows"`
+	UpdatedRows int32  `json:"updated_rows"`
+	FailedRows  int32  `json:"failed_rows"`
+	RowErrors   []byte `json:"row_errors"`
+	ID          int32  `json:"id"`
+}
+
+func (q *Queries) CompleteMedicineImportJob(ctx context.Context, arg CompleteMedicineImportJobParams) (MedicineImportJob, error) {
+	row := q.db.QueryRow(ctx, completeMedicineImportJob,
+		arg.Status,
+		arg.TotalRows,
+		arg.CreatedRows,
+		arg.UpdatedRows,
+		arg.FailedRows,
+		arg.RowErrors,
+		arg.ID,
+	)
+	var i MedicineImportJob
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.FileName,
+		&i.FileFormat,
+		&i.Upsert,
+		&i.Status,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.RowErrors,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const createMedicineImportJob = `-- name: CreateMedicineImportJob :one
+INSERT INTO medicine_import_jobs (
+    seller_username, file_name, file_format, upsert
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, seller_username, file_name, file_format, upsert, status, total_rows, created_rows, updated_rows, failed_rows, row_errors, created_at, completed_at
+`
+
+type CreateMedicineImportJobParams struct {
+	SellerUsername string `json:"seller_username"`
+	FileName       string `json:"file_name"`
+	FileFormat     string `json:"file_format"`
+	Upsert         bool   `json:"upsert"`
+}
+
+func (q *Queries) CreateMedicineImportJob(ctx context.Context, arg CreateMedicineImportJobParams) (MedicineImportJob, error) {
+	row := q.db.QueryRow(ctx, createMedicineImportJob,
+		arg.SellerUsername,
+		arg.FileName,
+		arg.FileFormat,
+		arg.Upsert,
+	)
+	var i MedicineImportJob
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.FileName,
+		&i.FileFormat,
+		&i.Upsert,
+		&i.Status,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.RowErrors,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getMedicineImportJob = `-- name: GetMedicineImportJob :one
+SELECT id, seller_username, file_name, file_format, upsert, status, total_rows, created_rows, updated_rows, failed_rows, row_errors, created_at, completed_at FROM medicine_import_jobs WHERE id = $1
+`
+
+func (q *Queries) GetMedicineImportJob(ctx context.Context, id int32) (MedicineImportJob, error) {
+	row := q.db.QueryRow(ctx, getMedicineImportJob, id)
+	var i MedicineImportJob
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.FileName,
+		&i.FileFormat,
+		&i.Upsert,
+		&i.Status,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.RowErrors,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const listMedicineImportJobs = `-- name: ListMedicineImportJobs :many
+SELECT id, seller_username, file_name, file_format, upsert, status, total_rows, created_rows, updated_rows, failed_rows, row_errors, created_at, completed_at FROM medicine_import_jobs
+WHERE seller_username = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListMedicineImportJobsParams struct {
+	SellerUsername string `json:"seller_username"`
+	Limit          int32  `json:"limit"`
+	Offset         int32  `json:"offset"`
+}
+
+func (q *Queries) ListMedicineImportJobs(ctx context.Context, arg ListMedicineImportJobsParams) ([]MedicineImportJob, error) {
+	rows, err := q.db.Query(ctx, listMedicineImportJobs, arg.SellerUsername, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MedicineImportJob{}
+	for rows.Next() {
+		var i MedicineImportJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerUsername,
+			&i.FileName,
+			&i.FileFormat,
+			&i.Upsert,
+			&i.Status,
+			&i.TotalRows,
+			&i.CreatedRows,
+			&i.UpdatedRows,
+			&i.FailedRows,
+			&i.RowErrors,
+			&i.CreatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateMedicineImportJobStatus = `-- name: UpdateMedicineImportJobStatus :exec
+UPDATE medicine_import_jobs SET status = $2
+WHERE id = $1
+`
+
+type UpdateMedicineImportJobStatusParams struct {
+	ID     int32  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) UpdateMedicineImportJobStatus(ctx context.Context, arg UpdateMedicineImportJobStatusParams) error {
+	_, err := q.db.Exec(ctx, updateMedicineImportJobStatus, arg.ID, arg.Status)
+	return err
+}
@@ -0,0 +1,634 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: order.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createOrder = `-- name: CreateOrder :one
+INSERT INTO orders (
+    patient_username, delivery_address, payment_due_by
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, patient_username, delivery_address, total_amount, payment_status, created_at, payment_due_by, paid_payment_id
+`
+
+type CreateOrderParams struct {
+	PatientUsername string           `json:"patient_username"`
+	DeliveryAddress string           `json:"delivery_address"`
+	PaymentDueBy    pgtype.Timestamp `json:"payment_due_by"`
+}
+
+func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
+	row := q.db.QueryRow(ctx, createOrder, arg.PatientUsername, arg.DeliveryAddress, arg.PaymentDueBy)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DeliveryAddress,
+		&i.TotalAmount,
+		&i.PaymentStatus,
+		&i.CreatedAt,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const createOrderItem = `-- name: CreateOrderItem :one
+INSERT INTO order_items (
+    sub_order_id, medicine_id, medicine_name, batch_number, quantity,
+    unit_price, discount, line_total, prescription_required
+) VALUES (
+    $1, $2, $3,
+    $4, $5, $6, $7,
+    ROUND($6::NUMERIC * $5::INT * (100 - $7::INT) / 100, 2),
+    $8
+)
+RETURNING id, sub_order_id, medicine_id, medicine_name, batch_number, quantity, unit_price, discount, line_total, prescription_required
+`
+
+type CreateOrderItemParams struct {
+	SubOrderID           int32          `json:"sub_order_id"`
+	MedicineID           int32          `json:"medicine_id"`
+	MedicineName         string         `json:"medicine_name"`
+	BatchNumber          string         `json:"batch_number"`
+	Quantity             int32          `json:"quantity"`
+	UnitPrice            pgtype.Numeric `json:"unit_price"`
+	Discount             int32          `json:"discount"`
+	PrescriptionRequired bool           `json:"prescription_required"`
+}
+
+func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error) {
+	row := q.db.QueryRow(ctx, createOrderItem,
+		arg.SubOrderID,
+		arg.MedicineID,
+		arg.MedicineName,
+		arg.BatchNumber,
+		arg.Quantity,
+		arg.UnitPrice,
+		arg.Discount,
+		arg.PrescriptionRequired,
+	)
+	var i OrderItem
+	err := row.Scan(
+		&i.ID,
+		&i.SubOrderID,
+		&i.MedicineID,
+		&i.MedicineName,
+		&i.BatchNumber,
+		&i.Quantity,
+		&i.UnitPrice,
+		&i.Discount,
+		&i.LineTotal,
+		&i.PrescriptionRequired,
+	)
+	return i, err
+}
+
+const createSubOrder = `-- name: CreateSubOrder :one
+INSERT INTO sub_orders (
+    order_id, seller_username
+) VALUES (
+    $1, $2
+)
+RETURNING id, order_id, seller_username, status, subtotal, created_at, updated_at, delivered_at
+`
+
+type CreateSubOrderParams struct {
+	OrderID        int32  `json:"order_id"`
+	SellerUsername string `json:"seller_username"`
+}
+
+func (q *Queries) CreateSubOrder(ctx context.Context, arg CreateSubOrderParams) (SubOrder, error) {
+	row := q.db.QueryRow(ctx, createSubOrder, arg.OrderID, arg.SellerUsername)
+	var i SubOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.SellerUsername,
+		&i.Status,
+		&i.Subtotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const expireOrder = `-- name: ExpireOrder :one
+UPDATE orders SET payment_status = 'expired'
+WHERE id = $1 AND payment_status = 'pending'
+RETURNING id, patient_username, delivery_address, total_amount, payment_status, created_at, payment_due_by, paid_payment_id
+`
+
+func (q *Queries) ExpireOrder(ctx context.Context, id int32) (Order, error) {
+	row := q.db.QueryRow(ctx, expireOrder, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DeliveryAddress,
+		&i.TotalAmount,
+		&i.PaymentStatus,
+		&i.CreatedAt,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const getOrder = `-- name: GetOrder :one
+SELECT id, patient_username, delivery_address, total_amount, payment_status, created_at, payment_due_by, paid_payment_id FROM orders WHERE id = $1
+`
+
+func (q *Queries) GetOrder(ctx context.Context, id int32) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrder, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DeliveryAddress,
+		&i.TotalAmount,
+		&i.PaymentStatus,
+		&i.CreatedAt,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT id, patient_username, delivery_address, total_amount, payment_status, created_at, payment_due_by, paid_payment_id FROM orders WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetOrderForUpdate(ctx context.Context, id int32) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DeliveryAddress,
+		&i.TotalAmount,
+		&i.PaymentStatus,
+		&i.CreatedAt,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const getSubOrder = `-- name: GetSubOrder :one
+SELECT id, order_id, seller_username, status, subtotal, created_at, updated_at, delivered_at FROM sub_orders WHERE id = $1
+`
+
+func (q *Queries) GetSubOrder(ctx context.Context, id int32) (SubOrder, error) {
+	row := q.db.QueryRow(ctx, getSubOrder, id)
+	var i SubOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.SellerUsername,
+		&i.Status,
+		&i.Subtotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const getSubOrderForUpdate = `-- name: GetSubOrderForUpdate :one
+SELECT id, order_id, seller_username, status, subtotal, created_at, updated_at, delivered_at FROM sub_orders WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetSubOrderForUpdate(ctx context.Context, id int32) (SubOrder, error) {
+	row := q.db.QueryRow(ctx, getSubOrderForUpdate, id)
+	var i SubOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.SellerUsername,
+		&i.Status,
+		&i.Subtotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const listCartItemsForCheckout = `-- name: ListCartItemsForCheckout :many
+SELECT c.id, c.medicine_id, c.quantity, m.seller_username
+FROM carts c
+JOIN medicines m ON m.id = c.medicine_id
+WHERE c.patient_username = $1
+ORDER BY c.medicine_id ASC
+`
+
+type ListCartItemsForCheckoutRow struct {
+	ID             int32  `json:"id"`
+	MedicineID     int32  `json:"medicine_id"`
+	Quantity       int32  `json:"quantity"`
+	SellerUsername string `json:"seller_username"`
+}
+
+// Ordered by medicine so concurrent checkouts lock medicines in the same
+// order
+func (q *Queries) ListCartItemsForCheckout(ctx context.Context, patientUsername string) ([]ListCartItemsForCheckoutRow, error) {
+	rows, err := q.db.Query(ctx, listCartItemsForCheckout, patientUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCartItemsForCheckoutRow{}
+	for rows.Next() {
+		var i ListCartItemsForCheckoutRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MedicineID,
+			&i.Quantity,
+			&i.SellerUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderItems = `-- name: ListOrderItems :many
+SELECT oi.id, oi.sub_order_id, oi.medicine_id, oi.medicine_name, oi.batch_number, oi.quantity, oi.unit_price, oi.discount, oi.line_total, oi.prescription_required FROM order_items oi
+JOIN sub_orders so ON so.id = oi.sub_order_id
+WHERE so.order_id = $1
+ORDER BY oi.id ASC
+`
+
+func (q *Queries) ListOrderItems(ctx context.Context, orderID int32) ([]OrderItem, error) {
+	rows, err := q.db.Query(ctx, listOrderItems, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderItem{}
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubOrderID,
+			&i.MedicineID,
+			&i.MedicineName,
+			&i.BatchNumber,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.Discount,
+			&i.LineTotal,
+			&i.PrescriptionRequired,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderSubOrders = `-- name: ListOrderSubOrders :many
+SELECT id, order_id, seller_username, status, subtotal, created_at, updated_at, delivered_at FROM sub_orders
+WHERE order_id = $1
+ORDER BY id ASC
+`
+
+func (q *Queries) ListOrderSubOrders(ctx context.Context, orderID int32) ([]SubOrder, error) {
+	rows, err := q.db.Query(ctx, listOrderSubOrders, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SubOrder{}
+	for rows.Next() {
+		var i SubOrder
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.SellerUsername,
+			&i.Status,
+			&i.Subtotal,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOverdueOrders = `-- name: ListOverdueOrders :many
+SELECT id FROM orders
+WHERE payment_status = 'pending' AND payment_due_by < $1
+ORDER BY id ASC
+LIMIT $2
+`
+
+type ListOverdueOrdersParams struct {
+	Now   pgtype.Timestamp `json:"now"`
+	Limit int32            `json:"limit"`
+}
+
+// Unpaid orders whose hold on their stock has run out
+func (q *Queries) ListOverdueOrders(ctx context.Context, arg ListOverdueOrdersParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listOverdueOrders, arg.Now, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPatientOrders = `-- name: ListPatientOrders :many
+SELECT id, patient_username, delivery_address, total_amount, payment_status, created_at, payment_due_by, paid_payment_id FROM orders
+WHERE patient_username = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListPatientOrdersParams struct {
+	PatientUsername string `json:"patient_username"`
+	Limit           int32  `json:"limit"`
+	Offset          int32  `json:"offset"`
+}
+
+func (q *Queries) ListPatientOrders(ctx context.Context, arg ListPatientOrdersParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, listPatientOrders, arg.PatientUsername, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientUsername,
+			&i.DeliveryAddress,
+			&i.TotalAmount,
+			&i.PaymentStatus,
+			&i.CreatedAt,
+			&i.PaymentDueBy,
+			&i.PaidPaymentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSellerSubOrders = `-- name: ListSellerSubOrders :many
+SELECT so.id, so.order_id, so.seller_username, so.status, so.subtotal, so.created_at, so.updated_at, so.delivered_at, o.patient_username, o.delivery_address
+FROM sub_orders so
+JOIN orders o ON o.id = so.order_id
+WHERE so.seller_username = $1
+  AND ($2::VARCHAR IS NULL OR so.status = $2)
+ORDER BY so.created_at DESC, so.id DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListSellerSubOrdersParams struct {
+	Seller string      `json:"seller"`
+	Status pgtype.Text `json:"status"`
+	Offset int32       `json:"offset"`
+	Limit  int32       `json:"limit"`
+}
+
+type ListSellerSubOrdersRow struct {
+	ID              int32            `json:"id"`
+	OrderID         int32            `json:"order_id"`
+	SellerUsername  string           `json:"seller_username"`
+	Status          string           `json:"status"`
+	Subtotal        pgtype.Numeric   `json:"subtotal"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	DeliveredAt     pgtype.Timestamp `json:"delivered_at"`
+	PatientUsername string           `json:"patient_username"`
+	DeliveryAddress string           `json:"delivery_address"`
+}
+
+func (q *Queries) ListSellerSubOrders(ctx context.Context, arg ListSellerSubOrdersParams) ([]ListSellerSubOrdersRow, error) {
+	rows, err := q.db.Query(ctx, listSellerSubOrders,
+		arg.Seller,
+		arg.Status,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSellerSubOrdersRow{}
+	for rows.Next() {
+		var i ListSellerSubOrdersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.SellerUsername,
+			&i.Status,
+			&i.Subtotal,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeliveredAt,
+			&i.PatientUsername,
+			&i.DeliveryAddress,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubOrderItems = `-- name: ListSubOrderItems :many
+SELECT id, sub_order_id, medicine_id, medicine_name, batch_number, quantity, unit_price, discount, line_total, prescription_required FROM order_items
+WHERE sub_order_id = $1
+ORDER BY id ASC
+`
+
+func (q *Queries) ListSubOrderItems(ctx context.Context, subOrderID int32) ([]OrderItem, error) {
+	rows, err := q.db.Query(ctx, listSubOrderItems, subOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderItem{}
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubOrderID,
+			&i.MedicineID,
+			&i.MedicineName,
+			&i.BatchNumber,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.Discount,
+			&i.LineTotal,
+			&i.PrescriptionRequired,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOrderPaid = `-- name: MarkOrderPaid :one
+UPDATE orders SET
+    payment_status = 'paid',
+    paid_payment_id = $1
+WHERE id = $2 AND payment_status = 'pending'
+RETURNING id, patient_username, delivery_address, total_amount, payment_status, created_at, payment_due_by, paid_payment_id
+`
+
+type MarkOrderPaidParams struct {
+	PaymentID pgtype.Int4 `json:"payment_id"`
+	ID        int32       `json:"id"`
+}
+
+func (q *Queries) MarkOrderPaid(ctx context.Context, arg MarkOrderPaidParams) (Order, error) {
+	row := q.db.QueryRow(ctx, markOrderPaid, arg.PaymentID, arg.ID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DeliveryAddress,
+		&i.TotalAmount,
+		&i.PaymentStatus,
+		&i.CreatedAt,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const updateOrderTotal = `-- name: UpdateOrderTotal :one
+UPDATE orders SET
+    total_amount = (
+        SELECT COALESCE(SUM(so.subtotal), 0) FROM sub_orders so
+        WHERE so.order_id = $1
+    )
+WHERE orders.id = $1
+RETURNING id, patient_username, delivery_address, total_amount, payment_status, created_at, payment_due_by, paid_payment_id
+`
+
+func (q *Queries) UpdateOrderTotal(ctx context.Context, id int32) (Order, error) {
+	row := q.db.QueryRow(ctx, updateOrderTotal, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DeliveryAddress,
+		&i.TotalAmount,
+		&i.PaymentStatus,
+		&i.CreatedAt,
+		&i.PaymentDueBy,
+		&i.PaidPaymentID,
+	)
+	return i, err
+}
+
+const updateSubOrderStatus = `-- name: UpdateSubOrderStatus :one
+UPDATE sub_orders SET
+    status = $1,
+    updated_at = now(),
+    delivered_at = CASE WHEN $1 = 'delivered' THEN now() ELSE delivered_at END
+WHERE id = $2 AND status = $3
+RETURNING id, order_id, seller_username, status, subtotal, created_at, updated_at, delivered_at
+`
+
+type UpdateSubOrderStatusParams struct {
+	Status     string `json:"status"`
+	ID         int32  `json:"id"`
+	FromStatus string `json:"from_status"`
+}
+
+// Moves a sub-order on only if it is still in from_status
+func (q *Queries) UpdateSubOrderStatus(ctx context.Context, arg UpdateSubOrderStatusParams) (SubOrder, error) {
+	row := q.db.QueryRow(ctx, updateSubOrderStatus, arg.Status, arg.ID, arg.FromStatus)
+	var i SubOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.SellerUsername,
+		&i.Status,
+		&i.Subtotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const updateSubOrderSubtotal = `-- name: UpdateSubOrderSubtotal :one
+UPDATE sub_orders SET
+    subtotal = (
+        SELECT COALESCE(SUM(oi.line_total), 0) FROM order_items oi
+        WHERE oi.sub_order_id = $1
+    )
+WHERE sub_orders.id = $1
+RETURNING id, order_id, seller_username, status, subtotal, created_at, updated_at, delivered_at
+`
+
+func (q *Queries) UpdateSubOrderSubtotal(ctx context.Context, id int32) (SubOrder, error) {
+	row := q.db.QueryRow(ctx, updateSubOrderSubtotal, id)
+	var i SubOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.SellerUsername,
+		&i.Status,
+		&i.Subtotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
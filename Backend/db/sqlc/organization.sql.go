@@ -0,0 +1,313 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: organization.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createOrganization = `-- name: CreateOrganization :one
+INSERT INTO organizations (
+    name, owner_username
+) VALUES (
+    $1, $2
+)
+RETURNING id, name, owner_username, created_at
+`
+
+type CreateOrganizationParams struct {
+	Name          string `json:"name"`
+	OwnerUsername string `json:"owner_username"`
+}
+
+func (q *Queries) CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, createOrganization, arg.Name, arg.OwnerUsername)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerUsername,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getOrganization = `-- name: GetOrganization :one
+SELECT id, name, owner_username, created_at FROM organizations WHERE id = $1
+`
+
+func (q *Queries) GetOrganization(ctx context.Context, id int32) (Organization, error) {
+	row := q.db.QueryRow(ctx, getOrganization, id)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerUsername,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listOrganizationBranchReport = `-- name: ListOrganizationBranchReport :many
+SELECT s.username, s.store_name, s.pincode,
+    COALESCE(sales.orders, 0)::INT AS orders,
+    COALESCE(sales.units, 0)::INT AS units_sold,
+    COALESCE(sales.revenue, 0)::NUMERIC(14, 2) AS revenue,
+    COALESCE(stock.medicines, 0)::INT AS medicines,
+    COALESCE(stock.units, 0)::INT AS stock_units,
+    COALESCE(stock.value, 0)::NUMERIC(14, 2) AS stock_value,
+    COALESCE(stock.low_stock, 0)::INT AS low_stock_medicines
+FROM sellers s
+LEFT JOIN (
+    SELECT seller_username, SUM(orders) AS orders, SUM(units) AS units, SUM(revenue) AS revenue
+    FROM seller_daily_sales
+    WHERE day BETWEEN $1::DATE AND $2::DATE
+    GROUP BY seller_username
+) sales ON sales.seller_username = s.username
+LEFT JOIN (
+    SELECT seller_username,
+        COUNT(*) AS medicines,
+        SUM(quantity) AS units,
+        SUM(quantity * price) AS value,
+        COUNT(*) FILTER (WHERE reorder_level IS NOT NULL AND quantity <= reorder_level) AS low_stock
+    FROM medicines
+    WHERE status = 'active'
+    GROUP BY seller_username
+) stock ON stock.seller_username = s.username
+WHERE s.organization_id = $3
+ORDER BY revenue DESC, s.username ASC
+`
+
+type ListOrganizationBranchReportParams struct {
+	FromDate       pgtype.Date `json:"from_date"`
+	ToDate         pgtype.Date `json:"to_date"`
+	OrganizationID pgtype.Int4 `json:"organization_id"`
+}
+
+type ListOrganizationBranchReportRow struct {
+	Username          string         `json:"username"`
+	StoreName         string         `json:"store_name"`
+	Pincode           pgtype.Text    `json:"pincode"`
+	Orders            int32          `json:"orders"`
+	UnitsSold         int32          `json:"units_sold"`
+	Revenue           pgtype.Numeric `json:"revenue"`
+	Medicines         int32          `json:"medicines"`
+	StockUnits        int32          `json:"stock_units"`
+	StockValue        pgtype.Numeric `json:"stock_value"`
+	LowStockMedicines int32          `json:"low_stock_medicines"`
+}
+
+// Sales between two dates (inclusive) and the stock on hand now for every
+// branch of an organisation
+func (q *Queries) ListOrganizationBranchReport(ctx context.Context, arg ListOrganizationBranchReportParams) ([]ListOrganizationBranchReportRow, error) {
+	rows, err := q.db.Query(ctx, listOrganizationBranchReport, arg.FromDate, arg.ToDate, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOrganizationBranchReportRow{}
+	for rows.Next() {
+		var i ListOrganizationBranchReportRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.StoreName,
+			&i.Pincode,
+			&i.Orders,
+			&i.UnitsSold,
+			&i.Revenue,
+			&i.Medicines,
+			&i.StockUnits,
+			&i.StockValue,
+			&i.LowStockMedicines,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationBranches = `-- name: ListOrganizationBranches :many
+SELECT username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at, pincode, latitude, longitude, delivery_radius_km, verified_at, organization_id, rating_average, delivery_rating_average, packaging_rating_average, authenticity_rating_average, review_count FROM sellers
+WHERE organization_id = $1
+ORDER BY store_name ASC, username ASC
+`
+
+func (q *Queries) ListOrganizationBranches(ctx context.Context, organizationID pgtype.Int4) ([]Seller, error) {
+	rows, err := q.db.Query(ctx, listOrganizationBranches, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Seller{}
+	for rows.Next() {
+		var i Seller
+		if err := rows.Scan(
+			&i.Username,
+			&i.FullName,
+			&i.Email,
+			&i.Password,
+			&i.MobileNumber,
+			&i.StoreName,
+			&i.GstNumber,
+			&i.DrugLicenseNumber,
+			&i.SellerType,
+			&i.StoreAddress,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.Pincode,
+			&i.Latitude,
+			&i.Longitude,
+			&i.DeliveryRadiusKm,
+			&i.VerifiedAt,
+			&i.OrganizationID,
+			&i.RatingAverage,
+			&i.DeliveryRatingAverage,
+			&i.PackagingRatingAverage,
+			&i.AuthenticityRatingAverage,
+			&i.ReviewCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationSalesByPeriod = `-- name: ListOrganizationSalesByPeriod :many
+SELECT date_trunc($1::VARCHAR, d.day)::DATE AS period,
+    SUM(d.orders)::INT AS orders,
+    SUM(d.units)::INT AS units,
+    SUM(d.revenue)::NUMERIC(14, 2) AS revenue,
+    (SUM(d.revenue) / NULLIF(SUM(d.orders), 0))::NUMERIC(12, 2) AS average_order_value
+FROM seller_daily_sales d
+JOIN sellers s ON s.username = d.seller_username
+WHERE s.organization_id = $2
+  AND d.day BETWEEN $3::DATE AND $4::DATE
+GROUP BY 1
+ORDER BY 1 ASC
+`
+
+type ListOrganizationSalesByPeriodParams struct {
+	Interval       string      `json:"interval"`
+	OrganizationID pgtype.Int4 `json:"organization_id"`
+	FromDate       pgtype.Date `json:"from_date"`
+	ToDate         pgtype.Date `json:"to_date"`
+}
+
+type ListOrganizationSalesByPeriodRow struct {
+	Period            pgtype.Date    `json:"period"`
+	Orders            int32          `json:"orders"`
+	Units             int32          `json:"units"`
+	Revenue           pgtype.Numeric `json:"revenue"`
+	AverageOrderValue pgtype.Numeric `json:"average_order_value"`
+}
+
+// Revenue, units and orders of all branches together per day, week or month
+// between two dates (inclusive)
+func (q *Queries) ListOrganizationSalesByPeriod(ctx context.Context, arg ListOrganizationSalesByPeriodParams) ([]ListOrganizationSalesByPeriodRow, error) {
+	rows, err := q.db.Query(ctx, listOrganizationSalesByPeriod,
+		arg.Interval,
+		arg.OrganizationID,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOrganizationSalesByPeriodRow{}
+	for rows.Next() {
+		var i ListOrganizationSalesByPeriodRow
+		if err := rows.Scan(
+			&i.Period,
+			&i.Orders,
+			&i.Units,
+			&i.Revenue,
+			&i.AverageOrderValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setSellerOrganization = `-- name: SetSellerOrganization :one
+UPDATE sellers SET organization_id = $1
+WHERE username = $2
+RETURNING username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at, pincode, latitude, longitude, delivery_radius_km, verified_at, organization_id, rating_average, delivery_rating_average, packaging_rating_average, authenticity_rating_average, review_count
+`
+
+type SetSellerOrganizationParams struct {
+	OrganizationID pgtype.Int4 `json:"organization_id"`
+	Username       string      `json:"username"`
+}
+
+func (q *Queries) SetSellerOrganization(ctx context.Context, arg SetSellerOrganizationParams) (Seller, error) {
+	row := q.db.QueryRow(ctx, setSellerOrganization, arg.OrganizationID, arg.Username)
+	var i Seller
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.MobileNumber,
+		&i.StoreName,
+		&i.GstNumber,
+		&i.DrugLicenseNumber,
+		&i.SellerType,
+		&i.StoreAddress,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Pincode,
+		&i.Latitude,
+		&i.Longitude,
+		&i.DeliveryRadiusKm,
+		&i.VerifiedAt,
+		&i.OrganizationID,
+		&i.RatingAverage,
+		&i.DeliveryRatingAverage,
+		&i.PackagingRatingAverage,
+		&i.AuthenticityRatingAverage,
+		&i.ReviewCount,
+	)
+	return i, err
+}
+
+const updateOrganizationName = `-- name: UpdateOrganizationName :one
+UPDATE organizations SET name = $2
+WHERE id = $1
+RETURNING id, name, owner_username, created_at
+`
+
+type UpdateOrganizationNameParams struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) UpdateOrganizationName(ctx context.Context, arg UpdateOrganizationNameParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, updateOrganizationName, arg.ID, arg.Name)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OwnerUsername,
+		&i.CreatedAt,
+	)
+	return i, err
+}
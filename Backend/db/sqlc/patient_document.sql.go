@@ -0,0 +1,442 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: patient_document.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createDocumentShare = `-- name: CreateDocumentShare :one
+INSERT INTO document_shares (
+    document_id, doctor_username, expires_at
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, document_id, doctor_username, expires_at, revoked_at, created_at
+`
+
+type CreateDocumentShareParams struct {
+	DocumentID     int32            `json:"document_id"`
+	DoctorUsername string           `json:"doctor_username"`
+	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) CreateDocumentShare(ctx context.Context, arg CreateDocumentShareParams) (DocumentShare, error) {
+	row := q.db.QueryRow(ctx, createDocumentShare, arg.DocumentID, arg.DoctorUsername, arg.ExpiresAt)
+	var i DocumentShare
+	err := row.Scan(
+		&i.ID,
+		&i.DocumentID,
+		&i.DoctorUsername,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPatientDocument = `-- name: CreatePatientDocument :one
+INSERT INTO patient_documents (
+    patient_username, document_type, title, document_date, tags, file_name,
+    content_type, size_bytes, storage_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, patient_username, document_type, title, document_date, tags, file_name, content_type, size_bytes, storage_key, created_at, updated_at
+`
+
+type CreatePatientDocumentParams struct {
+	PatientUsername string      `json:"patient_username"`
+	DocumentType    string      `json:"document_type"`
+	Title           string      `json:"title"`
+	DocumentDate    pgtype.Date `json:"document_date"`
+	Tags            []string    `json:"tags"`
+	FileName        string      `json:"file_name"`
+	ContentType     string      `json:"content_type"`
+	SizeBytes       int32       `json:"size_bytes"`
+	StorageKey      string      `json:"storage_key"`
+}
+
+func (q *Queries) CreatePatientDocument(ctx context.Context, arg CreatePatientDocumentParams) (PatientDocument, error) {
+	row := q.db.QueryRow(ctx, createPatientDocument,
+		arg.PatientUsername,
+		arg.DocumentType,
+		arg.Title,
+		arg.DocumentDate,
+		arg.Tags,
+		arg.FileName,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.StorageKey,
+	)
+	var i PatientDocument
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DocumentType,
+		&i.Title,
+		&i.DocumentDate,
+		&i.Tags,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deletePatientDocument = `-- name: DeletePatientDocument :one
+DELETE FROM patient_documents
+WHERE id = $1 AND patient_username = $2
+RETURNING id, patient_username, document_type, title, document_date, tags, file_name, content_type, size_bytes, storage_key, created_at, updated_at
+`
+
+type DeletePatientDocumentParams struct {
+	ID              int32  `json:"id"`
+	PatientUsername string `json:"patient_username"`
+}
+
+func (q *Queries) DeletePatientDocument(ctx context.Context, arg DeletePatientDocumentParams) (PatientDocument, error) {
+	row := q.db.QueryRow(ctx, deletePatientDocument, arg.ID, arg.PatientUsername)
+	var i PatientDocument
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DocumentType,
+		&i.Title,
+		&i.DocumentDate,
+		&i.Tags,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPatientDocument = `-- name: GetPatientDocument :one
+SELECT id, patient_username, document_type, title, document_date, tags, file_name, content_type, size_bytes, storage_key, created_at, updated_at FROM patient_documents WHERE id = $1
+`
+
+func (q *Queries) GetPatientDocument(ctx context.Context, id int32) (PatientDocument, error) {
+	row := q.db.QueryRow(ctx, getPatientDocument, id)
+	var i PatientDocument
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DocumentType,
+		&i.Title,
+		&i.DocumentDate,
+		&i.Tags,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const isDocumentSharedWithDoctor = `-- name: IsDocumentSharedWithDoctor :one
+SELECT EXISTS (
+    SELECT 1 FROM document_shares
+    WHERE document_id = $1
+      AND doctor_username = $2
+      AND revoked_at IS NULL
+      AND expires_at > now()
+)
+`
+
+type IsDocumentSharedWithDoctorParams struct {
+	DocumentID     int32  `json:"document_id"`
+	DoctorUsername string `json:"doctor_username"`
+}
+
+func (q *Queries) IsDocumentSharedWithDoctor(ctx context.Context, arg IsDocumentSharedWithDoctorParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isDocumentSharedWithDoctor, arg.DocumentID, arg.DoctorUsername)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listDocumentShares = `-- name: ListDocumentShares :many
+SELECT s.id, s.document_id, s.doctor_username, s.expires_at, s.revoked_at, s.created_at, d.title AS document_title, doc.full_name AS doctor_name
+FROM document_shares s
+JOIN patient_documents d ON d.id = s.document_id
+JOIN doctors doc ON doc.username = s.doctor_username
+WHERE d.patient_username = $1
+  AND s.revoked_at IS NULL
+  AND s.expires_at > now()
+ORDER BY s.expires_at, s.id
+`
+
+type ListDocumentSharesRow struct {
+	ID             int32            `json:"id"`
+	DocumentID     int32            `json:"document_id"`
+	DoctorUsername string           `json:"doctor_username"`
+	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
+	RevokedAt      pgtype.Timestamp `json:"revoked_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	DocumentTitle  string           `json:"document_title"`
+	DoctorName     string           `json:"doctor_name"`
+}
+
+// Shares of the patient's documents that haven't expired or been revoked,
+// the soonest to expire first
+func (q *Queries) ListDocumentShares(ctx context.Context, patientUsername string) ([]ListDocumentSharesRow, error) {
+	rows, err := q.db.Query(ctx, listDocumentShares, patientUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDocumentSharesRow{}
+	for rows.Next() {
+		var i ListDocumentSharesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.DocumentID,
+			&i.DoctorUsername,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+			&i.DocumentTitle,
+			&i.DoctorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDocumentsSharedWithDoctor = `-- name: ListDocumentsSharedWithDoctor :many
+SELECT DISTINCT ON (COALESCE(d.document_date, d.created_at::DATE), d.id)
+    d.id, d.patient_username, d.document_type, d.title, d.document_date, d.tags,
+    d.file_name, d.content_type, d.size_bytes, d.created_at,
+    p.full_name AS patient_name, s.expires_at AS shared_until
+FROM document_shares s
+JOIN patient_documents d ON d.id = s.document_id
+JOIN patients p ON p.username = d.patient_username
+WHERE s.doctor_username = $1
+  AND ($2::VARCHAR IS NULL OR d.patient_username = $2)
+  AND s.revoked_at IS NULL
+  AND s.expires_at > now()
+ORDER BY COALESCE(d.document_date, d.created_at::DATE) DESC, d.id DESC, s.expires_at DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListDocumentsSharedWithDoctorParams struct {
+	DoctorUsername  string      `json:"doctor_username"`
+	PatientUsername pgtype.Text `json:"patient_username"`
+	OffsetCount     int32       `json:"offset_count"`
+	LimitCount      int32       `json:"limit_count"`
+}
+
+type ListDocumentsSharedWithDoctorRow struct {
+	ID              int32            `json:"id"`
+	PatientUsername string           `json:"patient_username"`
+	DocumentType    string           `json:"document_type"`
+	Title           string           `json:"title"`
+	DocumentDate    pgtype.Date      `json:"document_date"`
+	Tags            []string         `json:"tags"`
+	FileName        string           `json:"file_name"`
+	ContentType     string           `json:"content_type"`
+	SizeBytes       int32            `json:"size_bytes"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	PatientName     string           `json:"patient_name"`
+	SharedUntil     pgtype.Timestamp `json:"shared_until"`
+}
+
+// The documents currently shared with the doctor, optionally by one
+// patient, newest first. A document shared more than once comes with the
+// share that lasts longest.
+func (q *Queries) ListDocumentsSharedWithDoctor(ctx context.Context, arg ListDocumentsSharedWithDoctorParams) ([]ListDocumentsSharedWithDoctorRow, error) {
+	rows, err := q.db.Query(ctx, listDocumentsSharedWithDoctor,
+		arg.DoctorUsername,
+		arg.PatientUsername,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDocumentsSharedWithDoctorRow{}
+	for rows.Next() {
+		var i ListDocumentsSharedWithDoctorRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientUsername,
+			&i.DocumentType,
+			&i.Title,
+			&i.DocumentDate,
+			&i.Tags,
+			&i.FileName,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.CreatedAt,
+			&i.PatientName,
+			&i.SharedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPatientDocuments = `-- name: ListPatientDocuments :many
+SELECT id, patient_username, document_type, title, document_date, tags, file_name, content_type, size_bytes, storage_key, created_at, updated_at FROM patient_documents
+WHERE patient_username = $1
+  AND ($2::VARCHAR IS NULL OR document_type = $2)
+  AND ($3::VARCHAR IS NULL OR $3::VARCHAR = ANY(tags))
+  AND ($4::DATE IS NULL OR document_date >= $4)
+  AND ($5::DATE IS NULL OR document_date <= $5)
+ORDER BY COALESCE(document_date, created_at::DATE) DESC, id DESC
+LIMIT $7 OFFSET $6
+`
+
+type ListPatientDocumentsParams struct {
+	PatientUsername string      `json:"patient_username"`
+	DocumentType    pgtype.Text `json:"document_type"`
+	Tag             pgtype.Text `json:"tag"`
+	FromDate        pgtype.Date `json:"from_date"`
+	ToDate          pgtype.Date `json:"to_date"`
+	OffsetCount     int32       `json:"offset_count"`
+	LimitCount      int32       `json:"limit_count"`
+}
+
+// The patient's documents, newest first, optionally of one type, with a
+// tag or dated within a range
+func (q *Queries) ListPatientDocuments(ctx context.Context, arg ListPatientDocumentsParams) ([]PatientDocument, error) {
+	rows, err := q.db.Query(ctx, listPatientDocuments,
+		arg.PatientUsername,
+		arg.DocumentType,
+		arg.Tag,
+		arg.FromDate,
+		arg.ToDate,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PatientDocument{}
+	for rows.Next() {
+		var i PatientDocument
+		if err := rows.Scan(
+			&i.ID,
+			&i.PatientUsername,
+			&i.DocumentType,
+			&i.Title,
+			&i.DocumentDate,
+			&i.Tags,
+			&i.FileName,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeDocumentShare = `-- name: RevokeDocumentShare :one
+UPDATE document_shares s SET revoked_at = now()
+FROM patient_documents d
+WHERE s.id = $1
+  AND d.id = s.document_id
+  AND d.patient_username = $2
+  AND s.revoked_at IS NULL
+RETURNING s.id, s.document_id, s.doctor_username, s.expires_at, s.revoked_at, s.created_at
+`
+
+type RevokeDocumentShareParams struct {
+	ID              int32  `json:"id"`
+	PatientUsername string `json:"patient_username"`
+}
+
+func (q *Queries) RevokeDocumentShare(ctx context.Context, arg RevokeDocumentShareParams) (DocumentShare, error) {
+	row := q.db.QueryRow(ctx, revokeDocumentShare, arg.ID, arg.PatientUsername)
+	var i DocumentShare
+	err := row.Scan(
+		&i.ID,
+		&i.DocumentID,
+		&i.DoctorUsername,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updatePatientDocument = `-- name: UpdatePatientDocument :one
+UPDATE patient_documents SET
+    document_type = $1,
+    title = $2,
+    document_date = $3,
+    tags = $4,
+    updated_at = now()
+WHERE id = $5 AND patient_username = $6
+RETURNING id, patient_username, document_type, title, document_date, tags, file_name, content_type, size_bytes, storage_key, created_at, updated_at
+`
+
+type UpdatePatientDocumentParams struct {
+	DocumentType    string      `json:"document_type"`
+	Title           string      `json:"title"`
+	DocumentDate    pgtype.Date `json:"document_date"`
+	Tags            []string    `json:"tags"`
+	ID              int32       `json:"id"`
+	PatientUsername string      `json:"patient_username"`
+}
+
+func (q *Queries) UpdatePatientDocument(ctx context.Context, arg UpdatePatientDocumentParams) (PatientDocument, error) {
+	row := q.db.QueryRow(ctx, updatePatientDocument,
+		arg.DocumentType,
+		arg.Title,
+		arg.DocumentDate,
+		arg.Tags,
+		arg.ID,
+		arg.PatientUsername,
+	)
+	var i PatientDocument
+	err := row.Scan(
+		&i.ID,
+		&i.PatientUsername,
+		&i.DocumentType,
+		&i.Title,
+		&i.DocumentDate,
+		&i.Tags,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,225 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: payout.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createPayout = `-- name: CreatePayout :one
+INSERT INTO payouts (
+    batch_id, seller_username, period_start, period_end, opening_balance,
+    gross_sales, commission, refunds, chargebacks, amount
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING id, batch_id, seller_username, period_start, period_end, opening_balance, gross_sales, commission, refunds, chargebacks, amount, created_at
+`
+
+type CreatePayoutParams struct {
+	BatchID        int32            `json:"batch_id"`
+	SellerUsername string           `json:"seller_username"`
+	PeriodStart    pgtype.Timestamp `json:"period_start"`
+	PeriodEnd      pgtype.Timestamp `json:"period_end"`
+	OpeningBalance pgtype.Numeric   `json:"opening_balance"`
+	GrossSales     pgtype.Numeric   `json:"gross_sales"`
+	Commission     pgtype.Numeric   `json:"commission"`
+	Refunds        pgtype.Numeric   `json:"refunds"`
+	Chargebacks    pgtype.Numeric   `json:"chargebacks"`
+	Amount         pgtype.Numeric   `json:"amount"`
+}
+
+func (q *Queries) CreatePayout(ctx context.Context, arg CreatePayoutParams) (Payout, error) {
+	row := q.db.QueryRow(ctx, createPayout,
+		arg.BatchID,
+		arg.SellerUsername,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.OpeningBalance,
+		arg.GrossSales,
+		arg.Commission,
+		arg.Refunds,
+		arg.Chargebacks,
+		arg.Amount,
+	)
+	var i Payout
+	err := row.Scan(
+		&i.ID,
+		&i.BatchID,
+		&i.SellerUsername,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.OpeningBalance,
+		&i.GrossSales,
+		&i.Commission,
+		&i.Refunds,
+		&i.Chargebacks,
+		&i.Amount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPayoutBatch = `-- name: CreatePayoutBatch :one
+INSERT INTO payout_batches (
+    cutoff
+) VALUES (
+    $1
+)
+RETURNING id, cutoff, payouts, total_amount, created_at
+`
+
+func (q *Queries) CreatePayoutBatch(ctx context.Context, cutoff pgtype.Timestamp) (PayoutBatch, error) {
+	row := q.db.QueryRow(ctx, createPayoutBatch, cutoff)
+	var i PayoutBatch
+	err := row.Scan(
+		&i.ID,
+		&i.Cutoff,
+		&i.Payouts,
+		&i.TotalAmount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLastPayoutBatch = `-- name: GetLastPayoutBatch :one
+SELECT id, cutoff, payouts, total_amount, created_at FROM payout_batches
+ORDER BY cutoff DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastPayoutBatch(ctx context.Context) (PayoutBatch, error) {
+	row := q.db.QueryRow(ctx, getLastPayoutBatch)
+	var i PayoutBatch
+	err := row.Scan(
+		&i.ID,
+		&i.Cutoff,
+		&i.Payouts,
+		&i.TotalAmount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLastSellerPayout = `-- name: GetLastSellerPayout :one
+SELECT id, batch_id, seller_username, period_start, period_end, opening_balance, gross_sales, commission, refunds, chargebacks, amount, created_at FROM payouts
+WHERE seller_username = $1
+ORDER BY period_end DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastSellerPayout(ctx context.Context, sellerUsername string) (Payout, error) {
+	row := q.db.QueryRow(ctx, getLastSellerPayout, sellerUsername)
+	var i Payout
+	err := row.Scan(
+		&i.ID,
+		&i.BatchID,
+		&i.SellerUsername,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.OpeningBalance,
+		&i.GrossSales,
+		&i.Commission,
+		&i.Refunds,
+		&i.Chargebacks,
+		&i.Amount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPayout = `-- name: GetPayout :one
+SELECT id, batch_id, seller_username, period_start, period_end, opening_balance, gross_sales, commission, refunds, chargebacks, amount, created_at FROM payouts WHERE id = $1
+`
+
+func (q *Queries) GetPayout(ctx context.Context, id int32) (Payout, error) {
+	row := q.db.QueryRow(ctx, getPayout, id)
+	var i Payout
+	err := row.Scan(
+		&i.ID,
+		&i.BatchID,
+		&i.SellerUsername,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.OpeningBalance,
+		&i.GrossSales,
+		&i.Commission,
+		&i.Refunds,
+		&i.Chargebacks,
+		&i.Amount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listSellerPayouts = `-- name: ListSellerPayouts :many
+SELECT id, batch_id, seller_username, period_start, period_end, opening_balance, gross_sales, commission, refunds, chargebacks, amount, created_at FROM payouts
+WHERE seller_username = $1
+ORDER BY period_end DESC, id DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListSellerPayoutsParams struct {
+	Seller string `json:"seller"`
+	Offset int32  `json:"offset"`
+	Limit  int32  `json:"limit"`
+}
+
+func (q *Queries) ListSellerPayouts(ctx context.Context, arg ListSellerPayoutsParams) ([]Payout, error) {
+	rows, err := q.db.Query(ctx, listSellerPayouts, arg.Seller, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payout{}
+	for rows.Next() {
+		var i Payout
+		if err := rows.Scan(
+			&i.ID,
+			&i.BatchID,
+			&i.SellerUsername,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.OpeningBalance,
+			&i.GrossSales,
+			&i.Commission,
+			&i.Refunds,
+			&i.Chargebacks,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePayoutBatchTotals = `-- name: UpdatePayoutBatchTotals :one
+UPDATE payout_batches SET
+    payouts = (SELECT COUNT(*) FROM payouts p WHERE p.batch_id = $1),
+    total_amount = (SELECT COALESCE(SUM(p.amount), 0) FROM payouts p WHERE p.batch_id = $1)
+WHERE payout_batches.id = $1
+RETURNING id, cutoff, payouts, total_amount, created_at
+`
+
+func (q *Queries) UpdatePayoutBatchTotals(ctx context.Context, id int32) (PayoutBatch, error) {
+	row := q.db.QueryRow(ctx, updatePayoutBatchTotals, id)
+	var i PayoutBatch
+	err := row.Scan(
+		&i.ID,
+		&i.Cutoff,
+		&i.Payouts,
+		&i.TotalAmount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,74 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: pincode.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getPincode = `-- name: GetPincode :one
+SELECT pincode, locality, district, state, latitude, longitude FROM pincodes WHERE pincode = $1
+`
+
+func (q *Queries) GetPincode(ctx context.Context, pincode string) (Pincode, error) {
+	row := q.db.QueryRow(ctx, getPincode, pincode)
+	var i Pincode
+	err := row.Scan(
+		&i.Pincode,
+		&i.Locality,
+		&i.District,
+		&i.State,
+		&i.Latitude,
+		&i.Longitude,
+	)
+	return i, err
+}
+
+const upsertPincode = `-- name: UpsertPincode :one
+INSERT INTO pincodes (
+    pincode, locality, district, state, latitude, longitude
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (pincode) DO UPDATE
+SET
+    locality = EXCLUDED.locality,
+    district = EXCLUDED.district,
+    state = EXCLUDED.state,
+    latitude = EXCLUDED.latitude,
+    longitude = EXCLUDED.longitude
+RETURNING pincode, locality, district, state, latitude, longitude
+`
+
+type UpsertPincodeParams struct {
+	Pincode   string  `json:"pincode"`
+	Locality  string  `json:"locality"`
+	District  string  `json:"district"`
+	State     string  `json:"state"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (q *Queries) UpsertPincode(ctx context.Context, arg UpsertPincodeParams) (Pincode, error) {
+	row := q.db.QueryRow(ctx, upsertPincode,
+		arg.Pincode,
+		arg.Locality,
+		arg.District,
+		arg.State,
+		arg.Latitude,
+		arg.Longitude,
+	)
+	var i Pincode
+	err := row.Scan(
+		&i.Pincode,
+		&i.Locality,
+		&i.District,
+		&i.State,
+		&i.Latitude,
+		&i.Longitude,
+	)
+	return i, err
+}
@@ -0,0 +1,314 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: purchase_order.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createPurchaseOrder = `-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (
+    buyer_username, supplier_username, notes
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, buyer_username, supplier_username, status, total_amount, notes, created_at, updated_at
+`
+
+type CreatePurchaseOrderParams struct {
+	BuyerUsername    string `json:"buyer_username"`
+	SupplierUsername string `json:"supplier_username"`
+	Notes            string `json:"notes"`
+}
+
+func (q *Queries) CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, createPurchaseOrder, arg.BuyerUsername, arg.SupplierUsername, arg.Notes)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerUsername,
+		&i.SupplierUsername,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createPurchaseOrderItem = `-- name: CreatePurchaseOrderItem :one
+INSERT INTO purchase_order_items (
+    purchase_order_id, medicine_id, name, description, active_ingredients,
+    strength, dosage_form, prescription_required, batch_number, expiry_date,
+    mrp, quantity, unit_price
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+)
+RETURNING id, purchase_order_id, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, mrp, quantity, unit_price, received_medicine_id
+`
+
+type CreatePurchaseOrderItemParams struct {
+	PurchaseOrderID      int32          `json:"purchase_order_id"`
+	MedicineID           int32          `json:"medicine_id"`
+	Name                 string         `json:"name"`
+	Description          string         `json:"description"`
+	ActiveIngredients    string         `json:"active_ingredients"`
+	Strength             string         `json:"strength"`
+	DosageForm           string         `json:"dosage_form"`
+	PrescriptionRequired bool           `json:"prescription_required"`
+	BatchNumber          string         `json:"batch_number"`
+	ExpiryDate           pgtype.Date    `json:"expiry_date"`
+	Mrp                  pgtype.Numeric `json:"mrp"`
+	Quantity             int32          `json:"quantity"`
+	UnitPrice            pgtype.Numeric `json:"unit_price"`
+}
+
+func (q *Queries) CreatePurchaseOrderItem(ctx context.Context, arg CreatePurchaseOrderItemParams) (PurchaseOrderItem, error) {
+	row := q.db.QueryRow(ctx, createPurchaseOrderItem,
+		arg.PurchaseOrderID,
+		arg.MedicineID,
+		arg.Name,
+		arg.Description,
+		arg.ActiveIngredients,
+		arg.Strength,
+		arg.DosageForm,
+		arg.PrescriptionRequired,
+		arg.BatchNumber,
+		arg.ExpiryDate,
+		arg.Mrp,
+		arg.Quantity,
+		arg.UnitPrice,
+	)
+	var i PurchaseOrderItem
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseOrderID,
+		&i.MedicineID,
+		&i.Name,
+		&i.Description,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.ExpiryDate,
+		&i.Mrp,
+		&i.Quantity,
+		&i.UnitPrice,
+		&i.ReceivedMedicineID,
+	)
+	return i, err
+}
+
+const getPurchaseOrder = `-- name: GetPurchaseOrder :one
+SELECT id, buyer_username, supplier_username, status, total_amount, notes, created_at, updated_at FROM purchase_orders WHERE id = $1
+`
+
+func (q *Queries) GetPurchaseOrder(ctx context.Context, id int32) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, getPurchaseOrder, id)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerUsername,
+		&i.SupplierUsername,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPurchaseOrderForUpdate = `-- name: GetPurchaseOrderForUpdate :one
+SELECT id, buyer_username, supplier_username, status, total_amount, notes, created_at, updated_at FROM purchase_orders WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetPurchaseOrderForUpdate(ctx context.Context, id int32) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, getPurchaseOrderForUpdate, id)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerUsername,
+		&i.SupplierUsername,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listPurchaseOrderItems = `-- name: ListPurchaseOrderItems :many
+SELECT id, purchase_order_id, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, mrp, quantity, unit_price, received_medicine_id FROM purchase_order_items
+WHERE purchase_order_id = $1
+ORDER BY id ASC
+`
+
+func (q *Queries) ListPurchaseOrderItems(ctx context.Context, purchaseOrderID int32) ([]PurchaseOrderItem, error) {
+	rows, err := q.db.Query(ctx, listPurchaseOrderItems, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PurchaseOrderItem{}
+	for rows.Next() {
+		var i PurchaseOrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.PurchaseOrderID,
+			&i.MedicineID,
+			&i.Name,
+			&i.Description,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.ExpiryDate,
+			&i.Mrp,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.ReceivedMedicineID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSellerPurchaseOrders = `-- name: ListSellerPurchaseOrders :many
+SELECT id, buyer_username, supplier_username, status, total_amount, notes, created_at, updated_at FROM purchase_orders
+WHERE (buyer_username = $1 OR supplier_username = $1)
+  AND ($2::VARCHAR IS NULL OR status = $2)
+ORDER BY created_at DESC, id DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListSellerPurchaseOrdersParams struct {
+	Seller string      `json:"seller"`
+	Status pgtype.Text `json:"status"`
+	Offset int32       `json:"offset"`
+	Limit  int32       `json:"limit"`
+}
+
+// Orders the seller placed as a buyer or received as a supplier
+func (q *Queries) ListSellerPurchaseOrders(ctx context.Context, arg ListSellerPurchaseOrdersParams) ([]PurchaseOrder, error) {
+	rows, err := q.db.Query(ctx, listSellerPurchaseOrders,
+		arg.Seller,
+		arg.Status,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PurchaseOrder{}
+	for rows.Next() {
+		var i PurchaseOrder
+		if err := rows.Scan(
+			&i.ID,
+			&i.BuyerUsername,
+			&i.SupplierUsername,
+			&i.Status,
+			&i.TotalAmount,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPurchaseOrderItemReceived = `-- name: SetPurchaseOrderItemReceived :exec
+UPDATE purchase_order_items SET received_medicine_id = $2
+WHERE id = $1
+`
+
+type SetPurchaseOrderItemReceivedParams struct {
+	ID                 int32       `json:"id"`
+	ReceivedMedicineID pgtype.Int4 `json:"received_medicine_id"`
+}
+
+func (q *Queries) SetPurchaseOrderItemReceived(ctx context.Context, arg SetPurchaseOrderItemReceivedParams) error {
+	_, err := q.db.Exec(ctx, setPurchaseOrderItemReceived, arg.ID, arg.ReceivedMedicineID)
+	return err
+}
+
+const updatePurchaseOrderStatus = `-- name: UpdatePurchaseOrderStatus :one
+UPDATE purchase_orders SET
+    status = $1,
+    updated_at = now()
+WHERE id = $2 AND status = $3
+RETURNING id, buyer_username, supplier_username, status, total_amount, notes, created_at, updated_at
+`
+
+type UpdatePurchaseOrderStatusParams struct {
+	Status     string `json:"status"`
+	ID         int32  `json:"id"`
+	FromStatus string `json:"from_status"`
+}
+
+// Moves an order on only if it is still in from_status, so concurrent
+// changes can't both succeed
+func (q *Queries) UpdatePurchaseOrderStatus(ctx context.Context, arg UpdatePurchaseOrderStatusParams) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, updatePurchaseOrderStatus, arg.Status, arg.ID, arg.FromStatus)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerUsername,
+		&i.SupplierUsername,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updatePurchaseOrderTotal = `-- name: UpdatePurchaseOrderTotal :one
+UPDATE purchase_orders SET
+    total_amount = (
+        SELECT COALESCE(SUM(i.quantity * i.unit_price), 0)
+        FROM purchase_order_items i
+        WHERE i.purchase_order_id = $1
+    )
+WHERE purchase_orders.id = $1
+RETURNING id, buyer_username, supplier_username, status, total_amount, notes, created_at, updated_at
+`
+
+func (q *Queries) UpdatePurchaseOrderTotal(ctx context.Context, id int32) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, updatePurchaseOrderTotal, id)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.BuyerUsername,
+		&i.SupplierUsername,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -1,211 +1,501 @@
-// Code generated by sqlc. DO NOT EDIT.
-// versions:
-//   sqlc v1.29.0
-// source: seller.sql
-
-package db
-
-import (
-	"context"
-
-	"github.com/jackc/pgx/v5/pgtype"
-)
-
-const createSeller = `-- name: CreateSeller :one
-INSERT INTO sellers (
-  username, full_name, email, password, mobile_number,
-  store_name, gst_number, drug_license_number,
-  seller_type, store_address
-) VALUES (
-  $1, $2, $3, $4, $5,
-  $6, $7, $8,
-  $9, $10
-) RETURNING username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at
-`
-
-type CreateSellerParams struct {
-	Username          string `json:"username"`
-	FullName          string `json:"full_name"`
-	Email             string `json:"email"`
-	Password          string `json:"password"`
-	MobileNumber      string `json:"mobile_number"`
-	StoreName         string `json:"store_name"`
-	GstNumber         string `json:"gst_number"`
-	DrugLicenseNumber string `json:"drug_license_number"`
-	SellerType        string `json:"seller_type"`
-	StoreAddress      string `json:"store_address"`
-}
-
-func (q *Queries) CreateSeller(ctx context.Context, arg CreateSellerParams) (Seller, error) {
-	row := q.db.QueryRow(ctx, createSeller,
-		arg.Username,
-		arg.FullName,
-		arg.Email,
-		arg.Password,
-		arg.MobileNumber,
-		arg.StoreName,
-		arg.GstNumber,
-		arg.DrugLicenseNumber,
-		arg.SellerType,
-		arg.StoreAddress,
-	)
-	var i Seller
-	err := row.Scan(
-		&i.Username,
-		&i.FullName,
-		&i.Email,
-		&i.Password,
-		&i.MobileNumber,
-		&i.StoreName,
-		&i.GstNumber,
-		&i.DrugLicenseNumber,
-		&i.SellerType,
-		&i.StoreAddress,
-		&i.PasswordChangedAt,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const deleteSeller = `-- name: DeleteSeller :one
-DELETE FROM sellers WHERE username = $1
-RETURNING username
-`
-
-func (q *Queries) DeleteSeller(ctx context.Context, username string) (string, error) {
-	row := q.db.QueryRow(ctx, deleteSeller, username)
-	err := row.Scan(&username)
-	return username, err
-}
-
-const getSellerByName = `-- name: GetSellerByName :one
-SELECT username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at FROM sellers WHERE username = $1
-`
-
-func (q *Queries) GetSellerByName(ctx context.Context, username string) (Seller, error) {
-	row := q.db.QueryRow(ctx, getSellerByName, username)
-	var i Seller
-	err := row.Scan(
-		&i.Username,
-		&i.FullName,
-		&i.Email,
-		&i.Password,
-		&i.MobileNumber,
-		&i.StoreName,
-		&i.GstNumber,
-		&i.DrugLicenseNumber,
-		&i.SellerType,
-		&i.StoreAddress,
-		&i.PasswordChangedAt,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const listSellersByStoreName = `-- name: ListSellersByStoreName :many
-SELECT username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at FROM sellers
-WHERE store_name ILIKE '%' || $1 || '%'
-ORDER BY store_name
-LIMIT $3 OFFSET $2
-`
-
-type ListSellersByStoreNameParams struct {
-	StoreName pgtype.Text `json:"store_name"`
-	Offset    int32       `json:"offset"`
-	Limit     int32       `json:"limit"`
-}
-
-func (q *Queries) ListSellersByStoreName(ctx context.Context, arg ListSellersByStoreNameParams) ([]Seller, error) {
-	rows, err := q.db.Query(ctx, listSellersByStoreName, arg.StoreName, arg.Offset, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Seller{}
-	for rows.Next() {
-		var i Seller
-		if err := rows.Scan(
-			&i.Username,
-			&i.FullName,
-			&i.Email,
-			&i.Password,
-			&i.MobileNumber,
-			&i.StoreName,
-			&i.GstNumber,
-			&i.DrugLicenseNumber,
-			&i.SellerType,
-			&i.StoreAddress,
-			&i.PasswordChangedAt,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const updateSeller = `-- name: UpdateSeller :one
-UPDATE sellers SET
-  full_name = COALESCE($1, full_name),
-  email = COALESCE($2, email),
-  password = COALESCE($3, password),
-  mobile_number = COALESCE($4, mobile_number),
-  store_name = COALESCE($5, store_name),
-  gst_number = COALESCE($6, gst_number),
-  drug_license_number = COALESCE($7, drug_license_number),
-  seller_type = COALESCE($8, seller_type),
-  store_address = COALESCE($9, store_address),
-  password_changed_at = COALESCE($10, password_changed_at)
-WHERE username = $11
-RETURNING username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at
-`
-
-type UpdateSellerParams struct {
-	FullName          pgtype.Text      `json:"full_name"`
-	Email             pgtype.Text      `json:"email"`
-	Password          pgtype.Text      `json:"password"`
-	MobileNumber      pgtype.Text      `json:"mobile_number"`
-	StoreName         pgtype.Text      `json:"store_name"`
-	GstNumber         pgtype.Text      `json:"gst_number"`
-	DrugLicenseNumber pgtype.Text      `json:"drug_license_number"`
-	SellerType        pgtype.Text      `json:"seller_type"`
-	StoreAddress      pgtype.Text      `json:"store_address"`
-	PasswordChangedAt pgtype.Timestamp `json:"password_changed_at"`
-	Username          string           `json:"username"`
-}
-
-func (q *Queries) UpdateSeller(ctx context.Context, arg UpdateSellerParams) (Seller, error) {
-	row := q.db.QueryRow(ctx, updateSeller,
-		arg.FullName,
-		arg.Email,
-		arg.Password,
-		arg.MobileNumber,
-		arg.StoreName,
-		arg.GstNumber,
-		arg.DrugLicenseNumber,
-		arg.SellerType,
-		arg.StoreAddress,
-		arg.PasswordChangedAt,
-		arg.Username,
-	)
-	var i Seller
-	err := row.Scan(
-		&i.Username,
-		&i.FullName,
-		&i.Email,
-		&i.Password,
-		&i.MobileNumber,
-		&i.StoreName,
-		&i.GstNumber,
-		&i.DrugLicenseNumber,
-		&i.SellerType,
-		&i.StoreAddress,
-		&i.PasswordChangedAt,
-		&i.CreatedAt,
-	)
-	return i, err
-}
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: seller.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createSeller = `-- name: CreateSeller :one
+INSERT INTO sellers (
+  username, full_name, email, password, mobile_number,
+  store_name, gst_number, drug_license_number,
+  seller_type, store_address,
+  pincode, latitude, longitude, delivery_radius_km
+) VALUES (
+  $1, $2, $3, $4, $5,
+  $6, $7, $8,
+  $9, $10,
+  $11, $12, $13, $14
+) RETURNING username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at, pincode, latitude, longitude, delivery_radius_km, verified_at, organization_id, rating_average, delivery_rating_average, packaging_rating_average, authenticity_rating_average, review_count
+`
+
+type CreateSellerParams struct {
+	Username          string        `json:"username"`
+	FullName          string        `json:"full_name"`
+	Email             string        `json:"email"`
+	Password          string        `json:"password"`
+	MobileNumber      string        `json:"mobile_number"`
+	StoreName         string        `json:"store_name"`
+	GstNumber         string        `json:"gst_number"`
+	DrugLicenseNumber string        `json:"drug_license_number"`
+	SellerType        string        `json:"seller_type"`
+	StoreAddress      string        `json:"store_address"`
+	Pincode           pgtype.Text   `json:"pincode"`
+	Latitude          pgtype.Float8 `json:"latitude"`
+	Longitude         pgtype.Float8 `json:"longitude"`
+	DeliveryRadiusKm  float64       `json:"delivery_radius_km"`
+}
+
+func (q *Queries) CreateSeller(ctx context.Context, arg CreateSellerParams) (Seller, error) {
+	row := q.db.QueryRow(ctx, createSeller,
+		arg.Username,
+		arg.FullName,
+		arg.Email,
+		arg.Password,
+		arg.MobileNumber,
+		arg.StoreName,
+		arg.GstNumber,
+		arg.DrugLicenseNumber,
+		arg.SellerType,
+		arg.StoreAddress,
+		arg.Pincode,
+		arg.Latitude,
+		arg.Longitude,
+		arg.DeliveryRadiusKm,
+	)
+	var i Seller
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.MobileNumber,
+		&i.StoreName,
+		&i.GstNumber,
+		&i.DrugLicenseNumber,
+		&i.SellerType,
+		&i.StoreAddress,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Pincode,
+		&i.Latitude,
+		&i.Longitude,
+		&i.DeliveryRadiusKm,
+		&i.VerifiedAt,
+		&i.OrganizationID,
+		&i.RatingAverage,
+		&i.DeliveryRatingAverage,
+		&i.PackagingRatingAverage,
+		&i.AuthenticityRatingAverage,
+		&i.ReviewCount,
+	)
+	return i, err
+}
+
+const deleteSeller = `-- name: DeleteSeller :one
+DELETE FROM sellers WHERE username = $1
+RETURNING username
+`
+
+func (q *Queries) DeleteSeller(ctx context.Context, username string) (string, error) {
+	row := q.db.QueryRow(ctx, deleteSeller, username)
+	err := row.Scan(&username)
+	return username, err
+}
+
+const getSellerByName = `-- name: GetSellerByName :one
+SELECT username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at, pincode, latitude, longitude, delivery_radius_km, verified_at, organization_id, rating_average, delivery_rating_average, packaging_rating_average, authenticity_rating_average, review_count FROM sellers WHERE username = $1
+`
+
+func (q *Queries) GetSellerByName(ctx context.Context, username string) (Seller, error) {
+	row := q.db.QueryRow(ctx, getSellerByName, username)
+	var i Seller
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.MobileNumber,
+		&i.StoreName,
+		&i.GstNumber,
+		&i.DrugLicenseNumber,
+		&i.SellerType,
+		&i.StoreAddress,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Pincode,
+		&i.Latitude,
+		&i.Longitude,
+		&i.DeliveryRadiusKm,
+		&i.VerifiedAt,
+		&i.OrganizationID,
+		&i.RatingAverage,
+		&i.DeliveryRatingAverage,
+		&i.PackagingRatingAverage,
+		&i.AuthenticityRatingAverage,
+		&i.ReviewCount,
+	)
+	return i, err
+}
+
+const listNearbySellersWithMedicine = `-- name: ListNearbySellersWithMedicine :many
+SELECT username, store_name, store_address, seller_type, mobile_number,
+    pincode, latitude, longitude, delivery_radius_km,
+    medicine_id, medicine_name, quantity, price, discount, expiry_date,
+    effective_price, distance_km, open_now, organization_id, organization_name
+FROM (
+    SELECT nearby.username, nearby.store_name, nearby.store_address, nearby.seller_type, nearby.mobile_number, nearby.pincode, nearby.latitude, nearby.longitude, nearby.delivery_radius_km, nearby.medicine_id, nearby.medicine_name, nearby.quantity, nearby.price, nearby.discount, nearby.expiry_date, nearby.effective_price, nearby.distance_km, nearby.open_now, nearby.organization_id, nearby.organization_name,
+        ROW_NUMBER() OVER (
+            PARTITION BY CASE WHEN nearby.organization_id IS NULL
+                              THEN 'medicine:' || nearby.medicine_id
+                              ELSE 'organization:' || nearby.organization_id || ':' || lower(nearby.medicine_name) END
+            ORDER BY nearby.distance_km ASC, nearby.effective_price ASC
+        ) AS branch_rank
+    FROM (
+        SELECT s.username, s.store_name, s.store_address, s.seller_type, s.mobile_number,
+            s.pincode, s.latitude, s.longitude, s.delivery_radius_km,
+            m.id AS medicine_id, m.name AS medicine_name, m.quantity, m.price, m.discount, m.expiry_date,
+            (m.price * (100 - m.discount) / 100)::NUMERIC(10, 2) AS effective_price,
+            haversine_km($1::FLOAT8, $2::FLOAT8, s.latitude, s.longitude)::FLOAT8 AS distance_km,
+            EXISTS (
+                SELECT 1 FROM seller_opening_hours h
+                WHERE h.seller_username = s.username
+                  AND h.day_of_week = EXTRACT(DOW FROM LOCALTIMESTAMP)
+                  AND LOCALTIME BETWEEN h.opens_at AND h.closes_at
+            ) AS open_now,
+            s.organization_id, o.name AS organization_name
+        FROM sellers s
+        JOIN medicines m ON m.seller_username = s.username
+        LEFT JOIN organizations o ON o.id = s.organization_id
+        WHERE s.latitude IS NOT NULL
+          AND s.longitude IS NOT NULL
+          -- cheap bounding box on latitude (1 degree is roughly 111 km) before the exact distance
+          AND s.latitude BETWEEN $1 - $3::FLOAT8 / 111
+                             AND $1 + $3 / 111
+          AND m.quantity > 0
+          AND m.expiry_date > CURRENT_DATE
+          AND m.status = 'active'
+          AND (m.name ILIKE '%' || $4::VARCHAR || '%' OR m.name % $4)
+    ) nearby
+    WHERE nearby.distance_km <= $3
+) ranked
+WHERE branch_rank = 1
+ORDER BY distance_km ASC, effective_price ASC
+LIMIT $6 OFFSET $5
+`
+
+type ListNearbySellersWithMedicineParams struct {
+	Latitude      float64 `json:"latitude"`
+	Longitude     float64 `json:"longitude"`
+	MaxDistanceKm float64 `json:"max_distance_km"`
+	Medicine      string  `json:"medicine"`
+	Offset        int32   `json:"offset"`
+	Limit         int32   `json:"limit"`
+}
+
+type ListNearbySellersWithMedicineRow struct {
+	Username         string         `json:"username"`
+	StoreName        string         `json:"store_name"`
+	StoreAddress     string         `json:"store_address"`
+	SellerType       string         `json:"seller_type"`
+	MobileNumber     string         `json:"mobile_number"`
+	Pincode          pgtype.Text    `json:"pincode"`
+	Latitude         pgtype.Float8  `json:"latitude"`
+	Longitude        pgtype.Float8  `json:"longitude"`
+	DeliveryRadiusKm float64        `json:"delivery_radius_km"`
+	MedicineID       int32          `json:"medicine_id"`
+	MedicineName     string         `json:"medicine_name"`
+	Quantity         int32          `json:"quantity"`
+	Price            pgtype.Numeric `json:"price"`
+	Discount         int32          `json:"discount"`
+	ExpiryDate       pgtype.Date    `json:"expiry_date"`
+	EffectivePrice   pgtype.Numeric `json:"effective_price"`
+	DistanceKm       float64        `json:"distance_km"`
+	OpenNow          bool           `json:"open_now"`
+	OrganizationID   pgtype.Int4    `json:"organization_id"`
+	OrganizationName pgtype.Text    `json:"organization_name"`
+}
+
+// Branches of a chain are collapsed to the one nearest the patient for each
+// medicine, so that a chain doesn't fill the results with its own stores
+func (q *Queries) ListNearbySellersWithMedicine(ctx context.Context, arg ListNearbySellersWithMedicineParams) ([]ListNearbySellersWithMedicineRow, error) {
+	rows, err := q.db.Query(ctx, listNearbySellersWithMedicine,
+		arg.Latitude,
+		arg.Longitude,
+		arg.MaxDistanceKm,
+		arg.Medicine,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListNearbySellersWithMedicineRow{}
+	for rows.Next() {
+		var i ListNearbySellersWithMedicineRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.StoreName,
+			&i.StoreAddress,
+			&i.SellerType,
+			&i.MobileNumber,
+			&i.Pincode,
+			&i.Latitude,
+			&i.Longitude,
+			&i.DeliveryRadiusKm,
+			&i.MedicineID,
+			&i.MedicineName,
+			&i.Quantity,
+			&i.Price,
+			&i.Discount,
+			&i.ExpiryDate,
+			&i.EffectivePrice,
+			&i.DistanceKm,
+			&i.OpenNow,
+			&i.OrganizationID,
+			&i.OrganizationName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSellersByStoreName = `-- name: ListSellersByStoreName :many
+SELECT username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at, pincode, latitude, longitude, delivery_radius_km, verified_at, organization_id, rating_average, delivery_rating_average, packaging_rating_average, authenticity_rating_average, review_count FROM sellers
+WHERE store_name ILIKE '%' || $1 || '%'
+ORDER BY store_name
+LIMIT $3 OFFSET $2
+`
+
+type ListSellersByStoreNameParams struct {
+	StoreName pgtype.Text `json:"store_name"`
+	Offset    int32       `json:"offset"`
+	Limit     int32       `json:"limit"`
+}
+
+func (q *Queries) ListSellersByStoreName(ctx context.Context, arg ListSellersByStoreNameParams) ([]Seller, error) {
+	rows, err := q.db.Query(ctx, listSellersByStoreName, arg.StoreName, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Seller{}
+	for rows.Next() {
+		var i Seller
+		if err := rows.Scan(
+			&i.Username,
+			&i.FullName,
+			&i.Email,
+			&i.Password,
+			&i.MobileNumber,
+			&i.StoreName,
+			&i.GstNumber,
+			&i.DrugLicenseNumber,
+			&i.SellerType,
+			&i.StoreAddress,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.Pincode,
+			&i.Latitude,
+			&i.Longitude,
+			&i.DeliveryRadiusKm,
+			&i.VerifiedAt,
+			&i.OrganizationID,
+			&i.RatingAverage,
+			&i.DeliveryRatingAverage,
+			&i.PackagingRatingAverage,
+			&i.AuthenticityRatingAverage,
+			&i.ReviewCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnverifiedSellers = `-- name: ListUnverifiedSellers :many
+SELECT username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at, pincode, latitude, longitude, delivery_radius_km, verified_at, organization_id, rating_average, delivery_rating_average, packaging_rating_average, authenticity_rating_average, review_count FROM sellers
+WHERE seller_type IN ('ngo', 'hospital') AND verified_at IS NULL
+ORDER BY created_at ASC
+LIMIT $1 OFFSET $2
+`
+
+type ListUnverifiedSellersParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListUnverifiedSellers(ctx context.Context, arg ListUnverifiedSellersParams) ([]Seller, error) {
+	rows, err := q.db.Query(ctx, listUnverifiedSellers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Seller{}
+	for rows.Next() {
+		var i Seller
+		if err := rows.Scan(
+			&i.Username,
+			&i.FullName,
+			&i.Email,
+			&i.Password,
+			&i.MobileNumber,
+			&i.StoreName,
+			&i.GstNumber,
+			&i.DrugLicenseNumber,
+			&i.SellerType,
+			&i.StoreAddress,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.Pincode,
+			&i.Latitude,
+			&i.Longitude,
+			&i.DeliveryRadiusKm,
+			&i.VerifiedAt,
+			&i.OrganizationID,
+			&i.RatingAverage,
+			&i.DeliveryRatingAverage,
+			&i.PackagingRatingAverage,
+			&i.AuthenticityRatingAverage,
+			&i.ReviewCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateSeller = `-- name: UpdateSeller :one
+UPDATE sellers SET
+  full_name = COALESCE($1, full_name),
+  email = COALESCE($2, email),
+  password = COALESCE($3, password),
+  mobile_number = COALESCE($4, mobile_number),
+  store_name = COALESCE($5, store_name),
+  gst_number = COALESCE($6, gst_number),
+  drug_license_number = COALESCE($7, drug_license_number),
+  seller_type = COALESCE($8, seller_type),
+  store_address = COALESCE($9, store_address),
+  pincode = COALESCE($10, pincode),
+  latitude = COALESCE($11, latitude),
+  longitude = COALESCE($12, longitude),
+  delivery_radius_km = COALESCE($13, delivery_radius_km),
+  password_changed_at = COALESCE($14, password_changed_at),
+  -- a seller changing type has to be verified again
+  verified_at = CASE WHEN $8 IS NOT NULL AND $8 <> seller_type
+                     THEN NULL ELSE verified_at END
+WHERE username = $15
+RETURNING username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at, pincode, latitude, longitude, delivery_radius_km, verified_at, organization_id, rating_average, delivery_rating_average, packaging_rating_average, authenticity_rating_average, review_count
+`
+
+type UpdateSellerParams struct {
+	FullName          pgtype.Text      `json:"full_name"`
+	Email             pgtype.Text      `json:"email"`
+	Password          pgtype.Text      `json:"password"`
+	MobileNumber      pgtype.Text      `json:"mobile_number"`
+	StoreName         pgtype.Text      `json:"store_name"`
+	GstNumber         pgtype.Text      `json:"gst_number"`
+	DrugLicenseNumber pgtype.Text      `json:"drug_license_number"`
+	SellerType        pgtype.Text      `json:"seller_type"`
+	StoreAddress      pgtype.Text      `json:"store_address"`
+	Pincode           pgtype.Text      `json:"pincode"`
+	Latitude          pgtype.Float8    `json:"latitude"`
+	Longitude         pgtype.Float8    `json:"longitude"`
+	DeliveryRadiusKm  pgtype.Float8    `json:"delivery_radius_km"`
+	PasswordChangedAt pgtype.Timestamp `json:"password_changed_at"`
+	Username          string           `json:"username"`
+}
+
+func (q *Queries) UpdateSeller(ctx context.Context, arg UpdateSellerParams) (Seller, error) {
+	row := q.db.QueryRow(ctx, updateSeller,
+		arg.FullName,
+		arg.Email,
+		arg.Password,
+		arg.MobileNumber,
+		arg.StoreName,
+		arg.GstNumber,
+		arg.DrugLicenseNumber,
+		arg.SellerType,
+		arg.StoreAddress,
+		arg.Pincode,
+		arg.Latitude,
+		arg.Longitude,
+		arg.DeliveryRadiusKm,
+		arg.PasswordChangedAt,
+		arg.Username,
+	)
+	var i Seller
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.MobileNumber,
+		&i.StoreName,
+		&i.GstNumber,
+		&i.DrugLicenseNumber,
+		&i.SellerType,
+		&i.StoreAddress,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Pincode,
+		&i.Latitude,
+		&i.Longitude,
+		&i.DeliveryRadiusKm,
+		&i.VerifiedAt,
+		&i.OrganizationID,
+		&i.RatingAverage,
+		&i.DeliveryRatingAverage,
+		&i.PackagingRatingAverage,
+		&i.AuthenticityRatingAverage,
+		&i.ReviewCount,
+	)
+	return i, err
+}
+
+const verifySeller = `-- name: VerifySeller :one
+UPDATE sellers SET verified_at = COALESCE(verified_at, now())
+WHERE username = $1
+RETURNING username, full_name, email, password, mobile_number, store_name, gst_number, drug_license_number, seller_type, store_address, password_changed_at, created_at, pincode, latitude, longitude, delivery_radius_km, verified_at, organization_id, rating_average, delivery_rating_average, packaging_rating_average, authenticity_rating_average, review_count
+`
+
+func (q *Queries) VerifySeller(ctx context.Context, username string) (Seller, error) {
+	row := q.db.QueryRow(ctx, verifySeller, username)
+	var i Seller
+	err := row.Scan(
+		&i.Username,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.MobileNumber,
+		&i.StoreName,
+		&i.GstNumber,
+		&i.DrugLicenseNumber,
+		&i.SellerType,
+		&i.StoreAddress,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Pincode,
+		&i.Latitude,
+		&i.Longitude,
+		&i.DeliveryRadiusKm,
+		&i.VerifiedAt,
+		&i.OrganizationID,
+		&i.RatingAverage,
+		&i.DeliveryRatingAverage,
+		&i.PackagingRatingAverage,
+		&i.AuthenticityRatingAverage,
+		&i.ReviewCount,
+	)
+	return i, err
+}
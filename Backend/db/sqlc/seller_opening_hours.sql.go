@@ -0,0 +1,88 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: seller_opening_hours.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createSellerOpeningHours = `-- name: CreateSellerOpeningHours :one
+INSERT INTO seller_opening_hours (
+    seller_username, day_of_week, opens_at, closes_at
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, seller_username, day_of_week, opens_at, closes_at
+`
+
+type CreateSellerOpeningHoursParams struct {
+	SellerUsername string      `json:"seller_username"`
+	DayOfWeek      int16       `json:"day_of_week"`
+	OpensAt        pgtype.Time `json:"opens_at"`
+	ClosesAt       pgtype.Time `json:"closes_at"`
+}
+
+func (q *Queries) CreateSellerOpeningHours(ctx context.Context, arg CreateSellerOpeningHoursParams) (SellerOpeningHour, error) {
+	row := q.db.QueryRow(ctx, createSellerOpeningHours,
+		arg.SellerUsername,
+		arg.DayOfWeek,
+		arg.OpensAt,
+		arg.ClosesAt,
+	)
+	var i SellerOpeningHour
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.DayOfWeek,
+		&i.OpensAt,
+		&i.ClosesAt,
+	)
+	return i, err
+}
+
+const deleteSellerOpeningHours = `-- name: DeleteSellerOpeningHours :exec
+DELETE FROM seller_opening_hours
+WHERE seller_username = $1
+`
+
+func (q *Queries) DeleteSellerOpeningHours(ctx context.Context, sellerUsername string) error {
+	_, err := q.db.Exec(ctx, deleteSellerOpeningHours, sellerUsername)
+	return err
+}
+
+const listSellerOpeningHours = `-- name: ListSellerOpeningHours :many
+SELECT id, seller_username, day_of_week, opens_at, closes_at FROM seller_opening_hours
+WHERE seller_username = $1
+ORDER BY day_of_week, opens_at
+`
+
+func (q *Queries) ListSellerOpeningHours(ctx context.Context, sellerUsername string) ([]SellerOpeningHour, error) {
+	rows, err := q.db.Query(ctx, listSellerOpeningHours, sellerUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SellerOpeningHour{}
+	for rows.Next() {
+		var i SellerOpeningHour
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerUsername,
+			&i.DayOfWeek,
+			&i.OpensAt,
+			&i.ClosesAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,335 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: staff.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const acceptStaffInvite = `-- name: AcceptStaffInvite :one
+UPDATE staff_invites SET accepted_at = now()
+WHERE id = $1
+RETURNING id, seller_username, email, staff_role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at
+`
+
+func (q *Queries) AcceptStaffInvite(ctx context.Context, id int32) (StaffInvite, error) {
+	row := q.db.QueryRow(ctx, acceptStaffInvite, id)
+	var i StaffInvite
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.Email,
+		&i.StaffRole,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createStaffInvite = `-- name: CreateStaffInvite :one
+INSERT INTO staff_invites (
+    seller_username, email, staff_role, token_hash, invited_by, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, seller_username, email, staff_role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at
+`
+
+type CreateStaffInviteParams struct {
+	SellerUsername string           `json:"seller_username"`
+	Email          string           `json:"email"`
+	StaffRole      string           `json:"staff_role"`
+	TokenHash      string           `json:"token_hash"`
+	InvitedBy      string           `json:"invited_by"`
+	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) CreateStaffInvite(ctx context.Context, arg CreateStaffInviteParams) (StaffInvite, error) {
+	row := q.db.QueryRow(ctx, createStaffInvite,
+		arg.SellerUsername,
+		arg.Email,
+		arg.StaffRole,
+		arg.TokenHash,
+		arg.InvitedBy,
+		arg.ExpiresAt,
+	)
+	var i StaffInvite
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.Email,
+		&i.StaffRole,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createStoreStaff = `-- name: CreateStoreStaff :one
+INSERT INTO store_staff (
+    username, seller_username, full_name, email, password, staff_role, invited_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING username, seller_username, full_name, email, password, staff_role, invited_by, password_changed_at, created_at
+`
+
+type CreateStoreStaffParams struct {
+	Username       string `json:"username"`
+	SellerUsername string `json:"seller_username"`
+	FullName       string `json:"full_name"`
+	Email          string `json:"email"`
+	Password       string `json:"password"`
+	StaffRole      string `json:"staff_role"`
+	InvitedBy      string `json:"invited_by"`
+}
+
+func (q *Queries) CreateStoreStaff(ctx context.Context, arg CreateStoreStaffParams) (StoreStaff, error) {
+	row := q.db.QueryRow(ctx, createStoreStaff,
+		arg.Username,
+		arg.SellerUsername,
+		arg.FullName,
+		arg.Email,
+		arg.Password,
+		arg.StaffRole,
+		arg.InvitedBy,
+	)
+	var i StoreStaff
+	err := row.Scan(
+		&i.Username,
+		&i.SellerUsername,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.StaffRole,
+		&i.InvitedBy,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteStoreStaff = `-- name: DeleteStoreStaff :one
+DELETE FROM store_staff
+WHERE username = $1 AND seller_username = $2
+RETURNING username
+`
+
+type DeleteStoreStaffParams struct {
+	Username       string `json:"username"`
+	SellerUsername string `json:"seller_username"`
+}
+
+func (q *Queries) DeleteStoreStaff(ctx context.Context, arg DeleteStoreStaffParams) (string, error) {
+	row := q.db.QueryRow(ctx, deleteStoreStaff, arg.Username, arg.SellerUsername)
+	var username string
+	err := row.Scan(&username)
+	return username, err
+}
+
+const getStaffInviteByTokenForUpdate = `-- name: GetStaffInviteByTokenForUpdate :one
+SELECT id, seller_username, email, staff_role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at FROM staff_invites
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetStaffInviteByTokenForUpdate(ctx context.Context, tokenHash string) (StaffInvite, error) {
+	row := q.db.QueryRow(ctx, getStaffInviteByTokenForUpdate, tokenHash)
+	var i StaffInvite
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.Email,
+		&i.StaffRole,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStoreStaff = `-- name: GetStoreStaff :one
+SELECT username, seller_username, full_name, email, password, staff_role, invited_by, password_changed_at, created_at FROM store_staff WHERE username = $1
+`
+
+func (q *Queries) GetStoreStaff(ctx context.Context, username string) (StoreStaff, error) {
+	row := q.db.QueryRow(ctx, getStoreStaff, username)
+	var i StoreStaff
+	err := row.Scan(
+		&i.Username,
+		&i.SellerUsername,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.StaffRole,
+		&i.InvitedBy,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listPendingStaffInvites = `-- name: ListPendingStaffInvites :many
+SELECT id, seller_username, email, staff_role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at FROM staff_invites
+WHERE seller_username = $1
+  AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > now()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPendingStaffInvites(ctx context.Context, sellerUsername string) ([]StaffInvite, error) {
+	rows, err := q.db.Query(ctx, listPendingStaffInvites, sellerUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StaffInvite{}
+	for rows.Next() {
+		var i StaffInvite
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerUsername,
+			&i.Email,
+			&i.StaffRole,
+			&i.TokenHash,
+			&i.InvitedBy,
+			&i.ExpiresAt,
+			&i.AcceptedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreStaff = `-- name: ListStoreStaff :many
+SELECT username, seller_username, full_name, email, password, staff_role, invited_by, password_changed_at, created_at FROM store_staff
+WHERE seller_username = $1
+ORDER BY created_at ASC, username ASC
+`
+
+func (q *Queries) ListStoreStaff(ctx context.Context, sellerUsername string) ([]StoreStaff, error) {
+	rows, err := q.db.Query(ctx, listStoreStaff, sellerUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StoreStaff{}
+	for rows.Next() {
+		var i StoreStaff
+		if err := rows.Scan(
+			&i.Username,
+			&i.SellerUsername,
+			&i.FullName,
+			&i.Email,
+			&i.Password,
+			&i.StaffRole,
+			&i.InvitedBy,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeExpiredStaffInvites = `-- name: RevokeExpiredStaffInvites :exec
+UPDATE staff_invites SET revoked_at = now()
+WHERE seller_username = $1 AND lower(email) = lower($2)
+  AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= now()
+`
+
+type RevokeExpiredStaffInvitesParams struct {
+	SellerUsername string `json:"seller_username"`
+	Email          string `json:"email"`
+}
+
+// Frees the pending slot of an email whose invite expired, so it can be
+// invited again
+func (q *Queries) RevokeExpiredStaffInvites(ctx context.Context, arg RevokeExpiredStaffInvitesParams) error {
+	_, err := q.db.Exec(ctx, revokeExpiredStaffInvites, arg.SellerUsername, arg.Email)
+	return err
+}
+
+const revokeStaffInvite = `-- name: RevokeStaffInvite :one
+UPDATE staff_invites SET revoked_at = now()
+WHERE id = $1 AND seller_username = $2
+  AND accepted_at IS NULL AND revoked_at IS NULL
+RETURNING id, seller_username, email, staff_role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at
+`
+
+type RevokeStaffInviteParams struct {
+	ID             int32  `json:"id"`
+	SellerUsername string `json:"seller_username"`
+}
+
+func (q *Queries) RevokeStaffInvite(ctx context.Context, arg RevokeStaffInviteParams) (StaffInvite, error) {
+	row := q.db.QueryRow(ctx, revokeStaffInvite, arg.ID, arg.SellerUsername)
+	var i StaffInvite
+	err := row.Scan(
+		&i.ID,
+		&i.SellerUsername,
+		&i.Email,
+		&i.StaffRole,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateStoreStaffRole = `-- name: UpdateStoreStaffRole :one
+UPDATE store_staff SET staff_role = $1
+WHERE username = $2 AND seller_username = $3
+RETURNING username, seller_username, full_name, email, password, staff_role, invited_by, password_changed_at, created_at
+`
+
+type UpdateStoreStaffRoleParams struct {
+	StaffRole      string `json:"staff_role"`
+	Username       string `json:"username"`
+	SellerUsername string `json:"seller_username"`
+}
+
+func (q *Queries) UpdateStoreStaffRole(ctx context.Context, arg UpdateStoreStaffRoleParams) (StoreStaff, error) {
+	row := q.db.QueryRow(ctx, updateStoreStaffRole, arg.StaffRole, arg.Username, arg.SellerUsername)
+	var i StoreStaff
+	err := row.Scan(
+		&i.Username,
+		&i.SellerUsername,
+		&i.FullName,
+		&i.Email,
+		&i.Password,
+		&i.StaffRole,
+		&i.InvitedBy,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
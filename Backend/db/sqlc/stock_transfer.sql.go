@@ -0,0 +1,429 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: stock_transfer.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createStockTransfer = `-- name: CreateStockTransfer :one
+INSERT INTO stock_transfers (
+    organization_id, from_seller, to_seller, medicine_id, name, description,
+    active_ingredients, strength, dosage_form, prescription_required,
+    batch_number, expiry_date, price, quantity, notes, requested_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+)
+RETURNING id, organization_id, from_seller, to_seller, status, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, price, quantity, notes, requested_by, dispatched_by, received_by, received_medicine_id, created_at, updated_at
+`
+
+type CreateStockTransferParams struct {
+	OrganizationID       int32          `json:"organization_id"`
+	FromSeller           string         `json:"from_seller"`
+	ToSeller             string         `json:"to_seller"`
+	MedicineID           int32          `json:"medicine_id"`
+	Name                 string         `json:"name"`
+	Description          string         `json:"description"`
+	ActiveIngredients    string         `json:"active_ingredients"`
+	Strength             string         `json:"strength"`
+	DosageForm           string         `json:"dosage_form"`
+	PrescriptionRequired bool           `json:"prescription_required"`
+	BatchNumber          string         `json:"batch_number"`
+	ExpiryDate           pgtype.Date    `json:"expiry_date"`
+	Price                pgtype.Numeric `json:"price"`
+	Quantity             int32          `json:"quantity"`
+	Notes                string         `json:"notes"`
+	RequestedBy          string         `json:"requested_by"`
+}
+
+func (q *Queries) CreateStockTransfer(ctx context.Context, arg CreateStockTransferParams) (StockTransfer, error) {
+	row := q.db.QueryRow(ctx, createStockTransfer,
+		arg.OrganizationID,
+		arg.FromSeller,
+		arg.ToSeller,
+		arg.MedicineID,
+		arg.Name,
+		arg.Description,
+		arg.ActiveIngredients,
+		arg.Strength,
+		arg.DosageForm,
+		arg.PrescriptionRequired,
+		arg.BatchNumber,
+		arg.ExpiryDate,
+		arg.Price,
+		arg.Quantity,
+		arg.Notes,
+		arg.RequestedBy,
+	)
+	var i StockTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.FromSeller,
+		&i.ToSeller,
+		&i.Status,
+		&i.MedicineID,
+		&i.Name,
+		&i.Description,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.ExpiryDate,
+		&i.Price,
+		&i.Quantity,
+		&i.Notes,
+		&i.RequestedBy,
+		&i.DispatchedBy,
+		&i.ReceivedBy,
+		&i.ReceivedMedicineID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStockTransfer = `-- name: GetStockTransfer :one
+SELECT id, organization_id, from_seller, to_seller, status, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, price, quantity, notes, requested_by, dispatched_by, received_by, received_medicine_id, created_at, updated_at FROM stock_transfers WHERE id = $1
+`
+
+func (q *Queries) GetStockTransfer(ctx context.Context, id int32) (StockTransfer, error) {
+	row := q.db.QueryRow(ctx, getStockTransfer, id)
+	var i StockTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.FromSeller,
+		&i.ToSeller,
+		&i.Status,
+		&i.MedicineID,
+		&i.Name,
+		&i.Description,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.ExpiryDate,
+		&i.Price,
+		&i.Quantity,
+		&i.Notes,
+		&i.RequestedBy,
+		&i.DispatchedBy,
+		&i.ReceivedBy,
+		&i.ReceivedMedicineID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStockTransferForUpdate = `-- name: GetStockTransferForUpdate :one
+SELECT id, organization_id, from_seller, to_seller, status, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, price, quantity, notes, requested_by, dispatched_by, received_by, received_medicine_id, created_at, updated_at FROM stock_transfers WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetStockTransferForUpdate(ctx context.Context, id int32) (StockTransfer, error) {
+	row := q.db.QueryRow(ctx, getStockTransferForUpdate, id)
+	var i StockTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.FromSeller,
+		&i.ToSeller,
+		&i.Status,
+		&i.MedicineID,
+		&i.Name,
+		&i.Description,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.ExpiryDate,
+		&i.Price,
+		&i.Quantity,
+		&i.Notes,
+		&i.RequestedBy,
+		&i.DispatchedBy,
+		&i.ReceivedBy,
+		&i.ReceivedMedicineID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listOrganizationStockTransfers = `-- name: ListOrganizationStockTransfers :many
+SELECT id, organization_id, from_seller, to_seller, status, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, price, quantity, notes, requested_by, dispatched_by, received_by, received_medicine_id, created_at, updated_at FROM stock_transfers
+WHERE organization_id = $1
+  AND ($2::VARCHAR IS NULL OR status = $2)
+ORDER BY created_at DESC, id DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListOrganizationStockTransfersParams struct {
+	OrganizationID int32       `json:"organization_id"`
+	Status         pgtype.Text `json:"status"`
+	Offset         int32       `json:"offset"`
+	Limit          int32       `json:"limit"`
+}
+
+func (q *Queries) ListOrganizationStockTransfers(ctx context.Context, arg ListOrganizationStockTransfersParams) ([]StockTransfer, error) {
+	rows, err := q.db.Query(ctx, listOrganizationStockTransfers,
+		arg.OrganizationID,
+		arg.Status,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StockTransfer{}
+	for rows.Next() {
+		var i StockTransfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.FromSeller,
+			&i.ToSeller,
+			&i.Status,
+			&i.MedicineID,
+			&i.Name,
+			&i.Description,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.ExpiryDate,
+			&i.Price,
+			&i.Quantity,
+			&i.Notes,
+			&i.RequestedBy,
+			&i.DispatchedBy,
+			&i.ReceivedBy,
+			&i.ReceivedMedicineID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSellerStockTransfers = `-- name: ListSellerStockTransfers :many
+SELECT id, organization_id, from_seller, to_seller, status, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, price, quantity, notes, requested_by, dispatched_by, received_by, received_medicine_id, created_at, updated_at FROM stock_transfers
+WHERE (from_seller = $1 OR to_seller = $1)
+  AND ($2::VARCHAR IS NULL OR status = $2)
+ORDER BY created_at DESC, id DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListSellerStockTransfersParams struct {
+	Seller string      `json:"seller"`
+	Status pgtype.Text `json:"status"`
+	Offset int32       `json:"offset"`
+	Limit  int32       `json:"limit"`
+}
+
+// Transfers the branch sends or receives
+func (q *Queries) ListSellerStockTransfers(ctx context.Context, arg ListSellerStockTransfersParams) ([]StockTransfer, error) {
+	rows, err := q.db.Query(ctx, listSellerStockTransfers,
+		arg.Seller,
+		arg.Status,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StockTransfer{}
+	for rows.Next() {
+		var i StockTransfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.FromSeller,
+			&i.ToSeller,
+			&i.Status,
+			&i.MedicineID,
+			&i.Name,
+			&i.Description,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.ExpiryDate,
+			&i.Price,
+			&i.Quantity,
+			&i.Notes,
+			&i.RequestedBy,
+			&i.DispatchedBy,
+			&i.ReceivedBy,
+			&i.ReceivedMedicineID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markStockTransferDispatched = `-- name: MarkStockTransferDispatched :one
+UPDATE stock_transfers SET
+    status = 'dispatched',
+    dispatched_by = $2,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, organization_id, from_seller, to_seller, status, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, price, quantity, notes, requested_by, dispatched_by, received_by, received_medicine_id, created_at, updated_at
+`
+
+type MarkStockTransferDispatchedParams struct {
+	ID           int32  `json:"id"`
+	DispatchedBy string `json:"dispatched_by"`
+}
+
+func (q *Queries) MarkStockTransferDispatched(ctx context.Context, arg MarkStockTransferDispatchedParams) (StockTransfer, error) {
+	row := q.db.QueryRow(ctx, markStockTransferDispatched, arg.ID, arg.DispatchedBy)
+	var i StockTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.FromSeller,
+		&i.ToSeller,
+		&i.Status,
+		&i.MedicineID,
+		&i.Name,
+		&i.Description,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.ExpiryDate,
+		&i.Price,
+		&i.Quantity,
+		&i.Notes,
+		&i.RequestedBy,
+		&i.DispatchedBy,
+		&i.ReceivedBy,
+		&i.ReceivedMedicineID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markStockTransferReceived = `-- name: MarkStockTransferReceived :one
+UPDATE stock_transfers SET
+    status = 'received',
+    received_by = $2,
+    received_medicine_id = $3,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, organization_id, from_seller, to_seller, status, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, price, quantity, notes, requested_by, dispatched_by, received_by, received_medicine_id, created_at, updated_at
+`
+
+type MarkStockTransferReceivedParams struct {
+	ID                 int32       `json:"id"`
+	ReceivedBy         string      `json:"received_by"`
+	ReceivedMedicineID pgtype.Int4 `json:"received_medicine_id"`
+}
+
+func (q *Queries) MarkStockTransferReceived(ctx context.Context, arg MarkStockTransferReceivedParams) (StockTransfer, error) {
+	row := q.db.QueryRow(ctx, markStockTransferReceived, arg.ID, arg.ReceivedBy, arg.ReceivedMedicineID)
+	var i StockTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.FromSeller,
+		&i.ToSeller,
+		&i.Status,
+		&i.MedicineID,
+		&i.Name,
+		&i.Description,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.ExpiryDate,
+		&i.Price,
+		&i.Quantity,
+		&i.Notes,
+		&i.RequestedBy,
+		&i.DispatchedBy,
+		&i.ReceivedBy,
+		&i.ReceivedMedicineID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateStockTransferStatus = `-- name: UpdateStockTransferStatus :one
+UPDATE stock_transfers SET
+    status = $1,
+    updated_at = now()
+WHERE id = $2 AND status = $3
+RETURNING id, organization_id, from_seller, to_seller, status, medicine_id, name, description, active_ingredients, strength, dosage_form, prescription_required, batch_number, expiry_date, price, quantity, notes, requested_by, dispatched_by, received_by, received_medicine_id, created_at, updated_at
+`
+
+type UpdateStockTransferStatusParams struct {
+	Status     string `json:"status"`
+	ID         int32  `json:"id"`
+	FromStatus string `json:"from_status"`
+}
+
+// Moves a transfer on only if it is still in from_status, so concurrent
+// changes can't both succeed
+func (q *Queries) UpdateStockTransferStatus(ctx context.Context, arg UpdateStockTransferStatusParams) (StockTransfer, error) {
+	row := q.db.QueryRow(ctx, updateStockTransferStatus, arg.Status, arg.ID, arg.FromStatus)
+	var i StockTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.FromSeller,
+		&i.ToSeller,
+		&i.Status,
+		&i.MedicineID,
+		&i.Name,
+		&i.Description,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.ExpiryDate,
+		&i.Price,
+		&i.Quantity,
+		&i.Notes,
+		&i.RequestedBy,
+		&i.DispatchedBy,
+		&i.ReceivedBy,
+		&i.ReceivedMedicineID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,656 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: store_review.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createMedicineReview = `-- name: CreateMedicineReview :one
+INSERT INTO medicine_reviews (
+    order_item_id, medicine_id, medicine_name, seller_username,
+    patient_username, rating, comment, status, moderation_note
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, order_item_id, medicine_id, medicine_name, seller_username, patient_username, rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at
+`
+
+type CreateMedicineReviewParams struct {
+	OrderItemID     int32  `json:"order_item_id"`
+	MedicineID      int32  `json:"medicine_id"`
+	MedicineName    string `json:"medicine_name"`
+	SellerUsername  string `json:"seller_username"`
+	PatientUsername string `json:"patient_username"`
+	Rating          int16  `json:"rating"`
+	Comment         string `json:"comment"`
+	Status          string `json:"status"`
+	ModerationNote  string `json:"moderation_note"`
+}
+
+func (q *Queries) CreateMedicineReview(ctx context.Context, arg CreateMedicineReviewParams) (MedicineReview, error) {
+	row := q.db.QueryRow(ctx, createMedicineReview,
+		arg.OrderItemID,
+		arg.MedicineID,
+		arg.MedicineName,
+		arg.SellerUsername,
+		arg.PatientUsername,
+		arg.Rating,
+		arg.Comment,
+		arg.Status,
+		arg.ModerationNote,
+	)
+	var i MedicineReview
+	err := row.Scan(
+		&i.ID,
+		&i.OrderItemID,
+		&i.MedicineID,
+		&i.MedicineName,
+		&i.SellerUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.SellerReply,
+		&i.RepliedBy,
+		&i.RepliedAt,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const createSellerReview = `-- name: CreateSellerReview :one
+INSERT INTO seller_reviews (
+    sub_order_id, seller_username, patient_username, delivery_rating,
+    packaging_rating, authenticity_rating, comment, status, moderation_note
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, sub_order_id, seller_username, patient_username, delivery_rating, packaging_rating, authenticity_rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at
+`
+
+type CreateSellerReviewParams struct {
+	SubOrderID         int32  `json:"sub_order_id"`
+	SellerUsername     string `json:"seller_username"`
+	PatientUsername    string `json:"patient_username"`
+	DeliveryRating     int16  `json:"delivery_rating"`
+	PackagingRating    int16  `json:"packaging_rating"`
+	AuthenticityRating int16  `json:"authenticity_rating"`
+	Comment            string `json:"comment"`
+	Status             string `json:"status"`
+	ModerationNote     string `json:"moderation_note"`
+}
+
+func (q *Queries) CreateSellerReview(ctx context.Context, arg CreateSellerReviewParams) (SellerReview, error) {
+	row := q.db.QueryRow(ctx, createSellerReview,
+		arg.SubOrderID,
+		arg.SellerUsername,
+		arg.PatientUsername,
+		arg.DeliveryRating,
+		arg.PackagingRating,
+		arg.AuthenticityRating,
+		arg.Comment,
+		arg.Status,
+		arg.ModerationNote,
+	)
+	var i SellerReview
+	err := row.Scan(
+		&i.ID,
+		&i.SubOrderID,
+		&i.SellerUsername,
+		&i.PatientUsername,
+		&i.DeliveryRating,
+		&i.PackagingRating,
+		&i.AuthenticityRating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.SellerReply,
+		&i.RepliedBy,
+		&i.RepliedAt,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const getMedicineReviewByOrderItem = `-- name: GetMedicineReviewByOrderItem :one
+SELECT id, order_item_id, medicine_id, medicine_name, seller_username, patient_username, rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at FROM medicine_reviews WHERE order_item_id = $1
+`
+
+func (q *Queries) GetMedicineReviewByOrderItem(ctx context.Context, orderItemID int32) (MedicineReview, error) {
+	row := q.db.QueryRow(ctx, getMedicineReviewByOrderItem, orderItemID)
+	var i MedicineReview
+	err := row.Scan(
+		&i.ID,
+		&i.OrderItemID,
+		&i.MedicineID,
+		&i.MedicineName,
+		&i.SellerUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.SellerReply,
+		&i.RepliedBy,
+		&i.RepliedAt,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const getOrderItemForReview = `-- name: GetOrderItemForReview :one
+SELECT oi.id, oi.sub_order_id, oi.medicine_id, oi.medicine_name, oi.batch_number, oi.quantity, oi.unit_price, oi.discount, oi.line_total, oi.prescription_required, so.seller_username, so.status AS sub_order_status, o.patient_username
+FROM order_items oi
+JOIN sub_orders so ON so.id = oi.sub_order_id
+JOIN orders o ON o.id = so.order_id
+WHERE oi.id = $1
+`
+
+type GetOrderItemForReviewRow struct {
+	ID                   int32          `json:"id"`
+	SubOrderID           int32          `json:"sub_order_id"`
+	MedicineID           int32          `json:"medicine_id"`
+	MedicineName         string         `json:"medicine_name"`
+	BatchNumber          string         `json:"batch_number"`
+	Quantity             int32          `json:"quantity"`
+	UnitPrice            pgtype.Numeric `json:"unit_price"`
+	Discount             int32          `json:"discount"`
+	LineTotal            pgtype.Numeric `json:"line_total"`
+	PrescriptionRequired bool           `json:"prescription_required"`
+	SellerUsername       string         `json:"seller_username"`
+	SubOrderStatus       string         `json:"sub_order_status"`
+	PatientUsername      string         `json:"patient_username"`
+}
+
+// An order line with its sub-order's seller and status and the patient
+// who placed the order
+func (q *Queries) GetOrderItemForReview(ctx context.Context, id int32) (GetOrderItemForReviewRow, error) {
+	row := q.db.QueryRow(ctx, getOrderItemForReview, id)
+	var i GetOrderItemForReviewRow
+	err := row.Scan(
+		&i.ID,
+		&i.SubOrderID,
+		&i.MedicineID,
+		&i.MedicineName,
+		&i.BatchNumber,
+		&i.Quantity,
+		&i.UnitPrice,
+		&i.Discount,
+		&i.LineTotal,
+		&i.PrescriptionRequired,
+		&i.SellerUsername,
+		&i.SubOrderStatus,
+		&i.PatientUsername,
+	)
+	return i, err
+}
+
+const getSellerReviewBySubOrder = `-- name: GetSellerReviewBySubOrder :one
+SELECT id, sub_order_id, seller_username, patient_username, delivery_rating, packaging_rating, authenticity_rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at FROM seller_reviews WHERE sub_order_id = $1
+`
+
+func (q *Queries) GetSellerReviewBySubOrder(ctx context.Context, subOrderID int32) (SellerReview, error) {
+	row := q.db.QueryRow(ctx, getSellerReviewBySubOrder, subOrderID)
+	var i SellerReview
+	err := row.Scan(
+		&i.ID,
+		&i.SubOrderID,
+		&i.SellerUsername,
+		&i.PatientUsername,
+		&i.DeliveryRating,
+		&i.PackagingRating,
+		&i.AuthenticityRating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.SellerReply,
+		&i.RepliedBy,
+		&i.RepliedAt,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const getSubOrderForReview = `-- name: GetSubOrderForReview :one
+SELECT so.id, so.order_id, so.seller_username, so.status, so.subtotal, so.created_at, so.updated_at, so.delivered_at, o.patient_username
+FROM sub_orders so
+JOIN orders o ON o.id = so.order_id
+WHERE so.id = $1
+`
+
+type GetSubOrderForReviewRow struct {
+	ID              int32            `json:"id"`
+	OrderID         int32            `json:"order_id"`
+	SellerUsername  string           `json:"seller_username"`
+	Status          string           `json:"status"`
+	Subtotal        pgtype.Numeric   `json:"subtotal"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	DeliveredAt     pgtype.Timestamp `json:"delivered_at"`
+	PatientUsername string           `json:"patient_username"`
+}
+
+// A sub-order with the patient who placed it
+func (q *Queries) GetSubOrderForReview(ctx context.Context, id int32) (GetSubOrderForReviewRow, error) {
+	row := q.db.QueryRow(ctx, getSubOrderForReview, id)
+	var i GetSubOrderForReviewRow
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.SellerUsername,
+		&i.Status,
+		&i.Subtotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeliveredAt,
+		&i.PatientUsername,
+	)
+	return i, err
+}
+
+const listMedicineReviews = `-- name: ListMedicineReviews :many
+SELECT r.id, r.order_item_id, r.medicine_id, r.rating, r.comment, r.seller_reply, r.replied_at,
+    r.created_at, p.full_name AS patient_name
+FROM medicine_reviews r
+JOIN patients p ON p.username = r.patient_username
+WHERE r.seller_username = $1
+  AND r.medicine_name = $2
+  AND r.status = 'published'
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListMedicineReviewsParams struct {
+	SellerUsername string `json:"seller_username"`
+	MedicineName   string `json:"medicine_name"`
+	OffsetCount    int32  `json:"offset_count"`
+	LimitCount     int32  `json:"limit_count"`
+}
+
+type ListMedicineReviewsRow struct {
+	ID          int32            `json:"id"`
+	OrderItemID int32            `json:"order_item_id"`
+	MedicineID  int32            `json:"medicine_id"`
+	Rating      int16            `json:"rating"`
+	Comment     string           `json:"comment"`
+	SellerReply pgtype.Text      `json:"seller_reply"`
+	RepliedAt   pgtype.Timestamp `json:"replied_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	PatientName string           `json:"patient_name"`
+}
+
+// The published reviews of every batch the seller has listed under the
+// medicine's name, newest first
+func (q *Queries) ListMedicineReviews(ctx context.Context, arg ListMedicineReviewsParams) ([]ListMedicineReviewsRow, error) {
+	rows, err := q.db.Query(ctx, listMedicineReviews,
+		arg.SellerUsername,
+		arg.MedicineName,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMedicineReviewsRow{}
+	for rows.Next() {
+		var i ListMedicineReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderItemID,
+			&i.MedicineID,
+			&i.Rating,
+			&i.Comment,
+			&i.SellerReply,
+			&i.RepliedAt,
+			&i.CreatedAt,
+			&i.PatientName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingMedicineReviews = `-- name: ListPendingMedicineReviews :many
+SELECT id, order_item_id, medicine_id, medicine_name, seller_username, patient_username, rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at FROM medicine_reviews
+WHERE status = 'pending'
+ORDER BY created_at ASC, id ASC
+LIMIT $2 OFFSET $1
+`
+
+type ListPendingMedicineReviewsParams struct {
+	OffsetCount int32 `json:"offset_count"`
+	LimitCount  int32 `json:"limit_count"`
+}
+
+// Held medicine reviews, oldest first
+func (q *Queries) ListPendingMedicineReviews(ctx context.Context, arg ListPendingMedicineReviewsParams) ([]MedicineReview, error) {
+	rows, err := q.db.Query(ctx, listPendingMedicineReviews, arg.OffsetCount, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MedicineReview{}
+	for rows.Next() {
+		var i MedicineReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderItemID,
+			&i.MedicineID,
+			&i.MedicineName,
+			&i.SellerUsername,
+			&i.PatientUsername,
+			&i.Rating,
+			&i.Comment,
+			&i.Status,
+			&i.ModerationNote,
+			&i.SellerReply,
+			&i.RepliedBy,
+			&i.RepliedAt,
+			&i.CreatedAt,
+			&i.ModeratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingSellerReviews = `-- name: ListPendingSellerReviews :many
+SELECT id, sub_order_id, seller_username, patient_username, delivery_rating, packaging_rating, authenticity_rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at FROM seller_reviews
+WHERE status = 'pending'
+ORDER BY created_at ASC, id ASC
+LIMIT $2 OFFSET $1
+`
+
+type ListPendingSellerReviewsParams struct {
+	OffsetCount int32 `json:"offset_count"`
+	LimitCount  int32 `json:"limit_count"`
+}
+
+// Held seller reviews, oldest first
+func (q *Queries) ListPendingSellerReviews(ctx context.Context, arg ListPendingSellerReviewsParams) ([]SellerReview, error) {
+	rows, err := q.db.Query(ctx, listPendingSellerReviews, arg.OffsetCount, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SellerReview{}
+	for rows.Next() {
+		var i SellerReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubOrderID,
+			&i.SellerUsername,
+			&i.PatientUsername,
+			&i.DeliveryRating,
+			&i.PackagingRating,
+			&i.AuthenticityRating,
+			&i.Comment,
+			&i.Status,
+			&i.ModerationNote,
+			&i.SellerReply,
+			&i.RepliedBy,
+			&i.RepliedAt,
+			&i.CreatedAt,
+			&i.ModeratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSellerReviews = `-- name: ListSellerReviews :many
+SELECT r.id, r.sub_order_id, r.delivery_rating, r.packaging_rating, r.authenticity_rating,
+    r.comment, r.seller_reply, r.replied_at, r.created_at, p.full_name AS patient_name
+FROM seller_reviews r
+JOIN patients p ON p.username = r.patient_username
+WHERE r.seller_username = $1
+  AND r.status = 'published'
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListSellerReviewsParams struct {
+	SellerUsername string `json:"seller_username"`
+	OffsetCount    int32  `json:"offset_count"`
+	LimitCount     int32  `json:"limit_count"`
+}
+
+type ListSellerReviewsRow struct {
+	ID                 int32            `json:"id"`
+	SubOrderID         int32            `json:"sub_order_id"`
+	DeliveryRating     int16            `json:"delivery_rating"`
+	PackagingRating    int16            `json:"packaging_rating"`
+	AuthenticityRating int16            `json:"authenticity_rating"`
+	Comment            string           `json:"comment"`
+	SellerReply        pgtype.Text      `json:"seller_reply"`
+	RepliedAt          pgtype.Timestamp `json:"replied_at"`
+	CreatedAt          pgtype.Timestamp `json:"created_at"`
+	PatientName        string           `json:"patient_name"`
+}
+
+// A seller's published reviews, newest first
+func (q *Queries) ListSellerReviews(ctx context.Context, arg ListSellerReviewsParams) ([]ListSellerReviewsRow, error) {
+	rows, err := q.db.Query(ctx, listSellerReviews, arg.SellerUsername, arg.OffsetCount, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSellerReviewsRow{}
+	for rows.Next() {
+		var i ListSellerReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubOrderID,
+			&i.DeliveryRating,
+			&i.PackagingRating,
+			&i.AuthenticityRating,
+			&i.Comment,
+			&i.SellerReply,
+			&i.RepliedAt,
+			&i.CreatedAt,
+			&i.PatientName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moderateMedicineReview = `-- name: ModerateMedicineReview :one
+UPDATE medicine_reviews SET
+    status = $1,
+    moderation_note = $2,
+    moderated_at = now()
+WHERE id = $3
+RETURNING id, order_item_id, medicine_id, medicine_name, seller_username, patient_username, rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at
+`
+
+type ModerateMedicineReviewParams struct {
+	Status         string `json:"status"`
+	ModerationNote string `json:"moderation_note"`
+	ID             int32  `json:"id"`
+}
+
+func (q *Queries) ModerateMedicineReview(ctx context.Context, arg ModerateMedicineReviewParams) (MedicineReview, error) {
+	row := q.db.QueryRow(ctx, moderateMedicineReview, arg.Status, arg.ModerationNote, arg.ID)
+	var i MedicineReview
+	err := row.Scan(
+		&i.ID,
+		&i.OrderItemID,
+		&i.MedicineID,
+		&i.MedicineName,
+		&i.SellerUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.SellerReply,
+		&i.RepliedBy,
+		&i.RepliedAt,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const moderateSellerReview = `-- name: ModerateSellerReview :one
+UPDATE seller_reviews SET
+    status = $1,
+    moderation_note = $2,
+    moderated_at = now()
+WHERE id = $3
+RETURNING id, sub_order_id, seller_username, patient_username, delivery_rating, packaging_rating, authenticity_rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at
+`
+
+type ModerateSellerReviewParams struct {
+	Status         string `json:"status"`
+	ModerationNote string `json:"moderation_note"`
+	ID             int32  `json:"id"`
+}
+
+func (q *Queries) ModerateSellerReview(ctx context.Context, arg ModerateSellerReviewParams) (SellerReview, error) {
+	row := q.db.QueryRow(ctx, moderateSellerReview, arg.Status, arg.ModerationNote, arg.ID)
+	var i SellerReview
+	err := row.Scan(
+		&i.ID,
+		&i.SubOrderID,
+		&i.SellerUsername,
+		&i.PatientUsername,
+		&i.DeliveryRating,
+		&i.PackagingRating,
+		&i.AuthenticityRating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.SellerReply,
+		&i.RepliedBy,
+		&i.RepliedAt,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const replyToMedicineReview = `-- name: ReplyToMedicineReview :one
+UPDATE medicine_reviews SET
+    seller_reply = $1,
+    replied_by = $2,
+    replied_at = now()
+WHERE id = $3
+  AND seller_username = $4
+  AND status = 'published'
+RETURNING id, order_item_id, medicine_id, medicine_name, seller_username, patient_username, rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at
+`
+
+type ReplyToMedicineReviewParams struct {
+	SellerReply    pgtype.Text `json:"seller_reply"`
+	RepliedBy      pgtype.Text `json:"replied_by"`
+	ID             int32       `json:"id"`
+	SellerUsername string      `json:"seller_username"`
+}
+
+func (q *Queries) ReplyToMedicineReview(ctx context.Context, arg ReplyToMedicineReviewParams) (MedicineReview, error) {
+	row := q.db.QueryRow(ctx, replyToMedicineReview,
+		arg.SellerReply,
+		arg.RepliedBy,
+		arg.ID,
+		arg.SellerUsername,
+	)
+	var i MedicineReview
+	err := row.Scan(
+		&i.ID,
+		&i.OrderItemID,
+		&i.MedicineID,
+		&i.MedicineName,
+		&i.SellerUsername,
+		&i.PatientUsername,
+		&i.Rating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.SellerReply,
+		&i.RepliedBy,
+		&i.RepliedAt,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
+
+const replyToSellerReview = `-- name: ReplyToSellerReview :one
+UPDATE seller_reviews SET
+    seller_reply = $1,
+    replied_by = $2,
+    replied_at = now()
+WHERE id = $3
+  AND seller_username = $4
+  AND status = 'published'
+RETURNING id, sub_order_id, seller_username, patient_username, delivery_rating, packaging_rating, authenticity_rating, comment, status, moderation_note, seller_reply, replied_by, replied_at, created_at, moderated_at
+`
+
+type ReplyToSellerReviewParams struct {
+	SellerReply    pgtype.Text `json:"seller_reply"`
+	RepliedBy      pgtype.Text `json:"replied_by"`
+	ID             int32       `json:"id"`
+	SellerUsername string      `json:"seller_username"`
+}
+
+func (q *Queries) ReplyToSellerReview(ctx context.Context, arg ReplyToSellerReviewParams) (SellerReview, error) {
+	row := q.db.QueryRow(ctx, replyToSellerReview,
+		arg.SellerReply,
+		arg.RepliedBy,
+		arg.ID,
+		arg.SellerUsername,
+	)
+	var i SellerReview
+	err := row.Scan(
+		&i.ID,
+		&i.SubOrderID,
+		&i.SellerUsername,
+		&i.PatientUsername,
+		&i.DeliveryRating,
+		&i.PackagingRating,
+		&i.AuthenticityRating,
+		&i.Comment,
+		&i.Status,
+		&i.ModerationNote,
+		&i.SellerReply,
+		&i.RepliedBy,
+		&i.RepliedAt,
+		&i.CreatedAt,
+		&i.ModeratedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,268 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: trade_pricing.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createTradePriceTier = `-- name: CreateTradePriceTier :one
+INSERT INTO trade_price_tiers (
+    medicine_id, min_quantity, unit_price
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, medicine_id, min_quantity, unit_price
+`
+
+type CreateTradePriceTierParams struct {
+	MedicineID  int32          `json:"medicine_id"`
+	MinQuantity int32          `json:"min_quantity"`
+	UnitPrice   pgtype.Numeric `json:"unit_price"`
+}
+
+func (q *Queries) CreateTradePriceTier(ctx context.Context, arg CreateTradePriceTierParams) (TradePriceTier, error) {
+	row := q.db.QueryRow(ctx, createTradePriceTier, arg.MedicineID, arg.MinQuantity, arg.UnitPrice)
+	var i TradePriceTier
+	err := row.Scan(
+		&i.ID,
+		&i.MedicineID,
+		&i.MinQuantity,
+		&i.UnitPrice,
+	)
+	return i, err
+}
+
+const deleteTradePriceTiers = `-- name: DeleteTradePriceTiers :exec
+DELETE FROM trade_price_tiers WHERE medicine_id = $1
+`
+
+func (q *Queries) DeleteTradePriceTiers(ctx context.Context, medicineID int32) error {
+	_, err := q.db.Exec(ctx, deleteTradePriceTiers, medicineID)
+	return err
+}
+
+const getTradeUnitPrice = `-- name: GetTradeUnitPrice :one
+SELECT COALESCE((
+    SELECT t.unit_price FROM trade_price_tiers t
+    WHERE t.medicine_id = m.id AND t.min_quantity <= $1::INT
+    ORDER BY t.min_quantity DESC
+    LIMIT 1
+), m.trade_price)::NUMERIC(10, 2) AS unit_price
+FROM medicines m
+WHERE m.id = $2
+`
+
+type GetTradeUnitPriceParams struct {
+	Quantity   int32 `json:"quantity"`
+	MedicineID int32 `json:"medicine_id"`
+}
+
+// Unit price of an order of the given quantity: the largest tier the
+// quantity reaches, or the base trade price below every tier
+func (q *Queries) GetTradeUnitPrice(ctx context.Context, arg GetTradeUnitPriceParams) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, getTradeUnitPrice, arg.Quantity, arg.MedicineID)
+	var unit_price pgtype.Numeric
+	err := row.Scan(&unit_price)
+	return unit_price, err
+}
+
+const listTradePriceTiers = `-- name: ListTradePriceTiers :many
+SELECT id, medicine_id, min_quantity, unit_price FROM trade_price_tiers
+WHERE medicine_id = $1
+ORDER BY min_quantity ASC
+`
+
+func (q *Queries) ListTradePriceTiers(ctx context.Context, medicineID int32) ([]TradePriceTier, error) {
+	rows, err := q.db.Query(ctx, listTradePriceTiers, medicineID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TradePriceTier{}
+	for rows.Next() {
+		var i TradePriceTier
+		if err := rows.Scan(
+			&i.ID,
+			&i.MedicineID,
+			&i.MinQuantity,
+			&i.UnitPrice,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTradePriceTiersForMedicines = `-- name: ListTradePriceTiersForMedicines :many
+SELECT id, medicine_id, min_quantity, unit_price FROM trade_price_tiers
+WHERE medicine_id = ANY($1::INT[])
+ORDER BY medicine_id ASC, min_quantity ASC
+`
+
+func (q *Queries) ListTradePriceTiersForMedicines(ctx context.Context, medicineIds []int32) ([]TradePriceTier, error) {
+	rows, err := q.db.Query(ctx, listTradePriceTiersForMedicines, medicineIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TradePriceTier{}
+	for rows.Next() {
+		var i TradePriceTier
+		if err := rows.Scan(
+			&i.ID,
+			&i.MedicineID,
+			&i.MinQuantity,
+			&i.UnitPrice,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWholesaleCatalogue = `-- name: ListWholesaleCatalogue :many
+SELECT m.id, m.name, m.description, m.active_ingredients, m.strength, m.dosage_form,
+    m.prescription_required, m.batch_number, m.expiry_date, m.quantity,
+    m.price AS mrp, m.trade_price, m.min_order_quantity,
+    s.username AS supplier_username, s.store_name, s.pincode
+FROM medicines m
+JOIN sellers s ON s.username = m.seller_username
+WHERE s.seller_type = 'wholesale'
+  AND m.trade_price IS NOT NULL
+  AND m.status = 'active'
+  AND m.quantity > 0
+  AND m.expiry_date > CURRENT_DATE
+  AND ($1::VARCHAR IS NULL OR s.username = $1)
+  AND ($2::VARCHAR = '' OR m.name ILIKE '%' || $2 || '%')
+ORDER BY m.name ASC, m.expiry_date DESC, m.id ASC
+LIMIT $4 OFFSET $3
+`
+
+type ListWholesaleCatalogueParams struct {
+	Supplier pgtype.Text `json:"supplier"`
+	Medicine string      `json:"medicine"`
+	Offset   int32       `json:"offset"`
+	Limit    int32       `json:"limit"`
+}
+
+type ListWholesaleCatalogueRow struct {
+	ID                   int32          `json:"id"`
+	Name                 string         `json:"name"`
+	Description          string         `json:"description"`
+	ActiveIngredients    string         `json:"active_ingredients"`
+	Strength             string         `json:"strength"`
+	DosageForm           string         `json:"dosage_form"`
+	PrescriptionRequired bool           `json:"prescription_required"`
+	BatchNumber          string         `json:"batch_number"`
+	ExpiryDate           pgtype.Date    `json:"expiry_date"`
+	Quantity             int32          `json:"quantity"`
+	Mrp                  pgtype.Numeric `json:"mrp"`
+	TradePrice           pgtype.Numeric `json:"trade_price"`
+	MinOrderQuantity     int32          `json:"min_order_quantity"`
+	SupplierUsername     string         `json:"supplier_username"`
+	StoreName            string         `json:"store_name"`
+	Pincode              pgtype.Text    `json:"pincode"`
+}
+
+func (q *Queries) ListWholesaleCatalogue(ctx context.Context, arg ListWholesaleCatalogueParams) ([]ListWholesaleCatalogueRow, error) {
+	rows, err := q.db.Query(ctx, listWholesaleCatalogue,
+		arg.Supplier,
+		arg.Medicine,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWholesaleCatalogueRow{}
+	for rows.Next() {
+		var i ListWholesaleCatalogueRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.PrescriptionRequired,
+			&i.BatchNumber,
+			&i.ExpiryDate,
+			&i.Quantity,
+			&i.Mrp,
+			&i.TradePrice,
+			&i.MinOrderQuantity,
+			&i.SupplierUsername,
+			&i.StoreName,
+			&i.Pincode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateMedicineTradePricing = `-- name: UpdateMedicineTradePricing :one
+UPDATE medicines SET
+    trade_price = $1,
+    min_order_quantity = $2
+WHERE id = $3
+RETURNING id, name, description, expiry_date, quantity, price, discount, seller_username, created_at, active_ingredients, strength, dosage_form, prescription_required, batch_number, status, quarantined_quantity, quarantined_at, original_discount, markdown_rule_id, trade_price, min_order_quantity, reorder_level, rating_average, review_count, withdrawn_at
+`
+
+type UpdateMedicineTradePricingParams struct {
+	TradePrice       pgtype.Numeric `json:"trade_price"`
+	MinOrderQuantity int32          `json:"min_order_quantity"`
+	ID               int32          `json:"id"`
+}
+
+func (q *Queries) UpdateMedicineTradePricing(ctx context.Context, arg UpdateMedicineTradePricingParams) (Medicine, error) {
+	row := q.db.QueryRow(ctx, updateMedicineTradePricing, arg.TradePrice, arg.MinOrderQuantity, arg.ID)
+	var i Medicine
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ExpiryDate,
+		&i.Quantity,
+		&i.Price,
+		&i.Discount,
+		&i.SellerUsername,
+		&i.CreatedAt,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.PrescriptionRequired,
+		&i.BatchNumber,
+		&i.Status,
+		&i.QuarantinedQuantity,
+		&i.QuarantinedAt,
+		&i.OriginalDiscount,
+		&i.MarkdownRuleID,
+		&i.TradePrice,
+		&i.MinOrderQuantity,
+		&i.ReorderLevel,
+		&i.RatingAverage,
+		&i.ReviewCount,
+		&i.WithdrawnAt,
+	)
+	return i, err
+}
@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrAppointmentNotPayable = errors.New("appointment isn't waiting for payment or its hold on the slot has run out")
+
+type BookAppointmentTxParams struct {
+	DoctorUsername  string           `json:"doctor_username"`
+	PatientUsername string           `json:"patient_username"`
+	StartsAt        pgtype.Timestamp `json:"starts_at"`
+	EndsAt          pgtype.Timestamp `json:"ends_at"`
+	Reason          string           `json:"reason"`
+	VisitType       string           `json:"visit_type"`
+	// Now is the booking time; PaymentDueBy is when the slot is given up
+	// again if the fee isn't paid
+	Now          pgtype.Timestamp `json:"now"`
+	PaymentDueBy pgtype.Timestamp `json:"payment_due_by"`
+}
+
+// BookAppointmentTx books an appointment at the doctor's fee for the visit
+// type, less the follow-up discount when it is a follow-up. Appointments
+// with a fee wait for payment until PaymentDueBy; free ones are confirmed
+// at once. Holds that ran out are released first so their slots can be
+// booked again.
+func (store *Store) BookAppointmentTx(ctx context.Context, arg BookAppointmentTxParams) (Appointment, error) {
+	var result Appointment
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.ReleaseUnpaidAppointments(ctx, arg.Now)
+		if err != nil {
+			return err
+		}
+
+		quote, err := q.GetAppointmentFee(ctx, GetAppointmentFeeParams{
+			VisitType:       arg.VisitType,
+			PatientUsername: arg.PatientUsername,
+			StartsAt:        arg.StartsAt,
+			DoctorUsername:  arg.DoctorUsername,
+		})
+		if err != nil {
+			return err
+		}
+
+		create := CreateAppointmentParams{
+			DoctorUsername:  arg.DoctorUsername,
+			PatientUsername: arg.PatientUsername,
+			StartsAt:        arg.StartsAt,
+			EndsAt:          arg.EndsAt,
+			Reason:          arg.Reason,
+			VisitType:       arg.VisitType,
+			Fee:             quote.Fee,
+			FollowUpOf:      pgtype.Int4{Int32: quote.FollowUpOf, Valid: quote.FollowUpOf != 0},
+			PaymentStatus:   "not_required",
+		}
+		if !isZero(quote.Fee) {
+			create.PaymentStatus = "pending"
+			create.PaymentDueBy = arg.PaymentDueBy
+		}
+
+		result, err = q.CreateAppointment(ctx, create)
+		return err
+	})
+
+	return result, err
+}
+
+type ConfirmAppointmentPaymentTxParams struct {
+	PaymentIntentID string           `json:"payment_intent_id"`
+	ChargeID        pgtype.Text      `json:"charge_id"`
+	PaymentMethod   string           `json:"payment_method"`
+	Now             pgtype.Timestamp `json:"now"`
+}
+
+type ConfirmAppointmentPaymentTxResult struct {
+	Appointment Appointment `json:"appointment"`
+	Payment     Payment     `json:"payment"`
+	// Confirmed is set when this call confirmed the appointment, rather
+	// than one before it
+	Confirmed bool `json:"-"`
+}
+
+// ConfirmAppointmentPaymentTx records that the provider collected an
+// appointment's fee and confirms the appointment with it, as long as it
+// still holds its slot. Confirming the same payment again changes nothing.
+// A payment that doesn't pay for the appointment, because its hold ran out
+// or it was paid for with another payment first, is still recorded as
+// succeeded and ErrAppointmentNotPayable returned, so the caller can refund
+// it.
+func (store *Store) ConfirmAppointmentPaymentTx(ctx context.Context, arg ConfirmAppointmentPaymentTxParams) (ConfirmAppointmentPaymentTxResult, error) {
+	var result ConfirmAppointmentPaymentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		payment, err := q.GetPaymentByIntentForUpdate(ctx, pgtype.Text{String: arg.PaymentIntentID, Valid: true})
+		if err != nil {
+			return err
+		}
+		if !payment.AppointmentID.Valid {
+			return ErrRecordNotFound
+		}
+
+		if payment.Status == "succeeded" {
+			result.Payment = payment
+			result.Appointment, err = q.GetAppointment(ctx, payment.AppointmentID.Int32)
+			return err
+		}
+
+		result.Payment, err = q.MarkPaymentSucceeded(ctx, MarkPaymentSucceededParams{
+			ID:            payment.ID,
+			ChargeID:      arg.ChargeID,
+			PaymentMethod: arg.PaymentMethod,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Appointment, err = q.MarkAppointmentPaid(ctx, MarkAppointmentPaidParams{
+			ID:        payment.AppointmentID.Int32,
+			PaymentID: pgtype.Int4{Int32: payment.ID, Valid: true},
+			Now:       arg.Now,
+		})
+		if errors.Is(err, ErrRecordNotFound) {
+			result.Appointment, err = q.GetAppointment(ctx, payment.AppointmentID.Int32)
+			return err
+		}
+		result.Confirmed = err == nil
+		return err
+	})
+	if err == nil && result.Appointment.PaidPaymentID.Int32 != result.Payment.ID {
+		err = ErrAppointmentNotPayable
+	}
+
+	return result, err
+}
+
+type CancelAppointmentTxParams struct {
+	Cancel CancelAppointmentParams `json:"cancel"`
+	// Refund marks what the patient paid for the appointment, if anything,
+	// as owed back to them
+	Refund bool `json:"refund"`
+}
+
+// CancelAppointmentTx cancels an appointment and, when asked to, marks its
+// payment refunded in the same transaction. The caller pays the refund back
+// through the payment provider.
+func (store *Store) CancelAppointmentTx(ctx context.Context, arg CancelAppointmentTxParams) (Appointment, error) {
+	var result Appointment
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result, err = q.CancelAppointment(ctx, arg.Cancel)
+		if err != nil {
+			return err
+		}
+
+		if !arg.Refund || result.PaymentStatus != "paid" {
+			return nil
+		}
+
+		result, err = q.MarkAppointmentRefunded(ctx, result.ID)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrConsultationClosed = errors.New("consultation is closed")
+
+type SendConsultationAttachmentTxParams struct {
+	Attachment CreateConsultationAttachmentParams `json:"attachment"`
+	// Caption is sent with the attachment as the message body
+	Caption string `json:"caption"`
+}
+
+type SendConsultationAttachmentTxResult struct {
+	Attachment ConsultationAttachment `json:"attachment"`
+	Message    ConsultationMessage    `json:"message"`
+}
+
+// SendConsultationAttachmentTx stores a file shared in a consultation
+// together with the message that carries it. Nothing is kept if the
+// consultation has been closed.
+func (store *Store) SendConsultationAttachmentTx(ctx context.Context, arg SendConsultationAttachmentTxParams) (SendConsultationAttachmentTxResult, error) {
+	var result SendConsultationAttachmentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Attachment, err = q.CreateConsultationAttachment(ctx, arg.Attachment)
+		if err != nil {
+			return err
+		}
+
+		result.Message, err = q.CreateConsultationMessage(ctx, CreateConsultationMessageParams{
+			ConsultationID: arg.Attachment.ConsultationID,
+			SenderUsername: arg.Attachment.UploadedBy,
+			Body:           arg.Caption,
+			AttachmentID:   pgtype.Int4{Int32: result.Attachment.ID, Valid: true},
+		})
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrConsultationClosed
+		}
+		return err
+	})
+
+	return result, err
+}
+
+type CloseConsultationTxParams struct {
+	ID       int32  `json:"id"`
+	ClosedBy string `json:"closed_by"`
+}
+
+// CloseConsultationTx closes a consultation, archiving its messages into
+// the transcript and removing the live copies. Attachments are kept.
+func (store *Store) CloseConsultationTx(ctx context.Context, arg CloseConsultationTxParams) (Consultation, error) {
+	var result Consultation
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result, err = q.CloseConsultation(ctx, CloseConsultationParams{
+			ID:       arg.ID,
+			ClosedBy: pgtype.Text{String: arg.ClosedBy, Valid: true},
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrConsultationClosed
+			}
+			return err
+		}
+
+		return q.DeleteConsultationMessages(ctx, arg.ID)
+	})
+
+	return result, err
+}
@@ -0,0 +1,35 @@
+package db
+
+import "context"
+
+type ReplaceDoctorAvailabilityTxParams struct {
+	DoctorUsername string                           `json:"doctor_username"`
+	Availability   []CreateDoctorAvailabilityParams `json:"availability"`
+}
+
+// ReplaceDoctorAvailabilityTx swaps a doctor's weekly availability for a new
+// set in one transaction. Appointments already booked are kept.
+func (store *Store) ReplaceDoctorAvailabilityTx(ctx context.Context, arg ReplaceDoctorAvailabilityTxParams) ([]DoctorAvailability, error) {
+	var result []DoctorAvailability
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.DeleteDoctorAvailability(ctx, arg.DoctorUsername)
+		if err != nil {
+			return err
+		}
+
+		result = make([]DoctorAvailability, 0, len(arg.Availability))
+		for _, availability := range arg.Availability {
+			availability.DoctorUsername = arg.DoctorUsername
+			created, err := q.CreateDoctorAvailability(ctx, availability)
+			if err != nil {
+				return err
+			}
+			result = append(result, created)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
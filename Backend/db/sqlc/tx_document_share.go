@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ShareDocumentsTxParams struct {
+	DocumentIDs    []int32          `json:"document_ids"`
+	DoctorUsername string           `json:"doctor_username"`
+	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
+}
+
+// ShareDocumentsTx shares a set of documents with a doctor in one
+// transaction, so either all of them are shared or none is
+func (store *Store) ShareDocumentsTx(ctx context.Context, arg ShareDocumentsTxParams) ([]DocumentShare, error) {
+	var result []DocumentShare
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = make([]DocumentShare, 0, len(arg.DocumentIDs))
+		for _, documentID := range arg.DocumentIDs {
+			share, err := q.CreateDocumentShare(ctx, CreateDocumentShareParams{
+				DocumentID:     documentID,
+				DoctorUsername: arg.DoctorUsername,
+				ExpiresAt:      arg.ExpiresAt,
+			})
+			if err != nil {
+				return err
+			}
+			result = append(result, share)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDonationOfferUnavailable = errors.New("donation offer is no longer available")
+	ErrDonationQuantityExceeded = errors.New("claimed quantity exceeds what is left on offer")
+	ErrDonationMedicineExpired  = errors.New("offered medicine is no longer on sale")
+)
+
+type ClaimDonationTxParams struct {
+	OfferID          int32  `json:"offer_id"`
+	ClaimantUsername string `json:"claimant_username"`
+	Quantity         int32  `json:"quantity"`
+	// OutMovementType and InMovementType describe the ledger entries on the
+	// donor's and the claimant's side of the transfer
+	OutMovementType string `json:"out_movement_type"`
+	InMovementType  string `json:"in_movement_type"`
+	Reason          string `json:"reason"`
+	Reference       string `json:"reference"`
+}
+
+type ClaimDonationTxResult struct {
+	Offer            DonationOffer `json:"offer"`
+	Claim            DonationClaim `json:"claim"`
+	SourceMovement   StockMovement `json:"source_movement"`
+	ReceivedMedicine Medicine      `json:"received_medicine"`
+	ReceivedMovement StockMovement `json:"received_movement"`
+}
+
+// ClaimDonationTx moves claimed stock from the donor's medicine to the
+// claimant's inventory. The claimant's copy of the batch is created if they
+// don't stock it yet, and both sides of the transfer go into the ledger.
+func (store *Store) ClaimDonationTx(ctx context.Context, arg ClaimDonationTxParams) (ClaimDonationTxResult, error) {
+	var result ClaimDonationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		offer, err := q.GetDonationOfferForUpdate(ctx, arg.OfferID)
+		if err != nil {
+			return err
+		}
+
+		if offer.Status != "open" {
+			return ErrDonationOfferUnavailable
+		}
+		if arg.Quantity > offer.QuantityRemaining {
+			return ErrDonationQuantityExceeded
+		}
+
+		source, err := q.GetMedicineForUpdate(ctx, offer.MedicineID)
+		if err != nil {
+			return err
+		}
+		if source.Status != "active" || !source.ExpiryDate.Time.After(time.Now()) {
+			return ErrDonationMedicineExpired
+		}
+
+		out, err := recordStockMovement(ctx, q, StockMovementTxParams{
+			MedicineID:     source.ID,
+			MovementType:   arg.OutMovementType,
+			QuantityChange: -arg.Quantity,
+			Reason:         arg.Reason,
+			Reference:      arg.Reference,
+			CreatedBy:      arg.ClaimantUsername,
+		})
+		if err != nil {
+			return err
+		}
+		result.SourceMovement = *out.Movement
+
+		received, err := getOrCreateReceivedMedicine(ctx, q, source, arg.ClaimantUsername)
+		if err != nil {
+			return err
+		}
+
+		in, err := recordStockMovement(ctx, q, StockMovementTxParams{
+			MedicineID:     received.ID,
+			MovementType:   arg.InMovementType,
+			QuantityChange: arg.Quantity,
+			Reason:         arg.Reason,
+			Reference:      arg.Reference,
+			CreatedBy:      arg.ClaimantUsername,
+		})
+		if err != nil {
+			return err
+		}
+		result.ReceivedMedicine = in.Medicine
+		result.ReceivedMovement = *in.Movement
+
+		result.Offer, err = q.UpdateDonationOfferRemaining(ctx, UpdateDonationOfferRemainingParams{
+			ID:                offer.ID,
+			QuantityRemaining: offer.QuantityRemaining - arg.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Claim, err = q.CreateDonationClaim(ctx, CreateDonationClaimParams{
+			OfferID:            offer.ID,
+			DonorUsername:      offer.SellerUsername,
+			ClaimantUsername:   arg.ClaimantUsername,
+			SourceMedicineID:   source.ID,
+			ReceivedMedicineID: received.ID,
+			Quantity:           arg.Quantity,
+			UnitPrice:          offer.UnitPrice,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// getOrCreateReceivedMedicine finds the claimant's listing of the same batch
+// as source, or creates an empty one for the transfer to fill.
+func getOrCreateReceivedMedicine(ctx context.Context, q *Queries, source Medicine, claimant string) (Medicine, error) {
+	if source.BatchNumber != "" {
+		medicine, err := q.GetSellerMedicineByNameAndBatch(ctx, GetSellerMedicineByNameAndBatchParams{
+			SellerUsername: claimant,
+			Name:           source.Name,
+			BatchNumber:    source.BatchNumber,
+		})
+		if err == nil && medicine.ExpiryDate.Time.Equal(source.ExpiryDate.Time) {
+			return medicine, nil
+		}
+		if err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return medicine, err
+		}
+	}
+
+	return q.CreateMedicine(ctx, CreateMedicineParams{
+		Name:                 source.Name,
+		Description:          source.Description,
+		ExpiryDate:           source.ExpiryDate,
+		Quantity:             0,
+		Price:                source.Price,
+		SellerUsername:       claimant,
+		ActiveIngredients:    source.ActiveIngredients,
+		Strength:             source.Strength,
+		DosageForm:           source.DosageForm,
+		PrescriptionRequired: source.PrescriptionRequired,
+		BatchNumber:          source.BatchNumber,
+	})
+}
@@ -0,0 +1,326 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrNotRefundable       = errors.New("only delivered sub-orders can be refunded")
+	ErrRefundExceedsAmount = errors.New("amount exceeds what is left to refund on the sub-order")
+	ErrNothingToPay        = errors.New("seller has no balance to pay out")
+)
+
+// ledgerEntry is one leg of a ledger transaction: a debit when amount is
+// positive, a credit when it is negative
+type ledgerEntry struct {
+	account string
+	amount  pgtype.Numeric
+}
+
+func negate(n pgtype.Numeric) pgtype.Numeric {
+	if n.Int == nil {
+		return n
+	}
+	n.Int = new(big.Int).Neg(n.Int)
+	return n
+}
+
+// add sums two numerics, keeping the finer of their scales
+func add(a, b pgtype.Numeric) pgtype.Numeric {
+	exp := min(a.Exp, b.Exp)
+	sum := new(big.Int)
+	for _, n := range []pgtype.Numeric{a, b} {
+		if n.Int == nil {
+			continue
+		}
+		scaled := new(big.Int).Set(n.Int)
+		scaled.Mul(scaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n.Exp-exp)), nil))
+		sum.Add(sum, scaled)
+	}
+	return pgtype.Numeric{Int: sum, Exp: exp, Valid: true}
+}
+
+func isZero(n pgtype.Numeric) bool {
+	return n.Int == nil || n.Int.Sign() == 0
+}
+
+// postLedgerTransaction records a transaction and its entries. Entries of
+// zero are left out; the database rejects transactions that don't balance
+// when they are committed.
+func postLedgerTransaction(ctx context.Context, q *Queries, arg CreateLedgerTransactionParams, entries []ledgerEntry) (LedgerTransaction, error) {
+	transaction, err := q.CreateLedgerTransaction(ctx, arg)
+	if err != nil {
+		return transaction, err
+	}
+
+	for _, entry := range entries {
+		if isZero(entry.amount) {
+			continue
+		}
+		_, err := q.CreateLedgerEntry(ctx, CreateLedgerEntryParams{
+			TransactionID: transaction.ID,
+			Account:       entry.account,
+			Amount:        entry.amount,
+		})
+		if err != nil {
+			return transaction, err
+		}
+	}
+
+	return transaction, nil
+}
+
+type DeliverSubOrderTxParams struct {
+	SubOrderID int32  `json:"sub_order_id"`
+	CreatedBy  string `json:"created_by"`
+}
+
+// DeliverSubOrderTx marks a shipped sub-order as delivered and credits the
+// seller with its subtotal less the platform's commission.
+func (store *Store) DeliverSubOrderTx(ctx context.Context, arg DeliverSubOrderTxParams) (SubOrder, error) {
+	var subOrder SubOrder
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		subOrder, err = q.UpdateSubOrderStatus(ctx, UpdateSubOrderStatusParams{
+			ID:         arg.SubOrderID,
+			Status:     "delivered",
+			FromStatus: "shipped",
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrSubOrderStatus
+			}
+			return err
+		}
+
+		earning, err := q.GetSubOrderEarning(ctx, subOrder.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = postLedgerTransaction(ctx, q, CreateLedgerTransactionParams{
+			Kind:           "earning",
+			SellerUsername: earning.SellerUsername,
+			SubOrderID:     pgtype.Int4{Int32: subOrder.ID, Valid: true},
+			CommissionRate: earning.CommissionRate,
+			Description:    fmt.Sprintf("order %d delivered", subOrder.OrderID),
+			CreatedBy:      arg.CreatedBy,
+		}, []ledgerEntry{
+			{account: "customer_payments", amount: earning.Subtotal},
+			{account: "seller_payable", amount: negate(earning.SellerAmount)},
+			{account: "platform_commission", amount: negate(earning.Commission)},
+		})
+		return err
+	})
+
+	return subOrder, err
+}
+
+type RefundSubOrderTxParams struct {
+	SubOrderID int32 `json:"sub_order_id"`
+	// Kind is refund or chargeback
+	Kind string `json:"kind"`
+	// A null Amount pays back everything not yet refunded
+	Amount      pgtype.Numeric `json:"amount"`
+	Description string         `json:"description"`
+	CreatedBy   string         `json:"created_by"`
+	// ChargebackID marks the reported chargeback as posted
+	ChargebackID pgtype.Int4 `json:"chargeback_id"`
+}
+
+// RefundSubOrderTx pays money back to the patient on a delivered sub-order.
+// The seller gives back their share and the platform its commission, in
+// proportion to the amount.
+func (store *Store) RefundSubOrderTx(ctx context.Context, arg RefundSubOrderTxParams) (LedgerTransaction, error) {
+	var result LedgerTransaction
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// Serialises refunds of the same sub-order
+		subOrder, err := q.GetSubOrderForUpdate(ctx, arg.SubOrderID)
+		if err != nil {
+			return err
+		}
+
+		split, err := q.GetSubOrderRefundSplit(ctx, GetSubOrderRefundSplitParams{
+			SubOrderID: pgtype.Int4{Int32: subOrder.ID, Valid: true},
+			Amount:     arg.Amount,
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrNotRefundable
+			}
+			return err
+		}
+		if !split.Allowed.Bool {
+			return ErrRefundExceedsAmount
+		}
+
+		result, err = postLedgerTransaction(ctx, q, CreateLedgerTransactionParams{
+			Kind:           arg.Kind,
+			SellerUsername: split.SellerUsername,
+			SubOrderID:     pgtype.Int4{Int32: subOrder.ID, Valid: true},
+			CommissionRate: split.CommissionRate,
+			Description:    arg.Description,
+			CreatedBy:      arg.CreatedBy,
+		}, []ledgerEntry{
+			{account: "customer_payments", amount: negate(split.Amount)},
+			{account: "seller_payable", amount: split.SellerAmount},
+			{account: "platform_commission", amount: split.Commission},
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.ChargebackID.Valid {
+			return q.MarkChargebackPosted(ctx, arg.ChargebackID.Int32)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+type CreateChargebackTxParams struct {
+	SubOrderID int32          `json:"sub_order_id"`
+	Amount     pgtype.Numeric `json:"amount"`
+	Reason     string         `json:"reason"`
+	CreatedBy  string         `json:"created_by"`
+}
+
+// CreateChargebackTx reports a chargeback for the next payout run to post.
+// Together with the chargebacks still waiting to be posted, it can't be more
+// than what is left to refund on the delivered sub-order.
+func (store *Store) CreateChargebackTx(ctx context.Context, arg CreateChargebackTxParams) (Chargeback, error) {
+	var result Chargeback
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// Serialises chargebacks and refunds of the same sub-order
+		subOrder, err := q.GetSubOrderForUpdate(ctx, arg.SubOrderID)
+		if err != nil {
+			return err
+		}
+
+		pending, err := q.GetPendingChargebackTotal(ctx, subOrder.ID)
+		if err != nil {
+			return err
+		}
+
+		split, err := q.GetSubOrderRefundSplit(ctx, GetSubOrderRefundSplitParams{
+			SubOrderID: pgtype.Int4{Int32: subOrder.ID, Valid: true},
+			Amount:     add(arg.Amount, pending),
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrNotRefundable
+			}
+			return err
+		}
+		if !split.Allowed.Bool {
+			return ErrRefundExceedsAmount
+		}
+
+		result, err = q.CreateChargeback(ctx, CreateChargebackParams{
+			SubOrderID: subOrder.ID,
+			Amount:     arg.Amount,
+			Reason:     arg.Reason,
+			CreatedBy:  arg.CreatedBy,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type CreatePayoutTxParams struct {
+	BatchID        int32            `json:"batch_id"`
+	SellerUsername string           `json:"seller_username"`
+	Cutoff         pgtype.Timestamp `json:"cutoff"`
+	CreatedBy      string           `json:"created_by"`
+}
+
+// CreatePayoutTx pays a seller their balance as of the batch cutoff and
+// records the settlement statement for the period since their previous
+// payout. The opening balance is what was left over at the previous cutoff,
+// such as entries committed after that payout was worked out, so that the
+// amount paid always equals the opening balance plus gross sales less
+// commission, refunds and chargebacks.
+func (store *Store) CreatePayoutTx(ctx context.Context, arg CreatePayoutTxParams) (Payout, error) {
+	var result Payout
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var periodStart pgtype.Timestamp
+		last, err := q.GetLastSellerPayout(ctx, arg.SellerUsername)
+		if err == nil {
+			periodStart = last.PeriodEnd
+		} else if !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		balance, err := q.GetSellerLedgerBalance(ctx, GetSellerLedgerBalanceParams{
+			Seller: arg.SellerUsername,
+			AsOf:   arg.Cutoff,
+		})
+		if err != nil {
+			return err
+		}
+		if isZero(balance) || balance.Int.Sign() < 0 {
+			return ErrNothingToPay
+		}
+
+		opening := pgtype.Numeric{Int: big.NewInt(0), Valid: true}
+		if periodStart.Valid {
+			opening, err = q.GetSellerLedgerBalance(ctx, GetSellerLedgerBalanceParams{
+				Seller: arg.SellerUsername,
+				AsOf:   periodStart,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		settlement, err := q.GetSellerSettlement(ctx, GetSellerSettlementParams{
+			Seller:      arg.SellerUsername,
+			PeriodStart: periodStart,
+			PeriodEnd:   arg.Cutoff,
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = q.CreatePayout(ctx, CreatePayoutParams{
+			BatchID:        arg.BatchID,
+			SellerUsername: arg.SellerUsername,
+			PeriodStart:    periodStart,
+			PeriodEnd:      arg.Cutoff,
+			OpeningBalance: opening,
+			GrossSales:     settlement.GrossSales,
+			Commission:     settlement.Commission,
+			Refunds:        settlement.Refunds,
+			Chargebacks:    settlement.Chargebacks,
+			Amount:         balance,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = postLedgerTransaction(ctx, q, CreateLedgerTransactionParams{
+			Kind:           "payout",
+			SellerUsername: arg.SellerUsername,
+			PayoutID:       pgtype.Int4{Int32: result.ID, Valid: true},
+			Description:    fmt.Sprintf("payout %d", result.ID),
+			CreatedBy:      arg.CreatedBy,
+		}, []ledgerEntry{
+			{account: "seller_payable", amount: balance},
+			{account: "payouts", amount: negate(balance)},
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type MarkdownTxParams struct {
+	MedicineID int32 `json:"medicine_id"`
+	// RuleID is the rule being applied; leave it null to restore the
+	// seller's original discount
+	RuleID   pgtype.Int4 `json:"rule_id"`
+	Discount int32       `json:"discount"`
+}
+
+type MarkdownTxResult struct {
+	Medicine Medicine      `json:"medicine"`
+	Event    MarkdownEvent `json:"event"`
+}
+
+// MarkdownTx applies or restores an automatic markdown on a medicine and
+// logs the change in one transaction.
+func (store *Store) MarkdownTx(ctx context.Context, arg MarkdownTxParams) (MarkdownTxResult, error) {
+	var result MarkdownTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetMedicineForUpdate(ctx, arg.MedicineID)
+		if err != nil {
+			return err
+		}
+
+		action := "applied"
+		if arg.RuleID.Valid {
+			result.Medicine, err = q.ApplyMarkdown(ctx, ApplyMarkdownParams{
+				ID:       arg.MedicineID,
+				Discount: arg.Discount,
+				RuleID:   arg.RuleID,
+			})
+		} else {
+			action = "restored"
+			result.Medicine, err = q.RestoreMarkdown(ctx, arg.MedicineID)
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Event, err = q.CreateMarkdownEvent(ctx, CreateMarkdownEventParams{
+			MedicineID:     before.ID,
+			SellerUsername: before.SellerUsername,
+			RuleID:         arg.RuleID,
+			Action:         action,
+			OldDiscount:    before.Discount,
+			NewDiscount:    result.Medicine.Discount,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrMedicineNotQuarantined = errors.New("medicine is not in quarantine")
+
+type QuarantineMedicineTxParams struct {
+	MedicineID int32 `json:"medicine_id"`
+	// MovementType and Reason describe the ledger entry that writes the
+	// stock off
+	MovementType string `json:"movement_type"`
+	Reason       string `json:"reason"`
+	CreatedBy    string `json:"created_by"`
+}
+
+type QuarantineMedicineTxResult struct {
+	Medicine Medicine       `json:"medicine"`
+	Movement *StockMovement `json:"movement"`
+}
+
+// QuarantineMedicineTx takes a medicine off sale without deleting it. Its
+// stock is written off in the ledger and kept aside as quarantined stock
+// until the seller records how it was disposed of.
+func (store *Store) QuarantineMedicineTx(ctx context.Context, arg QuarantineMedicineTxParams) (QuarantineMedicineTxResult, error) {
+	var result QuarantineMedicineTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		movement, err := recordStockMovement(ctx, q, StockMovementTxParams{
+			MedicineID:     arg.MedicineID,
+			MovementType:   arg.MovementType,
+			TargetQuantity: pgtype.Int4{Int32: 0, Valid: true},
+			Reason:         arg.Reason,
+			CreatedBy:      arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+		result.Movement = movement.Movement
+
+		var quarantined int32
+		if movement.Movement != nil {
+			quarantined = -movement.Movement.QuantityChange
+		}
+
+		result.Medicine, err = q.QuarantineMedicine(ctx, QuarantineMedicineParams{
+			ID:                  arg.MedicineID,
+			QuarantinedQuantity: quarantined,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type DisposeMedicineTxResult struct {
+	Medicine Medicine         `json:"medicine"`
+	Disposal MedicineDisposal `json:"disposal"`
+}
+
+// DisposeMedicineTx records the destruction of a quarantined medicine's
+// stock and closes it off as disposed. The quantity and seller of arg are
+// taken from the medicine itself.
+func (store *Store) DisposeMedicineTx(ctx context.Context, arg CreateMedicineDisposalParams) (DisposeMedicineTxResult, error) {
+	var result DisposeMedicineTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		medicine, err := q.GetMedicineForUpdate(ctx, arg.MedicineID)
+		if err != nil {
+			return err
+		}
+
+		if medicine.Status != "quarantined" {
+			return ErrMedicineNotQuarantined
+		}
+
+		arg.SellerUsername = medicine.SellerUsername
+		arg.Quantity = medicine.QuarantinedQuantity
+		result.Disposal, err = q.CreateMedicineDisposal(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Medicine, err = q.MarkMedicineDisposed(ctx, medicine.ID)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,326 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrCartEmpty           = errors.New("cart is empty")
+	ErrMedicineUnavailable = errors.New("medicine is not available")
+	ErrSubOrderStatus      = errors.New("sub-order can't be changed in its current status")
+	ErrOrderNotPayable     = errors.New("order isn't waiting for payment or its hold on the stock has run out")
+)
+
+// OrderReference ties the stock movements of an order back to it
+func OrderReference(orderID int32) string {
+	return fmt.Sprintf("order:%d", orderID)
+}
+
+type CheckoutTxParams struct {
+	PatientUsername string `json:"patient_username"`
+	DeliveryAddress string `json:"delivery_address"`
+	// PaymentDueBy is when the order gives its stock back if it hasn't
+	// been paid for
+	PaymentDueBy pgtype.Timestamp `json:"payment_due_by"`
+	// MovementType and Reason describe the ledger entries that take the
+	// ordered stock out of each seller's inventory
+	MovementType string `json:"movement_type"`
+	Reason       string `json:"reason"`
+}
+
+type CheckoutTxResult struct {
+	Order     Order       `json:"order"`
+	SubOrders []SubOrder  `json:"sub_orders"`
+	Items     []OrderItem `json:"items"`
+}
+
+// CheckoutTx turns a patient's cart into an order with one sub-order per
+// seller, takes the stock out of each seller's inventory and empties the
+// cart. Lines are priced at the medicine's price and discount at checkout.
+// The order waits for payment until PaymentDueBy.
+func (store *Store) CheckoutTx(ctx context.Context, arg CheckoutTxParams) (CheckoutTxResult, error) {
+	var result CheckoutTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		cart, err := q.ListCartItemsForCheckout(ctx, arg.PatientUsername)
+		if err != nil {
+			return err
+		}
+		if len(cart) == 0 {
+			return ErrCartEmpty
+		}
+
+		result.Order, err = q.CreateOrder(ctx, CreateOrderParams{
+			PatientUsername: arg.PatientUsername,
+			DeliveryAddress: arg.DeliveryAddress,
+			PaymentDueBy:    arg.PaymentDueBy,
+		})
+		if err != nil {
+			return err
+		}
+		reference := OrderReference(result.Order.ID)
+
+		subOrders := make(map[string]SubOrder)
+		for _, line := range cart {
+			medicine, err := q.GetMedicineForUpdate(ctx, line.MedicineID)
+			if err != nil {
+				return err
+			}
+			if medicine.Status != "active" || !medicine.ExpiryDate.Time.After(time.Now()) {
+				return fmt.Errorf("%w: %s", ErrMedicineUnavailable, medicine.Name)
+			}
+
+			subOrder, ok := subOrders[medicine.SellerUsername]
+			if !ok {
+				subOrder, err = q.CreateSubOrder(ctx, CreateSubOrderParams{
+					OrderID:        result.Order.ID,
+					SellerUsername: medicine.SellerUsername,
+				})
+				if err != nil {
+					return err
+				}
+				subOrders[medicine.SellerUsername] = subOrder
+			}
+
+			_, err = recordStockMovement(ctx, q, StockMovementTxParams{
+				MedicineID:     medicine.ID,
+				MovementType:   arg.MovementType,
+				QuantityChange: -line.Quantity,
+				Reason:         arg.Reason,
+				Reference:      reference,
+				CreatedBy:      arg.PatientUsername,
+			})
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, medicine.Name)
+			}
+
+			item, err := q.CreateOrderItem(ctx, CreateOrderItemParams{
+				SubOrderID:           subOrder.ID,
+				MedicineID:           medicine.ID,
+				MedicineName:         medicine.Name,
+				BatchNumber:          medicine.BatchNumber,
+				Quantity:             line.Quantity,
+				UnitPrice:            medicine.Price,
+				Discount:             medicine.Discount,
+				PrescriptionRequired: medicine.PrescriptionRequired,
+			})
+			if err != nil {
+				return err
+			}
+			result.Items = append(result.Items, item)
+		}
+
+		for _, subOrder := range subOrders {
+			subOrder, err = q.UpdateSubOrderSubtotal(ctx, subOrder.ID)
+			if err != nil {
+				return err
+			}
+			result.SubOrders = append(result.SubOrders, subOrder)
+		}
+		sort.Slice(result.SubOrders, func(i, j int) bool {
+			return result.SubOrders[i].ID < result.SubOrders[j].ID
+		})
+
+		result.Order, err = q.UpdateOrderTotal(ctx, result.Order.ID)
+		if err != nil {
+			return err
+		}
+
+		return q.ClearCart(ctx, arg.PatientUsername)
+	})
+
+	return result, err
+}
+
+type CancelSubOrderTxParams struct {
+	SubOrderID int32 `json:"sub_order_id"`
+	// MovementType and Reason describe the ledger entries that put the
+	// cancelled stock back
+	MovementType string `json:"movement_type"`
+	Reason       string `json:"reason"`
+	CreatedBy    string `json:"created_by"`
+}
+
+// CancelSubOrderTx cancels a sub-order that hasn't shipped and puts its
+// stock back on sale. Medicines that were deleted or taken off sale since
+// are not restocked.
+func (store *Store) CancelSubOrderTx(ctx context.Context, arg CancelSubOrderTxParams) (SubOrder, error) {
+	var subOrder SubOrder
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetSubOrderForUpdate(ctx, arg.SubOrderID)
+		if err != nil {
+			return err
+		}
+
+		subOrder, err = cancelSubOrder(ctx, q, current, arg)
+		return err
+	})
+
+	return subOrder, err
+}
+
+func cancelSubOrder(ctx context.Context, q *Queries, current SubOrder, arg CancelSubOrderTxParams) (SubOrder, error) {
+	if current.Status != "placed" && current.Status != "confirmed" {
+		return SubOrder{}, ErrSubOrderStatus
+	}
+
+	items, err := q.ListSubOrderItems(ctx, current.ID)
+	if err != nil {
+		return SubOrder{}, err
+	}
+
+	for _, item := range items {
+		medicine, err := q.GetMedicineForUpdate(ctx, item.MedicineID)
+		if errors.Is(err, ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return SubOrder{}, err
+		}
+		if medicine.Status != "active" {
+			continue
+		}
+
+		_, err = recordStockMovement(ctx, q, StockMovementTxParams{
+			MedicineID:     medicine.ID,
+			MovementType:   arg.MovementType,
+			QuantityChange: item.Quantity,
+			Reason:         arg.Reason,
+			Reference:      OrderReference(current.OrderID),
+			CreatedBy:      arg.CreatedBy,
+		})
+		if err != nil {
+			return SubOrder{}, err
+		}
+	}
+
+	return q.UpdateSubOrderStatus(ctx, UpdateSubOrderStatusParams{
+		ID:         current.ID,
+		Status:     "cancelled",
+		FromStatus: current.Status,
+	})
+}
+
+type ConfirmOrderPaymentTxParams struct {
+	PaymentIntentID string      `json:"payment_intent_id"`
+	ChargeID        pgtype.Text `json:"charge_id"`
+	PaymentMethod   string      `json:"payment_method"`
+}
+
+type ConfirmOrderPaymentTxResult struct {
+	Order   Order   `json:"order"`
+	Payment Payment `json:"payment"`
+}
+
+// ConfirmOrderPaymentTx records that the provider collected an order's
+// payment and marks the order paid with it. Confirming the same payment
+// again changes nothing. A payment that doesn't pay for the order, because
+// the order had expired or was paid for with another payment first, is
+// still recorded as succeeded and ErrOrderNotPayable returned, so the caller
+// can refund it.
+func (store *Store) ConfirmOrderPaymentTx(ctx context.Context, arg ConfirmOrderPaymentTxParams) (ConfirmOrderPaymentTxResult, error) {
+	var result ConfirmOrderPaymentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		payment, err := q.GetPaymentByIntentForUpdate(ctx, pgtype.Text{String: arg.PaymentIntentID, Valid: true})
+		if err != nil {
+			return err
+		}
+		if !payment.OrderID.Valid {
+			return ErrRecordNotFound
+		}
+
+		result.Order, err = q.GetOrderForUpdate(ctx, payment.OrderID.Int32)
+		if err != nil {
+			return err
+		}
+
+		if payment.Status == "succeeded" {
+			result.Payment = payment
+			return nil
+		}
+
+		result.Payment, err = q.MarkPaymentSucceeded(ctx, MarkPaymentSucceededParams{
+			ID:            payment.ID,
+			ChargeID:      arg.ChargeID,
+			PaymentMethod: arg.PaymentMethod,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.Order.PaymentStatus != "pending" {
+			return nil
+		}
+		result.Order, err = q.MarkOrderPaid(ctx, MarkOrderPaidParams{
+			ID:        result.Order.ID,
+			PaymentID: pgtype.Int4{Int32: result.Payment.ID, Valid: true},
+		})
+		return err
+	})
+	if err == nil && result.Order.PaidPaymentID.Int32 != result.Payment.ID {
+		err = ErrOrderNotPayable
+	}
+
+	return result, err
+}
+
+type ExpireOrderTxParams struct {
+	OrderID int32            `json:"order_id"`
+	Now     pgtype.Timestamp `json:"now"`
+	// MovementType and Reason describe the ledger entries that put the
+	// stock of the order back
+	MovementType string `json:"movement_type"`
+	Reason       string `json:"reason"`
+	CreatedBy    string `json:"created_by"`
+}
+
+// ExpireOrderTx gives up an order that wasn't paid for in time: its
+// sub-orders are cancelled, their stock put back and the order marked
+// expired. Orders that were paid for in the meantime are left alone.
+func (store *Store) ExpireOrderTx(ctx context.Context, arg ExpireOrderTxParams) (Order, error) {
+	var order Order
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		order, err = q.GetOrderForUpdate(ctx, arg.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.PaymentStatus != "pending" || !order.PaymentDueBy.Valid ||
+			!order.PaymentDueBy.Time.Before(arg.Now.Time) {
+			return ErrOrderNotPayable
+		}
+
+		subOrders, err := q.ListOrderSubOrders(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		for _, subOrder := range subOrders {
+			if subOrder.Status == "cancelled" {
+				continue
+			}
+			_, err = cancelSubOrder(ctx, q, subOrder, CancelSubOrderTxParams{
+				SubOrderID:   subOrder.ID,
+				MovementType: arg.MovementType,
+				Reason:       arg.Reason,
+				CreatedBy:    arg.CreatedBy,
+			})
+			if err != nil {
+				return fmt.Errorf("sub-order %d: %w", subOrder.ID, err)
+			}
+		}
+
+		order, err = q.ExpireOrder(ctx, order.ID)
+		return err
+	})
+
+	return order, err
+}
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrStockTransferStatus     = errors.New("stock transfer can't be changed in its current status")
+	ErrTransferMedicineOffSale = errors.New("transferred medicine is no longer on sale")
+)
+
+type CreateOrganizationTxParams struct {
+	Name          string `json:"name"`
+	OwnerUsername string `json:"owner_username"`
+}
+
+// CreateOrganizationTx creates an organisation with the owner's store as its
+// first branch.
+func (store *Store) CreateOrganizationTx(ctx context.Context, arg CreateOrganizationTxParams) (Organization, error) {
+	var result Organization
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateOrganization(ctx, CreateOrganizationParams{
+			Name:          arg.Name,
+			OwnerUsername: arg.OwnerUsername,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.SetSellerOrganization(ctx, SetSellerOrganizationParams{
+			OrganizationID: pgtype.Int4{Int32: result.ID, Valid: true},
+			Username:       arg.OwnerUsername,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type CreateBranchTxParams struct {
+	OrganizationID int32              `json:"organization_id"`
+	Seller         CreateSellerParams `json:"seller"`
+}
+
+// CreateBranchTx creates the seller account of a new branch and adds it to
+// the organisation.
+func (store *Store) CreateBranchTx(ctx context.Context, arg CreateBranchTxParams) (Seller, error) {
+	var result Seller
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		seller, err := q.CreateSeller(ctx, arg.Seller)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.SetSellerOrganization(ctx, SetSellerOrganizationParams{
+			OrganizationID: pgtype.Int4{Int32: arg.OrganizationID, Valid: true},
+			Username:       seller.Username,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type StockTransferTxParams struct {
+	TransferID int32 `json:"transfer_id"`
+	// MovementType, Reason and Reference describe the ledger entry made for
+	// the transfer
+	MovementType string `json:"movement_type"`
+	Reason       string `json:"reason"`
+	Reference    string `json:"reference"`
+	CreatedBy    string `json:"created_by"`
+}
+
+// DispatchStockTransferTx takes the requested stock out of the sending
+// branch's inventory and marks the transfer as dispatched.
+func (store *Store) DispatchStockTransferTx(ctx context.Context, arg StockTransferTxParams) (StockTransfer, error) {
+	var result StockTransfer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		transfer, err := q.GetStockTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != "requested" {
+			return ErrStockTransferStatus
+		}
+
+		medicine, err := q.GetMedicine(ctx, transfer.MedicineID)
+		if err != nil {
+			return err
+		}
+		if medicine.Status != "active" {
+			return ErrTransferMedicineOffSale
+		}
+
+		_, err = recordStockMovement(ctx, q, StockMovementTxParams{
+			MedicineID:     transfer.MedicineID,
+			MovementType:   arg.MovementType,
+			QuantityChange: -transfer.Quantity,
+			Reason:         arg.Reason,
+			Reference:      arg.Reference,
+			CreatedBy:      arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = q.MarkStockTransferDispatched(ctx, MarkStockTransferDispatchedParams{
+			ID:           transfer.ID,
+			DispatchedBy: arg.CreatedBy,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ReceiveStockTransferTx adds a dispatched transfer to the receiving branch's
+// inventory, topping up its listing of the same batch when there is one, and
+// marks the transfer as received.
+func (store *Store) ReceiveStockTransferTx(ctx context.Context, arg StockTransferTxParams) (StockTransfer, error) {
+	var result StockTransfer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		transfer, err := q.GetStockTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != "dispatched" {
+			return ErrStockTransferStatus
+		}
+
+		received, err := getOrCreateReceivedMedicine(ctx, q, Medicine{
+			Name:                 transfer.Name,
+			Description:          transfer.Description,
+			ExpiryDate:           transfer.ExpiryDate,
+			Price:                transfer.Price,
+			ActiveIngredients:    transfer.ActiveIngredients,
+			Strength:             transfer.Strength,
+			DosageForm:           transfer.DosageForm,
+			PrescriptionRequired: transfer.PrescriptionRequired,
+			BatchNumber:          transfer.BatchNumber,
+		}, transfer.ToSeller)
+		if err != nil {
+			return err
+		}
+
+		_, err = recordStockMovement(ctx, q, StockMovementTxParams{
+			MedicineID:     received.ID,
+			MovementType:   arg.MovementType,
+			QuantityChange: transfer.Quantity,
+			Reason:         arg.Reason,
+			Reference:      arg.Reference,
+			CreatedBy:      arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = q.MarkStockTransferReceived(ctx, MarkStockTransferReceivedParams{
+			ID:                 transfer.ID,
+			ReceivedBy:         arg.CreatedBy,
+			ReceivedMedicineID: pgtype.Int4{Int32: received.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,286 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrNotInCatalogue      = errors.New("medicine is not in the supplier's wholesale catalogue")
+	ErrBelowMinimumOrder   = errors.New("quantity is below the minimum order quantity")
+	ErrPurchaseOrderStatus = errors.New("purchase order can't be changed in its current status")
+)
+
+type TradePriceTierParams struct {
+	MinQuantity int32          `json:"min_quantity"`
+	UnitPrice   pgtype.Numeric `json:"unit_price"`
+}
+
+type SetTradePricingTxParams struct {
+	MedicineID int32 `json:"medicine_id"`
+	// A null TradePrice takes the medicine out of the wholesale catalogue
+	TradePrice       pgtype.Numeric         `json:"trade_price"`
+	MinOrderQuantity int32                  `json:"min_order_quantity"`
+	Tiers            []TradePriceTierParams `json:"tiers"`
+}
+
+type SetTradePricingTxResult struct {
+	Medicine Medicine         `json:"medicine"`
+	Tiers    []TradePriceTier `json:"tiers"`
+}
+
+// SetTradePricingTx replaces a medicine's trade price, minimum order quantity
+// and price tiers.
+func (store *Store) SetTradePricingTx(ctx context.Context, arg SetTradePricingTxParams) (SetTradePricingTxResult, error) {
+	var result SetTradePricingTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Medicine, err = q.UpdateMedicineTradePricing(ctx, UpdateMedicineTradePricingParams{
+			ID:               arg.MedicineID,
+			TradePrice:       arg.TradePrice,
+			MinOrderQuantity: arg.MinOrderQuantity,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteTradePriceTiers(ctx, arg.MedicineID); err != nil {
+			return err
+		}
+
+		result.Tiers = []TradePriceTier{}
+		for _, tier := range arg.Tiers {
+			created, err := q.CreateTradePriceTier(ctx, CreateTradePriceTierParams{
+				MedicineID:  arg.MedicineID,
+				MinQuantity: tier.MinQuantity,
+				UnitPrice:   tier.UnitPrice,
+			})
+			if err != nil {
+				return err
+			}
+			result.Tiers = append(result.Tiers, created)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+type PurchaseOrderItemParams struct {
+	MedicineID int32 `json:"medicine_id"`
+	Quantity   int32 `json:"quantity"`
+}
+
+type CreatePurchaseOrderTxParams struct {
+	BuyerUsername    string                    `json:"buyer_username"`
+	SupplierUsername string                    `json:"supplier_username"`
+	Notes            string                    `json:"notes"`
+	Items            []PurchaseOrderItemParams `json:"items"`
+}
+
+type PurchaseOrderTxResult struct {
+	Order PurchaseOrder       `json:"order"`
+	Items []PurchaseOrderItem `json:"items"`
+}
+
+// CreatePurchaseOrderTx places an order with a wholesale supplier. Each item
+// is priced at the trade price tier its quantity reaches and keeps a copy of
+// the supplier's medicine as it was ordered.
+func (store *Store) CreatePurchaseOrderTx(ctx context.Context, arg CreatePurchaseOrderTxParams) (PurchaseOrderTxResult, error) {
+	var result PurchaseOrderTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Order, err = q.CreatePurchaseOrder(ctx, CreatePurchaseOrderParams{
+			BuyerUsername:    arg.BuyerUsername,
+			SupplierUsername: arg.SupplierUsername,
+			Notes:            arg.Notes,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, item := range arg.Items {
+			medicine, err := q.GetMedicine(ctx, item.MedicineID)
+			if err != nil {
+				return err
+			}
+
+			if medicine.SellerUsername != arg.SupplierUsername || medicine.Status != "active" ||
+				!medicine.TradePrice.Valid || !medicine.ExpiryDate.Time.After(time.Now()) {
+				return fmt.Errorf("%w: medicine %d", ErrNotInCatalogue, medicine.ID)
+			}
+			if item.Quantity < medicine.MinOrderQuantity {
+				return fmt.Errorf("%w: %s needs at least %d", ErrBelowMinimumOrder, medicine.Name, medicine.MinOrderQuantity)
+			}
+			if item.Quantity > medicine.Quantity {
+				return fmt.Errorf("%w: %s", ErrInsufficientStock, medicine.Name)
+			}
+
+			unitPrice, err := q.GetTradeUnitPrice(ctx, GetTradeUnitPriceParams{
+				MedicineID: medicine.ID,
+				Quantity:   item.Quantity,
+			})
+			if err != nil {
+				return err
+			}
+
+			created, err := q.CreatePurchaseOrderItem(ctx, CreatePurchaseOrderItemParams{
+				PurchaseOrderID:      result.Order.ID,
+				MedicineID:           medicine.ID,
+				Name:                 medicine.Name,
+				Description:          medicine.Description,
+				ActiveIngredients:    medicine.ActiveIngredients,
+				Strength:             medicine.Strength,
+				DosageForm:           medicine.DosageForm,
+				PrescriptionRequired: medicine.PrescriptionRequired,
+				BatchNumber:          medicine.BatchNumber,
+				ExpiryDate:           medicine.ExpiryDate,
+				Mrp:                  medicine.Price,
+				Quantity:             item.Quantity,
+				UnitPrice:            unitPrice,
+			})
+			if err != nil {
+				return err
+			}
+			result.Items = append(result.Items, created)
+		}
+
+		result.Order, err = q.UpdatePurchaseOrderTotal(ctx, result.Order.ID)
+		return err
+	})
+
+	return result, err
+}
+
+type PurchaseOrderStockTxParams struct {
+	PurchaseOrderID int32 `json:"purchase_order_id"`
+	// MovementType, Reason and Reference describe the ledger entries made
+	// for each item of the order
+	MovementType string `json:"movement_type"`
+	Reason       string `json:"reason"`
+	Reference    string `json:"reference"`
+	CreatedBy    string `json:"created_by"`
+}
+
+// ShipPurchaseOrderTx takes the items of an accepted order out of the
+// supplier's stock and marks the order as shipped.
+func (store *Store) ShipPurchaseOrderTx(ctx context.Context, arg PurchaseOrderStockTxParams) (PurchaseOrderTxResult, error) {
+	var result PurchaseOrderTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		order, err := q.GetPurchaseOrderForUpdate(ctx, arg.PurchaseOrderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != "accepted" {
+			return ErrPurchaseOrderStatus
+		}
+
+		result.Items, err = q.ListPurchaseOrderItems(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range result.Items {
+			_, err = recordStockMovement(ctx, q, StockMovementTxParams{
+				MedicineID:     item.MedicineID,
+				MovementType:   arg.MovementType,
+				QuantityChange: -item.Quantity,
+				Reason:         arg.Reason,
+				Reference:      arg.Reference,
+				CreatedBy:      arg.CreatedBy,
+			})
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, item.Name)
+			}
+		}
+
+		result.Order, err = q.UpdatePurchaseOrderStatus(ctx, UpdatePurchaseOrderStatusParams{
+			ID:         order.ID,
+			Status:     "shipped",
+			FromStatus: order.Status,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ReceivePurchaseOrderTx adds the items of a shipped order to the buyer's
+// inventory as new batches, at the supplier's MRP, and marks the order as
+// received.
+func (store *Store) ReceivePurchaseOrderTx(ctx context.Context, arg PurchaseOrderStockTxParams) (PurchaseOrderTxResult, error) {
+	var result PurchaseOrderTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		order, err := q.GetPurchaseOrderForUpdate(ctx, arg.PurchaseOrderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != "shipped" {
+			return ErrPurchaseOrderStatus
+		}
+
+		items, err := q.ListPurchaseOrderItems(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			medicine, err := q.CreateMedicine(ctx, CreateMedicineParams{
+				Name:                 item.Name,
+				Description:          item.Description,
+				ExpiryDate:           item.ExpiryDate,
+				Quantity:             0,
+				Price:                item.Mrp,
+				SellerUsername:       order.BuyerUsername,
+				ActiveIngredients:    item.ActiveIngredients,
+				Strength:             item.Strength,
+				DosageForm:           item.DosageForm,
+				PrescriptionRequired: item.PrescriptionRequired,
+				BatchNumber:          item.BatchNumber,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = recordStockMovement(ctx, q, StockMovementTxParams{
+				MedicineID:     medicine.ID,
+				MovementType:   arg.MovementType,
+				QuantityChange: item.Quantity,
+				Reason:         arg.Reason,
+				Reference:      arg.Reference,
+				CreatedBy:      arg.CreatedBy,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = q.SetPurchaseOrderItemReceived(ctx, SetPurchaseOrderItemReceivedParams{
+				ID:                 item.ID,
+				ReceivedMedicineID: pgtype.Int4{Int32: medicine.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			item.ReceivedMedicineID = pgtype.Int4{Int32: medicine.ID, Valid: true}
+			result.Items = append(result.Items, item)
+		}
+
+		result.Order, err = q.UpdatePurchaseOrderStatus(ctx, UpdatePurchaseOrderStatusParams{
+			ID:         order.ID,
+			Status:     "received",
+			FromStatus: order.Status,
+		})
+		return err
+	})
+
+	return result, err
+}
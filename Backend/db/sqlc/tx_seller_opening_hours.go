@@ -0,0 +1,35 @@
+package db
+
+import "context"
+
+type ReplaceSellerOpeningHoursTxParams struct {
+	SellerUsername string                           `json:"seller_username"`
+	Hours          []CreateSellerOpeningHoursParams `json:"hours"`
+}
+
+// ReplaceSellerOpeningHoursTx swaps a seller's weekly opening hours for a
+// new set in one transaction, so the store is never seen without hours.
+func (store *Store) ReplaceSellerOpeningHoursTx(ctx context.Context, arg ReplaceSellerOpeningHoursTxParams) ([]SellerOpeningHour, error) {
+	var result []SellerOpeningHour
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.DeleteSellerOpeningHours(ctx, arg.SellerUsername)
+		if err != nil {
+			return err
+		}
+
+		result = make([]SellerOpeningHour, 0, len(arg.Hours))
+		for _, hours := range arg.Hours {
+			hours.SellerUsername = arg.SellerUsername
+			created, err := q.CreateSellerOpeningHours(ctx, hours)
+			if err != nil {
+				return err
+			}
+			result = append(result, created)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
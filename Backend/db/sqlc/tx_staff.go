@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrStaffInviteInvalid = errors.New("invite is invalid, expired or already used")
+
+type AcceptStaffInviteTxParams struct {
+	TokenHash string `json:"token_hash"`
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	// Password is the hashed password of the new account
+	Password string `json:"password"`
+}
+
+type AcceptStaffInviteTxResult struct {
+	Invite StaffInvite `json:"invite"`
+	Staff  StoreStaff  `json:"staff"`
+}
+
+// AcceptStaffInviteTx creates the staff account an invite is for, with the
+// invited email and role, and uses the invite up
+func (store *Store) AcceptStaffInviteTx(ctx context.Context, arg AcceptStaffInviteTxParams) (AcceptStaffInviteTxResult, error) {
+	var result AcceptStaffInviteTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		invite, err := q.GetStaffInviteByTokenForUpdate(ctx, arg.TokenHash)
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrStaffInviteInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if invite.AcceptedAt.Valid || invite.RevokedAt.Valid || !invite.ExpiresAt.Time.After(time.Now()) {
+			return ErrStaffInviteInvalid
+		}
+
+		result.Staff, err = q.CreateStoreStaff(ctx, CreateStoreStaffParams{
+			Username:       arg.Username,
+			SellerUsername: invite.SellerUsername,
+			FullName:       arg.FullName,
+			Email:          invite.Email,
+			Password:       arg.Password,
+			StaffRole:      invite.StaffRole,
+			InvitedBy:      invite.InvitedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Invite, err = q.AcceptStaffInvite(ctx, invite.ID)
+		return err
+	})
+
+	return result, err
+}
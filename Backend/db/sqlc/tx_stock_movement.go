@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrMedicineNotActive = errors.New("medicine is quarantined, disposed or withdrawn")
+)
+
+type StockMovementTxParams struct {
+	MedicineID   int32  `json:"medicine_id"`
+	MovementType string `json:"movement_type"`
+	// QuantityChange is the signed change to stock on hand. It is ignored
+	// when TargetQuantity is set, in which case the change is whatever
+	// brings the stock to that quantity.
+	QuantityChange int32       `json:"quantity_change"`
+	TargetQuantity pgtype.Int4 `json:"target_quantity"`
+	Reason         string      `json:"reason"`
+	Reference      string      `json:"reference"`
+	CreatedBy      string      `json:"created_by"`
+}
+
+type StockMovementTxResult struct {
+	Medicine Medicine `json:"medicine"`
+	// Movement is nil when the stock was already at the target quantity
+	Movement *StockMovement `json:"movement"`
+}
+
+// StockMovementTx changes a medicine's stock and appends the matching
+// ledger entry in one transaction. Every change to medicines.quantity
+// (checkout, cancellations, returns, the expiry job and manual corrections)
+// should go through here so the ledger always adds up to stock on hand.
+func (store *Store) StockMovementTx(ctx context.Context, arg StockMovementTxParams) (StockMovementTxResult, error) {
+	var result StockMovementTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = recordStockMovement(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func recordStockMovement(ctx context.Context, q *Queries, arg StockMovementTxParams) (StockMovementTxResult, error) {
+	var result StockMovementTxResult
+
+	medicine, err := q.GetMedicineForUpdate(ctx, arg.MedicineID)
+	if err != nil {
+		return result, err
+	}
+
+	change := arg.QuantityChange
+	if arg.TargetQuantity.Valid {
+		change = arg.TargetQuantity.Int32 - medicine.Quantity
+	}
+
+	result.Medicine = medicine
+	if change == 0 {
+		return result, nil
+	}
+
+	quantity := medicine.Quantity + change
+	if quantity < 0 {
+		return result, ErrInsufficientStock
+	}
+
+	result.Medicine, err = q.UpdateMedicineQuantity(ctx, UpdateMedicineQuantityParams{
+		ID:       medicine.ID,
+		Quantity: quantity,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	movement, err := q.CreateStockMovement(ctx, CreateStockMovementParams{
+		MedicineID:     medicine.ID,
+		SellerUsername: medicine.SellerUsername,
+		MovementType:   arg.MovementType,
+		QuantityChange: change,
+		QuantityAfter:  quantity,
+		Reason:         arg.Reason,
+		Reference:      arg.Reference,
+		CreatedBy:      arg.CreatedBy,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Movement = &movement
+
+	return result, nil
+}
+
+type CreateMedicineTxParams struct {
+	CreateMedicineParams
+	// MovementType, Reason and Reference describe the ledger entry for the
+	// opening stock of the new medicine
+	MovementType string `json:"movement_type"`
+	Reason       string `json:"reason"`
+	Reference    string `json:"reference"`
+	CreatedBy    string `json:"created_by"`
+}
+
+// CreateMedicineTx creates a medicine and records its opening stock in the
+// ledger.
+func (store *Store) CreateMedicineTx(ctx context.Context, arg CreateMedicineTxParams) (Medicine, error) {
+	var medicine Medicine
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		medicine, err = q.CreateMedicine(ctx, arg.CreateMedicineParams)
+		if err != nil {
+			return err
+		}
+
+		if medicine.Quantity == 0 {
+			return nil
+		}
+
+		_, err = q.CreateStockMovement(ctx, CreateStockMovementParams{
+			MedicineID:     medicine.ID,
+			SellerUsername: medicine.SellerUsername,
+			MovementType:   arg.MovementType,
+			QuantityChange: medicine.Quantity,
+			QuantityAfter:  medicine.Quantity,
+			Reason:         arg.Reason,
+			Reference:      arg.Reference,
+			CreatedBy:      arg.CreatedBy,
+		})
+		return err
+	})
+
+	return medicine, err
+}
+
+type WithdrawMedicineTxParams struct {
+	MedicineID int32  `json:"medicine_id"`
+	CreatedBy  string `json:"created_by"`
+}
+
+// WithdrawMedicineTx takes a medicine off sale for good. Its stock is
+// adjusted to zero in the ledger and the medicine is kept as withdrawn, so
+// carts, orders and the ledger still point at it. Only active medicines can
+// be withdrawn; others return ErrMedicineNotActive.
+func (store *Store) WithdrawMedicineTx(ctx context.Context, arg WithdrawMedicineTxParams) (Medicine, error) {
+	var medicine Medicine
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetMedicineForUpdate(ctx, arg.MedicineID)
+		if err != nil {
+			return err
+		}
+		if current.Status != "active" {
+			return ErrMedicineNotActive
+		}
+
+		_, err = recordStockMovement(ctx, q, StockMovementTxParams{
+			MedicineID:     arg.MedicineID,
+			MovementType:   "adjustment",
+			TargetQuantity: pgtype.Int4{Int32: 0, Valid: true},
+			Reason:         "medicine withdrawn",
+			CreatedBy:      arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		medicine, err = q.WithdrawMedicine(ctx, arg.MedicineID)
+		return err
+	})
+
+	return medicine, err
+}
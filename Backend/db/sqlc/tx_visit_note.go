@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrVisitNoteFinalized = errors.New("visit note is finalized and can't be changed")
+
+type SaveVisitNoteTxParams struct {
+	Note         SaveVisitNoteParams            `json:"note"`
+	Prescription []CreatePrescriptionItemParams `json:"prescription"`
+}
+
+type SaveVisitNoteTxResult struct {
+	Note         VisitNote          `json:"note"`
+	Prescription []PrescriptionItem `json:"prescription"`
+}
+
+// SaveVisitNoteTx writes the draft note of an appointment together with its
+// prescription, which replaces the one saved before
+func (store *Store) SaveVisitNoteTx(ctx context.Context, arg SaveVisitNoteTxParams) (SaveVisitNoteTxResult, error) {
+	var result SaveVisitNoteTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Note, err = q.SaveVisitNote(ctx, arg.Note)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrVisitNoteFinalized
+			}
+			return err
+		}
+
+		err = q.DeletePrescriptionItems(ctx, result.Note.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Prescription = make([]PrescriptionItem, 0, len(arg.Prescription))
+		for _, item := range arg.Prescription {
+			item.VisitNoteID = result.Note.ID
+			created, err := q.CreatePrescriptionItem(ctx, item)
+			if err != nil {
+				return err
+			}
+			result.Prescription = append(result.Prescription, created)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
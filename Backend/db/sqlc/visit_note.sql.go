@@ -0,0 +1,430 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: visit_note.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createPrescriptionItem = `-- name: CreatePrescriptionItem :one
+INSERT INTO prescription_items (
+    visit_note_id, medicine_name, active_ingredients, strength, dosage_form,
+    dose, frequency, duration_days, instructions
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, visit_note_id, medicine_name, active_ingredients, strength, dosage_form, dose, frequency, duration_days, instructions
+`
+
+type CreatePrescriptionItemParams struct {
+	VisitNoteID       int32  `json:"visit_note_id"`
+	MedicineName      string `json:"medicine_name"`
+	ActiveIngredients string `json:"active_ingredients"`
+	Strength          string `json:"strength"`
+	DosageForm        string `json:"dosage_form"`
+	Dose              string `json:"dose"`
+	Frequency         string `json:"frequency"`
+	DurationDays      int32  `json:"duration_days"`
+	Instructions      string `json:"instructions"`
+}
+
+func (q *Queries) CreatePrescriptionItem(ctx context.Context, arg CreatePrescriptionItemParams) (PrescriptionItem, error) {
+	row := q.db.QueryRow(ctx, createPrescriptionItem,
+		arg.VisitNoteID,
+		arg.MedicineName,
+		arg.ActiveIngredients,
+		arg.Strength,
+		arg.DosageForm,
+		arg.Dose,
+		arg.Frequency,
+		arg.DurationDays,
+		arg.Instructions,
+	)
+	var i PrescriptionItem
+	err := row.Scan(
+		&i.ID,
+		&i.VisitNoteID,
+		&i.MedicineName,
+		&i.ActiveIngredients,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Dose,
+		&i.Frequency,
+		&i.DurationDays,
+		&i.Instructions,
+	)
+	return i, err
+}
+
+const deletePrescriptionItems = `-- name: DeletePrescriptionItems :exec
+DELETE FROM prescription_items WHERE visit_note_id = $1
+`
+
+func (q *Queries) DeletePrescriptionItems(ctx context.Context, visitNoteID int32) error {
+	_, err := q.db.Exec(ctx, deletePrescriptionItems, visitNoteID)
+	return err
+}
+
+const finalizeVisitNote = `-- name: FinalizeVisitNote :one
+UPDATE visit_notes SET
+    status = 'finalized',
+    finalized_at = now(),
+    updated_at = now()
+WHERE id = $1 AND status = 'draft'
+RETURNING id, appointment_id, doctor_username, patient_username, chief_complaint, bp_systolic, bp_diastolic, pulse_bpm, temperature_c, respiratory_rate, spo2_percent, weight_kg, height_cm, diagnosis, icd10_code, advice, status, created_at, updated_at, finalized_at
+`
+
+func (q *Queries) FinalizeVisitNote(ctx context.Context, id int32) (VisitNote, error) {
+	row := q.db.QueryRow(ctx, finalizeVisitNote, id)
+	var i VisitNote
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.ChiefComplaint,
+		&i.BpSystolic,
+		&i.BpDiastolic,
+		&i.PulseBpm,
+		&i.TemperatureC,
+		&i.RespiratoryRate,
+		&i.Spo2Percent,
+		&i.WeightKg,
+		&i.HeightCm,
+		&i.Diagnosis,
+		&i.Icd10Code,
+		&i.Advice,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinalizedAt,
+	)
+	return i, err
+}
+
+const getICD10Code = `-- name: GetICD10Code :one
+SELECT code, description FROM icd10_codes WHERE code = $1
+`
+
+func (q *Queries) GetICD10Code(ctx context.Context, code string) (Icd10Code, error) {
+	row := q.db.QueryRow(ctx, getICD10Code, code)
+	var i Icd10Code
+	err := row.Scan(&i.Code, &i.Description)
+	return i, err
+}
+
+const getVisitNoteByAppointment = `-- name: GetVisitNoteByAppointment :one
+SELECT id, appointment_id, doctor_username, patient_username, chief_complaint, bp_systolic, bp_diastolic, pulse_bpm, temperature_c, respiratory_rate, spo2_percent, weight_kg, height_cm, diagnosis, icd10_code, advice, status, created_at, updated_at, finalized_at FROM visit_notes WHERE appointment_id = $1
+`
+
+func (q *Queries) GetVisitNoteByAppointment(ctx context.Context, appointmentID int32) (VisitNote, error) {
+	row := q.db.QueryRow(ctx, getVisitNoteByAppointment, appointmentID)
+	var i VisitNote
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.ChiefComplaint,
+		&i.BpSystolic,
+		&i.BpDiastolic,
+		&i.PulseBpm,
+		&i.TemperatureC,
+		&i.RespiratoryRate,
+		&i.Spo2Percent,
+		&i.WeightKg,
+		&i.HeightCm,
+		&i.Diagnosis,
+		&i.Icd10Code,
+		&i.Advice,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinalizedAt,
+	)
+	return i, err
+}
+
+const isDoctorOfPatient = `-- name: IsDoctorOfPatient :one
+SELECT EXISTS (
+    SELECT 1 FROM appointments
+    WHERE doctor_username = $1
+      AND patient_username = $2
+      AND status <> 'cancelled'
+)::BOOLEAN
+`
+
+type IsDoctorOfPatientParams struct {
+	DoctorUsername  string `json:"doctor_username"`
+	PatientUsername string `json:"patient_username"`
+}
+
+// Whether the doctor has an appointment with the patient that wasn't
+// cancelled, which lets them read the patient's history
+func (q *Queries) IsDoctorOfPatient(ctx context.Context, arg IsDoctorOfPatientParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isDoctorOfPatient, arg.DoctorUsername, arg.PatientUsername)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listPatientVisitSummaries = `-- name: ListPatientVisitSummaries :many
+SELECT v.id, v.appointment_id, v.doctor_username, v.patient_username, v.chief_complaint, v.bp_systolic, v.bp_diastolic, v.pulse_bpm, v.temperature_c, v.respiratory_rate, v.spo2_percent, v.weight_kg, v.height_cm, v.diagnosis, v.icd10_code, v.advice, v.status, v.created_at, v.updated_at, v.finalized_at, d.full_name AS doctor_name, d.specialization, d.hospital_name,
+    a.starts_at AS appointment_starts_at, i.description AS icd10_description
+FROM visit_notes v
+JOIN doctors d ON d.username = v.doctor_username
+JOIN appointments a ON a.id = v.appointment_id
+LEFT JOIN icd10_codes i ON i.code = v.icd10_code
+WHERE v.patient_username = $1
+  AND v.status = 'finalized'
+ORDER BY a.starts_at DESC, v.id DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListPatientVisitSummariesParams struct {
+	PatientUsername string `json:"patient_username"`
+	OffsetCount     int32  `json:"offset_count"`
+	LimitCount      int32  `json:"limit_count"`
+}
+
+type ListPatientVisitSummariesRow struct {
+	ID                  int32            `json:"id"`
+	AppointmentID       int32            `json:"appointment_id"`
+	DoctorUsername      string           `json:"doctor_username"`
+	PatientUsername     string           `json:"patient_username"`
+	ChiefComplaint      string           `json:"chief_complaint"`
+	BpSystolic          pgtype.Int4      `json:"bp_systolic"`
+	BpDiastolic         pgtype.Int4      `json:"bp_diastolic"`
+	PulseBpm            pgtype.Int4      `json:"pulse_bpm"`
+	TemperatureC        pgtype.Float8    `json:"temperature_c"`
+	RespiratoryRate     pgtype.Int4      `json:"respiratory_rate"`
+	Spo2Percent         pgtype.Int4      `json:"spo2_percent"`
+	WeightKg            pgtype.Float8    `json:"weight_kg"`
+	HeightCm            pgtype.Float8    `json:"height_cm"`
+	Diagnosis           string           `json:"diagnosis"`
+	Icd10Code           pgtype.Text      `json:"icd10_code"`
+	Advice              string           `json:"advice"`
+	Status              string           `json:"status"`
+	CreatedAt           pgtype.Timestamp `json:"created_at"`
+	UpdatedAt           pgtype.Timestamp `json:"updated_at"`
+	FinalizedAt         pgtype.Timestamp `json:"finalized_at"`
+	DoctorName          string           `json:"doctor_name"`
+	Specialization      string           `json:"specialization"`
+	HospitalName        pgtype.Text      `json:"hospital_name"`
+	AppointmentStartsAt pgtype.Timestamp `json:"appointment_starts_at"`
+	Icd10Description    pgtype.Text      `json:"icd10_description"`
+}
+
+// The patient's finalized visit notes, newest first, with the doctor who
+// wrote them
+func (q *Queries) ListPatientVisitSummaries(ctx context.Context, arg ListPatientVisitSummariesParams) ([]ListPatientVisitSummariesRow, error) {
+	rows, err := q.db.Query(ctx, listPatientVisitSummaries, arg.PatientUsername, arg.OffsetCount, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPatientVisitSummariesRow{}
+	for rows.Next() {
+		var i ListPatientVisitSummariesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.DoctorUsername,
+			&i.PatientUsername,
+			&i.ChiefComplaint,
+			&i.BpSystolic,
+			&i.BpDiastolic,
+			&i.PulseBpm,
+			&i.TemperatureC,
+			&i.RespiratoryRate,
+			&i.Spo2Percent,
+			&i.WeightKg,
+			&i.HeightCm,
+			&i.Diagnosis,
+			&i.Icd10Code,
+			&i.Advice,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FinalizedAt,
+			&i.DoctorName,
+			&i.Specialization,
+			&i.HospitalName,
+			&i.AppointmentStartsAt,
+			&i.Icd10Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPrescriptionItems = `-- name: ListPrescriptionItems :many
+SELECT id, visit_note_id, medicine_name, active_ingredients, strength, dosage_form, dose, frequency, duration_days, instructions FROM prescription_items
+WHERE visit_note_id = ANY($1::INTEGER[])
+ORDER BY visit_note_id, id
+`
+
+func (q *Queries) ListPrescriptionItems(ctx context.Context, visitNoteIds []int32) ([]PrescriptionItem, error) {
+	rows, err := q.db.Query(ctx, listPrescriptionItems, visitNoteIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PrescriptionItem{}
+	for rows.Next() {
+		var i PrescriptionItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.VisitNoteID,
+			&i.MedicineName,
+			&i.ActiveIngredients,
+			&i.Strength,
+			&i.DosageForm,
+			&i.Dose,
+			&i.Frequency,
+			&i.DurationDays,
+			&i.Instructions,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveVisitNote = `-- name: SaveVisitNote :one
+INSERT INTO visit_notes (
+    appointment_id, doctor_username, patient_username, chief_complaint,
+    bp_systolic, bp_diastolic, pulse_bpm, temperature_c, respiratory_rate,
+    spo2_percent, weight_kg, height_cm, diagnosis, icd10_code, advice
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+ON CONFLICT (appointment_id) DO UPDATE SET
+    chief_complaint = EXCLUDED.chief_complaint,
+    bp_systolic = EXCLUDED.bp_systolic,
+    bp_diastolic = EXCLUDED.bp_diastolic,
+    pulse_bpm = EXCLUDED.pulse_bpm,
+    temperature_c = EXCLUDED.temperature_c,
+    respiratory_rate = EXCLUDED.respiratory_rate,
+    spo2_percent = EXCLUDED.spo2_percent,
+    weight_kg = EXCLUDED.weight_kg,
+    height_cm = EXCLUDED.height_cm,
+    diagnosis = EXCLUDED.diagnosis,
+    icd10_code = EXCLUDED.icd10_code,
+    advice = EXCLUDED.advice,
+    updated_at = now()
+WHERE visit_notes.status = 'draft'
+RETURNING id, appointment_id, doctor_username, patient_username, chief_complaint, bp_systolic, bp_diastolic, pulse_bpm, temperature_c, respiratory_rate, spo2_percent, weight_kg, height_cm, diagnosis, icd10_code, advice, status, created_at, updated_at, finalized_at
+`
+
+type SaveVisitNoteParams struct {
+	AppointmentID   int32         `json:"appointment_id"`
+	DoctorUsername  string        `json:"doctor_username"`
+	PatientUsername string        `json:"patient_username"`
+	ChiefComplaint  string        `json:"chief_complaint"`
+	BpSystolic      pgtype.Int4   `json:"bp_systolic"`
+	BpDiastolic     pgtype.Int4   `json:"bp_diastolic"`
+	PulseBpm        pgtype.Int4   `json:"pulse_bpm"`
+	TemperatureC    pgtype.Float8 `json:"temperature_c"`
+	RespiratoryRate pgtype.Int4   `json:"respiratory_rate"`
+	Spo2Percent     pgtype.Int4   `json:"spo2_percent"`
+	WeightKg        pgtype.Float8 `json:"weight_kg"`
+	HeightCm        pgtype.Float8 `json:"height_cm"`
+	Diagnosis       string        `json:"diagnosis"`
+	Icd10Code       pgtype.Text   `json:"icd10_code"`
+	Advice          string        `json:"advice"`
+}
+
+// Creates the note of an appointment or replaces its draft. A finalized
+// note is left alone and no row is returned.
+func (q *Queries) SaveVisitNote(ctx context.Context, arg SaveVisitNoteParams) (VisitNote, error) {
+	row := q.db.QueryRow(ctx, saveVisitNote,
+		arg.AppointmentID,
+		arg.DoctorUsername,
+		arg.PatientUsername,
+		arg.ChiefComplaint,
+		arg.BpSystolic,
+		arg.BpDiastolic,
+		arg.PulseBpm,
+		arg.TemperatureC,
+		arg.RespiratoryRate,
+		arg.Spo2Percent,
+		arg.WeightKg,
+		arg.HeightCm,
+		arg.Diagnosis,
+		arg.Icd10Code,
+		arg.Advice,
+	)
+	var i VisitNote
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.DoctorUsername,
+		&i.PatientUsername,
+		&i.ChiefComplaint,
+		&i.BpSystolic,
+		&i.BpDiastolic,
+		&i.PulseBpm,
+		&i.TemperatureC,
+		&i.RespiratoryRate,
+		&i.Spo2Percent,
+		&i.WeightKg,
+		&i.HeightCm,
+		&i.Diagnosis,
+		&i.Icd10Code,
+		&i.Advice,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinalizedAt,
+	)
+	return i, err
+}
+
+const searchICD10Codes = `-- name: SearchICD10Codes :many
+SELECT code, description FROM icd10_codes
+WHERE code ILIKE $1::VARCHAR || '%'
+   OR description ILIKE '%' || $1::VARCHAR || '%'
+ORDER BY code ILIKE $1::VARCHAR || '%' DESC, code
+LIMIT $2
+`
+
+type SearchICD10CodesParams struct {
+	Query      string `json:"query"`
+	LimitCount int32  `json:"limit_count"`
+}
+
+// Codes starting with the query come first, then descriptions matching it
+func (q *Queries) SearchICD10Codes(ctx context.Context, arg SearchICD10CodesParams) ([]Icd10Code, error) {
+	rows, err := q.db.Query(ctx, searchICD10Codes, arg.Query, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Icd10Code{}
+	for rows.Next() {
+		var i Icd10Code
+		if err := rows.Scan(&i.Code, &i.Description); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
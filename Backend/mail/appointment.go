@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// AppointmentTimeLayout is how appointment times are shown in emails
+const AppointmentTimeLayout = "Mon, 02 Jan 2006 15:04"
+
+// NewAppointmentData fills in the appointment template for an action on
+// an appointment. The recipient is left for the caller to set.
+func NewAppointmentData(appointment db.Appointment, doctor db.Doctor, patient db.Patient, action string) AppointmentData {
+	data := AppointmentData{
+		Action:          action,
+		Title:           strings.ToUpper(action[:1]) + action[1:],
+		DoctorName:      doctor.FullName,
+		Specialization:  doctor.Specialization,
+		HospitalName:    doctor.HospitalName.String,
+		PatientName:     patient.FullName,
+		Time:            appointment.StartsAt.Time.Format(AppointmentTimeLayout),
+		DurationMinutes: int(appointment.EndsAt.Time.Sub(appointment.StartsAt.Time).Minutes()),
+		Reason:          appointment.Reason,
+		VideoVisit:      appointment.VisitType == util.VideoVisit,
+		FollowUp:        appointment.FollowUpOf.Valid,
+		Refunded:        appointment.PaymentStatus == util.PaymentRefunded,
+	}
+
+	if appointment.PaymentStatus != util.PaymentNotRequired {
+		data.Fee = formatMoney(appointment.Fee)
+	}
+
+	if appointment.CancelledBy.Valid {
+		data.CancelledBy = patient.FullName
+		if appointment.CancelledBy.String == doctor.Username {
+			data.CancelledBy = "Dr. " + doctor.FullName
+		}
+		data.CancellationReason = appointment.CancellationReason.String
+	}
+
+	return data
+}
+
+// SendAppointmentEmails sends the same appointment email to the patient and
+// the doctor. It returns how many of the two went out, with the errors of
+// those that didn't.
+func (m *Mailer) SendAppointmentEmails(doctor db.Doctor, patient db.Patient, data AppointmentData) (int, error) {
+	recipients := []struct{ name, email string }{
+		{patient.FullName, patient.Email},
+		{"Dr. " + doctor.FullName, doctor.Email},
+	}
+
+	var sent int
+	var errs []error
+	for _, recipient := range recipients {
+		data.RecipientName = recipient.name
+		if err := m.SendAppointmentEmail(recipient.email, data); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", recipient.email, err))
+			continue
+		}
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
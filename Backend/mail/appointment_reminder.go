@@ -0,0 +1,165 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// AppointmentReminderJob reminds patients and doctors of upcoming
+// appointments and closes appointments once they are over
+type AppointmentReminderJob struct {
+	store  db.Store
+	mailer *Mailer
+	config util.Config
+}
+
+// NewAppointmentReminderJob creates a new AppointmentReminderJob
+func NewAppointmentReminderJob(store db.Store, mailer *Mailer, config util.Config) *AppointmentReminderJob {
+	return &AppointmentReminderJob{
+		store:  store,
+		mailer: mailer,
+		config: config,
+	}
+}
+
+// StartAppointmentReminderScheduler runs the job every
+// AppointmentReminderPeriod, which bounds how late a reminder can be
+func (j *AppointmentReminderJob) StartAppointmentReminderScheduler(ctx context.Context) {
+	// Run immediately on startup
+	j.Run(ctx)
+
+	// Set up periodic check
+	ticker := time.NewTicker(j.config.AppointmentReminderPeriod)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				j.Run(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Run sends the reminders that are due, releases the slots of appointments
+// that weren't paid for and closes finished appointments
+func (j *AppointmentReminderJob) Run(ctx context.Context) {
+	for _, reminder := range util.AppointmentReminders {
+		j.SendReminders(ctx, reminder)
+	}
+	j.ReleaseUnpaidAppointments(ctx)
+	j.CloseFinishedAppointments(ctx)
+}
+
+// ReleaseUnpaidAppointments cancels appointments whose fee wasn't paid
+// before their hold on the slot ran out, freeing the slots
+func (j *AppointmentReminderJob) ReleaseUnpaidAppointments(ctx context.Context) {
+	appointments, err := j.store.ReleaseUnpaidAppointments(ctx, pgtype.Timestamp{Time: time.Now(), Valid: true})
+	if err != nil {
+		log.Printf("Error releasing unpaid appointments: %v", err)
+		return
+	}
+
+	if len(appointments) > 0 {
+		log.Printf("Released %d appointments that weren't paid for in time.", len(appointments))
+	}
+}
+
+// SendReminders sends one kind of reminder for every appointment it is due
+// for. Each reminder is recorded before it is sent, so a restart can't send
+// it again; it is given back only if neither email went out.
+func (j *AppointmentReminderJob) SendReminders(ctx context.Context, reminder util.AppointmentReminder) {
+	appointments, err := j.store.ListDueAppointmentReminders(ctx, db.ListDueAppointmentRemindersParams{
+		Now:         pgtype.Timestamp{Time: time.Now(), Valid: true},
+		LeadMinutes: int32(reminder.Lead / time.Minute),
+		Kind:        reminder.Kind,
+	})
+	if err != nil {
+		log.Printf("Error getting appointments due a %s reminder: %v", reminder.Kind, err)
+		return
+	}
+
+	var sentCount int
+	for _, appointment := range appointments {
+		claimed, err := j.store.ClaimAppointmentReminder(ctx, db.ClaimAppointmentReminderParams{
+			AppointmentID: appointment.ID,
+			Kind:          reminder.Kind,
+		})
+		if err != nil {
+			log.Printf("Error recording %s reminder of appointment %d: %v", reminder.Kind, appointment.ID, err)
+			continue
+		}
+		if claimed == 0 {
+			continue
+		}
+
+		doctor, err := j.store.GetDoctorByName(ctx, appointment.DoctorUsername)
+		if err != nil {
+			log.Printf("Error getting doctor %s: %v", appointment.DoctorUsername, err)
+			j.releaseReminder(ctx, appointment.ID, reminder.Kind)
+			continue
+		}
+		patient, err := j.store.GetPatientByName(ctx, appointment.PatientUsername)
+		if err != nil {
+			log.Printf("Error getting patient %s: %v", appointment.PatientUsername, err)
+			j.releaseReminder(ctx, appointment.ID, reminder.Kind)
+			continue
+		}
+
+		data := NewAppointmentData(appointment, doctor, patient, "reminder")
+		data.ReminderLead = reminder.LeadText
+
+		sent, err := j.mailer.SendAppointmentEmails(doctor, patient, data)
+		if err != nil {
+			log.Printf("Error sending %s reminder of appointment %d: %v", reminder.Kind, appointment.ID, err)
+		}
+		if sent == 0 {
+			j.releaseReminder(ctx, appointment.ID, reminder.Kind)
+			continue
+		}
+		sentCount++
+	}
+
+	log.Printf("Appointment reminders (%s) completed. Reminded %d appointments.", reminder.Kind, sentCount)
+}
+
+func (j *AppointmentReminderJob) releaseReminder(ctx context.Context, appointmentID int32, kind string) {
+	err := j.store.ReleaseAppointmentReminder(ctx, db.ReleaseAppointmentReminderParams{
+		AppointmentID: appointmentID,
+		Kind:          kind,
+	})
+	if err != nil {
+		log.Printf("Error releasing %s reminder of appointment %d: %v", kind, appointmentID, err)
+	}
+}
+
+// CloseFinishedAppointments closes booked appointments that ended more than
+// NoShowGracePeriod ago: completed when the patient or the doctor checked
+// in, a no-show when neither did
+func (j *AppointmentReminderJob) CloseFinishedAppointments(ctx context.Context) {
+	endedBefore := pgtype.Timestamp{Time: time.Now().Add(-j.config.NoShowGracePeriod), Valid: true}
+
+	appointments, err := j.store.CloseFinishedAppointments(ctx, endedBefore)
+	if err != nil {
+		log.Printf("Error closing finished appointments: %v", err)
+		return
+	}
+
+	var noShows int
+	for _, appointment := range appointments {
+		if appointment.Status == util.NoShowAppointment {
+			noShows++
+		}
+	}
+
+	if len(appointments) > 0 {
+		log.Printf("Closed %d finished appointments, %d of them no-shows.", len(appointments), noShows)
+	}
+}
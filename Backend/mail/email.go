@@ -1,141 +1,275 @@
-package mail
-
-import (
-	"bytes"
-	"fmt"
-	"html/template"
-	"net/smtp"
-	"path/filepath"
-	"time"
-
-	"github.com/pawaspy/MediBridge/util"
-)
-
-// EmailData contains data to be used in email templates
-type EmailData struct {
-	SellerName      string
-	MedicineName    string
-	MedicineID      int32
-	Price           string
-	Quantity        int32
-	ExpiryDate      string
-	DaysUntilExpiry int
-	DaysSinceExpiry int
-}
-
-// Mailer is responsible for sending emails
-type Mailer struct {
-	config      util.Config
-	host        string
-	port        string
-	username    string
-	password    string
-	senderName  string
-	senderEmail string
-	auth        smtp.Auth
-	templates   map[string]*template.Template
-}
-
-// NewMailer creates a new Mailer instance
-func NewMailer(config util.Config) (*Mailer, error) {
-	mailer := &Mailer{
-		config:      config,
-		host:        config.SMTPHost,
-		port:        config.SMTPPort,
-		username:    config.SMTPUsername,
-		password:    config.SMTPPassword,
-		senderName:  config.SenderName,
-		senderEmail: config.SenderEmail,
-		templates:   make(map[string]*template.Template),
-	}
-
-	mailer.auth = smtp.PlainAuth("", mailer.username, mailer.password, mailer.host)
-
-	// Load email templates
-	templatesDir := "mail/templates"
-	templates := []string{"expiring_soon.html", "expired.html"}
-
-	for _, tmpl := range templates {
-		t, err := template.ParseFiles(filepath.Join(templatesDir, tmpl))
-		if err != nil {
-			return nil, fmt.Errorf("error parsing template %s: %w", tmpl, err)
-		}
-		mailer.templates[tmpl] = t
-	}
-
-	return mailer, nil
-}
-
-// SendExpiringMedicineEmail sends an email notification for medicines expiring soon
-func (m *Mailer) SendExpiringMedicineEmail(recipientEmail, sellerName string, medicineID int32, medicineName, price string, quantity int32, expiryDate time.Time, daysUntilExpiry int) error {
-	templateName := "expiring_soon.html"
-	subject := "Important: Medicine Expiring Soon - Action Required"
-
-	data := EmailData{
-		SellerName:      sellerName,
-		MedicineName:    medicineName,
-		MedicineID:      medicineID,
-		Price:           price,
-		Quantity:        quantity,
-		ExpiryDate:      expiryDate.Format("2006-01-02"),
-		DaysUntilExpiry: daysUntilExpiry,
-	}
-
-	return m.sendEmail(recipientEmail, subject, templateName, data)
-}
-
-// SendExpiredMedicineEmail sends an email notification for expired medicines
-func (m *Mailer) SendExpiredMedicineEmail(recipientEmail, sellerName string, medicineID int32, medicineName string, quantity int32, expiryDate time.Time, daysSinceExpiry int) error {
-	templateName := "expired.html"
-	subject := "URGENT: Medicine Expired - Removed from Inventory"
-
-	data := EmailData{
-		SellerName:      sellerName,
-		MedicineName:    medicineName,
-		MedicineID:      medicineID,
-		Quantity:        quantity,
-		ExpiryDate:      expiryDate.Format("2006-01-02"),
-		DaysSinceExpiry: daysSinceExpiry,
-	}
-
-	return m.sendEmail(recipientEmail, subject, templateName, data)
-}
-
-// sendEmail handles the actual email sending process
-func (m *Mailer) sendEmail(to, subject, templateName string, data EmailData) error {
-	// Get the template
-	tmpl, ok := m.templates[templateName]
-	if !ok {
-		return fmt.Errorf("template %s not found", templateName)
-	}
-
-	// Render the template
-	var body bytes.Buffer
-	if err := tmpl.Execute(&body, data); err != nil {
-		return fmt.Errorf("error executing template: %w", err)
-	}
-
-	// Prepare email
-	from := fmt.Sprintf("%s <%s>", m.senderName, m.senderEmail)
-	headers := make(map[string]string)
-	headers["From"] = from
-	headers["To"] = to
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=UTF-8"
-
-	// Construct message
-	message := ""
-	for k, v := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
-	}
-	message += "\r\n" + body.String()
-
-	// Send email
-	addr := fmt.Sprintf("%s:%s", m.host, m.port)
-	if err := smtp.SendMail(addr, m.auth, m.senderEmail, []string{to}, []byte(message)); err != nil {
-		return fmt.Errorf("error sending email: %w", err)
-	}
-
-	return nil
-}
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/smtp"
+	"path/filepath"
+
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// ExpiryDigestItem is one medicine in an expiry digest
+type ExpiryDigestItem struct {
+	MedicineID   int32
+	MedicineName string
+	BatchNumber  string
+	Price        string
+	Quantity     int32
+	ExpiryDate   string
+	// Days is the number of days until expiry, or since expiry for
+	// medicines that have already expired
+	Days int
+	// DonationSuggested is set when the medicine could be offered to NGO
+	// and hospital sellers
+	DonationSuggested bool
+}
+
+// ExpiryDigestGroup is a section of the digest, such as "Expiring within
+// 30 days"
+type ExpiryDigestGroup struct {
+	Title   string
+	Expired bool
+	Items   []ExpiryDigestItem
+}
+
+// ExpiryDigestData contains data used by the expiry digest template
+type ExpiryDigestData struct {
+	SellerName string
+	Groups     []ExpiryDigestGroup
+	TotalItems int
+	// DonationSuggestions counts the items that could be offered as a
+	// donation, being within DonationWindowDays of expiry
+	DonationSuggestions int
+	DonationWindowDays  int
+}
+
+// MarkdownChange is one discount change made by the markdown job
+type MarkdownChange struct {
+	MedicineID   int32
+	MedicineName string
+	BatchNumber  string
+	ExpiryDate   string
+	OldDiscount  int32
+	NewDiscount  int32
+	Restored     bool
+}
+
+// MarkdownSummaryData contains data used by the markdown summary template
+type MarkdownSummaryData struct {
+	SellerName string
+	Changes    []MarkdownChange
+}
+
+// LowStockItem is a medicine at or below its reorder level
+type LowStockItem struct {
+	MedicineID        int32
+	MedicineName      string
+	BatchNumber       string
+	Quantity          int32
+	ReorderLevel      int32
+	UnitsSold         int32
+	SuggestedQuantity int
+}
+
+// LowStockData contains data used by the low stock template
+type LowStockData struct {
+	SellerName      string
+	Items           []LowStockItem
+	SalesWindowDays int
+	CoverDays       int
+}
+
+// StaffInviteData contains data used by the staff invite template
+type StaffInviteData struct {
+	StoreName string
+	InvitedBy string
+	RoleName  string
+	Token     string
+	ExpiresAt string
+}
+
+// PayoutStatementData contains data used by the settlement statement
+// template. Amounts are formatted rupee values.
+type PayoutStatementData struct {
+	SellerName     string
+	StoreName      string
+	PayoutID       int32
+	PeriodStart    string
+	PeriodEnd      string
+	OpeningBalance string
+	GrossSales     string
+	Commission     string
+	Refunds        string
+	Chargebacks    string
+	Amount         string
+}
+
+// AppointmentData contains data used by the appointment template, sent to
+// both the patient and the doctor when an appointment is booked,
+// rescheduled or cancelled, and ahead of it as a reminder
+type AppointmentData struct {
+	RecipientName string
+	// Action is booked, rescheduled, cancelled or reminder
+	Action          string
+	Title           string
+	DoctorName      string
+	Specialization  string
+	HospitalName    string
+	PatientName     string
+	Time            string
+	DurationMinutes int
+	Reason          string
+	// PreviousTime is set when the appointment was rescheduled
+	PreviousTime string
+	// CancelledBy and CancellationReason are set when it was cancelled
+	CancelledBy        string
+	CancellationReason string
+	// ReminderLead is how long before the appointment a reminder is sent,
+	// such as "24 hours"
+	ReminderLead string
+	// VideoVisit is set for video consultations
+	VideoVisit bool
+	// Fee is the fee in rupees, empty for free appointments. FollowUp
+	// tells it was charged at the follow-up rate and Refunded that it has
+	// been paid back.
+	Fee      string
+	FollowUp bool
+	Refunded bool
+}
+
+// Mailer is responsible for sending emails
+type Mailer struct {
+	config      util.Config
+	host        string
+	port        string
+	username    string
+	password    string
+	senderName  string
+	senderEmail string
+	auth        smtp.Auth
+	templates   map[string]*template.Template
+}
+
+// NewMailer creates a new Mailer instance
+func NewMailer(config util.Config) (*Mailer, error) {
+	mailer := &Mailer{
+		config:      config,
+		host:        config.SMTPHost,
+		port:        config.SMTPPort,
+		username:    config.SMTPUsername,
+		password:    config.SMTPPassword,
+		senderName:  config.SenderName,
+		senderEmail: config.SenderEmail,
+		templates:   make(map[string]*template.Template),
+	}
+
+	mailer.auth = smtp.PlainAuth("", mailer.username, mailer.password, mailer.host)
+
+	// Load email templates
+	templatesDir := "mail/templates"
+	templates := []string{"expiry_digest.html", "markdown_summary.html", "low_stock.html", "staff_invite.html", "payout_statement.html", "appointment.html"}
+
+	for _, tmpl := range templates {
+		t, err := template.ParseFiles(filepath.Join(templatesDir, tmpl))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template %s: %w", tmpl, err)
+		}
+		mailer.templates[tmpl] = t
+	}
+
+	return mailer, nil
+}
+
+// SendExpiryDigestEmail sends a seller a single summary of their expired
+// and soon-to-expire medicines
+func (m *Mailer) SendExpiryDigestEmail(recipientEmail string, data ExpiryDigestData) error {
+	templateName := "expiry_digest.html"
+	subject := fmt.Sprintf("Medicine Expiry Summary - %d item(s) need attention", data.TotalItems)
+
+	return m.sendEmail(recipientEmail, subject, templateName, data)
+}
+
+// SendMarkdownSummaryEmail tells a seller which discounts the markdown job
+// applied or restored
+func (m *Mailer) SendMarkdownSummaryEmail(recipientEmail string, data MarkdownSummaryData) error {
+	templateName := "markdown_summary.html"
+	subject := fmt.Sprintf("Automatic Markdowns - %d price change(s)", len(data.Changes))
+
+	return m.sendEmail(recipientEmail, subject, templateName, data)
+}
+
+// SendLowStockEmail tells a seller which medicines are running low and how
+// much of each to reorder
+func (m *Mailer) SendLowStockEmail(recipientEmail string, data LowStockData) error {
+	templateName := "low_stock.html"
+	subject := fmt.Sprintf("Low Stock Alert - %d item(s) to reorder", len(data.Items))
+
+	return m.sendEmail(recipientEmail, subject, templateName, data)
+}
+
+// SendStaffInviteEmail sends an invite to join a store's staff
+func (m *Mailer) SendStaffInviteEmail(recipientEmail string, data StaffInviteData) error {
+	templateName := "staff_invite.html"
+	subject := fmt.Sprintf("You're invited to join %s on MediBridge", data.StoreName)
+
+	return m.sendEmail(recipientEmail, subject, templateName, data)
+}
+
+// SendPayoutStatementEmail sends a seller the settlement statement of a
+// payout
+func (m *Mailer) SendPayoutStatementEmail(recipientEmail string, data PayoutStatementData) error {
+	templateName := "payout_statement.html"
+	subject := fmt.Sprintf("Settlement Statement - Payout of Rs. %s", data.Amount)
+
+	return m.sendEmail(recipientEmail, subject, templateName, data)
+}
+
+// SendAppointmentEmail tells a patient or doctor about an appointment that
+// was booked, rescheduled or cancelled, or reminds them of it
+func (m *Mailer) SendAppointmentEmail(recipientEmail string, data AppointmentData) error {
+	templateName := "appointment.html"
+	subject := fmt.Sprintf("Appointment %s - Dr. %s on %s", data.Title, data.DoctorName, data.Time)
+
+	return m.sendEmail(recipientEmail, subject, templateName, data)
+}
+
+// sendEmail handles the actual email sending process
+func (m *Mailer) sendEmail(to, subject, templateName string, data interface{}) error {
+	// Get the template
+	tmpl, ok := m.templates[templateName]
+	if !ok {
+		return fmt.Errorf("template %s not found", templateName)
+	}
+
+	// Render the template
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return fmt.Errorf("error executing template: %w", err)
+	}
+
+	// Prepare email
+	from := fmt.Sprintf("%s <%s>", m.senderName, m.senderEmail)
+	headers := make(map[string]string)
+	headers["From"] = from
+	headers["To"] = to
+	headers["Subject"] = subject
+	headers["MIME-Version"] = "1.0"
+	headers["Content-Type"] = "text/html; charset=UTF-8"
+
+	// Construct message
+	message := ""
+	for k, v := range headers {
+		message += fmt.Sprintf("%s: %s\r\n", k, v)
+	}
+	message += "\r\n" + body.String()
+
+	// Send email
+	addr := fmt.Sprintf("%s:%s", m.host, m.port)
+	if err := smtp.SendMail(addr, m.auth, m.senderEmail, []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	return nil
+}
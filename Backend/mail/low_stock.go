@@ -0,0 +1,143 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// LowStockChecker alerts sellers about medicines that dropped to their
+// reorder level
+type LowStockChecker struct {
+	store  db.Store
+	mailer *Mailer
+	config util.Config
+}
+
+// NewLowStockChecker creates a new LowStockChecker
+func NewLowStockChecker(store db.Store, mailer *Mailer, config util.Config) *LowStockChecker {
+	return &LowStockChecker{
+		store:  store,
+		mailer: mailer,
+		config: config,
+	}
+}
+
+// StartLowStockScheduler runs the low-stock check on the same period as the
+// expiry check
+func (l *LowStockChecker) StartLowStockScheduler(ctx context.Context) {
+	// Run immediately on startup
+	l.CheckLowStock(ctx)
+
+	// Set up periodic check
+	ticker := time.NewTicker(l.config.ExpiryCheckPeriod)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				l.CheckLowStock(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// CheckLowStock sends each seller one email listing the medicines that
+// reached their reorder level since the last check, with a suggested order
+// quantity for each
+func (l *LowStockChecker) CheckLowStock(ctx context.Context) {
+	log.Println("Starting low stock check...")
+
+	// Medicines restocked since the last run can be alerted on again
+	if _, err := l.store.DeleteResolvedLowStockAlerts(ctx); err != nil {
+		log.Printf("Error clearing resolved low stock alerts: %v", err)
+		return
+	}
+
+	medicines, err := l.store.ListLowStockMedicines(ctx, db.ListLowStockMedicinesParams{
+		SalesSince: LowStockSalesSince(l.config),
+	})
+	if err != nil {
+		log.Printf("Error getting low stock medicines: %v", err)
+		return
+	}
+
+	alerts := make(map[string][]db.ListLowStockMedicinesRow)
+	for _, medicine := range medicines {
+		if medicine.Alerted {
+			continue
+		}
+		alerts[medicine.SellerUsername] = append(alerts[medicine.SellerUsername], medicine)
+	}
+
+	var alertedCount int
+	for sellerUsername, sellerMedicines := range alerts {
+		seller, err := l.store.GetSellerByName(ctx, sellerUsername)
+		if err != nil {
+			log.Printf("Error getting seller %s: %v", sellerUsername, err)
+			continue
+		}
+
+		data := LowStockData{
+			SellerName:      seller.FullName,
+			SalesWindowDays: l.config.LowStockSalesWindowDays,
+			CoverDays:       l.config.ReorderCoverDays,
+		}
+		for _, medicine := range sellerMedicines {
+			data.Items = append(data.Items, LowStockItem{
+				MedicineID:        medicine.ID,
+				MedicineName:      medicine.Name,
+				BatchNumber:       medicine.BatchNumber,
+				Quantity:          medicine.Quantity,
+				ReorderLevel:      medicine.ReorderLevel,
+				UnitsSold:         medicine.UnitsSold,
+				SuggestedQuantity: SuggestReorderQuantity(l.config, medicine),
+			})
+		}
+
+		if err := l.mailer.SendLowStockEmail(seller.Email, data); err != nil {
+			log.Printf("Error sending low stock alert to %s: %v", seller.Email, err)
+			continue
+		}
+
+		for _, medicine := range sellerMedicines {
+			err := l.store.CreateLowStockAlert(ctx, db.CreateLowStockAlertParams{
+				MedicineID:     medicine.ID,
+				SellerUsername: sellerUsername,
+				Quantity:       medicine.Quantity,
+			})
+			if err != nil {
+				log.Printf("Error recording low stock alert for medicine %d: %v", medicine.ID, err)
+			}
+		}
+		alertedCount += len(sellerMedicines)
+	}
+
+	log.Printf("Low stock check completed. Alerted sellers about %d medicines.", alertedCount)
+}
+
+// LowStockSalesSince is the start of the window sales velocity is measured
+// over
+func LowStockSalesSince(config util.Config) pgtype.Timestamp {
+	return pgtype.Timestamp{
+		Time:  time.Now().AddDate(0, 0, -config.LowStockSalesWindowDays),
+		Valid: true,
+	}
+}
+
+// SuggestReorderQuantity suggests how much of a low-stock medicine to order
+func SuggestReorderQuantity(config util.Config, medicine db.ListLowStockMedicinesRow) int {
+	return util.SuggestReorderQuantity(
+		int(medicine.UnitsSold),
+		config.LowStockSalesWindowDays,
+		config.ReorderCoverDays,
+		int(medicine.Quantity),
+		int(medicine.ReorderLevel),
+	)
+}
@@ -0,0 +1,134 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// MarkdownApplier applies sellers' near-expiry markdown rules to their
+// medicines' discounts
+type MarkdownApplier struct {
+	store  db.Store
+	mailer *Mailer
+	config util.Config
+}
+
+// NewMarkdownApplier creates a new MarkdownApplier
+func NewMarkdownApplier(store db.Store, mailer *Mailer, config util.Config) *MarkdownApplier {
+	return &MarkdownApplier{
+		store:  store,
+		mailer: mailer,
+		config: config,
+	}
+}
+
+// StartMarkdownScheduler runs the markdown job on the same period as the
+// expiry check
+func (a *MarkdownApplier) StartMarkdownScheduler(ctx context.Context) {
+	// Run immediately on startup
+	a.ApplyMarkdowns(ctx)
+
+	// Set up periodic check
+	ticker := time.NewTicker(a.config.ExpiryCheckPeriod)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				a.ApplyMarkdowns(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// ApplyMarkdowns moves every medicine onto the discount of the nearest
+// markdown rule that covers it, restores the original discount of medicines
+// no rule covers any more, and tells each seller what changed
+func (a *MarkdownApplier) ApplyMarkdowns(ctx context.Context) {
+	log.Println("Starting markdown pricing run...")
+
+	candidates, err := a.store.ListMarkdownCandidates(ctx)
+	if err != nil {
+		log.Printf("Error getting markdown candidates: %v", err)
+		return
+	}
+
+	changes := make(map[string][]MarkdownChange)
+	for _, candidate := range candidates {
+		arg, ok := newMarkdownTxParams(candidate)
+		if !ok {
+			continue
+		}
+
+		result, err := a.store.MarkdownTx(ctx, arg)
+		if err != nil {
+			log.Printf("Error changing markdown of medicine %d: %v", candidate.ID, err)
+			continue
+		}
+
+		log.Printf("Markdown %s on medicine %s (ID: %d): discount %d%% -> %d%%",
+			result.Event.Action, candidate.Name, candidate.ID, result.Event.OldDiscount, result.Event.NewDiscount)
+
+		changes[candidate.SellerUsername] = append(changes[candidate.SellerUsername], MarkdownChange{
+			MedicineID:   candidate.ID,
+			MedicineName: candidate.Name,
+			BatchNumber:  candidate.BatchNumber,
+			ExpiryDate:   candidate.ExpiryDate.Time.Format("2006-01-02"),
+			OldDiscount:  result.Event.OldDiscount,
+			NewDiscount:  result.Event.NewDiscount,
+			Restored:     !arg.RuleID.Valid,
+		})
+	}
+
+	for sellerUsername, sellerChanges := range changes {
+		seller, err := a.store.GetSellerByName(ctx, sellerUsername)
+		if err != nil {
+			log.Printf("Error getting seller %s: %v", sellerUsername, err)
+			continue
+		}
+
+		err = a.mailer.SendMarkdownSummaryEmail(seller.Email, MarkdownSummaryData{
+			SellerName: seller.FullName,
+			Changes:    sellerChanges,
+		})
+		if err != nil {
+			log.Printf("Error sending markdown summary to %s: %v", seller.Email, err)
+		}
+	}
+
+	log.Printf("Markdown pricing run completed. Changed %d sellers' prices.", len(changes))
+}
+
+// newMarkdownTxParams decides what, if anything, to change on a candidate.
+// A rule only ever raises the discount above the seller's own one.
+func newMarkdownTxParams(candidate db.ListMarkdownCandidatesRow) (db.MarkdownTxParams, bool) {
+	arg := db.MarkdownTxParams{MedicineID: candidate.ID}
+
+	baseDiscount := candidate.Discount
+	if candidate.OriginalDiscount.Valid {
+		baseDiscount = candidate.OriginalDiscount.Int32
+	}
+
+	if candidate.RuleID != 0 && candidate.RuleDiscount > baseDiscount {
+		alreadyApplied := candidate.MarkdownRuleID.Valid &&
+			candidate.MarkdownRuleID.Int32 == candidate.RuleID &&
+			candidate.Discount == candidate.RuleDiscount
+		if alreadyApplied {
+			return arg, false
+		}
+
+		arg.RuleID = pgtype.Int4{Int32: candidate.RuleID, Valid: true}
+		arg.Discount = candidate.RuleDiscount
+		return arg, true
+	}
+
+	// No rule beats the seller's own discount any more
+	return arg, candidate.OriginalDiscount.Valid
+}
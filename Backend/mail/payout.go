@@ -0,0 +1,185 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// payoutCheckPeriod is how often the runner checks whether a payout batch
+// is due
+const payoutCheckPeriod = time.Hour
+
+// payoutCreatedBy is recorded on the ledger transactions the runner posts
+const payoutCreatedBy = "system"
+
+// PayoutRunner posts reported chargebacks and pays sellers their balance
+// once every payout period
+type PayoutRunner struct {
+	store  db.Store
+	mailer *Mailer
+	config util.Config
+}
+
+// NewPayoutRunner creates a new PayoutRunner
+func NewPayoutRunner(store db.Store, mailer *Mailer, config util.Config) *PayoutRunner {
+	return &PayoutRunner{
+		store:  store,
+		mailer: mailer,
+		config: config,
+	}
+}
+
+// StartPayoutScheduler checks every hour whether the next payout batch is
+// due, so a restart doesn't push the schedule back
+func (p *PayoutRunner) StartPayoutScheduler(ctx context.Context) {
+	// Run immediately on startup
+	p.RunDuePayouts(ctx)
+
+	// Set up periodic check
+	ticker := time.NewTicker(payoutCheckPeriod)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				p.RunDuePayouts(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// RunDuePayouts runs a payout batch if the payout period has passed since
+// the last one
+func (p *PayoutRunner) RunDuePayouts(ctx context.Context) {
+	last, err := p.store.GetLastPayoutBatch(ctx)
+	if err == nil && time.Since(last.Cutoff.Time) < p.config.PayoutPeriod {
+		return
+	}
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		log.Printf("Error getting last payout batch: %v", err)
+		return
+	}
+
+	p.RunPayouts(ctx)
+}
+
+// RunPayouts posts the chargebacks reported since the last run, then pays
+// every seller with a positive balance and emails them their settlement
+// statement
+func (p *PayoutRunner) RunPayouts(ctx context.Context) {
+	log.Println("Starting payout run...")
+
+	p.postChargebacks(ctx)
+
+	cutoff := pgtype.Timestamp{Time: time.Now(), Valid: true}
+	batch, err := p.store.CreatePayoutBatch(ctx, cutoff)
+	if err != nil {
+		log.Printf("Error creating payout batch: %v", err)
+		return
+	}
+
+	sellers, err := p.store.ListSellersWithPayableBalance(ctx, cutoff)
+	if err != nil {
+		log.Printf("Error getting sellers with a payable balance: %v", err)
+		return
+	}
+
+	for _, balance := range sellers {
+		payout, err := p.store.CreatePayoutTx(ctx, db.CreatePayoutTxParams{
+			BatchID:        batch.ID,
+			SellerUsername: balance.SellerUsername,
+			Cutoff:         cutoff,
+			CreatedBy:      payoutCreatedBy,
+		})
+		if errors.Is(err, db.ErrNothingToPay) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Error paying out seller %s: %v", balance.SellerUsername, err)
+			continue
+		}
+
+		p.sendStatement(ctx, payout)
+	}
+
+	batch, err = p.store.UpdatePayoutBatchTotals(ctx, batch.ID)
+	if err != nil {
+		log.Printf("Error updating payout batch %d: %v", batch.ID, err)
+		return
+	}
+
+	log.Printf("Payout run completed. Paid %d sellers a total of %s.", batch.Payouts, formatMoney(batch.TotalAmount))
+}
+
+// postChargebacks takes each reported chargeback back from its seller. A
+// chargeback that can't be posted, such as one larger than what is left to
+// refund on the sub-order, stays pending for an operator to look at.
+func (p *PayoutRunner) postChargebacks(ctx context.Context) {
+	chargebacks, err := p.store.ListPendingChargebacks(ctx)
+	if err != nil {
+		log.Printf("Error getting pending chargebacks: %v", err)
+		return
+	}
+
+	for _, chargeback := range chargebacks {
+		_, err := p.store.RefundSubOrderTx(ctx, db.RefundSubOrderTxParams{
+			SubOrderID:   chargeback.SubOrderID,
+			Kind:         util.ChargebackLedgerKind,
+			Amount:       chargeback.Amount,
+			Description:  chargeback.Reason,
+			CreatedBy:    payoutCreatedBy,
+			ChargebackID: pgtype.Int4{Int32: chargeback.ID, Valid: true},
+		})
+		if err != nil {
+			log.Printf("Error posting chargeback %d on sub-order %d: %v", chargeback.ID, chargeback.SubOrderID, err)
+		}
+	}
+}
+
+func (p *PayoutRunner) sendStatement(ctx context.Context, payout db.Payout) {
+	seller, err := p.store.GetSellerByName(ctx, payout.SellerUsername)
+	if err != nil {
+		log.Printf("Error getting seller %s: %v", payout.SellerUsername, err)
+		return
+	}
+
+	periodStart := "your first sale"
+	if payout.PeriodStart.Valid {
+		periodStart = payout.PeriodStart.Time.Format("2006-01-02 15:04")
+	}
+
+	data := PayoutStatementData{
+		SellerName:     seller.FullName,
+		StoreName:      seller.StoreName,
+		PayoutID:       payout.ID,
+		PeriodStart:    periodStart,
+		PeriodEnd:      payout.PeriodEnd.Time.Format("2006-01-02 15:04"),
+		OpeningBalance: formatMoney(payout.OpeningBalance),
+		GrossSales:     formatMoney(payout.GrossSales),
+		Commission:     formatMoney(payout.Commission),
+		Refunds:        formatMoney(payout.Refunds),
+		Chargebacks:    formatMoney(payout.Chargebacks),
+		Amount:         formatMoney(payout.Amount),
+	}
+
+	if err := p.mailer.SendPayoutStatementEmail(seller.Email, data); err != nil {
+		log.Printf("Error sending settlement statement to %s: %v", seller.Email, err)
+	}
+}
+
+func formatMoney(n pgtype.Numeric) string {
+	value, err := n.Float64Value()
+	if err != nil || !value.Valid {
+		return "0.00"
+	}
+	return fmt.Sprintf("%.2f", value.Float64)
+}
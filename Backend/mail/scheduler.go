@@ -1,169 +1,304 @@
-package mail
-
-import (
-	"context"
-	"database/sql"
-	"fmt"
-	"log"
-	"math"
-	"time"
-
-	db "github.com/pawaspy/MediBridge/db/sqlc"
-	"github.com/pawaspy/MediBridge/util"
-)
-
-// ExpiryChecker manages the process of checking medicine expiry dates
-type ExpiryChecker struct {
-	store  db.Store
-	mailer *Mailer
-	config util.Config
-}
-
-// NewExpiryChecker creates a new ExpiryChecker
-func NewExpiryChecker(store db.Store, mailer *Mailer, config util.Config) *ExpiryChecker {
-	return &ExpiryChecker{
-		store:  store,
-		mailer: mailer,
-		config: config,
-	}
-}
-
-// StartExpiryCheckScheduler schedules the daily expiry check job
-func (e *ExpiryChecker) StartExpiryCheckScheduler(ctx context.Context) {
-	// Run immediately on startup
-	e.CheckExpiry(ctx)
-
-	// Set up periodic check
-	ticker := time.NewTicker(e.config.ExpiryCheckPeriod)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				e.CheckExpiry(ctx)
-			case <-ctx.Done():
-				ticker.Stop()
-				return
-			}
-		}
-	}()
-}
-
-// CheckExpiry checks for expiring and expired medicines
-func (e *ExpiryChecker) CheckExpiry(ctx context.Context) {
-	log.Println("Starting medicine expiry check...")
-
-	// Get all medicines
-	medicines, err := e.getAllMedicines(ctx)
-	if err != nil {
-		log.Printf("Error getting medicines: %v", err)
-		return
-	}
-
-	now := time.Now()
-	var expiringCount, expiredCount int
-
-	// Process each medicine
-	for _, medicine := range medicines {
-		if !medicine.ExpiryDate.Valid {
-			continue
-		}
-
-		expiryDate := medicine.ExpiryDate.Time
-		daysUntilExpiry := int(math.Ceil(expiryDate.Sub(now).Hours() / 24))
-
-		// Get seller information
-		seller, err := e.store.GetSellerByName(ctx, medicine.SellerUsername)
-		if err != nil {
-			log.Printf("Error getting seller %s: %v", medicine.SellerUsername, err)
-			continue
-		}
-
-		// Handle expired medicines
-		if expiryDate.Before(now) {
-			daysSinceExpiry := int(math.Ceil(now.Sub(expiryDate).Hours() / 24))
-			err = e.handleExpiredMedicine(ctx, medicine, seller, daysSinceExpiry)
-			if err != nil {
-				log.Printf("Error handling expired medicine %d: %v", medicine.ID, err)
-			} else {
-				expiredCount++
-			}
-			continue
-		}
-
-		// Handle medicines expiring within 180 days
-		if daysUntilExpiry <= 180 && daysUntilExpiry >= 0 {
-			err = e.handleExpiringMedicine(medicine, seller, daysUntilExpiry)
-			if err != nil {
-				log.Printf("Error handling expiring medicine %d: %v", medicine.ID, err)
-			} else {
-				expiringCount++
-			}
-		}
-	}
-
-	log.Printf("Expiry check completed. Processed %d expiring and %d expired medicines.", expiringCount, expiredCount)
-}
-
-// getAllMedicines retrieves all medicines from the database
-func (e *ExpiryChecker) getAllMedicines(ctx context.Context) ([]db.Medicine, error) {
-	return e.store.ListAllMedicines(ctx)
-}
-
-// handleExpiringMedicine handles medicines that will expire soon
-func (e *ExpiryChecker) handleExpiringMedicine(medicine db.Medicine, seller db.Seller, daysUntilExpiry int) error {
-	// Convert numeric price to string
-	price := "0.00"
-	if medicine.Price.Valid {
-		var priceFloat float64
-		err := medicine.Price.Scan(&priceFloat)
-		if err == nil {
-			price = fmt.Sprintf("%.2f", priceFloat)
-		}
-	}
-
-	// Send email notification
-	err := e.mailer.SendExpiringMedicineEmail(
-		seller.Email,
-		seller.FullName,
-		medicine.ID,
-		medicine.Name,
-		price,
-		medicine.Quantity,
-		medicine.ExpiryDate.Time,
-		daysUntilExpiry,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to send expiring medicine email: %w", err)
-	}
-
-	log.Printf("Sent expiring medicine notification for %s (ID: %d) to %s",
-		medicine.Name, medicine.ID, seller.Email)
-	return nil
-}
-
-// handleExpiredMedicine handles medicines that have already expired
-func (e *ExpiryChecker) handleExpiredMedicine(ctx context.Context, medicine db.Medicine, seller db.Seller, daysSinceExpiry int) error {
-	// Send email notification
-	err := e.mailer.SendExpiredMedicineEmail(
-		seller.Email,
-		seller.FullName,
-		medicine.ID,
-		medicine.Name,
-		medicine.Quantity,
-		medicine.ExpiryDate.Time,
-		daysSinceExpiry,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to send expired medicine email: %w", err)
-	}
-
-	// Delete the expired medicine
-	_, err = e.store.DeleteMedicine(ctx, medicine.ID)
-	if err != nil && err != sql.ErrNoRows {
-		return fmt.Errorf("failed to delete expired medicine: %w", err)
-	}
-
-	log.Printf("Deleted expired medicine %s (ID: %d) and notified seller %s",
-		medicine.Name, medicine.ID, seller.Email)
-	return nil
-}
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	db "github.com/pawaspy/MediBridge/db/sqlc"
+	"github.com/pawaspy/MediBridge/util"
+)
+
+// ExpiryChecker manages the process of checking medicine expiry dates
+type ExpiryChecker struct {
+	store  db.Store
+	mailer *Mailer
+	config util.Config
+}
+
+// NewExpiryChecker creates a new ExpiryChecker
+func NewExpiryChecker(store db.Store, mailer *Mailer, config util.Config) *ExpiryChecker {
+	return &ExpiryChecker{
+		store:  store,
+		mailer: mailer,
+		config: config,
+	}
+}
+
+// StartExpiryCheckScheduler schedules the daily expiry check job
+func (e *ExpiryChecker) StartExpiryCheckScheduler(ctx context.Context) {
+	// Run immediately on startup
+	e.CheckExpiry(ctx)
+
+	// Set up periodic check
+	ticker := time.NewTicker(e.config.ExpiryCheckPeriod)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				e.CheckExpiry(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// sellerDigest collects the medicines to report to one seller in a run,
+// along with the threshold each one is being reported under
+type sellerDigest struct {
+	items map[int][]db.Medicine
+	days  map[int32]int
+}
+
+// CheckExpiry quarantines expired medicines and sends each seller one digest
+// of the medicines that crossed an expiry threshold since they were last told
+func (e *ExpiryChecker) CheckExpiry(ctx context.Context) {
+	log.Println("Starting medicine expiry check...")
+
+	// Get all medicines
+	medicines, err := e.getAllMedicines(ctx)
+	if err != nil {
+		log.Printf("Error getting medicines: %v", err)
+		return
+	}
+
+	now := time.Now()
+	digests := make(map[string]*sellerDigest)
+	var expiredCount int
+
+	addToDigest := func(medicine db.Medicine, threshold, days int) {
+		digest, ok := digests[medicine.SellerUsername]
+		if !ok {
+			digest = &sellerDigest{
+				items: make(map[int][]db.Medicine),
+				days:  make(map[int32]int),
+			}
+			digests[medicine.SellerUsername] = digest
+		}
+		digest.items[threshold] = append(digest.items[threshold], medicine)
+		digest.days[medicine.ID] = days
+	}
+
+	// Process each medicine
+	for _, medicine := range medicines {
+		if !medicine.ExpiryDate.Valid {
+			continue
+		}
+
+		expiryDate := medicine.ExpiryDate.Time
+
+		// Expired medicines are taken off sale straight away; the seller is
+		// told about them below
+		if expiryDate.Before(now) {
+			err = e.handleExpiredMedicine(ctx, medicine)
+			if err != nil {
+				log.Printf("Error handling expired medicine %d: %v", medicine.ID, err)
+				continue
+			}
+			expiredCount++
+			continue
+		}
+
+		days := int(math.Ceil(expiryDate.Sub(now).Hours() / 24))
+		threshold, ok := util.ExpiryThreshold(days, e.config.ExpiryNotifyThresholds)
+		if !ok {
+			continue
+		}
+		addToDigest(medicine, threshold, days)
+	}
+
+	// Report every expired medicine the seller hasn't been told about, so
+	// one whose digest failed to send is retried on the next run
+	expired, err := e.store.ListUnnotifiedExpiredMedicines(ctx)
+	if err != nil {
+		log.Printf("Error getting unreported expired medicines: %v", err)
+	}
+	for _, medicine := range expired {
+		days := int(math.Ceil(now.Sub(medicine.ExpiryDate.Time).Hours() / 24))
+		addToDigest(medicine, 0, days)
+	}
+
+	var sentCount int
+	for sellerUsername, digest := range digests {
+		sent, err := e.sendSellerDigest(ctx, sellerUsername, digest)
+		if err != nil {
+			log.Printf("Error sending expiry digest to seller %s: %v", sellerUsername, err)
+			continue
+		}
+		sentCount += sent
+	}
+
+	log.Printf("Expiry check completed. Quarantined %d expired medicines and notified sellers about %d medicines.", expiredCount, sentCount)
+}
+
+// getAllMedicines retrieves all medicines that are still on sale; quarantined
+// and disposed stock has already been dealt with
+func (e *ExpiryChecker) getAllMedicines(ctx context.Context) ([]db.Medicine, error) {
+	return e.store.ListActiveMedicines(ctx)
+}
+
+// sendSellerDigest emails a seller the medicines they haven't been told about
+// at their current threshold yet, then records them as sent. It returns the
+// number of medicines in the email.
+func (e *ExpiryChecker) sendSellerDigest(ctx context.Context, sellerUsername string, digest *sellerDigest) (int, error) {
+	sent, err := e.store.ListSentExpiryNotifications(ctx, sellerUsername)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sent notifications: %w", err)
+	}
+
+	alreadySent := make(map[db.ListSentExpiryNotificationsRow]bool, len(sent))
+	for _, row := range sent {
+		alreadySent[row] = true
+	}
+
+	// Expired first, then the nearest threshold
+	thresholds := append([]int{0}, e.config.ExpiryNotifyThresholds...)
+
+	data := ExpiryDigestData{}
+	var notified []db.CreateExpiryNotificationParams
+	for _, threshold := range thresholds {
+		group := ExpiryDigestGroup{
+			Title:   fmt.Sprintf("Expiring within %d days", threshold),
+			Expired: threshold == 0,
+		}
+		if group.Expired {
+			group.Title = "Expired and quarantined"
+		}
+
+		for _, medicine := range digest.items[threshold] {
+			key := db.ListSentExpiryNotificationsRow{
+				MedicineID:    medicine.ID,
+				ThresholdDays: int32(threshold),
+			}
+			if alreadySent[key] {
+				continue
+			}
+			// Don't list a medicine twice if thresholds are duplicated
+			alreadySent[key] = true
+
+			group.Items = append(group.Items, newExpiryDigestItem(medicine, digest.days[medicine.ID]))
+			notified = append(notified, db.CreateExpiryNotificationParams{
+				MedicineID:     medicine.ID,
+				SellerUsername: sellerUsername,
+				ThresholdDays:  int32(threshold),
+			})
+		}
+
+		if len(group.Items) > 0 {
+			data.Groups = append(data.Groups, group)
+			data.TotalItems += len(group.Items)
+		}
+	}
+
+	if data.TotalItems == 0 {
+		return 0, nil
+	}
+
+	// Get seller information
+	seller, err := e.store.GetSellerByName(ctx, sellerUsername)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get seller: %w", err)
+	}
+	data.SellerName = seller.FullName
+
+	if err := e.suggestDonations(ctx, seller, &data); err != nil {
+		log.Printf("Error checking donation offers of seller %s: %v", sellerUsername, err)
+	}
+
+	err = e.mailer.SendExpiryDigestEmail(seller.Email, data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send expiry digest email: %w", err)
+	}
+
+	for _, arg := range notified {
+		if err := e.store.CreateExpiryNotification(ctx, arg); err != nil {
+			log.Printf("Error recording expiry notification for medicine %d: %v", arg.MedicineID, err)
+		}
+	}
+
+	log.Printf("Sent expiry digest with %d medicines to %s", data.TotalItems, seller.Email)
+	return data.TotalItems, nil
+}
+
+// suggestDonations marks the near-expiry medicines that a retail or
+// wholesale seller could offer to NGO and hospital sellers and hasn't yet
+func (e *ExpiryChecker) suggestDonations(ctx context.Context, seller db.Seller, data *ExpiryDigestData) error {
+	if !util.CanOfferDonations(seller.SellerType) {
+		return nil
+	}
+
+	offeredIDs, err := e.store.ListOfferedMedicineIDs(ctx, seller.Username)
+	if err != nil {
+		return err
+	}
+
+	offered := make(map[int32]bool, len(offeredIDs))
+	for _, id := range offeredIDs {
+		offered[id] = true
+	}
+
+	for i := range data.Groups {
+		if data.Groups[i].Expired {
+			continue
+		}
+		for j := range data.Groups[i].Items {
+			item := &data.Groups[i].Items[j]
+			if offered[item.MedicineID] || !util.IsDonationCandidate(item.Days, e.config.DonationWindowDays) {
+				continue
+			}
+			item.DonationSuggested = true
+			data.DonationSuggestions++
+		}
+	}
+	data.DonationWindowDays = e.config.DonationWindowDays
+
+	return nil
+}
+
+func newExpiryDigestItem(medicine db.Medicine, days int) ExpiryDigestItem {
+	// Convert numeric price to string
+	price := "0.00"
+	if medicine.Price.Valid {
+		if value, err := medicine.Price.Float64Value(); err == nil {
+			price = fmt.Sprintf("%.2f", value.Float64)
+		}
+	}
+
+	// Quarantined stock is no longer counted in the quantity on sale
+	quantity := medicine.Quantity
+	if medicine.Status == util.QuarantinedMedicine {
+		quantity = medicine.QuarantinedQuantity
+	}
+
+	return ExpiryDigestItem{
+		MedicineID:   medicine.ID,
+		MedicineName: medicine.Name,
+		BatchNumber:  medicine.BatchNumber,
+		Price:        price,
+		Quantity:     quantity,
+		ExpiryDate:   medicine.ExpiryDate.Time.Format("2006-01-02"),
+		Days:         days,
+	}
+}
+
+// handleExpiredMedicine quarantines a medicine that has already expired. The
+// record is kept for sales history and carts, and the lost stock is written
+// off in the ledger.
+func (e *ExpiryChecker) handleExpiredMedicine(ctx context.Context, medicine db.Medicine) error {
+	_, err := e.store.QuarantineMedicineTx(ctx, db.QuarantineMedicineTxParams{
+		MedicineID:   medicine.ID,
+		MovementType: util.ExpiryWriteOffMovement,
+		Reason:       "expired",
+		CreatedBy:    util.SystemActor,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to quarantine expired medicine: %w", err)
+	}
+
+	log.Printf("Quarantined expired medicine %s (ID: %d)", medicine.Name, medicine.ID)
+	return nil
+}
@@ -0,0 +1,105 @@
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNotConfigured is returned by every call when no payment provider
+	// has been set up
+	ErrNotConfigured = errors.New("payments are not configured")
+	// ErrInvalidSignature is returned by ParseWebhook for a request that
+	// wasn't signed by the provider
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Statuses of a payment intent that the server acts on
+const (
+	IntentSucceeded = "succeeded"
+	IntentCanceled  = "canceled"
+)
+
+// Webhook events the server handles
+const (
+	EventIntentSucceeded = "payment_intent.succeeded"
+)
+
+// Intent is one attempt to collect a payment from a patient. Amounts are in
+// the currency's minor unit, such as paise.
+type Intent struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// ClientSecret lets the front end complete the payment with the
+	// provider's own widget
+	ClientSecret  string            `json:"client_secret"`
+	ChargeID      string            `json:"latest_charge"`
+	PaymentMethod string            `json:"payment_method"`
+	Metadata      map[string]string `json:"metadata"`
+}
+
+// Refund is money paid back on an intent
+type Refund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount int64  `json:"amount"`
+}
+
+// Event is a verified notification from the provider
+type Event struct {
+	ID     string
+	Type   string
+	Intent Intent
+}
+
+type CreateIntentParams struct {
+	Amount   int64
+	Currency string
+	// Metadata ties the intent back to what it pays for, such as
+	// {"order_id": "42"}
+	Metadata map[string]string
+	// IdempotencyKey makes retries of the same request create one intent
+	IdempotencyKey string
+}
+
+type RefundParams struct {
+	IntentID string
+	// Amount to refund; 0 refunds whatever is left on the intent
+	Amount         int64
+	Reason         string
+	IdempotencyKey string
+}
+
+// Provider is the payment gateway patients pay through. The server never
+// takes the client's word that a payment went through: it either looks the
+// intent up or acts on a webhook the provider signed.
+type Provider interface {
+	CreateIntent(ctx context.Context, arg CreateIntentParams) (Intent, error)
+	GetIntent(ctx context.Context, id string) (Intent, error)
+	Refund(ctx context.Context, arg RefundParams) (Refund, error)
+	// ParseWebhook checks the signature of a webhook request and decodes
+	// the event it carries
+	ParseWebhook(payload []byte, signature string) (Event, error)
+}
+
+// Unconfigured is used when no provider keys are set. Every call fails
+// with ErrNotConfigured, so nothing can be marked as paid.
+type Unconfigured struct{}
+
+func (Unconfigured) CreateIntent(ctx context.Context, arg CreateIntentParams) (Intent, error) {
+	return Intent{}, ErrNotConfigured
+}
+
+func (Unconfigured) GetIntent(ctx context.Context, id string) (Intent, error) {
+	return Intent{}, ErrNotConfigured
+}
+
+func (Unconfigured) Refund(ctx context.Context, arg RefundParams) (Refund, error) {
+	return Refund{}, ErrNotConfigured
+}
+
+func (Unconfigured) ParseWebhook(payload []byte, signature string) (Event, error) {
+	return Event{}, ErrNotConfigured
+}
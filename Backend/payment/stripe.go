@@ -0,0 +1,184 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stripeAPIURL = "https://api.stripe.com"
+	// Webhooks signed longer ago than this are refused, so a captured
+	// request can't be replayed later
+	stripeWebhookTolerance = 5 * time.Minute
+)
+
+// StripeProvider talks to the Stripe API over plain HTTP
+type StripeProvider struct {
+	secretKey     string
+	webhookSecret string
+	baseURL       string
+	client        *http.Client
+	now           func() time.Time
+}
+
+// NewStripeProvider creates a provider for the account of secretKey, which
+// checks webhooks against the endpoint's signing secret
+func NewStripeProvider(secretKey, webhookSecret string) *StripeProvider {
+	return &StripeProvider{
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		baseURL:       stripeAPIURL,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		now:           time.Now,
+	}
+}
+
+func (s *StripeProvider) CreateIntent(ctx context.Context, arg CreateIntentParams) (Intent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(arg.Amount, 10))
+	form.Set("currency", strings.ToLower(arg.Currency))
+	form.Set("automatic_payment_methods[enabled]", "true")
+	for key, value := range arg.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	var intent Intent
+	err := s.do(ctx, http.MethodPost, "/v1/payment_intents", form, arg.IdempotencyKey, &intent)
+	return intent, err
+}
+
+func (s *StripeProvider) GetIntent(ctx context.Context, id string) (Intent, error) {
+	var intent Intent
+	err := s.do(ctx, http.MethodGet, "/v1/payment_intents/"+url.PathEscape(id), nil, "", &intent)
+	return intent, err
+}
+
+func (s *StripeProvider) Refund(ctx context.Context, arg RefundParams) (Refund, error) {
+	form := url.Values{}
+	form.Set("payment_intent", arg.IntentID)
+	if arg.Amount > 0 {
+		form.Set("amount", strconv.FormatInt(arg.Amount, 10))
+	}
+	if arg.Reason != "" {
+		form.Set("metadata[reason]", arg.Reason)
+	}
+
+	var refund Refund
+	err := s.do(ctx, http.MethodPost, "/v1/refunds", form, arg.IdempotencyKey, &refund)
+	return refund, err
+}
+
+func (s *StripeProvider) ParseWebhook(payload []byte, signature string) (Event, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return Event{}, ErrInvalidSignature
+	}
+	if age := s.now().Sub(time.Unix(seconds, 0)); age > stripeWebhookTolerance || age < -stripeWebhookTolerance {
+		return Event{}, ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	valid := false
+	for _, sig := range signatures {
+		decoded, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(decoded, expected) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	result := Event{ID: event.ID, Type: event.Type}
+	if strings.HasPrefix(event.Type, "payment_intent.") {
+		if err := json.Unmarshal(event.Data.Object, &result.Intent); err != nil {
+			return Event{}, fmt.Errorf("invalid payment intent in webhook: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func (s *StripeProvider) do(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.secretKey, "")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("payment provider request failed: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	data, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+
+	if rsp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("payment provider: %s", apiErr.Error.Message)
+		}
+		return fmt.Errorf("payment provider returned %s", rsp.Status)
+	}
+
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,85 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func signWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestStripeParseWebhook(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	provider := NewStripeProvider("sk_test", "whsec_test")
+	provider.now = func() time.Time { return now }
+
+	payload := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","status":"succeeded","amount":25000,"currency":"inr","metadata":{"order_id":"42"}}}}`)
+
+	testCases := []struct {
+		name      string
+		signature string
+		ok        bool
+	}{
+		{"Valid", signWebhook("whsec_test", now.Unix(), payload), true},
+		{"WrongSecret", signWebhook("whsec_other", now.Unix(), payload), false},
+		{"TooOld", signWebhook("whsec_test", now.Add(-10*time.Minute).Unix(), payload), false},
+		{"Missing", "", false},
+		{"Malformed", "t=abc,v1=zz", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := provider.ParseWebhook(payload, tc.signature)
+			if !tc.ok {
+				require.ErrorIs(t, err, ErrInvalidSignature)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, EventIntentSucceeded, event.Type)
+			require.Equal(t, "pi_1", event.Intent.ID)
+			require.Equal(t, int64(25000), event.Intent.Amount)
+			require.Equal(t, "42", event.Intent.Metadata["order_id"])
+		})
+	}
+}
+
+func TestStripeGetIntent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, _ := r.BasicAuth()
+		switch {
+		case username != "sk_test":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"message":"Invalid API Key provided"}}`)
+		case r.URL.Path == "/v1/payment_intents/pi_1":
+			fmt.Fprint(w, `{"id":"pi_1","status":"succeeded","amount":25000,"currency":"inr","latest_charge":"ch_1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"No such payment_intent"}}`)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewStripeProvider("sk_test", "whsec_test")
+	provider.baseURL = server.URL
+
+	intent, err := provider.GetIntent(context.Background(), "pi_1")
+	require.NoError(t, err)
+	require.Equal(t, IntentSucceeded, intent.Status)
+	require.Equal(t, "ch_1", intent.ChargeID)
+
+	_, err = provider.GetIntent(context.Background(), "pi_2")
+	require.EqualError(t, err, "payment provider: No such payment_intent")
+}
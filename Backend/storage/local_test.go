@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	storage, err := NewLocalStorage(filepath.Join(root, "files"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	key := "documents/alice/report.pdf"
+
+	require.NoError(t, storage.Put(ctx, key, []byte("first")))
+	require.NoError(t, storage.Put(ctx, key, []byte("second")))
+
+	data, err := storage.Get(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("second"), data)
+
+	// Nothing is left behind but the file itself
+	entries, err := os.ReadDir(filepath.Join(root, "files", "documents", "alice"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.NoError(t, storage.Delete(ctx, key))
+	_, err = storage.Get(ctx, key)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, storage.Delete(ctx, key))
+}
+
+func TestLocalStorageInvalidKey(t *testing.T) {
+	storage, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, key := range []string{"", "/", "../secret", "documents/../../secret", "/documents/a", "documents//a", "documents/"} {
+		require.Error(t, storage.Put(ctx, key, []byte("data")), key)
+		_, err := storage.Get(ctx, key)
+		require.Error(t, err, key)
+	}
+}
+
+func TestNoopScanner(t *testing.T) {
+	var scanner Scanner = NoopScanner{}
+	require.NoError(t, scanner.Scan(context.Background(), "report.pdf", []byte("%PDF-1.4")))
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInfected is returned by a Scanner for a file that mustn't be stored
+var ErrInfected = errors.New("file failed the virus scan")
+
+// Scanner checks an uploaded file for malware before it is stored. It
+// returns ErrInfected, possibly wrapped, for files that must be refused,
+// and other errors when the file couldn't be scanned.
+type Scanner interface {
+	Scan(ctx context.Context, fileName string, data []byte) error
+}
+
+// NoopScanner lets every file through. It is used until a real scanner,
+// such as one backed by ClamAV, is plugged in.
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(ctx context.Context, fileName string, data []byte) error {
+	return nil
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Get for a key nothing is stored under
+var ErrNotFound = errors.New("file not found")
+
+// Storage keeps uploaded files, such as the documents in patients' medical
+// record vaults, under keys chosen by the caller. Keys are slash-separated
+// paths like "documents/alice/3f2a.pdf".
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the file stored under key. Deleting a key nothing is
+	// stored under isn't an error.
+	Delete(ctx context.Context, key string) error
+}
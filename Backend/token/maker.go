@@ -1,8 +1,9 @@
-package token
-
-import "time"
-
-type Maker interface {
-	CreateToken(username, role string, duration time.Duration) (string, *Payload, error)
-	VerifyToken(token string) (*Payload, error)
+package token
+
+import "time"
+
+type Maker interface {
+	CreateToken(username, role string, duration time.Duration) (string, *Payload, error)
+	CreateStoreToken(username, role, store, staffRole string, duration time.Duration) (string, *Payload, error)
+	VerifyToken(token string) (*Payload, error)
 }
\ No newline at end of file
@@ -1,52 +1,64 @@
-package token
-
-import (
-	"fmt"
-	"time"
-
-	"github.com/o1egl/paseto"
-)
-
-type PasetoMaker struct {
-	paseto *paseto.V2
-	symmetricKey []byte
-}
-
-func NewPasetoMaker(symmetricKey string) (Maker, error) {
-	if len(symmetricKey) < 32 {
-		return nil, fmt.Errorf("invalid key size: %v", symmetricKey)
-	}
-
-	maker := &PasetoMaker{
-		paseto: paseto.NewV2(),
-		symmetricKey: []byte(symmetricKey),
-	}
-
-	return maker, nil
-}
-
-func (maker *PasetoMaker) CreateToken(username, role string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, role, duration)
-	if err != nil {
-		return "", payload, err
-	}
-
-	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
-	return token, payload, nil
-}
-
-func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
-	payload := &Payload{}
-
-	err := maker.paseto.Decrypt(token, maker.symmetricKey, payload, nil)
-	if err != nil {
-		return nil, ErrInvalidToken
-	}
-
-	err = payload.Valid()
-	if err != nil {
-		return nil, err
-	}
-
-	return payload, nil
+package token
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/o1egl/paseto"
+)
+
+type PasetoMaker struct {
+	paseto *paseto.V2
+	symmetricKey []byte
+}
+
+func NewPasetoMaker(symmetricKey string) (Maker, error) {
+	if len(symmetricKey) < 32 {
+		return nil, fmt.Errorf("invalid key size: %v", symmetricKey)
+	}
+
+	maker := &PasetoMaker{
+		paseto: paseto.NewV2(),
+		symmetricKey: []byte(symmetricKey),
+	}
+
+	return maker, nil
+}
+
+func (maker *PasetoMaker) CreateToken(username, role string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	return token, payload, nil
+}
+
+func (maker *PasetoMaker) CreateStoreToken(username, role, store, staffRole string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
+	if err != nil {
+		return "", payload, err
+	}
+	payload.Store = store
+	payload.StaffRole = staffRole
+
+	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	return token, payload, err
+}
+
+func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+	payload := &Payload{}
+
+	err := maker.paseto.Decrypt(token, maker.symmetricKey, payload, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
 }
\ No newline at end of file
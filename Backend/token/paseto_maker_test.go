@@ -1,46 +1,65 @@
-package token
-
-import (
-	"testing"
-	"time"
-
-	"github.com/pawaspy/MediBridge/util"
-	"github.com/stretchr/testify/require"
-)
-
-func TestPasetoMaker(t *testing.T) {
-	maker, err := NewPasetoMaker(util.RandomString(32))
-	require.NoError(t, err)
-
-	username := util.RandomOwner()
-	duration := time.Minute
-	role := util.Patient
-
-	issuedAt := time.Now()
-	expiredAt := issuedAt.Add(duration)
-
-	token, payload, err := maker.CreateToken(username, role, duration)
-	require.NoError(t, err)
-	require.NotEmpty(t, token)
-
-	require.NotZero(t, payload.ID)
-	require.Equal(t, payload.Username, username)
-	require.Equal(t, payload.Role, role)
-	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
-	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
-}
-
-func TestExpiredPasetoToken(t *testing.T) {
-	maker, err := NewPasetoMaker(util.RandomString(32))
-	require.NoError(t, err)
-
-	token, payload, err := maker.CreateToken(util.RandomOwner(), util.Patient, -time.Minute)
-	require.NoError(t, err)
-	require.NotEmpty(t, token)
-	require.NotEmpty(t, payload)
-
-	payload, err = maker.VerifyToken(token)
-	require.Error(t, err)
-	require.EqualError(t, err, ErrExpiredToken.Error())
-	require.Nil(t, payload)
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pawaspy/MediBridge/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasetoMaker(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	duration := time.Minute
+	role := util.Patient
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(username, role, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, payload.Username, username)
+	require.Equal(t, payload.Role, role)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredPasetoToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.Patient, -time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoStoreToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	store := util.RandomOwner()
+
+	token, payload, err := maker.CreateStoreToken(username, util.Seller, store, util.PharmacistStaff, time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, util.Seller, payload.Role)
+	require.Equal(t, store, payload.Store)
+	require.Equal(t, util.PharmacistStaff, payload.StaffRole)
 }
\ No newline at end of file
@@ -1,45 +1,49 @@
-package token
-
-import (
-	"errors"
-	"time"
-
-	"github.com/google/uuid"
-)
-
-var (
-	ErrExpiredToken = errors.New("token has expired")
-	ErrInvalidToken = errors.New("token is invalid")
-)
-
-type Payload struct {
-	ID        uuid.UUID `json:"id"`
-	Role      string    `json:"role"`
-	Username  string    `json:"username"`
-	IssuedAt  time.Time `json:"issued_at"`
-	ExpiredAt time.Time `json:"expired_at"`
-}
-
-func NewPayload(username, role string, duration time.Duration) (*Payload, error) {
-	tokenId, err := uuid.NewRandom()
-	if err != nil {
-		return nil, err
-	}
-
-	payload := &Payload{
-		ID: tokenId,
-		Role: role,
-		Username: username,
-		IssuedAt: time.Now(),
-		ExpiredAt: time.Now().Add(duration),
-	}
-
-	return payload, nil
-}
-
-func (payload *Payload) Valid() error {
-	if time.Now().After(payload.ExpiredAt) {
-		return ErrExpiredToken
-	}
-	return nil
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrExpiredToken = errors.New("token has expired")
+	ErrInvalidToken = errors.New("token is invalid")
+)
+
+type Payload struct {
+	ID        uuid.UUID `json:"id"`
+	Role      string    `json:"role"`
+	Username  string    `json:"username"`
+	// Store and StaffRole are set on seller tokens: the store the user
+	// works for and their role in it. A store's own login is its owner.
+	Store     string    `json:"store,omitempty"`
+	StaffRole string    `json:"staff_role,omitempty"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+func NewPayload(username, role string, duration time.Duration) (*Payload, error) {
+	tokenId, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &Payload{
+		ID: tokenId,
+		Role: role,
+		Username: username,
+		IssuedAt: time.Now(),
+		ExpiredAt: time.Now().Add(duration),
+	}
+
+	return payload, nil
+}
+
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+	return nil
 }
\ No newline at end of file
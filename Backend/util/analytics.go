@@ -0,0 +1,36 @@
+package util
+
+const (
+	DailyInterval   = "day"
+	WeeklyInterval  = "week"
+	MonthlyInterval = "month"
+)
+
+func IsValidAnalyticsInterval(interval string) bool {
+	switch interval {
+	case DailyInterval, WeeklyInterval, MonthlyInterval:
+		return true
+	default:
+		return false
+	}
+}
+
+// StockTurnover is the number of times the average stock on hand was sold
+// over a period, measured in units
+func StockTurnover(unitsSold, openingStock, closingStock int) float64 {
+	averageStock := float64(openingStock+closingStock) / 2
+	if averageStock <= 0 {
+		return 0
+	}
+	return float64(unitsSold) / averageStock
+}
+
+// SellThrough is the share of near-expiry stock that was sold rather than
+// written off once it expired
+func SellThrough(unitsSold, unitsWrittenOff int) float64 {
+	total := unitsSold + unitsWrittenOff
+	if total <= 0 {
+		return 0
+	}
+	return float64(unitsSold) / float64(total)
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockTurnover(t *testing.T) {
+	require.InDelta(t, 2.0, StockTurnover(100, 40, 60), 0.0001)
+	require.Equal(t, 0.0, StockTurnover(10, 0, 0))
+}
+
+func TestSellThrough(t *testing.T) {
+	require.InDelta(t, 0.75, SellThrough(30, 10), 0.0001)
+	require.Equal(t, 0.0, SellThrough(0, 0))
+	require.Equal(t, 1.0, SellThrough(5, 0))
+}
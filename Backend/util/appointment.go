@@ -0,0 +1,122 @@
+package util
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	BookedAppointment    = "booked"
+	CancelledAppointment = "cancelled"
+	CompletedAppointment = "completed"
+	NoShowAppointment    = "no_show"
+)
+
+const (
+	InPersonVisit = "in_person"
+	VideoVisit    = "video"
+)
+
+// CheckInOpensBefore is how long before an appointment starts the patient
+// and doctor can check in
+const CheckInOpensBefore = 30 * time.Minute
+
+// AppointmentReminder is a reminder sent Lead before an appointment starts
+type AppointmentReminder struct {
+	Kind string
+	Lead time.Duration
+	// LeadText is the lead time as shown in the email
+	LeadText string
+}
+
+// AppointmentReminders are sent to both the patient and the doctor
+var AppointmentReminders = []AppointmentReminder{
+	{Kind: "24h", Lead: 24 * time.Hour, LeadText: "24 hours"},
+	{Kind: "1h", Lead: time.Hour, LeadText: "1 hour"},
+}
+
+// CanCheckIn reports whether an appointment running from start to end is
+// open for check-in at now
+func CanCheckIn(start, end, now time.Time) bool {
+	return !now.Before(start.Add(-CheckInOpensBefore)) && now.Before(end)
+}
+
+// IsWithinCancellationWindow reports whether an appointment starting at
+// start is too close at now for the patient to cancel or move it, given the
+// doctor's cancellation window in hours
+func IsWithinCancellationWindow(start, now time.Time, windowHours int) bool {
+	return start.Sub(now) < time.Duration(windowHours)*time.Hour
+}
+
+// IsRefundable reports whether cancelling a paid appointment starting at
+// start at now refunds the patient: always when the doctor cancels, and
+// otherwise when it is at least the doctor's refund window in hours ahead
+func IsRefundable(start, now time.Time, refundWindowHours int, byDoctor bool) bool {
+	return byDoctor || !IsWithinCancellationWindow(start, now, refundWindowHours)
+}
+
+// TimeRange is a span of time from Start up to, but not including, End
+type TimeRange struct {
+	Start time.Time `json:"starts_at"`
+	End   time.Time `json:"ends_at"`
+}
+
+func (r TimeRange) Overlaps(other TimeRange) bool {
+	return r.Start.Before(other.End) && other.Start.Before(r.End)
+}
+
+// AvailabilityWindow is a span of time a doctor takes appointments in, cut
+// into slots of SlotMinutes
+type AvailabilityWindow struct {
+	TimeRange
+	SlotMinutes int
+}
+
+// AppointmentSlots cuts availability windows into bookable slots, in order.
+// The tail of a window too short for a whole slot is dropped, as are slots
+// that overlap a blocked range, start before notBefore or overlap an earlier
+// slot from another window.
+func AppointmentSlots(windows []AvailabilityWindow, blocked []TimeRange, notBefore time.Time) []TimeRange {
+	var candidates []TimeRange
+	for _, window := range windows {
+		if window.SlotMinutes <= 0 {
+			continue
+		}
+		length := time.Duration(window.SlotMinutes) * time.Minute
+		for start := window.Start; !start.Add(length).After(window.End); start = start.Add(length) {
+			candidates = append(candidates, TimeRange{Start: start, End: start.Add(length)})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Start.Equal(candidates[j].Start) {
+			return candidates[i].End.Before(candidates[j].End)
+		}
+		return candidates[i].Start.Before(candidates[j].Start)
+	})
+
+	slots := make([]TimeRange, 0, len(candidates))
+	for _, slot := range candidates {
+		if slot.Start.Before(notBefore) {
+			continue
+		}
+		if len(slots) > 0 && slots[len(slots)-1].Overlaps(slot) {
+			continue
+		}
+		if overlapsAny(slot, blocked) {
+			continue
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots
+}
+
+func overlapsAny(r TimeRange, ranges []TimeRange) bool {
+	for _, other := range ranges {
+		if r.Overlaps(other) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2026, 1, 5, hour, minute, 0, 0, time.UTC)
+}
+
+func TestAppointmentSlots(t *testing.T) {
+	windows := []AvailabilityWindow{
+		{TimeRange: TimeRange{Start: at(9, 0), End: at(10, 10)}, SlotMinutes: 30},
+		{TimeRange: TimeRange{Start: at(14, 0), End: at(15, 0)}, SlotMinutes: 20},
+	}
+
+	slots := AppointmentSlots(windows, nil, time.Time{})
+	require.Equal(t, []TimeRange{
+		{Start: at(9, 0), End: at(9, 30)},
+		{Start: at(9, 30), End: at(10, 0)},
+		{Start: at(14, 0), End: at(14, 20)},
+		{Start: at(14, 20), End: at(14, 40)},
+		{Start: at(14, 40), End: at(15, 0)},
+	}, slots)
+
+	blocked := []TimeRange{
+		{Start: at(9, 15), End: at(9, 45)},
+		{Start: at(14, 20), End: at(14, 40)},
+	}
+	slots = AppointmentSlots(windows, blocked, at(14, 30))
+	require.Equal(t, []TimeRange{
+		{Start: at(14, 40), End: at(15, 0)},
+	}, slots)
+
+	slots = AppointmentSlots(windows, blocked, time.Time{})
+	require.Equal(t, []TimeRange{
+		{Start: at(14, 0), End: at(14, 20)},
+		{Start: at(14, 40), End: at(15, 0)},
+	}, slots)
+}
+
+func TestAppointmentSlotsOverlappingWindows(t *testing.T) {
+	windows := []AvailabilityWindow{
+		{TimeRange: TimeRange{Start: at(9, 0), End: at(10, 0)}, SlotMinutes: 30},
+		{TimeRange: TimeRange{Start: at(9, 15), End: at(10, 15)}, SlotMinutes: 15},
+	}
+
+	slots := AppointmentSlots(windows, nil, time.Time{})
+	require.Equal(t, []TimeRange{
+		{Start: at(9, 0), End: at(9, 30)},
+		{Start: at(9, 30), End: at(9, 45)},
+		{Start: at(9, 45), End: at(10, 0)},
+		{Start: at(10, 0), End: at(10, 15)},
+	}, slots)
+}
+
+func TestCanCheckIn(t *testing.T) {
+	start, end := at(10, 0), at(10, 30)
+
+	require.False(t, CanCheckIn(start, end, at(9, 29)))
+	require.True(t, CanCheckIn(start, end, at(9, 30)))
+	require.True(t, CanCheckIn(start, end, at(10, 29)))
+	require.False(t, CanCheckIn(start, end, at(10, 30)))
+}
+
+func TestIsWithinCancellationWindow(t *testing.T) {
+	start := at(18, 0)
+
+	require.False(t, IsWithinCancellationWindow(start, at(9, 0), 0))
+	require.False(t, IsWithinCancellationWindow(start, at(14, 0), 4))
+	require.True(t, IsWithinCancellationWindow(start, at(14, 1), 4))
+	require.True(t, IsWithinCancellationWindow(start, at(17, 59), 1))
+}
+
+func TestIsRefundable(t *testing.T) {
+	start := at(18, 0)
+
+	require.True(t, IsRefundable(start, at(9, 0), 8, false))
+	require.True(t, IsRefundable(start, at(10, 0), 8, false))
+	require.False(t, IsRefundable(start, at(10, 1), 8, false))
+	require.True(t, IsRefundable(start, at(17, 0), 8, true))
+}
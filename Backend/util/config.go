@@ -1,45 +1,146 @@
-package util
-
-import (
-	"time"
-
-	"github.com/spf13/viper"
-)
-
-type Config struct {
-	DBSource          string        `mapstructure:"DB_SOURCE"`
-	Environment       string        `mapstructure:"ENVIRONMENT"`
-	HTTPAddress       string        `mapstructure:"HTTP_ADDRESS"`
-	TokenSymmetricKey string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	TokenDuration     time.Duration `mapstructure:"TOKEN_DURATION"`
-	SMTPHost          string        `mapstructure:"SMTP_HOST"`
-	SMTPPort          string        `mapstructure:"SMTP_PORT"`
-	SMTPUsername      string        `mapstructure:"SMTP_USERNAME"`
-	SMTPPassword      string        `mapstructure:"SMTP_PASSWORD"`
-	SenderName        string        `mapstructure:"SENDER_NAME"`
-	SenderEmail       string        `mapstructure:"SENDER_EMAIL"`
-	ExpiryCheckPeriod time.Duration `mapstructure:"EXPIRY_CHECK_PERIOD"`
-}
-
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("app")
-	viper.SetConfigType("env")
-	viper.AutomaticEnv()
-
-	if err = viper.ReadInConfig(); err != nil {
-		return
-	}
-	err = viper.Unmarshal(&config)
-
-	// Set default values for mail config if not provided
-	if config.ExpiryCheckPeriod == 0 {
-		config.ExpiryCheckPeriod = 24 * time.Hour
-	}
-
-	if config.SenderName == "" {
-		config.SenderName = "MediBridge System"
-	}
-
-	return
-}
+package util
+
+import (
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	DBSource          string        `mapstructure:"DB_SOURCE"`
+	Environment       string        `mapstructure:"ENVIRONMENT"`
+	HTTPAddress       string        `mapstructure:"HTTP_ADDRESS"`
+	TokenSymmetricKey string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenDuration     time.Duration `mapstructure:"TOKEN_DURATION"`
+	SMTPHost          string        `mapstructure:"SMTP_HOST"`
+	SMTPPort          string        `mapstructure:"SMTP_PORT"`
+	SMTPUsername      string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword      string        `mapstructure:"SMTP_PASSWORD"`
+	SenderName        string        `mapstructure:"SENDER_NAME"`
+	SenderEmail       string        `mapstructure:"SENDER_EMAIL"`
+	ExpiryCheckPeriod time.Duration `mapstructure:"EXPIRY_CHECK_PERIOD"`
+	// Days-to-expiry thresholds for the expiry digest, e.g. "30,90,180"
+	ExpiryNotifyThresholds []int `mapstructure:"EXPIRY_NOTIFY_THRESHOLDS"`
+	// Stock within this many days of expiry can be offered to NGO and
+	// hospital sellers
+	DonationWindowDays int `mapstructure:"DONATION_WINDOW_DAYS"`
+	// Reorder suggestions are based on the sales of the last
+	// LowStockSalesWindowDays and aim to cover ReorderCoverDays of sales
+	LowStockSalesWindowDays int `mapstructure:"LOW_STOCK_SALES_WINDOW_DAYS"`
+	ReorderCoverDays        int `mapstructure:"REORDER_COVER_DAYS"`
+	// How often the sales rollups behind the seller analytics are refreshed
+	AnalyticsRefreshPeriod time.Duration `mapstructure:"ANALYTICS_REFRESH_PERIOD"`
+	// How long an invite to join a store's staff stays valid
+	StaffInviteDuration time.Duration `mapstructure:"STAFF_INVITE_DURATION"`
+	// How long after the previous payout batch the next one is run
+	PayoutPeriod time.Duration `mapstructure:"PAYOUT_PERIOD"`
+	// How often appointment reminders are sent and finished appointments
+	// are closed
+	AppointmentReminderPeriod time.Duration `mapstructure:"APPOINTMENT_REMINDER_PERIOD"`
+	// How long after an appointment ends it is closed as completed or as a
+	// no-show
+	NoShowGracePeriod time.Duration `mapstructure:"NO_SHOW_GRACE_PERIOD"`
+	// How long a paid appointment holds its slot while waiting for payment
+	AppointmentPaymentHold time.Duration `mapstructure:"APPOINTMENT_PAYMENT_HOLD"`
+	// Directory the documents in patients' medical record vaults are
+	// stored in
+	DocumentStorageDir string `mapstructure:"DOCUMENT_STORAGE_DIR"`
+	// Keys of the Stripe account patients pay through. Without them
+	// nothing can be paid for.
+	PaymentSecretKey     string `mapstructure:"PAYMENT_SECRET_KEY"`
+	PaymentWebhookSecret string `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	PaymentCurrency      string `mapstructure:"PAYMENT_CURRENCY"`
+	// How long a placed order holds its stock while waiting for payment,
+	// and how often orders whose hold ran out are released
+	OrderPaymentHold  time.Duration `mapstructure:"ORDER_PAYMENT_HOLD"`
+	OrderExpiryPeriod time.Duration `mapstructure:"ORDER_EXPIRY_PERIOD"`
+	// Origins the front end is served from, e.g.
+	// "https://medibridge.example,http://localhost:5173". Browsers can only
+	// open consultation chats from these.
+	FrontendOrigins []string `mapstructure:"FRONTEND_ORIGINS"`
+}
+
+func LoadConfig(path string) (config Config, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+	viper.AutomaticEnv()
+
+	if err = viper.ReadInConfig(); err != nil {
+		return
+	}
+	err = viper.Unmarshal(&config)
+
+	// Set default values for mail config if not provided
+	if config.ExpiryCheckPeriod == 0 {
+		config.ExpiryCheckPeriod = 24 * time.Hour
+	}
+
+	if len(config.ExpiryNotifyThresholds) == 0 {
+		config.ExpiryNotifyThresholds = []int{30, 90, 180}
+	}
+	sort.Ints(config.ExpiryNotifyThresholds)
+
+	if config.DonationWindowDays == 0 {
+		config.DonationWindowDays = 90
+	}
+
+	if config.LowStockSalesWindowDays == 0 {
+		config.LowStockSalesWindowDays = 30
+	}
+
+	if config.ReorderCoverDays == 0 {
+		config.ReorderCoverDays = 30
+	}
+
+	if config.AnalyticsRefreshPeriod == 0 {
+		config.AnalyticsRefreshPeriod = 15 * time.Minute
+	}
+
+	if config.StaffInviteDuration == 0 {
+		config.StaffInviteDuration = 7 * 24 * time.Hour
+	}
+
+	if config.PayoutPeriod == 0 {
+		config.PayoutPeriod = 7 * 24 * time.Hour
+	}
+
+	if config.AppointmentReminderPeriod == 0 {
+		config.AppointmentReminderPeriod = 5 * time.Minute
+	}
+
+	if config.NoShowGracePeriod == 0 {
+		config.NoShowGracePeriod = 30 * time.Minute
+	}
+
+	if config.AppointmentPaymentHold == 0 {
+		config.AppointmentPaymentHold = 15 * time.Minute
+	}
+
+	if config.DocumentStorageDir == "" {
+		config.DocumentStorageDir = "documents"
+	}
+
+	if config.PaymentCurrency == "" {
+		config.PaymentCurrency = "INR"
+	}
+
+	if config.OrderPaymentHold == 0 {
+		config.OrderPaymentHold = 30 * time.Minute
+	}
+
+	if config.OrderExpiryPeriod == 0 {
+		config.OrderExpiryPeriod = 5 * time.Minute
+	}
+
+	if len(config.FrontendOrigins) == 0 {
+		config.FrontendOrigins = []string{"http://localhost:5173"}
+	}
+
+	if config.SenderName == "" {
+		config.SenderName = "MediBridge System"
+	}
+
+	return
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	OpenConsultation   = "open"
+	ClosedConsultation = "closed"
+)
+
+const (
+	// MaxConsultationMessageLength is the longest chat message, in characters
+	MaxConsultationMessageLength = 4000
+	// MaxConsultationAttachmentSize is the largest file that can be shared in
+	// a consultation, in bytes
+	MaxConsultationAttachmentSize = 10 << 20
+)
+
+// consultationAttachmentTypes are the files that can be shared in a
+// consultation: lab reports, scans and photos
+var consultationAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// ConsultationAttachmentType sniffs the type of a file shared in a
+// consultation from its contents, so a renamed file can't pass as a report.
+// It reports false for types that can't be shared.
+func ConsultationAttachmentType(data []byte) (string, bool) {
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType, consultationAttachmentTypes[contentType]
+}
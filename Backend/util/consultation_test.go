@@ -0,0 +1,55 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsultationAttachmentType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		data        []byte
+		contentType string
+		ok          bool
+	}{
+		{
+			name:        "PDF",
+			data:        []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"),
+			contentType: "application/pdf",
+			ok:          true,
+		},
+		{
+			name:        "PNG",
+			data:        []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+			contentType: "image/png",
+			ok:          true,
+		},
+		{
+			name:        "JPEG",
+			data:        []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"),
+			contentType: "image/jpeg",
+			ok:          true,
+		},
+		{
+			name:        "PlainText",
+			data:        []byte("haemoglobin 13.5 g/dL"),
+			contentType: "text/plain",
+			ok:          false,
+		},
+		{
+			name:        "HTML",
+			data:        []byte("<html><script>alert(1)</script></html>"),
+			contentType: "text/html",
+			ok:          false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contentType, ok := ConsultationAttachmentType(tc.data)
+			require.Equal(t, tc.contentType, contentType)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
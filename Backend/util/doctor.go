@@ -0,0 +1,113 @@
+package util
+
+import (
+	"sort"
+	"strings"
+)
+
+// Common ways patients name a specialty, mapped to the name doctors are
+// searched by
+var specialtyAliases = map[string]string{
+	"heart":            "cardiology",
+	"cardiac":          "cardiology",
+	"cardio":           "cardiology",
+	"cardiology":       "cardiology",
+	"skin":             "dermatology",
+	"dermatology":      "dermatology",
+	"brain":            "neurology",
+	"neuro":            "neurology",
+	"neurology":        "neurology",
+	"bones":            "orthopedics",
+	"joints":           "orthopedics",
+	"orthopedic":       "orthopedics",
+	"orthopedics":      "orthopedics",
+	"eye":              "ophthalmology",
+	"eyes":             "ophthalmology",
+	"ophthalmology":    "ophthalmology",
+	"ear":              "ent",
+	"nose":             "ent",
+	"throat":           "ent",
+	"ent":              "ent",
+	"children":         "pediatrics",
+	"child":            "pediatrics",
+	"pediatric":        "pediatrics",
+	"pediatrics":       "pediatrics",
+	"women":            "gynecology",
+	"gynecology":       "gynecology",
+	"pregnancy":        "obstetrics",
+	"obstetrics":       "obstetrics",
+	"kidney":           "nephrology",
+	"nephrology":       "nephrology",
+	"surgery":          "general surgery",
+	"teeth":            "dentistry",
+	"tooth":            "dentistry",
+	"dental":           "dentistry",
+	"dentistry":        "dentistry",
+	"mental":           "psychiatry",
+	"psychiatry":       "psychiatry",
+	"psychology":       "psychology",
+	"diabetes":         "endocrinology",
+	"endocrine":        "endocrinology",
+	"endocrinology":    "endocrinology",
+	"lungs":            "pulmonology",
+	"respiratory":      "pulmonology",
+	"pulmonology":      "pulmonology",
+	"stomach":          "gastroenterology",
+	"digestive":        "gastroenterology",
+	"gastro":           "gastroenterology",
+	"gastroenterology": "gastroenterology",
+}
+
+// Longest aliases first, so partial matches prefer "heart" over "ear" in
+// "heart specialist" and always pick the same one
+var specialtyAliasKeys = sortedSpecialtyAliases()
+
+func sortedSpecialtyAliases() []string {
+	keys := make([]string, 0, len(specialtyAliases))
+	for key := range specialtyAliases {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// NormalizeSpecialty turns a specialty as a patient might put it, such as
+// "Heart" or "child doctor", into the lower-case specialty name, here
+// "cardiology" and "pediatrics". Unknown specialties are only trimmed and
+// lower-cased.
+func NormalizeSpecialty(specialty string) string {
+	specialty = strings.ToLower(strings.TrimSpace(specialty))
+
+	if standardized, ok := specialtyAliases[specialty]; ok {
+		return standardized
+	}
+
+	for _, key := range specialtyAliasKeys {
+		if strings.Contains(specialty, key) {
+			return specialtyAliases[key]
+		}
+	}
+
+	return specialty
+}
+
+// NormalizeLanguages lower-cases and trims the languages a doctor speaks,
+// dropping blanks and duplicates but keeping their order
+func NormalizeLanguages(languages []string) []string {
+	normalized := make([]string, 0, len(languages))
+	seen := make(map[string]bool, len(languages))
+	for _, language := range languages {
+		language = strings.ToLower(strings.TrimSpace(language))
+		if language == "" || seen[language] {
+			continue
+		}
+		seen[language] = true
+		normalized = append(normalized, language)
+	}
+	return normalized
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSpecialty(t *testing.T) {
+	testCases := []struct {
+		specialty  string
+		normalized string
+	}{
+		{specialty: "Cardiology", normalized: "cardiology"},
+		{specialty: " heart ", normalized: "cardiology"},
+		{specialty: "heart specialist", normalized: "cardiology"},
+		{specialty: "child doctor", normalized: "pediatrics"},
+		{specialty: "ENT", normalized: "ent"},
+		{specialty: "Rheumatology", normalized: "rheumatology"},
+		{specialty: "", normalized: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.specialty, func(t *testing.T) {
+			require.Equal(t, tc.normalized, NormalizeSpecialty(tc.specialty))
+		})
+	}
+}
+
+func TestNormalizeLanguages(t *testing.T) {
+	require.Equal(t, []string{"english", "hindi"}, NormalizeLanguages([]string{" English", "hindi", "ENGLISH", ""}))
+	require.Empty(t, NormalizeLanguages(nil))
+}
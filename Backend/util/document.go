@@ -0,0 +1,98 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Types of document in a patient's medical record vault
+const (
+	LabReportDocument        = "lab_report"
+	DischargeSummaryDocument = "discharge_summary"
+	ScanDocument             = "scan"
+	PrescriptionDocument     = "prescription"
+	VaccinationDocument      = "vaccination"
+	InsuranceDocument        = "insurance"
+	OtherDocument            = "other"
+)
+
+const (
+	// MaxDocumentSize is the largest document that can be uploaded, in bytes
+	MaxDocumentSize = 20 << 20
+	// MaxDocumentTags is the most tags a document can have, and
+	// MaxDocumentTagLength the longest tag, in characters
+	MaxDocumentTags      = 10
+	MaxDocumentTagLength = 32
+	// MaxDocumentShareHours is the longest a document can be shared with a
+	// doctor for
+	MaxDocumentShareHours = 30 * 24
+)
+
+const dicomContentType = "application/dicom"
+
+// documentContentTypes are the files that can be uploaded to the vault:
+// reports as PDFs, scans and photos as images or DICOM files
+var documentContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	dicomContentType:  true,
+}
+
+func IsValidDocumentType(documentType string) bool {
+	switch documentType {
+	case LabReportDocument, DischargeSummaryDocument, ScanDocument, PrescriptionDocument,
+		VaccinationDocument, InsuranceDocument, OtherDocument:
+		return true
+	}
+	return false
+}
+
+// IsDocumentContentType reports whether files of the MIME type can be
+// uploaded
+func IsDocumentContentType(contentType string) bool {
+	return documentContentTypes[contentType]
+}
+
+// DocumentContentType sniffs the type of an uploaded document from its
+// contents, so a renamed file can't pass as a report. It reports false for
+// types that can't be uploaded.
+func DocumentContentType(data []byte) (string, bool) {
+	// DICOM files start with a 128 byte preamble and the "DICM" prefix,
+	// which http.DetectContentType doesn't know about
+	if len(data) >= 132 && bytes.Equal(data[128:132], []byte("DICM")) {
+		return dicomContentType, true
+	}
+
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType, documentContentTypes[contentType]
+}
+
+// NormalizeDocumentTags lowercases tags and collapses their whitespace,
+// dropping empty and repeated ones, so "Blood  Test" and "blood test" are
+// the same tag
+func NormalizeDocumentTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+		if len([]rune(tag)) > MaxDocumentTagLength {
+			return nil, fmt.Errorf("tag is longer than %d characters: %s", MaxDocumentTagLength, tag)
+		}
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > MaxDocumentTags {
+		return nil, fmt.Errorf("a document can have at most %d tags", MaxDocumentTags)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentContentType(t *testing.T) {
+	dicom := append(make([]byte, 128), []byte("DICM\x02\x00")...)
+
+	testCases := []struct {
+		name        string
+		data        []byte
+		contentType string
+		ok          bool
+	}{
+		{"pdf", []byte("%PDF-1.7\n"), "application/pdf", true},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png", true},
+		{"jpeg", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "image/jpeg", true},
+		{"dicom", dicom, "application/dicom", true},
+		{"text", []byte("just some notes"), "text/plain", false},
+		{"html", []byte("<html><body>report</body></html>"), "text/html", false},
+		{"zip", []byte("PK\x03\x04"), "application/zip", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contentType, ok := DocumentContentType(tc.data)
+			require.Equal(t, tc.contentType, contentType)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestNormalizeDocumentTags(t *testing.T) {
+	tags, err := NormalizeDocumentTags([]string{" Blood  Test", "blood test", "", "Thyroid"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"blood test", "thyroid"}, tags)
+
+	tags, err = NormalizeDocumentTags(nil)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+
+	_, err = NormalizeDocumentTags([]string{strings.Repeat("a", MaxDocumentTagLength+1)})
+	require.Error(t, err)
+
+	tooMany := make([]string, MaxDocumentTags+1)
+	for i := range tooMany {
+		tooMany[i] = RandomString(8)
+	}
+	_, err = NormalizeDocumentTags(tooMany)
+	require.Error(t, err)
+}
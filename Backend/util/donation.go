@@ -0,0 +1,33 @@
+package util
+
+const (
+	DonationOffer   = "donation"
+	DiscountedOffer = "discounted"
+)
+
+func IsValidDonationOfferType(offerType string) bool {
+	switch offerType {
+	case DonationOffer, DiscountedOffer:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanOfferDonations reports whether sellers of this type can put near-expiry
+// stock on offer to NGOs and hospitals
+func CanOfferDonations(sellerType string) bool {
+	return sellerType == RetailSeller || sellerType == WholesaleSeller
+}
+
+// CanClaimDonations reports whether sellers of this type can claim offered
+// stock, once they are verified
+func CanClaimDonations(sellerType string) bool {
+	return sellerType == NGOSeller || sellerType == HospitalSeller
+}
+
+// IsDonationCandidate reports whether a medicine that expires in the given
+// number of days is close enough to expiry to be offered
+func IsDonationCandidate(days, windowDays int) bool {
+	return days > 0 && days <= windowDays
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDonationSellerTypes(t *testing.T) {
+	require.True(t, CanOfferDonations(RetailSeller))
+	require.True(t, CanOfferDonations(WholesaleSeller))
+	require.False(t, CanOfferDonations(NGOSeller))
+
+	require.True(t, CanClaimDonations(NGOSeller))
+	require.True(t, CanClaimDonations(HospitalSeller))
+	require.False(t, CanClaimDonations(RetailSeller))
+}
+
+func TestIsDonationCandidate(t *testing.T) {
+	require.True(t, IsDonationCandidate(1, 90))
+	require.True(t, IsDonationCandidate(90, 90))
+	require.False(t, IsDonationCandidate(91, 90))
+	require.False(t, IsDonationCandidate(0, 90))
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	TabletForm    = "tablet"
+	CapsuleForm   = "capsule"
+	SyrupForm     = "syrup"
+	InjectionForm = "injection"
+	CreamForm     = "cream"
+	OintmentForm  = "ointment"
+	DropsForm     = "drops"
+	InhalerForm   = "inhaler"
+	PowderForm    = "powder"
+)
+
+func IsValidDosageForm(dosageForm string) bool {
+	switch dosageForm {
+	case TabletForm, CapsuleForm, SyrupForm, InjectionForm, CreamForm,
+		OintmentForm, DropsForm, InhalerForm, PowderForm:
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizeIngredients turns a free-text ingredient list such as
+// "Paracetamol + Caffeine" into a canonical, order-independent form
+// ("caffeine, paracetamol") so that equivalent medicines compare equal.
+func NormalizeIngredients(ingredients string) string {
+	fields := strings.FieldsFunc(strings.ToLower(ingredients), func(r rune) bool {
+		return r == ',' || r == '+' || r == ';' || r == '/'
+	})
+
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, field := range fields {
+		ingredient := strings.Join(strings.Fields(field), " ")
+		if ingredient == "" || seen[ingredient] {
+			continue
+		}
+		seen[ingredient] = true
+		normalized = append(normalized, ingredient)
+	}
+
+	sort.Strings(normalized)
+	return strings.Join(normalized, ", ")
+}
+
+// NormalizeStrength lowercases a strength and strips whitespace so that
+// "500 MG" and "500mg" are treated as the same strength.
+func NormalizeStrength(strength string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strength)), "")
+}
@@ -0,0 +1,19 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeIngredients(t *testing.T) {
+	require.Equal(t, "caffeine, paracetamol", NormalizeIngredients("Paracetamol + Caffeine"))
+	require.Equal(t, "caffeine, paracetamol", NormalizeIngredients(" caffeine,paracetamol ,Caffeine"))
+	require.Equal(t, "folic acid", NormalizeIngredients("Folic   Acid"))
+	require.Empty(t, NormalizeIngredients(" , + "))
+}
+
+func TestNormalizeStrength(t *testing.T) {
+	require.Equal(t, "500mg", NormalizeStrength("500 MG"))
+	require.Equal(t, "5mg/5ml", NormalizeStrength("5 mg / 5 ml"))
+}
@@ -0,0 +1,12 @@
+package util
+
+// ExpiryThreshold returns the smallest of the ascending thresholds that
+// daysUntilExpiry falls under, and false when it is beyond all of them.
+func ExpiryThreshold(daysUntilExpiry int, thresholds []int) (int, bool) {
+	for _, threshold := range thresholds {
+		if daysUntilExpiry <= threshold {
+			return threshold, true
+		}
+	}
+	return 0, false
+}
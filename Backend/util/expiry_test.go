@@ -0,0 +1,22 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryThreshold(t *testing.T) {
+	thresholds := []int{30, 90, 180}
+
+	threshold, ok := ExpiryThreshold(12, thresholds)
+	require.True(t, ok)
+	require.Equal(t, 30, threshold)
+
+	threshold, ok = ExpiryThreshold(90, thresholds)
+	require.True(t, ok)
+	require.Equal(t, 90, threshold)
+
+	_, ok = ExpiryThreshold(181, thresholds)
+	require.False(t, ok)
+}
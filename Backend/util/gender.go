@@ -1,7 +1,7 @@
-package util
-
-const (
-	Male = "male"
-	Female = "female"
-	Other = "other"
+package util
+
+const (
+	Male = "male"
+	Female = "female"
+	Other = "other"
 )
\ No newline at end of file
@@ -0,0 +1,32 @@
+package util
+
+import "regexp"
+
+const (
+	// DefaultDeliveryRadiusKm is used for sellers that don't set their own
+	DefaultDeliveryRadiusKm = 5.0
+	// DefaultSearchRadiusKm bounds nearby pharmacy searches
+	DefaultSearchRadiusKm = 10.0
+)
+
+var pincodeRegex = regexp.MustCompile(`\b[1-9][0-9]{5}\b`)
+
+// IsValidPincode checks for a six digit Indian postal code
+func IsValidPincode(pincode string) bool {
+	return len(pincode) == 6 && pincodeRegex.MatchString(pincode)
+}
+
+// ExtractPincode returns the last pincode found in a free-text address,
+// or an empty string if there is none
+func ExtractPincode(address string) string {
+	matches := pincodeRegex.FindAllString(address, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1]
+}
+
+// IsValidCoordinates checks that a latitude/longitude pair is in range
+func IsValidCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
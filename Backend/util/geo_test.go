@@ -0,0 +1,21 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPincode(t *testing.T) {
+	require.Equal(t, "560034", ExtractPincode("12 MG Road, Koramangala, Bengaluru 560034"))
+	require.Equal(t, "110001", ExtractPincode("Flat 1234567, Connaught Place, New Delhi - 110001"))
+	require.Empty(t, ExtractPincode("Shop 4, Main Market"))
+	require.Empty(t, ExtractPincode("PO Box 012345"))
+}
+
+func TestIsValidPincode(t *testing.T) {
+	require.True(t, IsValidPincode("400001"))
+	require.False(t, IsValidPincode("40001"))
+	require.False(t, IsValidPincode("040001"))
+	require.False(t, IsValidPincode("4000011"))
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"slices"
+	"strings"
+)
+
+// Kinds of entry on a patient's health lists, and of the clinical terms
+// they are coded against
+const (
+	AllergyEntry   = "allergy"
+	ConditionEntry = "condition"
+)
+
+// Codes of the terms for allergies and conditions the vocabulary has no
+// term for. Entries coded with them carry a description instead.
+const (
+	OtherAllergyCode   = "ALG-OTHER"
+	OtherConditionCode = "CND-OTHER"
+)
+
+const (
+	MildSeverity     = "mild"
+	ModerateSeverity = "moderate"
+	SevereSeverity   = "severe"
+	UnknownSeverity  = "unknown"
+)
+
+const (
+	ActiveHealthEntry   = "active"
+	InactiveHealthEntry = "inactive"
+	ResolvedHealthEntry = "resolved"
+)
+
+func IsOtherClinicalTerm(code string) bool {
+	return code == OtherAllergyCode || code == OtherConditionCode
+}
+
+func IsValidSeverity(severity string) bool {
+	switch severity {
+	case MildSeverity, ModerateSeverity, SevereSeverity, UnknownSeverity:
+		return true
+	}
+	return false
+}
+
+func IsValidHealthEntryStatus(status string) bool {
+	switch status {
+	case ActiveHealthEntry, InactiveHealthEntry, ResolvedHealthEntry:
+		return true
+	}
+	return false
+}
+
+// FlaggedIngredients gives the flagged ingredients a medicine's active
+// ingredients contain. An ingredient matches on whole words, so a flag on
+// "amoxicillin" catches "Amoxicillin Trihydrate" but a flag on "codeine"
+// doesn't catch "dihydrocodeine".
+func FlaggedIngredients(activeIngredients string, flagged []string) []string {
+	var found []string
+	for _, ingredient := range strings.Split(NormalizeIngredients(activeIngredients), ", ") {
+		if ingredient == "" {
+			continue
+		}
+		words := " " + ingredient + " "
+		for _, flag := range flagged {
+			flag = strings.Join(strings.Fields(strings.ToLower(flag)), " ")
+			if flag != "" && strings.Contains(words, " "+flag+" ") && !slices.Contains(found, flag) {
+				found = append(found, flag)
+			}
+		}
+	}
+	return found
+}
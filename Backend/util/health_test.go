@@ -0,0 +1,42 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlaggedIngredients(t *testing.T) {
+	penicillins := []string{"penicillin", "amoxicillin", "ampicillin"}
+
+	testCases := []struct {
+		name        string
+		ingredients string
+		flagged     []string
+		expected    []string
+	}{
+		{"single ingredient", "Amoxicillin", penicillins, []string{"amoxicillin"}},
+		{"combination", "Amoxicillin + Clavulanic Acid", penicillins, []string{"amoxicillin"}},
+		{"salt form", "Amoxicillin Trihydrate", penicillins, []string{"amoxicillin"}},
+		{"multi-word flag", "Acetylsalicylic  Acid", []string{"acetylsalicylic acid"}, []string{"acetylsalicylic acid"}},
+		{"part of a word", "Dihydrocodeine", []string{"codeine"}, nil},
+		{"no match", "Paracetamol, Caffeine", penicillins, nil},
+		{"no flags", "Ibuprofen", nil, nil},
+		{"no ingredients", "", penicillins, nil},
+		{"reported once", "Aspirin; aspirin", []string{"aspirin", "Aspirin"}, []string{"aspirin"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, FlaggedIngredients(tc.ingredients, tc.flagged))
+		})
+	}
+}
+
+func TestIsValidSeverity(t *testing.T) {
+	for _, severity := range []string{MildSeverity, ModerateSeverity, SevereSeverity, UnknownSeverity} {
+		require.True(t, IsValidSeverity(severity))
+	}
+	require.False(t, IsValidSeverity("critical"))
+	require.False(t, IsValidSeverity(""))
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"regexp"
+	"strconv"
+)
+
+const (
+	EarningLedgerKind    = "earning"
+	RefundLedgerKind     = "refund"
+	ChargebackLedgerKind = "chargeback"
+	PayoutLedgerKind     = "payout"
+)
+
+var moneyAmountRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,2})?$`)
+
+// IsValidMoneyAmount checks for a positive amount in rupees with at most
+// two decimal places, so that it can be split without rounding errors
+func IsValidMoneyAmount(amount string) bool {
+	if !moneyAmountRegex.MatchString(amount) {
+		return false
+	}
+	value, err := strconv.ParseFloat(amount, 64)
+	return err == nil && value > 0
+}
+
+// IsValidCommissionRate checks for a commission percentage between 0 and
+// 100 with at most two decimal places
+func IsValidCommissionRate(rate string) bool {
+	if rate == "0" {
+		return true
+	}
+	if !IsValidMoneyAmount(rate) {
+		return false
+	}
+	value, err := strconv.ParseFloat(rate, 64)
+	return err == nil && value <= 100
+}
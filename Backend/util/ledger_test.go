@@ -0,0 +1,31 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidMoneyAmount(t *testing.T) {
+	require.True(t, IsValidMoneyAmount("100"))
+	require.True(t, IsValidMoneyAmount("99.5"))
+	require.True(t, IsValidMoneyAmount("0.01"))
+
+	require.False(t, IsValidMoneyAmount("0"))
+	require.False(t, IsValidMoneyAmount("0.00"))
+	require.False(t, IsValidMoneyAmount("-10"))
+	require.False(t, IsValidMoneyAmount("10.005"))
+	require.False(t, IsValidMoneyAmount("1e3"))
+	require.False(t, IsValidMoneyAmount(""))
+}
+
+func TestIsValidCommissionRate(t *testing.T) {
+	require.True(t, IsValidCommissionRate("0"))
+	require.True(t, IsValidCommissionRate("8.5"))
+	require.True(t, IsValidCommissionRate("100"))
+
+	require.False(t, IsValidCommissionRate("100.01"))
+	require.False(t, IsValidCommissionRate("-1"))
+	require.False(t, IsValidCommissionRate("8.555"))
+	require.False(t, IsValidCommissionRate(""))
+}
@@ -0,0 +1,27 @@
+package util
+
+const (
+	ActiveMedicine      = "active"
+	QuarantinedMedicine = "quarantined"
+	DisposedMedicine    = "disposed"
+	WithdrawnMedicine   = "withdrawn"
+)
+
+// Ways of destroying quarantined stock that a seller can record
+const (
+	IncinerationDisposal      = "incineration"
+	ReturnToSupplierDisposal  = "return_to_supplier"
+	AuthorizedAgencyDisposal  = "authorized_agency"
+	ChemicalTreatmentDisposal = "chemical_treatment"
+	OtherDisposal             = "other"
+)
+
+func IsValidDisposalMethod(method string) bool {
+	switch method {
+	case IncinerationDisposal, ReturnToSupplierDisposal, AuthorizedAgencyDisposal,
+		ChemicalTreatmentDisposal, OtherDisposal:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,35 @@
+package util
+
+const (
+	PlacedOrder    = "placed"
+	ConfirmedOrder = "confirmed"
+	ShippedOrder   = "shipped"
+	DeliveredOrder = "delivered"
+	CancelledOrder = "cancelled"
+)
+
+// subOrderTransitions lists the statuses each sub-order status can move on
+// to. Delivered and cancelled sub-orders are final.
+var subOrderTransitions = map[string][]string{
+	PlacedOrder:    {ConfirmedOrder, CancelledOrder},
+	ConfirmedOrder: {ShippedOrder, CancelledOrder},
+	ShippedOrder:   {DeliveredOrder},
+}
+
+func IsValidOrderStatus(status string) bool {
+	switch status {
+	case PlacedOrder, ConfirmedOrder, ShippedOrder, DeliveredOrder, CancelledOrder:
+		return true
+	default:
+		return false
+	}
+}
+
+func CanTransitionSubOrder(from, to string) bool {
+	for _, status := range subOrderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionSubOrder(t *testing.T) {
+	require.True(t, CanTransitionSubOrder(PlacedOrder, ConfirmedOrder))
+	require.True(t, CanTransitionSubOrder(ConfirmedOrder, CancelledOrder))
+	require.True(t, CanTransitionSubOrder(ShippedOrder, DeliveredOrder))
+
+	require.False(t, CanTransitionSubOrder(PlacedOrder, DeliveredOrder))
+	require.False(t, CanTransitionSubOrder(ShippedOrder, CancelledOrder))
+	require.False(t, CanTransitionSubOrder(DeliveredOrder, CancelledOrder))
+}
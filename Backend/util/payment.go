@@ -0,0 +1,18 @@
+package util
+
+// Payment statuses of an appointment. Free appointments don't need paying
+// for; paid ones are pending until the patient pays and unpaid when the
+// hold on the slot ran out or the appointment was cancelled first.
+const (
+	PaymentNotRequired = "not_required"
+	PaymentPending     = "pending"
+	PaymentPaid        = "paid"
+	PaymentRefunded    = "refunded"
+	PaymentUnpaid      = "unpaid"
+)
+
+// Statuses of a payment made through the provider. Payments are pending
+// until the provider confirms them.
+const (
+	PaymentSucceeded = "succeeded"
+)
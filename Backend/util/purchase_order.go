@@ -0,0 +1,37 @@
+package util
+
+const (
+	PlacedPurchaseOrder    = "placed"
+	AcceptedPurchaseOrder  = "accepted"
+	RejectedPurchaseOrder  = "rejected"
+	CancelledPurchaseOrder = "cancelled"
+	ShippedPurchaseOrder   = "shipped"
+	ReceivedPurchaseOrder  = "received"
+)
+
+// purchaseOrderTransitions lists the statuses each purchase order status can
+// move on to. Rejected, cancelled and received orders are final.
+var purchaseOrderTransitions = map[string][]string{
+	PlacedPurchaseOrder:   {AcceptedPurchaseOrder, RejectedPurchaseOrder, CancelledPurchaseOrder},
+	AcceptedPurchaseOrder: {ShippedPurchaseOrder, CancelledPurchaseOrder},
+	ShippedPurchaseOrder:  {ReceivedPurchaseOrder},
+}
+
+func IsValidPurchaseOrderStatus(status string) bool {
+	switch status {
+	case PlacedPurchaseOrder, AcceptedPurchaseOrder, RejectedPurchaseOrder,
+		CancelledPurchaseOrder, ShippedPurchaseOrder, ReceivedPurchaseOrder:
+		return true
+	default:
+		return false
+	}
+}
+
+func CanTransitionPurchaseOrder(from, to string) bool {
+	for _, status := range purchaseOrderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionPurchaseOrder(t *testing.T) {
+	require.True(t, CanTransitionPurchaseOrder(PlacedPurchaseOrder, AcceptedPurchaseOrder))
+	require.True(t, CanTransitionPurchaseOrder(AcceptedPurchaseOrder, CancelledPurchaseOrder))
+	require.True(t, CanTransitionPurchaseOrder(ShippedPurchaseOrder, ReceivedPurchaseOrder))
+
+	require.False(t, CanTransitionPurchaseOrder(PlacedPurchaseOrder, ShippedPurchaseOrder))
+	require.False(t, CanTransitionPurchaseOrder(ShippedPurchaseOrder, CancelledPurchaseOrder))
+	require.False(t, CanTransitionPurchaseOrder(ReceivedPurchaseOrder, PlacedPurchaseOrder))
+	require.False(t, CanTransitionPurchaseOrder("lost", PlacedPurchaseOrder))
+}
@@ -1,30 +1,30 @@
-package util
-
-import (
-	"fmt"
-	"math/rand"
-	"strings"
-)
-
-const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-
-func RandomString(n int) string {
-	var sb strings.Builder
-
-	k := len(alphabet)
-
-	for i := 0; i < n; i++ {
-		c := alphabet[rand.Intn(k)]
-		sb.WriteByte(c)
-	}
-
-	return sb.String()
-}
-
-func RandomEmail() string {
-	return fmt.Sprintf("%s@gmail.com", RandomString(6))
-}
-
-func RandomOwner() string {
-	return RandomString(6)
-}
+package util
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+func RandomString(n int) string {
+	var sb strings.Builder
+
+	k := len(alphabet)
+
+	for i := 0; i < n; i++ {
+		c := alphabet[rand.Intn(k)]
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
+
+func RandomEmail() string {
+	return fmt.Sprintf("%s@gmail.com", RandomString(6))
+}
+
+func RandomOwner() string {
+	return RandomString(6)
+}
@@ -0,0 +1,20 @@
+package util
+
+import "math"
+
+// SuggestReorderQuantity suggests how many units to order so that, at the
+// rate unitsSold were sold over the last windowDays, stock covers the next
+// coverDays of sales with the reorder level left over as safety stock.
+func SuggestReorderQuantity(unitsSold, windowDays, coverDays, quantity, reorderLevel int) int {
+	if windowDays <= 0 {
+		return 0
+	}
+
+	dailySales := float64(unitsSold) / float64(windowDays)
+	target := int(math.Ceil(dailySales*float64(coverDays))) + reorderLevel
+
+	if target <= quantity {
+		return 0
+	}
+	return target - quantity
+}
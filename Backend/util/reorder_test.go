@@ -0,0 +1,22 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestReorderQuantity(t *testing.T) {
+	// 60 sold in 30 days is 2 a day: 30 days of cover is 60, plus 10 safety
+	// stock, less the 5 on hand
+	require.Equal(t, 65, SuggestReorderQuantity(60, 30, 30, 5, 10))
+
+	// No sales still brings stock back up to the reorder level
+	require.Equal(t, 10, SuggestReorderQuantity(0, 30, 30, 0, 10))
+
+	// Partial units round up
+	require.Equal(t, 4, SuggestReorderQuantity(10, 30, 10, 0, 0))
+
+	require.Equal(t, 0, SuggestReorderQuantity(3, 30, 30, 20, 10))
+	require.Equal(t, 0, SuggestReorderQuantity(10, 0, 30, 0, 10))
+}
@@ -0,0 +1,34 @@
+package util
+
+import "regexp"
+
+const (
+	PendingReview   = "pending"
+	PublishedReview = "published"
+	RejectedReview  = "rejected"
+)
+
+// MaxReviewCommentLength is the longest review comment, in characters
+const MaxReviewCommentLength = 2000
+
+var reviewScreens = []struct {
+	pattern *regexp.Regexp
+	reason  string
+}{
+	{regexp.MustCompile(`(?:\d[\s-]*){10,}`), "contains a phone number"},
+	{regexp.MustCompile(`(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`), "contains an email address"},
+	{regexp.MustCompile(`(?i)https?://|www\.|\b[a-z0-9-]+\.(?:com|in|net|org|io|co)\b`), "contains a link"},
+}
+
+// ScreenReviewComment checks a review comment for contact details and
+// links, which shouldn't be published without an operator looking at them.
+// It gives the reason to hold the review, or "" when it can be published
+// straight away.
+func ScreenReviewComment(comment string) string {
+	for _, screen := range reviewScreens {
+		if screen.pattern.MatchString(comment) {
+			return screen.reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreenReviewComment(t *testing.T) {
+	testCases := []struct {
+		name    string
+		comment string
+		held    bool
+	}{
+		{name: "Plain", comment: "Listened patiently and explained the treatment well. 5 stars!", held: false},
+		{name: "Empty", comment: "", held: false},
+		{name: "ShortNumbers", comment: "Waited 20 minutes, fee was 500", held: false},
+		{name: "PhoneNumber", comment: "Call me on 98765 43210 for a better doctor", held: true},
+		{name: "Email", comment: "write to someone@example.com", held: true},
+		{name: "URL", comment: "see https://example.org/reviews", held: true},
+		{name: "BareDomain", comment: "cheaper at bestclinic.in", held: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason := ScreenReviewComment(tc.comment)
+			require.Equal(t, tc.held, reason != "", reason)
+		})
+	}
+}
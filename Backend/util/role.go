@@ -1,7 +1,8 @@
-package util
-
-const (
-	Doctor = "doctor"
-	Patient = "patient"
-	Seller = "seller"
+package util
+
+const (
+	Doctor = "doctor"
+	Patient = "patient"
+	Seller = "seller"
+	Admin = "admin"
 )
\ No newline at end of file
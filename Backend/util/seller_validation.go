@@ -1,6 +1,6 @@
-package util
-
-// This file is intentionally kept as a placeholder for future seller-related validation functions.
-// Note:
-// - IsValidPhoneNumber is defined in check.go
-// - IsValidSellerType and seller type constants are defined in seller_type.go
+package util
+
+// This file is intentionally kept as a placeholder for future seller-related validation functions.
+// Note:
+// - IsValidPhoneNumber is defined in check.go
+// - IsValidSellerType and seller type constants are defined in seller_type.go
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	CSVFormat  = "csv"
+	XLSXFormat = "xlsx"
+)
+
+func IsValidSpreadsheetFormat(format string) bool {
+	switch format {
+	case CSVFormat, XLSXFormat:
+		return true
+	default:
+		return false
+	}
+}
+
+// SpreadsheetFormatFromFileName picks the format from a file extension,
+// returning an empty string for anything that isn't CSV or XLSX.
+func SpreadsheetFormatFromFileName(fileName string) string {
+	name := strings.ToLower(fileName)
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		return CSVFormat
+	case strings.HasSuffix(name, ".xlsx"):
+		return XLSXFormat
+	default:
+		return ""
+	}
+}
+
+// ReadSpreadsheet reads every row of a CSV file, or of the first sheet of an
+// XLSX workbook. Rows may have different lengths.
+func ReadSpreadsheet(r io.Reader, format string) ([][]string, error) {
+	switch format {
+	case CSVFormat:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case XLSXFormat:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("workbook has no sheets")
+		}
+		return f.GetRows(sheets[0])
+	default:
+		return nil, fmt.Errorf("unsupported spreadsheet format %q", format)
+	}
+}
+
+// WriteSpreadsheet writes rows as CSV or as a single-sheet XLSX workbook.
+func WriteSpreadsheet(w io.Writer, format string, rows [][]string) error {
+	switch format {
+	case CSVFormat:
+		writer := csv.NewWriter(w)
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	case XLSXFormat:
+		f := excelize.NewFile()
+		defer f.Close()
+
+		sheet := f.GetSheetName(0)
+		for i, row := range rows {
+			cell, err := excelize.CoordinatesToCellName(1, i+1)
+			if err != nil {
+				return err
+			}
+			values := make([]interface{}, len(row))
+			for j, value := range row {
+				values[j] = value
+			}
+			if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+				return err
+			}
+		}
+		return f.Write(w)
+	default:
+		return fmt.Errorf("unsupported spreadsheet format %q", format)
+	}
+}
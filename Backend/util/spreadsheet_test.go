@@ -0,0 +1,37 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpreadsheetRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"name", "batch_number", "quantity"},
+		{"Paracetamol, 500mg", "B-01", "20"},
+		{"Cetirizine", "", "5"},
+	}
+
+	for _, format := range []string{CSVFormat, XLSXFormat} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteSpreadsheet(&buf, format, rows))
+
+		got, err := ReadSpreadsheet(&buf, format)
+		require.NoError(t, err)
+		require.Len(t, got, len(rows))
+		require.Equal(t, rows[0], got[0])
+		require.Equal(t, rows[1], got[1])
+		// XLSX drops trailing empty cells but keeps empty ones in the middle
+		require.Equal(t, "Cetirizine", got[2][0])
+		require.Equal(t, "5", got[2][2])
+	}
+}
+
+func TestSpreadsheetFormatFromFileName(t *testing.T) {
+	require.Equal(t, CSVFormat, SpreadsheetFormatFromFileName("stock.CSV"))
+	require.Equal(t, XLSXFormat, SpreadsheetFormatFromFileName("stock.xlsx"))
+	require.Empty(t, SpreadsheetFormatFromFileName("stock.xls"))
+	require.False(t, IsValidSpreadsheetFormat("pdf"))
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Store staff roles. The seller account itself acts as an owner.
+const (
+	OwnerStaff          = "owner"
+	PharmacistStaff     = "pharmacist"
+	InventoryClerkStaff = "inventory_clerk"
+)
+
+func IsValidStaffRole(role string) bool {
+	switch role {
+	case OwnerStaff, PharmacistStaff, InventoryClerkStaff:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanManageStore covers the store's profile, staff, pricing, purchasing and
+// reports
+func CanManageStore(role string) bool {
+	return role == OwnerStaff
+}
+
+// CanEditStock covers adding, changing and removing medicines and recording
+// stock movements
+func CanEditStock(role string) bool {
+	return role == OwnerStaff || role == InventoryClerkStaff
+}
+
+// CanApprovePrescriptions covers confirming orders for prescription
+// medicines
+func CanApprovePrescriptions(role string) bool {
+	return role == OwnerStaff || role == PharmacistStaff
+}
+
+// NewInviteToken returns a random token to be sent in an invite
+func NewInviteToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashInviteToken is what is stored in place of an invite token
+func HashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaffPermissions(t *testing.T) {
+	require.True(t, CanManageStore(OwnerStaff))
+	require.False(t, CanManageStore(PharmacistStaff))
+	require.False(t, CanManageStore(InventoryClerkStaff))
+
+	require.True(t, CanEditStock(OwnerStaff))
+	require.True(t, CanEditStock(InventoryClerkStaff))
+	require.False(t, CanEditStock(PharmacistStaff))
+
+	require.True(t, CanApprovePrescriptions(OwnerStaff))
+	require.True(t, CanApprovePrescriptions(PharmacistStaff))
+	require.False(t, CanApprovePrescriptions(InventoryClerkStaff))
+
+	require.False(t, IsValidStaffRole("cashier"))
+}
+
+func TestInviteToken(t *testing.T) {
+	token, err := NewInviteToken()
+	require.NoError(t, err)
+	require.Len(t, token, 64)
+
+	other, err := NewInviteToken()
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+
+	require.Equal(t, HashInviteToken(token), HashInviteToken(token))
+	require.NotEqual(t, token, HashInviteToken(token))
+}
@@ -0,0 +1,56 @@
+package util
+
+import "errors"
+
+const (
+	ReceiptMovement        = "receipt"
+	SaleMovement           = "sale"
+	ReturnMovement         = "return"
+	DamageMovement         = "damage"
+	ExpiryWriteOffMovement = "expiry_write_off"
+	AdjustmentMovement     = "adjustment"
+	// Transfers move stock between sellers through donation claims and
+	// between branches of an organisation, and can't be recorded by hand
+	TransferOutMovement = "transfer_out"
+	TransferInMovement  = "transfer_in"
+)
+
+// SystemActor is recorded as the author of stock movements made by
+// background jobs rather than a logged-in user.
+const SystemActor = "system"
+
+func IsValidStockMovementType(movementType string) bool {
+	switch movementType {
+	case ReceiptMovement, SaleMovement, ReturnMovement, DamageMovement,
+		ExpiryWriteOffMovement, AdjustmentMovement:
+		return true
+	default:
+		return false
+	}
+}
+
+// SignedStockChange turns the quantity of a movement into the change it
+// makes to stock on hand. Receipts and returns add stock; sales, damage and
+// expiry write-offs remove it. Adjustments keep the sign they were given.
+func SignedStockChange(movementType string, quantity int32) (int32, error) {
+	if quantity == 0 {
+		return 0, errors.New("quantity must not be zero")
+	}
+
+	switch movementType {
+	case ReceiptMovement, ReturnMovement:
+		if quantity < 0 {
+			return 0, errors.New("quantity must be positive")
+		}
+		return quantity, nil
+	case SaleMovement, DamageMovement, ExpiryWriteOffMovement:
+		if quantity < 0 {
+			return 0, errors.New("quantity must be positive")
+		}
+		return -quantity, nil
+	case AdjustmentMovement:
+		return quantity, nil
+	default:
+		return 0, errors.New("invalid stock movement type")
+	}
+}
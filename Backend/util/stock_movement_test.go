@@ -0,0 +1,30 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedStockChange(t *testing.T) {
+	change, err := SignedStockChange(ReceiptMovement, 10)
+	require.NoError(t, err)
+	require.Equal(t, int32(10), change)
+
+	change, err = SignedStockChange(SaleMovement, 3)
+	require.NoError(t, err)
+	require.Equal(t, int32(-3), change)
+
+	change, err = SignedStockChange(AdjustmentMovement, -2)
+	require.NoError(t, err)
+	require.Equal(t, int32(-2), change)
+
+	_, err = SignedStockChange(DamageMovement, -1)
+	require.Error(t, err)
+
+	_, err = SignedStockChange(ReceiptMovement, 0)
+	require.Error(t, err)
+
+	_, err = SignedStockChange("gift", 1)
+	require.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package util
+
+const (
+	RequestedStockTransfer  = "requested"
+	RejectedStockTransfer   = "rejected"
+	CancelledStockTransfer  = "cancelled"
+	DispatchedStockTransfer = "dispatched"
+	ReceivedStockTransfer   = "received"
+)
+
+// stockTransferTransitions lists the statuses each stock transfer status can
+// move on to. Rejected, cancelled and received transfers are final.
+var stockTransferTransitions = map[string][]string{
+	RequestedStockTransfer:  {DispatchedStockTransfer, RejectedStockTransfer, CancelledStockTransfer},
+	DispatchedStockTransfer: {ReceivedStockTransfer},
+}
+
+func IsValidStockTransferStatus(status string) bool {
+	switch status {
+	case RequestedStockTransfer, RejectedStockTransfer, CancelledStockTransfer,
+		DispatchedStockTransfer, ReceivedStockTransfer:
+		return true
+	default:
+		return false
+	}
+}
+
+func CanTransitionStockTransfer(from, to string) bool {
+	for _, status := range stockTransferTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
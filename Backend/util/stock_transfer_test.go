@@ -0,0 +1,23 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionStockTransfer(t *testing.T) {
+	require.True(t, CanTransitionStockTransfer(RequestedStockTransfer, DispatchedStockTransfer))
+	require.True(t, CanTransitionStockTransfer(RequestedStockTransfer, CancelledStockTransfer))
+	require.True(t, CanTransitionStockTransfer(DispatchedStockTransfer, ReceivedStockTransfer))
+
+	require.False(t, CanTransitionStockTransfer(RequestedStockTransfer, ReceivedStockTransfer))
+	require.False(t, CanTransitionStockTransfer(DispatchedStockTransfer, CancelledStockTransfer))
+	require.False(t, CanTransitionStockTransfer(RejectedStockTransfer, RequestedStockTransfer))
+	require.False(t, CanTransitionStockTransfer("lost", RequestedStockTransfer))
+}
+
+func TestIsValidStockTransferStatus(t *testing.T) {
+	require.True(t, IsValidStockTransferStatus(DispatchedStockTransfer))
+	require.False(t, IsValidStockTransferStatus("shipped"))
+}
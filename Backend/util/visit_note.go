@@ -0,0 +1,30 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	DraftVisitNote     = "draft"
+	FinalizedVisitNote = "finalized"
+)
+
+// An ICD-10 code is a letter, two digits and optionally a dot and up to
+// four more characters, e.g. J06.9 or S72.001A
+var icd10CodePattern = regexp.MustCompile(`^[A-Z][0-9][0-9A-Z](\.[0-9A-Z]{1,4})?$`)
+
+// NormalizeICD10Code puts a code typed as "j069" or " j06.9 " into the form
+// used by the code table, "J06.9". The result still has to be checked with
+// IsValidICD10Code.
+func NormalizeICD10Code(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) > 3 && !strings.Contains(code, ".") {
+		code = code[:3] + "." + code[3:]
+	}
+	return code
+}
+
+func IsValidICD10Code(code string) bool {
+	return icd10CodePattern.MatchString(code)
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeICD10Code(t *testing.T) {
+	testCases := []struct {
+		code       string
+		normalized string
+		valid      bool
+	}{
+		{code: "J06.9", normalized: "J06.9", valid: true},
+		{code: " j069 ", normalized: "J06.9", valid: true},
+		{code: "E11", normalized: "E11", valid: true},
+		{code: "s72001a", normalized: "S72.001A", valid: true},
+		{code: "I10.", normalized: "I10.", valid: false},
+		{code: "123", normalized: "123", valid: false},
+		{code: "J06.99999", normalized: "J06.99999", valid: false},
+		{code: "", normalized: "", valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			normalized := NormalizeICD10Code(tc.code)
+			require.Equal(t, tc.normalized, normalized)
+			require.Equal(t, tc.valid, IsValidICD10Code(normalized))
+		})
+	}
+}